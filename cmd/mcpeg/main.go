@@ -23,10 +23,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -58,16 +62,25 @@ type GatewayApp struct {
 	healthMgr     *health.HealthManager
 	server        *server.GatewayServer
 
+	// When the process started and its configuration was loaded, reported as
+	// lifecycle events once the server can deliver them
+	startedAt      time.Time
+	configLoadedAt time.Time
+
 	// Process management
 	pidManager    *process.PIDManager
 	daemonManager *process.DaemonManager
 
 	// Command line flags
-	configFile string
-	devMode    bool
-	daemon     bool
-	pidFile    string
-	logFile    string
+	configFile         string
+	strictConfig       bool
+	migratedConfigFile string
+	devMode            bool
+	profile            bool
+	daemon             bool
+	pidFile            string
+	logFile            string
+	handoffPID         int
 }
 
 // CodegenConfig represents codegen configuration
@@ -97,6 +110,16 @@ type CodegenConfig struct {
 	// Validation options
 	StrictValidation bool
 	ValidateOnly     bool
+	Verify           bool
+
+	// Streaming options
+	Stream  bool
+	Workers int
+
+	// Diff options
+	Diff           bool
+	OldSpecFile    string
+	FailOnBreaking bool
 
 	// Debug options
 	Verbose bool
@@ -118,6 +141,8 @@ func main() {
 		runCodegen(os.Args[2:])
 	case "validate", "val":
 		runValidate(os.Args[2:])
+	case "config-schema":
+		runConfigSchema(os.Args[2:])
 	case "version", "ver", "-v", "--version":
 		showVersion()
 	case "help", "-h", "--help":
@@ -131,7 +156,7 @@ func main() {
 
 // runGateway runs the gateway (this is the main daemon functionality)
 func runGateway(args []string) {
-	app := &GatewayApp{}
+	app := &GatewayApp{startedAt: time.Now()}
 
 	// Parse command line flags
 	if err := app.parseFlags(args); err != nil {
@@ -160,7 +185,7 @@ func runGateway(args []string) {
 		fmt.Fprintf(os.Stderr, "Error starting gateway: %v\n", err)
 		// Clean up PID file on startup failure
 		if app.pidManager != nil {
-			app.pidManager.RemovePID()
+			app.pidManager.RemoveOwnPID()
 		}
 		os.Exit(1)
 	}
@@ -168,9 +193,9 @@ func runGateway(args []string) {
 	// Wait for context cancellation (shutdown signal)
 	<-ctx.Done()
 
-	// Cleanup PID file on shutdown
+	// Cleanup PID file on shutdown, unless a restarted daemon has taken it over
 	if app.pidManager != nil {
-		app.pidManager.RemovePID()
+		app.pidManager.RemoveOwnPID()
 	}
 
 	app.logger.Info("gateway_shutdown_complete")
@@ -183,12 +208,16 @@ func (app *GatewayApp) parseFlags(args []string) error {
 
 	// Configuration flags
 	flagSet.StringVar(&app.configFile, "config", paths.GetDefaultConfigPath(), "Path to configuration file")
+	flagSet.BoolVar(&app.strictConfig, "strict-config", false, "Fail on unknown configuration fields instead of warning")
+	flagSet.StringVar(&app.migratedConfigFile, "write-migrated-config", "", "Write the configuration with deprecated fields migrated to this path")
 	flagSet.BoolVar(&app.devMode, "dev", false, "Enable development mode")
+	flagSet.BoolVar(&app.profile, "profile", false, "Expose pprof endpoints under /admin/debug/pprof/ (requires admin endpoints; local clients only without an admin API key)")
 
 	// Daemon mode flags
 	flagSet.BoolVar(&app.daemon, "daemon", false, "Run in daemon mode (background)")
 	flagSet.StringVar(&app.pidFile, "pid-file", paths.GetDefaultPIDFile(), "Path to PID file")
 	flagSet.StringVar(&app.logFile, "log-file", paths.GetDefaultLogFile(), "Path to log file")
+	flagSet.IntVar(&app.handoffPID, "handoff-pid", 0, "PID of the daemon this one replaces (set by -restart with reuse_port)")
 
 	// Show help and version flags
 	showHelp := flagSet.Bool("help", false, "Show help")
@@ -213,6 +242,8 @@ func (app *GatewayApp) parseFlags(args []string) error {
 		fmt.Fprintf(os.Stderr, "  mcpeg gateway -config config.yaml\n\n")
 		fmt.Fprintf(os.Stderr, "  # Start in development mode\n")
 		fmt.Fprintf(os.Stderr, "  mcpeg gateway -dev\n\n")
+		fmt.Fprintf(os.Stderr, "  # Expose pprof profiles under /admin/debug/pprof/\n")
+		fmt.Fprintf(os.Stderr, "  mcpeg gateway -dev -profile\n\n")
 		fmt.Fprintf(os.Stderr, "  # Start as daemon\n")
 		fmt.Fprintf(os.Stderr, "  mcpeg gateway -daemon\n\n")
 		fmt.Fprintf(os.Stderr, "  # Control daemon\n")
@@ -237,9 +268,12 @@ func (app *GatewayApp) parseFlags(args []string) error {
 		os.Exit(0)
 	}
 
-	// Handle control commands
+	// Handle control commands, which must not go on to start a gateway
 	if *stop || *restart || *status || *logRotate {
-		return app.handleControlCommand(*stop, *restart, *status, *logRotate)
+		if err := app.handleControlCommand(*stop, *restart, *status, *logRotate); err != nil {
+			return err
+		}
+		os.Exit(0)
 	}
 
 	return nil
@@ -262,6 +296,9 @@ func (app *GatewayApp) loadConfig() error {
 			EnvPrefix:         "MCPEG",
 			AllowEnvOverrides: true,
 			Validate:          true,
+			Migrations:        config.GatewayMigrations(),
+			MigratedFilePath:  app.migratedConfigFile,
+			Strict:            app.strictConfig,
 		}
 
 		if err := app.configLoader.LoadFromFile(app.configFile, app.gatewayConfig, opts); err != nil {
@@ -279,6 +316,10 @@ func (app *GatewayApp) loadConfig() error {
 		app.applyDevModeOverrides()
 	}
 
+	if app.profile {
+		app.gatewayConfig.Development.Profiling = true
+	}
+
 	// Validate final configuration
 	if err := app.gatewayConfig.Validate(); err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
@@ -289,6 +330,7 @@ func (app *GatewayApp) loadConfig() error {
 		"tls_enabled", app.gatewayConfig.Server.TLS.Enabled,
 		"metrics_enabled", app.gatewayConfig.Metrics.Enabled,
 		"development_mode", app.gatewayConfig.Development.Enabled)
+	app.configLoadedAt = time.Now()
 
 	return nil
 }
@@ -300,7 +342,7 @@ func (app *GatewayApp) applyDevModeOverrides() {
 	app.gatewayConfig.Development.AdminEndpoints.Enabled = true
 	app.gatewayConfig.Logging.Level = "debug"
 	app.gatewayConfig.Server.HealthCheck.Detailed = true
-	app.gatewayConfig.Metrics.Collection.SystemInterval = 5 * 1000000000 // 5 seconds in nanoseconds
+	app.gatewayConfig.Metrics.Collection.SystemInterval = 5 * time.Second
 
 	// Disable TLS for development mode
 	app.gatewayConfig.Server.TLS.Enabled = false
@@ -318,7 +360,7 @@ func (app *GatewayApp) initialize() error {
 	app.metrics = app.createMetrics()
 
 	// Initialize validator
-	app.validator = validation.NewValidator(app.logger, app.metrics)
+	app.validator = validation.NewValidatorWithConfig(app.logger, app.metrics, app.gatewayConfig.ToValidationConfig())
 
 	// Initialize health manager
 	app.healthMgr = health.NewHealthManager(app.logger, app.metrics, Version)
@@ -368,7 +410,7 @@ func (app *GatewayApp) createLogger() logging.Logger {
 		}
 
 		// Create production logger with both console and file output
-		if prodLogger, err := logging.NewProductionLogger(app.gatewayConfig.Logging.Level, fileConfig); err == nil {
+		if prodLogger, err := logging.NewProductionLoggerWithFormat(app.gatewayConfig.Logging.Level, app.gatewayConfig.Logging.Format, fileConfig); err == nil {
 			return prodLogger
 		}
 	}
@@ -380,7 +422,7 @@ func (app *GatewayApp) createLogger() logging.Logger {
 // createMetrics creates a metrics collector based on configuration
 func (app *GatewayApp) createMetrics() metrics.Metrics {
 	if app.gatewayConfig.Metrics.Enabled {
-		return &consoleMetrics{logger: app.logger}
+		return metrics.NewProductionMetrics(app.logger)
 	}
 	return &noOpMetrics{}
 }
@@ -434,13 +476,33 @@ func (app *GatewayApp) start(ctx context.Context) error {
 		}
 	}
 
-	// Write PID file
-	if err := app.pidManager.WritePID(); err != nil {
+	// A restart with reuse_port starts this daemon next to the running one,
+	// which keeps its PID file until this one is serving
+	if app.handoffPID > 0 {
+		go app.takeOverFrom(ctx, app.handoffPID)
+	} else if err := app.pidManager.WritePID(); err != nil {
 		return fmt.Errorf("failed to write PID file: %w", err)
 	}
 
-	// Setup cleanup on exit
-	app.pidManager.SetupCleanupOnExit()
+	// Emitted here rather than as they happen so a daemon reports them from
+	// the child process that serves
+	app.server.EmitLifecycleEvent(server.LifecycleEvent{
+		Event:     server.LifecycleStarting,
+		Timestamp: app.startedAt,
+		Details: map[string]interface{}{
+			"version": Version,
+			"commit":  Commit,
+			"pid":     os.Getpid(),
+		},
+	})
+	app.server.EmitLifecycleEvent(server.LifecycleEvent{
+		Event:     server.LifecycleConfigLoaded,
+		Timestamp: app.configLoadedAt,
+		Details: map[string]interface{}{
+			"config_file": app.configFile,
+			"address":     fmt.Sprintf("%s:%d", app.gatewayConfig.Server.Address, app.gatewayConfig.Server.Port),
+		},
+	})
 
 	// Print startup banner (only in non-daemon mode)
 	if !app.daemon {
@@ -451,7 +513,7 @@ func (app *GatewayApp) start(ctx context.Context) error {
 	if err := app.server.Start(ctx); err != nil {
 		app.logger.Error("gateway_start_failed", "error", err)
 		// Clean up PID file on server start failure
-		app.pidManager.RemovePID()
+		app.pidManager.RemoveOwnPID()
 		return err
 	}
 
@@ -529,6 +591,16 @@ func runCodegen(args []string) {
 	// Validation options
 	fs.BoolVar(&config.StrictValidation, "strict", false, "Enable strict validation")
 	fs.BoolVar(&config.ValidateOnly, "validate-only", false, "Only validate specification without generating code")
+	fs.BoolVar(&config.Verify, "verify", false, "Type-check the generated code and fail on compile errors")
+
+	// Streaming options
+	fs.BoolVar(&config.Stream, "stream", false, "Write code as it is generated instead of building it in memory (for large specs)")
+	fs.IntVar(&config.Workers, "workers", 0, "Goroutines generating declarations with -stream (0 uses all CPUs)")
+
+	// Diff options
+	fs.BoolVar(&config.Diff, "diff", false, "Compare -old against the new specification instead of generating code")
+	fs.StringVar(&config.OldSpecFile, "old", "", "Path to the previous OpenAPI specification (used with -diff)")
+	fs.BoolVar(&config.FailOnBreaking, "fail-on-breaking", false, "Exit non-zero if -diff finds breaking changes")
 
 	// Debug options
 	fs.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
@@ -543,7 +615,9 @@ func runCodegen(args []string) {
 		fmt.Fprintf(os.Stderr, "  mcpeg codegen -spec-file api/openapi/mcp-gateway.yaml\n")
 		fmt.Fprintf(os.Stderr, "  mcpeg codegen -spec-url https://api.example.com/openapi.yaml\n")
 		fmt.Fprintf(os.Stderr, "  mcpeg codegen -spec-file api.yaml -validate-only\n")
-		fmt.Fprintf(os.Stderr, "  mcpeg codegen -spec-file api.yaml -output internal/generated\n\n")
+		fmt.Fprintf(os.Stderr, "  mcpeg codegen -spec-file api.yaml -output internal/generated\n")
+		fmt.Fprintf(os.Stderr, "  mcpeg codegen -spec-file api.yaml -verify\n")
+		fmt.Fprintf(os.Stderr, "  mcpeg codegen -diff -old old.yaml -spec-file new.yaml -fail-on-breaking\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		fs.PrintDefaults()
 	}
@@ -565,6 +639,20 @@ func runCodegen(args []string) {
 		os.Exit(1)
 	}
 
+	if config.Diff {
+		if config.OldSpecFile == "" {
+			fmt.Fprintf(os.Stderr, "Error: -diff requires -old\n\n")
+			fs.Usage()
+			os.Exit(1)
+		}
+
+		if err := executeSpecDiff(config); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Execute code generation
 	if err := executeCodegen(config); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -625,6 +713,47 @@ func runValidate(args []string) {
 	}
 }
 
+// runConfigSchema prints a JSON Schema describing the gateway configuration
+func runConfigSchema(args []string) {
+	fs := flag.NewFlagSet("config-schema", flag.ExitOnError)
+
+	var output string
+	fs.StringVar(&output, "output", "", "Write the schema to this file instead of stdout")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "MCpeg Configuration Schema\n\n")
+		fmt.Fprintf(os.Stderr, "Emits a JSON Schema for the gateway YAML configuration, for validating\n")
+		fmt.Fprintf(os.Stderr, "config files and building configuration tooling.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  mcpeg config-schema [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Examples:\n")
+		fmt.Fprintf(os.Stderr, "  mcpeg config-schema > config.schema.json\n")
+		fmt.Fprintf(os.Stderr, "  mcpeg config-schema -output config.schema.json\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	data, err := json.MarshalIndent(config.GenerateSchema(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to encode schema: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+
+	if output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func executeCodegen(config CodegenConfig) error {
 	// Set up logging
 	logger := setupCodegenLogging(config)
@@ -682,7 +811,40 @@ func executeCodegen(config CodegenConfig) error {
 	}
 
 	// Set up code generator
-	generator := codegen.NewCodeGenerator(logger, metrics)
+	generator := codegen.NewCodeGeneratorWithConfig(logger, metrics, codegen.GeneratorConfig{
+		OutputDir:          config.OutputDir,
+		PackageName:        config.PackageName,
+		ModulePath:         config.ModulePath,
+		GenerateTypes:      config.GenerateTypes,
+		GenerateHandlers:   config.GenerateHandlers,
+		GenerateClients:    config.GenerateClients,
+		GenerateValidators: config.GenerateValidators,
+		GenerateTests:      config.GenerateTests,
+		UsePointers:        config.UsePointers,
+		JSONTags:           config.JSONTags,
+		ValidationTags:     config.ValidationTags,
+		Verify:             config.Verify,
+		Workers:            config.Workers,
+	})
+
+	if config.Stream {
+		logger.Info("starting_streaming_code_generation", "output_dir", config.OutputDir)
+		fmt.Printf("🔧 Streaming generated Go code to %s...\n", config.OutputDir)
+
+		summary, err := generator.StreamCode(ctx, parseResult.Spec)
+		if err != nil {
+			return fmt.Errorf("code generation failed: %w", err)
+		}
+		if config.Verify {
+			fmt.Println("🔍 Generated code compiles")
+		}
+
+		reportStreamResults(summary)
+
+		logger.Info("mcpeg_codegen_completed")
+		fmt.Println("✅ Code generation completed successfully!")
+		return nil
+	}
 
 	// Generate code
 	logger.Info("starting_code_generation")
@@ -700,6 +862,9 @@ func executeCodegen(config CodegenConfig) error {
 	if err := generator.WriteCode(ctx, generated); err != nil {
 		return fmt.Errorf("failed to write generated code: %w", err)
 	}
+	if config.Verify {
+		fmt.Println("🔍 Generated code compiles")
+	}
 
 	// Report generation results
 	reportGenerationResults(generated)
@@ -710,6 +875,67 @@ func executeCodegen(config CodegenConfig) error {
 	return nil
 }
 
+// executeSpecDiff compares two specifications and reports backward-incompatible changes
+func executeSpecDiff(config CodegenConfig) error {
+	logger := setupCodegenLogging(config)
+	validator := validation.NewValidator(logger, &noOpMetrics{})
+	parser := codegen.NewOpenAPIParser(logger, validator)
+
+	ctx := context.Background()
+
+	oldResult, err := parser.ParseFromFile(ctx, config.OldSpecFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse old specification: %w", err)
+	}
+	if oldResult.Spec == nil {
+		return fmt.Errorf("failed to parse old specification %s", config.OldSpecFile)
+	}
+
+	var newResult *codegen.ParseResult
+	if config.SpecFile != "" {
+		newResult, err = parser.ParseFromFile(ctx, config.SpecFile)
+	} else {
+		newResult, err = parser.ParseFromURL(ctx, config.SpecURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse new specification: %w", err)
+	}
+	if newResult.Spec == nil {
+		return fmt.Errorf("failed to parse new specification %s%s", config.SpecFile, config.SpecURL)
+	}
+
+	diff := codegen.DiffSpecs(oldResult.Spec, newResult.Spec)
+	reportSpecDiff(diff)
+
+	if config.FailOnBreaking && diff.HasBreakingChanges() {
+		return fmt.Errorf("%d breaking change(s) detected", len(diff.BreakingChanges()))
+	}
+
+	return nil
+}
+
+func reportSpecDiff(diff *codegen.SpecDiff) {
+	if len(diff.Changes) == 0 {
+		fmt.Println("✅ No differences between specifications")
+		return
+	}
+
+	fmt.Printf("📋 Specification changes (%d):\n", len(diff.Changes))
+	for _, change := range diff.Changes {
+		marker := "  "
+		if change.Breaking {
+			marker = "❌"
+		}
+		fmt.Printf("   %s [%s] %s: %s\n", marker, change.Type, change.Location, change.Message)
+	}
+
+	if breaking := diff.BreakingChanges(); len(breaking) > 0 {
+		fmt.Printf("\n❌ %d breaking change(s) detected\n", len(breaking))
+	} else {
+		fmt.Println("\n✅ All changes are backward-compatible")
+	}
+}
+
 func setupCodegenLogging(config CodegenConfig) logging.Logger {
 	level := "info"
 	if config.Debug {
@@ -783,6 +1009,16 @@ func reportGenerationResults(generated *codegen.GeneratedCode) {
 	fmt.Println()
 }
 
+func reportStreamResults(summary *codegen.StreamSummary) {
+	fmt.Printf("📦 Generated code summary:\n")
+	fmt.Printf("   Package: %s\n", summary.Package)
+	fmt.Printf("   Types: %d\n", summary.Types)
+	fmt.Printf("   Functions: %d\n", summary.Functions)
+	fmt.Printf("   Constants: %d\n", summary.Constants)
+	fmt.Printf("   Files: %s\n", strings.Join(summary.Files, ", "))
+	fmt.Println()
+}
+
 func showVersion() {
 	fmt.Printf("MCpeg - Model Context Protocol Enablement Gateway\n")
 	fmt.Printf("Pronounced \"MC peg\" • The Peg That Connects Model Contexts\n\n")
@@ -802,6 +1038,7 @@ func showHelp() {
 	fmt.Printf("  gateway    Start the MCP gateway server\n")
 	fmt.Printf("  codegen    Generate Go code from OpenAPI specifications\n")
 	fmt.Printf("  validate   Validate OpenAPI specifications\n")
+	fmt.Printf("  config-schema  Print the gateway configuration JSON Schema\n")
 	fmt.Printf("  version    Show version information\n")
 	fmt.Printf("  help       Show this help message\n\n")
 	fmt.Printf("Examples:\n")
@@ -812,6 +1049,7 @@ func showHelp() {
 	fmt.Printf("  mcpeg gateway -status                                 # Check daemon status\n")
 	fmt.Printf("  mcpeg codegen -spec-file api/openapi/mcp-gateway.yaml # Generate code\n")
 	fmt.Printf("  mcpeg validate -spec-file api/openapi/mcp-gateway.yaml # Validate spec\n")
+	fmt.Printf("  mcpeg config-schema -output config.schema.json        # Export config schema\n")
 	fmt.Printf("  mcpeg version                                         # Show version\n\n")
 	fmt.Printf("Use 'mcpeg <command> -h' for more information about a command.\n")
 }
@@ -891,63 +1129,7 @@ func (l *consoleLogger) Error(msg string, fields ...interface{}) {
 	fmt.Printf("[ERROR] %s %v\n", msg, fields)
 }
 
-// Simple metrics implementations
-type consoleMetrics struct {
-	logger logging.Logger
-}
-
-func (m *consoleMetrics) Inc(name string, labels ...string) {
-	m.logger.Debug("metric_inc", "name", name, "labels", labels)
-}
-
-func (m *consoleMetrics) Add(name string, value float64, labels ...string) {
-	m.logger.Debug("metric_add", "name", name, "value", value, "labels", labels)
-}
-
-func (m *consoleMetrics) Set(name string, value float64, labels ...string) {
-	m.logger.Debug("metric_set", "name", name, "value", value, "labels", labels)
-}
-
-func (m *consoleMetrics) Observe(name string, value float64, labels ...string) {
-	m.logger.Debug("metric_observe", "name", name, "value", value, "labels", labels)
-}
-
-func (m *consoleMetrics) Time(name string, labels ...string) metrics.Timer {
-	return &consoleTimer{start: time.Now(), name: name, metrics: m}
-}
-
-func (m *consoleMetrics) WithLabels(labels map[string]string) metrics.Metrics {
-	return m
-}
-
-func (m *consoleMetrics) WithPrefix(prefix string) metrics.Metrics {
-	return m
-}
-
-func (m *consoleMetrics) GetStats(name string) metrics.MetricStats {
-	return metrics.MetricStats{}
-}
-
-func (m *consoleMetrics) GetAllStats() map[string]metrics.MetricStats {
-	return make(map[string]metrics.MetricStats)
-}
-
-type consoleTimer struct {
-	start   time.Time
-	name    string
-	metrics *consoleMetrics
-}
-
-func (t *consoleTimer) Duration() time.Duration {
-	return time.Since(t.start)
-}
-
-func (t *consoleTimer) Stop() time.Duration {
-	duration := time.Since(t.start)
-	t.metrics.logger.Debug("timer_stopped", "name", t.name, "duration", duration)
-	return duration
-}
-
+// noOpMetrics discards metrics when they are disabled
 type noOpMetrics struct{}
 
 func (m *noOpMetrics) Inc(name string, labels ...string)                    {}
@@ -1031,13 +1213,23 @@ func (app *GatewayApp) handleStopCommand(pidManager *process.PIDManager) error {
 	return nil
 }
 
-// handleRestartCommand handles the restart command
+// handleRestartCommand handles the restart command. When the configuration
+// sets reuse_port the running daemon is replaced without unbinding the port;
+// otherwise it is stopped before the new one starts.
 func (app *GatewayApp) handleRestartCommand(pidManager *process.PIDManager) error {
 	isRunning, pid, err := pidManager.IsRunning()
 	if err != nil {
 		return fmt.Errorf("failed to check if daemon is running: %w", err)
 	}
 
+	// Validate the configuration before touching the running daemon
+	if err := app.loadConfig(); err != nil {
+		return fmt.Errorf("not restarting: %w", err)
+	}
+	if isRunning && app.gatewayConfig.Server.ReusePort {
+		return app.handOffRestart(pidManager, pid)
+	}
+
 	if isRunning {
 		fmt.Printf("Stopping MCpeg Gateway (PID: %d)...\n", pid)
 		if err := pidManager.StopProcess(false); err != nil {
@@ -1055,6 +1247,19 @@ func (app *GatewayApp) handleRestartCommand(pidManager *process.PIDManager) erro
 		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
+	if err := syscall.Exec(execPath, append([]string{execPath}, app.daemonArgs()...), os.Environ()); err != nil {
+		return fmt.Errorf("failed to restart daemon: %w", err)
+	}
+
+	return nil
+}
+
+// handoffStartGrace bounds how long a replacement daemon may take to start
+// serving, on top of the old daemon's shutdown timeout
+const handoffStartGrace = 30 * time.Second
+
+// daemonArgs returns the arguments that start this configuration as a daemon
+func (app *GatewayApp) daemonArgs() []string {
 	args := []string{"gateway", "--daemon"}
 	if app.configFile != "" {
 		args = append(args, "--config", app.configFile)
@@ -1065,12 +1270,75 @@ func (app *GatewayApp) handleRestartCommand(pidManager *process.PIDManager) erro
 	if app.logFile != "" {
 		args = append(args, "--log-file", app.logFile)
 	}
+	return args
+}
 
-	if err := syscall.Exec(execPath, append([]string{execPath}, args...), os.Environ()); err != nil {
-		return fmt.Errorf("failed to restart daemon: %w", err)
+// handOffRestart starts a replacement daemon that binds the port next to the
+// running one with SO_REUSEPORT and stops it once serving, then waits for
+// the old daemon to exit. If the replacement fails to start the old daemon
+// keeps serving.
+func (app *GatewayApp) handOffRestart(pidManager *process.PIDManager, pid int) error {
+	fmt.Printf("Starting MCpeg Gateway alongside PID %d...\n", pid)
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
-	return nil
+	args := append(app.daemonArgs(), "--handoff-pid", strconv.Itoa(pid))
+	cmd := exec.Command(execPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to start replacement daemon: %w", err)
+	}
+
+	timeout := app.gatewayConfig.Server.ShutdownTimeout + handoffStartGrace
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !processAlive(pid) {
+			if running, newPID, _ := pidManager.IsRunning(); running {
+				fmt.Printf("MCpeg Gateway restarted (PID: %d)\n", newPID)
+				return nil
+			}
+			return fmt.Errorf("previous daemon (PID %d) exited but no replacement is running", pid)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("previous daemon (PID %d) still running after %s; the replacement may have failed to start, check its log", pid, timeout)
+}
+
+// takeOverFrom records this daemon in the PID file and stops the daemon it
+// replaces once this one is serving
+func (app *GatewayApp) takeOverFrom(ctx context.Context, previousPID int) {
+	select {
+	case <-app.server.Serving():
+	case <-ctx.Done():
+		return
+	}
+
+	if err := app.pidManager.TakeOverPID(previousPID); err != nil {
+		app.logger.Error("handoff_pid_file_failed", "previous_pid", previousPID, "error", err)
+	}
+
+	previous, err := os.FindProcess(previousPID)
+	if err == nil {
+		err = previous.Signal(syscall.SIGTERM)
+	}
+	if err != nil {
+		app.logger.Warn("handoff_stop_previous_failed", "previous_pid", previousPID, "error", err)
+		return
+	}
+	app.logger.Info("handoff_previous_stopping", "previous_pid", previousPID)
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
 }
 
 // handleLogRotateCommand handles the log rotate command
@@ -3,9 +3,17 @@ module github.com/osakka/mcpeg
 go 1.22
 
 require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/fsnotify/fsnotify v1.7.0
 	github.com/gorilla/mux v1.8.1
+	golang.org/x/net v0.33.0
 	golang.org/x/time v0.5.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/golang-jwt/jwt/v5 v5.2.2
+
+require (
+	golang.org/x/sys v0.28.0
+	golang.org/x/text v0.21.0 // indirect
+)
@@ -51,6 +51,7 @@ const (
 	ErrorCodeToolNotFound       = -32002
 	ErrorCodePromptNotFound     = -32003
 	ErrorCodeServiceUnavailable = -32004
+	ErrorCodeRequestTimeout     = -32005
 )
 
 // MCP Protocol Types
@@ -249,6 +250,7 @@ type ProgressNotification struct {
 	ProgressToken interface{} `json:"progressToken"`
 	Progress      float64     `json:"progress"`
 	Total         float64     `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
 }
 
 // Internal Types for MCPEG
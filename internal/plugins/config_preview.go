@@ -0,0 +1,130 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/osakka/mcpeg/pkg/plugins"
+)
+
+// ConfigChange is one setting a configuration update would add, remove or change
+type ConfigChange struct {
+	Key      string      `json:"key"`
+	Change   string      `json:"change"` // added, removed or changed
+	Current  interface{} `json:"current,omitempty"`
+	Proposed interface{} `json:"proposed,omitempty"`
+}
+
+// ConfigUpdatePreview reports whether a proposed plugin configuration is
+// valid and how it differs from the current one, without applying it
+type ConfigUpdatePreview struct {
+	Plugin    string         `json:"plugin"`
+	Valid     bool           `json:"valid"`
+	Validated bool           `json:"validated"` // False when the plugin cannot check its configuration
+	Errors    []string       `json:"errors"`
+	Changes   []ConfigChange `json:"changes"`
+}
+
+// InvalidConfigError rejects a plugin configuration that failed the plugin's validation
+type InvalidConfigError struct {
+	Plugin string
+	Errors []string
+}
+
+func (e *InvalidConfigError) Error() string {
+	return fmt.Sprintf("invalid configuration for plugin %s: %s", e.Plugin, strings.Join(e.Errors, "; "))
+}
+
+// PreviewPluginConfiguration validates a proposed configuration, which
+// replaces the plugin's current one, and lists the settings it changes. The
+// running plugin is left untouched.
+func (mpi *MCpegPluginIntegration) PreviewPluginConfiguration(pluginName string, config map[string]interface{}) (*ConfigUpdatePreview, error) {
+	plugin, exists := mpi.loader.GetPluginManager().GetPlugin(pluginName)
+	if !exists {
+		return nil, fmt.Errorf("plugin %s not found", pluginName)
+	}
+
+	preview := &ConfigUpdatePreview{
+		Plugin:  pluginName,
+		Valid:   true,
+		Errors:  []string{},
+		Changes: []ConfigChange{},
+	}
+
+	if validator, ok := plugin.(plugins.ConfigValidator); ok {
+		preview.Validated = true
+		for _, err := range validator.ValidateConfig(config) {
+			preview.Errors = append(preview.Errors, err.Error())
+		}
+		preview.Valid = len(preview.Errors) == 0
+	}
+
+	current := map[string]interface{}{}
+	if existing, exists := mpi.loader.GetDefaultPluginConfigs()[pluginName]; exists {
+		current = existing.Config
+	}
+
+	changes, err := diffPluginConfigs(current, config)
+	if err != nil {
+		return nil, err
+	}
+	preview.Changes = changes
+
+	mpi.metrics.Inc("plugin_configuration_previews_total",
+		"plugin", pluginName,
+		"valid", fmt.Sprintf("%t", preview.Valid))
+	mpi.logger.Info("plugin_configuration_previewed",
+		"plugin", pluginName,
+		"valid", preview.Valid,
+		"errors", len(preview.Errors),
+		"changes", len(preview.Changes))
+
+	return preview, nil
+}
+
+// diffPluginConfigs compares two configurations by their JSON form, so a
+// default written as an int matches the float64 a JSON update decodes to
+func diffPluginConfigs(current, proposed map[string]interface{}) ([]ConfigChange, error) {
+	currentJSON, err := normalizeConfig(current)
+	if err != nil {
+		return nil, fmt.Errorf("current configuration: %w", err)
+	}
+	proposedJSON, err := normalizeConfig(proposed)
+	if err != nil {
+		return nil, fmt.Errorf("proposed configuration: %w", err)
+	}
+
+	changes := []ConfigChange{}
+	for key, value := range proposedJSON {
+		existing, exists := currentJSON[key]
+		switch {
+		case !exists:
+			changes = append(changes, ConfigChange{Key: key, Change: "added", Proposed: value})
+		case !reflect.DeepEqual(existing, value):
+			changes = append(changes, ConfigChange{Key: key, Change: "changed", Current: existing, Proposed: value})
+		}
+	}
+	for key, value := range currentJSON {
+		if _, exists := proposedJSON[key]; !exists {
+			changes = append(changes, ConfigChange{Key: key, Change: "removed", Current: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, nil
+}
+
+func normalizeConfig(config map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string]interface{}{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,101 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultAutoDisableThreshold = 3
+	defaultHealthCheckInterval  = 30 * time.Second
+)
+
+// HealthMonitorConfig controls taking plugins out of rotation when they fail
+// consecutive health checks and putting them back once they recover
+type HealthMonitorConfig struct {
+	// AutoDisableThreshold is the number of consecutive failed health checks
+	// after which a plugin stops receiving traffic. 0 disables auto-disable.
+	AutoDisableThreshold int
+	// CheckInterval is how often the background monitor checks plugin health
+	CheckInterval time.Duration
+}
+
+// DefaultHealthMonitorConfig returns the default plugin health monitor settings
+func DefaultHealthMonitorConfig() HealthMonitorConfig {
+	return HealthMonitorConfig{
+		AutoDisableThreshold: defaultAutoDisableThreshold,
+		CheckInterval:        defaultHealthCheckInterval,
+	}
+}
+
+// recordPluginHealth updates a plugin's consecutive failure count and disables
+// or re-enables it when it crosses the threshold or recovers
+func (mpi *MCpegPluginIntegration) recordPluginHealth(pluginName string, err error) {
+	threshold := mpi.healthConfig.AutoDisableThreshold
+	if threshold <= 0 {
+		return
+	}
+
+	mpi.healthMutex.Lock()
+	if err == nil {
+		delete(mpi.consecutiveFailures, pluginName)
+	} else {
+		mpi.consecutiveFailures[pluginName]++
+	}
+	failures := mpi.consecutiveFailures[pluginName]
+	mpi.healthMutex.Unlock()
+
+	manager := mpi.loader.GetPluginManager()
+
+	if err == nil {
+		if manager.EnablePlugin(pluginName) {
+			mpi.metrics.Inc("plugin_auto_enabled_total", "plugin", pluginName)
+			mpi.logger.Info("plugin_auto_enabled",
+				"plugin", pluginName)
+		}
+		return
+	}
+
+	if failures < threshold {
+		return
+	}
+
+	reason := fmt.Sprintf("unhealthy for %d consecutive health checks: %v", failures, err)
+	if manager.DisablePlugin(pluginName, reason) {
+		mpi.metrics.Inc("plugin_auto_disabled_total", "plugin", pluginName)
+		mpi.logger.Warn("plugin_auto_disabled",
+			"plugin", pluginName,
+			"consecutive_failures", failures,
+			"error", err)
+	}
+}
+
+// StartHealthMonitor periodically health checks plugins so unhealthy plugins
+// are disabled and recovered plugins re-enabled without an admin request. The
+// monitor stops when ctx is cancelled or the plugins are shut down.
+func (mpi *MCpegPluginIntegration) StartHealthMonitor(ctx context.Context) {
+	if mpi.healthConfig.AutoDisableThreshold <= 0 || mpi.healthConfig.CheckInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(mpi.healthConfig.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-mpi.stopMonitor:
+				return
+			case <-ticker.C:
+				mpi.HealthCheckPlugins(ctx)
+			}
+		}
+	}()
+
+	mpi.logger.Info("plugin_health_monitor_started",
+		"interval", mpi.healthConfig.CheckInterval,
+		"auto_disable_threshold", mpi.healthConfig.AutoDisableThreshold)
+}
@@ -0,0 +1,138 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/rbac"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestPluginAutoDisable tests that a plugin failing consecutive health checks
+// stops receiving traffic and is re-enabled once it recovers
+func TestPluginAutoDisable(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	serviceRegistry := registry.NewServiceRegistry(logger, mockMetrics, validator, healthMgr)
+	defer serviceRegistry.Shutdown()
+
+	config := DefaultHealthMonitorConfig()
+	config.AutoDisableThreshold = 2
+	integration := NewMCpegPluginIntegrationWithConfig(serviceRegistry, logger, mockMetrics, config)
+
+	plugin := &flakyPlugin{}
+	manager := integration.GetPluginManager()
+	if err := manager.RegisterPlugin(plugin); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	handler := mcp.NewPluginHandler(manager, mcp.PluginHandlerConfig{}, logger, mockMetrics)
+	capabilities := &rbac.ProcessedCapabilities{
+		UserID:  "test",
+		Plugins: map[string]rbac.PluginPermission{"*": {CanRead: true, CanExecute: true}},
+	}
+
+	ctx := context.Background()
+
+	t.Run("disabled after consecutive failures", func(t *testing.T) {
+		plugin.unhealthy.Store(true)
+
+		integration.HealthCheckPlugins(ctx)
+		if _, disabled := manager.DisabledReason("flaky"); disabled {
+			t.Fatal("expected plugin to stay enabled below the threshold")
+		}
+
+		status := integration.HealthCheckPlugins(ctx)
+		reason, disabled := manager.DisabledReason("flaky")
+		if !disabled {
+			t.Fatal("expected plugin to be disabled after reaching the threshold")
+		}
+		if !strings.Contains(reason, "2 consecutive health checks") {
+			t.Errorf("expected reason to mention the failure count, got %q", reason)
+		}
+		if pluginStatus := status["plugins"].(map[string]interface{})["flaky"].(map[string]interface{}); pluginStatus["disabled"] != true {
+			t.Errorf("expected health report to flag the plugin as disabled, got %v", pluginStatus)
+		}
+
+		if tools := integration.GetAllPluginTools(); len(tools) != 0 {
+			t.Errorf("expected disabled plugin tools to be hidden, got %v", tools)
+		}
+		if available := handler.ListAvailablePlugins(capabilities); len(available) != 0 {
+			t.Errorf("expected disabled plugin not to be listed, got %v", available)
+		}
+
+		_, err := handler.InvokePlugin(ctx, "flaky", "flaky_echo", nil, capabilities)
+		if err == nil || !strings.Contains(err.Error(), "plugin flaky is disabled") {
+			t.Errorf("expected a disabled plugin error, got %v", err)
+		}
+		if _, err := integration.HandlePluginToolCall(ctx, "flaky_echo", []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "disabled") {
+			t.Errorf("expected tool call to be rejected, got %v", err)
+		}
+		if plugin.calls.Load() != 0 {
+			t.Errorf("expected no calls to reach the disabled plugin, got %d", plugin.calls.Load())
+		}
+	})
+
+	t.Run("re-enabled once healthy", func(t *testing.T) {
+		plugin.unhealthy.Store(false)
+
+		integration.HealthCheckPlugins(ctx)
+		if _, disabled := manager.DisabledReason("flaky"); disabled {
+			t.Fatal("expected plugin to be re-enabled after a healthy check")
+		}
+		if tools := integration.GetAllPluginTools(); len(tools) != 1 {
+			t.Errorf("expected plugin tools to be listed again, got %v", tools)
+		}
+		if _, err := integration.HandlePluginToolCall(ctx, "flaky_echo", []byte(`{}`)); err != nil {
+			t.Errorf("expected tool call to succeed, got %v", err)
+		}
+	})
+
+	t.Run("a single failure after recovery does not disable", func(t *testing.T) {
+		plugin.unhealthy.Store(true)
+		integration.HealthCheckPlugins(ctx)
+		if _, disabled := manager.DisabledReason("flaky"); disabled {
+			t.Error("expected the failure count to restart after recovery")
+		}
+	})
+}
+
+// flakyPlugin reports health on demand; other Plugin methods are unused
+type flakyPlugin struct {
+	plugins.Plugin
+	unhealthy atomic.Bool
+	calls     atomic.Int64
+}
+
+func (p *flakyPlugin) Name() string        { return "flaky" }
+func (p *flakyPlugin) Version() string     { return "1.0.0" }
+func (p *flakyPlugin) Description() string { return "Plugin with controllable health" }
+
+func (p *flakyPlugin) GetTools() []registry.ToolDefinition {
+	return []registry.ToolDefinition{{Name: "flaky_echo", Description: "Echo"}}
+}
+
+func (p *flakyPlugin) HealthCheck(ctx context.Context) error {
+	if p.unhealthy.Load() {
+		return errors.New("backing store unreachable")
+	}
+	return nil
+}
+
+func (p *flakyPlugin) CallTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	p.calls.Add(1)
+	return map[string]interface{}{"ok": true}, nil
+}
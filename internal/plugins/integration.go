@@ -3,6 +3,9 @@ package plugins
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/osakka/mcpeg/internal/registry"
@@ -18,6 +21,13 @@ type MCpegPluginIntegration struct {
 	registry *registry.ServiceRegistry
 	logger   logging.Logger
 	metrics  metrics.Metrics
+
+	requiredPlugins     []string
+	healthConfig        HealthMonitorConfig
+	healthMutex         sync.Mutex
+	consecutiveFailures map[string]int
+	stopMonitor         chan struct{}
+	stopOnce            sync.Once
 }
 
 // NewMCpegPluginIntegration creates a new plugin integration
@@ -25,16 +35,29 @@ func NewMCpegPluginIntegration(
 	serviceRegistry *registry.ServiceRegistry,
 	logger logging.Logger,
 	metricsCollector metrics.Metrics,
+) *MCpegPluginIntegration {
+	return NewMCpegPluginIntegrationWithConfig(serviceRegistry, logger, metricsCollector, DefaultHealthMonitorConfig())
+}
+
+// NewMCpegPluginIntegrationWithConfig creates a new plugin integration with custom health monitor settings
+func NewMCpegPluginIntegrationWithConfig(
+	serviceRegistry *registry.ServiceRegistry,
+	logger logging.Logger,
+	metricsCollector metrics.Metrics,
+	healthConfig HealthMonitorConfig,
 ) *MCpegPluginIntegration {
 	loader := plugins.NewPluginLoader(logger, metricsCollector)
 	adapter := plugins.NewPluginServiceAdapter(loader, logger)
 
 	return &MCpegPluginIntegration{
-		loader:   loader,
-		adapter:  adapter,
-		registry: serviceRegistry,
-		logger:   logger.WithComponent("plugin_integration"),
-		metrics:  metricsCollector.WithPrefix("plugin_integration"),
+		loader:              loader,
+		adapter:             adapter,
+		registry:            serviceRegistry,
+		logger:              logger.WithComponent("plugin_integration"),
+		metrics:             metricsCollector.WithPrefix("plugin_integration"),
+		healthConfig:        healthConfig,
+		consecutiveFailures: make(map[string]int),
+		stopMonitor:         make(chan struct{}),
 	}
 }
 
@@ -42,11 +65,27 @@ func NewMCpegPluginIntegration(
 func (mpi *MCpegPluginIntegration) InitializePlugins(ctx context.Context) error {
 	mpi.logger.Info("initializing_mcpeg_plugins")
 
+	// A misspelled required plugin would otherwise never be checked
+	if unknown := mpi.UnknownPlugins(mpi.requiredPlugins); len(unknown) > 0 {
+		mpi.logger.Error("unknown_required_plugins", "plugins", unknown)
+		return fmt.Errorf("unknown plugin(s) in required plugins: %s", strings.Join(unknown, ", "))
+	}
+
 	// Get default plugin configurations
 	configs := mpi.loader.GetDefaultPluginConfigs()
+	for _, name := range mpi.requiredPlugins {
+		config, exists := configs[name]
+		if !exists {
+			config = plugins.PluginConfig{Name: name, Config: make(map[string]interface{})}
+		}
+		config.Required = true
+		configs[name] = config
+	}
 
-	// Load all built-in plugins
-	if err := mpi.loader.LoadAllPlugins(ctx, configs); err != nil {
+	// Load all built-in plugins; only required plugins failing stops startup
+	err := mpi.loader.LoadAllPlugins(ctx, configs)
+	mpi.logStartupSummary()
+	if err != nil {
 		mpi.logger.Error("failed_to_load_plugins", "error", err)
 		return fmt.Errorf("failed to load plugins: %w", err)
 	}
@@ -63,6 +102,49 @@ func (mpi *MCpegPluginIntegration) InitializePlugins(ctx context.Context) error
 	return nil
 }
 
+// SetRequiredPlugins names the plugins whose initialization failure fails
+// startup. Other plugins that fail are excluded from routing.
+func (mpi *MCpegPluginIntegration) SetRequiredPlugins(names []string) {
+	mpi.requiredPlugins = names
+}
+
+// UnknownPlugins returns the names that do not match any plugin the gateway loads
+func (mpi *MCpegPluginIntegration) UnknownPlugins(names []string) []string {
+	var unknown []string
+	for _, name := range names {
+		if !mpi.loader.KnownPlugin(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// logStartupSummary reports which plugins initialized and which failed
+func (mpi *MCpegPluginIntegration) logStartupSummary() {
+	manager := mpi.loader.GetPluginManager()
+	failed := manager.FailedPlugins()
+
+	var succeeded []string
+	for _, name := range manager.GetPlugins() {
+		if _, isFailed := failed[name]; !isFailed {
+			succeeded = append(succeeded, name)
+		}
+	}
+	sort.Strings(succeeded)
+
+	mpi.metrics.Set("plugins_failed_count", float64(len(failed)))
+	if len(failed) == 0 {
+		mpi.logger.Info("plugin_startup_summary",
+			"succeeded", succeeded,
+			"failed_count", 0)
+		return
+	}
+	mpi.logger.Warn("plugin_startup_summary",
+		"succeeded", succeeded,
+		"failed", failed,
+		"failed_count", len(failed))
+}
+
 // registerPluginsAsServices registers each plugin as a service in the MCpeg service registry
 func (mpi *MCpegPluginIntegration) registerPluginsAsServices() error {
 	services := mpi.loader.CreateRegisteredServices()
@@ -205,18 +287,27 @@ func (mpi *MCpegPluginIntegration) HealthCheckPlugins(ctx context.Context) map[s
 	healthyCount := 0
 	totalCount := len(results)
 
+	manager := mpi.loader.GetPluginManager()
 	for pluginName, err := range results {
+		mpi.recordPluginHealth(pluginName, err)
+
+		var status map[string]interface{}
 		if err != nil {
-			healthStatus[pluginName] = map[string]interface{}{
+			status = map[string]interface{}{
 				"status": "unhealthy",
 				"error":  err.Error(),
 			}
 		} else {
-			healthStatus[pluginName] = map[string]interface{}{
+			status = map[string]interface{}{
 				"status": "healthy",
 			}
 			healthyCount++
 		}
+		if reason, disabled := manager.DisabledReason(pluginName); disabled {
+			status["disabled"] = true
+			status["disabled_reason"] = reason
+		}
+		healthStatus[pluginName] = status
 	}
 
 	overallStatus := "healthy"
@@ -263,6 +354,7 @@ func (mpi *MCpegPluginIntegration) GetPluginMetrics() map[string]interface{} {
 // ShutdownPlugins shuts down all plugins
 func (mpi *MCpegPluginIntegration) ShutdownPlugins(ctx context.Context) error {
 	mpi.logger.Info("shutting_down_mcpeg_plugins")
+	mpi.stopOnce.Do(func() { close(mpi.stopMonitor) })
 
 	if err := mpi.loader.ShutdownAllPlugins(ctx); err != nil {
 		mpi.logger.Error("failed_to_shutdown_plugins", "error", err)
@@ -282,8 +374,16 @@ func (mpi *MCpegPluginIntegration) UpdatePluginConfiguration(ctx context.Context
 		"plugin", pluginName,
 		"config_keys", len(config))
 
+	preview, err := mpi.PreviewPluginConfiguration(pluginName, config)
+	if err != nil {
+		return err
+	}
+	if !preview.Valid {
+		return &InvalidConfigError{Plugin: pluginName, Errors: preview.Errors}
+	}
+
 	// In a full implementation, this would:
-	// 1. Validate the new configuration
+	// 1. Validate the new configuration (done above)
 	// 2. Stop the current plugin instance
 	// 3. Reinitialize with new configuration
 	// 4. Update the service registry
@@ -292,6 +392,28 @@ func (mpi *MCpegPluginIntegration) UpdatePluginConfiguration(ctx context.Context
 	return fmt.Errorf("plugin configuration updates not yet implemented")
 }
 
+// ReloadPlugin shuts down a single plugin and re-initializes a fresh instance with
+// its current configuration, leaving the previous instance running if that fails
+func (mpi *MCpegPluginIntegration) ReloadPlugin(ctx context.Context, pluginName string) (map[string]interface{}, error) {
+	config, exists := mpi.loader.GetDefaultPluginConfigs()[pluginName]
+	if !exists {
+		config = plugins.PluginConfig{Name: pluginName, Config: make(map[string]interface{})}
+	}
+
+	if err := mpi.loader.ReloadPlugin(ctx, pluginName, config); err != nil {
+		mpi.metrics.Inc("plugin_reload_errors_total", "plugin", pluginName)
+		return nil, err
+	}
+
+	// Health failures of the previous instance do not count against the new one
+	mpi.healthMutex.Lock()
+	delete(mpi.consecutiveFailures, pluginName)
+	mpi.healthMutex.Unlock()
+
+	mpi.metrics.Inc("plugin_reload_successes_total", "plugin", pluginName)
+	return mpi.loader.GetPluginInfo(pluginName)
+}
+
 // GetPluginConfiguration returns the current configuration for a plugin
 func (mpi *MCpegPluginIntegration) GetPluginConfiguration(pluginName string) (map[string]interface{}, error) {
 	configs := mpi.loader.GetDefaultPluginConfigs()
@@ -351,3 +473,11 @@ func (mpi *MCpegPluginIntegration) ListPluginCapabilities() map[string]interface
 func (mpi *MCpegPluginIntegration) GetPluginManager() *plugins.PluginManager {
 	return mpi.loader.GetPluginManager()
 }
+
+// RegisterStdioPlugins adds external MCP servers run as subprocesses to the
+// plugins loaded by InitializePlugins
+func (mpi *MCpegPluginIntegration) RegisterStdioPlugins(configs []plugins.StdioPluginConfig) {
+	for _, config := range configs {
+		mpi.loader.RegisterStdioPlugin(config)
+	}
+}
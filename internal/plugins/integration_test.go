@@ -9,7 +9,10 @@ import (
 	"github.com/osakka/mcpeg/internal/registry"
 	"github.com/osakka/mcpeg/pkg/health"
 	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/mcp"
 	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/rbac"
 	"github.com/osakka/mcpeg/pkg/validation"
 )
 
@@ -376,6 +379,96 @@ func TestPluginServiceRegistryIntegration(t *testing.T) {
 	})
 }
 
+// TestPartialPluginStartup tests that a plugin failing to start is excluded while the others serve
+func TestPartialPluginStartup(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	ctx := context.Background()
+
+	newIntegration := func(t *testing.T) (*MCpegPluginIntegration, *registry.ServiceRegistry) {
+		validator := validation.NewValidator(logger, mockMetrics)
+		healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+		t.Cleanup(healthMgr.Shutdown)
+		serviceRegistry := registry.NewServiceRegistry(logger, mockMetrics, validator, healthMgr)
+		t.Cleanup(func() { serviceRegistry.Shutdown() })
+
+		integration := NewMCpegPluginIntegration(serviceRegistry, logger, mockMetrics)
+		integration.RegisterStdioPlugins([]plugins.StdioPluginConfig{
+			{Name: "broken", Command: "/nonexistent/mcp-server"},
+		})
+		return integration, serviceRegistry
+	}
+
+	t.Run("failed plugin is excluded from routing", func(t *testing.T) {
+		integration, serviceRegistry := newIntegration(t)
+		if err := integration.InitializePlugins(ctx); err != nil {
+			t.Fatalf("expected startup to continue past a failing plugin: %v", err)
+		}
+		defer integration.ShutdownPlugins(context.Background())
+
+		registered := make(map[string]bool)
+		for _, service := range serviceRegistry.GetAllServices() {
+			registered[service.Name] = true
+		}
+		for _, name := range []string{"memory", "git", "editor"} {
+			if !registered[name] {
+				t.Errorf("expected %s to be registered", name)
+			}
+		}
+		if registered["broken"] {
+			t.Error("expected the failed plugin not to be registered")
+		}
+
+		handler := mcp.NewPluginHandler(integration.GetPluginManager(), mcp.PluginHandlerConfig{}, logger, mockMetrics)
+		capabilities := &rbac.ProcessedCapabilities{
+			UserID:  "test",
+			Plugins: map[string]rbac.PluginPermission{"*": {CanRead: true, CanExecute: true}},
+		}
+		for _, name := range handler.ListAvailablePlugins(capabilities) {
+			if name == "broken" {
+				t.Error("expected the failed plugin not to be listed")
+			}
+		}
+		if _, err := handler.InvokePlugin(ctx, "memory", "memory_list", map[string]interface{}{}, capabilities); err != nil {
+			t.Errorf("expected memory plugin to serve calls: %v", err)
+		}
+		if _, err := handler.InvokePlugin(ctx, "broken", "anything", map[string]interface{}{}, capabilities); err == nil {
+			t.Error("expected calls to the failed plugin to be rejected")
+		}
+
+		status := integration.HealthCheckPlugins(ctx)
+		broken := status["plugins"].(map[string]interface{})["broken"].(map[string]interface{})
+		if broken["disabled"] != true {
+			t.Errorf("expected the failed plugin to be reported as disabled, got %v", broken)
+		}
+	})
+
+	t.Run("required plugin failure fails startup", func(t *testing.T) {
+		integration, _ := newIntegration(t)
+		integration.SetRequiredPlugins([]string{"broken"})
+		defer integration.ShutdownPlugins(context.Background())
+
+		err := integration.InitializePlugins(ctx)
+		if err == nil || !strings.Contains(err.Error(), "broken") {
+			t.Errorf("expected startup to fail naming the required plugin, got %v", err)
+		}
+	})
+
+	t.Run("unknown required plugin fails startup", func(t *testing.T) {
+		integration, _ := newIntegration(t)
+		integration.SetRequiredPlugins([]string{"memory", "memroy"})
+		defer integration.ShutdownPlugins(context.Background())
+
+		err := integration.InitializePlugins(ctx)
+		if err == nil || !strings.Contains(err.Error(), "memroy") {
+			t.Fatalf("expected startup to fail naming the unknown plugin, got %v", err)
+		}
+		if loaded := integration.GetPluginManager().ListPlugins(); len(loaded) != 0 {
+			t.Errorf("expected no plugins to be loaded, got %d", len(loaded))
+		}
+	})
+}
+
 // mockMetrics implements metrics.Metrics interface for testing
 type mockMetrics struct {
 	metrics map[string]interface{}
@@ -0,0 +1,86 @@
+package plugins
+
+import (
+	"sort"
+	"strings"
+)
+
+// Metrics recorded by the MCP plugin handler for every tool invocation
+const (
+	invocationDurationMetric = "plugin_invocation_duration" // milliseconds
+	invocationErrorsMetric   = "plugin_tool_errors"
+	inFlightMetric           = "plugin_invocations_in_flight" // gauge
+	queuedMetric             = "plugin_invocations_queued"    // gauge
+	saturatedMetric          = "plugin_invocations_saturated"
+)
+
+// PluginInvocationStats summarises tool invocations of one plugin
+type PluginInvocationStats struct {
+	Plugin        string  `json:"plugin"`
+	Invocations   uint64  `json:"invocations"`
+	Errors        uint64  `json:"errors"`
+	DurationSumMs float64 `json:"duration_sum_ms"`
+	InFlight      int     `json:"in_flight"` // Calls executing now
+	Queued        int     `json:"queued"`    // Calls waiting at the concurrency limit now
+	Saturated     uint64  `json:"saturated"` // Calls that found the concurrency limit reached
+}
+
+// ErrorRate is the fraction of invocations that failed
+func (s PluginInvocationStats) ErrorRate() float64 {
+	if s.Invocations == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Invocations)
+}
+
+// GetPluginInvocationStats aggregates per-call plugin metrics by plugin,
+// sorted by name. Only loaded plugins are reported, so the set of plugin
+// labels stays bounded whatever plugin names callers ask for.
+func (mpi *MCpegPluginIntegration) GetPluginInvocationStats() []PluginInvocationStats {
+	byPlugin := make(map[string]*PluginInvocationStats)
+	for _, name := range mpi.GetPluginManager().GetPlugins() {
+		byPlugin[name] = &PluginInvocationStats{Plugin: name}
+	}
+
+	for key, stat := range mpi.metrics.GetAllStats() {
+		name, labels := parseMetricKey(key)
+		stats, known := byPlugin[labels["plugin"]]
+		if !known {
+			continue
+		}
+
+		switch name {
+		case invocationDurationMetric:
+			stats.Invocations += stat.Count
+			stats.DurationSumMs += stat.Sum
+		case invocationErrorsMetric:
+			stats.Errors += uint64(stat.Sum)
+		case inFlightMetric:
+			stats.InFlight += int(stat.LastValue)
+		case queuedMetric:
+			stats.Queued += int(stat.LastValue)
+		case saturatedMetric:
+			stats.Saturated += uint64(stat.Sum)
+		}
+	}
+
+	result := make([]PluginInvocationStats, 0, len(byPlugin))
+	for _, stats := range byPlugin {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Plugin < result[j].Plugin })
+	return result
+}
+
+// parseMetricKey splits a metrics key of the form name:label=value:... into
+// the metric name and its labels
+func parseMetricKey(key string) (string, map[string]string) {
+	parts := strings.Split(key, ":")
+	labels := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			labels[k] = v
+		}
+	}
+	return parts[0], labels
+}
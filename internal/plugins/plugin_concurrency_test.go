@@ -0,0 +1,179 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/rbac"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestPluginConcurrencyMetrics tests that concurrent invocations raise the
+// in-flight gauge, that calls over the plugin's limit are queued and counted
+// as saturated, and that both gauges fall back to zero once calls complete
+func TestPluginConcurrencyMetrics(t *testing.T) {
+	logger := logging.New("test")
+	productionMetrics := metrics.NewProductionMetrics(logger)
+	validator := validation.NewValidator(logger, productionMetrics)
+	healthMgr := health.NewHealthManager(logger, productionMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	serviceRegistry := registry.NewServiceRegistry(logger, productionMetrics, validator, healthMgr)
+	defer serviceRegistry.Shutdown()
+
+	integration := NewMCpegPluginIntegration(serviceRegistry, logger, productionMetrics)
+	plugin := &blockingPlugin{started: make(chan struct{}, 10), unblock: make(chan struct{})}
+	if err := integration.GetPluginManager().RegisterPlugin(plugin); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	handler := mcp.NewPluginHandler(integration.GetPluginManager(), mcp.PluginHandlerConfig{
+		DefaultTimeout: 5 * time.Second,
+		Concurrency:    mcp.PluginConcurrencyConfig{Limits: map[string]int{"blocking": 2}},
+	}, logger, productionMetrics)
+	capabilities := &rbac.ProcessedCapabilities{
+		UserID:  "test",
+		Plugins: map[string]rbac.PluginPermission{"*": {CanRead: true, CanExecute: true}},
+	}
+
+	statsFor := func() PluginInvocationStats {
+		for _, stats := range integration.GetPluginInvocationStats() {
+			if stats.Plugin == "blocking" {
+				return stats
+			}
+		}
+		t.Fatal("expected stats for the blocking plugin")
+		return PluginInvocationStats{}
+	}
+	waitFor := func(t *testing.T, condition func(PluginInvocationStats) bool) PluginInvocationStats {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			stats := statsFor()
+			if condition(stats) {
+				return stats
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("condition not reached, last stats %+v", stats)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := handler.InvokePlugin(context.Background(), "blocking", "blocking_wait", nil, capabilities); err != nil {
+				t.Errorf("expected the call to succeed, got %v", err)
+			}
+		}()
+	}
+
+	t.Run("in-flight gauge rises to the limit and excess calls queue", func(t *testing.T) {
+		<-plugin.started
+		<-plugin.started
+		stats := waitFor(t, func(s PluginInvocationStats) bool { return s.InFlight == 2 && s.Queued == 1 })
+		if stats.Saturated != 1 {
+			t.Errorf("expected one saturated call, got %d", stats.Saturated)
+		}
+		select {
+		case <-plugin.started:
+			t.Error("expected the third call to wait for a free slot")
+		default:
+		}
+	})
+
+	t.Run("gauges fall to zero once calls complete", func(t *testing.T) {
+		close(plugin.unblock)
+		wg.Wait()
+		stats := waitFor(t, func(s PluginInvocationStats) bool { return s.InFlight == 0 && s.Queued == 0 })
+		if stats.Invocations != 3 {
+			t.Errorf("expected 3 invocations, got %d", stats.Invocations)
+		}
+	})
+
+	t.Run("queued call gives up at its timeout", func(t *testing.T) {
+		blocked := &blockingPlugin{started: make(chan struct{}, 1), unblock: make(chan struct{})}
+		blocked.name = "held"
+		if err := integration.GetPluginManager().RegisterPlugin(blocked); err != nil {
+			t.Fatalf("failed to register plugin: %v", err)
+		}
+		// The handler timeout is long so the call holding the slot cannot
+		// time out and free it; only the queued call gets a short deadline
+		limited := mcp.NewPluginHandler(integration.GetPluginManager(), mcp.PluginHandlerConfig{
+			DefaultTimeout: time.Minute,
+			Concurrency:    mcp.PluginConcurrencyConfig{MaxInFlight: 1},
+		}, logger, productionMetrics)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			limited.InvokePlugin(context.Background(), "held", "blocking_wait", nil, capabilities)
+		}()
+		<-blocked.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := limited.InvokePlugin(ctx, "held", "blocking_wait", nil, capabilities)
+		if err == nil || !strings.Contains(err.Error(), "concurrency limit") {
+			t.Errorf("expected a concurrency limit error, got %v", err)
+		}
+		close(blocked.unblock)
+		<-done
+	})
+
+	t.Run("negative limits are rejected", func(t *testing.T) {
+		for name, invalid := range map[string]mcp.PluginConcurrencyConfig{
+			"max in flight": {MaxInFlight: -1},
+			"plugin limit":  {Limits: map[string]int{"blocking": -1}},
+		} {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
+
+// blockingPlugin holds every tool call until unblock is closed; other Plugin
+// methods are unused
+type blockingPlugin struct {
+	plugins.Plugin
+	name    string
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (p *blockingPlugin) Name() string {
+	if p.name != "" {
+		return p.name
+	}
+	return "blocking"
+}
+func (p *blockingPlugin) Version() string     { return "1.0.0" }
+func (p *blockingPlugin) Description() string { return "Plugin holding calls until released" }
+
+func (p *blockingPlugin) GetTools() []registry.ToolDefinition {
+	return []registry.ToolDefinition{{Name: "blocking_wait", Description: "Wait"}}
+}
+
+func (p *blockingPlugin) CallTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	p.started <- struct{}{}
+	select {
+	case <-p.unblock:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return map[string]interface{}{"ok": true}, nil
+}
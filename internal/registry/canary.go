@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+// SetCanaryWeights assigns traffic percentages to versions of a service type.
+// Weights are keyed by service version and must add up to 100.
+func (lb *LoadBalancer) SetCanaryWeights(serviceType string, weights map[string]int) error {
+	if serviceType == "" {
+		return fmt.Errorf("service type is required")
+	}
+	if len(weights) == 0 {
+		return fmt.Errorf("at least one version weight is required")
+	}
+
+	total := 0
+	copied := make(map[string]int, len(weights))
+	for version, weight := range weights {
+		if version == "" {
+			return fmt.Errorf("version must not be empty")
+		}
+		if weight < 0 || weight > 100 {
+			return fmt.Errorf("weight for version %s must be between 0 and 100, got %d", version, weight)
+		}
+		total += weight
+		copied[version] = weight
+	}
+	if total != 100 {
+		return fmt.Errorf("canary weights must add up to 100, got %d", total)
+	}
+
+	lb.mutex.Lock()
+	lb.canaryWeights[serviceType] = copied
+	lb.mutex.Unlock()
+
+	lb.logger.Info("canary_weights_updated",
+		"service_type", serviceType,
+		"weights", copied)
+
+	return nil
+}
+
+// GetCanaryWeights returns the canary weights for a service type, or nil if none are set
+func (lb *LoadBalancer) GetCanaryWeights(serviceType string) map[string]int {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+
+	weights, exists := lb.canaryWeights[serviceType]
+	if !exists {
+		return nil
+	}
+
+	result := make(map[string]int, len(weights))
+	for version, weight := range weights {
+		result[version] = weight
+	}
+	return result
+}
+
+// GetAllCanaryWeights returns the canary weights for every service type
+func (lb *LoadBalancer) GetAllCanaryWeights() map[string]map[string]int {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+
+	result := make(map[string]map[string]int, len(lb.canaryWeights))
+	for serviceType, weights := range lb.canaryWeights {
+		copied := make(map[string]int, len(weights))
+		for version, weight := range weights {
+			copied[version] = weight
+		}
+		result[serviceType] = copied
+	}
+	return result
+}
+
+// ClearCanaryWeights removes the canary split for a service type
+func (lb *LoadBalancer) ClearCanaryWeights(serviceType string) {
+	lb.mutex.Lock()
+	delete(lb.canaryWeights, serviceType)
+	lb.mutex.Unlock()
+
+	lb.logger.Info("canary_weights_cleared", "service_type", serviceType)
+}
+
+// applyCanary narrows services to a single version picked according to the
+// canary weights of serviceType. Requests carrying a session ID always land on
+// the same version; services are returned unchanged when no split is configured.
+func (lb *LoadBalancer) applyCanary(serviceType string, services []*RegisteredService, criteria SelectionCriteria) []*RegisteredService {
+	weights := lb.GetCanaryWeights(serviceType)
+	if len(weights) == 0 {
+		return services
+	}
+
+	byVersion := make(map[string][]*RegisteredService)
+	for _, service := range services {
+		byVersion[service.Version] = append(byVersion[service.Version], service)
+	}
+
+	// Only versions with available instances take part so traffic is not
+	// dropped while a version is down
+	var versions []string
+	total := 0
+	for version, weight := range weights {
+		if weight > 0 && len(byVersion[version]) > 0 {
+			versions = append(versions, version)
+			total += weight
+		}
+	}
+	if total == 0 {
+		lb.logger.Warn("canary_versions_unavailable",
+			"service_type", serviceType,
+			"weights", weights)
+		return services
+	}
+	sort.Strings(versions)
+
+	var bucket int
+	if criteria.SessionID != "" {
+		hasher := fnv.New32a()
+		hasher.Write([]byte(serviceType + ":" + criteria.SessionID))
+		bucket = int(hasher.Sum32() % uint32(total))
+	} else {
+		bucket = rand.Intn(total)
+	}
+
+	selected := versions[len(versions)-1]
+	for _, version := range versions {
+		if bucket < weights[version] {
+			selected = version
+			break
+		}
+		bucket -= weights[version]
+	}
+
+	lb.metrics.Inc("load_balancer_canary_selections_total",
+		"service_type", serviceType,
+		"version", selected)
+
+	return byVersion[selected]
+}
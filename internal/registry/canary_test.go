@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestCanaryRouting tests weighted version splits in SelectService
+func TestCanaryRouting(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	addTestService(sr, "search-v1-a", "search", "1.0.0")
+	addTestService(sr, "search-v1-b", "search", "1.0.0")
+	addTestService(sr, "search-v2-a", "search", "2.0.0")
+
+	lb := sr.GetLoadBalancer()
+	if err := lb.SetCanaryWeights("search", map[string]int{"1.0.0": 95, "2.0.0": 5}); err != nil {
+		t.Fatalf("failed to set canary weights: %v", err)
+	}
+
+	t.Run("traffic split matches canary percentage", func(t *testing.T) {
+		const requests = 20000
+		canary := 0
+		for i := 0; i < requests; i++ {
+			service, err := selectAndComplete(sr, SelectionCriteria{})
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			if service.Version == "2.0.0" {
+				canary++
+			}
+		}
+
+		share := float64(canary) / requests * 100
+		if share < 4 || share > 6 {
+			t.Errorf("expected about 5%% of traffic on canary, got %.2f%%", share)
+		}
+	})
+
+	t.Run("sessions are split and stay pinned", func(t *testing.T) {
+		canarySessions := 0
+		for i := 0; i < 2000; i++ {
+			criteria := SelectionCriteria{SessionID: fmt.Sprintf("session-%d", i)}
+
+			first, err := selectAndComplete(sr, criteria)
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			for j := 0; j < 5; j++ {
+				next, err := selectAndComplete(sr, criteria)
+				if err != nil {
+					t.Fatalf("select failed: %v", err)
+				}
+				if next.Version != first.Version {
+					t.Fatalf("session %s moved from %s to %s", criteria.SessionID, first.Version, next.Version)
+				}
+			}
+			if first.Version == "2.0.0" {
+				canarySessions++
+			}
+		}
+
+		share := float64(canarySessions) / 2000 * 100
+		if share < 3 || share > 7 {
+			t.Errorf("expected about 5%% of sessions on canary, got %.2f%%", share)
+		}
+	})
+
+	t.Run("unavailable canary version falls back", func(t *testing.T) {
+		if err := lb.SetCanaryWeights("search", map[string]int{"3.0.0": 50, "1.0.0": 50}); err != nil {
+			t.Fatalf("failed to set canary weights: %v", err)
+		}
+		for i := 0; i < 100; i++ {
+			service, err := selectAndComplete(sr, SelectionCriteria{})
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			if service.Version != "1.0.0" {
+				t.Fatalf("expected only the available weighted version, got %s", service.Version)
+			}
+		}
+	})
+
+	t.Run("invalid weights are rejected", func(t *testing.T) {
+		for _, weights := range []map[string]int{
+			{"1.0.0": 90, "2.0.0": 5},
+			{"1.0.0": 110, "2.0.0": -10},
+			{},
+		} {
+			if err := lb.SetCanaryWeights("search", weights); err == nil {
+				t.Errorf("expected weights %v to be rejected", weights)
+			}
+		}
+	})
+}
+
+// selectAndComplete selects a search service and records the request as successful
+// so the load balancer's success-rate filter keeps every instance eligible
+func selectAndComplete(sr *ServiceRegistry, criteria SelectionCriteria) (*RegisteredService, error) {
+	service, err := sr.SelectService("search", criteria)
+	if err != nil {
+		return nil, err
+	}
+	sr.GetLoadBalancer().RecordSuccess(service, time.Millisecond)
+	return service, nil
+}
+
+// addTestService registers a healthy service directly, bypassing endpoint health checks
+func addTestService(sr *ServiceRegistry, id, serviceType, version string) {
+	service := &RegisteredService{
+		ID:           id,
+		Name:         id,
+		Type:         serviceType,
+		Version:      version,
+		Status:       StatusActive,
+		Health:       HealthHealthy,
+		RegisteredAt: time.Now(),
+	}
+
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+	sr.services[id] = service
+	sr.byType[serviceType] = append(sr.byType[serviceType], service)
+}
+
+type mockMetrics struct{}
+
+func (m *mockMetrics) Inc(name string, labels ...string)                    {}
+func (m *mockMetrics) Add(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Set(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Observe(name string, value float64, labels ...string) {}
+func (m *mockMetrics) Time(name string, labels ...string) metrics.Timer     { return &mockTimer{} }
+func (m *mockMetrics) WithLabels(labels map[string]string) metrics.Metrics  { return m }
+func (m *mockMetrics) WithPrefix(prefix string) metrics.Metrics             { return m }
+func (m *mockMetrics) GetStats(name string) metrics.MetricStats             { return metrics.MetricStats{} }
+func (m *mockMetrics) GetAllStats() map[string]metrics.MetricStats {
+	return make(map[string]metrics.MetricStats)
+}
+
+type mockTimer struct{}
+
+func (t *mockTimer) Duration() time.Duration { return 0 }
+func (t *mockTimer) Stop() time.Duration     { return 0 }
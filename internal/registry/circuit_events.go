@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"time"
+)
+
+// Circuit breaker states reported in CircuitBreakerEvent
+const (
+	CircuitStateClosed   = "closed"
+	CircuitStateOpen     = "open"
+	CircuitStateHalfOpen = "half_open"
+)
+
+// CircuitBreakerEvent describes a circuit breaker state transition of one service
+type CircuitBreakerEvent struct {
+	ServiceID   string    `json:"service_id"`
+	ServiceName string    `json:"service_name"`
+	ServiceType string    `json:"service_type"`
+	OldState    string    `json:"old_state"`
+	NewState    string    `json:"new_state"`
+	Reason      string    `json:"reason"`
+	Timestamp   time.Time `json:"timestamp"`
+
+	// Failure details the transition was decided on, counted since the
+	// circuit last went half-open
+	ErrorRate      float64 `json:"error_rate"`
+	TotalRequests  int64   `json:"total_requests"`
+	FailedRequests int64   `json:"failed_requests"`
+	LastError      string  `json:"last_error,omitempty"`
+}
+
+// CircuitBreakerObserver is called on every circuit breaker state transition.
+// Observers run with the load balancer lock held and must not block or call
+// back into the load balancer.
+type CircuitBreakerObserver func(event CircuitBreakerEvent)
+
+// AddCircuitBreakerObserver registers an observer for circuit breaker transitions
+func (lb *LoadBalancer) AddCircuitBreakerObserver(observer CircuitBreakerObserver) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+
+	lb.circuitObservers = append(lb.circuitObservers, observer)
+}
+
+// CircuitState returns closed, open or half_open. Call it on a copy from
+// GetServiceStats, or with the load balancer lock held.
+func (ss *ServiceState) CircuitState() string {
+	switch {
+	case ss.CircuitOpen:
+		return CircuitStateOpen
+	case ss.CircuitHalfOpen:
+		return CircuitStateHalfOpen
+	default:
+		return CircuitStateClosed
+	}
+}
+
+// setCircuitState moves a service's circuit breaker to newState and notifies
+// observers when the state changed (assumes lock is held)
+func (lb *LoadBalancer) setCircuitState(state *ServiceState, newState, reason string, lastErr error) {
+	oldState := state.CircuitState()
+	if oldState == newState {
+		return
+	}
+
+	state.CircuitOpen = newState == CircuitStateOpen
+	state.CircuitHalfOpen = newState == CircuitStateHalfOpen
+	if state.CircuitOpen {
+		state.CircuitOpenedAt = time.Now()
+	}
+
+	event := CircuitBreakerEvent{
+		ServiceID:      state.Service.ID,
+		ServiceName:    state.Service.Name,
+		ServiceType:    state.Service.Type,
+		OldState:       oldState,
+		NewState:       newState,
+		Reason:         reason,
+		Timestamp:      time.Now().UTC(),
+		TotalRequests:  state.TrialRequests,
+		FailedRequests: state.TrialFailures,
+	}
+	if state.TrialRequests > 0 {
+		event.ErrorRate = float64(state.TrialFailures) / float64(state.TrialRequests)
+	}
+	if lastErr != nil {
+		event.LastError = lastErr.Error()
+	}
+
+	lb.metrics.Inc("load_balancer_circuit_transitions_total",
+		"service_type", state.Service.Type,
+		"from", oldState,
+		"to", newState)
+
+	for _, observer := range lb.circuitObservers {
+		observer(event)
+	}
+}
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestCircuitBreakerTransitions tests that every circuit breaker state change is reported to observers
+func TestCircuitBreakerTransitions(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+	addTestService(sr, "flaky-1", "flaky", "1.0.0")
+	service := sr.GetService("flaky-1")
+
+	lb := sr.GetLoadBalancer()
+	lb.config.CircuitBreakerTimeout = 10 * time.Millisecond
+
+	var events []CircuitBreakerEvent
+	lb.AddCircuitBreakerObserver(func(event CircuitBreakerEvent) {
+		events = append(events, event)
+	})
+
+	expectTransition := func(t *testing.T, oldState, newState, reason string) CircuitBreakerEvent {
+		t.Helper()
+		if len(events) != 1 {
+			t.Fatalf("expected one %s -> %s transition, got %+v", oldState, newState, events)
+		}
+		event := events[0]
+		events = nil
+		if event.ServiceID != "flaky-1" || event.OldState != oldState || event.NewState != newState || event.Reason != reason {
+			t.Fatalf("expected flaky-1 %s -> %s (%s), got %+v", oldState, newState, reason, event)
+		}
+		return event
+	}
+
+	selectService := func() (*RegisteredService, error) {
+		return lb.SelectService([]*RegisteredService{service}, SelectionCriteria{})
+	}
+	backendErr := errors.New("connection refused")
+
+	t.Run("failures open the circuit", func(t *testing.T) {
+		for i := 0; i < 11; i++ {
+			selected, err := selectService()
+			if err != nil {
+				t.Fatalf("expected the service to be selectable before the circuit opens: %v", err)
+			}
+			lb.RecordFailure(selected, backendErr)
+		}
+
+		event := expectTransition(t, CircuitStateClosed, CircuitStateOpen, "error_rate_exceeded")
+		if event.FailedRequests != 11 || event.ErrorRate != 1 || event.LastError != "connection refused" {
+			t.Errorf("expected the failure details in the event, got %+v", event)
+		}
+		if _, err := selectService(); err == nil {
+			t.Error("expected an open circuit to exclude the service")
+		}
+	})
+
+	t.Run("failed trial reopens the circuit", func(t *testing.T) {
+		time.Sleep(20 * time.Millisecond)
+		selected, err := selectService()
+		if err != nil {
+			t.Fatalf("expected trial traffic once the timeout elapsed: %v", err)
+		}
+		expectTransition(t, CircuitStateOpen, CircuitStateHalfOpen, "recovery_timeout_elapsed")
+
+		lb.RecordFailure(selected, backendErr)
+		expectTransition(t, CircuitStateHalfOpen, CircuitStateOpen, "trial_request_failed")
+	})
+
+	t.Run("successful trial closes the circuit", func(t *testing.T) {
+		time.Sleep(20 * time.Millisecond)
+		selected, err := selectService()
+		if err != nil {
+			t.Fatalf("expected trial traffic once the timeout elapsed: %v", err)
+		}
+		expectTransition(t, CircuitStateOpen, CircuitStateHalfOpen, "recovery_timeout_elapsed")
+
+		lb.RecordSuccess(selected, time.Millisecond)
+		expectTransition(t, CircuitStateHalfOpen, CircuitStateClosed, "trial_request_succeeded")
+		stats := lb.GetServiceStats("flaky-1")
+		if state := stats.CircuitState(); state != CircuitStateClosed {
+			t.Errorf("expected the circuit to be closed, got %s", state)
+		}
+		if stats.TotalRequests != 13 || stats.FailedRequests != 12 || stats.SuccessRequests != 1 {
+			t.Errorf("expected lifetime totals of 13 requests, 12 failed, 1 succeeded, got %d, %d, %d",
+				stats.TotalRequests, stats.FailedRequests, stats.SuccessRequests)
+		}
+		if stats.TrialRequests != 1 || stats.TrialSuccesses != 1 || stats.TrialFailures != 0 {
+			t.Errorf("expected the trial to count only its own request, got %d, %d, %d",
+				stats.TrialRequests, stats.TrialSuccesses, stats.TrialFailures)
+		}
+	})
+
+	t.Run("resetting a closed circuit is not a transition", func(t *testing.T) {
+		lb.ResetCircuitBreaker("flaky-1")
+		if len(events) != 0 {
+			t.Errorf("expected no event, got %+v", events)
+		}
+	})
+}
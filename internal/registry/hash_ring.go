@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hashRingReplicas is the number of virtual nodes per backend. More points
+// spread keys more evenly at the cost of a larger ring.
+const hashRingReplicas = 160
+
+// hashRing maps keys to services by consistent hashing, so adding or removing
+// a backend only moves the keys that hashed to that backend's points
+type hashRing struct {
+	members string // Sorted member IDs the ring was built from
+	points  []uint64
+	owners  map[uint64]string // Point to service ID
+}
+
+func newHashRing(services []*RegisteredService) *hashRing {
+	ring := &hashRing{
+		members: hashRingMembers(services),
+		points:  make([]uint64, 0, len(services)*hashRingReplicas),
+		owners:  make(map[uint64]string, len(services)*hashRingReplicas),
+	}
+
+	for _, service := range services {
+		for i := 0; i < hashRingReplicas; i++ {
+			point := hashRingKey(service.ID + "#" + strconv.Itoa(i))
+			// On the rare collision the lower ID keeps the point, independent of order
+			if owner, exists := ring.owners[point]; exists && owner < service.ID {
+				continue
+			} else if !exists {
+				ring.points = append(ring.points, point)
+			}
+			ring.owners[point] = service.ID
+		}
+	}
+
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// get returns the ID of the service owning the first point at or after the key's hash
+func (r *hashRing) get(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	hash := hashRingKey(key)
+	index := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= hash })
+	if index == len(r.points) {
+		index = 0
+	}
+	return r.owners[r.points[index]]
+}
+
+func hashRingKey(key string) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(key))
+	// FNV clusters similar inputs; a final mix spreads virtual nodes around the ring
+	h := hasher.Sum64()
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func hashRingMembers(services []*RegisteredService) string {
+	ids := make([]string, len(services))
+	for i, service := range services {
+		ids[i] = service.ID
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// hashRingFor returns the ring for a service type's candidates, rebuilding it
+// only when the set of candidates changes
+func (lb *LoadBalancer) hashRingFor(services []*RegisteredService) *hashRing {
+	serviceType := services[0].Type
+	members := hashRingMembers(services)
+
+	lb.mutex.RLock()
+	ring := lb.hashRings[serviceType]
+	lb.mutex.RUnlock()
+	if ring != nil && ring.members == members {
+		return ring
+	}
+
+	ring = newHashRing(services)
+
+	lb.mutex.Lock()
+	lb.hashRings[serviceType] = ring
+	lb.mutex.Unlock()
+
+	lb.logger.Debug("hash_ring_rebuilt",
+		"service_type", serviceType,
+		"members", len(services),
+		"points", len(ring.points))
+
+	return ring
+}
@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestHashStrategySessionAffinity tests consistent hashing of session keys onto backends
+func TestHashStrategySessionAffinity(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	for i := 0; i < 4; i++ {
+		addTestService(sr, fmt.Sprintf("search-%d", i), "search", "1.0.0")
+	}
+	if err := sr.GetLoadBalancer().SetStrategy("", "hash"); err != nil {
+		t.Fatalf("failed to set hash strategy: %v", err)
+	}
+
+	assign := func(t *testing.T) map[string]string {
+		t.Helper()
+		assignments := make(map[string]string)
+		for i := 0; i < 2000; i++ {
+			session := fmt.Sprintf("session-%d", i)
+			service, err := selectAndComplete(sr, SelectionCriteria{SessionID: session})
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			assignments[session] = service.ID
+		}
+		return assignments
+	}
+
+	before := assign(t)
+
+	t.Run("same session key maps to the same backend", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			service, err := selectAndComplete(sr, SelectionCriteria{SessionID: "session-42"})
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			if service.ID != before["session-42"] {
+				t.Fatalf("expected session-42 to stay on %s, got %s", before["session-42"], service.ID)
+			}
+		}
+	})
+
+	t.Run("keys spread across all backends", func(t *testing.T) {
+		counts := make(map[string]int)
+		for _, serviceID := range before {
+			counts[serviceID]++
+		}
+		for i := 0; i < 4; i++ {
+			if share := counts[fmt.Sprintf("search-%d", i)]; share < 300 || share > 700 {
+				t.Errorf("expected roughly a quarter of keys on search-%d, got %d of 2000", i, share)
+			}
+		}
+	})
+
+	t.Run("removing a backend moves only its keys", func(t *testing.T) {
+		sr.GetService("search-2").Health = HealthUnhealthy
+		defer func() { sr.GetService("search-2").Health = HealthHealthy }()
+
+		after := assign(t)
+		for session, previous := range before {
+			switch {
+			case previous == "search-2" && after[session] == "search-2":
+				t.Errorf("expected %s to leave the removed backend", session)
+			case previous != "search-2" && after[session] != previous:
+				t.Errorf("expected %s to stay on %s, moved to %s", session, previous, after[session])
+			}
+		}
+	})
+}
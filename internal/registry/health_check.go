@@ -0,0 +1,336 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Registration metadata keys for richer health check protocols
+const (
+	HealthMethodKey      = "health_method"       // HTTP method, GET by default
+	HealthBodyKey        = "health_body"         // Request body; non-string values are sent as JSON
+	HealthContentTypeKey = "health_content_type" // Body content type, application/json by default
+	HealthJSONAssertKey  = "health_json_assert"  // e.g. $.status == "UP"
+
+	HealthConnectTimeoutKey = "health_connect_timeout" // Dial timeout, e.g. "2s" or a number of seconds
+	HealthTimeoutKey        = "health_timeout"         // Whole-request timeout, including reading the response
+)
+
+// Health check timeouts used when a service does not configure its own
+const (
+	DefaultHealthConnectTimeout = 3 * time.Second
+	DefaultHealthTimeout        = 5 * time.Second
+
+	// maxHealthTimeout bounds configured timeouts, as services are checked
+	// one after another and a long timeout delays the checks of the rest
+	maxHealthTimeout = 2 * time.Minute
+)
+
+// healthCheckMethods are the methods a health check may use
+var healthCheckMethods = map[string]bool{
+	http.MethodGet:  true,
+	http.MethodHead: true,
+	http.MethodPost: true,
+	http.MethodPut:  true,
+}
+
+// validateHealthCheckMetadata rejects health check settings that could never
+// pass, so misconfigured services fail at registration rather than at every probe
+func validateHealthCheckMetadata(metadata map[string]interface{}) error {
+	if _, err := healthCheckMethod(metadata); err != nil {
+		return err
+	}
+	if _, _, err := healthCheckBody(metadata); err != nil {
+		return err
+	}
+	if _, _, err := healthCheckTimeouts(metadata); err != nil {
+		return err
+	}
+	if expr, ok := metadata[HealthJSONAssertKey].(string); ok && expr != "" {
+		if _, err := parseJSONAssertion(expr); err != nil {
+			return fmt.Errorf("invalid %s: %w", HealthJSONAssertKey, err)
+		}
+	}
+	return nil
+}
+
+func healthCheckMethod(metadata map[string]interface{}) (string, error) {
+	method, _ := metadata[HealthMethodKey].(string)
+	if method == "" {
+		return http.MethodGet, nil
+	}
+
+	method = strings.ToUpper(method)
+	if !healthCheckMethods[method] {
+		return "", fmt.Errorf("unsupported %s: %s", HealthMethodKey, method)
+	}
+	return method, nil
+}
+
+// healthCheckBody returns the request body and its content type, or a nil
+// reader when no body is configured
+func healthCheckBody(metadata map[string]interface{}) (io.Reader, string, error) {
+	body, exists := metadata[HealthBodyKey]
+	if !exists || body == nil {
+		return nil, "", nil
+	}
+
+	contentType, _ := metadata[HealthContentTypeKey].(string)
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	if text, ok := body.(string); ok {
+		return strings.NewReader(text), contentType, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s: %w", HealthBodyKey, err)
+	}
+	return bytes.NewReader(data), contentType, nil
+}
+
+// healthCheckTimeouts returns the connect and request timeouts for a
+// service's health checks, falling back to the defaults
+func healthCheckTimeouts(metadata map[string]interface{}) (connect, request time.Duration, err error) {
+	if connect, err = healthTimeout(metadata, HealthConnectTimeoutKey, DefaultHealthConnectTimeout); err != nil {
+		return 0, 0, err
+	}
+	if request, err = healthTimeout(metadata, HealthTimeoutKey, DefaultHealthTimeout); err != nil {
+		return 0, 0, err
+	}
+	return connect, request, nil
+}
+
+// healthTimeout reads a timeout given as a duration string or a number of
+// seconds, as JSON registration requests carry numbers as float64
+func healthTimeout(metadata map[string]interface{}, key string, fallback time.Duration) (time.Duration, error) {
+	var timeout time.Duration
+	switch value := metadata[key].(type) {
+	case nil:
+		return fallback, nil
+	case string:
+		if value == "" {
+			return fallback, nil
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		timeout = parsed
+	case float64:
+		timeout = time.Duration(value * float64(time.Second))
+	case int:
+		timeout = time.Duration(value) * time.Second
+	default:
+		return 0, fmt.Errorf("invalid %s: expected a duration such as \"10s\", got %T", key, value)
+	}
+
+	if timeout <= 0 || timeout > maxHealthTimeout {
+		return 0, fmt.Errorf("invalid %s: %s must be positive and at most %s", key, timeout, maxHealthTimeout)
+	}
+	return timeout, nil
+}
+
+// newHealthCheckClient builds the client for one health check with the
+// service's timeouts and TLS policy
+func newHealthCheckClient(service *RegisteredService) (*http.Client, error) {
+	connectTimeout, requestTimeout, err := healthCheckTimeouts(service.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := UpstreamTLSConfig(service.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			DialContext:       (&net.Dialer{Timeout: connectTimeout}).DialContext,
+			TLSClientConfig:   tlsConfig,
+			DisableKeepAlives: true,
+			IdleConnTimeout:   3 * time.Second,
+		},
+	}, nil
+}
+
+// newHealthCheckRequest builds the health check request from registration metadata
+func newHealthCheckRequest(ctx context.Context, service *RegisteredService, healthURL string) (*http.Request, error) {
+	method, err := healthCheckMethod(service.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	body, contentType, err := healthCheckBody(service.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, healthURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// jsonAssertion checks a value in a JSON health response, written as a
+// JSONPath followed by an optional comparison, e.g. $.status == "UP" or
+// $.checks[0].ok != false. A bare path asserts that the value exists.
+type jsonAssertion struct {
+	expr     string
+	path     []interface{} // string field names and int indexes
+	operator string        // "==", "!=" or "" for existence
+	expected interface{}
+}
+
+func parseJSONAssertion(expr string) (*jsonAssertion, error) {
+	path, rest, err := parseJSONPath(strings.TrimSpace(expr))
+	if err != nil {
+		return nil, err
+	}
+
+	assertion := &jsonAssertion{expr: expr, path: path}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return assertion, nil
+	}
+	if len(rest) < 2 || (rest[:2] != "==" && rest[:2] != "!=") {
+		return nil, fmt.Errorf("expected == or != after path, got %q", rest)
+	}
+	assertion.operator = rest[:2]
+
+	literal := strings.TrimSpace(rest[2:])
+	if err := json.Unmarshal([]byte(literal), &assertion.expected); err != nil {
+		return nil, fmt.Errorf("expected value %q is not a JSON literal: %w", literal, err)
+	}
+	return assertion, nil
+}
+
+// parseJSONPath parses the supported JSONPath subset: $ followed by .field,
+// ['field'] and [index] segments. It returns the unparsed remainder.
+func parseJSONPath(expr string) ([]interface{}, string, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, "", fmt.Errorf("path must start with $")
+	}
+	expr = expr[1:]
+
+	var path []interface{}
+	for expr != "" {
+		switch expr[0] {
+		case '.':
+			end := strings.IndexAny(expr[1:], ".[ =!")
+			if end < 0 {
+				end = len(expr) - 1
+			}
+			name := expr[1 : end+1]
+			if name == "" {
+				return nil, "", fmt.Errorf("empty field name in path")
+			}
+			path = append(path, name)
+			expr = expr[end+1:]
+		case '[':
+			end := strings.IndexByte(expr, ']')
+			if end < 0 {
+				return nil, "", fmt.Errorf("unterminated [ in path")
+			}
+			segment := expr[1:end]
+			if len(segment) >= 2 && (segment[0] == '\'' || segment[0] == '"') && segment[len(segment)-1] == segment[0] {
+				path = append(path, segment[1:len(segment)-1])
+			} else if index, err := strconv.Atoi(segment); err == nil && index >= 0 {
+				path = append(path, index)
+			} else {
+				return nil, "", fmt.Errorf("invalid path segment [%s]", segment)
+			}
+			expr = expr[end+1:]
+		default:
+			return path, expr, nil
+		}
+	}
+	return path, "", nil
+}
+
+// evaluate checks the assertion against a JSON response body
+func (a *jsonAssertion) evaluate(body []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("health response is not JSON, cannot evaluate %s: %w", a.expr, err)
+	}
+
+	value, found := lookupJSONPath(doc, a.path)
+	switch a.operator {
+	case "":
+		if !found {
+			return fmt.Errorf("health assertion %s failed: value not found", a.expr)
+		}
+	case "==":
+		if !found || !reflect.DeepEqual(value, a.expected) {
+			return fmt.Errorf("health assertion %s failed: got %v", a.expr, value)
+		}
+	case "!=":
+		if found && reflect.DeepEqual(value, a.expected) {
+			return fmt.Errorf("health assertion %s failed: got %v", a.expr, value)
+		}
+	}
+	return nil
+}
+
+func lookupJSONPath(doc interface{}, path []interface{}) (interface{}, bool) {
+	current := doc
+	for _, segment := range path {
+		switch key := segment.(type) {
+		case string:
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[key]; !ok {
+				return nil, false
+			}
+		case int:
+			array, ok := current.([]interface{})
+			if !ok || key >= len(array) {
+				return nil, false
+			}
+			current = array[key]
+		}
+	}
+	return current, true
+}
+
+// validateHealthJSONAssertion applies the health_json_assert metadata, if any
+func (sr *ServiceRegistry) validateHealthJSONAssertion(body []byte, service *RegisteredService) error {
+	expr, ok := service.Metadata[HealthJSONAssertKey].(string)
+	if !ok || expr == "" {
+		return nil
+	}
+
+	assertion, err := parseJSONAssertion(expr)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", HealthJSONAssertKey, err)
+	}
+	return assertion.evaluate(body)
+}
+
+// CheckServiceHealth runs an immediate health check against a registered
+// service and records the result, returning the failure if it is unhealthy
+func (sr *ServiceRegistry) CheckServiceHealth(ctx context.Context, serviceID string) error {
+	service := sr.GetService(serviceID)
+	if service == nil {
+		return fmt.Errorf("service not found: %s", serviceID)
+	}
+	return sr.performHealthCheck(ctx, service)
+}
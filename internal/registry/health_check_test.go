@@ -0,0 +1,152 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestHealthCheckMethodAndAssertion tests POST health checks with a body and JSONPath response assertions
+func TestHealthCheckMethodAndAssertion(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(body) != `{"probe":"deep"}` || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"UP","components":[{"name":"db","status":"DOWN"}]}`))
+	}))
+	defer backend.Close()
+
+	check := func(t *testing.T, metadata map[string]interface{}) HealthStatus {
+		t.Helper()
+		service := &RegisteredService{ID: "health-test", Type: "health_test", Endpoint: backend.URL, Metadata: metadata}
+		sr.performHealthCheck(context.Background(), service)
+		return service.Health
+	}
+
+	post := func(assertion string) map[string]interface{} {
+		return map[string]interface{}{
+			HealthMethodKey:     "post",
+			HealthBodyKey:       map[string]interface{}{"probe": "deep"},
+			HealthJSONAssertKey: assertion,
+		}
+	}
+
+	t.Run("POST with body and passing assertion is healthy", func(t *testing.T) {
+		if got := check(t, post(`$.status == "UP"`)); got != HealthHealthy {
+			t.Errorf("expected healthy, got %s", got)
+		}
+	})
+
+	t.Run("failing assertion is unhealthy", func(t *testing.T) {
+		if got := check(t, post(`$.components[0].status == "UP"`)); got != HealthUnhealthy {
+			t.Errorf("expected unhealthy, got %s", got)
+		}
+	})
+
+	t.Run("default GET is rejected by a POST-only backend", func(t *testing.T) {
+		if got := check(t, nil); got != HealthUnhealthy {
+			t.Errorf("expected unhealthy, got %s", got)
+		}
+	})
+
+	t.Run("invalid settings are rejected at registration", func(t *testing.T) {
+		for _, metadata := range []map[string]interface{}{
+			{HealthMethodKey: "DELETE"},
+			{HealthJSONAssertKey: "status == UP"},
+			{HealthJSONAssertKey: `$.status ~= "UP"`},
+		} {
+			if err := validateHealthCheckMetadata(metadata); err == nil {
+				t.Errorf("expected %v to be rejected", metadata)
+			}
+		}
+	})
+}
+
+// TestHealthCheckTimeouts tests per-service health check timeouts from registration metadata
+func TestHealthCheckTimeouts(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("OK"))
+	}))
+	defer slow.Close()
+
+	hung := make(chan struct{})
+	hungBackend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-hung:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hungBackend.Close()
+	defer close(hung)
+
+	check := func(t *testing.T, endpoint string, metadata map[string]interface{}) (HealthStatus, time.Duration) {
+		t.Helper()
+		service := &RegisteredService{ID: "timeout-test", Type: "timeout_test", Endpoint: endpoint, Metadata: metadata}
+		start := time.Now()
+		sr.performHealthCheck(context.Background(), service)
+		return service.Health, time.Since(start)
+	}
+
+	t.Run("slow backend within a longer timeout is healthy", func(t *testing.T) {
+		if got, _ := check(t, slow.URL, map[string]interface{}{HealthTimeoutKey: "2s"}); got != HealthHealthy {
+			t.Errorf("expected healthy, got %s", got)
+		}
+	})
+
+	t.Run("slow backend beyond a shorter timeout is unhealthy", func(t *testing.T) {
+		if got, _ := check(t, slow.URL, map[string]interface{}{HealthTimeoutKey: 0.05}); got != HealthUnhealthy {
+			t.Errorf("expected unhealthy, got %s", got)
+		}
+	})
+
+	t.Run("hung backend still times out", func(t *testing.T) {
+		got, elapsed := check(t, hungBackend.URL, map[string]interface{}{HealthTimeoutKey: "300ms"})
+		if got != HealthUnhealthy {
+			t.Errorf("expected unhealthy, got %s", got)
+		}
+		if elapsed > 2*time.Second {
+			t.Errorf("expected the check to give up after about 300ms, took %s", elapsed)
+		}
+	})
+
+	t.Run("invalid timeouts are rejected at registration", func(t *testing.T) {
+		for _, metadata := range []map[string]interface{}{
+			{HealthTimeoutKey: "soon"},
+			{HealthTimeoutKey: "-1s"},
+			{HealthConnectTimeoutKey: 0.0},
+			{HealthTimeoutKey: "1h"},
+			{HealthConnectTimeoutKey: true},
+		} {
+			if err := validateHealthCheckMetadata(metadata); err == nil {
+				t.Errorf("expected %v to be rejected", metadata)
+			}
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Weight of the newest latency sample in a backend's moving average
+	loadAwareSmoothing = 0.3
+
+	// A failure counts as at least this multiple of the backend's latency
+	// average, so a backend that fails fast does not look fast
+	loadAwareFailurePenalty = 2.0
+
+	// Latency a failure counts as on a backend without a latency average yet
+	loadAwareUnsampledFailure = time.Second
+
+	// Time over which an idle backend's latency estimate decays by a factor
+	// of e, so a backend passed over while slow is eventually tried again
+	loadAwareIdleDecay = 10 * time.Second
+)
+
+// observeLatency folds a request duration into the exponentially weighted
+// moving average of the backend's latency; the caller holds the lock
+func (ss *ServiceState) observeLatency(duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+	if ss.LatencyEWMA == 0 {
+		ss.LatencyEWMA = duration
+		return
+	}
+	ss.LatencyEWMA = time.Duration(loadAwareSmoothing*float64(duration) + (1-loadAwareSmoothing)*float64(ss.LatencyEWMA))
+}
+
+// observeFailureLatency folds a failed request into the latency average as
+// the longer of its duration and the penalized average; the caller holds
+// the lock
+func (ss *ServiceState) observeFailureLatency(duration time.Duration) {
+	penalty := time.Duration(loadAwareFailurePenalty * float64(ss.LatencyEWMA))
+	if ss.LatencyEWMA == 0 {
+		penalty = loadAwareUnsampledFailure
+	}
+	if penalty > duration {
+		duration = penalty
+	}
+	ss.observeLatency(duration)
+}
+
+// loadScore estimates how long a new request would take on the backend: its
+// latency average, decayed while it sits idle, scaled by the requests it is
+// already serving. Backends without a latency sample score zero.
+func (ss *ServiceState) loadScore(now time.Time) float64 {
+	latency := float64(ss.LatencyEWMA)
+	if !ss.LastUsed.IsZero() {
+		if idle := now.Sub(ss.LastUsed); idle > 0 {
+			latency *= math.Exp(-float64(idle) / float64(loadAwareIdleDecay))
+		}
+	}
+	active := ss.ActiveRequests
+	if active < 0 {
+		active = 0
+	}
+	return latency * float64(active+1)
+}
+
+// selectLoadAware picks the backend with the lowest load score, so traffic
+// moves away from backends whose latency climbs or whose requests pile up.
+// Ties go to the least recently used backend.
+func (lb *LoadBalancer) selectLoadAware(services []*RegisteredService) *RegisteredService {
+	if len(services) == 0 {
+		return nil
+	}
+
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+
+	now := time.Now()
+	var selected *RegisteredService
+	var selectedState *ServiceState
+	bestScore := math.Inf(1)
+	for _, service := range services {
+		state := lb.getOrCreateServiceState(service)
+		score := state.loadScore(now)
+		if score < bestScore || (score == bestScore && state.LastUsed.Before(selectedState.LastUsed)) {
+			selected, selectedState, bestScore = service, state, score
+		}
+	}
+	return selected
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestLoadAwareStrategy tests that load-aware selection shifts traffic away
+// from a backend as its latency climbs and away from backends with requests
+// piling up
+func TestLoadAwareStrategy(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	for i := 0; i < 3; i++ {
+		addTestService(sr, fmt.Sprintf("search-%d", i), "search", "1.0.0")
+	}
+	lb := sr.GetLoadBalancer()
+	if err := lb.SetStrategy("", "load_aware"); err != nil {
+		t.Fatalf("failed to set load_aware strategy: %v", err)
+	}
+
+	// Every backend answers in 10ms except search-0, whose latency is raised
+	// between rounds
+	slowLatency := 10 * time.Millisecond
+	route := func(t *testing.T, requests int) map[string]int {
+		t.Helper()
+		counts := make(map[string]int)
+		for i := 0; i < requests; i++ {
+			service, err := sr.SelectService("search", SelectionCriteria{})
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			counts[service.ID]++
+
+			latency := 10 * time.Millisecond
+			if service.ID == "search-0" {
+				latency = slowLatency
+			}
+			lb.RecordSuccess(service, latency)
+		}
+		return counts
+	}
+
+	t.Run("equal backends share traffic", func(t *testing.T) {
+		counts := route(t, 30)
+		for i := 0; i < 3; i++ {
+			if counts[fmt.Sprintf("search-%d", i)] != 10 {
+				t.Fatalf("expected an even split, got %v", counts)
+			}
+		}
+	})
+
+	t.Run("selection shifts away as latency climbs", func(t *testing.T) {
+		slowLatency = 40 * time.Millisecond
+		counts := route(t, 30)
+		if counts["search-0"] == 0 || counts["search-0"] > 2 {
+			t.Errorf("expected the slowed backend to be sampled and then avoided, got %v", counts)
+		}
+		if stats := lb.GetServiceStats("search-0"); stats.LatencyEWMA <= lb.GetServiceStats("search-1").LatencyEWMA {
+			t.Errorf("expected the slow backend to have the higher latency average, got %s", stats.LatencyEWMA)
+		}
+	})
+
+	t.Run("in-flight requests deprioritize a backend", func(t *testing.T) {
+		held, err := sr.SelectService("search", SelectionCriteria{})
+		if err != nil {
+			t.Fatalf("select failed: %v", err)
+		}
+		if held.ID == "search-0" {
+			t.Fatal("expected a fast backend to be chosen")
+		}
+		for i := 0; i < 5; i++ {
+			service, err := sr.SelectService("search", SelectionCriteria{})
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			if service.ID == held.ID {
+				t.Fatalf("expected %s to be avoided while it has a request in flight", held.ID)
+			}
+			lb.RecordSuccess(service, 10*time.Millisecond)
+		}
+		lb.RecordSuccess(held, 10*time.Millisecond)
+	})
+
+	t.Run("idle slow backend decays back into rotation", func(t *testing.T) {
+		lb.mutex.Lock()
+		lb.serviceState["search-0"].LastUsed = time.Now().Add(-5 * time.Minute)
+		lb.mutex.Unlock()
+
+		counts := route(t, 3)
+		if counts["search-0"] == 0 {
+			t.Errorf("expected the long idle backend to be tried again, got %v", counts)
+		}
+	})
+}
+
+// TestLoadAwareFastFailures tests that a backend failing fast is avoided
+// rather than favoured for its short response times
+func TestLoadAwareFastFailures(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	for i := 0; i < 2; i++ {
+		addTestService(sr, fmt.Sprintf("search-%d", i), "search", "1.0.0")
+	}
+	lb := sr.GetLoadBalancer()
+	if err := lb.SetStrategy("", "load_aware"); err != nil {
+		t.Fatalf("failed to set load_aware strategy: %v", err)
+	}
+
+	// Both backends have served requests in 10ms when search-0 starts
+	// answering every request with an error after 1ms
+	for i := 0; i < 4; i++ {
+		service, err := sr.SelectService("search", SelectionCriteria{})
+		if err != nil {
+			t.Fatalf("select failed: %v", err)
+		}
+		lb.RecordSuccess(service, 10*time.Millisecond)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 30; i++ {
+		service, err := sr.SelectService("search", SelectionCriteria{})
+		if err != nil {
+			t.Fatalf("select failed: %v", err)
+		}
+		counts[service.ID]++
+		if service.ID == "search-0" {
+			lb.RecordFailureAfter(service, fmt.Errorf("backend error"), time.Millisecond)
+		} else {
+			lb.RecordSuccess(service, 10*time.Millisecond)
+		}
+	}
+
+	if counts["search-0"] > 2 {
+		t.Errorf("expected the fast-failing backend to be avoided, got %v", counts)
+	}
+	if lb.GetServiceStats("search-0").LatencyEWMA <= lb.GetServiceStats("search-1").LatencyEWMA {
+		t.Errorf("expected failures to raise the latency average above the healthy backend's, got %s and %s",
+			lb.GetServiceStats("search-0").LatencyEWMA, lb.GetServiceStats("search-1").LatencyEWMA)
+	}
+
+	t.Run("failure before any latency sample is penalized", func(t *testing.T) {
+		addTestService(sr, "search-2", "search", "1.0.0")
+		service := sr.GetService("search-2")
+		lb.updateServiceSelection(service)
+		lb.RecordFailureAfter(service, fmt.Errorf("backend error"), time.Millisecond)
+		if stats := lb.GetServiceStats("search-2"); stats.LatencyEWMA < time.Second {
+			t.Errorf("expected a first fast failure to count as slow, got %s", stats.LatencyEWMA)
+		}
+	})
+
+	t.Run("failure without a duration still counts", func(t *testing.T) {
+		service := sr.GetService("search-1")
+		before := lb.GetServiceStats("search-1").LatencyEWMA
+		lb.updateServiceSelection(service)
+		lb.RecordFailure(service, fmt.Errorf("backend error"))
+		if after := lb.GetServiceStats("search-1").LatencyEWMA; after <= before {
+			t.Errorf("expected the latency average to rise from %s, got %s", before, after)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package registry
 
 import (
 	"fmt"
-	"hash/fnv"
 	"math/rand"
 	"sync"
 	"time"
@@ -21,11 +20,23 @@ type LoadBalancer struct {
 	// Per-service state tracking
 	serviceState map[string]*ServiceState
 	mutex        sync.RWMutex
+
+	// Canary traffic split per service type, keyed by version
+	canaryWeights map[string]map[string]int
+
+	// Strategy overrides per service type
+	typeStrategies map[string]string
+
+	// Consistent hash rings for the hash strategy, per service type
+	hashRings map[string]*hashRing
+
+	// Notified of circuit breaker state transitions
+	circuitObservers []CircuitBreakerObserver
 }
 
 // LoadBalancerConfig configures load balancing behavior
 type LoadBalancerConfig struct {
-	Strategy              string        `yaml:"strategy"`          // round_robin, least_connections, weighted, hash
+	Strategy              string        `yaml:"strategy"`          // round_robin, least_connections, weighted, hash, random, load_aware
 	HealthyThreshold      float64       `yaml:"healthy_threshold"` // 0.95 = 95% success rate
 	CircuitBreakerEnabled bool          `yaml:"circuit_breaker_enabled"`
 	CircuitBreakerTimeout time.Duration `yaml:"circuit_breaker_timeout"`
@@ -43,7 +54,16 @@ type ServiceState struct {
 	LastUsed        time.Time
 	CircuitOpen     bool
 	CircuitOpenedAt time.Time
+	CircuitHalfOpen bool // Trial traffic after the open timeout; the next result closes or reopens it
 	Weight          int
+	LatencyEWMA     time.Duration // Moving average of request latency, for the load_aware strategy
+
+	// Requests since the circuit last went half-open. Circuit and health
+	// decisions use these, so a recovered backend is not judged on the
+	// failures that opened its circuit; the totals above cover its lifetime.
+	TrialRequests  int64
+	TrialSuccesses int64
+	TrialFailures  int64
 
 	// Sticky session tracking
 	Sessions map[string]time.Time
@@ -71,7 +91,12 @@ func (ss *ServiceState) Copy() *ServiceState {
 		LastUsed:        ss.LastUsed,
 		CircuitOpen:     ss.CircuitOpen,
 		CircuitOpenedAt: ss.CircuitOpenedAt,
+		CircuitHalfOpen: ss.CircuitHalfOpen,
 		Weight:          ss.Weight,
+		TrialRequests:   ss.TrialRequests,
+		TrialSuccesses:  ss.TrialSuccesses,
+		TrialFailures:   ss.TrialFailures,
+		LatencyEWMA:     ss.LatencyEWMA,
 		Sessions:        sessionsCopy,
 		// mutex is not copied - new mutex will be zero-value initialized
 	}
@@ -88,11 +113,14 @@ type SessionContext struct {
 // NewLoadBalancer creates a new load balancer
 func NewLoadBalancer(registry *ServiceRegistry, logger logging.Logger, metrics metrics.Metrics) *LoadBalancer {
 	return &LoadBalancer{
-		registry:     registry,
-		logger:       logger.WithComponent("load_balancer"),
-		metrics:      metrics,
-		config:       defaultLoadBalancerConfig(),
-		serviceState: make(map[string]*ServiceState),
+		registry:       registry,
+		logger:         logger.WithComponent("load_balancer"),
+		metrics:        metrics,
+		config:         defaultLoadBalancerConfig(),
+		serviceState:   make(map[string]*ServiceState),
+		canaryWeights:  make(map[string]map[string]int),
+		typeStrategies: make(map[string]string),
+		hashRings:      make(map[string]*hashRing),
 	}
 }
 
@@ -108,10 +136,14 @@ func (lb *LoadBalancer) SelectService(services []*RegisteredService, criteria Se
 		return nil, fmt.Errorf("no healthy services available")
 	}
 
-	// Apply selection strategy
+	// Prefer the caller's region, falling back to others when it has no healthy backend
+	healthyServices = lb.applyRegionAffinity(healthyServices, criteria)
+
+	// Apply selection strategy; candidates share a service type
+	strategy := lb.GetEffectiveStrategy(healthyServices[0].Type)
 	var selected *RegisteredService
 
-	switch lb.config.Strategy {
+	switch strategy {
 	case "round_robin":
 		selected = lb.selectRoundRobin(healthyServices)
 	case "least_connections":
@@ -122,12 +154,14 @@ func (lb *LoadBalancer) SelectService(services []*RegisteredService, criteria Se
 		selected = lb.selectHash(healthyServices, criteria)
 	case "random":
 		selected = lb.selectRandom(healthyServices)
+	case "load_aware":
+		selected = lb.selectLoadAware(healthyServices)
 	default:
 		selected = lb.selectRoundRobin(healthyServices)
 	}
 
 	if selected == nil {
-		return nil, fmt.Errorf("failed to select service using strategy: %s", lb.config.Strategy)
+		return nil, fmt.Errorf("failed to select service using strategy: %s", strategy)
 	}
 
 	// Update service state
@@ -135,7 +169,7 @@ func (lb *LoadBalancer) SelectService(services []*RegisteredService, criteria Se
 
 	lb.logger.Debug("service_selected",
 		"service_id", selected.ID,
-		"strategy", lb.config.Strategy,
+		"strategy", strategy,
 		"total_candidates", len(services),
 		"healthy_candidates", len(healthyServices))
 
@@ -144,25 +178,34 @@ func (lb *LoadBalancer) SelectService(services []*RegisteredService, criteria Se
 
 // filterHealthyServices filters services based on health and circuit breaker state
 func (lb *LoadBalancer) filterHealthyServices(services []*RegisteredService) []*RegisteredService {
-	lb.mutex.RLock()
-	defer lb.mutex.RUnlock()
+	// Basic health check, read under the registry lock before taking ours
+	candidates := make([]*RegisteredService, 0, len(services))
+	for _, service := range services {
+		if status, health := lb.registry.serviceState(service); health == HealthHealthy && status == StatusActive {
+			candidates = append(candidates, service)
+		}
+	}
+
+	// Write lock: circuit breakers may move to half-open here
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
 
 	var healthy []*RegisteredService
 
-	for _, service := range services {
-		// Basic health check
-		if service.Health != HealthHealthy || service.Status != StatusActive {
-			continue
-		}
+	for _, service := range candidates {
 
 		// Circuit breaker check
 		if lb.config.CircuitBreakerEnabled {
 			state := lb.getOrCreateServiceState(service)
 			if state.CircuitOpen {
-				// Check if circuit should be closed
+				// Let trial traffic through once the timeout has elapsed
 				if time.Since(state.CircuitOpenedAt) > lb.config.CircuitBreakerTimeout {
-					state.CircuitOpen = false
-					lb.logger.Info("circuit_breaker_closed",
+					lb.setCircuitState(state, CircuitStateHalfOpen, "recovery_timeout_elapsed", nil)
+					// Judge the trial on its own results, not the failures that opened the circuit
+					state.TrialRequests = state.ActiveRequests
+					state.TrialSuccesses = 0
+					state.TrialFailures = 0
+					lb.logger.Info("circuit_breaker_half_open",
 						"service_id", service.ID,
 						"timeout_duration", lb.config.CircuitBreakerTimeout)
 				} else {
@@ -171,10 +214,11 @@ func (lb *LoadBalancer) filterHealthyServices(services []*RegisteredService) []*
 			}
 		}
 
-		// Success rate check
+		// Success rate check over completed requests, so requests still in
+		// flight do not count as failures
 		state := lb.getOrCreateServiceState(service)
-		if state.TotalRequests > 10 { // Only check after minimum requests
-			successRate := float64(state.SuccessRequests) / float64(state.TotalRequests)
+		if completed := state.TrialRequests - state.ActiveRequests; completed > 10 { // Only check after minimum requests
+			successRate := float64(state.TrialSuccesses) / float64(completed)
 			if successRate < lb.config.HealthyThreshold {
 				lb.logger.Warn("service_below_health_threshold",
 					"service_id", service.ID,
@@ -276,31 +320,27 @@ func (lb *LoadBalancer) selectWeighted(services []*RegisteredService) *Registere
 	return services[0]
 }
 
-// selectHash implements consistent hash-based load balancing
+// selectHash implements session affinity by consistent hashing: the same
+// session key always maps to the same backend while it stays healthy, and
+// membership changes only move the keys of the backend that came or went.
+// Requests without a session key have nothing to stick to and use round robin.
 func (lb *LoadBalancer) selectHash(services []*RegisteredService, criteria SelectionCriteria) *RegisteredService {
 	if len(services) == 0 {
 		return nil
 	}
 
-	// Create hash key from criteria
-	hashKey := ""
-	if criteria.LoadBalancing != "" {
-		hashKey = criteria.LoadBalancing
-	} else if criteria.PreferredRegion != "" {
-		hashKey = criteria.PreferredRegion
-	} else {
-		// Use a default key - perhaps client IP or session ID
-		hashKey = "default"
+	if criteria.SessionID == "" {
+		lb.metrics.Inc("load_balancer_hash_fallbacks_total", "reason", "no_session_key")
+		return lb.selectRoundRobin(services)
 	}
 
-	// Hash the key
-	hasher := fnv.New32a()
-	hasher.Write([]byte(hashKey))
-	hash := hasher.Sum32()
-
-	// Select service based on hash
-	index := int(hash) % len(services)
-	return services[index]
+	serviceID := lb.hashRingFor(services).get(criteria.SessionID)
+	for _, service := range services {
+		if service.ID == serviceID {
+			return service
+		}
+	}
+	return nil
 }
 
 // selectRandom implements random load balancing
@@ -322,6 +362,7 @@ func (lb *LoadBalancer) updateServiceSelection(service *RegisteredService) {
 	state.LastUsed = time.Now()
 	state.ActiveRequests++
 	state.TotalRequests++
+	state.TrialRequests++
 }
 
 // RecordSuccess records a successful request completion
@@ -332,6 +373,13 @@ func (lb *LoadBalancer) RecordSuccess(service *RegisteredService, duration time.
 	state := lb.getOrCreateServiceState(service)
 	state.ActiveRequests--
 	state.SuccessRequests++
+	state.TrialSuccesses++
+	state.observeLatency(duration)
+
+	if state.CircuitHalfOpen {
+		lb.setCircuitState(state, CircuitStateClosed, "trial_request_succeeded", nil)
+		lb.logger.Info("circuit_breaker_closed", "service_id", service.ID)
+	}
 
 	// Update service metrics
 	service.Metrics.RequestCount++
@@ -354,12 +402,22 @@ func (lb *LoadBalancer) RecordSuccess(service *RegisteredService, duration time.
 
 // RecordFailure records a failed request
 func (lb *LoadBalancer) RecordFailure(service *RegisteredService, err error) {
+	lb.RecordFailureAfter(service, err, 0)
+}
+
+// RecordFailureAfter records a failed request that took duration, which
+// counts toward the backend's latency average; a backend that fails slowly
+// is as overloaded as one that succeeds slowly, and one that fails fast is
+// penalized rather than rewarded
+func (lb *LoadBalancer) RecordFailureAfter(service *RegisteredService, err error, duration time.Duration) {
 	lb.mutex.Lock()
 	defer lb.mutex.Unlock()
 
 	state := lb.getOrCreateServiceState(service)
 	state.ActiveRequests--
 	state.FailedRequests++
+	state.TrialFailures++
+	state.observeFailureLatency(duration)
 
 	// Update service metrics
 	service.Metrics.ErrorCount++
@@ -368,10 +426,15 @@ func (lb *LoadBalancer) RecordFailure(service *RegisteredService, err error) {
 	}
 
 	// Check if circuit breaker should be opened
-	if lb.config.CircuitBreakerEnabled && state.TotalRequests > 10 {
-		errorRate := float64(state.FailedRequests) / float64(state.TotalRequests)
+	if state.CircuitHalfOpen {
+		lb.setCircuitState(state, CircuitStateOpen, "trial_request_failed", err)
+		lb.logger.Warn("circuit_breaker_reopened",
+			"service_id", service.ID,
+			"error", err)
+	} else if lb.config.CircuitBreakerEnabled && state.TrialRequests > 10 {
+		errorRate := float64(state.TrialFailures) / float64(state.TrialRequests)
 		if errorRate > (1.0 - lb.config.HealthyThreshold) {
-			state.CircuitOpen = true
+			lb.setCircuitState(state, CircuitStateOpen, "error_rate_exceeded", err)
 			state.CircuitOpenedAt = time.Now()
 
 			lb.logger.Warn("circuit_breaker_opened",
@@ -426,7 +489,7 @@ func (lb *LoadBalancer) ResetCircuitBreaker(serviceID string) {
 	defer lb.mutex.Unlock()
 
 	if state, exists := lb.serviceState[serviceID]; exists {
-		state.CircuitOpen = false
+		lb.setCircuitState(state, CircuitStateClosed, "manual_reset", nil)
 		lb.logger.Info("circuit_breaker_manually_reset", "service_id", serviceID)
 	}
 }
@@ -0,0 +1,56 @@
+package registry
+
+import (
+	"strings"
+)
+
+// Registration metadata keys locating a backend for region affinity
+const (
+	RegionMetadataKey = "region"
+	ZoneMetadataKey   = "zone"
+)
+
+// serviceLocality returns the region and zone a backend registered with
+func serviceLocality(service *RegisteredService) (region, zone string) {
+	region, _ = service.Metadata[RegionMetadataKey].(string)
+	zone, _ = service.Metadata[ZoneMetadataKey].(string)
+	return region, zone
+}
+
+// applyRegionAffinity narrows healthy candidates to those in the preferred
+// region, and within it the preferred zone, when any exist. Other regions are
+// only used when no healthy backend is left in the preferred one.
+func (lb *LoadBalancer) applyRegionAffinity(services []*RegisteredService, criteria SelectionCriteria) []*RegisteredService {
+	if criteria.PreferredRegion == "" || len(services) == 0 {
+		return services
+	}
+
+	var sameRegion, sameZone []*RegisteredService
+	for _, service := range services {
+		region, zone := serviceLocality(service)
+		if !strings.EqualFold(region, criteria.PreferredRegion) {
+			continue
+		}
+		sameRegion = append(sameRegion, service)
+		if criteria.PreferredZone != "" && strings.EqualFold(zone, criteria.PreferredZone) {
+			sameZone = append(sameZone, service)
+		}
+	}
+
+	switch {
+	case len(sameZone) > 0:
+		return sameZone
+	case len(sameRegion) > 0:
+		return sameRegion
+	}
+
+	lb.metrics.Inc("load_balancer_region_fallbacks_total",
+		"service_type", services[0].Type,
+		"region", criteria.PreferredRegion)
+	lb.logger.Debug("region_affinity_fallback",
+		"service_type", services[0].Type,
+		"preferred_region", criteria.PreferredRegion,
+		"candidates", len(services))
+
+	return services
+}
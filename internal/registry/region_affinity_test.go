@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRegionAffinity tests that selection prefers the caller's region and falls back across regions
+func TestRegionAffinity(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	locate := func(id, region, zone string) *RegisteredService {
+		addTestService(sr, id, "search", "1.0.0")
+		service := sr.services[id]
+		service.Metadata = map[string]interface{}{RegionMetadataKey: region, ZoneMetadataKey: zone}
+		return service
+	}
+	euA := locate("search-eu-a", "eu-west-1", "eu-west-1a")
+	euB := locate("search-eu-b", "eu-west-1", "eu-west-1b")
+	locate("search-us-a", "us-east-1", "us-east-1a")
+
+	selectRegions := func(criteria SelectionCriteria) map[string]int {
+		t.Helper()
+		seen := make(map[string]int)
+		for i := 0; i < 50; i++ {
+			service, err := selectAndComplete(sr, criteria)
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			region, _ := serviceLocality(service)
+			seen[region]++
+		}
+		return seen
+	}
+
+	t.Run("prefers same region", func(t *testing.T) {
+		seen := selectRegions(SelectionCriteria{PreferredRegion: "eu-west-1"})
+		if seen["eu-west-1"] != 50 {
+			t.Errorf("expected all requests in eu-west-1, got %v", seen)
+		}
+	})
+
+	t.Run("prefers same zone within region", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			service, err := selectAndComplete(sr, SelectionCriteria{PreferredRegion: "eu-west-1", PreferredZone: "eu-west-1b"})
+			if err != nil {
+				t.Fatalf("select failed: %v", err)
+			}
+			if service.ID != euB.ID {
+				t.Fatalf("expected %s in the preferred zone, got %s", euB.ID, service.ID)
+			}
+		}
+	})
+
+	t.Run("no preference uses all regions", func(t *testing.T) {
+		seen := selectRegions(SelectionCriteria{})
+		if seen["us-east-1"] == 0 || seen["eu-west-1"] == 0 {
+			t.Errorf("expected requests across regions, got %v", seen)
+		}
+	})
+
+	t.Run("falls back when local region is unhealthy", func(t *testing.T) {
+		euA.Health = HealthUnhealthy
+		sr.GetLoadBalancer().config.CircuitBreakerEnabled = true
+		state := sr.GetLoadBalancer().getOrCreateServiceState(euB)
+		sr.GetLoadBalancer().setCircuitState(state, CircuitStateOpen, "test", nil)
+
+		seen := selectRegions(SelectionCriteria{PreferredRegion: "eu-west-1"})
+		if seen["us-east-1"] != 50 {
+			t.Errorf("expected fallback to us-east-1, got %v", seen)
+		}
+
+		euA.Health = HealthHealthy
+		seen = selectRegions(SelectionCriteria{PreferredRegion: "eu-west-1"})
+		if seen["eu-west-1"] != 50 {
+			t.Errorf("expected traffic back in eu-west-1 once healthy, got %v", seen)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultRegistrationInitialBackoff = 250 * time.Millisecond
+	defaultRegistrationMaxBackoff     = 5 * time.Second
+)
+
+// RegistrationRetryConfig retries the health check RegisterService requires
+// with exponential backoff, so that a backend deployed together with the
+// gateway can register before it is ready to serve
+type RegistrationRetryConfig struct {
+	Window         time.Duration `yaml:"window"`          // How long to keep retrying; 0 fails on the first failed check
+	InitialBackoff time.Duration `yaml:"initial_backoff"` // Delay before the first retry, doubled after each; 0 uses 250ms
+	MaxBackoff     time.Duration `yaml:"max_backoff"`     // Cap on the delay between retries; 0 uses 5s
+}
+
+// Validate checks that the retry durations are usable
+func (c RegistrationRetryConfig) Validate() error {
+	if c.Window < 0 || c.InitialBackoff < 0 || c.MaxBackoff < 0 {
+		return fmt.Errorf("registration retry window and backoffs must not be negative")
+	}
+	if c.InitialBackoff > 0 && c.MaxBackoff > 0 && c.MaxBackoff < c.InitialBackoff {
+		return fmt.Errorf("registration retry max backoff %s is below the initial backoff %s", c.MaxBackoff, c.InitialBackoff)
+	}
+	return nil
+}
+
+// SetRegistrationRetry sets how the registration health check is retried
+func (sr *ServiceRegistry) SetRegistrationRetry(config RegistrationRetryConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	sr.mutex.Lock()
+	sr.config.RegistrationRetry = config
+	sr.mutex.Unlock()
+	return nil
+}
+
+// registrationHealthCheck performs the health check required for
+// registration, retrying with backoff over the configured window. Failed
+// checks do not count towards marking the service unavailable, since it is
+// still registering.
+func (sr *ServiceRegistry) registrationHealthCheck(ctx context.Context, service *RegisteredService) error {
+	sr.mutex.RLock()
+	retry := sr.config.RegistrationRetry
+	sr.mutex.RUnlock()
+
+	err := sr.performHealthCheck(ctx, service)
+	if err == nil || retry.Window <= 0 {
+		return err
+	}
+
+	backoff := retry.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultRegistrationInitialBackoff
+	}
+	maxBackoff := retry.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRegistrationMaxBackoff
+	}
+
+	start := time.Now()
+	deadline := start.Add(retry.Window)
+	attempts := 1
+	for {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			break
+		}
+		if backoff < wait {
+			wait = backoff
+		}
+
+		sr.logger.Debug("registration_health_check_retrying",
+			"service_id", service.ID,
+			"attempt", attempts,
+			"backoff", wait,
+			"error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("registration cancelled while waiting for the service to become healthy: %w", err)
+		case <-sr.ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("registry shut down while waiting for the service to become healthy: %w", err)
+		}
+
+		attempts++
+		sr.metrics.Inc("service_registration_health_retries_total", "service_type", service.Type)
+		if err = sr.performHealthCheck(ctx, service); err == nil {
+			sr.logger.Info("registration_health_check_recovered",
+				"service_id", service.ID,
+				"attempts", attempts,
+				"duration", time.Since(start))
+			return nil
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	sr.logger.Warn("registration_health_check_retries_exhausted",
+		"service_id", service.ID,
+		"attempts", attempts,
+		"window", retry.Window,
+		"error", err)
+	return fmt.Errorf("service not healthy after %d health checks over %s: %w", attempts, retry.Window, err)
+}
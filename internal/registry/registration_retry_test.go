@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRegistrationRetry tests that registration retries the health check of a backend that is still starting
+func TestRegistrationRetry(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	// newBackend starts a backend that fails health checks until readyAfter has passed
+	newBackend := func(t *testing.T, readyAfter time.Duration) (*httptest.Server, *atomic.Int32) {
+		t.Helper()
+		ready := time.Now().Add(readyAfter)
+		var checks atomic.Int32
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			checks.Add(1)
+			if time.Now().Before(ready) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(backend.Close)
+		return backend, &checks
+	}
+
+	register := func(name, endpoint string) (*ServiceRegistrationResponse, error) {
+		return sr.RegisterService(context.Background(), ServiceRegistrationRequest{
+			Name:     name,
+			Type:     "retry",
+			Version:  "1.0.0",
+			Endpoint: endpoint,
+			Protocol: "http",
+		})
+	}
+
+	t.Run("without retries a starting backend fails registration", func(t *testing.T) {
+		backend, checks := newBackend(t, 100*time.Millisecond)
+		if _, err := register("no-retry", backend.URL); err == nil {
+			t.Fatal("expected registration to fail while the backend is starting")
+		}
+		if checks.Load() != 1 {
+			t.Errorf("expected a single health check, got %d", checks.Load())
+		}
+	})
+
+	if err := sr.SetRegistrationRetry(RegistrationRetryConfig{
+		Window:         time.Second,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     40 * time.Millisecond,
+	}); err != nil {
+		t.Fatalf("failed to set registration retry: %v", err)
+	}
+
+	t.Run("backend ready within the window registers", func(t *testing.T) {
+		backend, checks := newBackend(t, 100*time.Millisecond)
+		resp, err := register("ready-in-window", backend.URL)
+		if err != nil {
+			t.Fatalf("expected registration to succeed once the backend is ready, got %v", err)
+		}
+		if checks.Load() < 2 {
+			t.Errorf("expected the health check to be retried, got %d checks", checks.Load())
+		}
+		if service := sr.GetService(resp.ServiceID); service == nil || service.Health != HealthHealthy {
+			t.Errorf("expected a healthy registered service, got %+v", service)
+		}
+	})
+
+	t.Run("backend not ready within the window fails", func(t *testing.T) {
+		backend, _ := newBackend(t, time.Hour)
+		start := time.Now()
+		if _, err := register("never-ready", backend.URL); err == nil {
+			t.Fatal("expected registration to fail when the backend never becomes ready")
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("expected retries to continue for the whole window, gave up after %s", elapsed)
+		}
+		if sr.GetService(sr.generateServiceID("never-ready", "retry")) != nil {
+			t.Error("expected the failed service not to be registered")
+		}
+	})
+
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		if err := sr.SetRegistrationRetry(RegistrationRetryConfig{InitialBackoff: time.Second, MaxBackoff: time.Millisecond}); err == nil {
+			t.Error("expected a max backoff below the initial backoff to be rejected")
+		}
+	})
+}
@@ -95,6 +95,9 @@ type ServiceRegistry struct {
 	// Circuit breaker configuration
 	maxFailures int
 
+	// Called with the ID of each removed service
+	removalListeners []func(serviceID string)
+
 	// Background monitoring
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -296,6 +299,9 @@ type RegistryConfig struct {
 	RequireHealthCheck  bool          `yaml:"require_health_check"`
 	MaxRegistrationTime time.Duration `yaml:"max_registration_time"`
 
+	// Retry of the required health check for backends not ready yet
+	RegistrationRetry RegistrationRetryConfig `yaml:"registration_retry"`
+
 	// Load balancing
 	LoadBalancingEnabled  bool   `yaml:"load_balancing_enabled"`
 	LoadBalancingStrategy string `yaml:"load_balancing_strategy"`
@@ -307,6 +313,12 @@ type RegistryConfig struct {
 	// Cleanup and maintenance
 	InactiveServiceTimeout time.Duration `yaml:"inactive_service_timeout"`
 	CleanupInterval        time.Duration `yaml:"cleanup_interval"`
+
+	// Warm-up probing for services registered with a warm-up period
+	WarmupProbeInterval time.Duration `yaml:"warmup_probe_interval"`
+
+	// Directories the TLS files named in registration metadata must be in
+	UpstreamTLSDirs []string `yaml:"upstream_tls_dirs"`
 }
 
 // ServiceRegistrationRequest represents a service registration request
@@ -315,7 +327,7 @@ type ServiceRegistrationRequest struct {
 	Type          string                 `json:"type" validate:"required"`
 	Version       string                 `json:"version" validate:"required"`
 	Description   string                 `json:"description,omitempty"`
-	Endpoint      string                 `json:"endpoint" validate:"required,url"`
+	Endpoint      string                 `json:"endpoint" validate:"required,endpoint"`
 	Protocol      string                 `json:"protocol" validate:"required"`
 	Tools         []ToolDefinition       `json:"tools,omitempty"`
 	Resources     []ResourceDefinition   `json:"resources,omitempty"`
@@ -324,6 +336,10 @@ type ServiceRegistrationRequest struct {
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	Tags          []string               `json:"tags,omitempty"`
 	Security      ServiceSecurity        `json:"security,omitempty"`
+
+	// WarmupPeriod keeps the service unselectable after registration while the
+	// registry probes it, until it responds healthily or the period expires
+	WarmupPeriod time.Duration `json:"warmup_period,omitempty"`
 }
 
 // ServiceRegistrationResponse represents the response to a registration request
@@ -401,6 +417,30 @@ func (sr *ServiceRegistry) RegisterService(ctx context.Context, req ServiceRegis
 		}
 	}
 
+	if err := validateHealthCheckMetadata(req.Metadata); err != nil {
+		return nil, errors.ValidationError("service_registry", "register_service",
+			err.Error(), map[string]interface{}{"name": req.Name})
+	}
+	sr.mutex.RLock()
+	tlsDirs := sr.config.UpstreamTLSDirs
+	sr.mutex.RUnlock()
+	if err := checkUpstreamTLSFiles(tlsDirs, req.Metadata); err != nil {
+		sr.metrics.Inc("service_tls_file_rejections_total", "service_type", req.Type)
+		return nil, errors.ValidationError("service_registry", "register_service",
+			err.Error(), map[string]interface{}{"name": req.Name})
+	}
+	if _, err := UpstreamTLSConfig(req.Metadata); err != nil {
+		return nil, errors.ValidationError("service_registry", "register_service",
+			err.Error(), map[string]interface{}{"name": req.Name})
+	}
+	if insecure, _ := InsecureUpstreamTLS(req.Metadata); insecure {
+		sr.metrics.Inc("service_insecure_tls_registrations_total", "service_type", req.Type)
+		sr.logger.Warn("service_tls_verification_disabled",
+			"name", req.Name,
+			"type", req.Type,
+			"endpoint", req.Endpoint)
+	}
+
 	// Generate unique service ID
 	serviceID := sr.generateServiceID(req.Name, req.Type)
 
@@ -436,9 +476,12 @@ func (sr *ServiceRegistry) RegisterService(ctx context.Context, req ServiceRegis
 		client:        &http.Client{Timeout: 30 * time.Second},
 	}
 
-	// Perform health check if required
-	if sr.config.RequireHealthCheck {
-		if err := sr.performHealthCheck(ctx, service); err != nil {
+	// Perform health check if required, retrying while the backend starts;
+	// services with a warm-up period are probed after registration instead,
+	// since they are expected to start cold
+	warmingUp := req.WarmupPeriod > 0
+	if sr.config.RequireHealthCheck && !warmingUp {
+		if err := sr.registrationHealthCheck(ctx, service); err != nil {
 			return nil, errors.UnavailableError("service_registry", "register_service", err, map[string]interface{}{
 				"service_id":         serviceID,
 				"endpoint":           req.Endpoint,
@@ -447,6 +490,14 @@ func (sr *ServiceRegistry) RegisterService(ctx context.Context, req ServiceRegis
 		}
 	}
 
+	// Services without a warm-up period are selectable as soon as they are
+	// added; the status is set first so readers never see it change unlocked
+	if !warmingUp {
+		service.Status = StatusActive
+		service.Health = HealthHealthy
+	}
+	status := service.Status
+
 	// Add service to registry
 	sr.mutex.Lock()
 	sr.services[serviceID] = service
@@ -454,9 +505,12 @@ func (sr *ServiceRegistry) RegisterService(ctx context.Context, req ServiceRegis
 	sr.updateCapabilities(service)
 	sr.mutex.Unlock()
 
-	// Update service status
-	service.Status = StatusActive
-	service.Health = HealthHealthy
+	message := "Service registered successfully"
+	if warmingUp {
+		message = fmt.Sprintf("Service registered, warming up for up to %s", req.WarmupPeriod)
+		sr.wg.Add(1)
+		go sr.warmUpService(service, req.WarmupPeriod)
+	}
 
 	// Record metrics
 	sr.recordRegistrationMetrics(service, time.Since(start))
@@ -464,8 +518,8 @@ func (sr *ServiceRegistry) RegisterService(ctx context.Context, req ServiceRegis
 	// Create response
 	response := &ServiceRegistrationResponse{
 		ServiceID: serviceID,
-		Status:    StatusActive,
-		Message:   "Service registered successfully",
+		Status:    status,
+		Message:   message,
 		Timestamp: time.Now(),
 		GatewayInfo: GatewayInfo{
 			Version:             "1.0.0",
@@ -481,8 +535,7 @@ func (sr *ServiceRegistry) RegisterService(ctx context.Context, req ServiceRegis
 		"type", req.Type,
 		"version", req.Version,
 		"endpoint", req.Endpoint,
-		"status", service.Status,
-		"health", service.Health,
+		"status", status,
 		"registration_time", time.Since(start),
 		"total_services", len(sr.services))
 
@@ -508,6 +561,30 @@ func (sr *ServiceRegistry) GetServicesByType(serviceType string) []*RegisteredSe
 	return result
 }
 
+// GetHealthyServicesByType retrieves the healthy, active services of a type;
+// warming, draining and unavailable instances are left out
+func (sr *ServiceRegistry) GetHealthyServicesByType(serviceType string) []*RegisteredService {
+	sr.mutex.RLock()
+	defer sr.mutex.RUnlock()
+
+	var healthy []*RegisteredService
+	for _, service := range sr.byType[serviceType] {
+		if service.Health == HealthHealthy && service.Status == StatusActive {
+			healthy = append(healthy, service)
+		}
+	}
+	return healthy
+}
+
+// serviceState reads a service's status and health, which health checks and
+// warm-ups update under the registry lock
+func (sr *ServiceRegistry) serviceState(service *RegisteredService) (ServiceStatus, HealthStatus) {
+	sr.mutex.RLock()
+	defer sr.mutex.RUnlock()
+
+	return service.Status, service.Health
+}
+
 // GetAllServices retrieves all registered services
 func (sr *ServiceRegistry) GetAllServices() map[string]*RegisteredService {
 	sr.mutex.RLock()
@@ -539,10 +616,9 @@ func (sr *ServiceRegistry) UnregisterService(ctx context.Context, serviceID stri
 	sr.logger.Info("service_unregistration_started", "service_id", serviceID)
 
 	sr.mutex.Lock()
-	defer sr.mutex.Unlock()
-
 	service, exists := sr.services[serviceID]
 	if !exists {
+		sr.mutex.Unlock()
 		return errors.ValidationError("service_registry", "unregister_service",
 			fmt.Sprintf("Service not found: %s", serviceID), map[string]interface{}{
 				"service_id": serviceID,
@@ -556,13 +632,17 @@ func (sr *ServiceRegistry) UnregisterService(ctx context.Context, serviceID stri
 	delete(sr.services, serviceID)
 	sr.removeServiceByType(service)
 	sr.updateCapabilitiesAfterRemoval(service)
+	remaining := len(sr.services)
+	sr.mutex.Unlock()
+
+	sr.notifyServicesRemoved([]string{serviceID})
 
 	sr.logger.Info("service_unregistration_completed",
 		"service_id", serviceID,
 		"name", service.Name,
 		"type", service.Type,
 		"uptime", time.Since(service.RegisteredAt),
-		"remaining_services", len(sr.services))
+		"remaining_services", remaining)
 
 	return nil
 }
@@ -590,14 +670,8 @@ func (sr *ServiceRegistry) SelectService(serviceType string, criteria SelectionC
 			})
 	}
 
-	// Filter healthy services
-	var healthy []*RegisteredService
-	for _, service := range services {
-		if service.Health == HealthHealthy && service.Status == StatusActive {
-			healthy = append(healthy, service)
-		}
-	}
-
+	// Only healthy, active instances take traffic
+	healthy := sr.GetHealthyServicesByType(serviceType)
 	if len(healthy) == 0 {
 		return nil, errors.UnavailableError("service_registry", "select_service",
 			fmt.Errorf("no healthy services available"), map[string]interface{}{
@@ -607,6 +681,9 @@ func (sr *ServiceRegistry) SelectService(serviceType string, criteria SelectionC
 			})
 	}
 
+	// Narrow to one version when a canary split is configured
+	healthy = sr.loadBalancer.applyCanary(serviceType, healthy, criteria)
+
 	// Use load balancer to select service
 	return sr.loadBalancer.SelectService(healthy, criteria)
 }
@@ -614,9 +691,11 @@ func (sr *ServiceRegistry) SelectService(serviceType string, criteria SelectionC
 // SelectionCriteria defines criteria for service selection
 type SelectionCriteria struct {
 	PreferredRegion string                 `json:"preferred_region,omitempty"`
+	PreferredZone   string                 `json:"preferred_zone,omitempty"`
 	Tags            []string               `json:"tags,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 	LoadBalancing   string                 `json:"load_balancing,omitempty"`
+	SessionID       string                 `json:"session_id,omitempty"`
 }
 
 // TriggerDiscovery manually triggers service discovery
@@ -730,20 +809,21 @@ func (sr *ServiceRegistry) performHealthCheck(ctx context.Context, service *Regi
 		return sr.updateServiceHealth(service, HealthHealthy, nil, time.Since(startTime))
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-		Transport: &http.Transport{
-			DisableKeepAlives: true,
-			IdleConnTimeout:   3 * time.Second,
-		},
-	}
-
 	// Construct health check URL
 	healthURL := sr.buildHealthCheckURL(service)
 
-	// Create HTTP request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", healthURL, nil)
+	// Create HTTP client with the service's timeouts
+	client, err := newHealthCheckClient(service)
+	if err != nil {
+		sr.logger.Error("service_health_check_request_creation_failed",
+			"service_id", service.ID,
+			"health_url", healthURL,
+			"error", err)
+		return sr.updateServiceHealth(service, HealthUnhealthy, err, time.Since(startTime))
+	}
+
+	// Create HTTP request with context; method and body come from metadata
+	req, err := newHealthCheckRequest(ctx, service, healthURL)
 	if err != nil {
 		sr.logger.Error("service_health_check_request_creation_failed",
 			"service_id", service.ID,
@@ -801,20 +881,24 @@ func (sr *ServiceRegistry) buildHealthCheckURL(service *RegisteredService) strin
 		healthPath = customPath
 	}
 
+	return service.EndpointURL() + healthPath
+}
+
+// EndpointURL returns the service endpoint as an absolute URL. Endpoints
+// registered without a scheme use http, or https when the tls metadata is "true".
+func (s *RegisteredService) EndpointURL() string {
 	// Check if endpoint already has a scheme
-	if strings.Contains(service.Endpoint, "://") {
-		// Endpoint already has full URL - just append health path
-		return service.Endpoint + healthPath
+	if strings.Contains(s.Endpoint, "://") {
+		return s.Endpoint
 	}
 
 	// Determine protocol for endpoints without scheme
 	protocol := "http"
-	if useTLS, ok := service.Metadata["tls"]; ok && useTLS == "true" {
+	if useTLS, ok := s.Metadata["tls"]; ok && useTLS == "true" {
 		protocol = "https"
 	}
 
-	// Build URL with protocol prefix
-	return fmt.Sprintf("%s://%s%s", protocol, service.Endpoint, healthPath)
+	return fmt.Sprintf("%s://%s", protocol, s.Endpoint)
 }
 
 // addHealthCheckAuth adds authentication headers if configured
@@ -869,6 +953,10 @@ func (sr *ServiceRegistry) validateHealthCheckResponse(resp *http.Response, serv
 		return nil
 	}
 
+	if err := sr.validateHealthJSONAssertion(body, service); err != nil {
+		return err
+	}
+
 	// Validate response content if configured
 	return sr.validateHealthResponseContent(body, service)
 }
@@ -978,14 +1066,19 @@ func (sr *ServiceRegistry) validateJSONHealthResponse(body []byte, service *Regi
 
 // updateServiceHealth updates service health status and metrics
 func (sr *ServiceRegistry) updateServiceHealth(service *RegisteredService, health HealthStatus, err error, duration time.Duration) error {
+	// Selection reads these fields concurrently
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
 	service.Health = health
 	service.lastHealth = time.Now()
 
 	// Record health check metrics
 	sr.recordHealthCheckMetrics(service, health, duration, err)
 
-	// Update failure count for circuit breaker logic
-	if health == HealthUnhealthy {
+	// Update failure count for circuit breaker logic; failed warm-up probes
+	// are expected and do not count towards it
+	if health == HealthUnhealthy && service.Status != StatusRegistering {
 		service.FailureCount++
 		if service.FailureCount >= sr.maxFailures {
 			service.Status = StatusUnavailable
@@ -994,7 +1087,7 @@ func (sr *ServiceRegistry) updateServiceHealth(service *RegisteredService, healt
 				"failure_count", service.FailureCount,
 				"max_failures", sr.maxFailures)
 		}
-	} else {
+	} else if health != HealthUnhealthy {
 		service.FailureCount = 0
 		if service.Status == StatusUnavailable {
 			service.Status = StatusActive
@@ -1114,19 +1207,26 @@ func (sr *ServiceRegistry) performAllHealthChecks() {
 	services := sr.GetAllServices()
 
 	for _, service := range services {
+		// Warming services are probed by their own warm-up loop
+		if status, _ := sr.serviceState(service); status == StatusRegistering {
+			continue
+		}
+
+		// A failed check has already marked the service unhealthy
 		if err := sr.performHealthCheck(sr.ctx, service); err != nil {
 			sr.logger.Warn("service_health_check_failed",
 				"service_id", service.ID,
 				"name", service.Name,
 				"endpoint", service.Endpoint,
 				"error", err)
-
-			service.Health = HealthUnhealthy
 		}
 	}
 }
 
 func (sr *ServiceRegistry) cleanupInactiveServices() {
+	var removed []string
+	defer func() { sr.notifyServicesRemoved(removed) }()
+
 	sr.mutex.Lock()
 	defer sr.mutex.Unlock()
 
@@ -1142,6 +1242,7 @@ func (sr *ServiceRegistry) cleanupInactiveServices() {
 
 			delete(sr.services, id)
 			sr.removeServiceByType(service)
+			removed = append(removed, id)
 		}
 	}
 }
@@ -1160,6 +1261,7 @@ func defaultRegistryConfig() RegistryConfig {
 		AllowSelfRegistration:  true,
 		InactiveServiceTimeout: 300 * time.Second,
 		CleanupInterval:        120 * time.Second,
+		WarmupProbeInterval:    defaultWarmupProbeInterval,
 	}
 }
 
@@ -0,0 +1,28 @@
+package registry
+
+// OnServiceRemoved registers a function called with the ID of each service
+// that leaves the registry, whether unregistered or cleaned up as inactive,
+// so state kept per service elsewhere can be dropped with it. Listeners run
+// after the registry lock is released.
+func (sr *ServiceRegistry) OnServiceRemoved(listener func(serviceID string)) {
+	sr.mutex.Lock()
+	sr.removalListeners = append(sr.removalListeners, listener)
+	sr.mutex.Unlock()
+}
+
+// notifyServicesRemoved calls the removal listeners for each removed service
+func (sr *ServiceRegistry) notifyServicesRemoved(serviceIDs []string) {
+	if len(serviceIDs) == 0 {
+		return
+	}
+
+	sr.mutex.RLock()
+	listeners := append([]func(string){}, sr.removalListeners...)
+	sr.mutex.RUnlock()
+
+	for _, serviceID := range serviceIDs {
+		for _, listener := range listeners {
+			listener(serviceID)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RegistrySnapshotVersion identifies the snapshot format written by Export
+const RegistrySnapshotVersion = 1
+
+// RegistrySnapshot is a serializable copy of the registered services for
+// backup or migration between gateway instances
+type RegistrySnapshot struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Services   []ServiceSnapshot `json:"services"`
+}
+
+// ServiceSnapshot is the persistent part of a registered service. Runtime
+// state such as health, status, metrics and HTTP clients is left out and
+// re-established on import.
+type ServiceSnapshot struct {
+	ID            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	Type          string                 `json:"type"`
+	Version       string                 `json:"version"`
+	Description   string                 `json:"description,omitempty"`
+	Endpoint      string                 `json:"endpoint"`
+	Protocol      string                 `json:"protocol"`
+	Tools         []ToolDefinition       `json:"tools,omitempty"`
+	Resources     []ResourceDefinition   `json:"resources,omitempty"`
+	Prompts       []PromptDefinition     `json:"prompts,omitempty"`
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	Security      ServiceSecurity        `json:"security"`
+	RegisteredAt  time.Time              `json:"registered_at"`
+}
+
+// ImportResult reports what happened to each service in an imported snapshot
+type ImportResult struct {
+	Imported  []ImportedService `json:"imported"`
+	Unchanged []string          `json:"unchanged"`
+	Conflicts []ImportConflict  `json:"conflicts"`
+}
+
+// ImportedService is a service added by an import, with its health check outcome
+type ImportedService struct {
+	ServiceID string       `json:"service_id"`
+	Health    HealthStatus `json:"health"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// ImportConflict is a snapshot service that was not imported
+type ImportConflict struct {
+	ServiceID string `json:"service_id"`
+	Reason    string `json:"reason"`
+}
+
+// Export returns a snapshot of the registered services ordered by ID. Plugin
+// services are left out since every gateway registers its own plugins.
+func (sr *ServiceRegistry) Export() RegistrySnapshot {
+	sr.mutex.RLock()
+	defer sr.mutex.RUnlock()
+
+	snapshot := RegistrySnapshot{
+		Version:    RegistrySnapshotVersion,
+		ExportedAt: time.Now(),
+		Services:   make([]ServiceSnapshot, 0, len(sr.services)),
+	}
+	for _, service := range sr.services {
+		if strings.HasPrefix(service.Endpoint, "plugin://") {
+			continue
+		}
+		snapshot.Services = append(snapshot.Services, newServiceSnapshot(service))
+	}
+	sort.Slice(snapshot.Services, func(i, j int) bool {
+		return snapshot.Services[i].ID < snapshot.Services[j].ID
+	})
+	return snapshot
+}
+
+// Import registers the services of a snapshot under their original IDs and
+// health checks them. Importing is idempotent: a service whose ID is already
+// registered with the same definition is reported unchanged, and one with a
+// different definition is reported as a conflict and left as it is.
+func (sr *ServiceRegistry) Import(ctx context.Context, snapshot RegistrySnapshot) (ImportResult, error) {
+	result := ImportResult{
+		Imported:  []ImportedService{},
+		Unchanged: []string{},
+		Conflicts: []ImportConflict{},
+	}
+	if snapshot.Version != RegistrySnapshotVersion {
+		return result, fmt.Errorf("unsupported registry snapshot version %d, expected %d",
+			snapshot.Version, RegistrySnapshotVersion)
+	}
+
+	var added []*RegisteredService
+	seen := make(map[string]bool)
+
+	sr.mutex.Lock()
+	for _, entry := range snapshot.Services {
+		// TLS files are checked against the allowed directories before they are read
+		if err := checkUpstreamTLSFiles(sr.config.UpstreamTLSDirs, entry.Metadata); err != nil {
+			result.Conflicts = append(result.Conflicts, ImportConflict{ServiceID: entry.ID, Reason: err.Error()})
+			continue
+		}
+		if reason := validateServiceSnapshot(entry); reason != "" {
+			result.Conflicts = append(result.Conflicts, ImportConflict{ServiceID: entry.ID, Reason: reason})
+			continue
+		}
+		if seen[entry.ID] {
+			result.Conflicts = append(result.Conflicts, ImportConflict{ServiceID: entry.ID, Reason: "duplicate service ID in snapshot"})
+			continue
+		}
+		seen[entry.ID] = true
+
+		if existing, exists := sr.services[entry.ID]; exists {
+			if sameServiceSnapshot(newServiceSnapshot(existing), entry) {
+				result.Unchanged = append(result.Unchanged, entry.ID)
+			} else {
+				result.Conflicts = append(result.Conflicts, ImportConflict{
+					ServiceID: entry.ID,
+					Reason:    "service already registered with a different definition",
+				})
+			}
+			continue
+		}
+
+		service := &RegisteredService{
+			ID:            entry.ID,
+			Name:          entry.Name,
+			Type:          entry.Type,
+			Version:       entry.Version,
+			Description:   entry.Description,
+			Endpoint:      entry.Endpoint,
+			Protocol:      entry.Protocol,
+			Tools:         entry.Tools,
+			Resources:     entry.Resources,
+			Prompts:       entry.Prompts,
+			Configuration: entry.Configuration,
+			Metadata:      entry.Metadata,
+			Tags:          entry.Tags,
+			Security:      entry.Security,
+			Status:        StatusActive,
+			Health:        HealthUnknown,
+			RegisteredAt:  entry.RegisteredAt,
+			LastSeen:      time.Now(),
+			client:        &http.Client{Timeout: 30 * time.Second},
+		}
+		sr.services[service.ID] = service
+		sr.addServiceByType(service)
+		sr.updateCapabilities(service)
+		added = append(added, service)
+	}
+	sr.mutex.Unlock()
+
+	// Health check the imported services concurrently; failures leave them
+	// registered as unhealthy for the regular health checks to pick up
+	outcomes := make([]ImportedService, len(added))
+	var wg sync.WaitGroup
+	for i, service := range added {
+		wg.Add(1)
+		go func(i int, service *RegisteredService) {
+			defer wg.Done()
+			outcome := ImportedService{ServiceID: service.ID}
+			if err := sr.performHealthCheck(ctx, service); err != nil {
+				outcome.Error = err.Error()
+			}
+			_, outcome.Health = sr.serviceState(service)
+			outcomes[i] = outcome
+		}(i, service)
+	}
+	wg.Wait()
+	result.Imported = append(result.Imported, outcomes...)
+
+	sr.metrics.Add("registry_imported_services_total", float64(len(result.Imported)))
+	sr.metrics.Add("registry_import_conflicts_total", float64(len(result.Conflicts)))
+	sr.logger.Info("registry_snapshot_imported",
+		"imported", len(result.Imported),
+		"unchanged", len(result.Unchanged),
+		"conflicts", len(result.Conflicts),
+		"total_services", len(sr.GetAllServices()))
+
+	return result, nil
+}
+
+// newServiceSnapshot copies the persistent fields of a service
+func newServiceSnapshot(service *RegisteredService) ServiceSnapshot {
+	return ServiceSnapshot{
+		ID:            service.ID,
+		Name:          service.Name,
+		Type:          service.Type,
+		Version:       service.Version,
+		Description:   service.Description,
+		Endpoint:      service.Endpoint,
+		Protocol:      service.Protocol,
+		Tools:         service.Tools,
+		Resources:     service.Resources,
+		Prompts:       service.Prompts,
+		Configuration: service.Configuration,
+		Metadata:      service.Metadata,
+		Tags:          service.Tags,
+		Security:      service.Security,
+		RegisteredAt:  service.RegisteredAt,
+	}
+}
+
+// validateServiceSnapshot returns why a snapshot entry cannot be imported, or ""
+func validateServiceSnapshot(entry ServiceSnapshot) string {
+	switch {
+	case entry.ID == "":
+		return "service ID is required"
+	case entry.Name == "" || entry.Type == "" || entry.Endpoint == "":
+		return "name, type and endpoint are required"
+	case strings.HasPrefix(entry.Endpoint, "plugin://"):
+		return "plugin services are registered by their plugins"
+	}
+	if err := validateHealthCheckMetadata(entry.Metadata); err != nil {
+		return err.Error()
+	}
+	if _, err := UpstreamTLSConfig(entry.Metadata); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// sameServiceSnapshot compares two definitions by their JSON encoding, which
+// treats values decoded from a snapshot and values built in code alike
+func sameServiceSnapshot(a, b ServiceSnapshot) bool {
+	a.RegisteredAt, b.RegisteredAt = time.Time{}, time.Time{}
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
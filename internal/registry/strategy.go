@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SupportedStrategies lists the load balancing strategies SelectService implements
+var SupportedStrategies = []string{
+	"round_robin",
+	"least_connections",
+	"weighted",
+	"hash",
+	"random",
+	"load_aware",
+}
+
+// IsSupportedStrategy reports whether name is a known load balancing strategy
+func IsSupportedStrategy(name string) bool {
+	for _, strategy := range SupportedStrategies {
+		if strategy == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStrategy changes the load balancing strategy at runtime. An empty
+// serviceType changes the default; otherwise the strategy applies only to
+// services of that type.
+func (lb *LoadBalancer) SetStrategy(serviceType, strategy string) error {
+	if !IsSupportedStrategy(strategy) {
+		return fmt.Errorf("unsupported load balancing strategy %q, must be one of: %s",
+			strategy, strings.Join(SupportedStrategies, ", "))
+	}
+
+	lb.mutex.Lock()
+	previous := lb.config.Strategy
+	if serviceType == "" {
+		lb.config.Strategy = strategy
+	} else {
+		if current, exists := lb.typeStrategies[serviceType]; exists {
+			previous = current
+		}
+		lb.typeStrategies[serviceType] = strategy
+	}
+	lb.mutex.Unlock()
+
+	lb.logger.Info("load_balancer_strategy_changed",
+		"service_type", serviceType,
+		"previous_strategy", previous,
+		"strategy", strategy)
+
+	return nil
+}
+
+// GetStrategy returns the default load balancing strategy
+func (lb *LoadBalancer) GetStrategy() string {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+
+	return lb.config.Strategy
+}
+
+// GetEffectiveStrategy returns the strategy used for a service type, which is
+// its override if one is set and the default otherwise
+func (lb *LoadBalancer) GetEffectiveStrategy(serviceType string) string {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+
+	if strategy, exists := lb.typeStrategies[serviceType]; exists {
+		return strategy
+	}
+	return lb.config.Strategy
+}
+
+// GetTypeStrategies returns the per-service-type strategy overrides
+func (lb *LoadBalancer) GetTypeStrategies() map[string]string {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+
+	result := make(map[string]string, len(lb.typeStrategies))
+	for serviceType, strategy := range lb.typeStrategies {
+		result[serviceType] = strategy
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Registration metadata keys for the TLS policy used to reach a service,
+// for both health checks and forwarded requests
+const (
+	TLSCAFileKey             = "tls_ca_file"              // PEM bundle trusted instead of the system roots
+	TLSCertFileKey           = "tls_cert_file"            // Client certificate presented for mutual TLS
+	TLSKeyFileKey            = "tls_key_file"             // Private key of the client certificate
+	TLSInsecureSkipVerifyKey = "tls_insecure_skip_verify" // Boolean; true disables certificate verification
+)
+
+// UpstreamTLSConfig builds the TLS configuration for calls to a service from
+// its registration metadata. It returns nil when the service sets no TLS
+// options, so calls keep the system defaults.
+func UpstreamTLSConfig(metadata map[string]interface{}) (*tls.Config, error) {
+	caFile, err := tlsMetadataString(metadata, TLSCAFileKey)
+	if err != nil {
+		return nil, err
+	}
+	certFile, err := tlsMetadataString(metadata, TLSCertFileKey)
+	if err != nil {
+		return nil, err
+	}
+	keyFile, err := tlsMetadataString(metadata, TLSKeyFileKey)
+	if err != nil {
+		return nil, err
+	}
+	insecure, err := InsecureUpstreamTLS(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if caFile == "" && certFile == "" && keyFile == "" && !insecure {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", TLSCAFileKey, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s %s contains no PEM certificates", TLSCAFileKey, caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("%s and %s must be set together", TLSCertFileKey, TLSKeyFileKey)
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	// Only an explicit boolean true gets here; see InsecureUpstreamTLS
+	config.InsecureSkipVerify = insecure
+	return config, nil
+}
+
+// SetUpstreamTLSDirs sets the directories the TLS files named in registration
+// metadata must be in. With none, registrations naming TLS files are
+// rejected, so a registering client cannot make the gateway read arbitrary
+// local files.
+func (sr *ServiceRegistry) SetUpstreamTLSDirs(dirs []string) error {
+	if err := ValidateUpstreamTLSDirs(dirs); err != nil {
+		return err
+	}
+	cleaned := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		cleaned = append(cleaned, filepath.Clean(dir))
+	}
+
+	sr.mutex.Lock()
+	sr.config.UpstreamTLSDirs = cleaned
+	sr.mutex.Unlock()
+	return nil
+}
+
+// ValidateUpstreamTLSDirs checks that each allowed TLS directory is absolute
+func ValidateUpstreamTLSDirs(dirs []string) error {
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("upstream TLS directory %q must be an absolute path", dir)
+		}
+	}
+	return nil
+}
+
+// checkUpstreamTLSFiles checks that each TLS file named in the metadata is
+// inside one of dirs once symlinks are resolved
+func checkUpstreamTLSFiles(dirs []string, metadata map[string]interface{}) error {
+	for _, key := range []string{TLSCAFileKey, TLSCertFileKey, TLSKeyFileKey} {
+		path, err := tlsMetadataString(metadata, key)
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			continue
+		}
+		if len(dirs) == 0 {
+			return fmt.Errorf("%s is not allowed: no upstream TLS directories are configured", key)
+		}
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("%s must be an absolute path", key)
+		}
+
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return fmt.Errorf("%s is not in an allowed upstream TLS directory", key)
+		}
+		if !withinDirs(dirs, resolved) {
+			return fmt.Errorf("%s is not in an allowed upstream TLS directory", key)
+		}
+	}
+	return nil
+}
+
+// withinDirs reports whether path is inside one of dirs, with the
+// directories' own symlinks resolved
+func withinDirs(dirs []string, path string) bool {
+	for _, dir := range dirs {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = resolved
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && rel != "." {
+			return true
+		}
+	}
+	return false
+}
+
+// InsecureUpstreamTLS reports whether a service disables certificate
+// verification. Only the boolean true enables it, so a mistyped value such
+// as "yes" is rejected rather than silently weakening or ignoring the policy.
+func InsecureUpstreamTLS(metadata map[string]interface{}) (bool, error) {
+	value, exists := metadata[TLSInsecureSkipVerifyKey]
+	if !exists || value == nil {
+		return false, nil
+	}
+	insecure, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s must be true or false, got %T", TLSInsecureSkipVerifyKey, value)
+	}
+	return insecure, nil
+}
+
+// UpstreamTLSFingerprint identifies a service's TLS settings, so clients
+// built for them can be reused until the service is registered with others
+func UpstreamTLSFingerprint(metadata map[string]interface{}) string {
+	return fmt.Sprintf("%v|%v|%v|%v",
+		metadata[TLSCAFileKey], metadata[TLSCertFileKey], metadata[TLSKeyFileKey], metadata[TLSInsecureSkipVerifyKey])
+}
+
+func tlsMetadataString(metadata map[string]interface{}, key string) (string, error) {
+	value, exists := metadata[key]
+	if !exists || value == nil {
+		return "", nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a file path, got %T", key, value)
+	}
+	return text, nil
+}
@@ -0,0 +1,133 @@
+package registry
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestUpstreamTLSFiles tests that registrations may only name TLS files in the configured directories
+func TestUpstreamTLSFiles(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	tlsServer.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+
+	allowed := t.TempDir()
+	outside := t.TempDir()
+	for _, dir := range []string{allowed, outside} {
+		if err := os.WriteFile(filepath.Join(dir, "ca.pem"), caPEM, 0600); err != nil {
+			t.Fatalf("failed to write CA bundle: %v", err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(outside, "ca.pem"), filepath.Join(allowed, "link.pem")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	register := func(name, caFile string) error {
+		_, err := sr.RegisterService(context.Background(), ServiceRegistrationRequest{
+			Name:     name,
+			Type:     "tls_files",
+			Version:  "1.0.0",
+			Endpoint: backend.URL,
+			Protocol: "http",
+			Metadata: map[string]interface{}{TLSCAFileKey: caFile},
+		})
+		return err
+	}
+
+	t.Run("TLS files are rejected without configured directories", func(t *testing.T) {
+		if err := register("no-dirs", filepath.Join(allowed, "ca.pem")); err == nil {
+			t.Error("expected a TLS file to be rejected when no directories are configured")
+		}
+	})
+
+	if err := sr.SetUpstreamTLSDirs([]string{allowed}); err != nil {
+		t.Fatalf("failed to set upstream TLS directories: %v", err)
+	}
+
+	t.Run("file in a configured directory is accepted", func(t *testing.T) {
+		if err := register("inside", filepath.Join(allowed, "ca.pem")); err != nil {
+			t.Errorf("expected the CA bundle to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("files outside the configured directories are rejected", func(t *testing.T) {
+		for _, path := range []string{
+			filepath.Join(outside, "ca.pem"),
+			filepath.Join(allowed, "..", filepath.Base(outside), "ca.pem"),
+			filepath.Join(allowed, "link.pem"),
+			"ca.pem",
+		} {
+			if err := register("outside", path); err == nil {
+				t.Errorf("expected %s to be rejected", path)
+			}
+		}
+	})
+
+	t.Run("relative directories are rejected", func(t *testing.T) {
+		if err := sr.SetUpstreamTLSDirs([]string{"certs"}); err == nil {
+			t.Error("expected a relative directory to be rejected")
+		}
+	})
+}
+
+// TestServiceRemovalListeners tests that listeners learn of unregistered services
+func TestServiceRemovalListeners(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	var removed []string
+	sr.OnServiceRemoved(func(serviceID string) { removed = append(removed, serviceID) })
+
+	resp, err := sr.RegisterService(context.Background(), ServiceRegistrationRequest{
+		Name:     "removed",
+		Type:     "removal",
+		Version:  "1.0.0",
+		Endpoint: backend.URL,
+		Protocol: "http",
+	})
+	if err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := sr.UnregisterService(context.Background(), resp.ServiceID); err != nil {
+		t.Fatalf("failed to unregister service: %v", err)
+	}
+	if err := sr.UnregisterService(context.Background(), resp.ServiceID); err == nil {
+		t.Fatal("expected a second unregistration to fail")
+	}
+
+	if len(removed) != 1 || removed[0] != resp.ServiceID {
+		t.Errorf("expected one removal of %s, got %v", resp.ServiceID, removed)
+	}
+}
@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"time"
+)
+
+const defaultWarmupProbeInterval = time.Second
+
+// warmUpService probes a newly registered service until it responds healthily
+// or its warm-up period expires. The service stays in StatusRegistering, and
+// therefore unselectable, until then.
+func (sr *ServiceRegistry) warmUpService(service *RegisteredService, period time.Duration) {
+	defer sr.wg.Done()
+
+	interval := sr.config.WarmupProbeInterval
+	if interval <= 0 {
+		interval = defaultWarmupProbeInterval
+	}
+
+	deadline := time.NewTimer(period)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	probes := 0
+	for {
+		probes++
+		if err := sr.performHealthCheck(sr.ctx, service); err == nil {
+			sr.completeWarmup(service, "healthy", probes)
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			// Hand the service to the regular health checks; it becomes
+			// selectable once one of them passes
+			sr.completeWarmup(service, "expired", probes)
+			return
+		case <-sr.ctx.Done():
+			return
+		}
+	}
+}
+
+// completeWarmup moves a warmed-up service into the active pool
+func (sr *ServiceRegistry) completeWarmup(service *RegisteredService, outcome string, probes int) {
+	sr.mutex.Lock()
+	_, registered := sr.services[service.ID]
+	if registered && service.Status == StatusRegistering {
+		service.Status = StatusActive
+	}
+	health := service.Health
+	sr.mutex.Unlock()
+
+	if !registered {
+		return
+	}
+
+	sr.metrics.Inc("service_warmups_total", "service_type", service.Type, "outcome", outcome)
+	sr.metrics.Observe("service_warmup_duration_seconds", time.Since(service.RegisteredAt).Seconds(),
+		"service_type", service.Type)
+
+	if outcome == "healthy" {
+		sr.logger.Info("service_warmup_completed",
+			"service_id", service.ID,
+			"probes", probes,
+			"duration", time.Since(service.RegisteredAt))
+	} else {
+		sr.logger.Warn("service_warmup_expired",
+			"service_id", service.ID,
+			"probes", probes,
+			"health", health)
+	}
+}
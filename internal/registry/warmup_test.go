@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestServiceWarmup tests that services with a warm-up period are not selected until they pass probes
+func TestServiceWarmup(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	defer healthMgr.Shutdown()
+
+	sr := NewServiceRegistry(logger, m, validation.NewValidator(logger, m), healthMgr)
+	defer sr.Shutdown()
+	sr.config.WarmupProbeInterval = 10 * time.Millisecond
+
+	var warm atomic.Bool
+	var probes atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		if !warm.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	register := func(t *testing.T, name string, warmup time.Duration) *RegisteredService {
+		t.Helper()
+		resp, err := sr.RegisterService(context.Background(), ServiceRegistrationRequest{
+			Name:         name,
+			Type:         "warmup_" + name,
+			Version:      "1.0.0",
+			Endpoint:     backend.URL,
+			Protocol:     "http",
+			WarmupPeriod: warmup,
+		})
+		if err != nil {
+			t.Fatalf("failed to register %s: %v", name, err)
+		}
+		return sr.GetService(resp.ServiceID)
+	}
+
+	waitFor := func(t *testing.T, condition func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for condition")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("service is selectable only after passing probes", func(t *testing.T) {
+		service := register(t, "cold", time.Minute)
+		if status, _ := sr.serviceState(service); status != StatusRegistering {
+			t.Fatalf("expected status %s during warm-up, got %s", StatusRegistering, status)
+		}
+
+		waitFor(t, func() bool { return probes.Load() >= 3 })
+		if _, err := sr.SelectService(service.Type, SelectionCriteria{}); err == nil {
+			t.Fatal("expected warming service not to be selectable")
+		}
+		sr.mutex.RLock()
+		failures := service.FailureCount
+		sr.mutex.RUnlock()
+		if failures != 0 {
+			t.Errorf("expected failed warm-up probes not to count as failures, got %d", failures)
+		}
+
+		warm.Store(true)
+		waitFor(t, func() bool {
+			_, err := sr.SelectService(service.Type, SelectionCriteria{})
+			return err == nil
+		})
+		if status, health := sr.serviceState(service); status != StatusActive || health != HealthHealthy {
+			t.Errorf("expected active healthy service after warm-up, got %s/%s", status, health)
+		}
+	})
+
+	t.Run("expired warm-up hands service to health checks", func(t *testing.T) {
+		warm.Store(false)
+		service := register(t, "never-warm", 50*time.Millisecond)
+
+		waitFor(t, func() bool {
+			status, _ := sr.serviceState(service)
+			return status == StatusActive
+		})
+		if _, err := sr.SelectService(service.Type, SelectionCriteria{}); err == nil {
+			t.Error("expected service that never passed a probe to remain unselectable")
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package router
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// Registration metadata keys overriding the gateway-wide forwarded header limits
+const (
+	MaxForwardHeadersMetadataKey     = "max_forward_headers"      // Most header lines forwarded from the client
+	MaxForwardHeaderBytesMetadataKey = "max_forward_header_bytes" // Most bytes of names and values forwarded
+	ForwardHeaderOverflowMetadataKey = "forward_header_overflow"  // drop or reject
+)
+
+// What happens to a request whose forwarded headers exceed a limit
+const (
+	HeaderOverflowDrop   = "drop"   // Forward headers in order while they fit, drop the rest
+	HeaderOverflowReject = "reject" // Fail the request without calling the backend
+)
+
+// BackendHeaderLimits bounds the client headers forwarded to a backend.
+// Header lines are counted per value, and sizes are the length of the name
+// plus the value. Injected headers are not counted. Zero disables a limit.
+type BackendHeaderLimits struct {
+	MaxCount int    `yaml:"max_count" json:"max_count"`
+	MaxBytes int    `yaml:"max_bytes" json:"max_bytes"`
+	Overflow string `yaml:"overflow" json:"overflow"` // drop (default) or reject
+}
+
+// Validate checks that the limits are not negative and the overflow policy is known
+func (l BackendHeaderLimits) Validate() error {
+	if l.MaxCount < 0 {
+		return fmt.Errorf("max_count must not be negative, got %d", l.MaxCount)
+	}
+	if l.MaxBytes < 0 {
+		return fmt.Errorf("max_bytes must not be negative, got %d", l.MaxBytes)
+	}
+	switch l.Overflow {
+	case "", HeaderOverflowDrop, HeaderOverflowReject:
+		return nil
+	default:
+		return fmt.Errorf("overflow must be %s or %s, got %q", HeaderOverflowDrop, HeaderOverflowReject, l.Overflow)
+	}
+}
+
+// headerLimitsFor returns the gateway-wide limits with the service's
+// registration metadata applied on top
+func (mr *MCPRouter) headerLimitsFor(service *registry.RegisteredService) (BackendHeaderLimits, error) {
+	limits := mr.config.BackendHeaders.Limits
+	if service != nil {
+		if count, ok, err := metadataInt(service.Metadata, MaxForwardHeadersMetadataKey); err != nil {
+			return limits, err
+		} else if ok {
+			limits.MaxCount = count
+		}
+		if size, ok, err := metadataInt(service.Metadata, MaxForwardHeaderBytesMetadataKey); err != nil {
+			return limits, err
+		} else if ok {
+			limits.MaxBytes = size
+		}
+		if overflow, ok := service.Metadata[ForwardHeaderOverflowMetadataKey].(string); ok && overflow != "" {
+			limits.Overflow = overflow
+		}
+	}
+	if limits.Overflow == "" {
+		limits.Overflow = HeaderOverflowDrop
+	}
+	return limits, limits.Validate()
+}
+
+// metadataInt reads a whole number given as a JSON number or a string
+func metadataInt(metadata map[string]interface{}, key string) (int, bool, error) {
+	switch value := metadata[key].(type) {
+	case nil:
+		return 0, false, nil
+	case int:
+		return value, true, nil
+	case float64:
+		if value != math.Trunc(value) || value > math.MaxInt32 {
+			return 0, false, fmt.Errorf("invalid %s: expected a whole number, got %v", key, value)
+		}
+		return int(value), true, nil
+	case string:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return parsed, true, nil
+	default:
+		return 0, false, fmt.Errorf("invalid %s: expected a number, got %T", key, value)
+	}
+}
+
+// limitForwardedHeaders applies the service's header limits to the client
+// headers selected for forwarding, in order. Headers are kept or dropped
+// whole, with all their values; under the reject policy any excess fails
+// the request instead.
+func (mr *MCPRouter) limitForwardedHeaders(reqCtx *RequestContext, service *registry.RegisteredService, names []string, headers http.Header) ([]string, error) {
+	limits, err := mr.headerLimitsFor(service)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header limits for service %s: %w", service.ID, err)
+	}
+	if limits.MaxCount == 0 && limits.MaxBytes == 0 {
+		return names, nil
+	}
+
+	var kept, dropped []string
+	count, size := 0, 0
+	for _, name := range names {
+		values := headers.Values(name)
+		headerSize := 0
+		for _, value := range values {
+			headerSize += len(name) + len(value)
+		}
+
+		if (limits.MaxCount > 0 && count+len(values) > limits.MaxCount) ||
+			(limits.MaxBytes > 0 && size+headerSize > limits.MaxBytes) {
+			dropped = append(dropped, name)
+			continue
+		}
+		kept = append(kept, name)
+		count += len(values)
+		size += headerSize
+	}
+	if len(dropped) == 0 {
+		return kept, nil
+	}
+
+	mr.metrics.Inc("backend_headers_over_limit_total", "service_id", service.ID, "policy", limits.Overflow)
+	mr.logger.Warn("backend_headers_over_limit",
+		"request_id", reqCtx.RequestID,
+		"service_id", service.ID,
+		"policy", limits.Overflow,
+		"headers", dropped,
+		"max_count", limits.MaxCount,
+		"max_bytes", limits.MaxBytes)
+
+	if limits.Overflow == HeaderOverflowReject {
+		return nil, errors.ValidationError(service.ID, "forward_headers",
+			"Forwarded request headers exceed the limits of the backend",
+			map[string]interface{}{
+				"headers":   dropped,
+				"max_count": limits.MaxCount,
+				"max_bytes": limits.MaxBytes,
+			})
+	}
+	return kept, nil
+}
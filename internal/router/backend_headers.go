@@ -0,0 +1,225 @@
+package router
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// Registration metadata keys extending the gateway-wide backend header settings
+const (
+	ForwardHeadersMetadataKey = "forward_headers" // list of inbound headers to forward
+	InjectHeadersMetadataKey  = "inject_headers"  // map of static headers to set
+)
+
+// BackendHeadersConfig controls which client headers reach backends and which
+// static headers are added to every backend request. Services can extend both
+// with the forward_headers and inject_headers registration metadata keys;
+// injected values for the same header override the gateway-wide ones. Limits
+// bound the forwarded headers, and services can override them with the
+// max_forward_headers, max_forward_header_bytes and forward_header_overflow keys.
+type BackendHeadersConfig struct {
+	Forward []string            `yaml:"forward" json:"forward"`
+	Inject  map[string]string   `yaml:"inject" json:"inject"`
+	Limits  BackendHeaderLimits `yaml:"limits" json:"limits"`
+}
+
+// strippedBackendHeaders are never forwarded from clients: hop-by-hop headers
+// describe the client connection, and credentials for the gateway must not
+// leak to backends. Use inject to authenticate the gateway to a backend.
+var strippedBackendHeaders = map[string]bool{
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+	"Authorization":       true,
+	"Cookie":              true,
+	"X-Admin-Api-Key":     true,
+}
+
+// gatewayManagedHeaders are set by the router itself and cannot be forwarded or injected
+var gatewayManagedHeaders = map[string]bool{
+	"Host":               true,
+	"Content-Type":       true,
+	"Content-Length":     true,
+	"Accept":             true,
+	IdempotencyKeyHeader: true,
+	TraceParentHeader:    true,
+}
+
+// validateBackendHeaders logs configured headers that will never be forwarded
+// or injected, so a misconfiguration is visible at startup, and drops
+// invalid header limits
+func (mr *MCPRouter) validateBackendHeaders() {
+	if err := mr.config.BackendHeaders.Limits.Validate(); err != nil {
+		mr.logger.Error("backend_header_limits_invalid", "error", err)
+		mr.config.BackendHeaders.Limits = BackendHeaderLimits{}
+	}
+	for _, name := range mr.config.BackendHeaders.Forward {
+		if !mr.forwardableHeader(http.CanonicalHeaderKey(name)) {
+			mr.logger.Warn("backend_header_not_forwardable", "header", name)
+		}
+	}
+	for name := range mr.config.BackendHeaders.Inject {
+		if !mr.injectableHeader(http.CanonicalHeaderKey(name)) {
+			mr.logger.Warn("backend_header_not_injectable", "header", name)
+		}
+	}
+}
+
+func (mr *MCPRouter) forwardableHeader(name string) bool {
+	return !strippedBackendHeaders[name] && mr.injectableHeader(name)
+}
+
+func (mr *MCPRouter) injectableHeader(name string) bool {
+	return !gatewayManagedHeaders[name] &&
+		name != http.CanonicalHeaderKey(mr.config.RequestIDHeader) &&
+		name != "Connection" && name != "Transfer-Encoding" && name != "Upgrade"
+}
+
+// applyBackendHeaders copies allowlisted client headers, within the
+// service's header limits, and injects static headers on an outgoing backend
+// request
+func (mr *MCPRouter) applyBackendHeaders(httpReq *http.Request, reqCtx *RequestContext, service *registry.RegisteredService) error {
+	if reqCtx != nil && reqCtx.InboundHeaders != nil {
+		// Headers named in the client's Connection header are hop-by-hop too
+		hopByHop := make(map[string]bool)
+		for _, value := range reqCtx.InboundHeaders.Values("Connection") {
+			for _, name := range strings.Split(value, ",") {
+				hopByHop[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+			}
+		}
+
+		var names []string
+		for _, name := range mr.forwardHeaderNames(service) {
+			if hopByHop[name] || !mr.forwardableHeader(name) {
+				continue
+			}
+			if len(reqCtx.InboundHeaders.Values(name)) > 0 {
+				names = append(names, name)
+			}
+		}
+
+		names, err := mr.limitForwardedHeaders(reqCtx, service, names, reqCtx.InboundHeaders)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			httpReq.Header[name] = append([]string(nil), reqCtx.InboundHeaders.Values(name)...)
+		}
+	}
+
+	for name, value := range mr.injectHeaders(service) {
+		if mr.injectableHeader(name) {
+			httpReq.Header.Set(name, value)
+		}
+	}
+	return nil
+}
+
+// forwardHeaderNames returns the canonical names of headers forwarded to a service
+func (mr *MCPRouter) forwardHeaderNames(service *registry.RegisteredService) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range mr.config.BackendHeaders.Forward {
+		add(name)
+	}
+	if service != nil {
+		switch list := service.Metadata[ForwardHeadersMetadataKey].(type) {
+		case []string:
+			for _, name := range list {
+				add(name)
+			}
+		case []interface{}:
+			for _, name := range list {
+				if s, ok := name.(string); ok {
+					add(s)
+				}
+			}
+		case string:
+			for _, name := range strings.Split(list, ",") {
+				add(name)
+			}
+		}
+	}
+	return names
+}
+
+// injectHeaders returns the static headers for a service, with service
+// metadata overriding gateway-wide values
+func (mr *MCPRouter) injectHeaders(service *registry.RegisteredService) map[string]string {
+	headers := make(map[string]string, len(mr.config.BackendHeaders.Inject))
+	for name, value := range mr.config.BackendHeaders.Inject {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	if service != nil {
+		switch inject := service.Metadata[InjectHeadersMetadataKey].(type) {
+		case map[string]string:
+			for name, value := range inject {
+				headers[http.CanonicalHeaderKey(name)] = value
+			}
+		case map[string]interface{}:
+			for name, value := range inject {
+				if s, ok := value.(string); ok {
+					headers[http.CanonicalHeaderKey(name)] = s
+				}
+			}
+		}
+	}
+	return headers
+}
+
+// forwardedHeaderKey identifies the request's values of every header the
+// gateway forwards, gateway-wide or through a service's forward_headers, so
+// requests that would reach backends with different headers share neither an
+// upstream call nor a cached result. The backend is not chosen yet when the
+// key is needed, so the headers of all registered services count.
+func (mr *MCPRouter) forwardedHeaderKey(reqCtx *RequestContext) string {
+	if reqCtx.InboundHeaders == nil {
+		return ""
+	}
+
+	names := mr.forwardHeaderNames(nil)
+	if mr.registry != nil {
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			seen[name] = true
+		}
+		for _, service := range mr.registry.GetAllServices() {
+			for _, name := range mr.forwardHeaderNames(service) {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	for _, name := range names {
+		key.WriteString(name)
+		key.WriteByte('=')
+		key.WriteString(strings.Join(reqCtx.InboundHeaders.Values(name), ","))
+		key.WriteByte('\n')
+	}
+	return key.String()
+}
@@ -0,0 +1,215 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestBackendHeaders tests that only allowlisted client headers reach backends and static headers are injected
+func TestBackendHeaders(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	received := make(chan http.Header, 1)
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"ok"}]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "header-backend", "tool_provider", backend.URL, map[string]interface{}{
+		ForwardHeadersMetadataKey: []interface{}{"X-Tenant"},
+		InjectHeadersMetadataKey:  map[string]interface{}{"X-Api-Key": "service-key"},
+	})
+
+	config := DefaultRouterConfig()
+	config.BackendHeaders = BackendHeadersConfig{
+		Forward: []string{"accept-language", "X-Correlation-ID", "Authorization", "Content-Type"},
+		Inject:  map[string]string{"X-Api-Key": "gateway-key", "X-Gateway": "mcpeg"},
+	}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "echo"})
+	req.Header.Set("Accept-Language", "de-DE")
+	req.Header.Set("X-Correlation-ID", "corr-123")
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("X-Unlisted", "secret")
+	req.Header.Set("Authorization", "Bearer client-token")
+	req.Header.Set("Cookie", "session=abc")
+	mr.handleMCPRequest(httptest.NewRecorder(), req)
+
+	var headers http.Header
+	select {
+	case headers = <-received:
+	default:
+		t.Fatal("expected request to reach the backend")
+	}
+
+	t.Run("allowlisted headers are forwarded", func(t *testing.T) {
+		for name, want := range map[string]string{
+			"Accept-Language":  "de-DE",
+			"X-Correlation-Id": "corr-123",
+			"X-Tenant":         "acme",
+		} {
+			if got := headers.Get(name); got != want {
+				t.Errorf("expected %s %q, got %q", name, want, got)
+			}
+		}
+	})
+
+	t.Run("other and sensitive headers are dropped", func(t *testing.T) {
+		for _, name := range []string{"X-Unlisted", "Authorization", "Cookie"} {
+			if got := headers.Get(name); got != "" {
+				t.Errorf("expected %s not to be forwarded, got %q", name, got)
+			}
+		}
+		if got := headers.Get("Content-Type"); got != "application/json" {
+			t.Errorf("expected gateway Content-Type to be kept, got %q", got)
+		}
+	})
+
+	t.Run("static headers are injected with service overrides", func(t *testing.T) {
+		if got := headers.Get("X-Gateway"); got != "mcpeg" {
+			t.Errorf("expected injected X-Gateway header, got %q", got)
+		}
+		if got := headers.Get("X-Api-Key"); got != "service-key" {
+			t.Errorf("expected service metadata to override X-Api-Key, got %q", got)
+		}
+	})
+
+	t.Run("sharing keys cover headers forwarded by services", func(t *testing.T) {
+		tenant := func(name string) *RequestContext {
+			headers := http.Header{}
+			headers.Set("X-Tenant", name)
+			return &RequestContext{InboundHeaders: headers}
+		}
+		acme, globex := tenant("acme"), tenant("globex")
+		if mr.forwardedHeaderKey(acme) == mr.forwardedHeaderKey(globex) {
+			t.Error("expected requests with different X-Tenant values not to be coalesced")
+		}
+
+		mr.config.DegradedMode.Enabled = true
+		defer func() { mr.config.DegradedMode.Enabled = false }()
+		request := &mcpTypes.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "resources/list"}
+		acmeKey, _ := mr.degradedCacheKey(acme, request)
+		globexKey, _ := mr.degradedCacheKey(globex, request)
+		if acmeKey == globexKey {
+			t.Error("expected requests with different X-Tenant values not to share a last-known-good result")
+		}
+	})
+}
+
+// TestBackendHeaderLimits tests that forwarded headers beyond a service's
+// limits are dropped or fail the request, per its overflow policy
+func TestBackendHeaderLimits(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	received := make(chan http.Header, 4)
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+
+	config := DefaultRouterConfig()
+	config.BackendHeaders = BackendHeadersConfig{
+		Forward: []string{"X-Tenant", "X-Locale", "X-Trace-Tags"},
+		Inject:  map[string]string{"X-Gateway": "mcpeg"},
+		Limits:  BackendHeaderLimits{MaxCount: 2},
+	}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	call := func(t *testing.T, method string) (http.Header, map[string]interface{}) {
+		t.Helper()
+		req := newJSONRPCRequest(t, method, nil)
+		req.Header.Set("X-Tenant", "acme")
+		req.Header.Set("X-Locale", "de-DE")
+		req.Header.Set("X-Trace-Tags", strings.Repeat("t", 64))
+		rec := httptest.NewRecorder()
+		mr.handleMCPRequest(rec, req)
+
+		var resp struct {
+			Error map[string]interface{} `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		select {
+		case headers := <-received:
+			return headers, resp.Error
+		default:
+			return nil, resp.Error
+		}
+	}
+
+	t.Run("excess headers are dropped by default", func(t *testing.T) {
+		registerTestService(t, serviceRegistry, "default-limits", "weather_provider", backend.URL, nil)
+
+		headers, rpcErr := call(t, "weather/forecast")
+		if rpcErr != nil || headers == nil {
+			t.Fatalf("expected the request to reach the backend, got %v", rpcErr)
+		}
+		if headers.Get("X-Tenant") != "acme" || headers.Get("X-Locale") != "de-DE" {
+			t.Errorf("expected the first two headers to be forwarded, got %v", headers)
+		}
+		if headers.Get("X-Trace-Tags") != "" {
+			t.Error("expected the header over the count limit to be dropped")
+		}
+		if headers.Get("X-Gateway") != "mcpeg" {
+			t.Error("expected injected headers not to count against the limit")
+		}
+	})
+
+	t.Run("service metadata overrides the size limit", func(t *testing.T) {
+		registerTestService(t, serviceRegistry, "small-headers", "calendar_provider", backend.URL, map[string]interface{}{
+			MaxForwardHeadersMetadataKey:     float64(10),
+			MaxForwardHeaderBytesMetadataKey: float64(40),
+		})
+
+		headers, rpcErr := call(t, "calendar/events")
+		if rpcErr != nil || headers == nil {
+			t.Fatalf("expected the request to reach the backend, got %v", rpcErr)
+		}
+		if headers.Get("X-Tenant") == "" || headers.Get("X-Locale") == "" || headers.Get("X-Trace-Tags") != "" {
+			t.Errorf("expected only the headers within 40 bytes to be forwarded, got %v", headers)
+		}
+	})
+
+	t.Run("reject policy fails the request", func(t *testing.T) {
+		registerTestService(t, serviceRegistry, "strict-headers", "billing_provider", backend.URL, map[string]interface{}{
+			ForwardHeaderOverflowMetadataKey: HeaderOverflowReject,
+		})
+
+		headers, rpcErr := call(t, "billing/invoice")
+		if headers != nil {
+			t.Fatal("expected the request not to reach the backend")
+		}
+		if rpcErr == nil || rpcErr["code"] != float64(-32602) {
+			t.Errorf("expected an invalid params error, got %v", rpcErr)
+		}
+	})
+
+	t.Run("invalid limits are rejected", func(t *testing.T) {
+		for name, limits := range map[string]BackendHeaderLimits{
+			"negative count":   {MaxCount: -1},
+			"negative size":    {MaxBytes: -1},
+			"unknown overflow": {Overflow: "truncate"},
+		} {
+			if err := limits.Validate(); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// maxBackendRedirects bounds redirect chains followed for one backend call
+const maxBackendRedirects = 5
+
+// backendClient returns the HTTP client for a backend call, with the
+// service's TLS policy. A zero timeout leaves the deadline to the request
+// context.
+func (mr *MCPRouter) backendClient(service *registry.RegisteredService, timeout time.Duration) (*http.Client, error) {
+	transport, err := mr.transportFor(service)
+	if err != nil {
+		return nil, err
+	}
+	if insecure, _ := registry.InsecureUpstreamTLS(service.Metadata); insecure {
+		mr.metrics.Inc("mcp_upstream_insecure_tls_requests_total", "service_id", service.ID)
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       timeout,
+		CheckRedirect: mr.checkBackendRedirect,
+	}, nil
+}
+
+// checkBackendRedirect stops at the first redirect unless FollowRedirects is
+// set. Followed redirects must keep the method, since the client turns a POST
+// into a GET without its body on 301, 302 and 303, so only 307 and 308
+// succeed.
+func (mr *MCPRouter) checkBackendRedirect(req *http.Request, via []*http.Request) error {
+	if !mr.config.FollowRedirects {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > maxBackendRedirects {
+		return redirectError{fmt.Errorf("stopped after %d redirects", maxBackendRedirects)}
+	}
+	if req.Method != via[0].Method {
+		return redirectError{fmt.Errorf("redirect to %s would change the request method from %s to %s and drop the request body",
+			req.URL.Redacted(), via[0].Method, req.Method)}
+	}
+
+	mr.logger.Debug("backend_redirect_followed",
+		"from", via[len(via)-1].URL.Redacted(),
+		"location", req.URL.Redacted())
+	return nil
+}
+
+// redirectError is a redirect the gateway refuses to follow. Retrying the
+// call would only be redirected the same way.
+type redirectError struct {
+	error
+}
+
+// isRedirect reports whether status is an HTTP redirect with a Location
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// backendRedirected records a redirect response that was not followed and
+// returns the error to report
+func (mr *MCPRouter) backendRedirected(reqCtx *RequestContext, service *registry.RegisteredService, method string, resp *http.Response) error {
+	location := resp.Header.Get("Location")
+	if resolved, err := resp.Location(); err == nil {
+		location = resolved.Redacted()
+	}
+
+	requestID := ""
+	if reqCtx != nil {
+		requestID = reqCtx.RequestID
+	}
+
+	mr.metrics.Inc("backend_redirects_total",
+		"service_id", service.ID,
+		"status", fmt.Sprint(resp.StatusCode))
+	mr.logger.Warn("backend_redirect_not_followed",
+		"request_id", requestID,
+		"service_id", service.ID,
+		"method", method,
+		"status_code", resp.StatusCode,
+		"location", location,
+		"follow_redirects", mr.config.FollowRedirects)
+
+	return fmt.Errorf("service %s redirected with HTTP %d to %s; update its endpoint or enable follow_redirects",
+		service.ID, resp.StatusCode, location)
+}
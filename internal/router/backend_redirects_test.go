@@ -0,0 +1,122 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestBackendRedirects tests that backend redirects fail the call unless following is enabled
+func TestBackendRedirects(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	var targetCalls atomic.Int32
+	target := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		targetCalls.Add(1)
+		if r.Method != http.MethodPost {
+			t.Errorf("expected the redirect target to receive POST, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"ok"}]}}`))
+	})
+
+	redirectStatus := http.StatusFound
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/mcp", redirectStatus)
+	})
+
+	call := func(t *testing.T, followRedirects bool) map[string]interface{} {
+		t.Helper()
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		registerTestService(t, serviceRegistry, "redirecting-backend", "tool_provider", backend.URL, nil)
+
+		config := DefaultRouterConfig()
+		config.FollowRedirects = followRedirects
+		config.ErrorDetail = ErrorDetailFull
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "echo"}))
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	t.Run("redirects are not followed by default", func(t *testing.T) {
+		targetCalls.Store(0)
+		response := call(t, false)
+		errObj, ok := response["error"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected an error response, got %v", response)
+		}
+		data, _ := json.Marshal(errObj)
+		if !strings.Contains(string(data), target.URL+"/mcp") {
+			t.Errorf("expected the error to name the redirect location, got %s", data)
+		}
+		if targetCalls.Load() != 0 {
+			t.Error("expected the redirect target not to be called")
+		}
+	})
+
+	t.Run("method-changing redirects are rejected when following", func(t *testing.T) {
+		targetCalls.Store(0)
+		if _, ok := call(t, true)["error"]; !ok {
+			t.Error("expected a 302 to fail rather than replay the call as GET")
+		}
+		if targetCalls.Load() != 0 {
+			t.Error("expected the redirect target not to be called")
+		}
+	})
+
+	t.Run("method-preserving redirects are followed when enabled", func(t *testing.T) {
+		targetCalls.Store(0)
+		redirectStatus = http.StatusTemporaryRedirect
+		defer func() { redirectStatus = http.StatusFound }()
+
+		response := call(t, true)
+		if _, ok := response["error"]; ok {
+			t.Fatalf("expected the redirect to be followed, got %v", response)
+		}
+		if targetCalls.Load() != 1 {
+			t.Errorf("expected the redirect target to be called once, got %d", targetCalls.Load())
+		}
+	})
+}
+
+// TestSchemelessBackendEndpoint tests that endpoints registered without a scheme are called over http
+func TestSchemelessBackendEndpoint(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"ok"}]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "schemeless-backend", "tool_provider", strings.TrimPrefix(backend.URL, "http://"), nil)
+
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, DefaultRouterConfig())
+
+	w := httptest.NewRecorder()
+	mr.handleMCPRequest(w, newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "echo"}))
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := response["error"]; ok {
+		t.Fatalf("expected the scheme-less endpoint to be reached, got %v", response)
+	}
+}
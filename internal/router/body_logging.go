@@ -0,0 +1,149 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RedactedValue replaces redacted fields in logged bodies
+const RedactedValue = "***"
+
+// BodyLoggingConfig configures opt-in trace logging of JSON-RPC request params
+// and response results for debugging routing problems
+type BodyLoggingConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Paths restricts logging to these URL paths (e.g. /mcp/tools/call); empty logs all
+	Paths []string `yaml:"paths"`
+
+	// RedactPaths lists dot-separated JSON paths relative to params or result
+	// (e.g. arguments.api_key); a * segment matches any key or array element
+	RedactPaths []string `yaml:"redact_paths"`
+
+	// MaxBodySize truncates logged bodies to this many bytes
+	MaxBodySize int `yaml:"max_body_size"`
+}
+
+// defaultBodyLoggingConfig returns body logging disabled with common secrets redacted
+func defaultBodyLoggingConfig() BodyLoggingConfig {
+	return BodyLoggingConfig{
+		Enabled: false,
+		RedactPaths: []string{
+			"arguments.api_key",
+			"arguments.token",
+			"arguments.password",
+			"arguments.secret",
+		},
+		MaxBodySize: 4096,
+	}
+}
+
+// logRequestBody logs redacted JSON-RPC request params when body logging applies to r
+func (mr *MCPRouter) logRequestBody(r *http.Request, reqCtx *RequestContext, params interface{}) {
+	if !mr.shouldLogBody(r) {
+		return
+	}
+
+	mr.logger.Trace("mcp_request_body",
+		"request_id", reqCtx.RequestID,
+		"method", reqCtx.Method,
+		"params", mr.formatLoggedBody(params))
+}
+
+// logResponseBody logs a redacted JSON-RPC response result when body logging applies to r
+func (mr *MCPRouter) logResponseBody(r *http.Request, reqCtx *RequestContext, result interface{}) {
+	if !mr.shouldLogBody(r) {
+		return
+	}
+
+	mr.logger.Trace("mcp_response_body",
+		"request_id", reqCtx.RequestID,
+		"method", reqCtx.Method,
+		"result", mr.formatLoggedBody(result))
+}
+
+func (mr *MCPRouter) shouldLogBody(r *http.Request) bool {
+	cfg := mr.config.BodyLogging
+	if !cfg.Enabled {
+		return false
+	}
+	if len(cfg.Paths) == 0 {
+		return true
+	}
+	return contains(cfg.Paths, r.URL.Path)
+}
+
+// formatLoggedBody redacts a body on a copy and truncates it to the configured size
+func (mr *MCPRouter) formatLoggedBody(body interface{}) string {
+	cfg := mr.config.BodyLogging
+	return formatRedactedBody(body, cfg.RedactPaths, cfg.MaxBodySize)
+}
+
+// formatRedactedBody serializes body with the values at redactPaths replaced,
+// truncated to maxSize bytes when maxSize is positive
+func formatRedactedBody(body interface{}, redactPaths []string, maxSize int) string {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Sprintf("<unserializable: %v>", err)
+	}
+
+	// Round-trip into generic values so redaction never touches the live request
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return fmt.Sprintf("<unserializable: %v>", err)
+	}
+
+	for _, path := range redactPaths {
+		redactJSONPath(generic, strings.Split(path, "."))
+	}
+
+	redacted, err := json.Marshal(generic)
+	if err != nil {
+		return fmt.Sprintf("<unserializable: %v>", err)
+	}
+
+	if maxSize > 0 && len(redacted) > maxSize {
+		return fmt.Sprintf("%s...(truncated %d bytes)", redacted[:maxSize], len(redacted)-maxSize)
+	}
+	return string(redacted)
+}
+
+// redactJSONPath replaces the value at path within a decoded JSON document
+func redactJSONPath(node interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+
+	segment, rest := path[0], path[1:]
+
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if segment != "*" && segment != key {
+				continue
+			}
+			if len(rest) == 0 {
+				value[key] = RedactedValue
+			} else {
+				redactJSONPath(child, rest)
+			}
+		}
+	case []interface{}:
+		// Array elements are traversed transparently unless addressed by a wildcard
+		if segment == "*" {
+			for i, child := range value {
+				if len(rest) == 0 {
+					value[i] = RedactedValue
+				} else {
+					redactJSONPath(child, rest)
+				}
+			}
+			return
+		}
+		for _, child := range value {
+			redactJSONPath(child, path)
+		}
+	}
+}
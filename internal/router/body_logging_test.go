@@ -0,0 +1,93 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestBodyLoggingRedaction tests redaction and truncation of logged JSON-RPC bodies
+func TestBodyLoggingRedaction(t *testing.T) {
+	logger := logging.New("test")
+
+	newRouter := func(cfg BodyLoggingConfig) *MCPRouter {
+		config := DefaultRouterConfig()
+		config.BodyLogging = cfg
+		return NewMCPRouterWithConfig(nil, nil, nil, logger, &mockMetrics{}, nil, config)
+	}
+
+	t.Run("configured sensitive fields are redacted", func(t *testing.T) {
+		mr := newRouter(BodyLoggingConfig{
+			Enabled:     true,
+			RedactPaths: []string{"arguments.api_key", "credentials.*.token"},
+			MaxBodySize: 4096,
+		})
+
+		params := map[string]interface{}{
+			"name": "search",
+			"arguments": map[string]interface{}{
+				"api_key": "sk-live-secret",
+				"query":   "weather",
+			},
+			"credentials": []interface{}{
+				map[string]interface{}{"token": "tok-1", "user": "alice"},
+				map[string]interface{}{"token": "tok-2", "user": "bob"},
+			},
+		}
+
+		logged := mr.formatLoggedBody(params)
+
+		for _, secret := range []string{"sk-live-secret", "tok-1", "tok-2"} {
+			if strings.Contains(logged, secret) {
+				t.Errorf("expected %s to be redacted, got %s", secret, logged)
+			}
+		}
+
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(logged), &decoded); err != nil {
+			t.Fatalf("expected logged body to remain valid JSON: %v", err)
+		}
+		arguments := decoded["arguments"].(map[string]interface{})
+		if arguments["api_key"] != RedactedValue {
+			t.Errorf("expected api_key to be %q, got %v", RedactedValue, arguments["api_key"])
+		}
+		if arguments["query"] != "weather" {
+			t.Errorf("expected non-sensitive field to be kept, got %v", arguments["query"])
+		}
+
+		// Redaction must not modify the body that is routed onwards
+		if params["arguments"].(map[string]interface{})["api_key"] != "sk-live-secret" {
+			t.Error("expected original params to be left untouched")
+		}
+	})
+
+	t.Run("bodies over the size limit are truncated", func(t *testing.T) {
+		mr := newRouter(BodyLoggingConfig{Enabled: true, MaxBodySize: 32})
+
+		logged := mr.formatLoggedBody(map[string]interface{}{"data": strings.Repeat("x", 500)})
+
+		if !strings.Contains(logged, "...(truncated") {
+			t.Errorf("expected truncation marker, got %s", logged)
+		}
+		if prefix := logged[:strings.Index(logged, "...")]; len(prefix) != 32 {
+			t.Errorf("expected 32 bytes of body before truncation, got %d", len(prefix))
+		}
+	})
+
+	t.Run("disabled by default and filtered by path", func(t *testing.T) {
+		if DefaultRouterConfig().BodyLogging.Enabled {
+			t.Error("expected body logging to be off by default")
+		}
+
+		mr := newRouter(BodyLoggingConfig{Enabled: true, Paths: []string{"/mcp/tools/call"}})
+		if !mr.shouldLogBody(httptest.NewRequest("POST", "/mcp/tools/call", nil)) {
+			t.Error("expected configured path to be logged")
+		}
+		if mr.shouldLogBody(httptest.NewRequest("POST", "/mcp", nil)) {
+			t.Error("expected unlisted path not to be logged")
+		}
+	})
+}
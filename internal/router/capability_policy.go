@@ -0,0 +1,146 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// CapabilityPolicyConfig is a gateway-wide allowlist/denylist of tool and resource
+// names applied to every user on top of RBAC. Entries are exact names or patterns
+// where * matches any run of characters, such as "*_delete". Tool entries match
+// either the bare tool name or plugin.tool; resource entries match the resource
+// URI. An empty allowlist allows everything, and a denylist match always wins.
+type CapabilityPolicyConfig struct {
+	AllowedTools     []string `yaml:"allowed_tools" json:"allowed_tools"`
+	DeniedTools      []string `yaml:"denied_tools" json:"denied_tools"`
+	AllowedResources []string `yaml:"allowed_resources" json:"allowed_resources"`
+	DeniedResources  []string `yaml:"denied_resources" json:"denied_resources"`
+}
+
+// Validate checks that no policy entry is empty
+func (c CapabilityPolicyConfig) Validate() error {
+	lists := map[string][]string{
+		"allowed_tools":     c.AllowedTools,
+		"denied_tools":      c.DeniedTools,
+		"allowed_resources": c.AllowedResources,
+		"denied_resources":  c.DeniedResources,
+	}
+	for field, patterns := range lists {
+		for _, pattern := range patterns {
+			if strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("empty pattern in %s", field)
+			}
+		}
+	}
+	return nil
+}
+
+// SetCapabilityPolicy validates and atomically replaces the capability policy,
+// taking effect for the next request
+func (mr *MCPRouter) SetCapabilityPolicy(policy CapabilityPolicyConfig) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	mr.capabilityPolicy.Store(&policy)
+
+	mr.logger.Info("capability_policy_updated",
+		"allowed_tools", len(policy.AllowedTools),
+		"denied_tools", len(policy.DeniedTools),
+		"allowed_resources", len(policy.AllowedResources),
+		"denied_resources", len(policy.DeniedResources))
+
+	return nil
+}
+
+// GetCapabilityPolicy returns the capability policy currently in force
+func (mr *MCPRouter) GetCapabilityPolicy() CapabilityPolicyConfig {
+	if policy := mr.capabilityPolicy.Load(); policy != nil {
+		return *policy
+	}
+	return CapabilityPolicyConfig{}
+}
+
+// toolAllowed reports whether the policy permits a plugin tool
+func (mr *MCPRouter) toolAllowed(pluginName, toolName string) bool {
+	policy := mr.capabilityPolicy.Load()
+	if policy == nil {
+		return true
+	}
+
+	bare := strings.TrimPrefix(toolName, pluginName+".")
+	return policyAllows(policy.AllowedTools, policy.DeniedTools, bare, pluginName+"."+bare)
+}
+
+// resourceAllowed reports whether the policy permits a resource URI
+func (mr *MCPRouter) resourceAllowed(uri string) bool {
+	policy := mr.capabilityPolicy.Load()
+	if policy == nil {
+		return true
+	}
+
+	return policyAllows(policy.AllowedResources, policy.DeniedResources, uri)
+}
+
+// capabilityDeniedError is returned when the gateway policy blocks a capability
+func (mr *MCPRouter) capabilityDeniedError(reqCtx *RequestContext, kind, name string) error {
+	mr.metrics.Inc("capability_policy_denials_total", "kind", kind, "name", name)
+	mr.logger.Warn("capability_policy_denied",
+		"request_id", reqCtx.RequestID,
+		"user_id", reqCtx.UserID,
+		"kind", kind,
+		"name", name)
+
+	return errors.AuthorizationError("mcp_router", "capability_policy",
+		fmt.Sprintf("The %s %s is disabled by gateway policy", kind, name),
+		map[string]interface{}{
+			"kind":       kind,
+			"name":       name,
+			"request_id": reqCtx.RequestID,
+		})
+}
+
+func policyAllows(allowed, denied []string, names ...string) bool {
+	if matchesAnyPattern(denied, names) {
+		return false
+	}
+	return len(allowed) == 0 || matchesAnyPattern(allowed, names)
+}
+
+func matchesAnyPattern(patterns, names []string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if wildcardMatch(pattern, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches name against pattern, where * matches any run of
+// characters including "/" so URI patterns like memory://* work
+func wildcardMatch(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(name, part)
+		if idx < 0 {
+			return false
+		}
+		name = name[idx+len(part):]
+	}
+
+	return len(name) >= len(last) && strings.HasSuffix(name, last)
+}
@@ -0,0 +1,156 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestCapabilityPolicy tests that denied tools are hidden from tools/list and rejected on tools/call
+func TestCapabilityPolicy(t *testing.T) {
+	logger := logging.New("test")
+	handler := &fakePluginHandler{
+		tools: map[string][]string{
+			"memory": {"memory_store", "memory_delete"},
+			"git":    {"git_status", "git_push"},
+		},
+	}
+
+	config := DefaultRouterConfig()
+	config.CapabilityPolicy = CapabilityPolicyConfig{
+		DeniedTools: []string{"*_delete", "git.git_push"},
+	}
+	mr := NewMCPRouterWithConfig(nil, handler, nil, logger, &mockMetrics{}, nil, config)
+
+	send := func(t *testing.T, method string, params map[string]interface{}) map[string]json.RawMessage {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+		req := httptest.NewRequest("POST", "/mcp", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, req)
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return resp
+	}
+
+	listTools := func(t *testing.T) map[string]bool {
+		t.Helper()
+		resp := send(t, "tools/list", nil)
+		var result struct {
+			Tools []mcpTypes.Tool `json:"tools"`
+		}
+		if err := json.Unmarshal(resp["result"], &result); err != nil {
+			t.Fatalf("failed to decode tools/list result: %v (%v)", err, resp)
+		}
+		names := make(map[string]bool)
+		for _, tool := range result.Tools {
+			names[tool.Name] = true
+		}
+		return names
+	}
+
+	errorCode := func(t *testing.T, resp map[string]json.RawMessage) int {
+		t.Helper()
+		var rpcErr struct {
+			Code int `json:"code"`
+		}
+		if err := json.Unmarshal(resp["error"], &rpcErr); err != nil {
+			t.Fatalf("expected error response, got %v", resp)
+		}
+		return rpcErr.Code
+	}
+
+	t.Run("denied tools are absent from tools/list", func(t *testing.T) {
+		names := listTools(t)
+		for _, denied := range []string{"memory_delete", "git_push"} {
+			if names[denied] {
+				t.Errorf("expected denied tool %s to be hidden, got %v", denied, names)
+			}
+		}
+		for _, allowed := range []string{"memory_store", "git_status"} {
+			if !names[allowed] {
+				t.Errorf("expected allowed tool %s in listing, got %v", allowed, names)
+			}
+		}
+	})
+
+	t.Run("denied tool is rejected on tools/call", func(t *testing.T) {
+		handler.lastTool = ""
+		resp := send(t, "tools/call", map[string]interface{}{"name": "memory_delete"})
+		if code := errorCode(t, resp); code != mcpTypes.ErrorCodeForbidden {
+			t.Errorf("expected permission denied code %d, got %d", mcpTypes.ErrorCodeForbidden, code)
+		}
+		if handler.lastTool != "" {
+			t.Errorf("expected denied call not to reach the plugin, reached %s", handler.lastTool)
+		}
+	})
+
+	t.Run("allowed tool is invoked", func(t *testing.T) {
+		resp := send(t, "tools/call", map[string]interface{}{"name": "git_status"})
+		if _, hasError := resp["error"]; hasError {
+			t.Fatalf("expected allowed call to succeed, got %s", resp["error"])
+		}
+		if handler.lastTool != "git_status" {
+			t.Errorf("expected git_status to be invoked, got %s", handler.lastTool)
+		}
+	})
+
+	t.Run("policy reload takes effect on the next request", func(t *testing.T) {
+		if err := mr.SetCapabilityPolicy(CapabilityPolicyConfig{AllowedTools: []string{"memory.*"}}); err != nil {
+			t.Fatalf("failed to set policy: %v", err)
+		}
+
+		names := listTools(t)
+		if !names["memory_delete"] || names["git_status"] {
+			t.Errorf("expected only memory tools after reload, got %v", names)
+		}
+
+		resp := send(t, "tools/call", map[string]interface{}{"name": "git_status"})
+		if code := errorCode(t, resp); code != mcpTypes.ErrorCodeForbidden {
+			t.Errorf("expected permission denied code %d, got %d", mcpTypes.ErrorCodeForbidden, code)
+		}
+	})
+
+	t.Run("invalid policy is rejected and the previous one kept", func(t *testing.T) {
+		if err := mr.SetCapabilityPolicy(CapabilityPolicyConfig{DeniedTools: []string{" "}}); err == nil {
+			t.Error("expected empty pattern to be rejected")
+		}
+		if allowed := mr.GetCapabilityPolicy().AllowedTools; len(allowed) != 1 || allowed[0] != "memory.*" {
+			t.Errorf("expected previous policy to remain, got %v", allowed)
+		}
+	})
+}
+
+// TestWildcardMatch tests capability pattern matching
+func TestWildcardMatch(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"memory_store", "memory_store", true},
+		{"memory_store", "memory_stores", false},
+		{"*_delete", "memory_delete", true},
+		{"*_delete", "memory_delete_all", false},
+		{"git.*", "git.git_push", true},
+		{"memory://*", "memory://notes/2024/today", true},
+		{"file://*/secret*", "file://home/user/secrets.txt", true},
+		{"file://*/secret*", "file://home/user/public.txt", false},
+		{"a*a", "a", false},
+		{"*", "", true},
+	}
+
+	for _, tc := range testCases {
+		if got := wildcardMatch(tc.pattern, tc.name); got != tc.match {
+			t.Errorf("wildcardMatch(%q, %q) = %v, expected %v", tc.pattern, tc.name, got, tc.match)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// coalescedCall is one upstream call shared by concurrent identical reads
+type coalescedCall struct {
+	done    chan struct{}
+	result  interface{}
+	err     error
+	outcome RequestContext // routing state of the shared call, copied to every waiter
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// requestCoalescer collapses concurrent identical read requests into a single
+// upstream call, singleflight style. Nothing is cached: once the call
+// completes the next request starts a new one.
+type requestCoalescer struct {
+	mutex sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+func newRequestCoalescer() *requestCoalescer {
+	return &requestCoalescer{
+		calls: make(map[string]*coalescedCall),
+	}
+}
+
+// do joins the in-flight call for key or starts one running fn. The shared
+// call is detached from the caller that started it, deadline included, since
+// callers may carry different deadlines; it is only cancelled once every
+// waiting caller has gone away. shared reports whether the caller joined a
+// call started by another request.
+func (c *requestCoalescer) do(ctx context.Context, key string, reqCtx *RequestContext, fn func(context.Context, *RequestContext) (interface{}, error)) (call *coalescedCall, shared bool, err error) {
+	c.mutex.Lock()
+	call, shared = c.calls[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = call
+
+		// The call routes with its own copy of the request context so it
+		// never races with a caller that stopped waiting
+		outcome := *reqCtx
+		go func() {
+			result, err := fn(callCtx, &outcome)
+
+			c.mutex.Lock()
+			if c.calls[key] == call {
+				delete(c.calls, key)
+			}
+			call.result, call.err, call.outcome = result, err, outcome
+			c.mutex.Unlock()
+
+			close(call.done)
+			cancel()
+		}()
+	}
+	call.waiters++
+	c.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call, shared, nil
+	case <-ctx.Done():
+		c.mutex.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			if c.calls[key] == call {
+				delete(c.calls, key)
+			}
+		}
+		c.mutex.Unlock()
+		return nil, shared, ctx.Err()
+	}
+}
+
+// routeWithCoalescing routes a request, sharing one upstream call between
+// concurrent read requests with the same method, params and capabilities.
+// Write methods always execute on their own.
+func (mr *MCPRouter) routeWithCoalescing(ctx context.Context, reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+	if !mr.config.RequestCoalescing || !readOnlyMethods[mcpReq.Method] {
+		return mr.routeJSONRPCRequest(ctx, reqCtx, mcpReq)
+	}
+
+	params, err := json.Marshal(mcpReq.Params)
+	if err != nil {
+		return mr.routeJSONRPCRequest(ctx, reqCtx, mcpReq)
+	}
+	key := mcpReq.Method + "\x00" + string(params) + "\x00" + capabilityHash(reqCtx) + "\x00" + mr.forwardedHeaderKey(reqCtx)
+
+	call, shared, err := mr.coalescer.do(ctx, key, reqCtx, func(callCtx context.Context, callReqCtx *RequestContext) (interface{}, error) {
+		return mr.routeJSONRPCRequest(callCtx, callReqCtx, mcpReq)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx.ServiceID = call.outcome.ServiceID
+	reqCtx.ServedStale = call.outcome.ServedStale
+	reqCtx.StaleAge = call.outcome.StaleAge
+
+	if shared {
+		mr.metrics.Inc("mcp_coalesced_requests_total", "method", mcpReq.Method)
+		mr.logger.Debug("mcp_request_coalesced",
+			"request_id", reqCtx.RequestID,
+			"method", mcpReq.Method,
+			"shared_with", call.outcome.RequestID)
+	}
+
+	return call.result, call.err
+}
+
+// capabilityHash identifies the roles and plugin permissions a request was
+// resolved with, so only callers allowed to see the same results share a call
+func capabilityHash(reqCtx *RequestContext) string {
+	if reqCtx.Capabilities == nil {
+		return ""
+	}
+
+	roles := append([]string(nil), reqCtx.Capabilities.Roles...)
+	sort.Strings(roles)
+
+	// Maps marshal with sorted keys, so equal permissions hash equally
+	data, err := json.Marshal(struct {
+		Roles   []string    `json:"roles"`
+		Plugins interface{} `json:"plugins"`
+	}{roles, reqCtx.Capabilities.Plugins})
+	if err != nil {
+		return reqCtx.Capabilities.UserID
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,104 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestRequestCoalescing tests that concurrent identical reads share one upstream call and writes do not
+func TestRequestCoalescing(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	const concurrent = 20
+
+	// The backend blocks every call until the current gate is closed
+	var calls atomic.Int32
+	var gate atomic.Pointer[chan struct{}]
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-*gate.Load()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"shared_tool","description":"d"}]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "coalescing-backend", "tool_provider", backend.URL, nil)
+
+	mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+	coalescedWaiters := func() int {
+		mr.coalescer.mutex.Lock()
+		defer mr.coalescer.mutex.Unlock()
+		waiters := 0
+		for _, call := range mr.coalescer.calls {
+			waiters += call.waiters
+		}
+		return waiters
+	}
+
+	// fire sends identical concurrent requests and releases the backend once
+	// ready reports they have all arrived
+	fire := func(t *testing.T, method string, ready func() bool) []*httptest.ResponseRecorder {
+		t.Helper()
+		calls.Store(0)
+		release := make(chan struct{})
+		gate.Store(&release)
+
+		recorders := make([]*httptest.ResponseRecorder, concurrent)
+		var wg sync.WaitGroup
+		for i := range recorders {
+			w := httptest.NewRecorder()
+			req := newJSONRPCRequest(t, method, map[string]interface{}{"name": "shared_tool"})
+			recorders[i] = w
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mr.handleMCPRequest(w, req)
+			}()
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for !ready() {
+			if time.Now().After(deadline) {
+				close(release)
+				t.Fatalf("timed out waiting for %d concurrent %s requests", concurrent, method)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+		wg.Wait()
+		return recorders
+	}
+
+	t.Run("identical reads share one upstream call", func(t *testing.T) {
+		recorders := fire(t, "tools/list", func() bool { return coalescedWaiters() == concurrent })
+		if got := calls.Load(); got != 1 {
+			t.Errorf("expected 1 upstream call, got %d", got)
+		}
+		for _, w := range recorders {
+			var resp map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+			}
+			if _, hasError := resp["error"]; hasError {
+				t.Errorf("expected coalesced read to succeed, got %s", resp["error"])
+			}
+		}
+	})
+
+	t.Run("writes are never coalesced", func(t *testing.T) {
+		fire(t, "tools/call", func() bool { return calls.Load() == concurrent })
+		if got := calls.Load(); got != concurrent {
+			t.Errorf("expected %d upstream calls, got %d", concurrent, got)
+		}
+	})
+}
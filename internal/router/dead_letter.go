@@ -0,0 +1,186 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+const defaultDeadLetterTimeout = 5 * time.Second
+
+// DeadLetterConfig configures where requests that fail every retry attempt
+// are recorded for later inspection or replay. Exactly one of FilePath or
+// Endpoint must be set when enabled.
+type DeadLetterConfig struct {
+	Enabled  bool          `yaml:"enabled" json:"enabled"`
+	FilePath string        `yaml:"file_path" json:"file_path"` // Entries are appended as JSON lines
+	Endpoint string        `yaml:"endpoint" json:"endpoint"`   // Entries are POSTed as JSON
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`     // Endpoint request timeout
+}
+
+// Validate checks that an enabled dead-letter config names exactly one sink
+func (c DeadLetterConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if (c.FilePath == "") == (c.Endpoint == "") {
+		return fmt.Errorf("exactly one of file_path or endpoint must be set")
+	}
+	if c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "http://") && !strings.HasPrefix(c.Endpoint, "https://") {
+		return fmt.Errorf("endpoint must be an http or https URL, got %s", c.Endpoint)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// DeadLetterEntry records a request that failed after exhausting its retries.
+// Params are redacted with the body logging redact paths.
+type DeadLetterEntry struct {
+	Timestamp   time.Time       `json:"timestamp"`
+	RequestID   string          `json:"request_id"`
+	Method      string          `json:"method"`
+	Params      json.RawMessage `json:"params,omitempty"`
+	ServiceID   string          `json:"service_id"`
+	ServiceType string          `json:"service_type"`
+	Attempts    int             `json:"attempts"`
+	Error       string          `json:"error"`
+}
+
+// DeadLetterSink stores dead-letter entries
+type DeadLetterSink interface {
+	Write(entry DeadLetterEntry) error
+}
+
+// newDeadLetterSink returns the sink described by config, or nil when disabled
+func newDeadLetterSink(config DeadLetterConfig) (DeadLetterSink, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.FilePath != "" {
+		return &fileDeadLetterSink{path: config.FilePath}, nil
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = defaultDeadLetterTimeout
+	}
+	return &httpDeadLetterSink{
+		endpoint: config.Endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// fileDeadLetterSink appends entries to a file as JSON lines
+type fileDeadLetterSink struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func (s *fileDeadLetterSink) Write(entry DeadLetterEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead-letter entry: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead-letter entry: %w", err)
+	}
+	return nil
+}
+
+// httpDeadLetterSink POSTs each entry to an endpoint
+type httpDeadLetterSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (s *httpDeadLetterSink) Write(entry DeadLetterEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead-letter entry: %w", err)
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send dead-letter entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("dead-letter endpoint returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SetDeadLetterSink replaces the sink receiving requests that exhaust their retries; nil disables it
+func (mr *MCPRouter) SetDeadLetterSink(sink DeadLetterSink) {
+	mr.deadLetter = sink
+}
+
+// recordDeadLetter writes a request that failed every attempt to the dead-letter sink
+func (mr *MCPRouter) recordDeadLetter(reqCtx *RequestContext, service *registry.RegisteredService, mcpReq *mcpTypes.JSONRPCRequest, attempts int, lastErr error) {
+	if mr.deadLetter == nil {
+		return
+	}
+
+	entry := DeadLetterEntry{
+		Timestamp:   time.Now().UTC(),
+		RequestID:   reqCtx.RequestID,
+		Method:      mcpReq.Method,
+		ServiceID:   service.ID,
+		ServiceType: service.Type,
+		Attempts:    attempts,
+		Error:       lastErr.Error(),
+	}
+
+	// Redact a copy so the request itself is left untouched
+	if raw, err := json.Marshal(mcpReq.Params); err == nil && mcpReq.Params != nil {
+		var params interface{}
+		if err := json.Unmarshal(raw, &params); err == nil {
+			for _, path := range mr.config.BodyLogging.RedactPaths {
+				redactJSONPath(params, strings.Split(path, "."))
+			}
+			entry.Params, _ = json.Marshal(params)
+		}
+	}
+
+	if err := mr.deadLetter.Write(entry); err != nil {
+		mr.metrics.Inc("mcp_dead_letter_write_failures_total", "method", mcpReq.Method)
+		mr.logger.Error("mcp_dead_letter_write_failed",
+			"request_id", reqCtx.RequestID,
+			"method", mcpReq.Method,
+			"error", err)
+		return
+	}
+
+	mr.metrics.Inc("mcp_dead_letters_total", "method", mcpReq.Method, "service_type", service.Type)
+	mr.logger.Warn("mcp_request_dead_lettered",
+		"request_id", reqCtx.RequestID,
+		"method", mcpReq.Method,
+		"service_id", service.ID,
+		"attempts", attempts,
+		"error", lastErr)
+}
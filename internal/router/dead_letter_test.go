@@ -0,0 +1,202 @@
+package router
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestDeadLetter tests that a /mcp request failing every retry is written to the dead-letter file with redacted params
+func TestDeadLetter(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	attempts := 0
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	serviceID := registerTestService(t, serviceRegistry, "failing-backend", "tool_provider", backend.URL, nil)
+
+	path := filepath.Join(t.TempDir(), "dead_letter.jsonl")
+	config := DefaultRouterConfig()
+	config.EnablePluginRouting = false
+	config.RetryEnabled = true
+	config.RetryAttempts = 3
+	config.RetryBackoff = time.Millisecond
+	config.ErrorDetail = ErrorDetailFull
+	config.DeadLetter = DeadLetterConfig{Enabled: true, FilePath: path}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{
+		"name":      "deploy",
+		"arguments": map[string]interface{}{"target": "prod", "api_key": "s3cret"},
+	})
+	req.Header.Set("X-Request-ID", "req-dead-1")
+	// Without an idempotency key a failed tools/call is not repeated
+	req.Header.Set(IdempotencyKeyHeader, "deploy-1")
+	w := httptest.NewRecorder()
+	mr.handleMCPRequest(w, req)
+
+	var resp struct {
+		Error *struct {
+			Data struct {
+				Details string `json:"details"`
+			} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if resp.Error == nil {
+		t.Fatalf("expected request to fail after exhausting retries, got %s", w.Body.String())
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+
+	entries := readDeadLetters(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 dead-letter entry, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.RequestID != "req-dead-1" || entry.Method != "tools/call" {
+		t.Errorf("unexpected request identity %+v", entry)
+	}
+	if entry.ServiceID != serviceID || entry.ServiceType != "tool_provider" {
+		t.Errorf("expected target service %s/tool_provider, got %s/%s", serviceID, entry.ServiceID, entry.ServiceType)
+	}
+	if entry.Attempts != 3 {
+		t.Errorf("expected 3 attempts recorded, got %d", entry.Attempts)
+	}
+	if entry.Error != resp.Error.Data.Details {
+		t.Errorf("expected final error %q, got %q", resp.Error.Data.Details, entry.Error)
+	}
+
+	var recorded struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(entry.Params, &recorded); err != nil {
+		t.Fatalf("failed to decode recorded params: %v", err)
+	}
+	if recorded.Name != "deploy" || recorded.Arguments["target"] != "prod" {
+		t.Errorf("expected params to be preserved, got %s", entry.Params)
+	}
+	if recorded.Arguments["api_key"] != RedactedValue {
+		t.Errorf("expected api_key to be redacted, got %v", recorded.Arguments["api_key"])
+	}
+}
+
+// TestRetrySafety tests that tools/call is only repeated when repeating it
+// cannot run the tool twice
+func TestRetrySafety(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	newRouter := func(t *testing.T, endpoint string) (*MCPRouter, string) {
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		t.Cleanup(func() { serviceRegistry.Shutdown() })
+		registerTestService(t, serviceRegistry, "retried-backend", "tool_provider", endpoint, nil)
+
+		path := filepath.Join(t.TempDir(), "dead_letter.jsonl")
+		config := DefaultRouterConfig()
+		config.EnablePluginRouting = false
+		config.RetryEnabled = true
+		config.RetryAttempts = 3
+		config.RetryBackoff = time.Millisecond
+		config.DeadLetter = DeadLetterConfig{Enabled: true, FilePath: path}
+		return NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config), path
+	}
+	call := func(mr *MCPRouter) {
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "deploy"}))
+		if !strings.Contains(w.Body.String(), `"error"`) {
+			t.Fatalf("expected the call to fail, got %s", w.Body.String())
+		}
+	}
+
+	t.Run("delivered calls are not repeated", func(t *testing.T) {
+		attempts := 0
+		backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadGateway)
+		})
+		mr, path := newRouter(t, backend.URL)
+
+		call(mr)
+		if attempts != 1 {
+			t.Errorf("expected a single attempt, got %d", attempts)
+		}
+		if entries := readDeadLetters(t, path); len(entries) != 1 || entries[0].Attempts != 1 {
+			t.Errorf("expected the failed call to be dead-lettered after one attempt, got %+v", entries)
+		}
+	})
+
+	t.Run("calls that never connected are retried", func(t *testing.T) {
+		backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {})
+		mr, path := newRouter(t, backend.URL)
+		backend.Close()
+
+		call(mr)
+		if entries := readDeadLetters(t, path); len(entries) != 1 || entries[0].Attempts != 3 {
+			t.Errorf("expected all 3 attempts to be made, got %+v", entries)
+		}
+	})
+}
+
+// readDeadLetters decodes the entries of a dead-letter file
+func readDeadLetters(t *testing.T, path string) []DeadLetterEntry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("expected dead-letter file to be written: %v", err)
+	}
+	defer file.Close()
+
+	var entries []DeadLetterEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry DeadLetterEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to decode dead-letter line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestDeadLetterConfigValidate tests that an enabled dead-letter config names exactly one sink
+func TestDeadLetterConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DeadLetterConfig
+		wantErr bool
+	}{
+		{"disabled", DeadLetterConfig{}, false},
+		{"file", DeadLetterConfig{Enabled: true, FilePath: "/tmp/dl.jsonl"}, false},
+		{"endpoint", DeadLetterConfig{Enabled: true, Endpoint: "https://dlq.example.com/mcp"}, false},
+		{"no sink", DeadLetterConfig{Enabled: true}, true},
+		{"both sinks", DeadLetterConfig{Enabled: true, FilePath: "/tmp/dl.jsonl", Endpoint: "https://dlq.example.com"}, true},
+		{"bad endpoint", DeadLetterConfig{Enabled: true, Endpoint: "dlq.example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
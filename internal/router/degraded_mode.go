@@ -0,0 +1,138 @@
+package router
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// StaleResponseHeader marks a response served from the last-known-good cache
+const StaleResponseHeader = "X-MCPEG-Stale"
+
+const defaultDegradedMaxEntries = 1000
+
+// readOnlyMethods are side-effect free methods that may be answered from the
+// last-known-good cache or shared between identical in-flight requests; all
+// other methods fail when backends are down and always execute on their own
+var readOnlyMethods = map[string]bool{
+	"tools/list":     true,
+	"resources/list": true,
+	"resources/read": true,
+	"prompts/list":   true,
+	"prompts/get":    true,
+}
+
+// DegradedModeConfig controls serving last-known-good responses for read
+// methods when no healthy backend can answer
+type DegradedModeConfig struct {
+	Enabled      bool          `yaml:"enabled" json:"enabled"`
+	MaxStaleness time.Duration `yaml:"max_staleness" json:"max_staleness"` // 0 serves entries of any age
+	MaxEntries   int           `yaml:"max_entries" json:"max_entries"`
+}
+
+func defaultDegradedModeConfig() DegradedModeConfig {
+	return DegradedModeConfig{
+		Enabled:      false,
+		MaxStaleness: 5 * time.Minute,
+		MaxEntries:   defaultDegradedMaxEntries,
+	}
+}
+
+type lastKnownGoodEntry struct {
+	result   interface{}
+	storedAt time.Time
+}
+
+// lastKnownGoodCache keeps the most recent successful result per read request
+type lastKnownGoodCache struct {
+	mutex      sync.RWMutex
+	entries    map[string]lastKnownGoodEntry
+	maxEntries int
+}
+
+func newLastKnownGoodCache(maxEntries int) *lastKnownGoodCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultDegradedMaxEntries
+	}
+	return &lastKnownGoodCache{
+		entries:    make(map[string]lastKnownGoodEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *lastKnownGoodCache) store(key string, result interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		// Evict the oldest entry to stay within the bound
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range c.entries {
+			if oldestKey == "" || entry.storedAt.Before(oldest) {
+				oldestKey, oldest = k, entry.storedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+
+	c.entries[key] = lastKnownGoodEntry{result: result, storedAt: time.Now()}
+}
+
+// lookup returns a cached result and its age; entries older than maxStaleness are ignored
+func (c *lastKnownGoodCache) lookup(key string, maxStaleness time.Duration) (interface{}, time.Duration, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	entry, exists := c.entries[key]
+	if !exists {
+		return nil, 0, false
+	}
+
+	age := time.Since(entry.storedAt)
+	if maxStaleness > 0 && age > maxStaleness {
+		return nil, 0, false
+	}
+	return entry.result, age, true
+}
+
+// degradedCacheKey returns the cache key for a request that may be served
+// stale, or false when degraded mode is off or the method is not a read.
+// Like coalescing, it includes the caller's capabilities so a stale result
+// only reaches callers allowed to see the original, and the forwarded headers
+// so it only reaches callers whose backend request would have been the same.
+func (mr *MCPRouter) degradedCacheKey(reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) (string, bool) {
+	if !mr.config.DegradedMode.Enabled || !readOnlyMethods[mcpReq.Method] {
+		return "", false
+	}
+
+	// Maps marshal with sorted keys, so equal params produce equal keys
+	params, err := json.Marshal(mcpReq.Params)
+	if err != nil {
+		return "", false
+	}
+	return mcpReq.Method + "\x00" + string(params) + "\x00" + capabilityHash(reqCtx) + "\x00" + mr.forwardedHeaderKey(reqCtx), true
+}
+
+// serveStale answers a read request from the last-known-good cache
+func (mr *MCPRouter) serveStale(reqCtx *RequestContext, cacheKey, reason string) (interface{}, bool) {
+	result, age, ok := mr.lastKnownGood.lookup(cacheKey, mr.config.DegradedMode.MaxStaleness)
+	if !ok {
+		mr.metrics.Inc("mcp_degraded_cache_misses_total", "method", reqCtx.Method, "reason", reason)
+		return nil, false
+	}
+
+	reqCtx.ServedStale = true
+	reqCtx.StaleAge = age
+
+	mr.metrics.Inc("mcp_degraded_responses_total", "method", reqCtx.Method, "reason", reason)
+	mr.logger.Warn("mcp_serving_stale_response",
+		"request_id", reqCtx.RequestID,
+		"method", reqCtx.Method,
+		"reason", reason,
+		"age", age)
+
+	return result, true
+}
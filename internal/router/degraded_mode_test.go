@@ -0,0 +1,167 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// TestDegradedMode tests that read methods serve stale cached results when backends are down
+func TestDegradedMode(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"cached_tool","description":"d"}]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	serviceID := registerTestService(t, serviceRegistry, "degraded-backend", "tool_provider", backend.URL, nil)
+
+	config := DefaultRouterConfig()
+	config.DegradedMode.Enabled = true
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	send := func(t *testing.T, method string) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, method, map[string]interface{}{"name": "cached_tool"}))
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return w, resp
+	}
+
+	// Prime the last-known-good cache while the backend is healthy
+	w, resp := send(t, "tools/list")
+	if _, hasError := resp["error"]; hasError {
+		t.Fatalf("expected healthy tools/list to succeed, got %s", resp["error"])
+	}
+	if w.Header().Get(StaleResponseHeader) != "" {
+		t.Error("expected fresh response not to be marked stale")
+	}
+	fresh := string(resp["result"])
+
+	// Take the backend down
+	serviceRegistry.GetService(serviceID).Health = registry.HealthUnhealthy
+	backend.Close()
+
+	t.Run("read method serves stale cache when backends are down", func(t *testing.T) {
+		w, resp := send(t, "tools/list")
+		if _, hasError := resp["error"]; hasError {
+			t.Fatalf("expected stale tools/list response, got %s", resp["error"])
+		}
+		if string(resp["result"]) != fresh {
+			t.Errorf("expected last-known-good result %s, got %s", fresh, resp["result"])
+		}
+		if w.Header().Get(StaleResponseHeader) != "true" {
+			t.Errorf("expected %s header on stale response", StaleResponseHeader)
+		}
+		if w.Header().Get("Age") == "" {
+			t.Error("expected Age header on stale response")
+		}
+	})
+
+	t.Run("write method fails when backends are down", func(t *testing.T) {
+		w, resp := send(t, "tools/call")
+		if _, hasError := resp["error"]; !hasError {
+			t.Fatalf("expected tools/call to fail, got %s", resp["result"])
+		}
+		if w.Header().Get(StaleResponseHeader) != "" {
+			t.Error("expected failed write not to be marked stale")
+		}
+	})
+
+	t.Run("entries past max staleness are not served", func(t *testing.T) {
+		mr.config.DegradedMode.MaxStaleness = time.Nanosecond
+		defer func() { mr.config.DegradedMode.MaxStaleness = config.DegradedMode.MaxStaleness }()
+
+		_, resp := send(t, "tools/list")
+		if _, hasError := resp["error"]; !hasError {
+			t.Errorf("expected expired cache entry not to be served, got %s", resp["result"])
+		}
+	})
+}
+
+// TestLastKnownGoodCacheBound tests that the cache evicts the oldest entry when full
+func TestLastKnownGoodCacheBound(t *testing.T) {
+	cache := newLastKnownGoodCache(2)
+	cache.store("a", 1)
+	time.Sleep(time.Millisecond)
+	cache.store("b", 2)
+	cache.store("c", 3)
+
+	if _, _, ok := cache.lookup("a", 0); ok {
+		t.Error("expected oldest entry to be evicted")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, _, ok := cache.lookup(key, 0); !ok {
+			t.Errorf("expected entry %s to be cached", key)
+		}
+	}
+}
+
+// TestDegradedModeCacheEntries tests what may later be served stale: only
+// validated responses, and only to callers with the same capabilities
+func TestDegradedModeCacheEntries(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	config := DefaultRouterConfig()
+	config.DegradedMode.Enabled = true
+
+	t.Run("responses failing validation are not cached", func(t *testing.T) {
+		backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"contents":[{"uri":"file:///a","blob":"not base64!"}]}}`))
+		})
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		serviceID := registerTestService(t, serviceRegistry, "invalid-reads", "resource_provider", backend.URL, nil)
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+		read := func() map[string]json.RawMessage {
+			w := httptest.NewRecorder()
+			mr.handleMCPRequest(w, newJSONRPCRequest(t, "resources/read", map[string]interface{}{"uri": "file:///a"}))
+			var resp map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+			}
+			return resp
+		}
+
+		if _, hasError := read()["error"]; !hasError {
+			t.Fatal("expected the invalid resource read to be rejected")
+		}
+
+		serviceRegistry.GetService(serviceID).Health = registry.HealthUnhealthy
+		backend.Close()
+		if resp := read(); resp["error"] == nil {
+			t.Errorf("expected no stale copy of the rejected response, got %s", resp["result"])
+		}
+	})
+
+	t.Run("cache keys include capabilities", func(t *testing.T) {
+		mr := NewMCPRouterWithConfig(nil, nil, nil, logger, mockMetrics, nil, config)
+		req := &mcpTypes.JSONRPCRequest{Method: "resources/read", Params: map[string]interface{}{"uri": "file:///a"}}
+
+		reader := &RequestContext{Capabilities: &rbac.ProcessedCapabilities{Roles: []string{"readonly"}}}
+		admin := &RequestContext{Capabilities: &rbac.ProcessedCapabilities{Roles: []string{"admin"}}}
+		readerKey, _ := mr.degradedCacheKey(reader, req)
+		adminKey, _ := mr.degradedCacheKey(admin, req)
+		if readerKey == adminKey {
+			t.Error("expected callers with different capabilities to use different cache entries")
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package router
+
+import (
+	"reflect"
+	"strings"
+)
+
+// resultArrayFields lists the array fields each MCP result must carry. Go
+// encodes nil slices as null, which strict clients reject, so these fields are
+// normalized to [] before a result is written.
+var resultArrayFields = map[string][]string{
+	"tools/list":               {"tools"},
+	"tools/call":               {"content"},
+	"resources/list":           {"resources"},
+	"resources/templates/list": {"resourceTemplates"},
+	"resources/read":           {"contents"},
+	"prompts/list":             {"prompts"},
+	"prompts/get":              {"messages"},
+}
+
+// normalizeResultArrays returns the result with null or missing array fields
+// replaced by empty arrays. Results may be shared with the degraded-mode
+// cache or coalesced callers, so a copy is modified rather than the original.
+func normalizeResultArrays(method string, result interface{}) interface{} {
+	fields, ok := resultArrayFields[method]
+	if !ok || result == nil {
+		return result
+	}
+
+	if object, ok := result.(map[string]interface{}); ok {
+		var normalized map[string]interface{}
+		for _, field := range fields {
+			if !isNilSlice(object[field]) {
+				continue
+			}
+			if normalized == nil {
+				normalized = make(map[string]interface{}, len(object)+1)
+				for key, value := range object {
+					normalized[key] = value
+				}
+			}
+			normalized[field] = emptySliceFor(object[field])
+		}
+		if normalized == nil {
+			return result
+		}
+		return normalized
+	}
+
+	// Typed results such as *types.ListToolsResult
+	value := reflect.ValueOf(result)
+	isPointer := value.Kind() == reflect.Ptr
+	if isPointer {
+		if value.IsNil() {
+			return result
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return result
+	}
+
+	normalized := reflect.New(value.Type())
+	normalized.Elem().Set(value)
+	changed := false
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !contains(fields, name) || field.Type.Kind() != reflect.Slice || !value.Field(i).IsNil() {
+			continue
+		}
+		if target := normalized.Elem().Field(i); target.CanSet() {
+			target.Set(reflect.MakeSlice(field.Type, 0, 0))
+			changed = true
+		}
+	}
+
+	switch {
+	case !changed:
+		return result
+	case isPointer:
+		return normalized.Interface()
+	default:
+		return normalized.Elem().Interface()
+	}
+}
+
+// isNilSlice reports whether a value is absent, null or a nil slice
+func isNilSlice(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Slice && v.IsNil()
+}
+
+// emptySliceFor returns an empty slice of the same type as a nil typed slice,
+// or an empty generic array for null values
+func emptySliceFor(value interface{}) interface{} {
+	if value != nil {
+		return reflect.MakeSlice(reflect.TypeOf(value), 0, 0).Interface()
+	}
+	return []interface{}{}
+}
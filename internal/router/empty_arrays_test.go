@@ -0,0 +1,79 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestEmptyArrayNormalization tests that null array fields in results are written as empty arrays
+func TestEmptyArrayNormalization(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":null}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "empty-backend", "tool_provider", backend.URL, nil)
+
+	listTools := func(t *testing.T, config RouterConfig) string {
+		t.Helper()
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "tools/list", nil))
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return string(resp["result"])
+	}
+
+	t.Run("empty tools/list result serializes an empty array", func(t *testing.T) {
+		if result := listTools(t, DefaultRouterConfig()); result != `{"tools":[]}` {
+			t.Errorf(`expected {"tools":[]}, got %s`, result)
+		}
+	})
+
+	t.Run("normalization can be disabled", func(t *testing.T) {
+		config := DefaultRouterConfig()
+		config.NormalizeEmptyArrays = false
+		if result := listTools(t, config); result != `{"tools":null}` {
+			t.Errorf(`expected {"tools":null}, got %s`, result)
+		}
+	})
+
+	t.Run("typed results and missing fields are normalized", func(t *testing.T) {
+		for method, result := range map[string]interface{}{
+			"tools/list":     &types.ListToolsResult{},
+			"resources/list": types.ListResourcesResult{},
+			"resources/read": map[string]interface{}{},
+			"prompts/get":    &types.GetPromptResult{Description: "d"},
+		} {
+			data, err := json.Marshal(normalizeResultArrays(method, result))
+			if err != nil {
+				t.Fatalf("failed to marshal %s result: %v", method, err)
+			}
+			if strings.Contains(string(data), "null") || !strings.Contains(string(data), "[]") {
+				t.Errorf("expected %s result to contain an empty array, got %s", method, data)
+			}
+		}
+	})
+
+	t.Run("original result is not modified", func(t *testing.T) {
+		original := map[string]interface{}{"content": nil}
+		normalizeResultArrays("tools/call", original)
+		if original["content"] != nil {
+			t.Error("expected shared result map to be left unchanged")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestUpstreamErrorCodes tests that backend failures surface distinct JSON-RPC codes and reasons
+func TestUpstreamErrorCodes(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	cases := []struct {
+		mode   string
+		status int
+		code   int
+		reason string
+	}{
+		{"failing", http.StatusServiceUnavailable, errors.CodeUpstreamError, errors.ReasonUpstreamError},
+		{"throttled", http.StatusTooManyRequests, errors.CodeRateLimited, errors.ReasonRateLimited},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.mode, func(t *testing.T) {
+			backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(tc.status)
+			})
+
+			serviceRegistry := newTestRegistry(logger, mockMetrics)
+			defer serviceRegistry.Shutdown()
+			registerTestService(t, serviceRegistry, "backend-"+tc.mode, "tool_provider", backend.URL, nil)
+			mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+			w := httptest.NewRecorder()
+			mr.handleMCPRequest(w, newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "tool"}))
+
+			var resp struct {
+				Error *struct {
+					Code int                    `json:"code"`
+					Data map[string]interface{} `json:"data"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+			}
+			if resp.Error == nil {
+				t.Fatalf("expected an error response, got %s", w.Body.String())
+			}
+			if resp.Error.Code != tc.code || resp.Error.Data["reason"] != tc.reason {
+				t.Errorf("expected %d/%s, got %d/%v", tc.code, tc.reason, resp.Error.Code, resp.Error.Data["reason"])
+			}
+			if resp.Error.Data["retryable"] != true {
+				t.Errorf("expected %s to be retryable, got %v", tc.mode, resp.Error.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"fmt"
+)
+
+// How much of an error is returned to clients in JSON-RPC error data
+const (
+	ErrorDetailFull      = "full"      // The error text is returned in data.details
+	ErrorDetailSanitized = "sanitized" // data.details is replaced by an error_ref logged with the full error
+)
+
+// ValidateErrorDetail checks that mode is a supported error detail mode;
+// empty selects the default
+func ValidateErrorDetail(mode string) error {
+	switch mode {
+	case "", ErrorDetailFull, ErrorDetailSanitized:
+		return nil
+	default:
+		return fmt.Errorf("unsupported error detail mode %q, expected %s or %s",
+			mode, ErrorDetailFull, ErrorDetailSanitized)
+	}
+}
+
+// sanitizeErrorData removes the error text from JSON-RPC error data, keeping
+// the classification fields clients act on, and adds a reference ID under
+// which the full error is logged. It returns the reference.
+func (mr *MCPRouter) sanitizeErrorData(data map[string]interface{}) string {
+	ref := "err_" + GenerateRequestID(RequestIDFormatUUID)
+	delete(data, "details")
+	data["error_ref"] = ref
+
+	mr.metrics.Inc("mcp_error_details_redacted_total", "reason", fmt.Sprint(data["reason"]))
+	return ref
+}
@@ -0,0 +1,82 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestErrorDetail tests that sanitized mode hides error text behind a reference ID
+func TestErrorDetail(t *testing.T) {
+	internalErr := fmt.Errorf("failed to open /var/lib/mcpeg/memory.json: permission denied")
+
+	writeError := func(t *testing.T, mode string) (string, map[string]interface{}) {
+		t.Helper()
+		config := DefaultRouterConfig()
+		config.ErrorDetail = mode
+		mr := NewMCPRouterWithConfig(nil, nil, nil, logging.New("test"), &mockMetrics{}, nil, config)
+
+		recorder := httptest.NewRecorder()
+		reqCtx := &RequestContext{RequestID: "test-request", JSONRPCID: 1, StartTime: time.Now()}
+		mr.writeErrorResponse(recorder, reqCtx, mcpTypes.ErrorCodeInternalError, "Internal error", internalErr)
+
+		var resp struct {
+			Error struct {
+				Message string                 `json:"message"`
+				Data    map[string]interface{} `json:"data"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return recorder.Body.String(), resp.Error.Data
+	}
+
+	t.Run("full", func(t *testing.T) {
+		_, data := writeError(t, ErrorDetailFull)
+		if data["details"] != internalErr.Error() {
+			t.Errorf("expected full error details, got %v", data["details"])
+		}
+		if _, ok := data["error_ref"]; ok {
+			t.Error("expected no error reference in full mode")
+		}
+	})
+
+	t.Run("sanitized", func(t *testing.T) {
+		body, data := writeError(t, ErrorDetailSanitized)
+		ref, _ := data["error_ref"].(string)
+		if !strings.HasPrefix(ref, "err_") {
+			t.Errorf("expected an error reference, got %v", data["error_ref"])
+		}
+		if _, ok := data["details"]; ok {
+			t.Error("expected details to be removed")
+		}
+		if strings.Contains(body, "/var/lib") || strings.Contains(body, "permission denied") {
+			t.Errorf("expected no internal details in response, got %s", body)
+		}
+		if data["reason"] == nil {
+			t.Error("expected the error reason to be kept")
+		}
+
+		_, other := writeError(t, ErrorDetailSanitized)
+		if other["error_ref"] == ref {
+			t.Error("expected each error to get its own reference")
+		}
+	})
+
+	t.Run("unset mode is sanitized", func(t *testing.T) {
+		if _, data := writeError(t, ""); data["error_ref"] == nil || data["details"] != nil {
+			t.Errorf("expected the default mode to hide error details, got %v", data)
+		}
+	})
+
+	if err := ValidateErrorDetail("verbose"); err == nil {
+		t.Error("expected unknown error detail mode to be rejected")
+	}
+}
@@ -0,0 +1,209 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// IdempotencyKeyHeader carries a client-chosen key identifying one logical
+// tool call across retries. It is forwarded to backends so they can enforce
+// exactly-once execution.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayHeader marks a response replayed from an earlier call with
+// the same idempotency key
+const IdempotentReplayHeader = "Idempotent-Replayed"
+
+const (
+	defaultIdempotencyWindow     = 5 * time.Minute
+	defaultIdempotencyMaxEntries = 10000
+)
+
+// idempotentMethods are deduplicated by idempotency key
+var idempotentMethods = map[string]bool{
+	"tools/call": true,
+}
+
+type idempotencyEntry struct {
+	fingerprint string
+	done        chan struct{}
+	result      interface{}
+	err         error
+	completedAt time.Time
+}
+
+// idempotencyCache remembers the outcome of keyed calls for a window so that
+// retries return the first result instead of invoking the tool again
+type idempotencyCache struct {
+	mutex      sync.Mutex
+	entries    map[string]*idempotencyEntry
+	maxEntries int
+}
+
+func newIdempotencyCache(maxEntries int) *idempotencyCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultIdempotencyMaxEntries
+	}
+	return &idempotencyCache{
+		entries:    make(map[string]*idempotencyEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// begin returns the entry for a key and whether the caller owns it and must
+// execute the call. Expired entries are replaced.
+func (c *idempotencyCache) begin(key, fingerprint string, window time.Duration) (*idempotencyEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if entry, exists := c.entries[key]; exists {
+		select {
+		case <-entry.done:
+			if time.Since(entry.completedAt) <= window {
+				return entry, false
+			}
+		default:
+			// Still in flight; the caller waits for its outcome
+			return entry, false
+		}
+	}
+
+	if len(c.entries) >= c.maxEntries {
+		c.evictLocked(window)
+	}
+
+	entry := &idempotencyEntry{fingerprint: fingerprint, done: make(chan struct{})}
+	c.entries[key] = entry
+	return entry, true
+}
+
+// complete records the outcome of an owned call. Failed calls are forgotten
+// so a retry after a failure executes again.
+func (c *idempotencyCache) complete(key string, entry *idempotencyEntry, result interface{}, err error) {
+	c.mutex.Lock()
+	entry.result = result
+	entry.err = err
+	entry.completedAt = time.Now()
+	if err != nil && c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	c.mutex.Unlock()
+
+	close(entry.done)
+}
+
+// evictLocked drops expired entries, or the oldest completed one if none expired
+func (c *idempotencyCache) evictLocked(window time.Duration) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		select {
+		case <-entry.done:
+		default:
+			continue
+		}
+		if time.Since(entry.completedAt) > window {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.completedAt.Before(oldest) {
+			oldestKey, oldest = key, entry.completedAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// idempotencyKeyFromRequest reads the key from the Idempotency-Key header or,
+// failing that, from params._meta.idempotencyKey
+func idempotencyKeyFromRequest(r *http.Request, mcpReq *mcpTypes.JSONRPCRequest) string {
+	if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+		return key
+	}
+
+	params, _ := mcpReq.Params.(map[string]interface{})
+	meta, _ := params["_meta"].(map[string]interface{})
+	key, _ := meta["idempotencyKey"].(string)
+	return key
+}
+
+// routeWithIdempotency routes a request, deduplicating keyed tool calls so a
+// retry with the same key within the window returns the first result
+func (mr *MCPRouter) routeWithIdempotency(ctx context.Context, reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+	if reqCtx.IdempotencyKey == "" || !idempotentMethods[mcpReq.Method] || mr.config.IdempotencyWindow <= 0 {
+		return mr.routeWithCoalescing(ctx, reqCtx, mcpReq)
+	}
+
+	if !IsValidRequestID(reqCtx.IdempotencyKey) {
+		return nil, errors.ValidationError("mcp_router", "idempotency",
+			"idempotency key must be 1-128 printable ASCII characters without spaces", nil)
+	}
+
+	// Keys are scoped per caller so clients cannot observe each other's results
+	cacheKey := reqCtx.ClientID + "\x00" + reqCtx.UserID + "\x00" + mcpReq.Method + "\x00" + reqCtx.IdempotencyKey
+	fingerprint := idempotencyFingerprint(mcpReq.Params)
+
+	entry, owner := mr.idempotency.begin(cacheKey, fingerprint, mr.config.IdempotencyWindow)
+	if owner {
+		result, err := mr.routeJSONRPCRequest(ctx, reqCtx, mcpReq)
+		mr.idempotency.complete(cacheKey, entry, result, err)
+		return result, err
+	}
+
+	if entry.fingerprint != fingerprint {
+		mr.metrics.Inc("mcp_idempotency_conflicts_total", "method", mcpReq.Method)
+		return nil, errors.ValidationError("mcp_router", "idempotency",
+			"idempotency key was already used with different parameters", map[string]interface{}{
+				"idempotency_key": reqCtx.IdempotencyKey,
+			})
+	}
+
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	reqCtx.IdempotentReplay = true
+	mr.metrics.Inc("mcp_idempotent_replays_total", "method", mcpReq.Method)
+	mr.logger.Info("mcp_idempotent_replay",
+		"request_id", reqCtx.RequestID,
+		"method", mcpReq.Method,
+		"idempotency_key", reqCtx.IdempotencyKey)
+
+	return entry.result, entry.err
+}
+
+// idempotencyFingerprint identifies the call parameters, ignoring _meta so the
+// key itself does not make otherwise identical retries differ
+func idempotencyFingerprint(params interface{}) string {
+	if paramsMap, ok := params.(map[string]interface{}); ok {
+		if _, hasMeta := paramsMap["_meta"]; hasMeta {
+			stripped := make(map[string]interface{}, len(paramsMap))
+			for key, value := range paramsMap {
+				if key != "_meta" {
+					stripped[key] = value
+				}
+			}
+			params = stripped
+		}
+	}
+
+	// Maps marshal with sorted keys, so equal params produce equal fingerprints
+	data, _ := json.Marshal(params)
+	return string(data)
+}
+
+// setIdempotencyHeader forwards the caller's idempotency key to a backend
+func setIdempotencyHeader(httpReq *http.Request, reqCtx *RequestContext) {
+	if reqCtx != nil && reqCtx.IdempotencyKey != "" {
+		httpReq.Header.Set(IdempotencyKeyHeader, reqCtx.IdempotencyKey)
+	}
+}
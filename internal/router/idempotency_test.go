@@ -0,0 +1,90 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestToolCallIdempotency tests that tool calls sharing an idempotency key invoke the backend once
+func TestToolCallIdempotency(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	var invocations atomic.Int32
+	var forwardedKey atomic.Value
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		n := invocations.Add(1)
+		forwardedKey.Store(r.Header.Get(IdempotencyKeyHeader))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"write #%d"}]}}`, n)
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "writer", "tool_provider", backend.URL, nil)
+
+	config := DefaultRouterConfig()
+	config.EnablePluginRouting = false
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	call := func(t *testing.T, key string, args map[string]interface{}) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+		t.Helper()
+		req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "write", "arguments": args})
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, req)
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return w, resp
+	}
+
+	t.Run("retry with the same key returns the first result", func(t *testing.T) {
+		args := map[string]interface{}{"value": "a"}
+		_, first := call(t, "write-1", args)
+		w, second := call(t, "write-1", args)
+
+		if invocations.Load() != 1 {
+			t.Errorf("expected backend to be invoked once, got %d", invocations.Load())
+		}
+		if string(first["result"]) != string(second["result"]) {
+			t.Errorf("expected identical results, got %s and %s", first["result"], second["result"])
+		}
+		if w.Header().Get(IdempotentReplayHeader) != "true" {
+			t.Errorf("expected %s header on the replayed response", IdempotentReplayHeader)
+		}
+		if forwardedKey.Load() != "write-1" {
+			t.Errorf("expected idempotency key to be forwarded to the backend, got %v", forwardedKey.Load())
+		}
+	})
+
+	t.Run("reusing a key with different parameters is rejected", func(t *testing.T) {
+		before := invocations.Load()
+		_, resp := call(t, "write-1", map[string]interface{}{"value": "b"})
+		if _, hasError := resp["error"]; !hasError {
+			t.Errorf("expected conflicting reuse to fail, got %s", resp["result"])
+		}
+		if invocations.Load() != before {
+			t.Error("expected conflicting reuse not to invoke the backend")
+		}
+	})
+
+	t.Run("calls without a key are not deduplicated", func(t *testing.T) {
+		before := invocations.Load()
+		call(t, "", map[string]interface{}{"value": "c"})
+		call(t, "", map[string]interface{}{"value": "c"})
+		if got := invocations.Load() - before; got != 2 {
+			t.Errorf("expected 2 backend invocations, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/errors"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// SupportedProtocolVersions lists the MCP protocol versions the gateway
+// accepts during the initialize handshake, newest first
+var SupportedProtocolVersions = []string{types.ProtocolVersion, "2024-11-05"}
+
+// resourceSubscribeMetadataKey lets a backend declare resources/subscribe
+// support in its registration metadata
+const resourceSubscribeMetadataKey = "resources_subscribe"
+
+// handleInitialize answers the initialize handshake locally with the gateway's
+// server info and the union of capabilities across backends and plugins
+func (mr *MCPRouter) handleInitialize(reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+	var params types.InitializeParams
+	if mcpReq.Params != nil {
+		raw, err := json.Marshal(mcpReq.Params)
+		if err == nil {
+			err = json.Unmarshal(raw, &params)
+		}
+		if err != nil {
+			return nil, errors.ValidationError("mcp_router", "initialize",
+				fmt.Sprintf("invalid initialize params: %v", err), nil)
+		}
+	}
+
+	version, err := negotiateProtocolVersion(params.ProtocolVersion)
+	if err != nil {
+		mr.metrics.Inc("mcp_initialize_rejected_total", "requested_version", params.ProtocolVersion)
+		return nil, err
+	}
+
+	result := &types.InitializeResult{
+		ProtocolVersion: version,
+		Capabilities:    mr.aggregateCapabilities(reqCtx),
+		ServerInfo: types.ServerInfo{
+			Name:    mr.config.ServerName,
+			Version: mr.config.ServerVersion,
+		},
+	}
+
+	mr.logger.Info("mcp_initialize_completed",
+		"request_id", reqCtx.RequestID,
+		"client_name", params.ClientInfo.Name,
+		"client_version", params.ClientInfo.Version,
+		"requested_version", params.ProtocolVersion,
+		"protocol_version", version)
+
+	return result, nil
+}
+
+// negotiateProtocolVersion returns the version to speak with a client, or an
+// error naming the supported versions when the client's is not one of them
+func negotiateProtocolVersion(requested string) (string, error) {
+	if requested == "" {
+		return "", errors.ValidationError("mcp_router", "initialize",
+			fmt.Sprintf("protocolVersion is required; supported versions: %s",
+				strings.Join(SupportedProtocolVersions, ", ")), nil)
+	}
+
+	for _, version := range SupportedProtocolVersions {
+		if version == requested {
+			return version, nil
+		}
+	}
+
+	return "", errors.ValidationError("mcp_router", "initialize",
+		fmt.Sprintf("unsupported protocol version %q; supported versions: %s",
+			requested, strings.Join(SupportedProtocolVersions, ", ")),
+		map[string]interface{}{
+			"requested": requested,
+			"supported": SupportedProtocolVersions,
+		})
+}
+
+// aggregateCapabilities merges the capabilities of every registered backend
+// and every plugin available to the caller
+func (mr *MCPRouter) aggregateCapabilities(reqCtx *RequestContext) types.ServerCapabilities {
+	var caps types.ServerCapabilities
+
+	for _, service := range mr.registry.GetAllServices() {
+		if service.Status == registry.StatusUnavailable {
+			continue
+		}
+
+		if len(service.Tools) > 0 || service.Type == mr.determineServiceType("tools/list") {
+			caps.Tools = &types.ToolsCapability{}
+		}
+		if len(service.Resources) > 0 || service.Type == mr.determineServiceType("resources/list") {
+			if caps.Resources == nil {
+				caps.Resources = &types.ResourcesCapability{}
+			}
+			if subscribe, _ := service.Metadata[resourceSubscribeMetadataKey].(bool); subscribe {
+				caps.Resources.Subscribe = true
+			}
+		}
+		if len(service.Prompts) > 0 || service.Type == mr.determineServiceType("prompts/list") {
+			caps.Prompts = &types.PromptsCapability{}
+		}
+	}
+
+	if mr.config.EnablePluginRouting && mr.pluginHandler != nil {
+		for _, pluginName := range mr.pluginHandler.ListAvailablePlugins(reqCtx.Capabilities) {
+			if tools, err := mr.pluginHandler.GetPluginTools(pluginName, reqCtx.Capabilities); err == nil && len(tools) > 0 {
+				caps.Tools = &types.ToolsCapability{}
+			}
+			if resources, err := mr.pluginHandler.GetPluginResources(pluginName, reqCtx.Capabilities); err == nil && len(resources) > 0 {
+				if caps.Resources == nil {
+					caps.Resources = &types.ResourcesCapability{}
+				}
+				if _, ok := mr.pluginHandler.(pluginResourceWatcher); ok {
+					caps.Resources.Subscribe = true
+				}
+			}
+			if prompts, err := mr.pluginHandler.GetPluginPrompts(pluginName, reqCtx.Capabilities); err == nil && len(prompts) > 0 {
+				caps.Prompts = &types.PromptsCapability{}
+			}
+		}
+	}
+
+	return caps
+}
@@ -0,0 +1,122 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// TestInitializeHandshake tests that initialize is answered locally with aggregated capabilities
+func TestInitializeHandshake(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "tools-backend", "tool_provider", "plugin://tools", nil)
+	registerTestService(t, serviceRegistry, "files-backend", "resource_provider", "plugin://files",
+		map[string]interface{}{"resources_subscribe": true})
+
+	config := DefaultRouterConfig()
+	config.ServerVersion = "1.2.3"
+	config.ErrorDetail = ErrorDetailFull
+	handler := &fakePromptPluginHandler{plugin: "prompts", prompts: []string{"summarize"}}
+	mr := NewMCPRouterWithConfig(serviceRegistry, handler, nil, logger, mockMetrics, nil, config)
+
+	initialize := func(t *testing.T, version string) (*types.InitializeResult, *types.Error) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "initialize", map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{},
+			"clientInfo":      map[string]interface{}{"name": "test-client", "version": "0.1.0"},
+		}))
+
+		var resp struct {
+			Result *types.InitializeResult `json:"result"`
+			Error  *types.Error            `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return resp.Result, resp.Error
+	}
+
+	t.Run("capabilities reflect backends and plugins", func(t *testing.T) {
+		result, rpcErr := initialize(t, types.ProtocolVersion)
+		if rpcErr != nil {
+			t.Fatalf("expected initialize to succeed, got %+v", rpcErr)
+		}
+		if result.ServerInfo.Name != "mcpeg" || result.ServerInfo.Version != "1.2.3" {
+			t.Errorf("expected gateway server info, got %+v", result.ServerInfo)
+		}
+
+		caps := result.Capabilities
+		if caps.Tools == nil {
+			t.Error("expected tools capability from the tool backend")
+		}
+		if caps.Resources == nil || !caps.Resources.Subscribe {
+			t.Errorf("expected resources capability with subscribe support, got %+v", caps.Resources)
+		}
+		if caps.Prompts == nil {
+			t.Error("expected prompts capability from the prompt plugin")
+		}
+	})
+
+	t.Run("supported older version is negotiated", func(t *testing.T) {
+		result, rpcErr := initialize(t, "2024-11-05")
+		if rpcErr != nil {
+			t.Fatalf("expected initialize to succeed, got %+v", rpcErr)
+		}
+		if result.ProtocolVersion != "2024-11-05" {
+			t.Errorf("expected negotiated version 2024-11-05, got %s", result.ProtocolVersion)
+		}
+	})
+
+	t.Run("unsupported version is rejected", func(t *testing.T) {
+		_, rpcErr := initialize(t, "1999-01-01")
+		if rpcErr == nil {
+			t.Fatal("expected unsupported protocol version to be rejected")
+		}
+		if rpcErr.Code != types.ErrorCodeInvalidParams {
+			t.Errorf("expected invalid params error, got %d", rpcErr.Code)
+		}
+		data, _ := rpcErr.Data.(map[string]interface{})
+		if details, _ := data["details"].(string); !strings.Contains(details, types.ProtocolVersion) {
+			t.Errorf("expected error to list supported versions, got %v", rpcErr.Data)
+		}
+	})
+}
+
+// fakePromptPluginHandler exposes a single plugin that only provides prompts
+type fakePromptPluginHandler struct {
+	mcpTypes.PluginHandler
+	plugin  string
+	prompts []string
+}
+
+func (f *fakePromptPluginHandler) ListAvailablePlugins(capabilities *rbac.ProcessedCapabilities) []string {
+	return []string{f.plugin}
+}
+
+func (f *fakePromptPluginHandler) GetPluginTools(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]mcpTypes.Tool, error) {
+	return nil, nil
+}
+
+func (f *fakePromptPluginHandler) GetPluginResources(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]mcpTypes.Resource, error) {
+	return nil, nil
+}
+
+func (f *fakePromptPluginHandler) GetPluginPrompts(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]mcpTypes.Prompt, error) {
+	var prompts []mcpTypes.Prompt
+	for _, name := range f.prompts {
+		prompts = append(prompts, mcpTypes.Prompt{Name: name})
+	}
+	return prompts, nil
+}
@@ -0,0 +1,109 @@
+package router
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// maxResponseSnippet bounds the excerpt of an invalid backend response kept
+// for diagnosis
+const maxResponseSnippet = 256
+
+// snippetReader passes a backend response through while keeping its first
+// bytes, so a body that fails to decode can be quoted without buffering it
+type snippetReader struct {
+	reader io.Reader
+	head   []byte
+}
+
+func newSnippetReader(body io.Reader) *snippetReader {
+	return &snippetReader{reader: body}
+}
+
+func (s *snippetReader) Read(p []byte) (int, error) {
+	n, err := s.reader.Read(p)
+	// Keep one byte past the snippet to tell whether the body was cut short
+	if room := maxResponseSnippet + 1 - len(s.head); room > 0 && n > 0 {
+		s.head = append(s.head, p[:min(n, room)]...)
+	}
+	return n, err
+}
+
+// fill reads on to the snippet length, as the decoder may fail before then
+func (s *snippetReader) fill() {
+	if room := maxResponseSnippet + 1 - len(s.head); room > 0 {
+		rest, _ := io.ReadAll(io.LimitReader(s.reader, int64(room)))
+		s.head = append(s.head, rest...)
+	}
+}
+
+// snippet returns the start of the body on one line, marked when truncated
+func (s *snippetReader) snippet() string {
+	head, truncated := s.head, false
+	if len(head) > maxResponseSnippet {
+		head, truncated = head[:maxResponseSnippet], true
+	}
+	// Drop a rune cut in half by the limit
+	for i := len(head) - 1; truncated && i >= 0 && i >= len(head)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(head[i]) {
+			if !utf8.FullRune(head[i:]) {
+				head = head[:i]
+			}
+			break
+		}
+	}
+
+	snippet := strings.Join(strings.Fields(strings.ToValidUTF8(string(head), "�")), " ")
+	if truncated {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// isJSONContentType reports whether a Content-Type names JSON, including
+// structured syntax types such as application/vnd.api+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// invalidResponse records a backend response that could not be decoded as
+// JSON-RPC and returns the error to report, quoting the start of the body
+func (mr *MCPRouter) invalidResponse(reqCtx *RequestContext, service *registry.RegisteredService, method string, resp *http.Response, body *snippetReader, cause error) error {
+	contentType := resp.Header.Get("Content-Type")
+	body.fill()
+	snippet := body.snippet()
+
+	// A JSON content type means the backend meant to send JSON-RPC and
+	// failed; anything else is usually a page from a proxy or the wrong endpoint
+	kind := "non_json"
+	if isJSONContentType(contentType) {
+		kind = "malformed_json"
+	}
+
+	mr.metrics.Inc("mcp_upstream_invalid_responses_total", "method", method, "service_id", service.ID, "kind", kind)
+
+	requestID := ""
+	if reqCtx != nil {
+		requestID = reqCtx.RequestID
+	}
+	mr.logger.Warn("upstream_response_invalid",
+		"request_id", requestID,
+		"service_id", service.ID,
+		"method", method,
+		"kind", kind,
+		"content_type", contentType,
+		"body_snippet", snippet,
+		"error", cause)
+
+	return errors.InvalidResponseError(service.ID, method, contentType, snippet, cause)
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestInvalidBackendResponses tests that non-JSON and malformed backend
+// responses are reported with their content type and the start of the body
+func TestInvalidBackendResponses(t *testing.T) {
+	logger := logging.New("test")
+	recordingMetrics := &cancellationRecordingMetrics{}
+
+	htmlPage := "<html>\n  <head><title>502 Bad Gateway</title></head>\n  <body>" + strings.Repeat("nginx ", 100) + "</body>\n</html>"
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("mode") {
+		case "html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(htmlPage))
+		case "truncated":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"sea`))
+		}
+	})
+
+	serviceRegistry := newTestRegistry(logger, recordingMetrics)
+	defer serviceRegistry.Shutdown()
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, recordingMetrics, nil, DefaultRouterConfig())
+	htmlService := serviceRegistry.GetService(registerTestService(t, serviceRegistry, "html-backend", "tool_provider", backend.URL+"/?mode=html", nil))
+	truncatedService := serviceRegistry.GetService(registerTestService(t, serviceRegistry, "truncated-backend", "tool_provider", backend.URL+"/?mode=truncated", nil))
+
+	invalidResponse := func(t *testing.T, err error) *errors.MCPError {
+		t.Helper()
+		var mcpErr *errors.MCPError
+		if !stderrors.As(err, &mcpErr) || mcpErr.Category != errors.CategoryInvalidResponse {
+			t.Fatalf("expected an invalid response error, got %v", err)
+		}
+		if mapping := errors.ToJSONRPC(err); mapping.Reason != errors.ReasonInvalidResponse {
+			t.Errorf("expected reason %s, got %s", errors.ReasonInvalidResponse, mapping.Reason)
+		}
+		return mcpErr
+	}
+
+	t.Run("html page", func(t *testing.T) {
+		_, err := mr.forwardToService(context.Background(), &RequestContext{RequestID: "req-html"}, htmlService, &mcpTypes.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "tools/list",
+		})
+		mcpErr := invalidResponse(t, err)
+
+		if mcpErr.Context["content_type"] != "text/html; charset=utf-8" {
+			t.Errorf("expected the HTML content type, got %v", mcpErr.Context["content_type"])
+		}
+		snippet, _ := mcpErr.Context["body_snippet"].(string)
+		if !strings.HasPrefix(snippet, "<html> <head><title>502 Bad Gateway</title></head>") {
+			t.Errorf("expected the start of the page on one line, got %q", snippet)
+		}
+		if !strings.HasSuffix(snippet, "...") || len(snippet) > maxResponseSnippet+3 {
+			t.Errorf("expected a truncated snippet, got %d bytes", len(snippet))
+		}
+		if !strings.Contains(err.Error(), "text/html") || !strings.Contains(err.Error(), "502 Bad Gateway") {
+			t.Errorf("expected the content type and body in the message, got %q", err.Error())
+		}
+	})
+
+	t.Run("truncated json", func(t *testing.T) {
+		_, err := mr.forwardToService(context.Background(), &RequestContext{RequestID: "req-truncated"}, truncatedService, &mcpTypes.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "tools/list",
+		})
+		mcpErr := invalidResponse(t, err)
+
+		if snippet := mcpErr.Context["body_snippet"]; snippet != `{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"sea` {
+			t.Errorf("expected the whole short body, got %q", snippet)
+		}
+		if !strings.Contains(mcpErr.Cause.Error(), "unexpected EOF") {
+			t.Errorf("expected the parse error as cause, got %v", mcpErr.Cause)
+		}
+	})
+
+	if got := recordingMetrics.count("mcp_upstream_invalid_responses_total"); got != 2 {
+		t.Errorf("expected 2 invalid response metrics, got %d", got)
+	}
+}
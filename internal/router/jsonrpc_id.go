@@ -0,0 +1,36 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// extractJSONRPCID reads the id member of a JSON-RPC request body so error
+// responses can echo it even when the rest of the request is malformed. The id
+// is kept as raw JSON so large numbers and strings round-trip byte for byte.
+// present is false when the body has no id member, which marks a notification.
+func extractJSONRPCID(body []byte) (id json.RawMessage, present bool, err error) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		// Not a JSON object, so no id can be recovered
+		return nil, false, nil
+	}
+
+	rawID, present := envelope["id"]
+	if !present {
+		return nil, false, nil
+	}
+
+	rawID = bytes.TrimSpace(rawID)
+	if len(rawID) == 0 {
+		return nil, true, fmt.Errorf("invalid id: empty value")
+	}
+
+	switch c := rawID[0]; {
+	case c == '"', c == '-', c >= '0' && c <= '9', bytes.Equal(rawID, []byte("null")):
+		return rawID, true, nil
+	default:
+		return nil, true, fmt.Errorf("invalid id: must be a string, number or null")
+	}
+}
@@ -0,0 +1,111 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestJSONRPCIDHandling tests that ids are echoed on errors and notifications get no response
+func TestJSONRPCIDHandling(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "jsonrpc-id-backend", "tool_provider", backend.URL, nil)
+
+	mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/mcp", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, req)
+		return w
+	}
+
+	// rawID returns the id member of a response exactly as it was encoded
+	rawID := func(t *testing.T, w *httptest.ResponseRecorder) string {
+		t.Helper()
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		if _, hasError := resp["error"]; !hasError {
+			t.Fatalf("expected error response, got %s", w.Body.String())
+		}
+		return string(resp["id"])
+	}
+
+	t.Run("error response preserves numeric id", func(t *testing.T) {
+		w := send(`{"jsonrpc":"1.0","id":42,"method":"tools/list"}`)
+		if id := rawID(t, w); id != "42" {
+			t.Errorf("expected id 42, got %s", id)
+		}
+	})
+
+	t.Run("large numeric id round-trips exactly", func(t *testing.T) {
+		w := send(`{"jsonrpc":"2.0","id":9007199254740993}`)
+		if id := rawID(t, w); id != "9007199254740993" {
+			t.Errorf("expected id 9007199254740993, got %s", id)
+		}
+	})
+
+	t.Run("error response preserves string id", func(t *testing.T) {
+		w := send(`{"jsonrpc":"2.0","id":"req-abc","method":42}`)
+		if id := rawID(t, w); id != `"req-abc"` {
+			t.Errorf("expected id \"req-abc\", got %s", id)
+		}
+	})
+
+	t.Run("null id is answered with null", func(t *testing.T) {
+		w := send(`{"jsonrpc":"2.0","id":null,"method":"unknown/method"}`)
+		if id := rawID(t, w); id != "null" {
+			t.Errorf("expected null id, got %s", id)
+		}
+	})
+
+	t.Run("unparseable body and invalid id types get null id", func(t *testing.T) {
+		for _, body := range []string{`{"jsonrpc":`, `{"jsonrpc":"2.0","id":{"a":1},"method":"tools/list"}`} {
+			w := send(body)
+			if id := rawID(t, w); id != "null" {
+				t.Errorf("expected null id for %s, got %s", body, id)
+			}
+		}
+	})
+
+	t.Run("notification produces no response", func(t *testing.T) {
+		w := send(`{"jsonrpc":"2.0","method":"tools/list"}`)
+		if w.Code != http.StatusAccepted || w.Body.Len() != 0 {
+			t.Errorf("expected empty 202 for notification, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("failed notification produces no response", func(t *testing.T) {
+		w := send(`{"jsonrpc":"2.0","method":"unknown/method"}`)
+		if w.Code != http.StatusAccepted || w.Body.Len() != 0 {
+			t.Errorf("expected empty 202 for failed notification, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("successful response echoes string id", func(t *testing.T) {
+		w := send(`{"jsonrpc":"2.0","id":"call-7","method":"tools/list"}`)
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if string(resp["id"]) != `"call-7"` {
+			t.Errorf("expected id \"call-7\", got %s (%s)", resp["id"], w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// jsonrpcVersion is the only protocol version the gateway speaks
+const jsonrpcVersion = "2.0"
+
+// checkJSONRPCVersion rejects requests not declaring JSON-RPC 2.0. With
+// LenientJSONRPCVersion a request without a jsonrpc member is taken as 2.0,
+// while one declaring any other version, empty or null included, is still
+// rejected.
+func (mr *MCPRouter) checkJSONRPCVersion(reqCtx *RequestContext, body []byte, mcpReq *mcpTypes.JSONRPCRequest) error {
+	if mcpReq.JSONRPC == jsonrpcVersion {
+		return nil
+	}
+	if mcpReq.JSONRPC == "" && mr.config.LenientJSONRPCVersion && !hasJSONRPCMember(body) {
+		mcpReq.JSONRPC = jsonrpcVersion
+		mr.metrics.Inc("mcp_jsonrpc_version_defaulted_total")
+		mr.logger.Debug("jsonrpc_version_defaulted",
+			"request_id", reqCtx.RequestID,
+			"method", mcpReq.Method)
+		return nil
+	}
+	return fmt.Errorf("invalid JSON-RPC version: %s", mcpReq.JSONRPC)
+}
+
+// hasJSONRPCMember reports whether a request object has a jsonrpc member
+func hasJSONRPCMember(body []byte) bool {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return false
+	}
+	_, present := envelope["jsonrpc"]
+	return present
+}
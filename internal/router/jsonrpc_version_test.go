@@ -0,0 +1,72 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestJSONRPCVersionLeniency tests that requests without a jsonrpc member
+// are accepted only in lenient mode, and that wrong versions never are
+func TestJSONRPCVersionLeniency(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+
+	strict := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, DefaultRouterConfig())
+	config := DefaultRouterConfig()
+	config.LenientJSONRPCVersion = true
+	lenient := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	call := func(t *testing.T, mr *MCPRouter, body string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mr.handleMCPRequest(rec, req)
+
+		var resp map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	missing := `{"id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`
+
+	t.Run("missing version is rejected in strict mode", func(t *testing.T) {
+		resp := call(t, strict, missing)
+		rpcErr, _ := resp["error"].(map[string]interface{})
+		if rpcErr == nil || rpcErr["code"] != float64(-32700) {
+			t.Errorf("expected a parse error, got %v", resp)
+		}
+	})
+
+	t.Run("missing version is accepted in lenient mode", func(t *testing.T) {
+		resp := call(t, lenient, missing)
+		if resp["error"] != nil || resp["result"] == nil {
+			t.Fatalf("expected a result, got %v", resp)
+		}
+		if resp["jsonrpc"] != "2.0" {
+			t.Errorf("expected a 2.0 response, got %v", resp["jsonrpc"])
+		}
+	})
+
+	for name, body := range map[string]string{
+		"wrong version": `{"jsonrpc":"1.0","id":1,"method":"initialize"}`,
+		"empty version": `{"jsonrpc":"","id":1,"method":"initialize"}`,
+		"null version":  `{"jsonrpc":null,"id":1,"method":"initialize"}`,
+	} {
+		t.Run(name+" is rejected in lenient mode", func(t *testing.T) {
+			resp := call(t, lenient, body)
+			if resp["error"] == nil {
+				t.Errorf("expected an error, got %v", resp)
+			}
+		})
+	}
+}
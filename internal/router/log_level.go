@@ -0,0 +1,100 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/errors"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// How logging/setLevel is handled
+const (
+	LogLevelModeForward = "forward" // Forward to logging_provider services only
+	LogLevelModeLocal   = "local"   // Adjust the gateway logger only
+	LogLevelModeBoth    = "both"    // Adjust the gateway logger, then forward to any logging_provider
+)
+
+// mcpLogLevels maps the RFC 5424 levels defined by MCP onto gateway log levels
+var mcpLogLevels = map[string]string{
+	"debug":     "debug",
+	"info":      "info",
+	"notice":    "info",
+	"warning":   "warn",
+	"error":     "error",
+	"critical":  "error",
+	"alert":     "error",
+	"emergency": "error",
+}
+
+// ValidateLogLevelMode checks that mode is a supported logging/setLevel mode;
+// empty selects the default
+func ValidateLogLevelMode(mode string) error {
+	switch mode {
+	case "", LogLevelModeForward, LogLevelModeLocal, LogLevelModeBoth:
+		return nil
+	default:
+		return fmt.Errorf("unsupported log level mode %q, expected %s, %s or %s",
+			mode, LogLevelModeForward, LogLevelModeLocal, LogLevelModeBoth)
+	}
+}
+
+// SetLevelController sets the logger whose level logging/setLevel adjusts
+func (mr *MCPRouter) SetLevelController(controller logging.LevelController) {
+	mr.levelController = controller
+}
+
+// handleSetLevel applies logging/setLevel to the gateway logger and, in both
+// mode, forwards it to any registered logging_provider services
+func (mr *MCPRouter) handleSetLevel(ctx context.Context, reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+	var params types.LoggingSetLevelParams
+	if mcpReq.Params != nil {
+		raw, err := json.Marshal(mcpReq.Params)
+		if err == nil {
+			err = json.Unmarshal(raw, &params)
+		}
+		if err != nil {
+			return nil, errors.ValidationError("mcp_router", "logging_set_level",
+				fmt.Sprintf("invalid logging/setLevel params: %v", err), nil)
+		}
+	}
+
+	gatewayLevel, ok := mcpLogLevels[params.Level]
+	if !ok {
+		mr.metrics.Inc("mcp_log_level_rejected_total", "level", params.Level)
+		return nil, errors.ValidationError("mcp_router", "logging_set_level",
+			fmt.Sprintf("unsupported log level %q", params.Level),
+			map[string]interface{}{"level": params.Level})
+	}
+
+	if mr.levelController == nil {
+		return nil, fmt.Errorf("gateway logger does not support runtime level changes")
+	}
+
+	previous := mr.levelController.GetLevel()
+	mr.levelController.SetLevel(gatewayLevel)
+
+	mr.metrics.Inc("mcp_log_level_changes_total", "level", params.Level)
+	mr.logger.Warn("gateway_log_level_changed",
+		"request_id", reqCtx.RequestID,
+		"client_id", reqCtx.ClientID,
+		"mcp_level", params.Level,
+		"previous_level", strings.ToLower(previous),
+		"level", gatewayLevel)
+
+	if mr.config.LogLevelMode == LogLevelModeBoth {
+		serviceType := mr.determineServiceType(mcpReq.Method)
+		if service, err := mr.registry.SelectService(serviceType, mr.selectionCriteria(reqCtx)); err == nil {
+			reqCtx.ServiceID = service.ID
+			if _, err := mr.forwardToService(ctx, reqCtx, service, mcpReq); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return map[string]interface{}{}, nil
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestLoggingSetLevel tests that logging/setLevel adjusts the gateway logger and rejects unknown levels
+func TestLoggingSetLevel(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	controller := logger.(logging.LevelController)
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+
+	setLevel := func(t *testing.T, mr *MCPRouter, level string) map[string]json.RawMessage {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "logging/setLevel", map[string]interface{}{"level": level}))
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return resp
+	}
+
+	t.Run("setLevel changes the effective gateway log level", func(t *testing.T) {
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, DefaultRouterConfig())
+		defer controller.SetLevel("trace")
+
+		for level, expected := range map[string]string{"warning": "warn", "debug": "debug", "critical": "error"} {
+			resp := setLevel(t, mr, level)
+			if _, hasError := resp["error"]; hasError {
+				t.Fatalf("expected setLevel %s to succeed, got %s", level, resp["error"])
+			}
+			if got := controller.GetLevel(); got != expected {
+				t.Errorf("expected setLevel %s to set gateway level %s, got %s", level, expected, got)
+			}
+		}
+	})
+
+	t.Run("invalid level is rejected with invalid params", func(t *testing.T) {
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, DefaultRouterConfig())
+		controller.SetLevel("info")
+		defer controller.SetLevel("trace")
+
+		resp := setLevel(t, mr, "verbose")
+		var rpcErr mcpTypes.JSONRPCError
+		if err := json.Unmarshal(resp["error"], &rpcErr); err != nil {
+			t.Fatalf("expected error response, got %v", resp)
+		}
+		if rpcErr.Code != mcpTypes.ErrorCodeInvalidParams {
+			t.Errorf("expected invalid params code %d, got %d", mcpTypes.ErrorCodeInvalidParams, rpcErr.Code)
+		}
+		if got := controller.GetLevel(); got != "info" {
+			t.Errorf("expected level to remain info, got %s", got)
+		}
+	})
+
+	t.Run("forward mode leaves the gateway logger alone", func(t *testing.T) {
+		var forwarded bool
+		backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			forwarded = true
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+		})
+		registerTestService(t, serviceRegistry, "log-backend", "logging_provider", backend.URL, nil)
+
+		config := DefaultRouterConfig()
+		config.LogLevelMode = LogLevelModeForward
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+		controller.SetLevel("info")
+		defer controller.SetLevel("trace")
+
+		resp := setLevel(t, mr, "error")
+		if _, hasError := resp["error"]; hasError {
+			t.Fatalf("expected forwarded setLevel to succeed, got %s", resp["error"])
+		}
+		if !forwarded {
+			t.Error("expected setLevel to be forwarded to the logging provider")
+		}
+		if got := controller.GetLevel(); got != "info" {
+			t.Errorf("expected gateway level to remain info, got %s", got)
+		}
+	})
+}
@@ -2,10 +2,19 @@ package router
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -28,6 +37,49 @@ type MCPRouter struct {
 	metrics       metrics.Metrics
 	validator     *validation.Validator
 	config        RouterConfig
+
+	// Gateway-wide tool/resource policy, swapped atomically on reload
+	capabilityPolicy atomic.Pointer[CapabilityPolicyConfig]
+
+	// Last successful read results served when backends are down
+	lastKnownGood *lastKnownGoodCache
+
+	// Outcomes of recent tool calls keyed by idempotency key
+	idempotency *idempotencyCache
+
+	// Upstream calls shared by identical in-flight read requests
+	coalescer *requestCoalescer
+
+	// Record of requests that failed every retry attempt
+	deadLetter DeadLetterSink
+
+	// Record of tool calls with hashed arguments and results
+	toolAudit *toolAuditWriter
+
+	// Gateway logger adjusted by logging/setLevel
+	levelController logging.LevelController
+
+	// Most recent requests for the admin API; nil when disabled
+	history *requestHistory
+
+	// Watched plugin resources and where their change notifications go
+	subscriptions *resourceSubscriptions
+	notify        NotificationPublisher
+
+	// Roots last listed by backends for roots/list aggregation
+	roots *rootsCache
+
+	// Transports for services with their own TLS policy
+	transports *upstreamTransports
+
+	// Call counts behind ToolRateLimits
+	toolRateLimiter *toolRateLimiter
+
+	// Retries allowed across all backends; nil when unlimited
+	retryBudget *retryBudget
+
+	// Cached plugin tool input schemas for argument validation
+	toolSchemas ToolSchemaLookup
 }
 
 // RouterConfig configures the MCP router
@@ -35,8 +87,16 @@ type RouterConfig struct {
 	// Request routing
 	DefaultTimeout      time.Duration `yaml:"default_timeout"`
 	MaxRequestSize      int64         `yaml:"max_request_size"`
+	MaxResponseSize     int64         `yaml:"max_response_size"` // Backend response cap; 0 disables the limit
 	EnableMethodRouting bool          `yaml:"enable_method_routing"`
 
+	// Per-method backend timeouts, e.g. a short tools/list and a long tools/call;
+	// methods without an entry use DefaultTimeout
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts"`
+
+	// Client deadlines read from a request header
+	RequestDeadline RequestDeadlineConfig `yaml:"request_deadline"`
+
 	// Load balancing
 	LoadBalancingEnabled  bool   `yaml:"load_balancing_enabled"`
 	LoadBalancingStrategy string `yaml:"load_balancing_strategy"`
@@ -50,6 +110,9 @@ type RouterConfig struct {
 	RetryAttempts int           `yaml:"retry_attempts"`
 	RetryBackoff  time.Duration `yaml:"retry_backoff"`
 
+	// Gateway-wide cap on retries relative to routed requests
+	RetryBudget RetryBudgetConfig `yaml:"retry_budget"`
+
 	// Monitoring
 	EnableMetrics bool `yaml:"enable_metrics"`
 	EnableTracing bool `yaml:"enable_tracing"`
@@ -57,11 +120,114 @@ type RouterConfig struct {
 	// Plugin integration
 	EnablePluginRouting   bool `yaml:"enable_plugin_routing"`
 	RequireAuthentication bool `yaml:"require_authentication"`
+
+	// Request identification
+	RequestIDHeader string `yaml:"request_id_header"`
+	RequestIDFormat string `yaml:"request_id_format"` // uuid, timestamp
+
+	// Header carrying the session key used for hash load balancing affinity
+	SessionHeader string `yaml:"session_header"`
+
+	// Region and zone whose backends requests prefer
+	RegionAffinity RegionAffinityConfig `yaml:"region_affinity"`
+
+	// Debug body logging
+	BodyLogging BodyLoggingConfig `yaml:"body_logging"`
+
+	// Gateway-wide tool and resource allowlist/denylist
+	CapabilityPolicy CapabilityPolicyConfig `yaml:"capability_policy"`
+
+	// Permission each MCP method and tool requires from the caller
+	MethodPolicy rbac.MethodPolicy `yaml:"method_policy"`
+
+	// Serve last-known-good responses for read methods when backends are down
+	DegradedMode DegradedModeConfig `yaml:"degraded_mode"`
+
+	// Window during which tool calls with the same idempotency key return the
+	// first result instead of executing again; 0 disables deduplication
+	IdempotencyWindow time.Duration `yaml:"idempotency_window"`
+
+	// Share one upstream call between concurrent identical read requests
+	RequestCoalescing bool `yaml:"request_coalescing"`
+
+	// Write null or missing array fields in MCP results (tools, resources,
+	// prompts, content, ...) as empty arrays
+	NormalizeEmptyArrays bool `yaml:"normalize_empty_arrays"`
+
+	// Record requests that exhaust their retries for later inspection or replay
+	DeadLetter DeadLetterConfig `yaml:"dead_letter"`
+
+	// Audit every tools/call with hashes of its arguments and result
+	ToolAudit ToolAuditConfig `yaml:"tool_audit"`
+
+	// In-memory record of recent requests served by the admin API
+	RequestHistory RequestHistoryConfig `yaml:"request_history"`
+
+	// Client headers forwarded to backends and static headers injected
+	BackendHeaders BackendHeadersConfig `yaml:"backend_headers"`
+
+	// Tool name patterns routed to plugins for tools/call, checked in order
+	// before the tools each plugin reports
+	PluginToolRoutes []PluginToolRoute `yaml:"plugin_tool_routes"`
+
+	// Follow backend redirects that keep the request method (307, 308);
+	// otherwise any 3xx response fails the call
+	FollowRedirects bool `yaml:"follow_redirects"`
+
+	// Whether logging/setLevel adjusts the gateway logger, is forwarded to
+	// logging_provider services, or both
+	LogLevelMode string `yaml:"log_level_mode"`
+
+	// Send methods without a prefix, which match no service type, to
+	// generic_adapter services; otherwise they are not found
+	GenericAdapterFallback bool `yaml:"generic_adapter_fallback"`
+
+	// Accept requests without a jsonrpc member as JSON-RPC 2.0; requests
+	// declaring another version are rejected either way
+	LenientJSONRPCVersion bool `yaml:"lenient_jsonrpc_version"`
+
+	// Whether JSON-RPC error data carries the error text (full) or only a
+	// reference to it in the gateway logs (sanitized, the default)
+	ErrorDetail string `yaml:"error_detail"`
+
+	// JSON field changes applied to requests and responses of matching services
+	Transformations []TransformRule `yaml:"transformations"`
+
+	// Calls per window allowed for matching tools, regardless of the HTTP
+	// rate limit
+	ToolRateLimits []ToolRateLimit `yaml:"tool_rate_limits"`
+
+	// How long resource subscriptions of a session outlive its last
+	// notification stream; 0 purges them at once and a negative value keeps
+	// them until unsubscribed
+	SubscriptionGracePeriod time.Duration `yaml:"subscription_grace_period"`
+
+	// Server info returned from the initialize handshake
+	ServerName    string `yaml:"server_name"`
+	ServerVersion string `yaml:"server_version"`
 }
 
+// Supported request ID formats
+const (
+	RequestIDFormatUUID      = "uuid"
+	RequestIDFormatTimestamp = "timestamp"
+)
+
+// Response validation modes, selectable per service via the
+// "response_validation" registration metadata key
+const (
+	ResponseValidationStrict   = "strict"   // Invalid responses fail the request
+	ResponseValidationLenient  = "lenient"  // Invalid responses are logged and passed through
+	ResponseValidationDisabled = "disabled" // Responses are not validated
+)
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
 // RequestContext provides context for request routing
 type RequestContext struct {
 	RequestID    string
+	ServiceID    string
 	TraceID      string
 	SpanID       string
 	ClientID     string
@@ -74,6 +240,30 @@ type RequestContext struct {
 	Capabilities *rbac.ProcessedCapabilities
 	AuthToken    string
 	IsPluginCall bool
+
+	// JSON-RPC id echoed in responses; IsNotification marks requests without an id
+	JSONRPCID      interface{}
+	IsNotification bool
+
+	// Set when the result came from the last-known-good cache
+	ServedStale bool
+	StaleAge    time.Duration
+
+	// Client-supplied key deduplicating retried tool calls
+	IdempotencyKey   string
+	IdempotentReplay bool
+
+	// Client request headers, forwarded to backends only when allowlisted
+	InboundHeaders http.Header
+
+	// Trace context of the gateway span, forwarded to backends as traceparent
+	TraceParent *TraceParent
+
+	// Token from params._meta under which the client wants progress notifications
+	ProgressToken interface{}
+
+	// Redacted params kept for the request history
+	historyParams string
 }
 
 // NewMCPRouter creates a new MCP router
@@ -85,15 +275,118 @@ func NewMCPRouter(
 	metrics metrics.Metrics,
 	validator *validation.Validator,
 ) *MCPRouter {
-	return &MCPRouter{
+	return NewMCPRouterWithConfig(registry, pluginHandler, rbacEngine, logger, metrics, validator, DefaultRouterConfig())
+}
+
+// NewMCPRouterWithConfig creates a new MCP router with an explicit configuration
+func NewMCPRouterWithConfig(
+	registry *registry.ServiceRegistry,
+	pluginHandler mcpTypes.PluginHandler,
+	rbacEngine *rbac.Engine,
+	logger logging.Logger,
+	metrics metrics.Metrics,
+	validator *validation.Validator,
+	config RouterConfig,
+) *MCPRouter {
+	if config.RequestIDHeader == "" {
+		config.RequestIDHeader = "X-Request-ID"
+	}
+	if config.RequestIDFormat == "" {
+		config.RequestIDFormat = RequestIDFormatUUID
+	}
+	if config.SessionHeader == "" {
+		config.SessionHeader = "X-Session-ID"
+	}
+	if config.RequestDeadline.Header == "" {
+		config.RequestDeadline.Header = DefaultDeadlineHeader
+	}
+	if config.RegionAffinity.RegionHeader == "" {
+		config.RegionAffinity.RegionHeader = defaultRegionAffinityConfig().RegionHeader
+	}
+	if config.RegionAffinity.ZoneHeader == "" {
+		config.RegionAffinity.ZoneHeader = defaultRegionAffinityConfig().ZoneHeader
+	}
+	if config.ServerName == "" {
+		config.ServerName = "mcpeg"
+	}
+	if config.ServerVersion == "" {
+		config.ServerVersion = "dev"
+	}
+	if config.LogLevelMode == "" {
+		config.LogLevelMode = LogLevelModeBoth
+	}
+	if config.ErrorDetail == "" {
+		config.ErrorDetail = ErrorDetailSanitized
+	}
+
+	mr := &MCPRouter{
 		registry:      registry,
 		pluginHandler: pluginHandler,
 		rbacEngine:    rbacEngine,
 		logger:        logger.WithComponent("mcp_router"),
 		metrics:       metrics,
 		validator:     validator,
-		config:        defaultRouterConfig(),
+		config:        config,
+		lastKnownGood: newLastKnownGoodCache(config.DegradedMode.MaxEntries),
+		idempotency:   newIdempotencyCache(defaultIdempotencyMaxEntries),
+		coalescer:     newRequestCoalescer(),
+		subscriptions: newResourceSubscriptions(),
+		roots:         newRootsCache(),
+		transports:    newUpstreamTransports(),
+
+		toolRateLimiter: newToolRateLimiter(),
+	}
+
+	if registry != nil {
+		registry.OnServiceRemoved(mr.transports.evict)
+	}
+
+	if err := config.RetryBudget.Validate(); err != nil {
+		mr.logger.Error("retry_budget_config_invalid", "error", err)
+	} else {
+		mr.retryBudget = newRetryBudget(config.RetryBudget)
+	}
+
+	if err := mr.SetCapabilityPolicy(config.CapabilityPolicy); err != nil {
+		mr.logger.Error("capability_policy_invalid", "error", err)
+	}
+
+	deadLetter, err := newDeadLetterSink(config.DeadLetter)
+	if err != nil {
+		mr.logger.Error("dead_letter_config_invalid", "error", err)
+	}
+	mr.deadLetter = deadLetter
+
+	toolAudit, err := newToolAuditSink(config.ToolAudit)
+	if err != nil {
+		mr.logger.Error("tool_audit_config_invalid", "error", err)
 	}
+	if toolAudit != nil {
+		mr.toolAudit = mr.newToolAuditWriter(toolAudit, config.ToolAudit.BufferSize)
+	}
+
+	if config.RequestHistory.Enabled {
+		mr.history = newRequestHistory(config.RequestHistory.Size)
+	}
+
+	mr.validateBackendHeaders()
+
+	if err := ValidateTransformRules(config.Transformations); err != nil {
+		mr.logger.Error("transformation_rules_invalid", "error", err)
+		mr.config.Transformations = nil
+	}
+
+	if err := ValidateToolRateLimits(config.ToolRateLimits); err != nil {
+		mr.logger.Error("tool_rate_limits_invalid", "error", err)
+		mr.config.ToolRateLimits = nil
+	}
+
+	// The root logger controls the level of every component derived from it
+	if controller, ok := logger.(logging.LevelController); ok {
+		mr.levelController = controller
+	}
+
+	return mr
 }
 
 // SetupRoutes configures HTTP routes for the MCP router
@@ -101,12 +394,16 @@ func (mr *MCPRouter) SetupRoutes(router *mux.Router) {
 	// MCP JSON-RPC endpoint
 	router.HandleFunc("/mcp", mr.handleMCPRequest).Methods("POST")
 
+	// One path per tool, as described by the gateway's OpenAPI document
+	router.HandleFunc(ToolCallPathPrefix+"{name}", mr.handleToolCallPath).Methods("POST")
+
 	// MCP method-specific endpoints
 	if mr.config.EnableMethodRouting {
 		router.HandleFunc("/mcp/tools/list", mr.handleToolsList).Methods("POST")
 		router.HandleFunc("/mcp/tools/call", mr.handleToolsCall).Methods("POST")
 		router.HandleFunc("/mcp/resources/list", mr.handleResourcesList).Methods("POST")
 		router.HandleFunc("/mcp/resources/read", mr.handleResourcesRead).Methods("POST")
+		router.HandleFunc("/mcp/resources/content", mr.handleResourceContent).Methods("GET")
 		router.HandleFunc("/mcp/resources/subscribe", mr.handleResourcesSubscribe).Methods("POST")
 		router.HandleFunc("/mcp/prompts/list", mr.handlePromptsList).Methods("POST")
 		router.HandleFunc("/mcp/prompts/get", mr.handlePromptsGet).Methods("POST")
@@ -124,6 +421,9 @@ func (mr *MCPRouter) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
 	// Create request context
 	reqCtx := mr.createRequestContext(r)
 
+	// Echo the request ID so clients can correlate responses with gateway logs
+	w.Header().Set(mr.config.RequestIDHeader, reqCtx.RequestID)
+
 	mr.logger.Info("mcp_request_started",
 		"request_id", reqCtx.RequestID,
 		"method", reqCtx.Method,
@@ -131,47 +431,60 @@ func (mr *MCPRouter) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
 
 	// Parse JSON-RPC request
 	var mcpReq mcpTypes.JSONRPCRequest
-	if err := mr.parseJSONRPCRequest(r, &mcpReq); err != nil {
+	if err := mr.parseJSONRPCRequest(r, reqCtx, &mcpReq); err != nil {
 		mr.writeErrorResponse(w, reqCtx, mcpTypes.ErrorCodeParseError, "Invalid JSON-RPC request", err)
 		return
 	}
 
 	reqCtx.Method = mcpReq.Method
+	reqCtx.IdempotencyKey = idempotencyKeyFromRequest(r, &mcpReq)
+	reqCtx.ProgressToken = progressTokenFromParams(mcpReq.Params)
+	mr.logRequestBody(r, reqCtx, mcpReq.Params)
+	mr.captureHistoryParams(reqCtx, mcpReq.Params)
 
 	// Authenticate request if authentication is enabled
-	if mr.config.RequireAuthentication && mr.rbacEngine != nil {
-		if err := mr.authenticateRequest(r, reqCtx); err != nil {
-			mr.writeErrorResponse(w, reqCtx, mcpTypes.ErrorCodeUnauthorized, "Authentication failed", err)
-			return
-		}
-	} else {
-		// Set default capabilities for unauthenticated requests
-		reqCtx.Capabilities = &rbac.ProcessedCapabilities{
-			UserID: "anonymous",
-			Roles:  []string{"admin"},
-			Plugins: map[string]rbac.PluginPermission{
-				"*": {CanRead: true, CanWrite: true, CanExecute: true, CanAdmin: true},
-			},
-		}
+	if err := mr.resolveCapabilities(r, reqCtx); err != nil {
+		mr.auditToolRejection(reqCtx, &mcpReq, mcpTypes.ErrorCodeUnauthorized)
+		mr.writeErrorResponse(w, reqCtx, mcpTypes.ErrorCodeUnauthorized, "Authentication failed", err)
+		return
+	}
+
+	// Check the caller may use this method before routing it
+	if err := mr.authorizeMethod(reqCtx, mcpReq.Method, mcpReq.Params); err != nil {
+		mr.auditToolRejection(reqCtx, &mcpReq, errors.ToJSONRPC(err).Code)
+		mr.handleRoutingError(w, reqCtx, err)
+		return
 	}
 
 	// Validate request
 	if mr.config.ValidateRequests {
 		if err := mr.validateJSONRPCRequest(&mcpReq); err != nil {
+			mr.auditToolRejection(reqCtx, &mcpReq, mcpTypes.ErrorCodeInvalidParams)
 			mr.writeErrorResponse(w, reqCtx, mcpTypes.ErrorCodeInvalidParams, "Request validation failed", err)
 			return
 		}
 	}
 
+	// Honour the client's deadline, bounded by the gateway's own
+	ctx, cancel, err := mr.requestDeadlineContext(r, reqCtx)
+	if err != nil {
+		mr.auditToolRejection(reqCtx, &mcpReq, errors.ToJSONRPC(err).Code)
+		mr.handleRoutingError(w, reqCtx, err)
+		return
+	}
+	defer cancel()
+
 	// Route request to appropriate service
-	result, err := mr.routeJSONRPCRequest(r.Context(), reqCtx, &mcpReq)
+	result, err := mr.routeWithIdempotency(ctx, reqCtx, &mcpReq)
 	if err != nil {
+		mr.auditToolCall(reqCtx, &mcpReq, nil, err)
 		mr.handleRoutingError(w, reqCtx, err)
 		return
 	}
 
-	// Validate response
-	if mr.config.ValidateResponses {
+	// Validate response; service-routed responses were already validated
+	// according to the target service's response validation mode
+	if mr.config.ValidateResponses && reqCtx.ServiceID == "" {
 		if err := mr.validateResponse(result); err != nil {
 			mr.logger.Warn("response_validation_failed",
 				"request_id", reqCtx.RequestID,
@@ -179,20 +492,40 @@ func (mr *MCPRouter) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Write successful response
-	response := types.Response{
-		JSONRPC: "2.0",
-		Result:  result,
-		ID:      mcpReq.ID,
+	if mr.config.NormalizeEmptyArrays {
+		result = normalizeResultArrays(reqCtx.Method, result)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	mr.auditToolCall(reqCtx, &mcpReq, result, nil)
+	mr.logResponseBody(r, reqCtx, result)
+
+	// Notifications are processed but never answered
+	if reqCtx.IsNotification {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		// Write successful response
+		response := types.Response{
+			JSONRPC: "2.0",
+			Result:  result,
+			ID:      reqCtx.JSONRPCID,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if reqCtx.ServedStale {
+			w.Header().Set(StaleResponseHeader, "true")
+			w.Header().Set("Age", strconv.Itoa(int(reqCtx.StaleAge.Seconds())))
+		}
+		if reqCtx.IdempotentReplay {
+			w.Header().Set(IdempotentReplayHeader, "true")
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	}
 
 	// Record metrics
 	duration := time.Since(start)
 	mr.recordRequestMetrics(reqCtx, duration, nil)
+	mr.recordRequestHistory(reqCtx, 0, "", nil)
 
 	mr.logger.Info("mcp_request_completed",
 		"request_id", reqCtx.RequestID,
@@ -201,169 +534,18 @@ func (mr *MCPRouter) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
 		"success", true)
 }
 
-// routeRequest routes an MCP request to the appropriate service
-func (mr *MCPRouter) routeRequest(ctx context.Context, reqCtx *RequestContext, mcpReq *types.Request) (interface{}, error) {
-	// Check if this is a plugin request first
-	if mr.config.EnablePluginRouting && mr.pluginHandler != nil {
-		if result, handled, err := mr.tryPluginRouting(ctx, reqCtx, mcpReq); handled {
-			return result, err
-		}
-	}
-
-	// Determine target service type based on method
-	serviceType := mr.determineServiceType(mcpReq.Method)
-	if serviceType == "" {
-		return nil, errors.ValidationError("mcp_router", "route_request",
-			fmt.Sprintf("Unknown MCP method: %s", mcpReq.Method), map[string]interface{}{
-				"method":     mcpReq.Method,
-				"request_id": reqCtx.RequestID,
-			})
-	}
-
-	reqCtx.ServiceType = serviceType
-
-	// Create selection criteria
-	criteria := registry.SelectionCriteria{
-		LoadBalancing: mr.config.LoadBalancingStrategy,
-		Metadata:      reqCtx.Preferences,
-	}
-
-	// Select service instance
-	service, err := mr.registry.SelectService(serviceType, criteria)
-	if err != nil {
-		return nil, errors.UnavailableError("mcp_router", "route_request", err, map[string]interface{}{
-			"service_type": serviceType,
-			"method":       mcpReq.Method,
-			"request_id":   reqCtx.RequestID,
-		})
-	}
-
-	mr.logger.Debug("service_selected_for_request",
-		"request_id", reqCtx.RequestID,
-		"service_id", service.ID,
-		"service_type", serviceType,
-		"method", mcpReq.Method)
-
-	// Execute request with retry logic
-	var result interface{}
-	var lastErr error
-
-	attempts := 1
-	if mr.config.RetryEnabled {
-		attempts = mr.config.RetryAttempts
+// methodTimeout returns the backend timeout for an MCP method
+func (mr *MCPRouter) methodTimeout(method string) time.Duration {
+	if timeout, ok := mr.config.MethodTimeouts[method]; ok && timeout > 0 {
+		return timeout
 	}
-
-	for attempt := 1; attempt <= attempts; attempt++ {
-		startTime := time.Now()
-
-		result, lastErr = mr.executeRequest(ctx, service, mcpReq)
-
-		duration := time.Since(startTime)
-
-		if lastErr == nil {
-			// Success - record metrics and return
-			mr.registry.GetLoadBalancer().RecordSuccess(service, duration)
-			return result, nil
-		}
-
-		// Record failure
-		mr.registry.GetLoadBalancer().RecordFailure(service, lastErr)
-
-		mr.logger.Warn("service_request_failed",
-			"request_id", reqCtx.RequestID,
-			"service_id", service.ID,
-			"attempt", attempt,
-			"max_attempts", attempts,
-			"error", lastErr,
-			"duration", duration)
-
-		// If not the last attempt, wait before retrying
-		if attempt < attempts {
-			backoff := mr.config.RetryBackoff * time.Duration(attempt)
-			time.Sleep(backoff)
-
-			// Try to select a different service instance for retry
-			if newService, err := mr.registry.SelectService(serviceType, criteria); err == nil {
-				service = newService
-				mr.logger.Debug("retrying_with_different_service",
-					"request_id", reqCtx.RequestID,
-					"new_service_id", service.ID,
-					"attempt", attempt+1)
-			}
-		}
-	}
-
-	return nil, lastErr
+	return mr.config.DefaultTimeout
 }
 
-// executeRequest executes an MCP request against a specific service
-func (mr *MCPRouter) executeRequest(ctx context.Context, service *registry.RegisteredService, mcpReq *types.Request) (interface{}, error) {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: mr.config.DefaultTimeout,
-	}
-
-	// Prepare request body
-	reqBody, err := json.Marshal(mcpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", service.Endpoint, strings.NewReader(string(reqBody)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("User-Agent", "MCPEG/1.0")
-
-	// Execute request
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check HTTP status
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
-	}
-
-	// Parse response
-	var mcpResp types.Response
-	if err := json.NewDecoder(resp.Body).Decode(&mcpResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// Check for JSON-RPC error
-	if mcpResp.Error != nil {
-		return nil, fmt.Errorf("MCP error %d: %s", mcpResp.Error.Code, mcpResp.Error.Message)
-	}
-
-	return mcpResp.Result, nil
-}
-
-// determineServiceType determines the appropriate service type for an MCP method
+// determineServiceType determines the appropriate service type for an MCP
+// method, or "" when no service type can serve it
 func (mr *MCPRouter) determineServiceType(method string) string {
-	// Map MCP methods to service types
-	methodServiceMap := map[string]string{
-		"tools/list":             "tool_provider",
-		"tools/call":             "tool_provider",
-		"resources/list":         "resource_provider",
-		"resources/read":         "resource_provider",
-		"resources/subscribe":    "resource_provider",
-		"prompts/list":           "prompt_provider",
-		"prompts/get":            "prompt_provider",
-		"completion/complete":    "completion_provider",
-		"logging/setLevel":       "logging_provider",
-		"sampling/createMessage": "sampling_provider",
-		"roots/list":             "root_provider",
-	}
-
-	if serviceType, exists := methodServiceMap[method]; exists {
+	if serviceType, exists := mcpMethodServiceTypes[method]; exists {
 		return serviceType
 	}
 
@@ -373,8 +555,11 @@ func (mr *MCPRouter) determineServiceType(method string) string {
 		return parts[0] + "_provider"
 	}
 
-	// Default to generic adapter
-	return "generic_adapter"
+	// Methods without a prefix only reach generic adapters when enabled
+	if mr.config.GenericAdapterFallback {
+		return GenericAdapterServiceType
+	}
+	return ""
 }
 
 // Method-specific handlers (simplified implementations)
@@ -439,16 +624,31 @@ func (mr *MCPRouter) handleMethodRequest(w http.ResponseWriter, r *http.Request,
 // Helper methods
 
 func (mr *MCPRouter) createRequestContext(r *http.Request) *RequestContext {
-	return &RequestContext{
-		RequestID:   generateRequestID(),
+	// Honor a client-supplied request ID for end-to-end correlation
+	requestID := r.Header.Get(mr.config.RequestIDHeader)
+	if !IsValidRequestID(requestID) {
+		requestID = GenerateRequestID(mr.config.RequestIDFormat)
+	}
+
+	reqCtx := &RequestContext{
+		RequestID:   requestID,
 		TraceID:     r.Header.Get("X-Trace-ID"),
 		SpanID:      r.Header.Get("X-Span-ID"),
 		ClientID:    r.Header.Get("X-Client-ID"),
 		UserID:      r.Header.Get("X-User-ID"),
-		SessionID:   r.Header.Get("X-Session-ID"),
+		SessionID:   r.Header.Get(mr.config.SessionHeader),
 		StartTime:   time.Now(),
 		Preferences: make(map[string]interface{}),
+
+		InboundHeaders: r.Header.Clone(),
 	}
+
+	if traceParent, ok := ParseTraceParent(r.Header.Get(TraceParentHeader)); ok {
+		reqCtx.TraceParent = &traceParent
+		reqCtx.TraceID = traceParent.TraceID
+		reqCtx.SpanID = traceParent.SpanID
+	}
+	return reqCtx
 }
 
 func (mr *MCPRouter) parseRequest(r *http.Request, mcpReq *types.Request) error {
@@ -524,8 +724,8 @@ func (mr *MCPRouter) validateInitializeResult(result *types.InitializeResult) er
 		return fmt.Errorf("protocol version is required")
 	}
 
-	// Validate version format (semantic versioning)
-	if !isValidSemanticVersion(result.ProtocolVersion) {
+	// Validate version format (dated MCP revision or semantic versioning)
+	if !isValidProtocolVersion(result.ProtocolVersion) {
 		return fmt.Errorf("invalid protocol version format: %s", result.ProtocolVersion)
 	}
 
@@ -815,6 +1015,12 @@ func (mr *MCPRouter) validateResourceContent(content *types.ResourceContent, con
 		return fmt.Errorf("%s: content cannot have both text and blob data", context)
 	}
 
+	if hasBlob {
+		if _, err := base64.StdEncoding.DecodeString(content.Blob); err != nil {
+			return fmt.Errorf("%s: blob is not valid base64: %w", context, err)
+		}
+	}
+
 	return nil
 }
 
@@ -983,6 +1189,16 @@ func (mr *MCPRouter) validateLoggingLevelResult(result *types.LoggingLevelResult
 }
 
 // Utility functions for validation
+
+// isValidProtocolVersion accepts dated MCP revisions such as 2025-03-26 as
+// well as semantic versions
+func isValidProtocolVersion(version string) bool {
+	if _, err := time.Parse("2006-01-02", version); err == nil {
+		return true
+	}
+	return isValidSemanticVersion(version)
+}
+
 func isValidSemanticVersion(version string) bool {
 	// Simple semantic version validation (major.minor.patch)
 	parts := strings.Split(version, ".")
@@ -1058,23 +1274,47 @@ func getMapKeys(m map[string]interface{}) []string {
 }
 
 func (mr *MCPRouter) writeErrorResponse(w http.ResponseWriter, reqCtx *RequestContext, code int, message string, err error) {
+	data := errors.JSONRPCData(code, err)
+	errorRef := ""
+	if mr.config.ErrorDetail == ErrorDetailSanitized {
+		errorRef = mr.sanitizeErrorData(data)
+	}
+
 	errorResp := types.Response{
 		JSONRPC: "2.0",
 		Error: &types.Error{
 			Code:    code,
 			Message: message,
-			Data:    err.Error(),
+			Data:    data,
 		},
 		ID: nil,
 	}
 
+	logFields := []interface{}{
+		"error_code", code,
+		"error_message", message,
+		"error", err,
+	}
+	if errorRef != "" {
+		// The reference is all the client sees, so the full error must be logged
+		logFields = append(logFields, "error_ref", errorRef)
+	}
+
 	if reqCtx != nil {
+		// Echo the request's id whenever it was parsed; it stays null otherwise
+		errorResp.ID = reqCtx.JSONRPCID
+
 		mr.recordRequestMetrics(reqCtx, time.Since(reqCtx.StartTime), err)
-		mr.logger.Error("mcp_request_failed",
-			"request_id", reqCtx.RequestID,
-			"error_code", code,
-			"error_message", message,
-			"error", err)
+		mr.recordRequestHistory(reqCtx, code, errorRef, err)
+		mr.logger.Error("mcp_request_failed", append([]interface{}{"request_id", reqCtx.RequestID}, logFields...)...)
+
+		// Errors for notifications are logged but not returned to the client
+		if reqCtx.IsNotification {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+	} else if errorRef != "" {
+		mr.logger.Error("mcp_request_failed", logFields...)
 	}
 
 	mr.writeJSONResponse(w, errorResp)
@@ -1086,26 +1326,31 @@ func (mr *MCPRouter) writeJSONResponse(w http.ResponseWriter, response interface
 }
 
 func (mr *MCPRouter) handleRoutingError(w http.ResponseWriter, reqCtx *RequestContext, err error) {
-	// Determine appropriate error code based on error type
-	var code int
-	var message string
-
-	switch {
-	case errors.IsValidationError(err):
-		code = types.ErrorCodeInvalidParams
-		message = "Invalid parameters"
-	case errors.IsUnavailableError(err):
-		code = types.ErrorCodeInternalError
-		message = "Service unavailable"
-	case errors.IsTimeoutError(err):
-		code = types.ErrorCodeInternalError
-		message = "Request timeout"
-	default:
-		code = types.ErrorCodeInternalError
-		message = "Internal error"
+	// Each error category has its own code so clients can tell failures apart
+	mapping := errors.ToJSONRPC(err)
+	mr.writeErrorResponse(w, reqCtx, mapping.Code, mapping.Message, err)
+}
+
+// resolveCapabilities authenticates the request when authentication is enabled,
+// otherwise it grants the default capabilities for unauthenticated requests
+func (mr *MCPRouter) resolveCapabilities(r *http.Request, reqCtx *RequestContext) error {
+	if mr.config.RequireAuthentication {
+		// Deny rather than fall back to anonymous access when nothing can
+		// verify the caller
+		if mr.rbacEngine == nil {
+			return fmt.Errorf("authentication is required but no RBAC engine is configured")
+		}
+		return mr.authenticateRequest(r, reqCtx)
 	}
 
-	mr.writeErrorResponse(w, reqCtx, code, message, err)
+	reqCtx.Capabilities = &rbac.ProcessedCapabilities{
+		UserID: anonymousUserID,
+		Roles:  []string{"admin"},
+		Plugins: map[string]rbac.PluginPermission{
+			"*": {CanRead: true, CanWrite: true, CanExecute: true, CanAdmin: true},
+		},
+	}
+	return nil
 }
 
 // authenticateRequest handles JWT authentication and RBAC processing
@@ -1136,7 +1381,10 @@ func (mr *MCPRouter) authenticateRequest(r *http.Request, reqCtx *RequestContext
 	// Store capabilities in request context
 	reqCtx.Capabilities = capabilities
 	reqCtx.UserID = capabilities.UserID
-	reqCtx.SessionID = capabilities.SessionID
+	// Keep the session header's key for affinity when the token carries none
+	if capabilities.SessionID != "" {
+		reqCtx.SessionID = capabilities.SessionID
+	}
 
 	mr.logger.Debug("request_authenticated",
 		"request_id", reqCtx.RequestID,
@@ -1158,6 +1406,10 @@ func (mr *MCPRouter) tryPluginRouting(ctx context.Context, reqCtx *RequestContex
 		return mr.handlePluginResourcesList(ctx, reqCtx, mcpReq)
 	case "resources/read":
 		return mr.handlePluginResourcesRead(ctx, reqCtx, mcpReq)
+	case "resources/subscribe":
+		return mr.handlePluginResourcesSubscribe(ctx, reqCtx, mcpReq, true)
+	case "resources/unsubscribe":
+		return mr.handlePluginResourcesSubscribe(ctx, reqCtx, mcpReq, false)
 	case "prompts/list":
 		return mr.handlePluginPromptsList(ctx, reqCtx, mcpReq)
 
@@ -1227,8 +1479,9 @@ func (mr *MCPRouter) handlePluginToolsList(ctx context.Context, reqCtx *RequestC
 		"available_plugins", availablePlugins,
 		"plugin_count", len(availablePlugins))
 
-	// Aggregate tools from all accessible plugins
-	var allTools []mcpTypes.Tool
+	// Aggregate tools from all accessible plugins in a stable order
+	sort.Strings(availablePlugins)
+	toolsByPlugin := make(map[string][]mcpTypes.Tool, len(availablePlugins))
 	for _, pluginName := range availablePlugins {
 		tools, err := mr.pluginHandler.GetPluginTools(pluginName, reqCtx.Capabilities)
 		if err != nil {
@@ -1240,9 +1493,19 @@ func (mr *MCPRouter) handlePluginToolsList(ctx context.Context, reqCtx *RequestC
 		mr.logger.Debug("plugin_tools_retrieved",
 			"plugin", pluginName,
 			"tool_count", len(tools))
-		allTools = append(allTools, tools...)
+
+		// Tools disabled by gateway policy are hidden entirely
+		allowed := make([]mcpTypes.Tool, 0, len(tools))
+		for _, tool := range tools {
+			if mr.toolAllowed(pluginName, tool.Name) {
+				allowed = append(allowed, tool)
+			}
+		}
+		toolsByPlugin[pluginName] = allowed
 	}
 
+	allTools := mr.namespaceCollidingTools(reqCtx, availablePlugins, toolsByPlugin)
+
 	mr.metrics.Inc("plugin_tools_list_calls", "user_id", reqCtx.UserID)
 	mr.logger.Info("plugin_tools_list_completed",
 		"request_id", reqCtx.RequestID,
@@ -1278,39 +1541,23 @@ func (mr *MCPRouter) handlePluginToolsCall(ctx context.Context, reqCtx *RequestC
 		return nil, true, fmt.Errorf("missing tool name")
 	}
 
-	// Extract plugin name from tool name (supports plugin.tool or plugin_tool formats)
-	var pluginName, actualToolName string
-
-	if strings.Contains(toolName, ".") {
-		// Format: plugin.tool
-		toolParts := strings.SplitN(toolName, ".", 2)
-		pluginName = toolParts[0]
-		actualToolName = toolParts[1]
-	} else if strings.HasPrefix(toolName, "memory_") {
-		// Memory plugin tools - preserve full name
-		pluginName = "memory"
-		actualToolName = toolName
-	} else if strings.HasPrefix(toolName, "git_") {
-		// Git plugin tools - preserve full name
-		pluginName = "git"
-		actualToolName = toolName
-	} else if strings.HasPrefix(toolName, "editor_") {
-		// Editor plugin tools - preserve full name
-		pluginName = "editor"
-		actualToolName = toolName
-	} else if strings.Contains(toolName, "_") {
-		// Generic plugin_tool format
-		toolParts := strings.SplitN(toolName, "_", 2)
-		pluginName = toolParts[0]
-		actualToolName = toolName // Preserve full name for compatibility
-	} else {
-		// Direct tool name - try with memory plugin as default
-		pluginName = "memory"
-		actualToolName = toolName
+	pluginName, actualToolName, err := mr.resolvePluginTool(reqCtx, toolName, params)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if !mr.toolAllowed(pluginName, actualToolName) {
+		return nil, true, mr.capabilityDeniedError(reqCtx, "tool", toolName)
+	}
+	if err := mr.authorizeToolOwner(reqCtx, pluginName, actualToolName); err != nil {
+		return nil, true, err
 	}
 
 	// Get tool arguments
 	arguments, _ := params["arguments"].(map[string]interface{})
+	if err := mr.validateToolArguments(reqCtx, pluginName, actualToolName, arguments); err != nil {
+		return nil, true, err
+	}
 
 	mr.logger.Info("plugin_tool_call_started",
 		"request_id", reqCtx.RequestID,
@@ -1319,7 +1566,7 @@ func (mr *MCPRouter) handlePluginToolsCall(ctx context.Context, reqCtx *RequestC
 		"user_id", reqCtx.UserID)
 
 	// Execute plugin tool
-	result, err := mr.pluginHandler.InvokePlugin(ctx, pluginName, actualToolName, arguments, reqCtx.Capabilities)
+	result, err := mr.pluginHandler.InvokePlugin(mr.pluginProgressContext(ctx, reqCtx, pluginName), pluginName, actualToolName, arguments, reqCtx.Capabilities)
 	if err != nil {
 		mr.logger.Error("plugin_tool_call_failed",
 			"request_id", reqCtx.RequestID,
@@ -1338,6 +1585,94 @@ func (mr *MCPRouter) handlePluginToolsCall(ctx context.Context, reqCtx *RequestC
 	return result, true, nil
 }
 
+// namespaceCollidingTools flattens per-plugin tool lists, prefixing any tool whose
+// name is exposed by more than one plugin with its plugin name (plugin.tool)
+func (mr *MCPRouter) namespaceCollidingTools(reqCtx *RequestContext, pluginNames []string, toolsByPlugin map[string][]mcpTypes.Tool) []mcpTypes.Tool {
+	owners := make(map[string][]string)
+	for _, pluginName := range pluginNames {
+		for _, tool := range toolsByPlugin[pluginName] {
+			owners[tool.Name] = append(owners[tool.Name], pluginName)
+		}
+	}
+
+	allTools := make([]mcpTypes.Tool, 0)
+	for _, pluginName := range pluginNames {
+		for _, tool := range toolsByPlugin[pluginName] {
+			if len(owners[tool.Name]) > 1 {
+				tool.Name = pluginName + "." + strings.TrimPrefix(tool.Name, pluginName+".")
+			}
+			allTools = append(allTools, tool)
+		}
+	}
+
+	for toolName, plugins := range owners {
+		if len(plugins) > 1 {
+			mr.metrics.Inc("plugin_tool_name_collisions_total", "tool", toolName)
+			mr.logger.Warn("plugin_tool_name_collision",
+				"request_id", reqCtx.RequestID,
+				"tool", toolName,
+				"plugins", plugins)
+		}
+	}
+
+	return allTools
+}
+
+// resolvePluginTool deterministically resolves the plugin serving a tools/call request.
+// An explicit "plugin" parameter wins, followed by a plugin.tool prefix naming an
+// accessible plugin, followed by the configured tool routes, followed by a lookup
+// of the unqualified name across the tools each plugin reports.
+func (mr *MCPRouter) resolvePluginTool(reqCtx *RequestContext, toolName string, params map[string]interface{}) (string, string, error) {
+	if pluginName, ok := params["plugin"].(string); ok && pluginName != "" {
+		return pluginName, strings.TrimPrefix(toolName, pluginName+"."), nil
+	}
+
+	availablePlugins := mr.pluginHandler.ListAvailablePlugins(reqCtx.Capabilities)
+	sort.Strings(availablePlugins)
+
+	if prefix, name, found := strings.Cut(toolName, "."); found && contains(availablePlugins, prefix) {
+		return prefix, name, nil
+	}
+
+	if pluginName, ok := mr.routedPlugin(toolName); ok {
+		return pluginName, toolName, nil
+	}
+
+	var candidates []string
+	for _, pluginName := range availablePlugins {
+		tools, err := mr.pluginHandler.GetPluginTools(pluginName, reqCtx.Capabilities)
+		if err != nil {
+			continue
+		}
+		for _, tool := range tools {
+			if strings.TrimPrefix(tool.Name, pluginName+".") == toolName {
+				candidates = append(candidates, pluginName)
+				break
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", "", mr.unknownToolError(reqCtx, toolName, availablePlugins)
+	case 1:
+		return candidates[0], toolName, nil
+	default:
+		mr.metrics.Inc("plugin_tool_ambiguous_calls_total", "tool", toolName)
+		mr.logger.Warn("plugin_tool_call_ambiguous",
+			"request_id", reqCtx.RequestID,
+			"tool", toolName,
+			"plugins", candidates)
+		return "", "", errors.ValidationError("mcp_router", "resolve_plugin_tool",
+			fmt.Sprintf("Tool %s is provided by multiple plugins; qualify it as plugin.tool or set the plugin parameter", toolName),
+			map[string]interface{}{
+				"tool":       toolName,
+				"plugins":    candidates,
+				"request_id": reqCtx.RequestID,
+			})
+	}
+}
+
 // handlePluginResourcesList handles resources/list through plugin system
 func (mr *MCPRouter) handlePluginResourcesList(ctx context.Context, reqCtx *RequestContext, mcpReq *types.Request) (interface{}, bool, error) {
 	reqCtx.IsPluginCall = true
@@ -1359,7 +1694,11 @@ func (mr *MCPRouter) handlePluginResourcesList(ctx context.Context, reqCtx *Requ
 				"error", err)
 			continue
 		}
-		allResources = append(allResources, resources...)
+		for _, resource := range resources {
+			if mr.resourceAllowed(resource.URI) {
+				allResources = append(allResources, resource)
+			}
+		}
 	}
 
 	mr.metrics.Inc("plugin_resources_list_calls", "user_id", reqCtx.UserID)
@@ -1397,6 +1736,10 @@ func (mr *MCPRouter) handlePluginResourcesRead(ctx context.Context, reqCtx *Requ
 		return nil, true, fmt.Errorf("missing resource URI")
 	}
 
+	if !mr.resourceAllowed(params.URI) {
+		return nil, true, mr.capabilityDeniedError(reqCtx, "resource", params.URI)
+	}
+
 	mr.logger.Debug("plugin_resource_read_started",
 		"request_id", reqCtx.RequestID,
 		"user_id", reqCtx.UserID,
@@ -1417,30 +1760,25 @@ func (mr *MCPRouter) handlePluginResourcesRead(ctx context.Context, reqCtx *Requ
 		"request_id", reqCtx.RequestID,
 		"uri", params.URI)
 
-	// Convert the plugin result to proper MCP ResourceContent format
-	content := map[string]interface{}{
-		"uri":      params.URI,
-		"mimeType": "application/json",
+	// Convert the plugin result to proper MCP ResourceContent format and check
+	// it against the MIME type the plugin declares for this resource
+	declaredMimeType := mr.pluginResourceMimeType(reqCtx, params.URI)
+	content, err := pluginResourceContent(params.URI, result, declaredMimeType)
+	if err == nil {
+		err = mr.validateReadResourceContent(&content, declaredMimeType, "content[0]")
 	}
-
-	// Convert result to JSON text if it's not already a string
-	switch v := result.(type) {
-	case string:
-		content["text"] = v
-		content["mimeType"] = "text/plain"
-	default:
-		// Convert to JSON string for structured data
-		if jsonBytes, err := json.Marshal(v); err == nil {
-			content["text"] = string(jsonBytes)
-		} else {
-			// Fallback to string representation
-			content["text"] = fmt.Sprintf("%v", v)
-		}
+	if err != nil {
+		mr.metrics.Inc("mcp_resource_content_rejections_total", "service_id", "plugin")
+		mr.logger.Error("plugin_resource_content_invalid",
+			"request_id", reqCtx.RequestID,
+			"uri", params.URI,
+			"error", err)
+		return nil, true, fmt.Errorf("invalid content for resource %s: %w", params.URI, err)
 	}
 
 	// Return the result in proper MCP format
 	return map[string]interface{}{
-		"contents": []interface{}{content},
+		"contents": []types.ResourceContent{content},
 	}, true, nil
 }
 
@@ -2008,31 +2346,80 @@ func (mr *MCPRouter) recordRequestMetrics(reqCtx *RequestContext, duration time.
 	mr.metrics.Observe("mcp_request_duration_seconds", duration.Seconds(), labels...)
 }
 
-func generateRequestID() string {
-	return fmt.Sprintf("req_%d", time.Now().UnixNano())
+// requestIDSequence disambiguates timestamp request IDs generated in the same nanosecond
+var requestIDSequence uint64
+
+// GenerateRequestID creates a new request ID in the given format.
+// Unknown formats fall back to UUIDv4.
+func GenerateRequestID(format string) string {
+	if format == RequestIDFormatTimestamp {
+		return fmt.Sprintf("req_%d_%d", time.Now().UnixNano(), atomic.AddUint64(&requestIDSequence, 1))
+	}
+
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("req_%d_%d", time.Now().UnixNano(), atomic.AddUint64(&requestIDSequence, 1))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// IsValidRequestID reports whether a client-supplied request ID is safe to adopt.
+// IDs must be non-empty, bounded in length and limited to printable ASCII without spaces.
+func IsValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
 }
 
-func defaultRouterConfig() RouterConfig {
+// DefaultRouterConfig returns the default router configuration
+func DefaultRouterConfig() RouterConfig {
 	return RouterConfig{
-		DefaultTimeout:        30 * time.Second,
-		MaxRequestSize:        10 * 1024 * 1024, // 10MB
-		EnableMethodRouting:   true,
-		LoadBalancingEnabled:  true,
-		LoadBalancingStrategy: "round_robin",
-		ValidateRequests:      true,
-		ValidateResponses:     false,
-		RetryEnabled:          true,
-		RetryAttempts:         3,
-		RetryBackoff:          1 * time.Second,
-		EnableMetrics:         true,
-		EnableTracing:         true,
-		EnablePluginRouting:   true,
-		RequireAuthentication: false, // Can be enabled via config
+		DefaultTimeout:          30 * time.Second,
+		MaxRequestSize:          10 * 1024 * 1024, // 10MB
+		MaxResponseSize:         defaultMaxResponseSize,
+		EnableMethodRouting:     true,
+		LoadBalancingEnabled:    true,
+		LoadBalancingStrategy:   "round_robin",
+		ValidateRequests:        true,
+		ValidateResponses:       false,
+		RetryEnabled:            true,
+		RetryAttempts:           3,
+		RetryBackoff:            1 * time.Second,
+		EnableMetrics:           true,
+		EnableTracing:           true,
+		EnablePluginRouting:     true,
+		RequireAuthentication:   false, // Can be enabled via config
+		RequestIDHeader:         "X-Request-ID",
+		RequestIDFormat:         RequestIDFormatUUID,
+		SessionHeader:           "X-Session-ID",
+		RequestDeadline:         RequestDeadlineConfig{Header: DefaultDeadlineHeader},
+		RegionAffinity:          defaultRegionAffinityConfig(),
+		BodyLogging:             defaultBodyLoggingConfig(),
+		DegradedMode:            defaultDegradedModeConfig(),
+		IdempotencyWindow:       defaultIdempotencyWindow,
+		SubscriptionGracePeriod: defaultSubscriptionGracePeriod,
+		RequestCoalescing:       true,
+		NormalizeEmptyArrays:    true,
+		LogLevelMode:            LogLevelModeBoth,
+		ErrorDetail:             ErrorDetailSanitized,
+		ServerName:              "mcpeg",
+		ServerVersion:           "dev",
 	}
 }
 
 // JSON-RPC specific parser
-func (mr *MCPRouter) parseJSONRPCRequest(r *http.Request, mcpReq *mcpTypes.JSONRPCRequest) error {
+func (mr *MCPRouter) parseJSONRPCRequest(r *http.Request, reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) error {
 	if r.Header.Get("Content-Type") != "application/json" {
 		return fmt.Errorf("invalid content type, expected application/json")
 	}
@@ -2041,19 +2428,40 @@ func (mr *MCPRouter) parseJSONRPCRequest(r *http.Request, mcpReq *mcpTypes.JSONR
 		return fmt.Errorf("request too large: %d bytes", r.ContentLength)
 	}
 
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(mcpReq); err != nil {
+	body, err := io.ReadAll(io.LimitReader(r.Body, mr.config.MaxRequestSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	if int64(len(body)) > mr.config.MaxRequestSize {
+		return fmt.Errorf("request too large: more than %d bytes", mr.config.MaxRequestSize)
+	}
+
+	// Recover the id before full decoding so error responses can echo it
+	id, idPresent, err := extractJSONRPCID(body)
+	if id != nil {
+		reqCtx.JSONRPCID = id
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, mcpReq); err != nil {
 		return fmt.Errorf("invalid JSON: %w", err)
 	}
+	mcpReq.ID = reqCtx.JSONRPCID
 
-	if mcpReq.JSONRPC != "2.0" {
-		return fmt.Errorf("invalid JSON-RPC version: %s", mcpReq.JSONRPC)
+	if err := mr.checkJSONRPCVersion(reqCtx, body, mcpReq); err != nil {
+		return err
 	}
 
 	if mcpReq.Method == "" {
 		return fmt.Errorf("missing method")
 	}
 
+	// Only a well-formed request without an id is a notification; malformed
+	// ones still get an error response with a null id
+	reqCtx.IsNotification = !idPresent
+
 	return nil
 }
 
@@ -2064,6 +2472,28 @@ func (mr *MCPRouter) validateJSONRPCRequest(mcpReq *mcpTypes.JSONRPCRequest) err
 }
 
 func (mr *MCPRouter) routeJSONRPCRequest(ctx context.Context, reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+	// The handshake is answered by the gateway itself rather than a backend
+	switch mcpReq.Method {
+	case "initialize":
+		return mr.handleInitialize(reqCtx, mcpReq)
+	case "notifications/initialized":
+		return nil, nil
+	case "roots/list":
+		return mr.routeRootsList(ctx, reqCtx, mcpReq)
+	case "notifications/roots/list_changed":
+		return nil, mr.invalidateRoots(reqCtx)
+	case "logging/setLevel":
+		if mr.config.LogLevelMode != LogLevelModeForward {
+			return mr.handleSetLevel(ctx, reqCtx, mcpReq)
+		}
+	}
+
+	if mcpReq.Method == "tools/call" {
+		if err := mr.checkToolRateLimit(reqCtx, mcpReq.Params); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check for plugin routing
 	if mr.config.EnablePluginRouting && mr.pluginHandler != nil {
 		// Convert JSONRPCRequest to legacy types.Request for existing plugin code
@@ -2087,30 +2517,282 @@ func (mr *MCPRouter) routeJSONRPCRequest(ctx context.Context, reqCtx *RequestCon
 	// Fallback to service routing
 	serviceType := mr.determineServiceType(mcpReq.Method)
 	services := mr.registry.GetServicesByType(serviceType)
+	if len(services) == 0 && !isMCPMethod(mcpReq.Method) {
+		return nil, mr.methodNotFound(reqCtx, mcpReq.Method)
+	}
+
+	// In degraded mode read methods fall back to the last-known-good response
+	cacheKey, degradable := mr.degradedCacheKey(reqCtx, mcpReq)
+	if degradable && len(mr.registry.GetHealthyServicesByType(serviceType)) == 0 {
+		if result, ok := mr.serveStale(reqCtx, cacheKey, "no_healthy_backend"); ok {
+			return result, nil
+		}
+	}
 
 	if len(services) == 0 {
-		return nil, fmt.Errorf("no services available for method: %s", mcpReq.Method)
+		return nil, errors.UnavailableError(serviceType, "route_request",
+			fmt.Errorf("no services available for method: %s", mcpReq.Method),
+			map[string]interface{}{"method": mcpReq.Method})
+	}
+
+	// Pick a healthy instance with the load balancing strategy in effect
+	service, err := mr.registry.SelectService(serviceType, mr.selectionCriteria(reqCtx))
+	if err != nil {
+		if degradable {
+			if result, ok := mr.serveStale(reqCtx, cacheKey, "no_healthy_backend"); ok {
+				return result, nil
+			}
+		}
+		return nil, errors.UnavailableError(serviceType, "route_request", err,
+			map[string]interface{}{"method": mcpReq.Method})
 	}
+	reqCtx.ServiceType = serviceType
+	reqCtx.ServiceID = service.ID
+	if err := mr.authorizeServiceTool(reqCtx, service, mcpReq); err != nil {
+		return nil, err
+	}
+
+	result, service, err := mr.forwardWithRetries(ctx, reqCtx, service, mcpReq)
+	if err != nil {
+		if degradable {
+			if result, ok := mr.serveStale(reqCtx, cacheKey, "backend_error"); ok {
+				return result, nil
+			}
+		}
+		return nil, err
+	}
+
+	if err := mr.validateServiceResponse(reqCtx, service, result); err != nil {
+		return nil, err
+	}
+
+	if mcpReq.Method == "resources/read" {
+		if err := mr.validateServiceResourceRead(service, result); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only responses that passed validation may be served stale later
+	if degradable {
+		mr.lastKnownGood.store(cacheKey, result)
+	}
+
+	return result, nil
+}
+
+// forwardWithRetries forwards a request to the selected backend, retrying
+// retryable failures on a newly selected instance when retries are enabled.
+// It returns the instance that made the last attempt. A request that gives up
+// on a retryable failure is dead-lettered. Requests a backend may have acted
+// on are only repeated when that is safe: read methods, calls carrying an
+// Idempotency-Key and attempts that never connected.
+func (mr *MCPRouter) forwardWithRetries(ctx context.Context, reqCtx *RequestContext, service *registry.RegisteredService, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, *registry.RegisteredService, error) {
+	attempts := 1
+	if mr.config.RetryEnabled && mr.config.RetryAttempts > 1 {
+		attempts = mr.config.RetryAttempts
+	}
+	loadBalancer := mr.registry.GetLoadBalancer()
+	mr.retryBudget.recordRequest()
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		startTime := time.Now()
+		result, err := mr.forwardToService(ctx, reqCtx, service, mcpReq)
+		duration := time.Since(startTime)
+
+		// Report the outcome so the load balancer's view of the backend stays current
+		if err == nil {
+			loadBalancer.RecordSuccess(service, duration)
+			return result, service, nil
+		}
+		loadBalancer.RecordFailureAfter(service, err, duration)
+		lastErr = err
+
+		mr.logger.Warn("service_request_failed",
+			"request_id", reqCtx.RequestID,
+			"service_id", service.ID,
+			"attempt", attempt,
+			"max_attempts", attempts,
+			"error", err,
+			"duration", duration)
 
-	// Use first available service for now
-	service := services[0]
-	return mr.forwardToService(ctx, service, mcpReq)
+		if !retryableFailure(ctx, err) {
+			return nil, service, err
+		}
+		if attempt == attempts {
+			break
+		}
+		if !repeatableRequest(reqCtx, mcpReq.Method) && !dialFailure(err) {
+			attempts = attempt
+			break
+		}
+
+		// Stop retrying once retries across the gateway use up their budget
+		if !mr.retryBudget.tryRetry() {
+			mr.metrics.Inc("mcp_retry_budget_exhausted_total", "service_type", reqCtx.ServiceType)
+			mr.logger.Warn("retry_budget_exhausted",
+				"request_id", reqCtx.RequestID,
+				"service_type", reqCtx.ServiceType,
+				"attempt", attempt)
+			attempts = attempt
+			break
+		}
+
+		// Wait before retrying, giving up if the caller goes away meanwhile
+		backoff := time.NewTimer(mr.config.RetryBackoff * time.Duration(attempt))
+		select {
+		case <-backoff.C:
+		case <-ctx.Done():
+			backoff.Stop()
+			return nil, service, lastErr
+		}
+
+		// Try to select a different service instance for retry
+		newService, err := mr.registry.SelectService(reqCtx.ServiceType, mr.selectionCriteria(reqCtx))
+		if err != nil {
+			attempts = attempt
+			break
+		}
+		if err := mr.authorizeServiceTool(reqCtx, newService, mcpReq); err != nil {
+			return nil, service, err
+		}
+		service = newService
+		reqCtx.ServiceID = service.ID
+		mr.logger.Debug("retrying_with_different_service",
+			"request_id", reqCtx.RequestID,
+			"new_service_id", service.ID,
+			"attempt", attempt+1)
+	}
+
+	mr.recordDeadLetter(reqCtx, service, mcpReq, attempts, lastErr)
+	return nil, service, lastErr
+}
+
+// retryableFailure reports whether a failed attempt may succeed on another
+// try: the backend could not be reached or failed with a retryable server
+// error while the caller is still waiting. Attempts that used up their method
+// timeout are not retried, so the timeout bounds the request, and rate limited
+// ones are not either, as the client is told when to retry.
+func retryableFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || stderrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var refused redirectError
+	if stderrors.As(err, &refused) {
+		return false
+	}
+	var transportErr *url.Error
+	if stderrors.As(err, &transportErr) {
+		return true
+	}
+	var mcpErr *errors.MCPError
+	if stderrors.As(err, &mcpErr) && mcpErr.Category != errors.CategoryRateLimit {
+		return errors.ToJSONRPC(err).Retryable
+	}
+	return false
 }
 
-func (mr *MCPRouter) forwardToService(ctx context.Context, service *registry.RegisteredService, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+// repeatableRequest reports whether sending a request again cannot repeat a
+// side effect: the method only reads, or the backend can deduplicate the call
+// by its Idempotency-Key
+func repeatableRequest(reqCtx *RequestContext, method string) bool {
+	return readOnlyMethods[method] || reqCtx.IdempotencyKey != ""
+}
+
+// dialFailure reports whether an attempt failed before connecting to the
+// backend, so the request never reached it
+func dialFailure(err error) bool {
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// selectionCriteria builds the criteria the registry uses to choose a backend
+// instance for a request
+func (mr *MCPRouter) selectionCriteria(reqCtx *RequestContext) registry.SelectionCriteria {
+	region, zone := mr.preferredLocality(reqCtx)
+	return registry.SelectionCriteria{
+		PreferredRegion: region,
+		PreferredZone:   zone,
+		LoadBalancing:   mr.config.LoadBalancingStrategy,
+		Metadata:        reqCtx.Preferences,
+		SessionID:       reqCtx.SessionID,
+	}
+}
+
+// responseValidationMode resolves the response validation mode for a service,
+// falling back to the global ValidateResponses setting when not overridden
+func (mr *MCPRouter) responseValidationMode(service *registry.RegisteredService) string {
+	if mode, ok := service.Metadata["response_validation"].(string); ok {
+		switch strings.ToLower(mode) {
+		case ResponseValidationStrict, ResponseValidationLenient, ResponseValidationDisabled:
+			return strings.ToLower(mode)
+		default:
+			mr.logger.Warn("unknown_response_validation_mode",
+				"service_id", service.ID,
+				"mode", mode)
+		}
+	}
+
+	if mr.config.ValidateResponses {
+		return ResponseValidationLenient
+	}
+	return ResponseValidationDisabled
+}
+
+// validateServiceResponse validates a backend response according to the service's validation mode
+func (mr *MCPRouter) validateServiceResponse(reqCtx *RequestContext, service *registry.RegisteredService, result interface{}) error {
+	mode := mr.responseValidationMode(service)
+	if mode == ResponseValidationDisabled {
+		return nil
+	}
+
+	err := mr.validateResponse(result)
+	if err == nil {
+		return nil
+	}
+
+	mr.metrics.Inc("mcp_response_validation_failures_total", "service_id", service.ID, "mode", mode)
+
+	if mode == ResponseValidationStrict {
+		mr.logger.Error("response_validation_failed",
+			"request_id", reqCtx.RequestID,
+			"service_id", service.ID,
+			"mode", mode,
+			"error", err)
+		return fmt.Errorf("invalid response from service %s: %w", service.ID, err)
+	}
+
+	mr.logger.Warn("response_validation_failed",
+		"request_id", reqCtx.RequestID,
+		"service_id", service.ID,
+		"mode", mode,
+		"error", err)
+	return nil
+}
+
+func (mr *MCPRouter) forwardToService(ctx context.Context, reqCtx *RequestContext, service *registry.RegisteredService, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+	outbound, err := mr.transformRequest(reqCtx, service, mcpReq)
+	if err != nil {
+		return nil, err
+	}
+
 	// Marshal request
-	reqBody, err := json.Marshal(mcpReq)
+	reqBody, err := json.Marshal(outbound)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Create HTTP client
-	client := &http.Client{
-		Timeout: mr.config.DefaultTimeout,
+	// Apply the method timeout to the caller's context rather than the client,
+	// so a client disconnect or an outer deadline aborts the upstream call too
+	ctx, cancel := context.WithTimeout(ctx, mr.methodTimeout(mcpReq.Method))
+	defer cancel()
+	client, err := mr.backendClient(service, 0)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", service.Endpoint, strings.NewReader(string(reqBody)))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", service.EndpointURL(), strings.NewReader(string(reqBody)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -2119,23 +2801,68 @@ func (mr *MCPRouter) forwardToService(ctx context.Context, service *registry.Reg
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
 	httpReq.Header.Set("User-Agent", "MCPEG/1.0")
+	if reqCtx != nil {
+		httpReq.Header.Set(mr.config.RequestIDHeader, reqCtx.RequestID)
+
+		// Backends may stream progress notifications ahead of the response
+		if reqCtx.ProgressToken != nil {
+			httpReq.Header.Set("Accept", "application/json, text/event-stream")
+		}
+	}
+	setIdempotencyHeader(httpReq, reqCtx)
+	setTraceParentHeader(httpReq, reqCtx)
+	if err := mr.applyBackendHeaders(httpReq, reqCtx, service); err != nil {
+		return nil, err
+	}
 
 	// Execute request
+	callStart := time.Now()
 	resp, err := client.Do(httpReq)
+	mr.logTracedBackendCall(reqCtx, service, mcpReq.Method, resp, err, time.Since(callStart))
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, mr.upstreamCancelled(ctx, reqCtx, service, mcpReq.Method)
+		}
 		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check HTTP status
+	if isRedirect(resp.StatusCode) {
+		return nil, mr.backendRedirected(reqCtx, service, mcpReq.Method, resp)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return nil, upstreamStatusError(service, mcpReq.Method, resp)
+	}
+
+	// Bound the response so a misbehaving backend cannot exhaust memory
+	var body io.Reader = resp.Body
+	var limited *limitedBody
+	if mr.config.MaxResponseSize > 0 {
+		if resp.ContentLength > mr.config.MaxResponseSize {
+			return nil, mr.responseTooLarge(reqCtx, service, mcpReq.Method, resp.ContentLength)
+		}
+		limited = newLimitedBody(resp.Body, mr.config.MaxResponseSize)
+		body = limited
 	}
 
 	// Parse response
 	var mcpResp mcpTypes.JSONRPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&mcpResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	snippet := newSnippetReader(body)
+	var decodeErr error
+	if isEventStream(resp.Header.Get("Content-Type")) {
+		decodeErr = mr.readEventStream(reqCtx, service, snippet, &mcpResp)
+	} else {
+		decodeErr = json.NewDecoder(snippet).Decode(&mcpResp)
+	}
+	if err := decodeErr; err != nil {
+		if ctx.Err() != nil {
+			return nil, mr.upstreamCancelled(ctx, reqCtx, service, mcpReq.Method)
+		}
+		if limited != nil && limited.exceeded {
+			return nil, mr.responseTooLarge(reqCtx, service, mcpReq.Method, limited.read)
+		}
+		return nil, mr.invalidResponse(reqCtx, service, mcpReq.Method, resp, snippet, err)
 	}
 
 	// Check for JSON-RPC error
@@ -2143,7 +2870,46 @@ func (mr *MCPRouter) forwardToService(ctx context.Context, service *registry.Reg
 		return nil, fmt.Errorf("MCP error %d: %s", mcpResp.Error.Code, mcpResp.Error.Message)
 	}
 
-	return mcpResp.Result, nil
+	return mr.transformResponse(reqCtx, service, mcpReq.Method, mcpResp.Result)
+}
+
+// upstreamStatusError classifies a non-200 backend response: 429 is a rate
+// limit carrying the backend's Retry-After and 5xx is an upstream error
+func upstreamStatusError(service *registry.RegisteredService, method string, resp *http.Response) error {
+	statusErr := fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return errors.RateLimitError(service.ID, method, time.Duration(retryAfter)*time.Second,
+			map[string]interface{}{"status_code": resp.StatusCode})
+	case resp.StatusCode >= 500:
+		return errors.UpstreamError(service.ID, method, resp.StatusCode, statusErr, nil)
+	}
+	return statusErr
+}
+
+// upstreamCancelled records an upstream call aborted by its context and
+// returns the error to report. The reason distinguishes timeouts from the
+// caller going away.
+func (mr *MCPRouter) upstreamCancelled(ctx context.Context, reqCtx *RequestContext, service *registry.RegisteredService, method string) error {
+	reason := "canceled"
+	if ctx.Err() == context.DeadlineExceeded {
+		reason = "deadline_exceeded"
+	}
+
+	mr.metrics.Inc("mcp_upstream_cancellations_total", "method", method, "service_id", service.ID, "reason", reason)
+
+	requestID := ""
+	if reqCtx != nil {
+		requestID = reqCtx.RequestID
+	}
+	mr.logger.Warn("upstream_request_cancelled",
+		"request_id", requestID,
+		"service_id", service.ID,
+		"method", method,
+		"reason", reason)
+
+	return fmt.Errorf("upstream request to service %s aborted: %w", service.ID, ctx.Err())
 }
 
 // Phase 4: Hot Plugin Reloading Handler Methods
@@ -2178,6 +2944,11 @@ func (mr *MCPRouter) handlePluginReload(ctx context.Context, reqCtx *RequestCont
 		}, true, nil
 	}
 
+	// The reloaded plugin may change the schemas validation was cached for
+	if mr.validator != nil {
+		mr.validator.InvalidateCache("plugin_reloaded")
+	}
+
 	return map[string]interface{}{
 		"reload_operation": result,
 		"success":          true,
@@ -0,0 +1,303 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRequestIDPropagation tests request ID assignment, propagation and echoing
+func TestRequestIDPropagation(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	var backendRequestID string
+	var backendMutex sync.Mutex
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		backendMutex.Lock()
+		backendRequestID = r.Header.Get("X-Request-ID")
+		backendMutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "request-id-backend", "tool_provider", backend.URL, nil)
+
+	mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+	t.Run("client supplied request ID is preserved and echoed", func(t *testing.T) {
+		req := newJSONRPCRequest(t, "tools/list", nil)
+		req.Header.Set("X-Request-ID", "client-correlation-123")
+		w := httptest.NewRecorder()
+
+		mr.handleMCPRequest(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("X-Request-ID"); got != "client-correlation-123" {
+			t.Errorf("expected echoed request ID client-correlation-123, got %q", got)
+		}
+
+		backendMutex.Lock()
+		defer backendMutex.Unlock()
+		if backendRequestID != "client-correlation-123" {
+			t.Errorf("expected backend to receive request ID client-correlation-123, got %q", backendRequestID)
+		}
+	})
+
+	t.Run("request ID is generated when absent", func(t *testing.T) {
+		req := newJSONRPCRequest(t, "tools/list", nil)
+		w := httptest.NewRecorder()
+
+		mr.handleMCPRequest(w, req)
+
+		if got := w.Header().Get("X-Request-ID"); len(got) != 36 {
+			t.Errorf("expected generated UUID request ID, got %q", got)
+		}
+	})
+
+	t.Run("invalid client request ID is replaced", func(t *testing.T) {
+		req := newJSONRPCRequest(t, "tools/list", nil)
+		req.Header.Set("X-Request-ID", "bad id\twith whitespace")
+		w := httptest.NewRecorder()
+
+		mr.handleMCPRequest(w, req)
+
+		got := w.Header().Get("X-Request-ID")
+		if got == "bad id\twith whitespace" || !IsValidRequestID(got) {
+			t.Errorf("expected invalid request ID to be replaced, got %q", got)
+		}
+	})
+
+	t.Run("request ID is echoed on error responses", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/mcp", bytes.NewBufferString("not json"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-ID", "failing-request")
+		w := httptest.NewRecorder()
+
+		mr.handleMCPRequest(w, req)
+
+		if got := w.Header().Get("X-Request-ID"); got != "failing-request" {
+			t.Errorf("expected echoed request ID on error, got %q", got)
+		}
+	})
+
+	t.Run("generated IDs are unique across concurrent requests", func(t *testing.T) {
+		const workers = 50
+		const requestsPerWorker = 20
+
+		var mutex sync.Mutex
+		seen := make(map[string]bool, workers*requestsPerWorker)
+		var wg sync.WaitGroup
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < requestsPerWorker; j++ {
+					req := newJSONRPCRequest(t, "tools/list", nil)
+					w := httptest.NewRecorder()
+					mr.handleMCPRequest(w, req)
+
+					id := w.Header().Get("X-Request-ID")
+					mutex.Lock()
+					if seen[id] {
+						t.Errorf("duplicate request ID generated: %s", id)
+					}
+					seen[id] = true
+					mutex.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(seen) != workers*requestsPerWorker {
+			t.Errorf("expected %d unique request IDs, got %d", workers*requestsPerWorker, len(seen))
+		}
+	})
+}
+
+// TestGenerateRequestID tests the supported request ID formats
+func TestGenerateRequestID(t *testing.T) {
+	t.Run("uuid format", func(t *testing.T) {
+		id := GenerateRequestID(RequestIDFormatUUID)
+		if len(id) != 36 || id[14] != '4' {
+			t.Errorf("expected UUIDv4, got %s", id)
+		}
+	})
+
+	t.Run("timestamp format", func(t *testing.T) {
+		first := GenerateRequestID(RequestIDFormatTimestamp)
+		second := GenerateRequestID(RequestIDFormatTimestamp)
+		if first == second {
+			t.Errorf("expected distinct timestamp IDs, got %s twice", first)
+		}
+		if first[:4] != "req_" {
+			t.Errorf("expected req_ prefix, got %s", first)
+		}
+	})
+}
+
+// TestPerServiceResponseValidation tests strict and lenient response validation overrides
+func TestPerServiceResponseValidation(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	// Both backends return an empty result, which fails response validation
+	malformed := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	}
+	strictBackend := newTestBackend(t, malformed)
+	lenientBackend := newTestBackend(t, malformed)
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "strict-backend", "tool_provider", strictBackend.URL,
+		map[string]interface{}{"response_validation": "strict"})
+	registerTestService(t, serviceRegistry, "lenient-backend", "prompt_provider", lenientBackend.URL,
+		map[string]interface{}{"response_validation": "lenient"})
+
+	mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+	t.Run("strict service returns internal error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "tools/list", nil))
+
+		var resp map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		rpcErr, ok := resp["error"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected JSON-RPC error for strict service, got %s", w.Body.String())
+		}
+		if code := int(rpcErr["code"].(float64)); code != -32603 {
+			t.Errorf("expected internal error code -32603, got %d", code)
+		}
+	})
+
+	t.Run("lenient service passes response through", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "prompts/list", nil))
+
+		var resp map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if _, hasError := resp["error"]; hasError {
+			t.Fatalf("expected lenient service to pass through, got %s", w.Body.String())
+		}
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("services without override follow global default", func(t *testing.T) {
+		service := &registry.RegisteredService{ID: "plain", Metadata: map[string]interface{}{}}
+
+		if mode := mr.responseValidationMode(service); mode != ResponseValidationDisabled {
+			t.Errorf("expected disabled mode with global validation off, got %s", mode)
+		}
+
+		mr.config.ValidateResponses = true
+		defer func() { mr.config.ValidateResponses = false }()
+
+		if mode := mr.responseValidationMode(service); mode != ResponseValidationLenient {
+			t.Errorf("expected lenient mode with global validation on, got %s", mode)
+		}
+	})
+}
+
+// Test helpers
+
+func newTestRegistry(logger logging.Logger, m metrics.Metrics) *registry.ServiceRegistry {
+	validator := validation.NewValidator(logger, m)
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	return registry.NewServiceRegistry(logger, m, validator, healthMgr)
+}
+
+// newTestBackend starts a backend that answers health checks and delegates MCP calls to handler
+func newTestBackend(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/", handler)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func registerTestService(t *testing.T, sr *registry.ServiceRegistry, name, serviceType, endpoint string, metadata map[string]interface{}) string {
+	t.Helper()
+
+	resp, err := sr.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+		Name:     name,
+		Type:     serviceType,
+		Version:  "1.0.0",
+		Endpoint: endpoint,
+		Protocol: "http",
+		Metadata: metadata,
+	})
+	if err != nil {
+		t.Fatalf("failed to register service %s: %v", name, err)
+	}
+	return resp.ServiceID
+}
+
+func newJSONRPCRequest(t *testing.T, method string, params interface{}) *http.Request {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		t.Errorf("failed to marshal request: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/mcp", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// mockMetrics implements a basic metrics interface for testing
+type mockMetrics struct{}
+
+func (m *mockMetrics) Inc(name string, labels ...string)                    {}
+func (m *mockMetrics) Add(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Set(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Observe(name string, value float64, labels ...string) {}
+func (m *mockMetrics) Time(name string, labels ...string) metrics.Timer     { return &mockTimer{} }
+func (m *mockMetrics) WithLabels(labels map[string]string) metrics.Metrics  { return m }
+func (m *mockMetrics) WithPrefix(prefix string) metrics.Metrics             { return m }
+func (m *mockMetrics) GetStats(name string) metrics.MetricStats             { return metrics.MetricStats{} }
+func (m *mockMetrics) GetAllStats() map[string]metrics.MetricStats {
+	return make(map[string]metrics.MetricStats)
+}
+
+type mockTimer struct{}
+
+func (t *mockTimer) Duration() time.Duration { return 0 }
+func (t *mockTimer) Stop() time.Duration     { return 0 }
@@ -0,0 +1,112 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/errors"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// authorizeMethod checks that the caller's capabilities grant the permission
+// the method policy requires for a request, before it is routed anywhere.
+// Holding the permission on any plugin passes; tools/call is checked against
+// the tool's owner by authorizeToolOwner once it is known.
+func (mr *MCPRouter) authorizeMethod(reqCtx *RequestContext, method string, params interface{}) error {
+	toolName := ""
+	if method == "tools/call" {
+		if paramMap, ok := params.(map[string]interface{}); ok {
+			toolName, _ = paramMap["name"].(string)
+		}
+	}
+
+	required := mr.config.MethodPolicy.RequiredPermission(method, toolName)
+	if required == rbac.PermissionNone {
+		return nil
+	}
+	if reqCtx.Capabilities != nil && reqCtx.Capabilities.HasAnyPermission(required) {
+		return nil
+	}
+
+	mr.metrics.Inc("rbac_method_denials_total", "method", method, "permission", required)
+	mr.logger.Warn("rbac_method_denied",
+		"request_id", reqCtx.RequestID,
+		"user_id", reqCtx.UserID,
+		"method", method,
+		"tool", toolName,
+		"required_permission", required)
+
+	context := map[string]interface{}{
+		"method":              method,
+		"required_permission": required,
+		"request_id":          reqCtx.RequestID,
+	}
+	message := fmt.Sprintf("Method %s requires %s permission", method, required)
+	if toolName != "" {
+		context["tool"] = toolName
+		message = fmt.Sprintf("Tool %s requires %s permission", toolName, required)
+	}
+	return errors.AuthorizationError("mcp_router", "authorize_method", message, context)
+}
+
+// CanCallTool reports whether capabilities grant the permission a tool
+// requires on the plugin or backend service that provides it
+func (mr *MCPRouter) CanCallTool(capabilities *rbac.ProcessedCapabilities, owner, toolName string) bool {
+	required := mr.config.MethodPolicy.RequiredPermission("tools/call", toolName)
+	if required == rbac.PermissionNone {
+		return true
+	}
+	return capabilities != nil && capabilities.HasPermission(owner, required)
+}
+
+// CallerCapabilities authenticates a request the way /mcp does and returns
+// what the caller may access
+func (mr *MCPRouter) CallerCapabilities(r *http.Request) (*rbac.ProcessedCapabilities, error) {
+	reqCtx := mr.createRequestContext(r)
+	if err := mr.resolveCapabilities(r, reqCtx); err != nil {
+		return nil, err
+	}
+	return reqCtx.Capabilities, nil
+}
+
+// authorizeToolOwner checks that the caller holds the permission a tool
+// requires on the plugin or backend service that provides it, so execute
+// permission on one plugin does not reach the tools of another
+func (mr *MCPRouter) authorizeToolOwner(reqCtx *RequestContext, owner, toolName string) error {
+	if mr.CanCallTool(reqCtx.Capabilities, owner, toolName) {
+		return nil
+	}
+	required := mr.config.MethodPolicy.RequiredPermission("tools/call", toolName)
+
+	mr.metrics.Inc("rbac_tool_denials_total", "owner", owner, "permission", required)
+	mr.logger.Warn("rbac_tool_denied",
+		"request_id", reqCtx.RequestID,
+		"user_id", reqCtx.UserID,
+		"owner", owner,
+		"tool", toolName,
+		"required_permission", required)
+
+	return errors.AuthorizationError("mcp_router", "authorize_tool",
+		fmt.Sprintf("Tool %s requires %s permission on %s", toolName, required, owner),
+		map[string]interface{}{
+			"tool":                toolName,
+			"owner":               owner,
+			"required_permission": required,
+			"request_id":          reqCtx.RequestID,
+		})
+}
+
+// authorizeServiceTool applies authorizeToolOwner to a tools/call forwarded
+// to a backend service; other methods pass
+func (mr *MCPRouter) authorizeServiceTool(reqCtx *RequestContext, service *registry.RegisteredService, mcpReq *mcpTypes.JSONRPCRequest) error {
+	if mcpReq.Method != "tools/call" {
+		return nil
+	}
+	toolName := ""
+	if params, ok := mcpReq.Params.(map[string]interface{}); ok {
+		toolName, _ = params["name"].(string)
+	}
+	return mr.authorizeToolOwner(reqCtx, service.Name, toolName)
+}
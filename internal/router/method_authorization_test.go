@@ -0,0 +1,168 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// TestMethodAuthorization tests that method policies are enforced against the caller's capabilities
+func TestMethodAuthorization(t *testing.T) {
+	logger := logging.New("test")
+
+	config := DefaultRouterConfig()
+	config.MethodPolicy = rbac.MethodPolicy{
+		Tools: map[string]string{"memory_delete": rbac.PermissionWrite},
+	}
+	mr := NewMCPRouterWithConfig(nil, &fakePluginHandler{}, nil, logger, &mockMetrics{}, nil, config)
+
+	// Capabilities of the built-in readonly role
+	readOnly := &RequestContext{
+		RequestID: "req-1",
+		UserID:    "reader",
+		Capabilities: &rbac.ProcessedCapabilities{
+			UserID:  "reader",
+			Roles:   []string{"readonly"},
+			Plugins: map[string]rbac.PluginPermission{"memory": {CanRead: true}},
+		},
+	}
+
+	t.Run("read-only user may list tools", func(t *testing.T) {
+		if err := mr.authorizeMethod(readOnly, "tools/list", nil); err != nil {
+			t.Errorf("expected tools/list to be allowed, got %v", err)
+		}
+		if err := mr.authorizeMethod(readOnly, "initialize", nil); err != nil {
+			t.Errorf("expected initialize to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("read-only user is denied a write tool", func(t *testing.T) {
+		err := mr.authorizeMethod(readOnly, "tools/call", map[string]interface{}{"name": "memory_delete"})
+		if err == nil {
+			t.Fatal("expected tools/call of a write tool to be denied")
+		}
+
+		w := httptest.NewRecorder()
+		mr.handleRoutingError(w, readOnly, err)
+		var resp struct {
+			Error struct {
+				Code int `json:"code"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		if resp.Error.Code != mcpTypes.ErrorCodeForbidden {
+			t.Errorf("expected permission denied code %d, got %d", mcpTypes.ErrorCodeForbidden, resp.Error.Code)
+		}
+	})
+
+	t.Run("unclassified methods require admin", func(t *testing.T) {
+		if err := mr.authorizeMethod(readOnly, "admin/shutdown", nil); err == nil {
+			t.Error("expected an unknown method to be denied to a read-only user")
+		}
+		admin := &RequestContext{Capabilities: &rbac.ProcessedCapabilities{
+			Plugins: map[string]rbac.PluginPermission{"*": {CanAdmin: true}},
+		}}
+		if err := mr.authorizeMethod(admin, "admin/shutdown", nil); err != nil {
+			t.Errorf("expected admin to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("tool permission is checked against the tool's owner", func(t *testing.T) {
+		handler := &fakePluginHandler{tools: map[string][]string{
+			"memory": {"memory_store"},
+			"git":    {"git_commit"},
+		}}
+		mr := NewMCPRouterWithConfig(nil, handler, nil, logger, &mockMetrics{}, nil, DefaultRouterConfig())
+		memoryUser := &RequestContext{
+			RequestID: "req-2",
+			Capabilities: &rbac.ProcessedCapabilities{
+				UserID:  "memory-user",
+				Plugins: map[string]rbac.PluginPermission{"memory": {CanRead: true, CanExecute: true}},
+			},
+		}
+
+		call := func(tool string) error {
+			params := map[string]interface{}{"name": tool}
+			if err := mr.authorizeMethod(memoryUser, "tools/call", params); err != nil {
+				t.Fatalf("expected execute on any plugin to pass the method check, got %v", err)
+			}
+			_, _, err := mr.handlePluginToolsCall(context.Background(), memoryUser, newToolsCallRequest(t, params))
+			return err
+		}
+		if err := call("memory_store"); err != nil {
+			t.Errorf("expected the user's own plugin tool to be allowed, got %v", err)
+		}
+		if err := call("git_commit"); err == nil {
+			t.Error("expected another plugin's tool to be denied")
+		}
+		if handler.lastTool != "memory_store" {
+			t.Errorf("expected the denied call not to reach the plugin, reached %s", handler.lastTool)
+		}
+
+		request := &mcpTypes.JSONRPCRequest{Method: "tools/call", Params: map[string]interface{}{"name": "search"}}
+		if err := mr.authorizeServiceTool(memoryUser, &registry.RegisteredService{Name: "search"}, request); err == nil {
+			t.Error("expected a backend service's tool to be denied without permission on the service")
+		}
+		if err := mr.authorizeServiceTool(memoryUser, &registry.RegisteredService{Name: "memory"}, request); err != nil {
+			t.Errorf("expected the permitted service's tool to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("tool visibility follows the owner's permissions", func(t *testing.T) {
+		executor := &rbac.ProcessedCapabilities{
+			UserID:  "executor",
+			Plugins: map[string]rbac.PluginPermission{"memory": {CanRead: true, CanExecute: true}},
+		}
+		if !mr.CanCallTool(executor, "memory", "memory_search") {
+			t.Error("expected a tool of an executable plugin to be callable")
+		}
+		if mr.CanCallTool(executor, "memory", "memory_delete") {
+			t.Error("expected a write tool to be hidden without write permission")
+		}
+		if mr.CanCallTool(readOnly.Capabilities, "memory", "memory_search") {
+			t.Error("expected tools to be hidden from a read-only user")
+		}
+		if mr.CanCallTool(executor, "git", "git_log") {
+			t.Error("expected a tool of another plugin to be hidden")
+		}
+		if mr.CanCallTool(nil, "memory", "memory_search") {
+			t.Error("expected a caller without capabilities to see no tools")
+		}
+	})
+
+	t.Run("required authentication without an RBAC engine denies", func(t *testing.T) {
+		handler := &fakePluginHandler{tools: map[string][]string{"memory": {"memory_store"}}}
+		config := DefaultRouterConfig()
+		config.RequireAuthentication = true
+		mr := NewMCPRouterWithConfig(nil, handler, nil, logger, &mockMetrics{}, nil, config)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+			"params": map[string]interface{}{"name": "memory_store"},
+		})
+		req := httptest.NewRequest("POST", "/mcp", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, req)
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		if _, hasError := resp["error"]; !hasError {
+			t.Errorf("expected anonymous call to be denied, got %v", resp)
+		}
+		if handler.lastTool != "" {
+			t.Errorf("expected denied call not to reach the plugin, reached %s", handler.lastTool)
+		}
+	})
+}
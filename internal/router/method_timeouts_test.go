@@ -0,0 +1,73 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestMethodTimeouts tests that per-method timeout overrides replace the default backend timeout
+func TestMethodTimeouts(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	const backendDelay = 300 * time.Millisecond
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(backendDelay)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[],"content":[]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "slow-backend", "tool_provider", backend.URL, nil)
+
+	config := DefaultRouterConfig()
+	config.DefaultTimeout = 100 * time.Millisecond
+	config.MethodTimeouts = map[string]time.Duration{
+		"tools/list": 20 * time.Millisecond,
+		"tools/call": 5 * time.Second,
+	}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	send := func(t *testing.T, method string) (map[string]json.RawMessage, time.Duration) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		start := time.Now()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, method, map[string]interface{}{"name": "slow"}))
+		elapsed := time.Since(start)
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return resp, elapsed
+	}
+
+	t.Run("short override times out quickly", func(t *testing.T) {
+		resp, elapsed := send(t, "tools/list")
+		if _, hasError := resp["error"]; !hasError {
+			t.Fatalf("expected tools/list to time out, got %s", resp["result"])
+		}
+		if elapsed >= backendDelay {
+			t.Errorf("expected tools/list to fail before the backend answered, took %s", elapsed)
+		}
+	})
+
+	t.Run("long override outlasts the default timeout", func(t *testing.T) {
+		resp, _ := send(t, "tools/call")
+		if _, hasError := resp["error"]; hasError {
+			t.Fatalf("expected tools/call to succeed under its long override, got %s", resp["error"])
+		}
+	})
+
+	t.Run("methods without an override use the default timeout", func(t *testing.T) {
+		if timeout := mr.methodTimeout("resources/list"); timeout != config.DefaultTimeout {
+			t.Errorf("expected default timeout %s, got %s", config.DefaultTimeout, timeout)
+		}
+	})
+}
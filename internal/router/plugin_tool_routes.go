@@ -0,0 +1,60 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// PluginToolRoute sends tools/call requests for tool names matching Pattern to
+// Plugin. Patterns are exact names or use * to match any run of characters,
+// such as "memory_*".
+type PluginToolRoute struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Plugin  string `yaml:"plugin" json:"plugin"`
+}
+
+// ValidatePluginToolRoutes checks that every route names a pattern and a plugin
+func ValidatePluginToolRoutes(routes []PluginToolRoute) error {
+	for i, route := range routes {
+		if strings.TrimSpace(route.Pattern) == "" {
+			return fmt.Errorf("route %d: pattern is required", i)
+		}
+		if strings.TrimSpace(route.Plugin) == "" {
+			return fmt.Errorf("route %d (%s): plugin is required", i, route.Pattern)
+		}
+	}
+	return nil
+}
+
+// routedPlugin returns the plugin of the first route matching the tool name
+func (mr *MCPRouter) routedPlugin(toolName string) (string, bool) {
+	for _, route := range mr.config.PluginToolRoutes {
+		if wildcardMatch(route.Pattern, toolName) {
+			return route.Plugin, true
+		}
+	}
+	return "", false
+}
+
+// unknownToolError is returned when no plugin serves a tools/call tool name
+func (mr *MCPRouter) unknownToolError(reqCtx *RequestContext, toolName string, availablePlugins []string) error {
+	mr.metrics.Inc("plugin_tool_unknown_calls_total")
+	mr.logger.Warn("plugin_tool_not_found",
+		"request_id", reqCtx.RequestID,
+		"tool", toolName,
+		"available_plugins", availablePlugins)
+
+	available := "none"
+	if len(availablePlugins) > 0 {
+		available = strings.Join(availablePlugins, ", ")
+	}
+	return errors.ValidationError("mcp_router", "resolve_plugin_tool",
+		fmt.Sprintf("Tool %s is not provided by any plugin; available plugins: %s", toolName, available),
+		map[string]interface{}{
+			"tool":       toolName,
+			"plugins":    availablePlugins,
+			"request_id": reqCtx.RequestID,
+		})
+}
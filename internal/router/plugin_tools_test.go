@@ -0,0 +1,195 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// TestPluginToolNameCollisions tests that plugins exposing the same tool name stay addressable
+func TestPluginToolNameCollisions(t *testing.T) {
+	logger := logging.New("test")
+	handler := &fakePluginHandler{
+		tools: map[string][]string{
+			"alpha": {"status", "alpha_only"},
+			"beta":  {"status"},
+		},
+	}
+	mr := NewMCPRouter(nil, handler, nil, logger, &mockMetrics{}, nil)
+	reqCtx := &RequestContext{RequestID: "test-request", Capabilities: executeAnything}
+
+	t.Run("colliding tools are namespaced in tools/list", func(t *testing.T) {
+		result, _, err := mr.handlePluginToolsList(context.Background(), reqCtx, &types.Request{Method: "tools/list"})
+		if err != nil {
+			t.Fatalf("tools/list failed: %v", err)
+		}
+
+		tools := result.(map[string]interface{})["tools"].([]mcpTypes.Tool)
+		names := make(map[string]bool)
+		for _, tool := range tools {
+			names[tool.Name] = true
+		}
+
+		for _, expected := range []string{"alpha.status", "beta.status", "alpha_only"} {
+			if !names[expected] {
+				t.Errorf("expected tool %s in listing, got %v", expected, names)
+			}
+		}
+		if names["status"] {
+			t.Error("expected unqualified colliding tool name to be namespaced")
+		}
+	})
+
+	testCases := []struct {
+		name           string
+		params         map[string]interface{}
+		expectedPlugin string
+		expectedTool   string
+	}{
+		{"qualified name", map[string]interface{}{"name": "beta.status"}, "beta", "status"},
+		{"explicit plugin field", map[string]interface{}{"name": "status", "plugin": "alpha"}, "alpha", "status"},
+		{"explicit plugin with qualified name", map[string]interface{}{"name": "beta.status", "plugin": "beta"}, "beta", "status"},
+		{"unique unqualified name", map[string]interface{}{"name": "alpha_only"}, "alpha", "alpha_only"},
+	}
+
+	for _, tc := range testCases {
+		t.Run("tools/call resolves "+tc.name, func(t *testing.T) {
+			if _, _, err := mr.handlePluginToolsCall(context.Background(), reqCtx, newToolsCallRequest(t, tc.params)); err != nil {
+				t.Fatalf("tools/call failed: %v", err)
+			}
+			if handler.lastPlugin != tc.expectedPlugin || handler.lastTool != tc.expectedTool {
+				t.Errorf("expected call to %s/%s, got %s/%s",
+					tc.expectedPlugin, tc.expectedTool, handler.lastPlugin, handler.lastTool)
+			}
+		})
+	}
+
+	t.Run("tools/call rejects ambiguous unqualified name", func(t *testing.T) {
+		handler.lastPlugin = ""
+		_, _, err := mr.handlePluginToolsCall(context.Background(), reqCtx, newToolsCallRequest(t, map[string]interface{}{"name": "status"}))
+		if err == nil {
+			t.Fatal("expected error for ambiguous tool name")
+		}
+		if handler.lastPlugin != "" {
+			t.Errorf("expected ambiguous call not to reach a plugin, reached %s", handler.lastPlugin)
+		}
+	})
+}
+
+func newToolsCallRequest(t *testing.T, params map[string]interface{}) *types.Request {
+	t.Helper()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("failed to marshal params: %v", err)
+	}
+	return &types.Request{Method: "tools/call", Params: raw}
+}
+
+// fakePluginHandler serves fixed tool lists and records tool invocations.
+// Methods not needed by the router tests are left to the embedded nil interface.
+type fakePluginHandler struct {
+	mcpTypes.PluginHandler
+	tools      map[string][]string
+	lastPlugin string
+	lastTool   string
+}
+
+// executeAnything lets test requests call the tools of every plugin
+var executeAnything = &rbac.ProcessedCapabilities{
+	UserID:  "anonymous",
+	Plugins: map[string]rbac.PluginPermission{"*": {CanRead: true, CanExecute: true}},
+}
+
+func (f *fakePluginHandler) ListAvailablePlugins(capabilities *rbac.ProcessedCapabilities) []string {
+	names := make([]string, 0, len(f.tools))
+	for name := range f.tools {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (f *fakePluginHandler) GetPluginTools(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]mcpTypes.Tool, error) {
+	var tools []mcpTypes.Tool
+	for _, name := range f.tools[pluginName] {
+		tools = append(tools, mcpTypes.Tool{Name: name, Description: pluginName + " " + name})
+	}
+	return tools, nil
+}
+
+func (f *fakePluginHandler) InvokePlugin(ctx context.Context, pluginName, toolName string, params map[string]interface{}, capabilities *rbac.ProcessedCapabilities) (*mcpTypes.ToolResult, error) {
+	f.lastPlugin = pluginName
+	f.lastTool = toolName
+	return &mcpTypes.ToolResult{}, nil
+}
+
+// TestPluginToolRoutes tests that tools/call routing honours the plugin field, the routing table and rejects unknown tools
+func TestPluginToolRoutes(t *testing.T) {
+	logger := logging.New("test")
+	handler := &fakePluginHandler{
+		tools: map[string][]string{
+			"alpha": {"status", "alpha_only"},
+			"beta":  {"status"},
+		},
+	}
+	config := DefaultRouterConfig()
+	config.PluginToolRoutes = []PluginToolRoute{
+		{Pattern: "status", Plugin: "beta"},
+		{Pattern: "gamma_*", Plugin: "gamma"},
+	}
+	mr := NewMCPRouterWithConfig(nil, handler, nil, logger, &mockMetrics{}, nil, config)
+	reqCtx := &RequestContext{RequestID: "test-request", Capabilities: executeAnything}
+
+	testCases := []struct {
+		name           string
+		params         map[string]interface{}
+		expectedPlugin string
+		expectedTool   string
+	}{
+		{"explicit plugin overrides a route", map[string]interface{}{"name": "status", "plugin": "alpha"}, "alpha", "status"},
+		{"exact route settles a colliding name", map[string]interface{}{"name": "status"}, "beta", "status"},
+		{"wildcard route reaches an unlisted tool", map[string]interface{}{"name": "gamma_sync"}, "gamma", "gamma_sync"},
+		{"unrouted tool found in plugin tool lists", map[string]interface{}{"name": "alpha_only"}, "alpha", "alpha_only"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := mr.handlePluginToolsCall(context.Background(), reqCtx, newToolsCallRequest(t, tc.params)); err != nil {
+				t.Fatalf("tools/call failed: %v", err)
+			}
+			if handler.lastPlugin != tc.expectedPlugin || handler.lastTool != tc.expectedTool {
+				t.Errorf("expected call to %s/%s, got %s/%s",
+					tc.expectedPlugin, tc.expectedTool, handler.lastPlugin, handler.lastTool)
+			}
+		})
+	}
+
+	t.Run("unknown tool lists available plugins", func(t *testing.T) {
+		handler.lastPlugin = ""
+		_, _, err := mr.handlePluginToolsCall(context.Background(), reqCtx, newToolsCallRequest(t, map[string]interface{}{"name": "memory_store"}))
+		if err == nil {
+			t.Fatal("expected error for a tool no plugin provides")
+		}
+		if !strings.Contains(err.Error(), "available plugins: alpha, beta") {
+			t.Errorf("expected error to list the available plugins, got %v", err)
+		}
+		if handler.lastPlugin != "" {
+			t.Errorf("expected unknown tool not to reach a plugin, reached %s", handler.lastPlugin)
+		}
+	})
+
+	t.Run("routes require a pattern and a plugin", func(t *testing.T) {
+		if err := ValidatePluginToolRoutes([]PluginToolRoute{{Pattern: "memory_*"}}); err == nil {
+			t.Error("expected a route without a plugin to be rejected")
+		}
+		if err := ValidatePluginToolRoutes(config.PluginToolRoutes); err != nil {
+			t.Errorf("expected valid routes, got %v", err)
+		}
+	})
+}
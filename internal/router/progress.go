@@ -0,0 +1,141 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/internal/registry"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/plugins"
+)
+
+// progressTokenFromParams reads params._meta.progressToken, which MCP allows
+// to be a string or a number
+func progressTokenFromParams(params interface{}) interface{} {
+	paramsMap, _ := params.(map[string]interface{})
+	meta, _ := paramsMap["_meta"].(map[string]interface{})
+	switch token := meta["progressToken"].(type) {
+	case string, float64:
+		return token
+	}
+	return nil
+}
+
+// relayProgress sends a notifications/progress message to the streams of the
+// user or session that issued the request. Only progress carrying the token
+// of the request being served is relayed, so a backend cannot report
+// progress against another client's request.
+func (mr *MCPRouter) relayProgress(reqCtx *RequestContext, source string, token interface{}, params interface{}) {
+	if reqCtx == nil || reqCtx.ProgressToken == nil || !reflect.DeepEqual(token, reqCtx.ProgressToken) {
+		mr.metrics.Inc("mcp_progress_notifications_dropped_total", "source", source)
+		requestID := ""
+		if reqCtx != nil {
+			requestID = reqCtx.RequestID
+		}
+		mr.logger.Debug("progress_notification_dropped",
+			"request_id", requestID,
+			"source", source,
+			"progress_token", token)
+		return
+	}
+
+	mr.metrics.Inc("mcp_progress_notifications_total", "method", reqCtx.Method, "source", source)
+	if mr.notify != nil {
+		mr.notify("notifications/progress", params, []Subscriber{requestSubscriber(reqCtx)})
+	}
+}
+
+// pluginProgressContext lets a plugin tool call report progress with
+// plugins.ReportProgress when the client asked for it
+func (mr *MCPRouter) pluginProgressContext(ctx context.Context, reqCtx *RequestContext, pluginName string) context.Context {
+	if reqCtx.ProgressToken == nil {
+		return ctx
+	}
+	return plugins.WithProgress(ctx, func(progress, total float64, message string) {
+		mr.relayProgress(reqCtx, pluginName, reqCtx.ProgressToken, types.ProgressNotification{
+			ProgressToken: reqCtx.ProgressToken,
+			Progress:      progress,
+			Total:         total,
+			Message:       message,
+		})
+	})
+}
+
+// isEventStream reports whether a backend answered with Server-Sent Events
+func isEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// readEventStream reads a streamed backend response: JSON-RPC messages in
+// SSE data fields, with notifications sent while the request runs and the
+// response last. Progress notifications are relayed as they arrive.
+func (mr *MCPRouter) readEventStream(reqCtx *RequestContext, service *registry.RegisteredService, body io.Reader, mcpResp *mcpTypes.JSONRPCResponse) error {
+	reader := bufio.NewReader(body)
+	var data strings.Builder
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		// Event names, ids, retry hints and comments are not needed
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+
+		// A blank line, or the end of the stream, completes an event
+		if (line == "" || readErr != nil) && data.Len() > 0 {
+			done, err := mr.dispatchStreamMessage(reqCtx, service, []byte(data.String()), mcpResp)
+			data.Reset()
+			if err != nil || done {
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			return fmt.Errorf("event stream ended without a response")
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// dispatchStreamMessage handles one message of a streamed response,
+// reporting whether it was the response that ends the stream
+func (mr *MCPRouter) dispatchStreamMessage(reqCtx *RequestContext, service *registry.RegisteredService, data []byte, mcpResp *mcpTypes.JSONRPCResponse) (bool, error) {
+	var message struct {
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return false, fmt.Errorf("invalid message in event stream: %w", err)
+	}
+
+	switch message.Method {
+	case "":
+		return true, json.Unmarshal(data, mcpResp)
+	case "notifications/progress":
+		mr.relayProgress(reqCtx, service.ID, message.Params["progressToken"], message.Params)
+	default:
+		requestID := ""
+		if reqCtx != nil {
+			requestID = reqCtx.RequestID
+		}
+		mr.logger.Debug("backend_stream_message_ignored",
+			"request_id", requestID,
+			"service_id", service.ID,
+			"message_method", message.Method)
+	}
+	return false, nil
+}
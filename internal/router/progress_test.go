@@ -0,0 +1,170 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+type publishedNotification struct {
+	method   string
+	params   interface{}
+	audience []Subscriber
+}
+
+// TestProgressNotificationRelay tests that progress streamed by a backend during a long tool call reaches clients
+func TestProgressNotificationRelay(t *testing.T) {
+	logger := logging.New("test")
+	recordingMetrics := &cancellationRecordingMetrics{}
+
+	release := make(chan struct{})
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			t.Errorf("expected the gateway to accept an event stream, got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		event := func(message string) {
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", message)
+			w.(http.Flusher).Flush()
+		}
+
+		event(`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"job-1","progress":1,"total":2}}`)
+		event(`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"someone-else","progress":1}}`)
+		<-release
+		event(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"done"}]}}`)
+	})
+
+	serviceRegistry := newTestRegistry(logger, recordingMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "long-jobs", "tool_provider", backend.URL, nil)
+
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, recordingMetrics, nil, DefaultRouterConfig())
+	notifications := make(chan publishedNotification, 10)
+	mr.SetNotificationPublisher(func(method string, params interface{}, audience []Subscriber) {
+		notifications <- publishedNotification{method, params, audience}
+	})
+
+	router := mux.NewRouter()
+	mr.SetupRoutes(router)
+
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(w, newJSONRPCRequest(t, "tools/call", map[string]interface{}{
+			"name":      "long_job",
+			"arguments": map[string]interface{}{},
+			"_meta":     map[string]interface{}{"progressToken": "job-1"},
+		}))
+	}()
+
+	select {
+	case n := <-notifications:
+		params, _ := n.params.(map[string]interface{})
+		if n.method != "notifications/progress" || params["progressToken"] != "job-1" || params["progress"] != 1.0 {
+			t.Errorf("expected progress 1 for job-1, got %s %v", n.method, n.params)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a progress notification while the call runs")
+	}
+	select {
+	case <-done:
+		t.Fatal("expected the call to still be running when progress arrived")
+	default:
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("call did not complete")
+	}
+
+	var resp struct {
+		Result map[string]interface{} `json:"result"`
+		Error  interface{}            `json:"error"`
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error != nil || resp.Result["content"] == nil {
+		t.Fatalf("expected the streamed result, got %s", w.Body.String())
+	}
+
+	select {
+	case n := <-notifications:
+		t.Errorf("expected progress for another token to be dropped, got %v", n.params)
+	default:
+	}
+	if got := recordingMetrics.count("mcp_progress_notifications_dropped_total"); got != 1 {
+		t.Errorf("expected one dropped progress notification, got %d", got)
+	}
+}
+
+// progressPluginHandler reports progress from inside a plugin tool call
+type progressPluginHandler struct {
+	fakePluginHandler
+}
+
+func (p *progressPluginHandler) InvokePlugin(ctx context.Context, pluginName, toolName string, params map[string]interface{}, capabilities *rbac.ProcessedCapabilities) (*mcpTypes.ToolResult, error) {
+	plugins.ReportProgress(ctx, 5, 10, "halfway")
+	return p.fakePluginHandler.InvokePlugin(ctx, pluginName, toolName, params, capabilities)
+}
+
+// TestPluginProgressNotifications tests that progress reported by a plugin is relayed with the request's token
+func TestPluginProgressNotifications(t *testing.T) {
+	handler := &progressPluginHandler{fakePluginHandler{tools: map[string][]string{"indexer": {"reindex"}}}}
+	mr := NewMCPRouter(nil, handler, nil, logging.New("test"), &mockMetrics{}, nil)
+
+	var published []publishedNotification
+	mr.SetNotificationPublisher(func(method string, params interface{}, audience []Subscriber) {
+		published = append(published, publishedNotification{method, params, audience})
+	})
+
+	call := func(token interface{}) {
+		t.Helper()
+		params, _ := json.Marshal(map[string]interface{}{"name": "reindex"})
+		reqCtx := &RequestContext{
+			RequestID:     "test-request",
+			Method:        "tools/call",
+			SessionID:     "session-1",
+			ProgressToken: token,
+			Capabilities: &rbac.ProcessedCapabilities{
+				UserID:  "alice",
+				Plugins: map[string]rbac.PluginPermission{"indexer": {CanExecute: true}},
+			},
+		}
+		if _, _, err := mr.handlePluginToolsCall(context.Background(), reqCtx, &types.Request{Method: "tools/call", Params: params}); err != nil {
+			t.Fatalf("tools/call failed: %v", err)
+		}
+	}
+
+	call(42.0)
+	if len(published) != 1 || published[0].method != "notifications/progress" {
+		t.Fatalf("expected one progress notification, got %+v", published)
+	}
+	progress, _ := published[0].params.(types.ProgressNotification)
+	if progress.ProgressToken != 42.0 || progress.Progress != 5 || progress.Total != 10 || progress.Message != "halfway" {
+		t.Errorf("unexpected progress notification %+v", published[0].params)
+	}
+	if audience := published[0].audience; len(audience) != 1 || audience[0] != (Subscriber{UserID: "alice", SessionID: "session-1"}) {
+		t.Errorf("expected progress addressed to the caller's session only, got %+v", audience)
+	}
+
+	call(nil)
+	if len(published) != 1 {
+		t.Errorf("expected no progress without a progressToken, got %+v", published)
+	}
+}
@@ -0,0 +1,31 @@
+package router
+
+// RegionAffinityConfig selects the region and zone whose backends a request
+// prefers. Backends declare theirs in the region and zone registration metadata.
+type RegionAffinityConfig struct {
+	RegionHeader string `yaml:"region_header"` // Request header naming the caller's region; empty uses X-Client-Region
+	ZoneHeader   string `yaml:"zone_header"`   // Request header naming the caller's zone; empty uses X-Client-Zone
+	Region       string `yaml:"region"`        // Region preferred when the request names none, usually the gateway's own
+	Zone         string `yaml:"zone"`          // Zone preferred when the request names none
+}
+
+func defaultRegionAffinityConfig() RegionAffinityConfig {
+	return RegionAffinityConfig{
+		RegionHeader: "X-Client-Region",
+		ZoneHeader:   "X-Client-Zone",
+	}
+}
+
+// preferredLocality returns the region and zone to prefer for a request: the
+// request headers first, then the client's preferences, then the configured
+// defaults. A zone is only taken from the same source as its region.
+func (mr *MCPRouter) preferredLocality(reqCtx *RequestContext) (region, zone string) {
+	if region = reqCtx.InboundHeaders.Get(mr.config.RegionAffinity.RegionHeader); region != "" {
+		return region, reqCtx.InboundHeaders.Get(mr.config.RegionAffinity.ZoneHeader)
+	}
+	if region, _ = reqCtx.Preferences["region"].(string); region != "" {
+		zone, _ = reqCtx.Preferences["zone"].(string)
+		return region, zone
+	}
+	return mr.config.RegionAffinity.Region, mr.config.RegionAffinity.Zone
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// DefaultDeadlineHeader carries the client's deadline for a request
+const DefaultDeadlineHeader = "X-Request-Deadline"
+
+// RequestDeadlineConfig lets clients bound how long the gateway works on their
+// request. The header holds an RFC 3339 timestamp or a duration such as
+// "1.5s"; either can only shorten the gateway's own timeout.
+type RequestDeadlineConfig struct {
+	Header      string        `yaml:"header"`       // Empty uses X-Request-Deadline
+	MaxDuration time.Duration `yaml:"max_duration"` // Longest deadline honoured; 0 uses the method's timeout
+}
+
+// parseRequestDeadline parses a deadline header value relative to now
+func parseRequestDeadline(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(duration), nil
+	}
+	if deadline, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return deadline, nil
+	}
+	return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or a duration such as 1.5s, got %q", value)
+}
+
+// requestDeadlineContext derives the context a request is routed with from
+// the client's deadline header, bounded by the gateway's maximum. A request
+// whose deadline has already passed is rejected without being routed.
+func (mr *MCPRouter) requestDeadlineContext(r *http.Request, reqCtx *RequestContext) (context.Context, context.CancelFunc, error) {
+	value := r.Header.Get(mr.config.RequestDeadline.Header)
+	if value == "" {
+		return r.Context(), func() {}, nil
+	}
+
+	now := time.Now()
+	deadline, err := parseRequestDeadline(value, now)
+	if err != nil {
+		return nil, nil, errors.ValidationError("mcp_router", "request_deadline",
+			fmt.Sprintf("Invalid %s header: %v", mr.config.RequestDeadline.Header, err),
+			map[string]interface{}{"request_id": reqCtx.RequestID})
+	}
+
+	if !deadline.After(now) {
+		mr.metrics.Inc("request_deadline_expired_total", "method", reqCtx.Method)
+		mr.logger.Warn("request_deadline_expired",
+			"request_id", reqCtx.RequestID,
+			"method", reqCtx.Method,
+			"deadline", deadline,
+			"expired_for", now.Sub(deadline))
+
+		timeoutErr := errors.TimeoutError("mcp_router", "request_deadline", 0,
+			map[string]interface{}{
+				"deadline":   deadline.UTC().Format(time.RFC3339Nano),
+				"request_id": reqCtx.RequestID,
+			})
+		timeoutErr.Message = fmt.Sprintf("Request deadline %s has already passed", deadline.UTC().Format(time.RFC3339Nano))
+		return nil, nil, timeoutErr
+	}
+
+	maxDuration := mr.config.RequestDeadline.MaxDuration
+	if maxDuration <= 0 {
+		maxDuration = mr.methodTimeout(reqCtx.Method)
+	}
+	if limit := now.Add(maxDuration); maxDuration > 0 && deadline.After(limit) {
+		deadline = limit
+	}
+
+	mr.logger.Debug("request_deadline_applied",
+		"request_id", reqCtx.RequestID,
+		"method", reqCtx.Method,
+		"remaining", deadline.Sub(now))
+
+	ctx, cancel := context.WithDeadline(r.Context(), deadline)
+	return ctx, cancel, nil
+}
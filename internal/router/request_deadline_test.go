@@ -0,0 +1,132 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestRequestDeadline tests that a client deadline header shortens the backend timeout and rejects expired requests
+func TestRequestDeadline(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	const backendDelay = 300 * time.Millisecond
+	var backendCalls atomic.Int32
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls.Add(1)
+		select {
+		case <-time.After(backendDelay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "slow-backend", "tool_provider", backend.URL, nil)
+
+	config := DefaultRouterConfig()
+	config.DefaultTimeout = 5 * time.Second
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	send := func(t *testing.T, deadline string) (map[string]json.RawMessage, time.Duration) {
+		t.Helper()
+		req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "slow"})
+		if deadline != "" {
+			req.Header.Set(DefaultDeadlineHeader, deadline)
+		}
+
+		w := httptest.NewRecorder()
+		start := time.Now()
+		mr.handleMCPRequest(w, req)
+		elapsed := time.Since(start)
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return resp, elapsed
+	}
+
+	errorCode := func(t *testing.T, resp map[string]json.RawMessage) int {
+		t.Helper()
+		var rpcErr struct {
+			Code int `json:"code"`
+		}
+		if err := json.Unmarshal(resp["error"], &rpcErr); err != nil {
+			t.Fatalf("expected an error response, got %v", resp)
+		}
+		return rpcErr.Code
+	}
+
+	t.Run("without the header the gateway timeout applies", func(t *testing.T) {
+		if resp, _ := send(t, ""); resp["error"] != nil {
+			t.Fatalf("expected the slow backend to answer, got %s", resp["error"])
+		}
+	})
+
+	t.Run("duration header shortens the timeout", func(t *testing.T) {
+		resp, elapsed := send(t, "50ms")
+		if code := errorCode(t, resp); code != mcpTypes.ErrorCodeTimeout {
+			t.Errorf("expected timeout code %d, got %d", mcpTypes.ErrorCodeTimeout, code)
+		}
+		if elapsed >= backendDelay {
+			t.Errorf("expected the call to end at the client deadline, took %s", elapsed)
+		}
+	})
+
+	t.Run("absolute deadline shortens the timeout", func(t *testing.T) {
+		resp, elapsed := send(t, time.Now().Add(50*time.Millisecond).UTC().Format(time.RFC3339Nano))
+		if code := errorCode(t, resp); code != mcpTypes.ErrorCodeTimeout {
+			t.Errorf("expected timeout code %d, got %d", mcpTypes.ErrorCodeTimeout, code)
+		}
+		if elapsed >= backendDelay {
+			t.Errorf("expected the call to end at the client deadline, took %s", elapsed)
+		}
+	})
+
+	t.Run("past deadline is rejected without calling the backend", func(t *testing.T) {
+		backendCalls.Store(0)
+		resp, elapsed := send(t, time.Now().Add(-time.Second).UTC().Format(time.RFC3339))
+		if code := errorCode(t, resp); code != mcpTypes.ErrorCodeTimeout {
+			t.Errorf("expected timeout code %d, got %d", mcpTypes.ErrorCodeTimeout, code)
+		}
+		if backendCalls.Load() != 0 {
+			t.Error("expected an expired request not to reach the backend")
+		}
+		if elapsed >= 50*time.Millisecond {
+			t.Errorf("expected an immediate rejection, took %s", elapsed)
+		}
+	})
+
+	t.Run("malformed header is rejected", func(t *testing.T) {
+		resp, _ := send(t, "tomorrow")
+		if code := errorCode(t, resp); code != mcpTypes.ErrorCodeInvalidParams {
+			t.Errorf("expected invalid params code %d, got %d", mcpTypes.ErrorCodeInvalidParams, code)
+		}
+	})
+
+	t.Run("deadline is bounded by the gateway maximum", func(t *testing.T) {
+		bounded := DefaultRouterConfig()
+		bounded.RequestDeadline.MaxDuration = 50 * time.Millisecond
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, bounded)
+
+		req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "slow"})
+		req.Header.Set(DefaultDeadlineHeader, "1h")
+		w := httptest.NewRecorder()
+		start := time.Now()
+		mr.handleMCPRequest(w, req)
+		if elapsed := time.Since(start); elapsed >= backendDelay {
+			t.Errorf("expected the gateway maximum to cap the deadline, took %s", elapsed)
+		}
+	})
+}
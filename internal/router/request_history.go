@@ -0,0 +1,157 @@
+package router
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestHistorySize      = 100
+	maxRequestHistorySize          = 10000
+	defaultRequestHistoryParamSize = 1024
+)
+
+// RequestHistoryConfig configures an in-memory record of the most recent MCP
+// requests for debugging intermittent failures through the admin API. Params
+// are redacted with the body logging redact paths.
+type RequestHistoryConfig struct {
+	Enabled       bool `yaml:"enabled" json:"enabled"`
+	Size          int  `yaml:"size" json:"size"`                       // Requests kept; 0 uses 100
+	MaxParamsSize int  `yaml:"max_params_size" json:"max_params_size"` // Params truncated to this many bytes; 0 uses 1024
+}
+
+// Validate checks that the history size is bounded
+func (c RequestHistoryConfig) Validate() error {
+	if c.Size < 0 || c.Size > maxRequestHistorySize {
+		return fmt.Errorf("size must be between 0 and %d, got %d", maxRequestHistorySize, c.Size)
+	}
+	if c.MaxParamsSize < 0 {
+		return fmt.Errorf("max params size must not be negative, got %d", c.MaxParamsSize)
+	}
+	return nil
+}
+
+// RecentRequest is one completed request in the request history
+type RecentRequest struct {
+	Timestamp  time.Time `json:"timestamp"`
+	RequestID  string    `json:"request_id"`
+	Method     string    `json:"method"`
+	Status     string    `json:"status"` // success or error
+	DurationMS float64   `json:"duration_ms"`
+	ServiceID  string    `json:"service_id,omitempty"`
+	UserID     string    `json:"user_id,omitempty"`
+	ErrorCode  int       `json:"error_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	ErrorRef   string    `json:"error_ref,omitempty"`
+	Params     string    `json:"params,omitempty"`
+}
+
+// RequestHistoryFilter selects requests from the history; empty fields match all
+type RequestHistoryFilter struct {
+	Status string
+	Method string
+	Limit  int
+}
+
+// requestHistory is a fixed-size ring buffer of recent requests
+type requestHistory struct {
+	mutex   sync.Mutex
+	entries []RecentRequest
+	next    int
+	full    bool
+}
+
+func newRequestHistory(size int) *requestHistory {
+	if size <= 0 {
+		size = defaultRequestHistorySize
+	}
+	return &requestHistory{entries: make([]RecentRequest, size)}
+}
+
+// add records a request, overwriting the oldest once the buffer is full
+func (h *requestHistory) add(entry RecentRequest) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the requests matching filter, newest first
+func (h *requestHistory) list(filter RequestHistoryFilter) []RecentRequest {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	count := h.next
+	if h.full {
+		count = len(h.entries)
+	}
+
+	result := make([]RecentRequest, 0, count)
+	for i := 0; i < count; i++ {
+		entry := h.entries[(h.next-1-i+len(h.entries))%len(h.entries)]
+		if filter.Status != "" && entry.Status != filter.Status {
+			continue
+		}
+		if filter.Method != "" && entry.Method != filter.Method {
+			continue
+		}
+		result = append(result, entry)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result
+}
+
+// RecentRequests returns recorded requests matching filter, newest first, and
+// false when the request history is disabled
+func (mr *MCPRouter) RecentRequests(filter RequestHistoryFilter) ([]RecentRequest, bool) {
+	if mr.history == nil {
+		return nil, false
+	}
+	return mr.history.list(filter), true
+}
+
+// captureHistoryParams keeps the redacted request params for the history entry
+func (mr *MCPRouter) captureHistoryParams(reqCtx *RequestContext, params interface{}) {
+	if mr.history == nil || params == nil {
+		return
+	}
+
+	maxSize := mr.config.RequestHistory.MaxParamsSize
+	if maxSize == 0 {
+		maxSize = defaultRequestHistoryParamSize
+	}
+	reqCtx.historyParams = formatRedactedBody(params, mr.config.BodyLogging.RedactPaths, maxSize)
+}
+
+// recordRequestHistory adds a completed request to the history
+func (mr *MCPRouter) recordRequestHistory(reqCtx *RequestContext, code int, errorRef string, err error) {
+	if mr.history == nil {
+		return
+	}
+
+	entry := RecentRequest{
+		Timestamp:  reqCtx.StartTime.UTC(),
+		RequestID:  reqCtx.RequestID,
+		Method:     reqCtx.Method,
+		Status:     "success",
+		DurationMS: float64(time.Since(reqCtx.StartTime).Microseconds()) / 1000,
+		ServiceID:  reqCtx.ServiceID,
+		UserID:     reqCtx.UserID,
+		Params:     reqCtx.historyParams,
+	}
+	if err != nil {
+		entry.Status = "error"
+		entry.ErrorCode = code
+		entry.Error = err.Error()
+		entry.ErrorRef = errorRef
+	}
+
+	mr.history.add(entry)
+}
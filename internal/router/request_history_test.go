@@ -0,0 +1,81 @@
+package router
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestRequestHistory tests that completed requests are recorded, filtered and capped
+func TestRequestHistory(t *testing.T) {
+	logger := logging.New("test")
+	handler := &fakePluginHandler{tools: map[string][]string{"memory": {"memory_list"}}}
+
+	config := DefaultRouterConfig()
+	config.RequestHistory = RequestHistoryConfig{Enabled: true, Size: 3}
+	mr := NewMCPRouterWithConfig(nil, handler, nil, logger, &mockMetrics{}, nil, config)
+
+	send := func(method string, params interface{}) {
+		mr.handleMCPRequest(httptest.NewRecorder(), newJSONRPCRequest(t, method, params))
+	}
+
+	send("tools/list", nil)
+	send("tools/call", map[string]interface{}{
+		"name":      "memory_list",
+		"arguments": map[string]interface{}{"api_key": "secret-value"},
+	})
+	send("resources/read", map[string]interface{}{})
+
+	t.Run("recent requests are recorded newest first", func(t *testing.T) {
+		requests, enabled := mr.RecentRequests(RequestHistoryFilter{})
+		if !enabled || len(requests) != 3 {
+			t.Fatalf("expected 3 recorded requests, got %d (enabled=%v)", len(requests), enabled)
+		}
+		if requests[2].Method != "tools/list" || requests[2].Status != "success" {
+			t.Errorf("expected the oldest entry to be a successful tools/list, got %+v", requests[2])
+		}
+		if requests[0].Status != "error" || requests[0].Error == "" || requests[0].ErrorCode == 0 {
+			t.Errorf("expected the newest entry to record the failed call, got %+v", requests[0])
+		}
+		if strings.Contains(requests[1].Params, "secret-value") || !strings.Contains(requests[1].Params, RedactedValue) {
+			t.Errorf("expected params to be redacted, got %s", requests[1].Params)
+		}
+	})
+
+	t.Run("filtering by status and method", func(t *testing.T) {
+		failed, _ := mr.RecentRequests(RequestHistoryFilter{Status: "error"})
+		if len(failed) != 1 || failed[0].Method != "resources/read" {
+			t.Errorf("expected one failed resources/read, got %+v", failed)
+		}
+		calls, _ := mr.RecentRequests(RequestHistoryFilter{Method: "tools/call"})
+		if len(calls) != 1 || calls[0].Status != "success" {
+			t.Errorf("expected one successful tools/call, got %+v", calls)
+		}
+		limited, _ := mr.RecentRequests(RequestHistoryFilter{Limit: 2})
+		if len(limited) != 2 {
+			t.Errorf("expected limit to return 2 requests, got %d", len(limited))
+		}
+	})
+
+	t.Run("buffer caps at its size", func(t *testing.T) {
+		send("tools/list", nil)
+		send("tools/list", nil)
+
+		requests, _ := mr.RecentRequests(RequestHistoryFilter{})
+		if len(requests) != 3 {
+			t.Fatalf("expected the buffer to hold 3 requests, got %d", len(requests))
+		}
+		if requests[2].Status != "error" {
+			t.Errorf("expected the oldest requests to be overwritten, oldest is %+v", requests[2])
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		disabled := NewMCPRouterWithConfig(nil, handler, nil, logger, &mockMetrics{}, nil, DefaultRouterConfig())
+		if _, enabled := disabled.RecentRequests(RequestHistoryFilter{}); enabled {
+			t.Error("expected request history to be disabled by default")
+		}
+	})
+}
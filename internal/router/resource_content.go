@@ -0,0 +1,237 @@
+package router
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/errors"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+const defaultBinaryMimeType = "application/octet-stream"
+
+// pluginResourceContent converts a plugin ReadResource result into MCP resource
+// content. Byte slices become base64 blobs, ResourceContent values and maps
+// carrying a blob pass through, strings become text and anything else is
+// encoded as JSON text. declaredMimeType fills in a missing MIME type.
+func pluginResourceContent(uri string, result interface{}, declaredMimeType string) (types.ResourceContent, error) {
+	content := types.ResourceContent{URI: uri}
+	genericMimeType := false
+
+	switch v := result.(type) {
+	case []byte:
+		content.Blob = base64.StdEncoding.EncodeToString(v)
+		content.MimeType = defaultBinaryMimeType
+		genericMimeType = true
+	case types.ResourceContent:
+		content = v
+	case *types.ResourceContent:
+		if v == nil {
+			return content, fmt.Errorf("resource %s returned nil content", uri)
+		}
+		content = *v
+	case string:
+		content.Text = v
+		content.MimeType = "text/plain"
+		genericMimeType = true
+	default:
+		if m, ok := v.(map[string]interface{}); ok {
+			if _, hasBlob := m["blob"]; hasBlob {
+				raw, err := json.Marshal(m)
+				if err != nil {
+					return content, fmt.Errorf("failed to encode resource %s content: %w", uri, err)
+				}
+				if err := json.Unmarshal(raw, &content); err != nil {
+					return content, fmt.Errorf("invalid blob content for resource %s: %w", uri, err)
+				}
+				break
+			}
+		}
+
+		content.MimeType = "application/json"
+		if jsonBytes, err := json.Marshal(v); err == nil {
+			content.Text = string(jsonBytes)
+		} else {
+			// Fallback to string representation
+			content.Text = fmt.Sprintf("%v", v)
+		}
+	}
+
+	if content.URI == "" {
+		content.URI = uri
+	}
+
+	// Raw bytes and strings carry no type of their own, so the declared one wins
+	if declaredMimeType != "" && (content.MimeType == "" || genericMimeType) {
+		content.MimeType = declaredMimeType
+	}
+
+	return content, nil
+}
+
+// checkResourceMimeType rejects content whose MIME type does not match the type
+// declared in the resource definition. Parameters such as charset are ignored
+// and a declared type like image/* matches any image subtype.
+func checkResourceMimeType(content *types.ResourceContent, declaredMimeType string) error {
+	if declaredMimeType == "" || content.MimeType == "" {
+		return nil
+	}
+
+	declared, _, err := mime.ParseMediaType(declaredMimeType)
+	if err != nil {
+		return fmt.Errorf("resource %s declares invalid MIME type %q: %w", content.URI, declaredMimeType, err)
+	}
+	actual, _, err := mime.ParseMediaType(content.MimeType)
+	if err != nil {
+		return fmt.Errorf("resource %s returned invalid MIME type %q: %w", content.URI, content.MimeType, err)
+	}
+
+	if declared == actual {
+		return nil
+	}
+	if strings.HasSuffix(declared, "/*") && strings.HasPrefix(actual, strings.TrimSuffix(declared, "*")) {
+		return nil
+	}
+
+	return fmt.Errorf("resource %s returned MIME type %s but declares %s", content.URI, actual, declared)
+}
+
+// validateReadResourceContent validates a single read result entry against its declared MIME type
+func (mr *MCPRouter) validateReadResourceContent(content *types.ResourceContent, declaredMimeType, context string) error {
+	if err := mr.validateResourceContent(content, context); err != nil {
+		return err
+	}
+	if err := checkResourceMimeType(content, declaredMimeType); err != nil {
+		return fmt.Errorf("%s: %w", context, err)
+	}
+	return nil
+}
+
+// pluginResourceMimeType looks up the MIME type a plugin declares for a plugin:// URI
+func (mr *MCPRouter) pluginResourceMimeType(reqCtx *RequestContext, uri string) string {
+	pluginName, _, found := strings.Cut(strings.TrimPrefix(uri, "plugin://"), "/")
+	if !found || !strings.HasPrefix(uri, "plugin://") {
+		return ""
+	}
+
+	resources, err := mr.pluginHandler.GetPluginResources(pluginName, reqCtx.Capabilities)
+	if err != nil {
+		return ""
+	}
+	for _, resource := range resources {
+		if resource.URI == uri {
+			return resource.MimeType
+		}
+	}
+	return ""
+}
+
+// validateServiceResourceRead checks a backend resources/read result: blobs must
+// be valid base64 and each content MIME type must match the service's resource definition
+func (mr *MCPRouter) validateServiceResourceRead(service *registry.RegisteredService, result interface{}) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode resource read result from service %s: %w", service.ID, err)
+	}
+
+	var readResult types.ReadResourceResult
+	if err := json.Unmarshal(raw, &readResult); err != nil {
+		return fmt.Errorf("invalid resource read result from service %s: %w", service.ID, err)
+	}
+
+	declared := make(map[string]string, len(service.Resources))
+	for _, resource := range service.Resources {
+		declared[resource.URI] = resource.MimeType
+	}
+
+	for i, content := range readResult.Contents {
+		if err := mr.validateReadResourceContent(&content, declared[content.URI], fmt.Sprintf("content[%d]", i)); err != nil {
+			mr.metrics.Inc("mcp_resource_content_rejections_total", "service_id", service.ID)
+			return fmt.Errorf("invalid resource content from service %s: %w", service.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// handleResourceContent serves the first content entry of a resource read as raw
+// bytes, decoding base64 blobs and setting Content-Type from the content MIME type
+func (mr *MCPRouter) handleResourceContent(w http.ResponseWriter, r *http.Request) {
+	reqCtx := mr.createRequestContext(r)
+	reqCtx.Method = "resources/read"
+	w.Header().Set(mr.config.RequestIDHeader, reqCtx.RequestID)
+
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		http.Error(w, "missing uri query parameter", http.StatusBadRequest)
+		return
+	}
+
+	if err := mr.resolveCapabilities(r, reqCtx); err != nil {
+		http.Error(w, "authentication failed", http.StatusUnauthorized)
+		return
+	}
+	if err := mr.authorizeMethod(reqCtx, reqCtx.Method, nil); err != nil {
+		http.Error(w, "permission denied", http.StatusForbidden)
+		return
+	}
+
+	result, err := mr.routeJSONRPCRequest(r.Context(), reqCtx, &mcpTypes.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      reqCtx.RequestID,
+		Method:  "resources/read",
+		Params:  map[string]interface{}{"uri": uri},
+	})
+	if err != nil {
+		mr.logger.Warn("resource_content_read_failed",
+			"request_id", reqCtx.RequestID,
+			"uri", uri,
+			"error", err)
+		if errors.IsAuthorizationError(err) {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "failed to read resource", http.StatusBadGateway)
+		return
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, "failed to encode resource", http.StatusInternalServerError)
+		return
+	}
+	var readResult types.ReadResourceResult
+	if err := json.Unmarshal(raw, &readResult); err != nil || len(readResult.Contents) == 0 {
+		http.Error(w, "resource has no content", http.StatusBadGateway)
+		return
+	}
+
+	content := readResult.Contents[0]
+	body := []byte(content.Text)
+	mimeType := content.MimeType
+	if content.Blob != "" {
+		body, err = base64.StdEncoding.DecodeString(content.Blob)
+		if err != nil {
+			http.Error(w, "resource blob is not valid base64", http.StatusBadGateway)
+			return
+		}
+		if mimeType == "" {
+			mimeType = defaultBinaryMimeType
+		}
+	} else if mimeType == "" {
+		mimeType = "text/plain; charset=utf-8"
+	}
+
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+
+	mr.metrics.Inc("mcp_resource_content_served_total", "binary", fmt.Sprintf("%t", content.Blob != ""))
+}
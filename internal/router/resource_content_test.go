@@ -0,0 +1,164 @@
+package router
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// TestBinaryResourceContent tests base64 blob pass-through, validation and MIME checks on resource reads
+func TestBinaryResourceContent(t *testing.T) {
+	logger := logging.New("test")
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00, 0xff}
+
+	handler := &fakeResourcePluginHandler{
+		plugin: "media",
+		resources: map[string]fakeResource{
+			"plugin://media/logo":     {mimeType: "image/png", result: png},
+			"plugin://media/broken":   {mimeType: "image/png", result: map[string]interface{}{"blob": "not*base64!", "mimeType": "image/png"}},
+			"plugin://media/mislabel": {mimeType: "image/png", result: &types.ResourceContent{Blob: base64.StdEncoding.EncodeToString(png), MimeType: "text/html"}},
+		},
+	}
+	mr := NewMCPRouter(nil, handler, nil, logger, &mockMetrics{}, nil)
+
+	read := func(t *testing.T, uri string) map[string]json.RawMessage {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mr.handleMCPRequest(w, newJSONRPCRequest(t, "resources/read", map[string]interface{}{"uri": uri}))
+
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return resp
+	}
+
+	t.Run("valid base64 blob is passed through", func(t *testing.T) {
+		resp := read(t, "plugin://media/logo")
+
+		var result types.ReadResourceResult
+		if err := json.Unmarshal(resp["result"], &result); err != nil || len(result.Contents) != 1 {
+			t.Fatalf("expected one content entry, got %v (%v)", resp, err)
+		}
+		content := result.Contents[0]
+		if content.MimeType != "image/png" || content.Text != "" {
+			t.Errorf("expected image/png blob content, got %+v", content)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(content.Blob)
+		if err != nil || string(decoded) != string(png) {
+			t.Errorf("expected blob to decode to the original bytes, got %q (%v)", decoded, err)
+		}
+	})
+
+	t.Run("binary resource is served directly with its Content-Type", func(t *testing.T) {
+		routes := mux.NewRouter()
+		mr.SetupRoutes(routes)
+
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("GET", "/mcp/resources/content?uri=plugin://media/logo", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+			t.Errorf("expected Content-Type image/png, got %s", ct)
+		}
+		if w.Body.String() != string(png) {
+			t.Errorf("expected raw PNG bytes, got %q", w.Body.Bytes())
+		}
+	})
+
+	t.Run("invalid base64 payload is rejected", func(t *testing.T) {
+		resp := read(t, "plugin://media/broken")
+		if _, hasError := resp["error"]; !hasError {
+			t.Fatalf("expected error for invalid base64 blob, got %s", resp["result"])
+		}
+	})
+
+	t.Run("MIME mismatch with the resource definition is rejected", func(t *testing.T) {
+		resp := read(t, "plugin://media/mislabel")
+		if _, hasError := resp["error"]; !hasError {
+			t.Fatalf("expected error for MIME mismatch, got %s", resp["result"])
+		}
+	})
+}
+
+// TestServiceResourceReadValidation tests backend resources/read results against service resource definitions
+func TestServiceResourceReadValidation(t *testing.T) {
+	mr := NewMCPRouter(nil, nil, nil, logging.New("test"), &mockMetrics{}, nil)
+	service := &registry.RegisteredService{
+		ID: "docs-service",
+		Resources: []registry.ResourceDefinition{
+			{URI: "file://manual.pdf", Name: "manual", MimeType: "application/pdf"},
+			{URI: "file://photos/cat", Name: "cat", MimeType: "image/*"},
+		},
+	}
+	blob := base64.StdEncoding.EncodeToString([]byte("%PDF-1.7"))
+
+	testCases := []struct {
+		name    string
+		content map[string]interface{}
+		valid   bool
+	}{
+		{"matching MIME", map[string]interface{}{"uri": "file://manual.pdf", "mimeType": "application/pdf", "blob": blob}, true},
+		{"MIME parameters ignored", map[string]interface{}{"uri": "file://manual.pdf", "mimeType": "Application/PDF; version=1.7", "blob": blob}, true},
+		{"wildcard declaration", map[string]interface{}{"uri": "file://photos/cat", "mimeType": "image/jpeg", "blob": blob}, true},
+		{"undeclared resource", map[string]interface{}{"uri": "file://other", "mimeType": "text/plain", "text": "hi"}, true},
+		{"MIME mismatch", map[string]interface{}{"uri": "file://manual.pdf", "mimeType": "text/plain", "blob": blob}, false},
+		{"invalid base64", map[string]interface{}{"uri": "file://manual.pdf", "mimeType": "application/pdf", "blob": "%%%"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := map[string]interface{}{"contents": []interface{}{tc.content}}
+			err := mr.validateServiceResourceRead(service, result)
+			if tc.valid && err != nil {
+				t.Errorf("expected content to be accepted, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("expected content to be rejected")
+			}
+			if !tc.valid && err != nil && !strings.Contains(err.Error(), "docs-service") {
+				t.Errorf("expected error to name the service, got %v", err)
+			}
+		})
+	}
+}
+
+type fakeResource struct {
+	mimeType string
+	result   interface{}
+}
+
+// fakeResourcePluginHandler serves fixed resources from a single plugin
+type fakeResourcePluginHandler struct {
+	mcpTypes.PluginHandler
+	plugin    string
+	resources map[string]fakeResource
+}
+
+func (f *fakeResourcePluginHandler) ListAvailablePlugins(capabilities *rbac.ProcessedCapabilities) []string {
+	return []string{f.plugin}
+}
+
+func (f *fakeResourcePluginHandler) GetPluginResources(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]mcpTypes.Resource, error) {
+	var resources []mcpTypes.Resource
+	for uri, resource := range f.resources {
+		resources = append(resources, mcpTypes.Resource{URI: uri, Name: uri, MimeType: resource.mimeType})
+	}
+	return resources, nil
+}
+
+func (f *fakeResourcePluginHandler) ReadPluginResource(ctx context.Context, uri string, capabilities *rbac.ProcessedCapabilities) (interface{}, error) {
+	return f.resources[uri].result, nil
+}
@@ -0,0 +1,397 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/errors"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// defaultSubscriptionGracePeriod covers reconnects after a network blip
+const defaultSubscriptionGracePeriod = 30 * time.Second
+
+// pluginResourceWatcher is implemented by plugin handlers that can watch
+// plugin resources for changes
+type pluginResourceWatcher interface {
+	WatchPluginResource(uri string, capabilities *rbac.ProcessedCapabilities, onChange func(uri string)) (func(), error)
+}
+
+// NotificationPublisher sends a server-initiated notification to the
+// notification streams of audience, or to every stream when audience is nil
+type NotificationPublisher func(method string, params interface{}, audience []Subscriber)
+
+// anonymousUserID is the user of requests made without authentication
+const anonymousUserID = "anonymous"
+
+// Subscriber identifies whose notification streams a notification belongs
+// to: an authenticated user, optionally narrowed to one of their sessions
+type Subscriber struct {
+	UserID    string
+	SessionID string
+}
+
+// Receives reports whether a stream opened by s gets a notification
+// addressed to target. A notification for a user reaches every stream of
+// that user; one for a session reaches only that session's streams.
+func (s Subscriber) Receives(target Subscriber) bool {
+	return s.UserID == target.UserID && (target.SessionID == "" || target.SessionID == s.SessionID)
+}
+
+// requestSubscriber returns the subscriber a request acts for. The user comes
+// from the resolved capabilities, never from client-supplied headers.
+func requestSubscriber(reqCtx *RequestContext) Subscriber {
+	userID := anonymousUserID
+	if reqCtx.Capabilities != nil && reqCtx.Capabilities.UserID != "" {
+		userID = reqCtx.Capabilities.UserID
+	}
+	return Subscriber{UserID: userID, SessionID: reqCtx.SessionID}
+}
+
+// resourceSubscription is one watched resource and the clients subscribed to it
+type resourceSubscription struct {
+	subscribers map[Subscriber]struct{}
+	stop        func()
+}
+
+// resourceSubscriptions tracks resources/subscribe requests for plugin
+// resources. A resource is watched while at least one client is subscribed.
+// Subscribers with open notification streams are counted so their
+// subscriptions can be purged some time after the last stream closes.
+type resourceSubscriptions struct {
+	mutex         sync.Mutex
+	subscriptions map[string]*resourceSubscription
+	streams       map[Subscriber]int
+	purges        map[Subscriber]*pendingPurge
+}
+
+// pendingPurge is a disconnected subscriber whose grace period is running
+type pendingPurge struct {
+	timer *time.Timer
+}
+
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{
+		subscriptions: make(map[string]*resourceSubscription),
+		streams:       make(map[Subscriber]int),
+		purges:        make(map[Subscriber]*pendingPurge),
+	}
+}
+
+// subscribe adds subscriber to uri, calling watch to start watching the
+// resource when it is the first subscriber
+func (rs *resourceSubscriptions) subscribe(uri string, subscriber Subscriber, watch func() (func(), error)) error {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	if sub, exists := rs.subscriptions[uri]; exists {
+		sub.subscribers[subscriber] = struct{}{}
+		return nil
+	}
+
+	stop, err := watch()
+	if err != nil {
+		return err
+	}
+	rs.subscriptions[uri] = &resourceSubscription{
+		subscribers: map[Subscriber]struct{}{subscriber: {}},
+		stop:        stop,
+	}
+	return nil
+}
+
+// unsubscribe removes subscriber from uri and stops the watch once no
+// subscribers remain. It reports whether the watch was stopped.
+func (rs *resourceSubscriptions) unsubscribe(uri string, subscriber Subscriber) bool {
+	rs.mutex.Lock()
+	sub, exists := rs.subscriptions[uri]
+	if !exists {
+		rs.mutex.Unlock()
+		return false
+	}
+	delete(sub.subscribers, subscriber)
+	if len(sub.subscribers) > 0 {
+		rs.mutex.Unlock()
+		return false
+	}
+	delete(rs.subscriptions, uri)
+	rs.mutex.Unlock()
+
+	sub.stop()
+	return true
+}
+
+// subscribers returns the clients subscribed to uri
+func (rs *resourceSubscriptions) subscribers(uri string) []Subscriber {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	sub, exists := rs.subscriptions[uri]
+	if !exists {
+		return nil
+	}
+	subscribers := make([]Subscriber, 0, len(sub.subscribers))
+	for subscriber := range sub.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	return subscribers
+}
+
+// count returns the number of watched resources
+func (rs *resourceSubscriptions) count() int {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	return len(rs.subscriptions)
+}
+
+// subscribedLocked returns the number of resources subscriber is subscribed to
+func (rs *resourceSubscriptions) subscribedLocked(subscriber Subscriber) int {
+	count := 0
+	for _, sub := range rs.subscriptions {
+		if _, exists := sub.subscribers[subscriber]; exists {
+			count++
+		}
+	}
+	return count
+}
+
+// connect records an open stream for subscriber, cancelling a pending purge.
+// It returns the number of subscriptions restored by the cancellation.
+func (rs *resourceSubscriptions) connect(subscriber Subscriber) int {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	rs.streams[subscriber]++
+	pending, exists := rs.purges[subscriber]
+	if !exists {
+		return 0
+	}
+	pending.timer.Stop()
+	delete(rs.purges, subscriber)
+	return rs.subscribedLocked(subscriber)
+}
+
+// disconnect records a closed stream for subscriber. Once its last stream
+// closes, purge runs after grace, or at once for a zero grace; a negative
+// grace keeps the subscriptions until they are unsubscribed.
+func (rs *resourceSubscriptions) disconnect(subscriber Subscriber, grace time.Duration, purge func()) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	if rs.streams[subscriber] > 1 {
+		rs.streams[subscriber]--
+		return
+	}
+	delete(rs.streams, subscriber)
+
+	if grace < 0 || rs.subscribedLocked(subscriber) == 0 {
+		return
+	}
+	if grace == 0 {
+		go purge()
+		return
+	}
+
+	pending := &pendingPurge{}
+	pending.timer = time.AfterFunc(grace, func() {
+		rs.mutex.Lock()
+		current := rs.purges[subscriber] == pending
+		if current {
+			delete(rs.purges, subscriber)
+		}
+		rs.mutex.Unlock()
+
+		// A reconnect cancelled this purge after the timer fired
+		if current {
+			purge()
+		}
+	})
+	rs.purges[subscriber] = pending
+}
+
+// purge removes every subscription of subscriber unless it has reconnected,
+// stopping watches left without subscribers. It returns the purged URIs.
+func (rs *resourceSubscriptions) purge(subscriber Subscriber) []string {
+	rs.mutex.Lock()
+	if rs.streams[subscriber] > 0 {
+		rs.mutex.Unlock()
+		return nil
+	}
+
+	var purged []string
+	var stops []func()
+	for uri, sub := range rs.subscriptions {
+		if _, exists := sub.subscribers[subscriber]; !exists {
+			continue
+		}
+		purged = append(purged, uri)
+		delete(sub.subscribers, subscriber)
+		if len(sub.subscribers) == 0 {
+			delete(rs.subscriptions, uri)
+			stops = append(stops, sub.stop)
+		}
+	}
+	rs.mutex.Unlock()
+
+	for _, stop := range stops {
+		stop()
+	}
+	return purged
+}
+
+// ConnectNotificationStream authenticates a notification stream the same way
+// as /mcp requests and returns the subscriber its notifications are
+// addressed to. The client's resource subscriptions are kept while it has a
+// stream open and for SubscriptionGracePeriod after its last one closes, so a
+// client that reconnects with the same session ID in time keeps them. Call
+// the returned function when the stream closes.
+func (mr *MCPRouter) ConnectNotificationStream(r *http.Request) (Subscriber, func(), error) {
+	reqCtx := mr.createRequestContext(r)
+	if err := mr.resolveCapabilities(r, reqCtx); err != nil {
+		mr.metrics.Inc("notification_stream_auth_failures_total")
+		mr.logger.Warn("notification_stream_auth_failed",
+			"request_id", reqCtx.RequestID,
+			"remote_addr", r.RemoteAddr,
+			"error", err)
+		return Subscriber{}, nil, err
+	}
+	return requestSubscriber(reqCtx), mr.sessionConnected(reqCtx), nil
+}
+
+// sessionConnected records an open notification stream for the resource
+// subscriptions of a session. Clients without a session ID are not tracked.
+func (mr *MCPRouter) sessionConnected(reqCtx *RequestContext) (disconnected func()) {
+	sessionID := reqCtx.SessionID
+	if sessionID == "" {
+		return func() {}
+	}
+	subscriber := requestSubscriber(reqCtx)
+
+	if restored := mr.subscriptions.connect(subscriber); restored > 0 {
+		mr.metrics.Inc("mcp_subscriptions_restored_total")
+		mr.logger.Info("resource_subscriptions_restored",
+			"session_id", sessionID,
+			"subscriptions", restored)
+	}
+
+	return func() {
+		mr.subscriptions.disconnect(subscriber, mr.config.SubscriptionGracePeriod, func() {
+			purged := mr.subscriptions.purge(subscriber)
+			if len(purged) == 0 {
+				return
+			}
+			mr.metrics.Add("mcp_subscriptions_purged_total", float64(len(purged)))
+			mr.logger.Info("resource_subscriptions_purged",
+				"session_id", sessionID,
+				"subscriptions", len(purged),
+				"grace_period", mr.config.SubscriptionGracePeriod.String())
+		})
+	}
+}
+
+// SetNotificationPublisher sets where notifications/resources/updated is sent
+func (mr *MCPRouter) SetNotificationPublisher(publish NotificationPublisher) {
+	mr.notify = publish
+}
+
+// publishResourceUpdated tells the clients subscribed to a resource that it changed
+func (mr *MCPRouter) publishResourceUpdated(uri string) {
+	mr.metrics.Inc("mcp_resource_updates_total")
+	mr.logger.Debug("resource_updated", "uri", uri)
+
+	audience := mr.subscriptions.subscribers(uri)
+	if mr.notify != nil && len(audience) > 0 {
+		mr.notify("notifications/resources/updated", map[string]interface{}{"uri": uri}, audience)
+	}
+}
+
+// handlePluginResourcesSubscribe handles resources/subscribe and
+// resources/unsubscribe for plugin:// resources; other URIs go to backends
+func (mr *MCPRouter) handlePluginResourcesSubscribe(ctx context.Context, reqCtx *RequestContext, mcpReq *types.Request, subscribe bool) (interface{}, bool, error) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+
+	if mcpReq.Params != nil {
+		if err := json.Unmarshal(mcpReq.Params, &params); err != nil {
+			return nil, true, fmt.Errorf("failed to parse %s parameters: %w", mcpReq.Method, err)
+		}
+	}
+
+	if !strings.HasPrefix(params.URI, "plugin://") {
+		return nil, false, nil
+	}
+	reqCtx.IsPluginCall = true
+
+	// Subscriptions belong to the authenticated user and, when given, the
+	// session; unauthenticated callers only have the session to tell them apart
+	subscriber := requestSubscriber(reqCtx)
+	if subscriber.SessionID == "" && subscriber.UserID == anonymousUserID {
+		return nil, true, errors.ValidationError("mcp_router", "resource_subscription",
+			fmt.Sprintf("%s requires a session ID for unauthenticated clients", mcpReq.Method),
+			map[string]interface{}{
+				"uri":        params.URI,
+				"header":     mr.config.SessionHeader,
+				"request_id": reqCtx.RequestID,
+			})
+	}
+	if !subscribe {
+		if mr.subscriptions.unsubscribe(params.URI, subscriber) {
+			mr.logger.Info("plugin_resource_watch_stopped", "uri", params.URI)
+		}
+		return map[string]interface{}{}, true, nil
+	}
+
+	if !mr.resourceAllowed(params.URI) {
+		return nil, true, mr.capabilityDeniedError(reqCtx, "resource", params.URI)
+	}
+
+	// Checked on every subscribe, since a watch started by another
+	// subscriber does not prove this caller may read the resource
+	pluginName, _, _ := strings.Cut(strings.TrimPrefix(params.URI, "plugin://"), "/")
+	if reqCtx.Capabilities == nil || !reqCtx.Capabilities.HasPermission(pluginName, rbac.PermissionRead) {
+		mr.metrics.Inc("plugin_resource_subscriptions_denied_total", "plugin", pluginName)
+		mr.logger.Warn("plugin_resource_subscribe_denied",
+			"request_id", reqCtx.RequestID,
+			"user_id", subscriber.UserID,
+			"uri", params.URI)
+		return nil, true, errors.AuthorizationError("mcp_router", "resource_subscription",
+			fmt.Sprintf("Subscribing to %s requires read permission on plugin %s", params.URI, pluginName),
+			map[string]interface{}{
+				"uri":        params.URI,
+				"plugin":     pluginName,
+				"request_id": reqCtx.RequestID,
+			})
+	}
+
+	watcher, ok := mr.pluginHandler.(pluginResourceWatcher)
+	if !ok {
+		return nil, true, fmt.Errorf("plugin resources do not support subscriptions")
+	}
+
+	err := mr.subscriptions.subscribe(params.URI, subscriber, func() (func(), error) {
+		stop, err := watcher.WatchPluginResource(params.URI, reqCtx.Capabilities, mr.publishResourceUpdated)
+		if err == nil {
+			mr.logger.Info("plugin_resource_watch_started",
+				"request_id", reqCtx.RequestID,
+				"uri", params.URI)
+		}
+		return stop, err
+	})
+	if err != nil {
+		mr.logger.Error("plugin_resource_subscribe_failed",
+			"request_id", reqCtx.RequestID,
+			"uri", params.URI,
+			"error", err)
+		return nil, true, err
+	}
+
+	mr.metrics.Inc("plugin_resource_subscriptions_total", "user_id", reqCtx.UserID)
+	return map[string]interface{}{}, true, nil
+}
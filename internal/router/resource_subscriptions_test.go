@@ -0,0 +1,255 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// watchingPluginHandler records resource watches started by the router
+type watchingPluginHandler struct {
+	fakePluginHandler
+	onChange map[string]func(uri string)
+	started  int
+	stopped  int
+}
+
+func (w *watchingPluginHandler) WatchPluginResource(uri string, capabilities *rbac.ProcessedCapabilities, onChange func(uri string)) (func(), error) {
+	w.started++
+	w.onChange[uri] = onChange
+	return func() { w.stopped++ }, nil
+}
+
+// TestPluginResourceSubscriptions tests that subscribers share one watch that emits resources/updated
+func TestPluginResourceSubscriptions(t *testing.T) {
+	handler := &watchingPluginHandler{onChange: make(map[string]func(uri string))}
+	mr := NewMCPRouter(nil, handler, nil, logging.New("test"), &mockMetrics{}, nil)
+
+	var published []publishedNotification
+	mr.SetNotificationPublisher(func(method string, params interface{}, audience []Subscriber) {
+		published = append(published, publishedNotification{method, params, audience})
+	})
+
+	const uri = "plugin://editor/file/notes.txt"
+	anonymous := &rbac.ProcessedCapabilities{
+		UserID:  "anonymous",
+		Plugins: map[string]rbac.PluginPermission{"*": {CanRead: true}},
+	}
+	request := func(method, sessionID string, capabilities *rbac.ProcessedCapabilities) error {
+		params, _ := json.Marshal(map[string]string{"uri": uri})
+		reqCtx := &RequestContext{RequestID: "test-request", SessionID: sessionID, Capabilities: capabilities}
+		_, handled, err := mr.tryPluginRouting(context.Background(), reqCtx, &types.Request{Method: method, Params: params})
+		if !handled {
+			t.Fatalf("expected %s of a plugin resource to be handled", method)
+		}
+		return err
+	}
+	call := func(method, sessionID string) {
+		t.Helper()
+		if err := request(method, sessionID, anonymous); err != nil {
+			t.Fatalf("%s for session %s: %v", method, sessionID, err)
+		}
+	}
+
+	call("resources/subscribe", "a")
+	call("resources/subscribe", "b")
+	call("resources/subscribe", "b")
+	if handler.started != 1 {
+		t.Fatalf("expected one watch for two subscribers, got %d", handler.started)
+	}
+
+	handler.onChange[uri](uri)
+	if len(published) != 1 || published[0].method != "notifications/resources/updated" {
+		t.Fatalf("expected a resources/updated notification, got %+v", published)
+	}
+	if got := published[0].params.(map[string]interface{})["uri"]; got != uri {
+		t.Errorf("expected notification for %s, got %v", uri, got)
+	}
+	if audience := published[0].audience; len(audience) != 2 {
+		t.Errorf("expected the update addressed to the two subscribed sessions, got %+v", audience)
+	}
+
+	t.Run("every subscribe is authorized", func(t *testing.T) {
+		noRead := &rbac.ProcessedCapabilities{
+			UserID:  "mallory",
+			Plugins: map[string]rbac.PluginPermission{"editor": {CanExecute: true}},
+		}
+		if err := request("resources/subscribe", "m", noRead); err == nil {
+			t.Error("expected a caller without read permission to be denied an already watched resource")
+		}
+		if subscribers := mr.subscriptions.subscribers(uri); len(subscribers) != 2 {
+			t.Errorf("expected the denied caller not to be subscribed, got %+v", subscribers)
+		}
+	})
+
+	t.Run("unauthenticated callers need a session", func(t *testing.T) {
+		if err := request("resources/subscribe", "", anonymous); err == nil {
+			t.Error("expected an anonymous subscribe without a session to be rejected")
+		}
+		if err := request("resources/unsubscribe", "", anonymous); err == nil {
+			t.Error("expected an anonymous unsubscribe without a session to be rejected")
+		}
+		if handler.stopped != 0 {
+			t.Error("expected the watch to be unaffected")
+		}
+	})
+
+	t.Run("users are subscribed separately", func(t *testing.T) {
+		alice := &rbac.ProcessedCapabilities{UserID: "alice", Plugins: anonymous.Plugins}
+		bob := &rbac.ProcessedCapabilities{UserID: "bob", Plugins: anonymous.Plugins}
+		if err := request("resources/subscribe", "", alice); err != nil {
+			t.Fatalf("subscribe failed: %v", err)
+		}
+		if err := request("resources/unsubscribe", "", bob); err != nil {
+			t.Fatalf("unsubscribe failed: %v", err)
+		}
+		if subscribers := mr.subscriptions.subscribers(uri); len(subscribers) != 3 {
+			t.Errorf("expected another user's unsubscribe to leave alice subscribed, got %+v", subscribers)
+		}
+		if err := request("resources/unsubscribe", "", alice); err != nil {
+			t.Fatalf("unsubscribe failed: %v", err)
+		}
+	})
+
+	call("resources/unsubscribe", "a")
+	if handler.stopped != 0 {
+		t.Error("expected the watch to continue while a subscriber remains")
+	}
+	call("resources/unsubscribe", "b")
+	if handler.stopped != 1 || mr.subscriptions.count() != 0 {
+		t.Errorf("expected the last unsubscribe to stop the watch, stopped=%d watched=%d", handler.stopped, mr.subscriptions.count())
+	}
+
+	t.Run("non-plugin resources are left to backends", func(t *testing.T) {
+		params, _ := json.Marshal(map[string]string{"uri": "file:///tmp/notes.txt"})
+		_, handled, _ := mr.tryPluginRouting(context.Background(), &RequestContext{}, &types.Request{Method: "resources/subscribe", Params: params})
+		if handled {
+			t.Error("expected non-plugin URI not to be handled by plugin routing")
+		}
+	})
+}
+
+// stopSignallingPluginHandler reports stopped resource watches on a channel,
+// since subscriptions are purged from a timer goroutine
+type stopSignallingPluginHandler struct {
+	fakePluginHandler
+	stopped chan string
+}
+
+func (s *stopSignallingPluginHandler) WatchPluginResource(uri string, capabilities *rbac.ProcessedCapabilities, onChange func(uri string)) (func(), error) {
+	return func() { s.stopped <- uri }, nil
+}
+
+// TestSubscriptionGracePeriod tests that a session reconnecting within the
+// grace period keeps its subscriptions and that a longer disconnect purges them
+func TestSubscriptionGracePeriod(t *testing.T) {
+	const grace = 50 * time.Millisecond
+	handler := &stopSignallingPluginHandler{stopped: make(chan string, 4)}
+	config := DefaultRouterConfig()
+	config.SubscriptionGracePeriod = grace
+	mr := NewMCPRouterWithConfig(nil, handler, nil, logging.New("test"), &mockMetrics{}, nil, config)
+
+	const uri = "plugin://editor/file/notes.txt"
+	params, _ := json.Marshal(map[string]string{"uri": uri})
+	reqCtx := &RequestContext{
+		RequestID:    "test-request",
+		SessionID:    "a",
+		Capabilities: &rbac.ProcessedCapabilities{UserID: "anonymous", Plugins: map[string]rbac.PluginPermission{"*": {CanRead: true}}},
+	}
+	if _, _, err := mr.tryPluginRouting(context.Background(), reqCtx, &types.Request{Method: "resources/subscribe", Params: params}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	stream := httptest.NewRequest("GET", "/mcp/events", nil)
+	stream.Header.Set("X-Session-ID", "a")
+
+	t.Run("quick reconnect restores subscriptions", func(t *testing.T) {
+		_, disconnected, err := mr.ConnectNotificationStream(stream)
+		if err != nil {
+			t.Fatalf("failed to connect stream: %v", err)
+		}
+		disconnected()
+		time.Sleep(grace / 5)
+		_, disconnected, _ = mr.ConnectNotificationStream(stream)
+		defer disconnected()
+
+		select {
+		case stopped := <-handler.stopped:
+			t.Fatalf("expected the watch to survive the reconnect, %s was stopped", stopped)
+		case <-time.After(3 * grace):
+		}
+		if mr.subscriptions.count() != 1 {
+			t.Errorf("expected the subscription to be kept, got %d", mr.subscriptions.count())
+		}
+	})
+
+	t.Run("long disconnect purges subscriptions", func(t *testing.T) {
+		select {
+		case stopped := <-handler.stopped:
+			if stopped != uri {
+				t.Errorf("expected the watch of %s to stop, got %s", uri, stopped)
+			}
+		case <-time.After(20 * grace):
+			t.Fatal("expected the subscription to be purged after the grace period")
+		}
+		if mr.subscriptions.count() != 0 {
+			t.Errorf("expected no subscriptions, got %d", mr.subscriptions.count())
+		}
+	})
+}
+
+// TestConnectNotificationStream tests that notification streams are
+// authenticated like /mcp and addressed by the resolved identity
+func TestConnectNotificationStream(t *testing.T) {
+	logger := logging.New("test")
+
+	t.Run("identity comes from the resolved capabilities", func(t *testing.T) {
+		mr := NewMCPRouter(nil, &fakePluginHandler{}, nil, logger, &mockMetrics{}, nil)
+		stream := httptest.NewRequest("GET", "/mcp/events", nil)
+		stream.Header.Set("X-User-ID", "someone-else")
+		stream.Header.Set("X-Session-ID", "a")
+
+		subscriber, disconnected, err := mr.ConnectNotificationStream(stream)
+		if err != nil {
+			t.Fatalf("failed to connect stream: %v", err)
+		}
+		defer disconnected()
+		if subscriber != (Subscriber{UserID: "anonymous", SessionID: "a"}) {
+			t.Errorf("expected the anonymous user's session a, got %+v", subscriber)
+		}
+	})
+
+	t.Run("required authentication denies unauthenticated streams", func(t *testing.T) {
+		config := DefaultRouterConfig()
+		config.RequireAuthentication = true
+		mr := NewMCPRouterWithConfig(nil, &fakePluginHandler{}, nil, logger, &mockMetrics{}, nil, config)
+
+		if _, _, err := mr.ConnectNotificationStream(httptest.NewRequest("GET", "/mcp/events", nil)); err == nil {
+			t.Error("expected a stream without credentials to be denied")
+		}
+	})
+
+	t.Run("notifications reach only the addressed user or session", func(t *testing.T) {
+		stream := Subscriber{UserID: "alice", SessionID: "a"}
+		for _, tc := range []struct {
+			target Subscriber
+			want   bool
+		}{
+			{Subscriber{UserID: "alice", SessionID: "a"}, true},
+			{Subscriber{UserID: "alice"}, true},
+			{Subscriber{UserID: "alice", SessionID: "b"}, false},
+			{Subscriber{UserID: "bob", SessionID: "a"}, false},
+			{Subscriber{UserID: "bob"}, false},
+		} {
+			if got := stream.Receives(tc.target); got != tc.want {
+				t.Errorf("Receives(%+v) = %v, want %v", tc.target, got, tc.want)
+			}
+		}
+	})
+}
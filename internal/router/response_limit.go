@@ -0,0 +1,54 @@
+package router
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// defaultMaxResponseSize bounds backend responses read by the gateway
+const defaultMaxResponseSize = 50 * 1024 * 1024 // 50MB
+
+// limitedBody reads at most limit bytes from a backend response and fails
+// once the body proves larger, so an oversized response is never buffered
+type limitedBody struct {
+	reader   io.Reader
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func newLimitedBody(body io.Reader, limit int64) *limitedBody {
+	// Read one byte past the limit to tell "exactly limit" from "too large"
+	return &limitedBody{reader: io.LimitReader(body, limit+1), limit: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		b.exceeded = true
+		return 0, fmt.Errorf("response exceeds %d bytes", b.limit)
+	}
+	return n, err
+}
+
+// responseTooLarge records a backend response over MaxResponseSize and
+// returns the error to report
+func (mr *MCPRouter) responseTooLarge(reqCtx *RequestContext, service *registry.RegisteredService, method string, size int64) error {
+	mr.metrics.Inc("mcp_upstream_oversized_responses_total", "method", method, "service_id", service.ID)
+
+	requestID := ""
+	if reqCtx != nil {
+		requestID = reqCtx.RequestID
+	}
+	mr.logger.Warn("upstream_response_too_large",
+		"request_id", requestID,
+		"service_id", service.ID,
+		"method", method,
+		"size", size,
+		"max_response_size", mr.config.MaxResponseSize)
+
+	return fmt.Errorf("response from service %s exceeds the maximum size of %d bytes", service.ID, mr.config.MaxResponseSize)
+}
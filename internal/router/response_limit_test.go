@@ -0,0 +1,90 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestMaxResponseSize tests that oversized backend responses are rejected without being buffered
+func TestMaxResponseSize(t *testing.T) {
+	logger := logging.New("test")
+	recordingMetrics := &cancellationRecordingMetrics{}
+
+	const maxResponseSize = 64 * 1024
+	const streamedSize = 64 * 1024 * 1024
+
+	var written atomic.Int64
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("mode") {
+		case "declared":
+			// Content-Length alone is enough to reject the response
+			w.Header().Set("Content-Length", strconv.Itoa(streamedSize))
+			w.WriteHeader(http.StatusOK)
+		case "streamed":
+			// Stream a huge string value without a Content-Length
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"data":"`))
+			chunk := []byte(strings.Repeat("x", 32*1024))
+			for written.Load() < streamedSize {
+				n, err := w.Write(chunk)
+				written.Add(int64(n))
+				if err != nil {
+					return
+				}
+			}
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"data":"small"}}`))
+		}
+	})
+
+	serviceRegistry := newTestRegistry(logger, recordingMetrics)
+	defer serviceRegistry.Shutdown()
+
+	config := DefaultRouterConfig()
+	config.MaxResponseSize = maxResponseSize
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, recordingMetrics, nil, config)
+
+	forward := func(t *testing.T, mode string) (interface{}, error) {
+		t.Helper()
+		serviceID := registerTestService(t, serviceRegistry, "backend-"+mode, "tool_provider", backend.URL+"/?mode="+mode, nil)
+		return mr.forwardToService(context.Background(), &RequestContext{RequestID: "req-" + mode}, serviceRegistry.GetService(serviceID), &mcpTypes.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "tools/call",
+		})
+	}
+
+	t.Run("responses within the limit are returned", func(t *testing.T) {
+		if _, err := forward(t, "small"); err != nil {
+			t.Fatalf("expected small response to succeed, got %v", err)
+		}
+	})
+
+	t.Run("declared oversized response is rejected", func(t *testing.T) {
+		_, err := forward(t, "declared")
+		if err == nil || !strings.Contains(err.Error(), "exceeds the maximum size") {
+			t.Fatalf("expected oversized response error, got %v", err)
+		}
+	})
+
+	t.Run("streamed oversized response is rejected without buffering it", func(t *testing.T) {
+		_, err := forward(t, "streamed")
+		if err == nil || !strings.Contains(err.Error(), "exceeds the maximum size") {
+			t.Fatalf("expected oversized response error, got %v", err)
+		}
+		if sent := written.Load(); sent >= streamedSize {
+			t.Errorf("expected the gateway to stop reading early, backend sent all %d bytes", sent)
+		}
+	})
+
+	if got := recordingMetrics.count("mcp_upstream_oversized_responses_total"); got != 2 {
+		t.Errorf("expected 2 oversized response metrics, got %d", got)
+	}
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultRetryBudgetWindow = 10 * time.Second
+
+// RetryBudgetConfig caps retries across all backends at a fraction of the
+// requests routed over a sliding window, so retries cannot multiply the load
+// on backends that are already struggling. A zero Ratio disables the budget.
+type RetryBudgetConfig struct {
+	Ratio               float64       `yaml:"ratio"`                  // Retries allowed per routed request, e.g. 0.2
+	MinRetriesPerSecond int           `yaml:"min_retries_per_second"` // Allowed regardless of ratio, so quiet gateways still retry
+	Window              time.Duration `yaml:"window"`                 // Period requests and retries are counted over
+}
+
+// Validate checks that the ratio, floor and window are not negative
+func (c RetryBudgetConfig) Validate() error {
+	if c.Ratio < 0 {
+		return fmt.Errorf("ratio must not be negative, got %v", c.Ratio)
+	}
+	if c.MinRetriesPerSecond < 0 {
+		return fmt.Errorf("min_retries_per_second must not be negative, got %d", c.MinRetriesPerSecond)
+	}
+	if c.Window < 0 {
+		return fmt.Errorf("window must not be negative, got %s", c.Window)
+	}
+	return nil
+}
+
+// retryBudget counts routed requests and retries in one-second buckets over
+// the window. A nil budget allows every retry.
+type retryBudget struct {
+	ratio      float64
+	minRetries int // Floor over the whole window
+	buckets    []retryBudgetBucket
+	now        func() time.Time
+	mutex      sync.Mutex
+}
+
+type retryBudgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+// newRetryBudget returns the budget described by config, or nil when disabled
+func newRetryBudget(config RetryBudgetConfig) *retryBudget {
+	if config.Ratio <= 0 {
+		return nil
+	}
+	window := config.Window
+	if window <= 0 {
+		window = defaultRetryBudgetWindow
+	}
+	seconds := int((window + time.Second - 1) / time.Second)
+
+	return &retryBudget{
+		ratio:      config.Ratio,
+		minRetries: config.MinRetriesPerSecond * seconds,
+		buckets:    make([]retryBudgetBucket, seconds),
+		now:        time.Now,
+	}
+}
+
+// bucket returns the bucket for the current second, clearing it if it last
+// held an earlier second; the caller holds the lock
+func (b *retryBudget) bucket() *retryBudgetBucket {
+	second := b.now().Unix()
+	bucket := &b.buckets[second%int64(len(b.buckets))]
+	if bucket.second != second {
+		*bucket = retryBudgetBucket{second: second}
+	}
+	return bucket
+}
+
+// recordRequest counts a routed request, earning retry budget
+func (b *retryBudget) recordRequest() {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.bucket().requests++
+}
+
+// tryRetry spends budget on a retry, reporting false when the retries in the
+// window already reach the allowance
+func (b *retryBudget) tryRetry() bool {
+	if b == nil {
+		return true
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	current := b.bucket()
+	oldest := current.second - int64(len(b.buckets)) + 1
+	requests, retries := 0, 0
+	for _, bucket := range b.buckets {
+		if bucket.second >= oldest {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	if float64(retries+1) > b.ratio*float64(requests)+float64(b.minRetries) {
+		return false
+	}
+	current.retries++
+	return true
+}
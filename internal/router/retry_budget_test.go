@@ -0,0 +1,116 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestRetryBudget tests that under sustained failures retries stay within
+// the configured ratio of routed requests, and that the allowance returns as
+// old requests leave the window
+func TestRetryBudget(t *testing.T) {
+	t.Run("retries are capped under sustained failures", func(t *testing.T) {
+		now := time.Unix(1700000000, 0)
+		budget := newRetryBudget(RetryBudgetConfig{Ratio: 0.2, MinRetriesPerSecond: 1, Window: 10 * time.Second})
+		budget.now = func() time.Time { return now }
+
+		// 100 requests per second for 30 seconds, each failing and wanting 2 retries
+		retriesPerSecond := make([]int, 30)
+		for second := range retriesPerSecond {
+			for i := 0; i < 100; i++ {
+				budget.recordRequest()
+				for retry := 0; retry < 2; retry++ {
+					if budget.tryRetry() {
+						retriesPerSecond[second]++
+					}
+				}
+			}
+			now = now.Add(time.Second)
+		}
+
+		total := 0
+		for _, retries := range retriesPerSecond {
+			total += retries
+		}
+		// Without a budget the 3000 requests would send 6000 retries
+		if limit := int(0.2*3000) + 30; total > limit {
+			t.Errorf("expected at most %d retries, got %d", limit, total)
+		}
+		if steady := retriesPerSecond[len(retriesPerSecond)-1]; steady < 15 || steady > 25 {
+			t.Errorf("expected about 20 retries per second once the window is full, got %d", steady)
+		}
+	})
+
+	t.Run("floor allows retries at low traffic", func(t *testing.T) {
+		budget := newRetryBudget(RetryBudgetConfig{Ratio: 0.1, MinRetriesPerSecond: 1, Window: 2 * time.Second})
+		budget.recordRequest()
+		allowed := 0
+		for i := 0; i < 5; i++ {
+			if budget.tryRetry() {
+				allowed++
+			}
+		}
+		if allowed != 2 {
+			t.Errorf("expected the 2 floor retries of the window, got %d", allowed)
+		}
+	})
+
+	t.Run("router stops retrying when the budget is spent", func(t *testing.T) {
+		logger := logging.New("test")
+		mockMetrics := &mockMetrics{}
+
+		calls := 0
+		backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		registerTestService(t, serviceRegistry, "failing-backend", "tool_provider", backend.URL, nil)
+
+		config := DefaultRouterConfig()
+		config.EnablePluginRouting = false
+		config.RetryEnabled = true
+		config.RetryAttempts = 2
+		config.RetryBackoff = time.Millisecond
+		config.RetryBudget = RetryBudgetConfig{Ratio: 0.2}
+		mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+		for i := 0; i < 5; i++ {
+			w := httptest.NewRecorder()
+			mr.handleMCPRequest(w, newJSONRPCRequest(t, "tools/list", nil))
+
+			var resp map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+			}
+			if _, hasError := resp["error"]; !hasError {
+				t.Fatalf("expected the request to fail, got %s", w.Body.String())
+			}
+		}
+
+		// Each request earns a fifth of a retry, so only the fifth request
+		// finds a whole retry to spend
+		if calls != 6 {
+			t.Errorf("expected 5 requests and 1 retry to reach the backend, got %d calls", calls)
+		}
+	})
+
+	t.Run("invalid configs are rejected", func(t *testing.T) {
+		for name, invalid := range map[string]RetryBudgetConfig{
+			"ratio":  {Ratio: -0.1},
+			"floor":  {Ratio: 0.1, MinRetriesPerSecond: -1},
+			"window": {Ratio: 0.1, Window: -time.Second},
+		} {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/errors"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// rootsMetadataKey lets a backend of any type declare roots/list support in
+// its registration metadata; root_provider backends are always asked
+const rootsMetadataKey = "roots"
+
+// pluginRootsProvider is implemented by plugin handlers that can report the
+// roots plugins expose
+type pluginRootsProvider interface {
+	GetPluginRoots(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]mcpTypes.Root, error)
+}
+
+// maxRootsCacheEntries bounds the callers whose backend roots are cached
+const maxRootsCacheEntries = 1024
+
+// rootsCache holds the roots last listed by backends for each caller. An
+// entry is refreshed when its session sends notifications/roots/list_changed
+// or the set of root backends changes.
+type rootsCache struct {
+	mutex   sync.Mutex
+	entries map[Subscriber]rootsCacheEntry
+}
+
+type rootsCacheEntry struct {
+	services string
+	roots    []mcpTypes.Root
+	sources  int
+	stored   time.Time
+}
+
+func newRootsCache() *rootsCache {
+	return &rootsCache{entries: make(map[Subscriber]rootsCacheEntry)}
+}
+
+// get returns the roots cached for a caller and the number of backends that
+// had any, if they were listed by the same backends
+func (c *rootsCache) get(caller Subscriber, services string) ([]mcpTypes.Root, int, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[caller]
+	if !ok || entry.services != services {
+		return nil, 0, false
+	}
+	return entry.roots, entry.sources, true
+}
+
+// store caches roots for a caller, evicting the oldest entry when full
+func (c *rootsCache) store(caller Subscriber, services string, roots []mcpTypes.Root, sources int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.entries[caller]; !exists && len(c.entries) >= maxRootsCacheEntries {
+		var oldest Subscriber
+		var oldestStored time.Time
+		for key, entry := range c.entries {
+			if oldestStored.IsZero() || entry.stored.Before(oldestStored) {
+				oldest, oldestStored = key, entry.stored
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[caller] = rootsCacheEntry{services: services, roots: roots, sources: sources, stored: time.Now()}
+}
+
+func (c *rootsCache) invalidate(caller Subscriber) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, caller)
+}
+
+// routeRootsList answers roots/list with the roots of every plugin and
+// backend that declares them, with duplicate and nested roots removed
+func (mr *MCPRouter) routeRootsList(ctx context.Context, reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) (interface{}, error) {
+	var roots []mcpTypes.Root
+	sources := 0
+
+	if mr.config.EnablePluginRouting && mr.pluginHandler != nil {
+		if provider, ok := mr.pluginHandler.(pluginRootsProvider); ok {
+			for _, pluginName := range mr.pluginHandler.ListAvailablePlugins(reqCtx.Capabilities) {
+				pluginRoots, err := provider.GetPluginRoots(pluginName, reqCtx.Capabilities)
+				if err != nil {
+					mr.logger.Warn("failed_to_get_plugin_roots",
+						"plugin", pluginName,
+						"error", err)
+					continue
+				}
+				if len(pluginRoots) > 0 {
+					roots = append(roots, pluginRoots...)
+					sources++
+				}
+			}
+		}
+	}
+
+	backendRoots, backends := mr.backendRoots(ctx, reqCtx, mcpReq)
+	roots = append(roots, backendRoots...)
+	sources += backends
+
+	merged := mergeRoots(roots)
+
+	mr.metrics.Inc("mcp_roots_list_calls_total")
+	mr.logger.Info("roots_list_completed",
+		"request_id", reqCtx.RequestID,
+		"root_count", len(merged),
+		"duplicate_count", len(roots)-len(merged),
+		"source_count", sources)
+
+	return map[string]interface{}{
+		"roots": merged,
+	}, nil
+}
+
+// rootServices returns the available backends that provide roots
+func (mr *MCPRouter) rootServices() []*registry.RegisteredService {
+	if mr.registry == nil {
+		return nil
+	}
+
+	rootType := mr.determineServiceType("roots/list")
+	var services []*registry.RegisteredService
+	for _, service := range mr.registry.GetAllServices() {
+		if service.Status == registry.StatusUnavailable {
+			continue
+		}
+		declared, _ := service.Metadata[rootsMetadataKey].(bool)
+		if service.Type == rootType || declared {
+			services = append(services, service)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].ID < services[j].ID })
+	return services
+}
+
+// backendRoots lists the roots of every root backend, from the cache when
+// the same backends were last asked, and reports how many backends had roots
+func (mr *MCPRouter) backendRoots(ctx context.Context, reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest) ([]mcpTypes.Root, int) {
+	services := mr.rootServices()
+	ids := make([]string, len(services))
+	for i, service := range services {
+		ids[i] = service.ID
+	}
+	key := strings.Join(ids, ",")
+
+	caller := requestSubscriber(reqCtx)
+	if roots, sources, ok := mr.roots.get(caller, key); ok {
+		mr.metrics.Inc("mcp_roots_cache_hits_total")
+		return roots, sources
+	}
+
+	var roots []mcpTypes.Root
+	sources := 0
+	complete := true
+	for _, service := range services {
+		result, err := mr.forwardToService(ctx, reqCtx, service, mcpReq)
+		if err != nil {
+			complete = false
+			mr.logger.Warn("failed_to_get_backend_roots",
+				"request_id", reqCtx.RequestID,
+				"service_id", service.ID,
+				"error", err)
+			continue
+		}
+
+		serviceRoots, err := decodeRoots(result)
+		if err != nil {
+			complete = false
+			mr.logger.Warn("invalid_backend_roots",
+				"request_id", reqCtx.RequestID,
+				"service_id", service.ID,
+				"error", err)
+			continue
+		}
+		if len(serviceRoots) > 0 {
+			roots = append(roots, serviceRoots...)
+			sources++
+		}
+	}
+
+	// A failed backend is asked again on the next call rather than cached as rootless
+	if complete {
+		mr.roots.store(caller, key, roots, sources)
+	}
+	return roots, sources
+}
+
+// invalidateRoots handles notifications/roots/list_changed: backend roots are
+// listed again on the sending session's next roots/list. Other callers'
+// cached roots are left alone.
+func (mr *MCPRouter) invalidateRoots(reqCtx *RequestContext) error {
+	if reqCtx.SessionID == "" {
+		mr.metrics.Inc("mcp_roots_list_changed_rejected_total")
+		mr.logger.Warn("roots_list_changed_without_session",
+			"request_id", reqCtx.RequestID)
+		return errors.ValidationError("mcp_router", "roots_list_changed",
+			"notifications/roots/list_changed requires a session ID",
+			map[string]interface{}{
+				"header":     mr.config.SessionHeader,
+				"request_id": reqCtx.RequestID,
+			})
+	}
+	mr.roots.invalidate(requestSubscriber(reqCtx))
+
+	mr.metrics.Inc("mcp_roots_list_changed_total")
+	mr.logger.Info("roots_list_changed",
+		"request_id", reqCtx.RequestID,
+		"session_id", reqCtx.SessionID)
+	return nil
+}
+
+// decodeRoots reads the roots array of a backend roots/list result
+func decodeRoots(result interface{}) ([]mcpTypes.Root, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Roots []mcpTypes.Root `json:"roots"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	roots := list.Roots[:0]
+	for _, root := range list.Roots {
+		if root.URI != "" {
+			roots = append(roots, root)
+		}
+	}
+	return roots, nil
+}
+
+// mergeRoots sorts roots by URI and drops duplicates and roots nested inside
+// another root, which already exposes them. The first name seen for a URI wins.
+func mergeRoots(roots []mcpTypes.Root) []mcpTypes.Root {
+	byURI := make(map[string]mcpTypes.Root, len(roots))
+	for _, root := range roots {
+		uri := normalizeRootURI(root.URI)
+		if existing, ok := byURI[uri]; ok {
+			if existing.Name == "" {
+				existing.Name = root.Name
+				byURI[uri] = existing
+			}
+			continue
+		}
+		byURI[uri] = mcpTypes.Root{URI: uri, Name: root.Name}
+	}
+
+	sorted := make([]mcpTypes.Root, 0, len(byURI))
+	for _, root := range byURI {
+		sorted = append(sorted, root)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].URI < sorted[j].URI })
+
+	// Sorting places a root before every root nested in it
+	merged := make([]mcpTypes.Root, 0, len(sorted))
+	for _, root := range sorted {
+		nested := false
+		for _, parent := range merged {
+			if strings.HasPrefix(root.URI, strings.TrimSuffix(parent.URI, "/")+"/") {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			merged = append(merged, root)
+		}
+	}
+	return merged
+}
+
+// normalizeRootURI cleans the path of a hierarchical URI so that spellings
+// such as file:///srv/app/ and file:///srv/./app compare equal
+func normalizeRootURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme == "" || parsed.Opaque != "" {
+		return uri
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	if parsed.Path != "" {
+		parsed.Path = path.Clean(parsed.Path)
+		parsed.RawPath = ""
+	}
+	return parsed.String()
+}
@@ -0,0 +1,148 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// rootsPluginHandler serves fixed plugin roots
+type rootsPluginHandler struct {
+	fakePluginHandler
+	roots map[string][]mcpTypes.Root
+}
+
+func (h *rootsPluginHandler) ListAvailablePlugins(capabilities *rbac.ProcessedCapabilities) []string {
+	return []string{"editor", "git"}
+}
+
+func (h *rootsPluginHandler) GetPluginRoots(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]mcpTypes.Root, error) {
+	return h.roots[pluginName], nil
+}
+
+// newRootsBackend starts a backend answering roots/list and counts the calls
+func newRootsBackend(t *testing.T, calls *int32, roots ...mcpTypes.Root) string {
+	return newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  map[string]interface{}{"roots": roots},
+		})
+	}).URL
+}
+
+// TestRootsListAggregation tests that roots/list merges plugin and backend
+// roots, drops duplicates and nested roots, and refreshes on list_changed
+func TestRootsListAggregation(t *testing.T) {
+	logger := logging.New("test")
+	m := &mockMetrics{}
+	reg := newTestRegistry(logger, m)
+	t.Cleanup(func() { reg.Shutdown() })
+
+	var firstCalls, secondCalls int32
+	registerTestService(t, reg, "roots-a", "root_provider", newRootsBackend(t, &firstCalls,
+		mcpTypes.Root{URI: "file:///srv/app/", Name: "app"},
+		mcpTypes.Root{URI: "file:///srv/data"},
+	), nil)
+	registerTestService(t, reg, "roots-b", "tool_provider", newRootsBackend(t, &secondCalls,
+		mcpTypes.Root{URI: "file:///srv/data", Name: "data"},
+		mcpTypes.Root{URI: "file:///srv/app/src", Name: "app sources"},
+	), map[string]interface{}{"roots": true})
+
+	handler := &rootsPluginHandler{roots: map[string][]mcpTypes.Root{
+		"editor": {{URI: "file:///home/dev/project", Name: "editor working directory"}},
+		"git":    {{URI: "file:///home/dev/./project", Name: "git repository"}},
+	}}
+	mr := NewMCPRouterWithConfig(reg, handler, nil, logger, m, nil, DefaultRouterConfig())
+	reqCtx := &RequestContext{RequestID: "roots-request", SessionID: "session-1"}
+	request := &mcpTypes.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "roots/list"}
+
+	listRoots := func(t *testing.T) []mcpTypes.Root {
+		t.Helper()
+		result, err := mr.routeJSONRPCRequest(context.Background(), reqCtx, request)
+		if err != nil {
+			t.Fatalf("roots/list failed: %v", err)
+		}
+		return result.(map[string]interface{})["roots"].([]mcpTypes.Root)
+	}
+
+	expected := []mcpTypes.Root{
+		{URI: "file:///home/dev/project", Name: "editor working directory"},
+		{URI: "file:///srv/app", Name: "app"},
+		{URI: "file:///srv/data", Name: "data"},
+	}
+	if roots := listRoots(t); !reflect.DeepEqual(roots, expected) {
+		t.Fatalf("expected merged roots %v, got %v", expected, roots)
+	}
+
+	t.Run("backend roots are cached until list_changed", func(t *testing.T) {
+		listRoots(t)
+		if firstCalls != 1 || secondCalls != 1 {
+			t.Fatalf("expected each backend to be asked once, got %d and %d", firstCalls, secondCalls)
+		}
+
+		notification := &mcpTypes.JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/roots/list_changed"}
+		if _, err := mr.routeJSONRPCRequest(context.Background(), reqCtx, notification); err != nil {
+			t.Fatalf("list_changed notification failed: %v", err)
+		}
+		listRoots(t)
+		if firstCalls != 2 || secondCalls != 2 {
+			t.Errorf("expected backends to be asked again after list_changed, got %d and %d", firstCalls, secondCalls)
+		}
+	})
+
+	t.Run("list_changed only affects the sending session", func(t *testing.T) {
+		published := 0
+		mr.SetNotificationPublisher(func(method string, params interface{}, audience []Subscriber) {
+			published++
+		})
+		defer mr.SetNotificationPublisher(nil)
+
+		notification := &mcpTypes.JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/roots/list_changed"}
+		other := &RequestContext{RequestID: "other-request", SessionID: "session-2"}
+		if _, err := mr.routeJSONRPCRequest(context.Background(), other, notification); err != nil {
+			t.Fatalf("list_changed notification failed: %v", err)
+		}
+		listRoots(t)
+		if firstCalls != 2 || secondCalls != 2 {
+			t.Errorf("expected another session's list_changed to keep the cached roots, got %d and %d calls", firstCalls, secondCalls)
+		}
+		if published != 0 {
+			t.Errorf("expected list_changed not to be broadcast, got %d notifications", published)
+		}
+
+		sessionless := &RequestContext{RequestID: "sessionless-request"}
+		if _, err := mr.routeJSONRPCRequest(context.Background(), sessionless, notification); err == nil {
+			t.Error("expected list_changed without a session to be rejected")
+		}
+	})
+}
+
+// TestMergeRoots tests root normalization and deduplication
+func TestMergeRoots(t *testing.T) {
+	roots := mergeRoots([]mcpTypes.Root{
+		{URI: "file:///a/b/c"},
+		{URI: "file:///a/b/", Name: ""},
+		{URI: "FILE:///a/b", Name: "b"},
+		{URI: "file:///a/bc", Name: "sibling"},
+		{URI: "https://example.com/repo", Name: "remote"},
+		{URI: "https://example.com/repo/sub"},
+	})
+
+	expected := []mcpTypes.Root{
+		{URI: "file:///a/b", Name: "b"},
+		{URI: "file:///a/bc", Name: "sibling"},
+		{URI: "https://example.com/repo", Name: "remote"},
+	}
+	if !reflect.DeepEqual(roots, expected) {
+		t.Errorf("expected %v, got %v", expected, roots)
+	}
+}
@@ -0,0 +1,242 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestServiceSelection tests that /mcp requests reach the backend instance
+// chosen by the registry's load balancer rather than a fixed one
+func TestServiceSelection(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	t.Run("canary split applies to live traffic", func(t *testing.T) {
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		registerNamedService(t, serviceRegistry, "stable", "1.0.0", nil)
+		registerNamedService(t, serviceRegistry, "canary", "2.0.0", nil)
+
+		if err := serviceRegistry.GetLoadBalancer().SetCanaryWeights("tool_provider", map[string]int{"1.0.0": 0, "2.0.0": 100}); err != nil {
+			t.Fatalf("failed to set canary weights: %v", err)
+		}
+		mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+		for i := 0; i < 5; i++ {
+			if served := sendSelectionRequest(t, mr, nil); served != "canary" {
+				t.Fatalf("expected all traffic on the canary version, got %s", served)
+			}
+		}
+	})
+
+	t.Run("strategy changes alter the chosen backend", func(t *testing.T) {
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		registerNamedService(t, serviceRegistry, "first", "1.0.0", nil)
+		registerNamedService(t, serviceRegistry, "second", "1.0.0", nil)
+		mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+		session := map[string]string{"X-Session-ID": "session-1"}
+		served := func() map[string]int {
+			counts := make(map[string]int)
+			for i := 0; i < 4; i++ {
+				counts[sendSelectionRequest(t, mr, session)]++
+			}
+			return counts
+		}
+
+		if counts := served(); counts["first"] != 2 || counts["second"] != 2 {
+			t.Fatalf("expected round robin to alternate between backends, got %v", counts)
+		}
+
+		// The admin strategy endpoint makes the same call
+		if err := serviceRegistry.GetLoadBalancer().SetStrategy("tool_provider", "hash"); err != nil {
+			t.Fatalf("failed to set strategy: %v", err)
+		}
+		if counts := served(); len(counts) != 1 {
+			t.Errorf("expected hashing to pin the session to one backend, got %v", counts)
+		}
+	})
+
+	t.Run("session header pins sessions under consistent hashing", func(t *testing.T) {
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		for _, name := range []string{"first", "second", "third"} {
+			registerNamedService(t, serviceRegistry, name, "1.0.0", nil)
+		}
+		if err := serviceRegistry.GetLoadBalancer().SetStrategy("", "hash"); err != nil {
+			t.Fatalf("failed to set strategy: %v", err)
+		}
+		mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+		used := make(map[string]bool)
+		for i := 0; i < 30; i++ {
+			session := map[string]string{"X-Session-ID": fmt.Sprintf("session-%d", i)}
+			first := sendSelectionRequest(t, mr, session)
+			for j := 0; j < 3; j++ {
+				if next := sendSelectionRequest(t, mr, session); next != first {
+					t.Fatalf("session-%d moved from %s to %s", i, first, next)
+				}
+			}
+			used[first] = true
+		}
+		if len(used) < 2 {
+			t.Errorf("expected sessions to spread across backends, got %v", used)
+		}
+	})
+
+	t.Run("same region is preferred with cross-region fallback", func(t *testing.T) {
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		registerNamedService(t, serviceRegistry, "eu-a", "1.0.0", map[string]interface{}{"region": "eu-west", "zone": "a"})
+		registerNamedService(t, serviceRegistry, "eu-b", "1.0.0", map[string]interface{}{"region": "eu-west", "zone": "b"})
+		registerNamedService(t, serviceRegistry, "us", "1.0.0", map[string]interface{}{"region": "us-east"})
+		mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+		for i := 0; i < 4; i++ {
+			if served := sendSelectionRequest(t, mr, map[string]string{"X-Client-Region": "us-east"}); served != "us" {
+				t.Fatalf("expected the us-east backend, got %s", served)
+			}
+			if served := sendSelectionRequest(t, mr, map[string]string{"X-Client-Region": "eu-west", "X-Client-Zone": "b"}); served != "eu-b" {
+				t.Fatalf("expected the eu-west zone b backend, got %s", served)
+			}
+		}
+
+		counts := make(map[string]int)
+		for i := 0; i < 6; i++ {
+			counts[sendSelectionRequest(t, mr, map[string]string{"X-Client-Region": "ap-south"})]++
+		}
+		if len(counts) != 3 {
+			t.Errorf("expected a region without backends to fall back to all of them, got %v", counts)
+		}
+	})
+
+	t.Run("warming instances receive no traffic", func(t *testing.T) {
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		registerNamedService(t, serviceRegistry, "active", "1.0.0", nil)
+
+		// The warming backend never passes its health probe
+		warming := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"warming","description":"d"}]}}`))
+		}))
+		defer warming.Close()
+		if _, err := serviceRegistry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+			Name:         "warming",
+			Type:         "tool_provider",
+			Version:      "1.0.0",
+			Endpoint:     warming.URL,
+			Protocol:     "http",
+			WarmupPeriod: time.Minute,
+		}); err != nil {
+			t.Fatalf("failed to register service warming: %v", err)
+		}
+		mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+		for i := 0; i < 4; i++ {
+			if served := sendSelectionRequest(t, mr, nil); served != "active" {
+				t.Fatalf("expected only the active backend to be used, got %s", served)
+			}
+		}
+	})
+
+	t.Run("load-aware strategy shifts traffic to the faster backend", func(t *testing.T) {
+		serviceRegistry := newTestRegistry(logger, mockMetrics)
+		defer serviceRegistry.Shutdown()
+		registerNamedService(t, serviceRegistry, "fast", "1.0.0", nil)
+
+		slow := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(20 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":"slow","description":"d"}]}}`))
+		})
+		if _, err := serviceRegistry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+			Name:     "slow",
+			Type:     "tool_provider",
+			Version:  "1.0.0",
+			Endpoint: slow.URL,
+			Protocol: "http",
+		}); err != nil {
+			t.Fatalf("failed to register service slow: %v", err)
+		}
+
+		if err := serviceRegistry.GetLoadBalancer().SetStrategy("", "load_aware"); err != nil {
+			t.Fatalf("failed to set strategy: %v", err)
+		}
+		mr := NewMCPRouter(serviceRegistry, nil, nil, logger, mockMetrics, nil)
+
+		counts := make(map[string]int)
+		for i := 0; i < 20; i++ {
+			counts[sendSelectionRequest(t, mr, nil)]++
+		}
+		if counts["slow"] == 0 || counts["slow"] > 3 {
+			t.Errorf("expected the slow backend to be sampled and then avoided, got %v", counts)
+		}
+	})
+}
+
+// registerNamedService registers a tool provider whose tools/list result
+// names it, so tests can tell which instance served a request
+func registerNamedService(t *testing.T, sr *registry.ServiceRegistry, name, version string, metadata map[string]interface{}) string {
+	t.Helper()
+
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"tools":[{"name":%q,"description":"d"}]}}`, name)
+	})
+
+	resp, err := sr.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+		Name:     name,
+		Type:     "tool_provider",
+		Version:  version,
+		Endpoint: backend.URL,
+		Protocol: "http",
+		Metadata: metadata,
+	})
+	if err != nil {
+		t.Fatalf("failed to register service %s: %v", name, err)
+	}
+	return resp.ServiceID
+}
+
+// sendSelectionRequest sends tools/list through /mcp and returns the name of
+// the backend that answered it
+func sendSelectionRequest(t *testing.T, mr *MCPRouter, headers map[string]string) string {
+	t.Helper()
+
+	req := newJSONRPCRequest(t, "tools/list", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	mr.handleMCPRequest(w, req)
+
+	var resp struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	if resp.Error != nil || len(resp.Result.Tools) != 1 {
+		t.Fatalf("expected one tool from the serving backend, got %s", w.Body.String())
+	}
+	return resp.Result.Tools[0].Name
+}
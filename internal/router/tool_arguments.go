@@ -0,0 +1,118 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// ToolSchemaLookup returns the input schema a plugin publishes for one of its
+// tools. ok is false when the tool is unknown or publishes no input schema.
+type ToolSchemaLookup func(pluginName, toolName string) (schema map[string]interface{}, ok bool)
+
+// SetToolSchemaLookup sets where tools/call finds the input schemas plugin
+// tool arguments are checked against
+func (mr *MCPRouter) SetToolSchemaLookup(lookup ToolSchemaLookup) {
+	mr.toolSchemas = lookup
+}
+
+// validateToolArguments checks plugin tool arguments against the tool's
+// cached input schema. Tools without a schema accept any arguments.
+func (mr *MCPRouter) validateToolArguments(reqCtx *RequestContext, pluginName, toolName string, arguments map[string]interface{}) error {
+	if mr.toolSchemas == nil {
+		return nil
+	}
+	schema, ok := mr.toolSchemas(pluginName, toolName)
+	if !ok {
+		return nil
+	}
+
+	problems := checkToolArguments(schema, arguments)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	mr.metrics.Inc("tool_argument_validation_failures_total", "plugin", pluginName, "tool", toolName)
+	mr.logger.Warn("tool_arguments_invalid",
+		"request_id", reqCtx.RequestID,
+		"plugin", pluginName,
+		"tool", toolName,
+		"problems", problems)
+
+	return errors.ValidationError("mcp_router", "tool_arguments",
+		fmt.Sprintf("Invalid arguments for tool %s: %s", toolName, strings.Join(problems, "; ")),
+		map[string]interface{}{
+			"plugin":     pluginName,
+			"tool":       toolName,
+			"problems":   problems,
+			"request_id": reqCtx.RequestID,
+		})
+}
+
+// checkToolArguments applies the top level of an object input schema:
+// required properties, declared property types and additionalProperties
+func checkToolArguments(schema, arguments map[string]interface{}) []string {
+	var problems []string
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, present := arguments[name]; !present {
+					problems = append(problems, fmt.Sprintf("missing required argument %s", name))
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, declared := properties[name].(map[string]interface{})
+		if !declared {
+			if allowed, ok := schema["additionalProperties"].(bool); ok && !allowed {
+				problems = append(problems, fmt.Sprintf("unexpected argument %s", name))
+			}
+			continue
+		}
+		if expected, ok := property["type"].(string); ok && !jsonTypeMatches(expected, arguments[name]) {
+			problems = append(problems, fmt.Sprintf("argument %s must be of type %s", name, expected))
+		}
+	}
+
+	return problems
+}
+
+// jsonTypeMatches reports whether a decoded JSON value has a JSON Schema type.
+// Unknown types match anything.
+func jsonTypeMatches(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
@@ -0,0 +1,351 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+const (
+	defaultToolAuditTimeout    = 5 * time.Second
+	defaultToolAuditBufferSize = 1024
+)
+
+// ToolAuditConfig configures an audit record of every tools/call. Records
+// carry hashes of the redacted arguments and of the result instead of the
+// values, proving what was exchanged without storing it. Exactly one of
+// FilePath or Endpoint must be set when enabled.
+type ToolAuditConfig struct {
+	Enabled  bool          `yaml:"enabled" json:"enabled"`
+	FilePath string        `yaml:"file_path" json:"file_path"` // Records are appended as JSON lines
+	Endpoint string        `yaml:"endpoint" json:"endpoint"`   // Records are POSTed as JSON
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`     // Endpoint request timeout
+
+	// Records queued for the sink while it writes; 1024 by default. Records
+	// arriving while the queue is full are dropped.
+	BufferSize int `yaml:"buffer_size" json:"buffer_size"`
+
+	// Optional key making the hashes HMAC-SHA256, so guessable values such as
+	// email addresses cannot be confirmed by hashing candidates
+	HashKey string `yaml:"hash_key" json:"-"`
+}
+
+// Validate checks that an enabled tool audit config names exactly one sink
+func (c ToolAuditConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if (c.FilePath == "") == (c.Endpoint == "") {
+		return fmt.Errorf("exactly one of file_path or endpoint must be set")
+	}
+	if c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "http://") && !strings.HasPrefix(c.Endpoint, "https://") {
+		return fmt.Errorf("endpoint must be an http or https URL, got %s", c.Endpoint)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", c.Timeout)
+	}
+	if c.BufferSize < 0 {
+		return fmt.Errorf("buffer_size must not be negative, got %d", c.BufferSize)
+	}
+	return nil
+}
+
+// ToolAuditRecord records a tool call. Hashes are prefixed with their
+// algorithm and computed over the canonical JSON of the value, with object
+// keys sorted; arguments are redacted with the body logging redact paths
+// before hashing.
+type ToolAuditRecord struct {
+	Timestamp     time.Time `json:"timestamp"` // When the call started
+	RequestID     string    `json:"request_id"`
+	Tool          string    `json:"tool"`
+	ClientID      string    `json:"client_id,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+	ServiceID     string    `json:"service_id,omitempty"`
+	ArgumentsHash string    `json:"arguments_hash"`
+	ResultHash    string    `json:"result_hash,omitempty"` // Unset when the call failed
+	DurationMs    float64   `json:"duration_ms"`
+	Success       bool      `json:"success"`
+	ErrorCode     int       `json:"error_code,omitempty"`
+	Rejected      bool      `json:"rejected,omitempty"` // Refused before routing, such as by authentication
+}
+
+// ToolAuditSink stores tool audit records
+type ToolAuditSink interface {
+	Write(record ToolAuditRecord) error
+}
+
+// newToolAuditSink returns the sink described by config, or nil when disabled
+func newToolAuditSink(config ToolAuditConfig) (ToolAuditSink, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.FilePath != "" {
+		return &fileToolAuditSink{path: config.FilePath}, nil
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = defaultToolAuditTimeout
+	}
+	return &httpToolAuditSink{
+		endpoint: config.Endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// fileToolAuditSink appends records to a file as JSON lines
+type fileToolAuditSink struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func (s *fileToolAuditSink) Write(record ToolAuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool audit record: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open tool audit file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write tool audit record: %w", err)
+	}
+	return nil
+}
+
+// httpToolAuditSink POSTs each record to an endpoint
+type httpToolAuditSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (s *httpToolAuditSink) Write(record ToolAuditRecord) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool audit record: %w", err)
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send tool audit record: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("tool audit endpoint returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// toolAuditWriter hands records to the sink on a background goroutine, so a
+// slow file or endpoint never holds up the response
+type toolAuditWriter struct {
+	sink    ToolAuditSink
+	records chan ToolAuditRecord
+	done    chan struct{}
+
+	// Guards closing records against concurrent enqueues
+	mutex  sync.RWMutex
+	closed bool
+}
+
+func (mr *MCPRouter) newToolAuditWriter(sink ToolAuditSink, bufferSize int) *toolAuditWriter {
+	if bufferSize <= 0 {
+		bufferSize = defaultToolAuditBufferSize
+	}
+	w := &toolAuditWriter{
+		sink:    sink,
+		records: make(chan ToolAuditRecord, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go mr.runToolAuditWriter(w)
+	return w
+}
+
+// enqueue queues a record, reporting false when the queue is full or closed
+func (w *toolAuditWriter) enqueue(record ToolAuditRecord) bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		return false
+	}
+	select {
+	case w.records <- record:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops accepting records and waits until the queued ones are written
+func (w *toolAuditWriter) close(ctx context.Context) error {
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.records)
+	}
+	w.mutex.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tool audit records still queued: %w", ctx.Err())
+	}
+}
+
+// runToolAuditWriter writes queued records until the writer is closed. A
+// sink failure is logged and counted but never reaches the call.
+func (mr *MCPRouter) runToolAuditWriter(w *toolAuditWriter) {
+	defer close(w.done)
+	for record := range w.records {
+		if err := w.sink.Write(record); err != nil {
+			mr.metrics.Inc("mcp_tool_audit_write_failures_total", "tool", record.Tool)
+			mr.logger.Error("mcp_tool_audit_write_failed",
+				"request_id", record.RequestID,
+				"tool", record.Tool,
+				"error", err)
+			continue
+		}
+		mr.metrics.Inc("mcp_tool_audit_records_total", "tool", record.Tool, "success", fmt.Sprintf("%t", record.Success))
+	}
+}
+
+// SetToolAuditSink replaces the sink receiving tool audit records; nil
+// disables it. Records already queued for the previous sink are still
+// written to it.
+func (mr *MCPRouter) SetToolAuditSink(sink ToolAuditSink) {
+	previous := mr.toolAudit
+	mr.toolAudit = nil
+	if sink != nil {
+		mr.toolAudit = mr.newToolAuditWriter(sink, mr.config.ToolAudit.BufferSize)
+	}
+	if previous != nil {
+		go previous.close(context.Background())
+	}
+}
+
+// CloseToolAudit writes the queued tool audit records and stops the writer;
+// later calls are not audited. It gives up when ctx is done.
+func (mr *MCPRouter) CloseToolAudit(ctx context.Context) error {
+	if mr.toolAudit == nil {
+		return nil
+	}
+	return mr.toolAudit.close(ctx)
+}
+
+// auditToolCall records a tools/call that was routed, with the result
+// returned to the client or the error that failed it
+func (mr *MCPRouter) auditToolCall(reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest, result interface{}, callErr error) {
+	mr.writeToolAudit(reqCtx, mcpReq, func(record *ToolAuditRecord) {
+		record.Success = callErr == nil
+		if callErr == nil {
+			record.ResultHash = mr.toolAuditHash(canonicalJSONValue(result))
+		} else {
+			record.ErrorCode = errors.ToJSONRPC(callErr).Code
+		}
+	})
+}
+
+// auditToolRejection records a tools/call refused before routing, with the
+// JSON-RPC error code the client received
+func (mr *MCPRouter) auditToolRejection(reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest, code int) {
+	mr.writeToolAudit(reqCtx, mcpReq, func(record *ToolAuditRecord) {
+		record.Rejected = true
+		record.ErrorCode = code
+	})
+}
+
+// writeToolAudit queues the record of a tools/call, completed by outcome.
+// A full queue drops the record, which is logged and counted.
+func (mr *MCPRouter) writeToolAudit(reqCtx *RequestContext, mcpReq *mcpTypes.JSONRPCRequest, outcome func(*ToolAuditRecord)) {
+	if mr.toolAudit == nil || mcpReq.Method != "tools/call" {
+		return
+	}
+
+	params, _ := canonicalJSONValue(mcpReq.Params).(map[string]interface{})
+	for _, path := range mr.config.BodyLogging.RedactPaths {
+		redactJSONPath(params, strings.Split(path, "."))
+	}
+	tool, _ := params["name"].(string)
+
+	record := ToolAuditRecord{
+		Timestamp:     reqCtx.StartTime.UTC(),
+		RequestID:     reqCtx.RequestID,
+		Tool:          tool,
+		ClientID:      reqCtx.ClientID,
+		UserID:        reqCtx.UserID,
+		ServiceID:     reqCtx.ServiceID,
+		ArgumentsHash: mr.toolAuditHash(params["arguments"]),
+		DurationMs:    float64(time.Since(reqCtx.StartTime).Microseconds()) / 1000,
+	}
+	outcome(&record)
+
+	if !mr.toolAudit.enqueue(record) {
+		mr.metrics.Inc("mcp_tool_audit_records_dropped_total", "tool", tool)
+		mr.logger.Error("mcp_tool_audit_record_dropped",
+			"request_id", reqCtx.RequestID,
+			"tool", tool)
+	}
+}
+
+// toolAuditHash hashes the canonical JSON of a decoded value, encoded
+// without HTML escaping so other tools can reproduce it
+func (mr *MCPRouter) toolAuditHash(value interface{}) string {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return ""
+	}
+
+	var h hash.Hash
+	algorithm := "sha256"
+	if key := mr.config.ToolAudit.HashKey; key != "" {
+		h = hmac.New(sha256.New, []byte(key))
+		algorithm = "hmac-sha256"
+	} else {
+		h = sha256.New()
+	}
+	h.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return algorithm + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalJSONValue round-trips a value through JSON into generic maps and
+// slices, which marshal with sorted keys, so equal documents hash equally
+// and redaction never touches the live value
+func canonicalJSONValue(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil
+	}
+	return generic
+}
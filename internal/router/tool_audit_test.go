@@ -0,0 +1,260 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestToolAudit tests that tool calls produce audit records whose argument
+// hashes ignore key order and redacted values, and whose result hashes match
+// the result returned to the client
+func TestToolAudit(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params struct {
+				Name string `json:"name"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Params.Name == "broken" {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"tool crashed"}}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"found <3> results"}]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "tools", "tool_provider", backend.URL, nil)
+
+	auditPath := filepath.Join(t.TempDir(), "tool_audit.jsonl")
+	config := DefaultRouterConfig()
+	config.ToolAudit = ToolAuditConfig{Enabled: true, FilePath: auditPath}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	call := func(t *testing.T, params map[string]interface{}) json.RawMessage {
+		t.Helper()
+		req := newJSONRPCRequest(t, "tools/call", params)
+		req.Header.Set("X-Client-ID", "client-a")
+		rec := httptest.NewRecorder()
+		mr.handleMCPRequest(rec, req)
+
+		var resp struct {
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp.Result
+	}
+
+	result := call(t, map[string]interface{}{
+		"name":      "search",
+		"arguments": map[string]interface{}{"query": "alice@example.com", "api_key": "first-secret"},
+	})
+	call(t, map[string]interface{}{
+		"arguments": map[string]interface{}{"api_key": "second-secret", "query": "alice@example.com"},
+		"name":      "search",
+	})
+	call(t, map[string]interface{}{
+		"name":      "search",
+		"arguments": map[string]interface{}{"query": "bob@example.com"},
+	})
+	call(t, map[string]interface{}{"name": "broken"})
+
+	if err := mr.CloseToolAudit(context.Background()); err != nil {
+		t.Fatalf("failed to flush tool audit: %v", err)
+	}
+	records := readToolAuditRecords(t, auditPath)
+	if len(records) != 4 {
+		t.Fatalf("expected 4 audit records, got %+v", records)
+	}
+
+	t.Run("record identifies the call", func(t *testing.T) {
+		record := records[0]
+		if record.Tool != "search" || record.ClientID != "client-a" || record.ServiceID == "" {
+			t.Errorf("expected tool, caller and service recorded, got %+v", record)
+		}
+		if !record.Success || record.RequestID == "" || record.Timestamp.IsZero() || record.DurationMs < 0 {
+			t.Errorf("expected a timed successful call, got %+v", record)
+		}
+	})
+
+	t.Run("arguments hash covers redacted canonical arguments", func(t *testing.T) {
+		expected := sha256Hex(`{"api_key":"***","query":"alice@example.com"}`)
+		if records[0].ArgumentsHash != expected {
+			t.Errorf("expected arguments hash %s, got %s", expected, records[0].ArgumentsHash)
+		}
+		if records[1].ArgumentsHash != records[0].ArgumentsHash {
+			t.Error("expected the same redacted arguments in another key order to hash equally")
+		}
+		if records[2].ArgumentsHash == records[0].ArgumentsHash {
+			t.Error("expected different arguments to hash differently")
+		}
+	})
+
+	t.Run("result hash matches the result returned", func(t *testing.T) {
+		expected := sha256Hex(`{"content":[{"text":"found <3> results","type":"text"}]}`)
+		if records[0].ResultHash != expected {
+			t.Errorf("expected result hash %s, got %s", expected, records[0].ResultHash)
+		}
+		var returned interface{}
+		if err := json.Unmarshal(result, &returned); err != nil {
+			t.Fatalf("failed to decode result: %v", err)
+		}
+		if hash := mr.toolAuditHash(returned); hash != records[0].ResultHash {
+			t.Errorf("expected the returned result to hash to %s, got %s", records[0].ResultHash, hash)
+		}
+	})
+
+	t.Run("failed call is recorded without a result", func(t *testing.T) {
+		record := records[3]
+		if record.Success || record.ErrorCode == 0 || record.ResultHash != "" {
+			t.Errorf("expected a failed call with an error code, got %+v", record)
+		}
+		if record.ArgumentsHash != sha256Hex("null") {
+			t.Errorf("expected missing arguments to hash as null, got %s", record.ArgumentsHash)
+		}
+	})
+
+	t.Run("hash key switches to HMAC", func(t *testing.T) {
+		keyed := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, func() RouterConfig {
+			keyedConfig := config
+			keyedConfig.ToolAudit.HashKey = "audit-key"
+			return keyedConfig
+		}())
+		hash := keyed.toolAuditHash(map[string]interface{}{"query": "alice@example.com"})
+		if hash[:12] != "hmac-sha256:" || hash == mr.toolAuditHash(map[string]interface{}{"query": "alice@example.com"}) {
+			t.Errorf("expected an HMAC hash, got %s", hash)
+		}
+	})
+
+	t.Run("calls rejected before routing are recorded", func(t *testing.T) {
+		rejectingConfig := DefaultRouterConfig()
+		rejectingConfig.RequireAuthentication = true
+		rejecting := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, rejectingConfig)
+		sink := &recordingToolAuditSink{}
+		rejecting.SetToolAuditSink(sink)
+
+		req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "search"})
+		rejecting.handleMCPRequest(httptest.NewRecorder(), req)
+		if err := rejecting.CloseToolAudit(context.Background()); err != nil {
+			t.Fatalf("failed to flush tool audit: %v", err)
+		}
+
+		recorded := sink.all()
+		if len(recorded) != 1 {
+			t.Fatalf("expected the rejected call to be recorded, got %+v", recorded)
+		}
+		if record := recorded[0]; !record.Rejected || record.Success || record.Tool != "search" || record.ErrorCode != mcpTypes.ErrorCodeUnauthorized {
+			t.Errorf("expected an unauthorized rejection of search, got %+v", record)
+		}
+	})
+
+	t.Run("a slow sink does not hold up the call", func(t *testing.T) {
+		sink := &recordingToolAuditSink{release: make(chan struct{})}
+		slow := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, DefaultRouterConfig())
+		slow.SetToolAuditSink(sink)
+
+		done := make(chan struct{})
+		go func() {
+			req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "search"})
+			slow.handleMCPRequest(httptest.NewRecorder(), req)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the call to finish while the sink is blocked")
+		}
+
+		close(sink.release)
+		if err := slow.CloseToolAudit(context.Background()); err != nil {
+			t.Fatalf("failed to flush tool audit: %v", err)
+		}
+		if recorded := sink.all(); len(recorded) != 1 || !recorded[0].Success {
+			t.Errorf("expected the call to be recorded once the sink resumed, got %+v", recorded)
+		}
+	})
+
+	t.Run("invalid configs are rejected", func(t *testing.T) {
+		for name, invalid := range map[string]ToolAuditConfig{
+			"no sink":         {Enabled: true},
+			"two sinks":       {Enabled: true, FilePath: auditPath, Endpoint: "http://localhost/audit"},
+			"endpoint scheme": {Enabled: true, Endpoint: "ftp://localhost/audit"},
+			"negative buffer": {Enabled: true, FilePath: auditPath, BufferSize: -1},
+		} {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
+
+// recordingToolAuditSink keeps records in memory, blocking each write until
+// release is closed when set
+type recordingToolAuditSink struct {
+	release chan struct{}
+	mutex   sync.Mutex
+	records []ToolAuditRecord
+}
+
+func (s *recordingToolAuditSink) Write(record ToolAuditRecord) error {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *recordingToolAuditSink) all() []ToolAuditRecord {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]ToolAuditRecord(nil), s.records...)
+}
+
+func sha256Hex(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func readToolAuditRecords(t *testing.T, path string) []ToolAuditRecord {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open tool audit file: %v", err)
+	}
+	defer file.Close()
+
+	var records []ToolAuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record ToolAuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid tool audit record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
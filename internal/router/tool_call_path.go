@@ -0,0 +1,63 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// ToolCallPathPrefix is where each tool can be called on its own path: a POST
+// to ToolCallPathPrefix+name with the tool arguments as the JSON body is
+// served as a tools/call request for that tool, and answered with the
+// JSON-RPC response.
+const ToolCallPathPrefix = "/mcp/tools/call/"
+
+// handleToolCallPath wraps the arguments posted to a tool's path in a
+// tools/call request and serves it like any other /mcp request
+func (mr *MCPRouter) handleToolCallPath(w http.ResponseWriter, r *http.Request) {
+	toolName := mux.Vars(r)["name"]
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, mr.config.MaxRequestSize+1))
+	if err != nil {
+		mr.writeErrorResponse(w, nil, mcpTypes.ErrorCodeParseError, "Invalid request body", err)
+		return
+	}
+	if int64(len(body)) > mr.config.MaxRequestSize {
+		mr.writeErrorResponse(w, nil, mcpTypes.ErrorCodeParseError, "Invalid request body",
+			fmt.Errorf("request too large: more than %d bytes", mr.config.MaxRequestSize))
+		return
+	}
+
+	arguments := json.RawMessage(bytes.TrimSpace(body))
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(arguments, &object); err != nil || object == nil {
+		mr.writeErrorResponse(w, nil, mcpTypes.ErrorCodeParseError, "Invalid request body",
+			fmt.Errorf("tool arguments must be a JSON object"))
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": toolName, "arguments": arguments},
+	})
+	if err != nil {
+		mr.writeErrorResponse(w, nil, mcpTypes.ErrorCodeInternalError, "Internal error", err)
+		return
+	}
+
+	call := r.Clone(r.Context())
+	call.Body = io.NopCloser(bytes.NewReader(payload))
+	call.ContentLength = int64(len(payload))
+	call.Header.Set("Content-Type", "application/json")
+	mr.handleMCPRequest(w, call)
+}
@@ -0,0 +1,167 @@
+package router
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// defaultToolRateLimitWindow applies to limits configured without a window
+const defaultToolRateLimitWindow = time.Minute
+
+// maxToolRateLimitEntries bounds the tracked windows; the least recently
+// used is dropped to make room for a new one
+const maxToolRateLimitEntries = 10000
+
+// ToolRateLimit caps tools/call for tools matching Tool, an exact name or a
+// pattern where * matches any run of characters, at Limit calls per Window.
+// The count is shared by every caller unless PerClient is set, in which case
+// each authenticated user has its own count and unauthenticated callers share
+// one. The first matching entry applies; this is independent of the HTTP rate
+// limit.
+type ToolRateLimit struct {
+	Tool      string        `yaml:"tool" json:"tool"`
+	Limit     int           `yaml:"limit" json:"limit"`
+	Window    time.Duration `yaml:"window" json:"window"` // 1m by default
+	PerClient bool          `yaml:"per_client" json:"per_client"`
+}
+
+// ValidateToolRateLimits checks that every limit names a tool and allows at
+// least one call
+func ValidateToolRateLimits(limits []ToolRateLimit) error {
+	for i, limit := range limits {
+		if strings.TrimSpace(limit.Tool) == "" {
+			return fmt.Errorf("limit %d: tool is required", i)
+		}
+		if limit.Limit <= 0 {
+			return fmt.Errorf("limit %d (%s): limit must be positive, got %d", i, limit.Tool, limit.Limit)
+		}
+		if limit.Window < 0 {
+			return fmt.Errorf("limit %d (%s): window must not be negative, got %s", i, limit.Tool, limit.Window)
+		}
+	}
+	return nil
+}
+
+// toolRateLimiter counts tool calls in fixed windows per tool, or per tool
+// and user, keeping at most maxEntries windows
+type toolRateLimiter struct {
+	mu         sync.Mutex
+	maxEntries int
+	windows    map[string]*list.Element
+	recent     *list.List // Most recently used window at the front
+}
+
+// toolRateWindow is one count with its own window length, since each limit
+// sets its own
+type toolRateWindow struct {
+	key    string
+	start  time.Time
+	window time.Duration
+	count  int
+}
+
+func newToolRateLimiter() *toolRateLimiter {
+	return &toolRateLimiter{
+		maxEntries: maxToolRateLimitEntries,
+		windows:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+// allow counts a call against key and reports how long until the window
+// resets when the limit is already reached
+func (l *toolRateLimiter) allow(key string, limit int, window time.Duration, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, exists := l.windows[key]
+	if !exists {
+		if l.recent.Len() >= l.maxEntries {
+			oldest := l.recent.Back()
+			l.recent.Remove(oldest)
+			delete(l.windows, oldest.Value.(*toolRateWindow).key)
+		}
+		l.windows[key] = l.recent.PushFront(&toolRateWindow{key: key, start: now, window: window, count: 1})
+		return true, 0
+	}
+
+	l.recent.MoveToFront(element)
+	w := element.Value.(*toolRateWindow)
+	if now.Sub(w.start) >= w.window {
+		w.start, w.window, w.count = now, window, 1
+		return true, 0
+	}
+	if w.count >= limit {
+		return false, w.start.Add(w.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// toolRateLimitFor returns the first configured limit matching a tool
+func (mr *MCPRouter) toolRateLimitFor(toolName string) (ToolRateLimit, bool) {
+	for _, limit := range mr.config.ToolRateLimits {
+		if wildcardMatch(limit.Tool, toolName) {
+			if limit.Window <= 0 {
+				limit.Window = defaultToolRateLimitWindow
+			}
+			return limit, true
+		}
+	}
+	return ToolRateLimit{}, false
+}
+
+// checkToolRateLimit rejects a tools/call once its tool has used up the calls
+// its limit allows in the current window
+func (mr *MCPRouter) checkToolRateLimit(reqCtx *RequestContext, params interface{}) error {
+	paramMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	toolName, _ := paramMap["name"].(string)
+	if toolName == "" {
+		return nil
+	}
+
+	limit, ok := mr.toolRateLimitFor(toolName)
+	if !ok {
+		return nil
+	}
+
+	// Keyed by the matched pattern so tools sharing a wildcard limit are
+	// still counted separately. Per-client counts follow the authenticated
+	// user, not the client-supplied X-Client-ID, so a caller cannot get a
+	// fresh count by changing headers.
+	key := limit.Tool + "\x00" + toolName
+	user := requestSubscriber(reqCtx).UserID
+	if limit.PerClient {
+		key += "\x00" + user
+	}
+
+	allowed, retryAfter := mr.toolRateLimiter.allow(key, limit.Limit, limit.Window, time.Now())
+	if allowed {
+		return nil
+	}
+
+	mr.metrics.Inc("mcp_tool_rate_limited_total", "tool", toolName)
+	mr.logger.Warn("tool_rate_limited",
+		"request_id", reqCtx.RequestID,
+		"tool", toolName,
+		"client_id", reqCtx.ClientID,
+		"user_id", user,
+		"limit", limit.Limit,
+		"window", limit.Window.String(),
+		"per_client", limit.PerClient,
+		"retry_after", retryAfter.String())
+
+	return errors.RateLimitError("mcp_router", "tools/call", retryAfter, map[string]interface{}{
+		"tool":   toolName,
+		"limit":  limit.Limit,
+		"window": limit.Window.String(),
+	})
+}
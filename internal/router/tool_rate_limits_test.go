@@ -0,0 +1,154 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/rbac"
+)
+
+// TestToolRateLimits tests that a tool over its limit is throttled with a
+// rate limit error while other tools and other clients keep being served
+func TestToolRateLimits(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "tools", "tool_provider", backend.URL, nil)
+
+	config := DefaultRouterConfig()
+	config.ToolRateLimits = []ToolRateLimit{
+		{Tool: "generate_report", Limit: 2, Window: time.Hour},
+		{Tool: "search_*", Limit: 1, Window: time.Hour, PerClient: true},
+	}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	call := func(t *testing.T, tool, clientID string) map[string]interface{} {
+		t.Helper()
+		req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": tool})
+		if clientID != "" {
+			req.Header.Set("X-Client-ID", clientID)
+		}
+		rec := httptest.NewRecorder()
+		mr.handleMCPRequest(rec, req)
+
+		var resp struct {
+			Error map[string]interface{} `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp.Error
+	}
+
+	t.Run("tool over its limit is throttled", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if rpcErr := call(t, "generate_report", "client-a"); rpcErr != nil {
+				t.Fatalf("call %d: expected success, got %v", i+1, rpcErr)
+			}
+		}
+
+		// The limit is shared by every client
+		rpcErr := call(t, "generate_report", "client-b")
+		if rpcErr == nil || rpcErr["code"] != float64(-32429) {
+			t.Fatalf("expected rate limit error, got %v", rpcErr)
+		}
+		data, _ := rpcErr["data"].(map[string]interface{})
+		if data["reason"] != "rate_limited" || data["retry_after"] == nil {
+			t.Errorf("expected rate_limited with retry_after, got %v", data)
+		}
+	})
+
+	t.Run("other tools are not throttled", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			if rpcErr := call(t, "lookup", "client-a"); rpcErr != nil {
+				t.Fatalf("call %d: expected success, got %v", i+1, rpcErr)
+			}
+		}
+	})
+
+	t.Run("per-client limits count each user separately", func(t *testing.T) {
+		check := func(user, tool string) error {
+			reqCtx := &RequestContext{
+				RequestID:    "req-1",
+				Capabilities: &rbac.ProcessedCapabilities{UserID: user},
+			}
+			return mr.checkToolRateLimit(reqCtx, map[string]interface{}{"name": tool})
+		}
+		if err := check("alice", "search_docs"); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if err := check("alice", "search_docs"); err == nil {
+			t.Error("expected alice to be throttled")
+		}
+		if err := check("bob", "search_docs"); err != nil {
+			t.Errorf("expected bob to be served, got %v", err)
+		}
+		if err := check("alice", "search_code"); err != nil {
+			t.Errorf("expected another tool under the same pattern to be served, got %v", err)
+		}
+	})
+
+	t.Run("client-supplied IDs do not get a fresh count", func(t *testing.T) {
+		if rpcErr := call(t, "search_web", "client-a"); rpcErr != nil {
+			t.Fatalf("expected success, got %v", rpcErr)
+		}
+		if rpcErr := call(t, "search_web", "client-b"); rpcErr == nil || rpcErr["code"] != float64(-32429) {
+			t.Errorf("expected the same anonymous caller to be throttled under another X-Client-ID, got %v", rpcErr)
+		}
+	})
+
+	t.Run("tracked windows are bounded and keep their own length", func(t *testing.T) {
+		limiter := newToolRateLimiter()
+		limiter.maxEntries = 3
+		now := time.Now()
+
+		limiter.allow("short", 1, time.Second, now)
+		if allowed, _ := limiter.allow("long", 1, time.Hour, now); !allowed {
+			t.Fatal("expected the first call to be allowed")
+		}
+		// A call under another limit does not expire the long window early
+		if allowed, _ := limiter.allow("short", 1, time.Second, now.Add(2*time.Second)); !allowed {
+			t.Error("expected the short window to have reset")
+		}
+		if allowed, _ := limiter.allow("long", 1, time.Hour, now.Add(2*time.Second)); allowed {
+			t.Error("expected the long window to still be counting")
+		}
+
+		for i := 0; i < 5; i++ {
+			limiter.allow(fmt.Sprintf("user-%d", i), 1, time.Hour, now)
+		}
+		if len(limiter.windows) != 3 || limiter.recent.Len() != 3 {
+			t.Errorf("expected 3 tracked windows, got %d", len(limiter.windows))
+		}
+		if _, exists := limiter.windows["user-4"]; !exists {
+			t.Error("expected the most recent window to be kept")
+		}
+		if _, exists := limiter.windows["long"]; exists {
+			t.Error("expected the least recently used window to be dropped")
+		}
+	})
+
+	t.Run("invalid limits are rejected", func(t *testing.T) {
+		for name, limits := range map[string][]ToolRateLimit{
+			"missing tool":    {{Limit: 1}},
+			"zero limit":      {{Tool: "generate_report"}},
+			"negative window": {{Tool: "generate_report", Limit: 1, Window: -time.Second}},
+		} {
+			if err := ValidateToolRateLimits(limits); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// TraceParentHeader carries the W3C trace context (version-traceid-spanid-flags).
+// The gateway forwards it to backends with its own span as the parent, so the
+// sampling decision propagates through the sampled flag.
+const TraceParentHeader = "Traceparent"
+
+// traceFlagSampled is the sampled bit of the traceparent trace flags
+const traceFlagSampled = 0x01
+
+// TraceParent is a parsed traceparent header
+type TraceParent struct {
+	TraceID string // 32 lowercase hex characters
+	SpanID  string // 16 lowercase hex characters
+	Sampled bool
+}
+
+// ParseTraceParent parses a version 00 traceparent header. Later versions are
+// accepted as long as they start with the version 00 fields, as the spec requires.
+func ParseTraceParent(header string) (TraceParent, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return TraceParent{}, false
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return TraceParent{}, false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isLowerHex(version) || len(traceID) != 32 || !isLowerHex(traceID) ||
+		len(spanID) != 16 || !isLowerHex(spanID) || len(flags) != 2 || !isLowerHex(flags) {
+		return TraceParent{}, false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return TraceParent{}, false
+	}
+
+	flagBits, _ := hex.DecodeString(flags)
+	return TraceParent{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flagBits[0]&traceFlagSampled != 0,
+	}, true
+}
+
+// String formats the trace context as a version 00 traceparent header
+func (tp TraceParent) String() string {
+	flags := 0
+	if tp.Sampled {
+		flags = traceFlagSampled
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", tp.TraceID, tp.SpanID, flags)
+}
+
+// NewTraceID returns a random trace ID
+func NewTraceID() string {
+	return randomHexID(16)
+}
+
+// NewSpanID returns a random span ID
+func NewSpanID() string {
+	return randomHexID(8)
+}
+
+func randomHexID(size int) string {
+	b := make([]byte, size)
+	for {
+		rand.Read(b)
+		// All-zero IDs are invalid
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// setTraceParentHeader forwards the gateway's trace context to a backend
+func setTraceParentHeader(httpReq *http.Request, reqCtx *RequestContext) {
+	if reqCtx != nil && reqCtx.TraceParent != nil {
+		httpReq.Header.Set(TraceParentHeader, reqCtx.TraceParent.String())
+	}
+}
+
+// logTracedBackendCall logs the backend leg of a sampled request
+func (mr *MCPRouter) logTracedBackendCall(reqCtx *RequestContext, service *registry.RegisteredService, method string, resp *http.Response, err error, duration time.Duration) {
+	if reqCtx == nil || reqCtx.TraceParent == nil || !reqCtx.TraceParent.Sampled {
+		return
+	}
+
+	fields := []interface{}{
+		"request_id", reqCtx.RequestID,
+		"trace_id", reqCtx.TraceParent.TraceID,
+		"parent_span_id", reqCtx.TraceParent.SpanID,
+		"service_id", service.ID,
+		"endpoint", service.Endpoint,
+		"method", method,
+		"duration_ms", duration.Milliseconds(),
+	}
+	if err != nil {
+		fields = append(fields, "error", err)
+	} else {
+		fields = append(fields, "status", resp.StatusCode, "content_length", resp.ContentLength)
+	}
+	mr.logger.Info("trace_backend_call", fields...)
+}
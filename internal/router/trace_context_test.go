@@ -0,0 +1,73 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestParseTraceParent tests traceparent header parsing
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		header  string
+		valid   bool
+		sampled bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-03-extra", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		tp, ok := ParseTraceParent(tt.header)
+		if ok != tt.valid || tp.Sampled != tt.sampled {
+			t.Errorf("ParseTraceParent(%q) = %+v, %v; expected valid %v, sampled %v", tt.header, tp, ok, tt.valid, tt.sampled)
+		}
+		if ok && tt.header[:2] == "00" && tp.String() != tt.header {
+			t.Errorf("expected %q to format back unchanged, got %q", tt.header, tp.String())
+		}
+	}
+}
+
+// TestTraceParentForwarding tests that the inbound trace context and sampling decision reach backends
+func TestTraceParentForwarding(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	received := make(chan http.Header, 1)
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"ok"}]}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "trace-backend", "tool_provider", backend.URL, nil)
+
+	config := DefaultRouterConfig()
+	config.BackendHeaders = BackendHeadersConfig{Inject: map[string]string{TraceParentHeader: "00-bogus"}}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{"name": "echo"})
+	req.Header.Set(TraceParentHeader, traceParent)
+	mr.handleMCPRequest(httptest.NewRecorder(), req)
+
+	select {
+	case headers := <-received:
+		if got := headers.Get(TraceParentHeader); got != traceParent {
+			t.Errorf("expected backend traceparent %q, got %q", traceParent, got)
+		}
+	default:
+		t.Fatal("expected request to reach the backend")
+	}
+}
@@ -0,0 +1,277 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// Transformation directions
+const (
+	TransformRequest  = "request"  // Applied to params before forwarding
+	TransformResponse = "response" // Applied to the result before returning
+)
+
+// Transformation operations
+const (
+	TransformOpRename  = "rename"  // Move the value at path to to
+	TransformOpSet     = "set"     // Write value at path, replacing any existing value
+	TransformOpDefault = "default" // Write value at path only when it is missing
+	TransformOpRemove  = "remove"  // Delete the value at path
+)
+
+// TransformRule reshapes the JSON exchanged with matching backends. Service
+// and Method are exact names or patterns where * matches any run of
+// characters; an empty pattern matches everything. Operations run in order
+// against the request params or the response result.
+type TransformRule struct {
+	Service    string               `yaml:"service" json:"service"` // Registered service name
+	Method     string               `yaml:"method" json:"method"`   // MCP method, e.g. tools/call
+	Direction  string               `yaml:"direction" json:"direction"`
+	Operations []TransformOperation `yaml:"operations" json:"operations"`
+}
+
+// TransformOperation is one field change. Paths are dot-separated object
+// keys relative to the params or result, such as "arguments.query".
+type TransformOperation struct {
+	Op    string      `yaml:"op" json:"op"`
+	Path  string      `yaml:"path" json:"path"`
+	To    string      `yaml:"to,omitempty" json:"to,omitempty"`       // rename only
+	Value interface{} `yaml:"value,omitempty" json:"value,omitempty"` // set and default only
+}
+
+// ValidateTransformRules checks that every rule has a direction and that
+// every operation is complete, so mistakes fail at load time
+func ValidateTransformRules(rules []TransformRule) error {
+	for i, rule := range rules {
+		if rule.Direction != TransformRequest && rule.Direction != TransformResponse {
+			return fmt.Errorf("rule %d: direction must be %s or %s, got %q", i, TransformRequest, TransformResponse, rule.Direction)
+		}
+		if len(rule.Operations) == 0 {
+			return fmt.Errorf("rule %d: at least one operation is required", i)
+		}
+		for j, op := range rule.Operations {
+			if err := op.validate(); err != nil {
+				return fmt.Errorf("rule %d operation %d: %w", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (op TransformOperation) validate() error {
+	if err := validateTransformPath(op.Path); err != nil {
+		return fmt.Errorf("path: %w", err)
+	}
+
+	switch op.Op {
+	case TransformOpRename:
+		if err := validateTransformPath(op.To); err != nil {
+			return fmt.Errorf("to: %w", err)
+		}
+		if op.To == op.Path {
+			return fmt.Errorf("rename of %s to itself", op.Path)
+		}
+	case TransformOpSet, TransformOpDefault:
+		if op.Value == nil {
+			return fmt.Errorf("%s of %s requires a value", op.Op, op.Path)
+		}
+		if _, err := json.Marshal(op.Value); err != nil {
+			return fmt.Errorf("%s of %s: value is not JSON: %w", op.Op, op.Path, err)
+		}
+	case TransformOpRemove:
+	default:
+		return fmt.Errorf("unknown op %q, must be one of [%s %s %s %s]", op.Op,
+			TransformOpRename, TransformOpSet, TransformOpDefault, TransformOpRemove)
+	}
+	return nil
+}
+
+func validateTransformPath(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("is required")
+	}
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			return fmt.Errorf("empty key in %q", path)
+		}
+	}
+	return nil
+}
+
+// transformRequest returns the request to send to a service, with the
+// service's request rules applied to a copy of the params
+func (mr *MCPRouter) transformRequest(reqCtx *RequestContext, service *registry.RegisteredService, mcpReq *mcpTypes.JSONRPCRequest) (*mcpTypes.JSONRPCRequest, error) {
+	rules := mr.matchingTransforms(service, mcpReq.Method, TransformRequest)
+	if len(rules) == 0 {
+		return mcpReq, nil
+	}
+
+	params, err := mr.applyTransforms(reqCtx, service, mcpReq.Method, TransformRequest, rules, mcpReq.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	transformed := *mcpReq
+	transformed.Params = params
+	return &transformed, nil
+}
+
+// transformResponse applies the service's response rules to a result
+func (mr *MCPRouter) transformResponse(reqCtx *RequestContext, service *registry.RegisteredService, method string, result interface{}) (interface{}, error) {
+	rules := mr.matchingTransforms(service, method, TransformResponse)
+	if len(rules) == 0 {
+		return result, nil
+	}
+	return mr.applyTransforms(reqCtx, service, method, TransformResponse, rules, result)
+}
+
+func (mr *MCPRouter) matchingTransforms(service *registry.RegisteredService, method, direction string) []TransformRule {
+	var rules []TransformRule
+	for _, rule := range mr.config.Transformations {
+		if rule.Direction != direction {
+			continue
+		}
+		if rule.Service != "" && !wildcardMatch(rule.Service, service.Name) {
+			continue
+		}
+		if rule.Method != "" && !wildcardMatch(rule.Method, method) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// applyTransforms runs the rules against the JSON form of a value. Only
+// objects can be transformed; any other value is returned unchanged.
+func (mr *MCPRouter) applyTransforms(reqCtx *RequestContext, service *registry.RegisteredService, method, direction string, rules []TransformRule, value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s for transformation: %w", direction, err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode %s for transformation: %w", direction, err)
+	}
+
+	object, ok := doc.(map[string]interface{})
+	if !ok {
+		if value == nil {
+			object = map[string]interface{}{}
+		} else {
+			return value, nil
+		}
+	}
+
+	applied := 0
+	for _, rule := range rules {
+		for _, op := range rule.Operations {
+			if op.apply(object) {
+				applied++
+			}
+		}
+	}
+
+	requestID := ""
+	if reqCtx != nil {
+		requestID = reqCtx.RequestID
+	}
+	mr.metrics.Inc("mcp_transformations_applied_total",
+		"service_id", service.ID,
+		"method", method,
+		"direction", direction)
+	mr.logger.Debug("transformation_applied",
+		"request_id", requestID,
+		"service_id", service.ID,
+		"method", method,
+		"direction", direction,
+		"rules", len(rules),
+		"operations_applied", applied)
+
+	return object, nil
+}
+
+// apply changes the document and reports whether it did. Missing paths make
+// rename and remove no-ops; set and default create missing parent objects
+// but never replace a non-object parent.
+func (op TransformOperation) apply(doc map[string]interface{}) bool {
+	switch op.Op {
+	case TransformOpRename:
+		value, found := removeTransformPath(doc, op.Path)
+		if !found {
+			return false
+		}
+		return setTransformPath(doc, op.To, value, true)
+	case TransformOpSet:
+		return setTransformPath(doc, op.Path, op.valueCopy(), true)
+	case TransformOpDefault:
+		return setTransformPath(doc, op.Path, op.valueCopy(), false)
+	case TransformOpRemove:
+		_, found := removeTransformPath(doc, op.Path)
+		return found
+	}
+	return false
+}
+
+// valueCopy returns the configured value in its JSON form, so later
+// operations on the document never modify the rule itself
+func (op TransformOperation) valueCopy() interface{} {
+	data, err := json.Marshal(op.Value)
+	if err != nil {
+		return op.Value
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return op.Value
+	}
+	return value
+}
+
+func setTransformPath(doc map[string]interface{}, path string, value interface{}, overwrite bool) bool {
+	keys := strings.Split(path, ".")
+	parent := doc
+	for _, key := range keys[:len(keys)-1] {
+		next, exists := parent[key]
+		if !exists {
+			child := map[string]interface{}{}
+			parent[key] = child
+			parent = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		parent = child
+	}
+
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists && !overwrite {
+		return false
+	}
+	parent[last] = value
+	return true
+}
+
+func removeTransformPath(doc map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	parent := doc
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := parent[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		parent = child
+	}
+
+	last := keys[len(keys)-1]
+	value, exists := parent[last]
+	if exists {
+		delete(parent, last)
+	}
+	return value, exists
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestTransformations tests that configured rules reshape requests to and responses from matching services
+func TestTransformations(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	received := make(chan map[string]interface{}, 1)
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		received <- body
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"ok"}],"meta":{"source":"legacy"}}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "legacy-search", "tool_provider", backend.URL, nil)
+
+	config := DefaultRouterConfig()
+	config.Transformations = []TransformRule{
+		{
+			Service:   "legacy-*",
+			Method:    "tools/call",
+			Direction: TransformRequest,
+			Operations: []TransformOperation{
+				{Op: TransformOpRename, Path: "arguments.q", To: "arguments.query"},
+				{Op: TransformOpDefault, Path: "arguments.limit", Value: 10},
+			},
+		},
+		{
+			Service:   "legacy-search",
+			Direction: TransformResponse,
+			Operations: []TransformOperation{
+				{Op: TransformOpSet, Path: "meta.gateway", Value: "mcpeg"},
+				{Op: TransformOpDefault, Path: "isError", Value: false},
+			},
+		},
+		{
+			Service:    "other-*",
+			Direction:  TransformResponse,
+			Operations: []TransformOperation{{Op: TransformOpRemove, Path: "content"}},
+		},
+	}
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+	req := newJSONRPCRequest(t, "tools/call", map[string]interface{}{
+		"name":      "search",
+		"arguments": map[string]interface{}{"q": "gateway", "limit": 3},
+	})
+	rec := httptest.NewRecorder()
+	mr.handleMCPRequest(rec, req)
+
+	var forwarded map[string]interface{}
+	select {
+	case forwarded = <-received:
+	default:
+		t.Fatalf("expected request to reach the backend, got %s", rec.Body.String())
+	}
+
+	t.Run("request fields are renamed before forwarding", func(t *testing.T) {
+		params, _ := forwarded["params"].(map[string]interface{})
+		arguments, _ := params["arguments"].(map[string]interface{})
+		if arguments["query"] != "gateway" {
+			t.Errorf("expected q renamed to query, got %v", arguments)
+		}
+		if _, exists := arguments["q"]; exists {
+			t.Errorf("expected q to be removed, got %v", arguments)
+		}
+		if arguments["limit"] != float64(3) {
+			t.Errorf("expected the default not to replace the client's limit, got %v", arguments["limit"])
+		}
+		if params["name"] != "search" {
+			t.Errorf("expected untouched fields to be kept, got %v", params)
+		}
+	})
+
+	t.Run("response fields are injected before returning", func(t *testing.T) {
+		var resp struct {
+			Result map[string]interface{} `json:"result"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		meta, _ := resp.Result["meta"].(map[string]interface{})
+		if meta["gateway"] != "mcpeg" || meta["source"] != "legacy" {
+			t.Errorf("expected gateway field added beside the backend's, got %v", meta)
+		}
+		if resp.Result["isError"] != false {
+			t.Errorf("expected isError defaulted to false, got %v", resp.Result["isError"])
+		}
+		if _, exists := resp.Result["content"]; !exists {
+			t.Error("expected rules for other services not to apply")
+		}
+	})
+
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		for name, rules := range map[string][]TransformRule{
+			"unknown direction": {{Direction: "both", Operations: []TransformOperation{{Op: TransformOpRemove, Path: "a"}}}},
+			"no operations":     {{Direction: TransformRequest}},
+			"unknown op":        {{Direction: TransformRequest, Operations: []TransformOperation{{Op: "copy", Path: "a"}}}},
+			"rename without to": {{Direction: TransformRequest, Operations: []TransformOperation{{Op: TransformOpRename, Path: "a"}}}},
+			"set without value": {{Direction: TransformResponse, Operations: []TransformOperation{{Op: TransformOpSet, Path: "a"}}}},
+			"empty path key":    {{Direction: TransformResponse, Operations: []TransformOperation{{Op: TransformOpRemove, Path: "a..b"}}}},
+		} {
+			if err := ValidateTransformRules(rules); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/osakka/mcpeg/pkg/errors"
+)
+
+// GenericAdapterServiceType serves methods without a prefix when
+// RouterConfig.GenericAdapterFallback is enabled
+const GenericAdapterServiceType = "generic_adapter"
+
+// mcpMethodServiceTypes maps the MCP methods served by backends to the
+// service type providing them
+var mcpMethodServiceTypes = map[string]string{
+	"tools/list":             "tool_provider",
+	"tools/call":             "tool_provider",
+	"resources/list":         "resource_provider",
+	"resources/read":         "resource_provider",
+	"resources/subscribe":    "resource_provider",
+	"resources/unsubscribe":  "resource_provider",
+	"prompts/list":           "prompt_provider",
+	"prompts/get":            "prompt_provider",
+	"completion/complete":    "completion_provider",
+	"logging/setLevel":       "logging_provider",
+	"sampling/createMessage": "sampling_provider",
+	"roots/list":             "root_provider",
+}
+
+// gatewayMethods are answered by the gateway itself
+var gatewayMethods = []string{
+	"initialize",
+	"notifications/initialized",
+	"notifications/roots/list_changed",
+}
+
+// isMCPMethod reports whether a method is part of MCP, so a missing backend
+// means the method is unavailable rather than unknown
+func isMCPMethod(method string) bool {
+	_, exists := mcpMethodServiceTypes[method]
+	return exists
+}
+
+// supportedMethods lists the MCP methods, followed by a prefix pattern such
+// as weather/* for each other service type registered as <prefix>_provider
+func (mr *MCPRouter) supportedMethods() []string {
+	methods := append([]string(nil), gatewayMethods...)
+	for method := range mcpMethodServiceTypes {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	standard := make(map[string]bool, len(mcpMethodServiceTypes))
+	for _, serviceType := range mcpMethodServiceTypes {
+		standard[serviceType] = true
+	}
+
+	prefixes := make(map[string]bool)
+	for _, service := range mr.registry.GetAllServices() {
+		prefix, ok := strings.CutSuffix(service.Type, "_provider")
+		if ok && prefix != "" && !standard[service.Type] {
+			prefixes[prefix+"/*"] = true
+		}
+	}
+	custom := make([]string, 0, len(prefixes))
+	for pattern := range prefixes {
+		custom = append(custom, pattern)
+	}
+	sort.Strings(custom)
+
+	return append(methods, custom...)
+}
+
+// methodNotFound is returned for a method that is not part of MCP and that no
+// registered service provides
+func (mr *MCPRouter) methodNotFound(reqCtx *RequestContext, method string) error {
+	mr.metrics.Inc("mcp_method_not_found_total")
+	mr.logger.Warn("mcp_method_not_found",
+		"request_id", reqCtx.RequestID,
+		"method", method,
+		"generic_adapter_fallback", mr.config.GenericAdapterFallback)
+
+	return errors.MethodNotFoundError("mcp_router", method, mr.supportedMethods())
+}
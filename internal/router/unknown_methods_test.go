@@ -0,0 +1,101 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestUnknownMethods tests that methods nothing serves return method not
+// found with the supported methods, and that the generic adapter is opt-in
+func TestUnknownMethods(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+
+	reached := make(chan string, 4)
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		reached <- req.Method
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	})
+
+	serviceRegistry := newTestRegistry(logger, mockMetrics)
+	defer serviceRegistry.Shutdown()
+	registerTestService(t, serviceRegistry, "weather", "weather_provider", backend.URL, nil)
+	registerTestService(t, serviceRegistry, "adapter", GenericAdapterServiceType, backend.URL, nil)
+
+	call := func(t *testing.T, mr *MCPRouter, method string) map[string]interface{} {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mr.handleMCPRequest(rec, newJSONRPCRequest(t, method, nil))
+
+		var resp struct {
+			Error map[string]interface{} `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp.Error
+	}
+
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, DefaultRouterConfig())
+
+	for _, method := range []string{"ping", "calendar/events"} {
+		t.Run(method+" is not found", func(t *testing.T) {
+			rpcErr := call(t, mr, method)
+			if rpcErr == nil || rpcErr["code"] != float64(-32601) {
+				t.Fatalf("expected method not found, got %v", rpcErr)
+			}
+
+			data, _ := rpcErr["data"].(map[string]interface{})
+			if data["reason"] != "method_not_found" {
+				t.Errorf("expected reason method_not_found, got %v", data["reason"])
+			}
+			supported := map[string]bool{}
+			for _, m := range data["supported_methods"].([]interface{}) {
+				supported[m.(string)] = true
+			}
+			for _, want := range []string{"initialize", "tools/call", "resources/read", "weather/*"} {
+				if !supported[want] {
+					t.Errorf("expected %s among supported methods, got %v", want, data["supported_methods"])
+				}
+			}
+		})
+	}
+
+	t.Run("methods of registered service types are routed", func(t *testing.T) {
+		if rpcErr := call(t, mr, "weather/forecast"); rpcErr != nil {
+			t.Fatalf("expected success, got %v", rpcErr)
+		}
+		if got := <-reached; got != "weather/forecast" {
+			t.Errorf("expected weather/forecast to reach the backend, got %s", got)
+		}
+	})
+
+	t.Run("MCP methods without a backend are unavailable rather than unknown", func(t *testing.T) {
+		rpcErr := call(t, mr, "prompts/get")
+		if rpcErr == nil || rpcErr["code"] != float64(-32503) {
+			t.Errorf("expected service unavailable, got %v", rpcErr)
+		}
+	})
+
+	t.Run("generic adapter fallback is opt-in", func(t *testing.T) {
+		config := DefaultRouterConfig()
+		config.GenericAdapterFallback = true
+		fallback := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, mockMetrics, nil, config)
+
+		if rpcErr := call(t, fallback, "ping"); rpcErr != nil {
+			t.Fatalf("expected the generic adapter to answer, got %v", rpcErr)
+		}
+		if got := <-reached; got != "ping" {
+			t.Errorf("expected ping to reach the generic adapter, got %s", got)
+		}
+	})
+}
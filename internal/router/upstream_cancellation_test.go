@@ -0,0 +1,100 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// cancellationRecordingMetrics records counter increments by metric name
+type cancellationRecordingMetrics struct {
+	mockMetrics
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+func (m *cancellationRecordingMetrics) Inc(name string, labels ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[name]++
+}
+
+func (m *cancellationRecordingMetrics) count(name string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.counts[name]
+}
+
+// TestForwardToServiceCancellation tests that cancelling the caller's context aborts the upstream call
+func TestForwardToServiceCancellation(t *testing.T) {
+	logger := logging.New("test")
+	recordingMetrics := &cancellationRecordingMetrics{}
+
+	received := make(chan struct{})
+	aborted := make(chan struct{})
+	backend := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		// Drain the body so the server notices when the client goes away
+		io.ReadAll(r.Body)
+		close(received)
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	serviceRegistry := newTestRegistry(logger, recordingMetrics)
+	defer serviceRegistry.Shutdown()
+	serviceID := registerTestService(t, serviceRegistry, "slow-backend", "tool_provider", backend.URL, nil)
+
+	mr := NewMCPRouterWithConfig(serviceRegistry, nil, nil, logger, recordingMetrics, nil, DefaultRouterConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := mr.forwardToService(ctx, &RequestContext{RequestID: "req-1"}, serviceRegistry.GetService(serviceID), &mcpTypes.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "tools/call",
+		})
+		errCh <- err
+	}()
+
+	select {
+	case <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("backend never received the request")
+	}
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected forwardToService to return promptly after cancellation")
+	}
+
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Error("expected the upstream request to be aborted")
+	}
+
+	if got := recordingMetrics.count("mcp_upstream_cancellations_total"); got != 1 {
+		t.Errorf("expected 1 upstream cancellation recorded, got %d", got)
+	}
+}
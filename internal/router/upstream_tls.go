@@ -0,0 +1,84 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// upstreamTransport is the transport built for one service's TLS settings
+type upstreamTransport struct {
+	fingerprint string
+	transport   *http.Transport
+}
+
+// upstreamTransports keeps a transport per service with its own TLS policy,
+// so connections to it are pooled like those on the default transport
+type upstreamTransports struct {
+	mutex      sync.Mutex
+	transports map[string]*upstreamTransport
+}
+
+func newUpstreamTransports() *upstreamTransports {
+	return &upstreamTransports{transports: make(map[string]*upstreamTransport)}
+}
+
+// evict drops the transport of a service that left the registry and closes
+// its pooled connections
+func (ut *upstreamTransports) evict(serviceID string) {
+	ut.mutex.Lock()
+	cached, exists := ut.transports[serviceID]
+	delete(ut.transports, serviceID)
+	ut.mutex.Unlock()
+
+	if exists && cached.transport != nil {
+		cached.transport.CloseIdleConnections()
+	}
+}
+
+// transportFor returns the transport for calls to a service, or nil for the
+// default transport when the service sets no TLS options
+func (mr *MCPRouter) transportFor(service *registry.RegisteredService) (http.RoundTripper, error) {
+	fingerprint := registry.UpstreamTLSFingerprint(service.Metadata)
+
+	ut := mr.transports
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if cached, exists := ut.transports[service.ID]; exists && cached.fingerprint == fingerprint {
+		return transportOrDefault(cached.transport), nil
+	}
+
+	tlsConfig, err := registry.UpstreamTLSConfig(service.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings for service %s: %w", service.ID, err)
+	}
+
+	var transport *http.Transport
+	if tlsConfig != nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+
+		if tlsConfig.InsecureSkipVerify {
+			mr.logger.Warn("upstream_tls_verification_disabled",
+				"service_id", service.ID,
+				"endpoint", service.Endpoint)
+		}
+	}
+
+	// Settings changed since the last call; drop the old pooled connections
+	if cached, exists := ut.transports[service.ID]; exists && cached.transport != nil {
+		cached.transport.CloseIdleConnections()
+	}
+	ut.transports[service.ID] = &upstreamTransport{fingerprint: fingerprint, transport: transport}
+	return transportOrDefault(transport), nil
+}
+
+func transportOrDefault(transport *http.Transport) http.RoundTripper {
+	if transport == nil {
+		return http.DefaultTransport
+	}
+	return transport
+}
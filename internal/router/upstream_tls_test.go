@@ -0,0 +1,122 @@
+package router
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/logging"
+	mcpTypes "github.com/osakka/mcpeg/pkg/mcp"
+)
+
+// TestUpstreamTLSPolicy tests that backends are verified against a service's CA bundle and that
+// verification is skipped only when explicitly enabled
+func TestUpstreamTLSPolicy(t *testing.T) {
+	logger := logging.New("test")
+	recordingMetrics := &cancellationRecordingMetrics{}
+
+	// httptest signs its certificate with a CA the system does not trust
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}`))
+	}))
+	backend.Config.ErrorLog = log.New(io.Discard, "", 0) // Rejected handshakes are expected
+	backend.StartTLS()
+	defer backend.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: backend.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+
+	mr := NewMCPRouterWithConfig(nil, nil, nil, logger, recordingMetrics, nil, DefaultRouterConfig())
+	forward := func(t *testing.T, metadata map[string]interface{}) error {
+		t.Helper()
+		service := &registry.RegisteredService{ID: "tls-backend", Endpoint: backend.URL, Metadata: metadata}
+		_, err := mr.forwardToService(context.Background(), &RequestContext{RequestID: "req-1"}, service, &mcpTypes.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "tools/list",
+		})
+		return err
+	}
+
+	t.Run("untrusted certificate is rejected by default", func(t *testing.T) {
+		if err := forward(t, nil); err == nil || !strings.Contains(err.Error(), "certificate") {
+			t.Errorf("expected a certificate verification error, got %v", err)
+		}
+	})
+
+	t.Run("custom CA verifies the backend", func(t *testing.T) {
+		if err := forward(t, map[string]interface{}{registry.TLSCAFileKey: caFile}); err != nil {
+			t.Errorf("expected the backend to be verified against the CA bundle, got %v", err)
+		}
+	})
+
+	t.Run("skip verify is honored when explicitly enabled", func(t *testing.T) {
+		if err := forward(t, map[string]interface{}{registry.TLSInsecureSkipVerifyKey: true}); err != nil {
+			t.Errorf("expected verification to be skipped, got %v", err)
+		}
+		if got := recordingMetrics.count("mcp_upstream_insecure_tls_requests_total"); got != 1 {
+			t.Errorf("expected the insecure request to be counted, got %d", got)
+		}
+	})
+
+	t.Run("skip verify is not honored otherwise", func(t *testing.T) {
+		for _, value := range []interface{}{false, "true", "yes", 1} {
+			if err := forward(t, map[string]interface{}{registry.TLSInsecureSkipVerifyKey: value}); err == nil {
+				t.Errorf("expected %v (%T) not to skip verification", value, value)
+			}
+		}
+	})
+
+	t.Run("invalid settings are rejected", func(t *testing.T) {
+		for _, metadata := range []map[string]interface{}{
+			{registry.TLSCAFileKey: filepath.Join(t.TempDir(), "missing.pem")},
+			{registry.TLSCertFileKey: caFile},
+			{registry.TLSCAFileKey: 42},
+		} {
+			if _, err := registry.UpstreamTLSConfig(metadata); err == nil {
+				t.Errorf("expected %v to be rejected", metadata)
+			}
+		}
+	})
+
+	t.Run("transport is dropped when its service is unregistered", func(t *testing.T) {
+		reg := newTestRegistry(logger, recordingMetrics)
+		defer reg.Shutdown()
+		mr := NewMCPRouterWithConfig(reg, nil, nil, logger, recordingMetrics, nil, DefaultRouterConfig())
+
+		plain := newTestBackend(t, func(w http.ResponseWriter, r *http.Request) {})
+		serviceID := registerTestService(t, reg, "tls-backend", "tls_test", plain.URL,
+			map[string]interface{}{registry.TLSInsecureSkipVerifyKey: true})
+		if _, err := mr.transportFor(reg.GetService(serviceID)); err != nil {
+			t.Fatalf("failed to build transport: %v", err)
+		}
+
+		cached := func() bool {
+			mr.transports.mutex.Lock()
+			defer mr.transports.mutex.Unlock()
+			_, exists := mr.transports.transports[serviceID]
+			return exists
+		}
+		if !cached() {
+			t.Fatal("expected the service's transport to be cached")
+		}
+		if err := reg.UnregisterService(context.Background(), serviceID); err != nil {
+			t.Fatalf("failed to unregister service: %v", err)
+		}
+		if cached() {
+			t.Error("expected the transport to be evicted with its service")
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/internal/router"
+	"github.com/osakka/mcpeg/pkg/capabilities"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestPluginCapabilityChangeNotifications tests that rediscovering a plugin
+// that gained a tool notifies clients that the tool list changed
+func TestPluginCapabilityChangeNotifications(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+	defer server.registry.Shutdown()
+
+	plugin := &changingToolsPlugin{tools: []registry.ToolDefinition{{Name: "search", Description: "Search things"}}}
+	if err := server.pluginIntegration.GetPluginManager().RegisterPlugin(plugin); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	var diffs []capabilities.CapabilityDiff
+	server.discoveryEngine.OnCapabilityChange(func(diff capabilities.CapabilityDiff) {
+		diffs = append(diffs, diff)
+	})
+
+	ctx := context.Background()
+	if _, err := server.discoveryEngine.DiscoverPlugin(ctx, "changing-tools"); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+
+	sub, _, _ := server.notifications.Subscribe(router.Subscriber{}, 0, false)
+	defer sub.Close()
+
+	// Rediscovering an unchanged plugin, as after a no-op reload, notifies nobody
+	if _, err := server.discoveryEngine.DiscoverPlugin(ctx, "changing-tools"); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+	select {
+	case notification := <-sub.C():
+		t.Fatalf("expected no notification for unchanged capabilities, got %s", notification.Method)
+	default:
+	}
+
+	plugin.setTools([]registry.ToolDefinition{
+		{Name: "search", Description: "Search things"},
+		{Name: "index", Description: "Index things"},
+	})
+	if _, err := server.discoveryEngine.DiscoverPlugin(ctx, "changing-tools"); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+
+	select {
+	case notification := <-sub.C():
+		if notification.Method != "notifications/tools/list_changed" {
+			t.Errorf("expected notifications/tools/list_changed, got %s", notification.Method)
+		}
+	default:
+		t.Fatal("expected a notification after the plugin gained a tool")
+	}
+	select {
+	case notification := <-sub.C():
+		t.Errorf("expected only the tool list to be reported, got %s", notification.Method)
+	default:
+	}
+
+	if len(diffs) != 1 {
+		t.Fatalf("expected one capability diff, got %d", len(diffs))
+	}
+	diff := diffs[0]
+	if len(diff.Added) != 1 || diff.Added[0] != (capabilities.CapabilityRef{Type: capabilities.CapabilityTool, Name: "index"}) ||
+		len(diff.Removed) != 0 || len(diff.Changed) != 0 {
+		t.Errorf("expected index to be the only added tool, got %+v", diff)
+	}
+}
+
+// TestCapabilityBaselinesAtStartup tests that the first rediscovery after
+// startup, such as the one following a reload, reports what changed
+func TestCapabilityBaselinesAtStartup(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+	defer server.registry.Shutdown()
+
+	plugin := &changingToolsPlugin{tools: []registry.ToolDefinition{{Name: "search", Description: "Search things"}}}
+	if err := server.pluginIntegration.GetPluginManager().RegisterPlugin(plugin); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+	ctx := context.Background()
+	if err := server.initializePlugins(ctx); err != nil {
+		t.Fatalf("failed to initialize plugins: %v", err)
+	}
+	defer server.pluginIntegration.ShutdownPlugins(ctx)
+
+	sub, _, _ := server.notifications.Subscribe(router.Subscriber{}, 0, false)
+	defer sub.Close()
+
+	plugin.setTools(nil)
+	if _, err := server.discoveryEngine.DiscoverPlugin(ctx, "changing-tools"); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+
+	select {
+	case notification := <-sub.C():
+		if notification.Method != "notifications/tools/list_changed" {
+			t.Errorf("expected notifications/tools/list_changed, got %s", notification.Method)
+		}
+	default:
+		t.Fatal("expected the first rediscovery to report the removed tool")
+	}
+}
+
+// changingToolsPlugin offers a tool list that tests can replace
+type changingToolsPlugin struct {
+	plugins.Plugin
+	mutex sync.Mutex
+	tools []registry.ToolDefinition
+}
+
+func (p *changingToolsPlugin) setTools(tools []registry.ToolDefinition) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.tools = tools
+}
+
+func (p *changingToolsPlugin) Name() string        { return "changing-tools" }
+func (p *changingToolsPlugin) Version() string     { return "1.0.0" }
+func (p *changingToolsPlugin) Description() string { return "Plugin whose tools change" }
+func (p *changingToolsPlugin) GetTools() []registry.ToolDefinition {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.tools
+}
+func (p *changingToolsPlugin) GetResources() []registry.ResourceDefinition { return nil }
+func (p *changingToolsPlugin) GetPrompts() []registry.PromptDefinition     { return nil }
+func (p *changingToolsPlugin) Initialize(ctx context.Context, config plugins.PluginConfig) error {
+	return nil
+}
+func (p *changingToolsPlugin) Shutdown(ctx context.Context) error    { return nil }
+func (p *changingToolsPlugin) HealthCheck(ctx context.Context) error { return nil }
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+const (
+	defaultCircuitWebhookTimeout = 5 * time.Second
+
+	// maxCircuitWebhookDeliveries bounds concurrent webhook calls; transitions
+	// beyond it are dropped rather than queued
+	maxCircuitWebhookDeliveries = 16
+)
+
+// CircuitBreakerAlertConfig sends load balancer circuit breaker transitions to
+// operators. Delivery is best-effort: a slow or failing webhook never delays
+// request routing, and events that cannot be delivered are dropped.
+type CircuitBreakerAlertConfig struct {
+	WebhookURL     string        `yaml:"webhook_url"`     // Events are POSTed as JSON
+	WebhookTimeout time.Duration `yaml:"webhook_timeout"` // 0 uses 5s
+	NotifyClients  bool          `yaml:"notify_clients"`  // Also stream events to SSE clients as notifications/message
+}
+
+// Validate checks the webhook URL
+func (c CircuitBreakerAlertConfig) Validate() error {
+	if c.WebhookURL != "" && !strings.HasPrefix(c.WebhookURL, "http://") && !strings.HasPrefix(c.WebhookURL, "https://") {
+		return fmt.Errorf("webhook URL must be an http or https URL, got %s", c.WebhookURL)
+	}
+	if c.WebhookTimeout < 0 {
+		return fmt.Errorf("webhook timeout must not be negative, got %s", c.WebhookTimeout)
+	}
+	return nil
+}
+
+// circuitAlerter delivers circuit breaker events to the configured webhook
+// and notification stream
+type circuitAlerter struct {
+	gs         *GatewayServer
+	config     CircuitBreakerAlertConfig
+	client     *http.Client
+	deliveries chan struct{}
+}
+
+// newCircuitAlerter returns an alerter for config, or nil when alerts are disabled
+func newCircuitAlerter(gs *GatewayServer, config CircuitBreakerAlertConfig) *circuitAlerter {
+	if config.WebhookURL == "" && !config.NotifyClients {
+		return nil
+	}
+
+	timeout := config.WebhookTimeout
+	if timeout == 0 {
+		timeout = defaultCircuitWebhookTimeout
+	}
+	return &circuitAlerter{
+		gs:         gs,
+		config:     config,
+		client:     &http.Client{Timeout: timeout},
+		deliveries: make(chan struct{}, maxCircuitWebhookDeliveries),
+	}
+}
+
+// handleEvent is the load balancer's circuit breaker observer. It runs under
+// the load balancer lock, so the webhook call happens on its own goroutine.
+func (a *circuitAlerter) handleEvent(event registry.CircuitBreakerEvent) {
+	if a.config.NotifyClients {
+		level := "info"
+		if event.NewState == registry.CircuitStateOpen {
+			level = "warning"
+		}
+		// Every client stream receives this, so the backend's error text,
+		// which can name internal hosts, only goes to the webhook and logs
+		clientEvent := event
+		clientEvent.LastError = ""
+		a.gs.PublishNotification("notifications/message", map[string]interface{}{
+			"level":  level,
+			"logger": "circuit_breaker",
+			"data":   clientEvent,
+		}, nil)
+	}
+
+	if a.config.WebhookURL == "" {
+		return
+	}
+
+	select {
+	case a.deliveries <- struct{}{}:
+	default:
+		a.gs.metrics.Inc("circuit_breaker_webhook_dropped_total", "service_type", event.ServiceType)
+		a.gs.logger.Warn("circuit_breaker_webhook_dropped",
+			"service_id", event.ServiceID,
+			"new_state", event.NewState)
+		return
+	}
+
+	go func() {
+		defer func() { <-a.deliveries }()
+		a.deliver(event)
+	}()
+}
+
+// deliver POSTs one event to the webhook
+func (a *circuitAlerter) deliver(event registry.CircuitBreakerEvent) {
+	err := a.post(event)
+	if err != nil {
+		a.gs.metrics.Inc("circuit_breaker_webhook_failures_total", "service_type", event.ServiceType)
+		a.gs.logger.Warn("circuit_breaker_webhook_failed",
+			"service_id", event.ServiceID,
+			"old_state", event.OldState,
+			"new_state", event.NewState,
+			"error", err)
+		return
+	}
+
+	a.gs.metrics.Inc("circuit_breaker_webhook_deliveries_total", "service_type", event.ServiceType)
+	a.gs.logger.Debug("circuit_breaker_webhook_delivered",
+		"service_id", event.ServiceID,
+		"new_state", event.NewState)
+}
+
+func (a *circuitAlerter) post(event registry.CircuitBreakerEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal circuit breaker event: %w", err)
+	}
+
+	resp, err := a.client.Post(a.config.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send circuit breaker event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
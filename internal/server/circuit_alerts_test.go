@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/internal/router"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestCircuitBreakerWebhook tests that a circuit breaker tripping is POSTed to the webhook and streamed to clients
+func TestCircuitBreakerWebhook(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	received := make(chan registry.CircuitBreakerEvent, 4)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event registry.CircuitBreakerEvent
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected a JSON POST, got %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode webhook payload: %v", err)
+		}
+		received <- event
+	}))
+	defer webhook.Close()
+
+	server := NewGatewayServer(ServerConfig{
+		CircuitBreakerAlerts: CircuitBreakerAlertConfig{WebhookURL: webhook.URL, NotifyClients: true},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	sub, _, _ := server.notifications.Subscribe(router.Subscriber{}, 0, false)
+	defer sub.Close()
+
+	service := &registry.RegisteredService{
+		ID:     "search-1",
+		Name:   "search",
+		Type:   "tool_provider",
+		Status: registry.StatusActive,
+		Health: registry.HealthHealthy,
+	}
+	lb := server.registry.GetLoadBalancer()
+	for i := 0; i < 11; i++ {
+		selected, err := lb.SelectService([]*registry.RegisteredService{service}, registry.SelectionCriteria{})
+		if err != nil {
+			t.Fatalf("expected the service to be selectable before the circuit opens: %v", err)
+		}
+		lb.RecordFailure(selected, errors.New("backend timeout"))
+	}
+
+	select {
+	case event := <-received:
+		if event.ServiceID != "search-1" || event.ServiceType != "tool_provider" ||
+			event.OldState != registry.CircuitStateClosed || event.NewState != registry.CircuitStateOpen {
+			t.Errorf("expected search-1 closed -> open, got %+v", event)
+		}
+		if event.FailedRequests != 11 || event.TotalRequests != 11 || event.LastError != "backend timeout" || event.Timestamp.IsZero() {
+			t.Errorf("expected the failure details in the payload, got %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the webhook call")
+	}
+
+	select {
+	case notification := <-sub.C():
+		params, _ := notification.Params.(map[string]interface{})
+		if notification.Method != "notifications/message" || params["level"] != "warning" || params["logger"] != "circuit_breaker" {
+			t.Errorf("expected a circuit_breaker warning notification, got %+v", notification)
+		}
+		if event, _ := params["data"].(registry.CircuitBreakerEvent); event.ServiceID != "search-1" || event.LastError != "" {
+			t.Errorf("expected the streamed event without the backend error, got %+v", params["data"])
+		}
+	default:
+		t.Error("expected the transition to be streamed to clients")
+	}
+}
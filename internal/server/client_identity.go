@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// clientIdentifier derives rate limiting keys from requests. Forwarding headers
+// are only honoured from trusted proxies, and client IPs can be bucketed by
+// subnet so rotating addresses within a prefix does not evade limits.
+type clientIdentifier struct {
+	trustedProxies []*net.IPNet // empty trusts forwarding headers from any peer
+	ipv4Prefix     int          // 0 keys IPv4 clients by full address
+	ipv6Prefix     int          // 0 keys IPv6 clients by full address
+}
+
+// ValidateClientIdentification checks subnet prefix lengths and trusted proxy CIDRs
+func ValidateClientIdentification(ipv4Prefix, ipv6Prefix int, trustedProxies []string) error {
+	if ipv4Prefix < 0 || ipv4Prefix > 32 {
+		return fmt.Errorf("IPv4 client prefix must be between 0 and 32, got %d", ipv4Prefix)
+	}
+	if ipv6Prefix < 0 || ipv6Prefix > 128 {
+		return fmt.Errorf("IPv6 client prefix must be between 0 and 128, got %d", ipv6Prefix)
+	}
+	for _, cidr := range trustedProxies {
+		if _, err := parseTrustedProxy(cidr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTrustedProxy accepts a CIDR block or a single address
+func parseTrustedProxy(cidr string) (*net.IPNet, error) {
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", cidr)
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy CIDR %q: %w", cidr, err)
+	}
+	return network, nil
+}
+
+// newClientIdentifier builds the identifier from server config, skipping
+// trusted proxies that fail to parse
+func (gs *GatewayServer) newClientIdentifier() *clientIdentifier {
+	ci := &clientIdentifier{
+		ipv4Prefix: gs.config.RateLimitIPv4Prefix,
+		ipv6Prefix: gs.config.RateLimitIPv6Prefix,
+	}
+	for _, cidr := range gs.config.TrustedProxies {
+		network, err := parseTrustedProxy(cidr)
+		if err != nil {
+			gs.logger.Warn("trusted_proxy_invalid", "proxy", cidr, "error", err)
+			continue
+		}
+		ci.trustedProxies = append(ci.trustedProxies, network)
+	}
+	return ci
+}
+
+// parseClientIP parses an address that may carry a port or IPv6 brackets,
+// e.g. 203.0.113.7:443, [2001:db8::1]:443 or [2001:db8::1]
+func parseClientIP(value string) net.IP {
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	if zone := strings.IndexByte(value, '%'); zone >= 0 {
+		value = value[:zone]
+	}
+	return net.ParseIP(value)
+}
+
+func (ci *clientIdentifier) isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range ci.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddress returns the originating client address for r. With trusted
+// proxies configured, X-Forwarded-For is walked from the nearest hop and the
+// first untrusted address wins; headers from untrusted peers are ignored.
+// Without trusted proxies the leftmost forwarded address is used as before.
+func (ci *clientIdentifier) clientAddress(r *http.Request) string {
+	remote := parseClientIP(r.RemoteAddr)
+	trustAll := len(ci.trustedProxies) == 0
+
+	if trustAll || ci.isTrustedProxy(remote) {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			hops := strings.Split(xff, ",")
+			if trustAll {
+				return strings.TrimSpace(hops[0])
+			}
+			for i := len(hops) - 1; i >= 0; i-- {
+				hop := strings.TrimSpace(hops[i])
+				if ip := parseClientIP(hop); ip == nil || !ci.isTrustedProxy(ip) {
+					return hop
+				}
+			}
+		}
+		if xri := r.Header.Get("X-Real-IP"); xri != "" {
+			return strings.TrimSpace(xri)
+		}
+	}
+
+	if remote == nil {
+		return r.RemoteAddr
+	}
+	return remote.String()
+}
+
+// identify returns the rate limiting key for r. IP clients are normalized and,
+// when a prefix is configured, bucketed by subnet (e.g. 2001:db8:1:2::/64).
+// Non-IP identifiers from trusted headers are returned unchanged.
+func (ci *clientIdentifier) identify(r *http.Request) string {
+	address := ci.clientAddress(r)
+	ip := parseClientIP(address)
+	if ip == nil {
+		return address
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		if ci.ipv4Prefix > 0 && ci.ipv4Prefix < 32 {
+			return fmt.Sprintf("%s/%d", ip4.Mask(net.CIDRMask(ci.ipv4Prefix, 32)), ci.ipv4Prefix)
+		}
+		return ip4.String()
+	}
+
+	if ci.ipv6Prefix > 0 && ci.ipv6Prefix < 128 {
+		return fmt.Sprintf("%s/%d", ip.Mask(net.CIDRMask(ci.ipv6Prefix, 128)), ci.ipv6Prefix)
+	}
+	return ip.String()
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestClientIdentification tests IPv6 parsing, subnet normalization and forwarding header trust
+func TestClientIdentification(t *testing.T) {
+	newRequest := func(remoteAddr, xff string) *http.Request {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		req.RemoteAddr = remoteAddr
+		if xff != "" {
+			req.Header.Set("X-Forwarded-For", xff)
+		}
+		return req
+	}
+
+	t.Run("IPv6 addresses are normalized to the configured prefix", func(t *testing.T) {
+		ci := &clientIdentifier{ipv4Prefix: 24, ipv6Prefix: 64}
+		tests := []struct {
+			remoteAddr, xff, expected string
+		}{
+			{"[2001:db8:1:2:aaaa::1]:4321", "", "2001:db8:1:2::/64"},
+			{"192.0.2.1:1234", "[2001:DB8:1:2::beef]:443", "2001:db8:1:2::/64"},
+			{"192.0.2.1:1234", "[2001:db8:1:3::1], 10.0.0.1", "2001:db8:1:3::/64"},
+			{"203.0.113.77:1234", "", "203.0.113.0/24"},
+			{"[::ffff:203.0.113.77]:1234", "", "203.0.113.0/24"},
+		}
+		for _, test := range tests {
+			if got := ci.identify(newRequest(test.remoteAddr, test.xff)); got != test.expected {
+				t.Errorf("identify(%s, %q) = %s, expected %s", test.remoteAddr, test.xff, got, test.expected)
+			}
+		}
+
+		full := &clientIdentifier{}
+		if got := full.identify(newRequest("[2001:db8::1]:4321", "")); got != "2001:db8::1" {
+			t.Errorf("expected unbracketed full address without a prefix, got %s", got)
+		}
+	})
+
+	t.Run("forwarding headers are only trusted from trusted proxies", func(t *testing.T) {
+		ci := &clientIdentifier{}
+		for _, cidr := range []string{"10.0.0.0/8", "fd00::/8"} {
+			network, err := parseTrustedProxy(cidr)
+			if err != nil {
+				t.Fatalf("failed to parse %s: %v", cidr, err)
+			}
+			ci.trustedProxies = append(ci.trustedProxies, network)
+		}
+
+		tests := []struct {
+			name, remoteAddr, xff, expected string
+		}{
+			{"spoofed header from untrusted peer", "198.51.100.9:1234", "1.2.3.4", "198.51.100.9"},
+			{"header from trusted proxy", "10.1.1.1:1234", "203.0.113.5", "203.0.113.5"},
+			{"spoofed leftmost entry is skipped", "10.1.1.1:1234", "1.2.3.4, 203.0.113.5, 10.2.2.2", "203.0.113.5"},
+			{"trusted IPv6 proxy", "[fd00::1]:1234", "[2001:db8::7]", "2001:db8::7"},
+		}
+		for _, test := range tests {
+			if got := ci.identify(newRequest(test.remoteAddr, test.xff)); got != test.expected {
+				t.Errorf("%s: expected %s, got %s", test.name, test.expected, got)
+			}
+		}
+	})
+
+	t.Run("invalid settings are rejected", func(t *testing.T) {
+		if err := ValidateClientIdentification(33, 64, nil); err == nil {
+			t.Error("expected IPv4 prefix over 32 to be rejected")
+		}
+		if err := ValidateClientIdentification(24, 129, nil); err == nil {
+			t.Error("expected IPv6 prefix over 128 to be rejected")
+		}
+		if err := ValidateClientIdentification(24, 64, []string{"not-a-cidr"}); err == nil {
+			t.Error("expected invalid trusted proxy to be rejected")
+		}
+		if err := ValidateClientIdentification(24, 64, []string{"10.0.0.0/8", "192.0.2.1", "::1"}); err != nil {
+			t.Errorf("expected valid settings to pass, got %v", err)
+		}
+	})
+}
+
+// TestRateLimitSubnetBucketing tests that clients in one subnet share a rate limit bucket
+func TestRateLimitSubnetBucketing(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{
+		EnableRateLimit:     true,
+		RateLimitRPS:        5,
+		RateLimitIPv4Prefix: 24,
+		RateLimitIPv6Prefix: 64,
+		RateLimitOverrides: map[string]int{
+			"198.18.0.0/16": RateLimitUnlimited,
+		},
+	}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	handler := server.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// send issues one request from each address and returns how many were allowed
+	send := func(addresses ...string) int {
+		allowed := 0
+		for _, address := range addresses {
+			req := httptest.NewRequest("POST", "/mcp", nil)
+			req.RemoteAddr = address
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code == http.StatusOK {
+				allowed++
+			}
+		}
+		return allowed
+	}
+
+	rotating := func(format string, n int) []string {
+		addresses := make([]string, n)
+		for i := range addresses {
+			addresses[i] = fmt.Sprintf(format, i+1)
+		}
+		return addresses
+	}
+
+	t.Run("rotating IPv6 addresses within a /64 share a bucket", func(t *testing.T) {
+		if allowed := send(rotating("[2001:db8:1:2::%x]:1234", 20)...); allowed != 5 {
+			t.Errorf("expected 5 requests allowed across the /64, got %d", allowed)
+		}
+		if allowed := send("[2001:db8:1:3::1]:1234"); allowed != 1 {
+			t.Error("expected a different /64 to have its own bucket")
+		}
+	})
+
+	t.Run("rotating IPv4 addresses within a /24 share a bucket", func(t *testing.T) {
+		if allowed := send(rotating("203.0.113.%d:1234", 20)...); allowed != 5 {
+			t.Errorf("expected 5 requests allowed across the /24, got %d", allowed)
+		}
+	})
+
+	t.Run("CIDR override covers subnet buckets", func(t *testing.T) {
+		if allowed := send(rotating("198.18.7.%d:1234", 50)...); allowed != 50 {
+			t.Errorf("expected allowlisted subnet never to be throttled, got %d of 50", allowed)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content-Encoding tokens for the supported compression algorithms
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+	EncodingBrotli  = "br"
+)
+
+// DefaultCompressionAlgorithms is the server preference order used when none is configured
+var DefaultCompressionAlgorithms = []string{EncodingBrotli, EncodingGzip, EncodingDeflate}
+
+// ValidateCompressionSettings checks a compression level and algorithm preference list.
+// Level 0 selects each algorithm's default; levels above 9 are brotli-only and are
+// capped at 9 for gzip and deflate.
+func ValidateCompressionSettings(level int, algorithms []string) error {
+	if level < 0 || level > brotli.BestCompression {
+		return fmt.Errorf("compression level must be between 0 and %d, got %d", brotli.BestCompression, level)
+	}
+	for _, algorithm := range algorithms {
+		switch algorithm {
+		case EncodingGzip, EncodingDeflate, EncodingBrotli:
+		default:
+			return fmt.Errorf("unsupported compression algorithm %q, must be one of [%s %s %s]",
+				algorithm, EncodingBrotli, EncodingGzip, EncodingDeflate)
+		}
+	}
+	return nil
+}
+
+// negotiateEncoding picks the compression algorithm for a request from its
+// Accept-Encoding header. The client's highest q-value wins; ties go to the
+// earliest algorithm in the server preference order. Returns "" when the
+// client accepts none of the configured algorithms.
+func negotiateEncoding(acceptEncoding string, preferences []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	accepted := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		if coding == "*" {
+			wildcard = q
+		} else {
+			accepted[coding] = q
+		}
+	}
+
+	best := ""
+	bestQ := 0.0
+	for _, algorithm := range preferences {
+		q, listed := accepted[algorithm]
+		if !listed {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = algorithm, q
+		}
+	}
+	return best
+}
+
+// newEncoder returns a compressing writer for an algorithm at the configured level
+func newEncoder(encoding string, level int, w io.Writer) (io.WriteCloser, error) {
+	flateLevel := level
+	if flateLevel == 0 {
+		flateLevel = flate.DefaultCompression
+	} else if flateLevel > flate.BestCompression {
+		flateLevel = flate.BestCompression
+	}
+
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriterLevel(w, flateLevel)
+	case EncodingDeflate:
+		return flate.NewWriter(w, flateLevel)
+	case EncodingBrotli:
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		return brotli.NewWriterLevel(w, level), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression algorithm %q", encoding)
+	}
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w     io.Writer
+	count int64
+}
+
+func (cw *countingWriter) Write(data []byte) (int, error) {
+	n, err := cw.w.Write(data)
+	cw.count += int64(n)
+	return n, err
+}
+
+// compressionAlgorithms returns the configured preference order or the default
+func (gs *GatewayServer) compressionAlgorithms() []string {
+	if len(gs.config.CompressionAlgorithms) > 0 {
+		return gs.config.CompressionAlgorithms
+	}
+	return DefaultCompressionAlgorithms
+}
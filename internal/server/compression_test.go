@@ -0,0 +1,140 @@
+package server
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestNegotiateEncoding tests algorithm selection from Accept-Encoding against the server preference order
+func TestNegotiateEncoding(t *testing.T) {
+	preferences := []string{EncodingBrotli, EncodingGzip, EncodingDeflate}
+
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", EncodingGzip},
+		{"deflate", EncodingDeflate},
+		{"gzip, deflate, br", EncodingBrotli},
+		{"gzip;q=1.0, br;q=0.5", EncodingGzip},
+		{"br;q=0, gzip;q=0.2", EncodingGzip},
+		{"GZIP, Deflate", EncodingGzip},
+		{"*", EncodingBrotli},
+		{"*;q=0.5, gzip;q=0.8", EncodingGzip},
+		{"*, br;q=0", EncodingGzip},
+		{"zstd", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.acceptEncoding, preferences); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.acceptEncoding, got, tt.want)
+		}
+	}
+
+	if got := negotiateEncoding("gzip, deflate, br", []string{EncodingDeflate}); got != EncodingDeflate {
+		t.Errorf("expected only configured algorithms to be selected, got %q", got)
+	}
+}
+
+// TestCompressionMiddleware tests that responses are encoded with the negotiated algorithm and sizes are counted
+func TestCompressionMiddleware(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		EnableCompression:     true,
+		CompressionLevel:      9,
+		CompressionAlgorithms: []string{EncodingGzip, EncodingBrotli, EncodingDeflate},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	body := strings.Repeat(`{"jsonrpc":"2.0","result":{"tools":[]}}`, 200)
+	handler := server.compressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "999")
+		w.Write([]byte(body))
+	}))
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		EncodingGzip:    func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		EncodingDeflate: func(r io.Reader) (io.Reader, error) { return flate.NewReader(r), nil },
+		EncodingBrotli:  func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+
+	for _, tt := range []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"gzip, br", EncodingGzip},
+		{"br, deflate", EncodingBrotli},
+		{"deflate", EncodingDeflate},
+		{"identity", ""},
+	} {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/mcp", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Content-Encoding"); got != tt.want {
+				t.Fatalf("expected Content-Encoding %q, got %q", tt.want, got)
+			}
+
+			var reader io.Reader = w.Body
+			if tt.want != "" {
+				if w.Header().Get("Content-Length") != "" {
+					t.Error("expected the uncompressed Content-Length to be dropped")
+				}
+				var err error
+				if reader, err = decoders[tt.want](w.Body); err != nil {
+					t.Fatalf("failed to open %s stream: %v", tt.want, err)
+				}
+			}
+
+			decoded, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if string(decoded) != body {
+				t.Errorf("decoded body does not match the original (%d bytes vs %d)", len(decoded), len(body))
+			}
+		})
+	}
+
+	t.Run("compressed size counts bytes written", func(t *testing.T) {
+		for _, encoding := range []string{EncodingGzip, EncodingDeflate, EncodingBrotli} {
+			w := httptest.NewRecorder()
+			crw, err := server.newCompressedResponseWriter(w, encoding)
+			if err != nil {
+				t.Fatalf("failed to create %s writer: %v", encoding, err)
+			}
+			crw.Write([]byte(body))
+			crw.Close()
+
+			if got := crw.GetOriginalSize(); got != int64(len(body)) {
+				t.Errorf("%s: expected original size %d, got %d", encoding, len(body), got)
+			}
+			if got := crw.GetCompressedSize(); got != int64(w.Body.Len()) {
+				t.Errorf("%s: expected compressed size %d to match bytes written, got %d", encoding, w.Body.Len(), got)
+			}
+			if w.Body.Len() >= len(body) {
+				t.Errorf("%s: expected body to shrink, got %d bytes", encoding, w.Body.Len())
+			}
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// connectionLimitResponse is written to plain HTTP connections rejected by the
+// connection limit. TLS connections are closed without a response since the
+// handshake has not happened yet.
+const connectionLimitResponse = "HTTP/1.1 503 Service Unavailable\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Retry-After: 1\r\n" +
+	"Connection: close\r\n" +
+	"Content-Length: 32\r\n" +
+	"\r\n" +
+	"Too many concurrent connections\n"
+
+// connectionRejectWriteTimeout bounds writing the 503 to a rejected connection
+const connectionRejectWriteTimeout = time.Second
+
+// limitListener tracks accepted connections and rejects new ones once
+// maxConns are open. A maxConns of 0 tracks connections without a limit.
+// Listeners of one server share the active count, so the limit covers them
+// together.
+type limitListener struct {
+	net.Listener
+	maxConns    int
+	writeReject bool
+	active      *atomic.Int64
+	onActive    func(active int64)
+	onRejected  func()
+}
+
+// newLimitListener wraps a listener with connection tracking and limiting
+func (gs *GatewayServer) newLimitListener(listener net.Listener) *limitListener {
+	return &limitListener{
+		Listener:    listener,
+		maxConns:    gs.config.MaxConcurrentConnections,
+		writeReject: !gs.config.TLSEnabled,
+		active:      &gs.activeConnections,
+		onActive: func(active int64) {
+			gs.metrics.Set("http_connections_active", float64(active))
+		},
+		onRejected: func() {
+			gs.metrics.Inc("http_connections_rejected_total")
+		},
+	}
+}
+
+// Accept returns the next connection within the limit; connections over the
+// limit are rejected and never reach the HTTP server
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		active, ok := l.acquire()
+		if !ok {
+			l.onRejected()
+			go l.reject(conn)
+			continue
+		}
+
+		l.onActive(active)
+		return &limitedConn{Conn: conn, listener: l}, nil
+	}
+}
+
+// acquire takes a connection slot, returning the new active count. The
+// compare-and-swap keeps listeners sharing the count from both taking the
+// last slot.
+func (l *limitListener) acquire() (int64, bool) {
+	for {
+		active := l.active.Load()
+		if l.maxConns > 0 && active >= int64(l.maxConns) {
+			return active, false
+		}
+		if l.active.CompareAndSwap(active, active+1) {
+			return active + 1, true
+		}
+	}
+}
+
+// Active returns the number of open connections
+func (l *limitListener) Active() int64 {
+	return l.active.Load()
+}
+
+func (l *limitListener) reject(conn net.Conn) {
+	defer conn.Close()
+
+	if l.writeReject {
+		conn.SetWriteDeadline(time.Now().Add(connectionRejectWriteTimeout))
+		conn.Write([]byte(connectionLimitResponse))
+	}
+}
+
+// limitedConn releases its slot in the listener when closed
+type limitedConn struct {
+	net.Conn
+	listener *limitListener
+	once     sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		c.listener.onActive(c.listener.active.Add(-1))
+	})
+	return err
+}
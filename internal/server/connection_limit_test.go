@@ -0,0 +1,145 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestConnectionLimit tests that connections beyond the limit are rejected and the gauge tracks open connections
+func TestConnectionLimit(t *testing.T) {
+	logger := logging.New("test")
+	recorder := &gaugeRecordingMetrics{}
+	validator := validation.NewValidator(logger, &mockMetrics{})
+	healthMgr := health.NewHealthManager(logger, &mockMetrics{}, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{MaxConcurrentConnections: 2}, logger, recorder, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	limited := server.newLimitListener(listener)
+
+	httpServer := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})}
+	go httpServer.Serve(limited)
+	defer httpServer.Close()
+
+	waitForActive := func(t *testing.T, want int64) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for limited.Active() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d active connections, got %d", want, limited.Active())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	var held []net.Conn
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+		held = append(held, conn)
+	}
+	waitForActive(t, 2)
+
+	t.Run("connection beyond the limit gets 503", func(t *testing.T) {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatalf("expected a 503 response, got error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", resp.StatusCode)
+		}
+		if got := recorder.count("http_connections_rejected_total"); got != 1 {
+			t.Errorf("expected 1 rejected connection, got %d", got)
+		}
+	})
+
+	t.Run("gauge tracks active connections", func(t *testing.T) {
+		if got := recorder.gauge("http_connections_active"); got != 2 {
+			t.Errorf("expected active connections gauge 2, got %v", got)
+		}
+
+		held[0].Close()
+		waitForActive(t, 1)
+		if got := recorder.gauge("http_connections_active"); got != 1 {
+			t.Errorf("expected active connections gauge 1 after close, got %v", got)
+		}
+	})
+
+	t.Run("freed slot accepts a new connection", func(t *testing.T) {
+		client := &http.Client{Timeout: 2 * time.Second}
+		resp, err := client.Get("http://" + listener.Addr().String() + "/")
+		if err != nil {
+			t.Fatalf("expected request to succeed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+	})
+}
+
+// gaugeRecordingMetrics records the last gauge value and counter totals per metric name
+type gaugeRecordingMetrics struct {
+	mockMetrics
+	mutex    sync.Mutex
+	gauges   map[string]float64
+	counters map[string]int
+}
+
+func (m *gaugeRecordingMetrics) Set(name string, value float64, labels ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.gauges == nil {
+		m.gauges = make(map[string]float64)
+	}
+	m.gauges[name] = value
+}
+
+func (m *gaugeRecordingMetrics) Inc(name string, labels ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.counters == nil {
+		m.counters = make(map[string]int)
+	}
+	m.counters[name]++
+}
+
+func (m *gaugeRecordingMetrics) gauge(name string) float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.gauges[name]
+}
+
+func (m *gaugeRecordingMetrics) count(name string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.counters[name]
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Admin response formats selected from the Accept header
+const (
+	ResponseFormatJSON    = "json"
+	ResponseFormatYAML    = "yaml"
+	ResponseFormatMsgpack = "msgpack"
+)
+
+// responseFormatTypes lists the media types accepted for each format; the
+// first is sent as the Content-Type
+var responseFormatTypes = map[string][]string{
+	ResponseFormatJSON:    {"application/json"},
+	ResponseFormatYAML:    {"application/yaml", "application/x-yaml", "text/yaml"},
+	ResponseFormatMsgpack: {"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"},
+}
+
+// ValidateResponseFormat checks that format names a supported admin response format
+func ValidateResponseFormat(format string) error {
+	if _, ok := responseFormatTypes[format]; format != "" && !ok {
+		return fmt.Errorf("response format must be %s, %s or %s, got %q",
+			ResponseFormatJSON, ResponseFormatYAML, ResponseFormatMsgpack, format)
+	}
+	return nil
+}
+
+// negotiateResponseFormat picks the format for an Accept header. The media
+// range with the highest quality wins, ties going to the earlier one; a
+// wildcard selects defaultFormat. It returns false when the header lists no
+// range a supported format matches.
+func negotiateResponseFormat(accept, defaultFormat string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return defaultFormat, true
+	}
+
+	type candidate struct {
+		format  string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		if format, ok := formatForMediaType(mediaType, defaultFormat); ok {
+			candidates = append(candidates, candidate{format, quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].format, true
+}
+
+// formatForMediaType maps a media range to a response format
+func formatForMediaType(mediaType, defaultFormat string) (string, bool) {
+	switch mediaType {
+	case "*/*", "application/*":
+		return defaultFormat, true
+	case "text/*":
+		return ResponseFormatYAML, true
+	}
+	for format, types := range responseFormatTypes {
+		for _, candidate := range types {
+			if mediaType == candidate {
+				return format, true
+			}
+		}
+	}
+	return "", false
+}
+
+// negotiatedResponseWriter carries the format negotiated for an admin
+// request to writeJSONResponse
+type negotiatedResponseWriter struct {
+	http.ResponseWriter
+	format string
+}
+
+// WriteHeader sets the negotiated Content-Type for handlers that write the
+// status before the body
+func (w *negotiatedResponseWriter) WriteHeader(status int) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", responseFormatTypes[w.format][0])
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *negotiatedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *negotiatedResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// contentNegotiationMiddleware selects the admin response format from the
+// Accept header, answering 406 when the header accepts none of them
+func (gs *GatewayServer) contentNegotiationMiddleware(next http.Handler) http.Handler {
+	defaultFormat := gs.config.AdminResponseFormat
+	if defaultFormat == "" {
+		defaultFormat = ResponseFormatJSON
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format, ok := negotiateResponseFormat(r.Header.Get("Accept"), defaultFormat)
+		if !ok {
+			gs.metrics.Inc("admin_responses_not_acceptable_total")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotAcceptable)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":     "not_acceptable",
+				"message":   "No supported response format matches the Accept header",
+				"supported": []string{"application/json", "application/yaml", "application/msgpack"},
+			})
+			return
+		}
+		w.Header().Add("Vary", "Accept")
+		next.ServeHTTP(&negotiatedResponseWriter{ResponseWriter: w, format: format}, r)
+	})
+}
+
+// encodeResponse serializes data in format. YAML and msgpack documents are
+// built from the JSON encoding so field names match the JSON response.
+func encodeResponse(format string, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, err
+	}
+	if format == ResponseFormatJSON {
+		return buf.Bytes(), nil
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(buf.Bytes(), &generic); err != nil {
+		return nil, err
+	}
+	if format == ResponseFormatYAML {
+		return yaml.Marshal(generic)
+	}
+	var packed bytes.Buffer
+	if err := writeMsgpack(&packed, generic); err != nil {
+		return nil, err
+	}
+	return packed.Bytes(), nil
+}
+
+// writeMsgpack encodes a decoded JSON value as MessagePack. Whole numbers are
+// written as integers and map keys in sorted order.
+func writeMsgpack(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			writeMsgpackInt(buf, int64(v))
+		} else {
+			buf.WriteByte(0xcb)
+			binary.Write(buf, binary.BigEndian, v)
+		}
+	case string:
+		writeMsgpackHeader(buf, len(v), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		buf.WriteString(v)
+	case []interface{}:
+		writeMsgpackHeader(buf, len(v), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := writeMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		writeMsgpackHeader(buf, len(v), 0x80, 15, 0, 0xde, 0xdf)
+		for _, key := range keys {
+			writeMsgpack(buf, key)
+			if err := writeMsgpack(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cannot encode %T as msgpack", value)
+	}
+	return nil
+}
+
+// writeMsgpackHeader writes a length prefix: fixed when length fits in
+// fixMax, otherwise the 8-bit (if the type has one), 16-bit or 32-bit form
+func writeMsgpackHeader(buf *bytes.Buffer, length int, fixPrefix byte, fixMax int, prefix8, prefix16, prefix32 byte) {
+	switch {
+	case length <= fixMax:
+		buf.WriteByte(fixPrefix | byte(length))
+	case prefix8 != 0 && length <= math.MaxUint8:
+		buf.WriteByte(prefix8)
+		buf.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		buf.WriteByte(prefix16)
+		binary.Write(buf, binary.BigEndian, uint16(length))
+	default:
+		buf.WriteByte(prefix32)
+		binary.Write(buf, binary.BigEndian, uint32(length))
+	}
+}
+
+func writeMsgpackInt(buf *bytes.Buffer, v int64) {
+	switch {
+	case v >= 0 && v <= 127:
+		buf.WriteByte(byte(v))
+	case v < 0 && v >= -32:
+		buf.WriteByte(byte(int8(v)))
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, v)
+	}
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// TestAdminContentNegotiation tests that admin responses are serialized in
+// the format the Accept header asks for, with a matching Content-Type, and
+// that an Accept header no supported format satisfies gets a 406
+func TestAdminContentNegotiation(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		EnableAdminEndpoints: true,
+		Maintenance:          MaintenanceConfig{Message: "upgrading backends", RetryAfter: 90 * time.Second},
+	}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+	defer server.registry.Shutdown()
+
+	get := func(t *testing.T, accept string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/admin/maintenance", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	expected := map[string]interface{}{
+		"enabled":             false,
+		"message":             "upgrading backends",
+		"retry_after_seconds": int64(90),
+	}
+	check := func(t *testing.T, decoded map[string]interface{}) {
+		t.Helper()
+		for key, want := range expected {
+			if got := decoded[key]; fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("expected %s to be %v, got %v", key, want, got)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		accept      string
+		contentType string
+		decode      func([]byte) (map[string]interface{}, error)
+	}{
+		{"no accept header defaults to json", "", "application/json", decodeJSONMap},
+		{"wildcard defaults to json", "*/*", "application/json", decodeJSONMap},
+		{"json", "application/json", "application/json", decodeJSONMap},
+		{"yaml", "application/yaml", "application/yaml", decodeYAMLMap},
+		{"msgpack", "application/msgpack", "application/msgpack", decodeMsgpackMap},
+		{"highest quality wins", "application/json;q=0.5, application/x-yaml", "application/yaml", decodeYAMLMap},
+		{"unsupported ranges are skipped", "text/html, application/msgpack;q=0.1", "application/msgpack", decodeMsgpackMap},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := get(t, tc.accept)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != tc.contentType {
+				t.Errorf("expected Content-Type %s, got %s", tc.contentType, contentType)
+			}
+			decoded, err := tc.decode(w.Body.Bytes())
+			if err != nil {
+				t.Fatalf("failed to decode %s response: %v", tc.contentType, err)
+			}
+			check(t, decoded)
+		})
+	}
+
+	t.Run("unsupported accept header is rejected", func(t *testing.T) {
+		for _, accept := range []string{"text/html", "application/xml, application/json;q=0"} {
+			w := get(t, accept)
+			if w.Code != http.StatusNotAcceptable {
+				t.Errorf("%s: expected status 406, got %d", accept, w.Code)
+			}
+		}
+	})
+
+	t.Run("configured default applies to wildcards", func(t *testing.T) {
+		yamlServer := NewGatewayServer(ServerConfig{
+			EnableAdminEndpoints: true,
+			AdminResponseFormat:  ResponseFormatYAML,
+		}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+		defer yamlServer.registry.Shutdown()
+
+		req := httptest.NewRequest("GET", "/admin/maintenance", nil)
+		req.Header.Set("Accept", "*/*")
+		w := httptest.NewRecorder()
+		yamlServer.httpServer.Handler.ServeHTTP(w, req)
+		if contentType := w.Header().Get("Content-Type"); contentType != "application/yaml" {
+			t.Errorf("expected the configured yaml default, got %s", contentType)
+		}
+		if err := ValidateResponseFormat("xml"); err == nil {
+			t.Error("expected an unknown response format to be rejected")
+		}
+	})
+}
+
+func decodeJSONMap(body []byte) (map[string]interface{}, error) {
+	var decoded map[string]interface{}
+	err := json.Unmarshal(body, &decoded)
+	return decoded, err
+}
+
+func decodeYAMLMap(body []byte) (map[string]interface{}, error) {
+	var decoded map[string]interface{}
+	err := yaml.Unmarshal(body, &decoded)
+	return decoded, err
+}
+
+// decodeMsgpackMap decodes the subset of MessagePack the gateway writes
+func decodeMsgpackMap(body []byte) (map[string]interface{}, error) {
+	reader := bytes.NewReader(body)
+	value, err := readMsgpack(reader)
+	if err != nil {
+		return nil, err
+	}
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes", reader.Len())
+	}
+	decoded, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map, got %T", value)
+	}
+	return decoded, nil
+}
+
+func readMsgpack(r *bytes.Reader) (interface{}, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	readLength := func(size int) (int, error) {
+		buf := make([]byte, size)
+		if _, err := r.Read(buf); err != nil {
+			return 0, err
+		}
+		switch size {
+		case 1:
+			return int(buf[0]), nil
+		case 2:
+			return int(binary.BigEndian.Uint16(buf)), nil
+		default:
+			return int(binary.BigEndian.Uint32(buf)), nil
+		}
+	}
+	readString := func(length int) (string, error) {
+		buf := make([]byte, length)
+		_, err := r.Read(buf)
+		return string(buf), err
+	}
+	readMap := func(length int) (interface{}, error) {
+		result := make(map[string]interface{}, length)
+		for i := 0; i < length; i++ {
+			key, err := readMsgpack(r)
+			if err != nil {
+				return nil, err
+			}
+			value, err := readMsgpack(r)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(key)] = value
+		}
+		return result, nil
+	}
+	readArray := func(length int) (interface{}, error) {
+		result := make([]interface{}, length)
+		for i := range result {
+			if result[i], err = readMsgpack(r); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	switch {
+	case prefix <= 0x7f:
+		return int64(prefix), nil
+	case prefix >= 0xe0:
+		return int64(int8(prefix)), nil
+	case prefix&0xf0 == 0x80:
+		return readMap(int(prefix & 0x0f))
+	case prefix&0xf0 == 0x90:
+		return readArray(int(prefix & 0x0f))
+	case prefix&0xe0 == 0xa0:
+		return readString(int(prefix & 0x1f))
+	}
+
+	switch prefix {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcb:
+		var v uint64
+		err := binary.Read(r, binary.BigEndian, &v)
+		return math.Float64frombits(v), err
+	case 0xd3:
+		var v int64
+		err := binary.Read(r, binary.BigEndian, &v)
+		return v, err
+	case 0xd9, 0xda, 0xdb:
+		length, err := readLength(map[byte]int{0xd9: 1, 0xda: 2, 0xdb: 4}[prefix])
+		if err != nil {
+			return nil, err
+		}
+		return readString(length)
+	case 0xdc, 0xdd:
+		length, err := readLength(map[byte]int{0xdc: 2, 0xdd: 4}[prefix])
+		if err != nil {
+			return nil, err
+		}
+		return readArray(length)
+	case 0xde, 0xdf:
+		length, err := readLength(map[byte]int{0xde: 2, 0xdf: 4}[prefix])
+		if err != nil {
+			return nil, err
+		}
+		return readMap(length)
+	}
+	return nil, fmt.Errorf("unsupported msgpack prefix 0x%x", prefix)
+}
@@ -23,7 +23,7 @@
 // HTTP middleware stack (applied in order):
 //   1. Request logging and correlation ID assignment
 //   2. CORS handling for cross-origin requests
-//   3. Compression (gzip) for response optimization
+//   3. Compression (brotli, gzip, deflate) for response optimization
 //   4. Authentication and JWT token validation
 //   5. Authorization with role-based access control
 //   6. Rate limiting and throttling
@@ -55,21 +55,25 @@
 package server
 
 import (
-	"compress/gzip"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"os"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/osakka/mcpeg/internal/mcp/types"
 	"github.com/osakka/mcpeg/internal/plugins"
 	"github.com/osakka/mcpeg/internal/registry"
 	"github.com/osakka/mcpeg/internal/router"
@@ -79,6 +83,7 @@ import (
 	"github.com/osakka/mcpeg/pkg/logging"
 	"github.com/osakka/mcpeg/pkg/mcp"
 	"github.com/osakka/mcpeg/pkg/metrics"
+	pkgPlugins "github.com/osakka/mcpeg/pkg/plugins"
 	"github.com/osakka/mcpeg/pkg/rbac"
 	"github.com/osakka/mcpeg/pkg/validation"
 )
@@ -94,6 +99,9 @@ type GatewayServer struct {
 	validator  *validation.Validator
 	healthMgr  *health.HealthManager
 
+	// Guards config fields the admin API replaces at runtime
+	configMutex sync.RWMutex
+
 	// Plugin system integration
 	pluginIntegration *plugins.MCpegPluginIntegration
 
@@ -110,7 +118,45 @@ type GatewayServer struct {
 	startTime time.Time
 
 	// Rate limiting
-	rateLimiter RateLimiter
+	rateLimiter    RateLimiter
+	clientIdentity *clientIdentifier
+
+	// Bounded queue for requests beyond the concurrency limit
+	requestQueue *requestQueue
+
+	// Long-lived streaming connections drained on shutdown
+	streamConns map[StreamConnection]struct{}
+	streamMutex sync.Mutex
+
+	// Server-initiated notifications streamed to SSE clients
+	notifications *NotificationHub
+
+	// Startup readiness state machine
+	readinessState ReadinessState
+	readinessMutex sync.Mutex
+	backendDials   *backendDialCache
+
+	// Closed once the listeners accept connections
+	serving chan struct{}
+
+	// The socket file this server created, so shutdown leaves alone one
+	// that a replacement gateway has since put in its place
+	unixSocketFile os.FileInfo
+
+	// Connections open on every listener, counted against MaxConcurrentConnections
+	activeConnections atomic.Int64
+
+	// Cached plugin tool schemas for argument validation
+	toolSchemas *toolSchemaCache
+
+	// Maintenance mode, toggled via config or the admin API
+	maintenance      MaintenanceConfig
+	maintenanceSince time.Time
+	maintenanceMutex sync.RWMutex
+
+	// Destination of lifecycle events besides the log; nil logs only
+	lifecycleSink  LifecycleSink
+	lifecycleMutex sync.Mutex
 }
 
 // ServerConfig configures the gateway server
@@ -122,12 +168,112 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration `yaml:"write_timeout"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	RequestTimeout  time.Duration `yaml:"request_timeout"` // Per-request handler deadline, 0 disables
+
+	// Backend timeouts keyed by MCP method; unlisted methods use the router default
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts"`
+
+	// Backend response body cap in bytes; 0 uses the router default
+	MaxResponseSize int64 `yaml:"max_response_size"`
+
+	// Client deadlines read from a request header; empty header uses the router default
+	RequestDeadline router.RequestDeadlineConfig `yaml:"request_deadline"`
+
+	// Serve last-known-good responses for read methods when no backend is healthy
+	DegradedMode router.DegradedModeConfig `yaml:"degraded_mode"`
+
+	// Dedup window for tool calls sharing an idempotency key; 0 uses the router
+	// default and a negative value disables deduplication
+	IdempotencyWindow time.Duration `yaml:"idempotency_window"`
+
+	// How long a session's resource subscriptions outlive its last event
+	// stream; 0 uses the router default and a negative value keeps them
+	SubscriptionGracePeriod time.Duration `yaml:"subscription_grace_period"`
+
+	// Turns off sharing one upstream call between concurrent identical reads
+	DisableRequestCoalescing bool `yaml:"disable_request_coalescing"`
+
+	// Turns off writing null array fields in MCP results as []
+	DisableArrayNormalization bool `yaml:"disable_array_normalization"`
+
+	// Sink for requests that fail every retry attempt
+	DeadLetter router.DeadLetterConfig `yaml:"dead_letter"`
+
+	// Audit record of every tool call, with hashed arguments and results
+	ToolAudit router.ToolAuditConfig `yaml:"tool_audit"`
+
+	// Recent requests kept in memory for GET /admin/debug/requests
+	RequestHistory router.RequestHistoryConfig `yaml:"request_history"`
+
+	// Client headers forwarded to backends and static headers injected
+	BackendHeaders router.BackendHeadersConfig `yaml:"backend_headers"`
+
+	// JSON field changes applied to backend requests and responses
+	Transformations []router.TransformRule `yaml:"transformations"`
+
+	// Per-tool tools/call limits, independent of the HTTP rate limit
+	ToolRateLimits []router.ToolRateLimit `yaml:"tool_rate_limits"`
+
+	// Follow backend redirects that keep the request method (307, 308)
+	FollowBackendRedirects bool `yaml:"follow_backend_redirects"`
+
+	// Reject MCP traffic with 503 while health, metrics and admin keep serving
+	Maintenance MaintenanceConfig `yaml:"maintenance"`
+
+	// Default and maximum page size of paginated admin list endpoints
+	Pagination PaginationConfig `yaml:"pagination"`
+
+	// How logging/setLevel is handled: forward, local or both; empty uses the router default
+	LogLevelMode string `yaml:"log_level_mode"`
+
+	// JSON-RPC error data verbosity: full or sanitized; empty uses the router default
+	ErrorDetail string `yaml:"error_detail"`
+
+	// Route methods without a prefix to generic_adapter services instead of
+	// answering method not found
+	GenericAdapterFallback bool `yaml:"generic_adapter_fallback"`
+
+	// Treat requests without a jsonrpc member as JSON-RPC 2.0
+	LenientJSONRPCVersion bool `yaml:"lenient_jsonrpc_version"`
+
+	// Slow-loris protection; zero values fall back to defaults
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes"`
+
+	// Connections beyond this limit are rejected with 503; 0 disables the limit
+	MaxConcurrentConnections int `yaml:"max_concurrent_connections"`
+
+	// Requests beyond the concurrency limit wait in a bounded FIFO queue
+	RequestQueue RequestQueueConfig `yaml:"request_queue"`
+
+	// HTTP/2 (h2 and h2c) and connection keepalive tuning
+	HTTP2     HTTP2Config     `yaml:"http2"`
+	KeepAlive KeepAliveConfig `yaml:"keepalive"`
+
+	// Unix domain socket served alongside, or instead of, the TCP listener
+	UnixSocket UnixSocketConfig `yaml:"unix_socket"`
+
+	// Bind the TCP listener with SO_REUSEPORT so a restarted gateway can take
+	// over the port before this one releases it
+	ReusePort bool `yaml:"reuse_port"`
 
 	// TLS settings
 	TLSEnabled  bool   `yaml:"tls_enabled"`
 	TLSCertFile string `yaml:"tls_cert_file"`
 	TLSKeyFile  string `yaml:"tls_key_file"`
 
+	// CA bundle for verifying optional client certificates, used by self-registration
+	TLSClientCAFile string `yaml:"tls_client_ca_file"`
+
+	// Lets backends register themselves via POST /register without the admin key
+	SelfRegistration SelfRegistrationConfig `yaml:"self_registration"`
+
+	// Retry the registration health check while a backend starts up
+	RegistrationRetry registry.RegistrationRetryConfig `yaml:"registration_retry"`
+
+	// Directories backend TLS files named at registration must be in
+	UpstreamTLSDirs []string `yaml:"upstream_tls_dirs"`
+
 	// CORS settings
 	CORSEnabled      bool     `yaml:"cors_enabled"`
 	CORSAllowOrigins []string `yaml:"cors_allow_origins"`
@@ -139,16 +285,129 @@ type ServerConfig struct {
 	EnableRateLimit   bool `yaml:"enable_rate_limit"`
 	RateLimitRPS      int  `yaml:"rate_limit_rps"`
 
+	// Compression level (0 uses each algorithm's default) and algorithm
+	// preference order among gzip, deflate and br
+	CompressionLevel      int      `yaml:"compression_level"`
+	CompressionAlgorithms []string `yaml:"compression_algorithms"`
+
+	// Per-client RPS keyed by client ID, CIDR or prefix*; RateLimitUnlimited exempts a client
+	RateLimitOverrides map[string]int `yaml:"rate_limit_overrides"`
+
+	// Rate limit clients per subnet of this size (e.g. 24 and 64); 0 keys by full address
+	RateLimitIPv4Prefix int `yaml:"rate_limit_ipv4_prefix"`
+	RateLimitIPv6Prefix int `yaml:"rate_limit_ipv6_prefix"`
+
+	// Idle time after which a client's rate limit state is evicted
+	RateLimitClientTTL time.Duration `yaml:"rate_limit_client_ttl"`
+
+	// Peers whose X-Forwarded-For and X-Real-IP headers are honoured; empty trusts all
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
 	// Management endpoints
 	EnableHealthEndpoints bool `yaml:"enable_health_endpoints"`
 	EnableMetricsEndpoint bool `yaml:"enable_metrics_endpoint"`
 	EnableAdminEndpoints  bool `yaml:"enable_admin_endpoints"`
 
+	// Mount pprof handlers under /admin/debug/pprof/; requires admin endpoints
+	EnableProfiling bool `yaml:"enable_profiling"`
+
+	// Allow POST /admin/metrics/reset to clear counters; development only
+	EnableMetricsReset bool `yaml:"enable_metrics_reset"`
+
+	// Readiness gate
+	ReadinessCriticalPlugins []string      `yaml:"readiness_critical_plugins"` // Plugins that must be healthy before ready
+	WaitForReadiness         bool          `yaml:"wait_for_readiness"`         // Delay opening the listener until ready
+	ReadinessTimeout         time.Duration `yaml:"readiness_timeout"`          // Maximum listener delay, 0 waits indefinitely
+
+	// Service types that need healthy instances before ready, replacing the
+	// default of any one healthy service
+	ReadinessRequiredServices []ReadinessRequiredService `yaml:"readiness_required_services"`
+
+	// Require a backend of each service type to accept TCP connections
+	ReadinessBackendCheck ReadinessBackendCheckConfig `yaml:"readiness_backend_check"`
+
+	// Check plugins and backends once at startup, optionally aborting on failure
+	SelfTest SelfTestConfig `yaml:"self_test"`
+
+	// Sink for startup and shutdown lifecycle events, which are always logged
+	Lifecycle LifecycleConfig `yaml:"lifecycle"`
+
+	// Plugins failing this many consecutive health checks stop receiving traffic
+	// until they recover; 0 uses the default and a negative value disables it
+	PluginAutoDisableThreshold int           `yaml:"plugin_auto_disable_threshold"`
+	PluginHealthCheckInterval  time.Duration `yaml:"plugin_health_check_interval"`
+
+	// External MCP servers launched as subprocesses and exposed as plugins
+	StdioPlugins []pkgPlugins.StdioPluginConfig `yaml:"stdio_plugins"`
+
+	// Plugins that must initialize for startup to succeed; others that fail
+	// are excluded from routing
+	RequiredPlugins []string `yaml:"required_plugins"`
+
+	// Tool name patterns routed to plugins for tools/call
+	PluginToolRoutes []router.PluginToolRoute `yaml:"plugin_tool_routes"`
+
+	// Limits on concurrent tool calls per plugin
+	PluginConcurrency mcp.PluginConcurrencyConfig `yaml:"plugin_concurrency"`
+
+	// Gateway-wide tool and resource allowlist/denylist applied on top of RBAC
+	CapabilityPolicy router.CapabilityPolicyConfig `yaml:"capability_policy"`
+
+	// Permission each MCP method and tool requires; unlisted methods use the RBAC defaults
+	MethodPolicy rbac.MethodPolicy `yaml:"method_policy"`
+
 	// Admin API authentication
 	AdminAPIKey    string `yaml:"admin_api_key"`
 	AdminAPIHeader string `yaml:"admin_api_header"`
+
+	// Admin response format used when the Accept header allows any: json
+	// (default), yaml or msgpack
+	AdminResponseFormat string `yaml:"admin_response_format"`
+
+	// Request identification
+	RequestIDHeader string `yaml:"request_id_header"`
+	RequestIDFormat string `yaml:"request_id_format"` // uuid, timestamp
+
+	// Default load balancing strategy and the header whose value pins a
+	// session to one backend under the hash strategy
+	LoadBalancerStrategy string `yaml:"load_balancer_strategy"`
+	SessionHeader        string `yaml:"session_header"`
+
+	// Region and zone whose backends requests prefer
+	RegionAffinity router.RegionAffinityConfig `yaml:"region_affinity"`
+
+	// Gateway-wide cap on retries relative to routed requests
+	RetryBudget router.RetryBudgetConfig `yaml:"retry_budget"`
+
+	// Webhook and client notifications for circuit breaker state transitions
+	CircuitBreakerAlerts CircuitBreakerAlertConfig `yaml:"circuit_breaker_alerts"`
+
+	// Fraction of requests getting detailed span logging, and the header forcing it
+	TraceSampling TraceSamplingConfig `yaml:"trace_sampling"`
+
+	// Debug body logging (JSON-RPC params and results at trace level)
+	LogRequestBodies   bool     `yaml:"log_request_bodies"`
+	BodyLogPaths       []string `yaml:"body_log_paths"`
+	BodyLogRedactPaths []string `yaml:"body_log_redact_paths"`
+	BodyLogMaxSize     int      `yaml:"body_log_max_size"`
+
+	// Include a truncated panic stack trace in 500 responses; development only
+	ExposePanicTraces bool `yaml:"expose_panic_traces"`
 }
 
+const (
+	// defaultReadHeaderTimeout bounds how long clients may take to send request
+	// headers, so connections trickling headers (slow-loris) are dropped
+	defaultReadHeaderTimeout = 10 * time.Second
+
+	// defaultMaxHeaderBytes caps the size of request headers
+	defaultMaxHeaderBytes = 1 << 20
+
+	// maxExposedPanicTrace bounds the stack trace returned to clients when
+	// ExposePanicTraces is enabled; the full trace is always logged
+	maxExposedPanicTrace = 4096
+)
+
 // NewGatewayServer creates a new gateway server
 func NewGatewayServer(
 	config ServerConfig,
@@ -171,9 +430,29 @@ func NewGatewayServerWithVersion(
 ) *GatewayServer {
 	// Create service registry
 	serviceRegistry := registry.NewServiceRegistry(logger, metrics, validator, healthMgr)
+	if config.LoadBalancerStrategy != "" {
+		if err := serviceRegistry.GetLoadBalancer().SetStrategy("", config.LoadBalancerStrategy); err != nil {
+			logger.Error("load_balancer_strategy_invalid", "error", err)
+		}
+	}
+	if err := serviceRegistry.SetRegistrationRetry(config.RegistrationRetry); err != nil {
+		logger.Error("registration_retry_invalid", "error", err)
+	}
+	if err := serviceRegistry.SetUpstreamTLSDirs(config.UpstreamTLSDirs); err != nil {
+		logger.Error("upstream_tls_dirs_invalid", "error", err)
+	}
 
 	// Initialize plugin system
-	pluginIntegration := plugins.NewMCpegPluginIntegration(serviceRegistry, logger, metrics)
+	pluginHealthConfig := plugins.DefaultHealthMonitorConfig()
+	if config.PluginAutoDisableThreshold != 0 {
+		pluginHealthConfig.AutoDisableThreshold = config.PluginAutoDisableThreshold
+	}
+	if config.PluginHealthCheckInterval > 0 {
+		pluginHealthConfig.CheckInterval = config.PluginHealthCheckInterval
+	}
+	pluginIntegration := plugins.NewMCpegPluginIntegrationWithConfig(serviceRegistry, logger, metrics, pluginHealthConfig)
+	pluginIntegration.RegisterStdioPlugins(config.StdioPlugins)
+	pluginIntegration.SetRequiredPlugins(config.RequiredPlugins)
 
 	// Create RBAC engine with minimal config for now
 	rbacConfig := rbac.Config{
@@ -199,6 +478,7 @@ func NewGatewayServerWithVersion(
 		RetryBackoff:   time.Second,
 		CacheEnabled:   true,
 		CacheTTL:       5 * time.Minute,
+		Concurrency:    config.PluginConcurrency,
 	}
 	pluginHandler := mcp.NewPluginHandler(pluginManager, pluginHandlerConfig, logger, metrics)
 
@@ -262,7 +542,86 @@ func NewGatewayServerWithVersion(
 	)
 
 	// Create MCP router with plugin support and enhanced capabilities
-	mcpRouter := router.NewMCPRouter(serviceRegistry, pluginHandler, rbacEngine, logger, metrics, validator)
+	routerConfig := router.DefaultRouterConfig()
+	if config.RequestIDHeader != "" {
+		routerConfig.RequestIDHeader = config.RequestIDHeader
+	}
+	if config.RequestIDFormat != "" {
+		routerConfig.RequestIDFormat = config.RequestIDFormat
+	}
+	config.RequestIDHeader = routerConfig.RequestIDHeader
+	config.RequestIDFormat = routerConfig.RequestIDFormat
+	if config.TraceSampling.ForceHeader == "" {
+		config.TraceSampling.ForceHeader = defaultTraceForceHeader
+	}
+	if config.SessionHeader != "" {
+		routerConfig.SessionHeader = config.SessionHeader
+	}
+	if config.RegionAffinity.RegionHeader != "" {
+		routerConfig.RegionAffinity.RegionHeader = config.RegionAffinity.RegionHeader
+	}
+	if config.RegionAffinity.ZoneHeader != "" {
+		routerConfig.RegionAffinity.ZoneHeader = config.RegionAffinity.ZoneHeader
+	}
+	routerConfig.RegionAffinity.Region = config.RegionAffinity.Region
+	routerConfig.RegionAffinity.Zone = config.RegionAffinity.Zone
+	// Redaction also applies to dead-letter entries, so it is set even when body logging is off
+	if len(config.BodyLogRedactPaths) > 0 {
+		routerConfig.BodyLogging.RedactPaths = config.BodyLogRedactPaths
+	}
+	if config.LogRequestBodies {
+		routerConfig.BodyLogging.Enabled = true
+		routerConfig.BodyLogging.Paths = config.BodyLogPaths
+		if config.BodyLogMaxSize > 0 {
+			routerConfig.BodyLogging.MaxBodySize = config.BodyLogMaxSize
+		}
+	}
+	routerConfig.CapabilityPolicy = config.CapabilityPolicy
+	routerConfig.MethodPolicy = config.MethodPolicy
+	if config.RequestDeadline.Header != "" {
+		routerConfig.RequestDeadline.Header = config.RequestDeadline.Header
+	}
+	routerConfig.RequestDeadline.MaxDuration = config.RequestDeadline.MaxDuration
+	if len(config.MethodTimeouts) > 0 {
+		routerConfig.MethodTimeouts = config.MethodTimeouts
+	}
+	if config.MaxResponseSize > 0 {
+		routerConfig.MaxResponseSize = config.MaxResponseSize
+	}
+	if config.DegradedMode.Enabled {
+		routerConfig.DegradedMode = config.DegradedMode
+	}
+	if config.IdempotencyWindow != 0 {
+		routerConfig.IdempotencyWindow = config.IdempotencyWindow
+	}
+	if config.SubscriptionGracePeriod != 0 {
+		routerConfig.SubscriptionGracePeriod = config.SubscriptionGracePeriod
+	}
+	if config.DisableRequestCoalescing {
+		routerConfig.RequestCoalescing = false
+	}
+	if config.DisableArrayNormalization {
+		routerConfig.NormalizeEmptyArrays = false
+	}
+	routerConfig.DeadLetter = config.DeadLetter
+	routerConfig.ToolAudit = config.ToolAudit
+	routerConfig.RetryBudget = config.RetryBudget
+	routerConfig.RequestHistory = config.RequestHistory
+	routerConfig.BackendHeaders = config.BackendHeaders
+	routerConfig.Transformations = config.Transformations
+	routerConfig.ToolRateLimits = config.ToolRateLimits
+	routerConfig.FollowRedirects = config.FollowBackendRedirects
+	routerConfig.PluginToolRoutes = config.PluginToolRoutes
+	if config.LogLevelMode != "" {
+		routerConfig.LogLevelMode = config.LogLevelMode
+	}
+	if config.ErrorDetail != "" {
+		routerConfig.ErrorDetail = config.ErrorDetail
+	}
+	routerConfig.GenericAdapterFallback = config.GenericAdapterFallback
+	routerConfig.LenientJSONRPCVersion = config.LenientJSONRPCVersion
+	routerConfig.ServerVersion = version
+	mcpRouter := router.NewMCPRouterWithConfig(serviceRegistry, pluginHandler, rbacEngine, logger, metrics, validator, routerConfig)
 
 	server := &GatewayServer{
 		config:            config,
@@ -277,12 +636,43 @@ func NewGatewayServerWithVersion(
 		metrics:           metrics,
 		validator:         validator,
 		healthMgr:         healthMgr,
+		streamConns:       make(map[StreamConnection]struct{}),
+		notifications:     NewNotificationHub(defaultNotificationHistory),
+		readinessState:    ReadinessStarting,
+		backendDials:      newBackendDialCache(),
+		serving:           make(chan struct{}),
+		toolSchemas:       newToolSchemaCache(),
 		version:           version,
 		commit:            commit,
 		buildTime:         buildTime,
 		startTime:         time.Now(),
 	}
 
+	// Create the rate limiter up front so overrides can be managed via the admin API
+	server.clientIdentity = server.newClientIdentifier()
+	server.rateLimiter = server.newRateLimiter()
+
+	if config.RequestQueue.Enabled {
+		server.requestQueue = newRequestQueue(config.RequestQueue)
+	}
+
+	server.setMaintenance(config.Maintenance, "config")
+
+	lifecycleSink, err := newLifecycleSink(config.Lifecycle)
+	if err != nil {
+		server.logger.Error("lifecycle_config_invalid", "error", err)
+	}
+	server.lifecycleSink = lifecycleSink
+
+	mcpRouter.SetNotificationPublisher(server.PublishNotification)
+	mcpRouter.SetToolSchemaLookup(server.ToolInputSchema)
+	discoveryEngine.OnCapabilityChange(server.publishCapabilityChanges)
+	pluginHandler.OnPluginReloaded(server.detectCapabilityChanges)
+
+	if alerter := newCircuitAlerter(server, config.CircuitBreakerAlerts); alerter != nil {
+		serviceRegistry.GetLoadBalancer().AddCircuitBreakerObserver(alerter.handleEvent)
+	}
+
 	// Setup HTTP server
 	server.setupHTTPServer()
 
@@ -302,28 +692,63 @@ func (gs *GatewayServer) setupHTTPServer() {
 
 	// Setup MCP routes
 	gs.mcpRouter.SetupRoutes(mainRouter)
+	mainRouter.HandleFunc(NotificationStreamPath, gs.handleNotificationStream).Methods("GET")
+	mainRouter.HandleFunc(OpenAPISpecPath, gs.handleOpenAPISpec).Methods("GET")
 
 	// Setup management routes
 	gs.setupManagementRoutes(mainRouter)
 
 	// Create HTTP server
 	address := fmt.Sprintf("%s:%d", gs.config.Address, gs.config.Port)
+	readHeaderTimeout := gs.config.ReadHeaderTimeout
+	if readHeaderTimeout <= 0 {
+		readHeaderTimeout = defaultReadHeaderTimeout
+	}
+	maxHeaderBytes := gs.config.MaxHeaderBytes
+	if maxHeaderBytes <= 0 {
+		maxHeaderBytes = defaultMaxHeaderBytes
+	}
+
 	gs.httpServer = &http.Server{
-		Addr:         address,
-		Handler:      mainRouter,
-		ReadTimeout:  gs.config.ReadTimeout,
-		WriteTimeout: gs.config.WriteTimeout,
-		IdleTimeout:  gs.config.IdleTimeout,
+		Addr:              address,
+		Handler:           mainRouter,
+		ReadTimeout:       gs.config.ReadTimeout,
+		ReadHeaderTimeout: readHeaderTimeout,
+		WriteTimeout:      gs.config.WriteTimeout,
+		IdleTimeout:       gs.config.IdleTimeout,
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+
+	if gs.config.TLSEnabled && gs.config.TLSClientCAFile != "" {
+		tlsConfig, err := clientCATLSConfig(gs.config.TLSClientCAFile)
+		if err != nil {
+			gs.logger.Error("tls_client_ca_load_failed", "error", err)
+		} else {
+			gs.httpServer.TLSConfig = tlsConfig
+		}
+	}
+
+	if err := gs.configureHTTP2(); err != nil {
+		gs.logger.Error("http2_configuration_failed", "error", err)
 	}
 }
 
 // addMiddleware adds middleware to the router
 func (gs *GatewayServer) addMiddleware(router *mux.Router) {
+	// Request ID middleware
+	router.Use(gs.requestIDMiddleware)
+
+	// Trace middleware, ahead of anything that may reject the request
+	router.Use(gs.traceMiddleware)
+
 	// CORS middleware
 	if gs.config.CORSEnabled {
 		router.Use(gs.corsMiddleware)
 	}
 
+	// Maintenance middleware, always installed so the admin API can enable it
+	router.Use(gs.maintenanceMiddleware)
+
 	// Compression middleware
 	if gs.config.EnableCompression {
 		router.Use(gs.compressionMiddleware)
@@ -334,6 +759,16 @@ func (gs *GatewayServer) addMiddleware(router *mux.Router) {
 		router.Use(gs.rateLimitMiddleware)
 	}
 
+	// Request queue middleware
+	if gs.requestQueue != nil {
+		router.Use(gs.requestQueueMiddleware)
+	}
+
+	// Request timeout middleware
+	if gs.config.RequestTimeout > 0 {
+		router.Use(gs.requestTimeoutMiddleware)
+	}
+
 	// Metrics middleware
 	router.Use(gs.metricsMiddleware)
 
@@ -350,12 +785,26 @@ func (gs *GatewayServer) setupManagementRoutes(router *mux.Router) {
 		router.HandleFunc("/health", gs.handleHealth).Methods("GET")
 		router.HandleFunc("/health/live", gs.handleLiveness).Methods("GET")
 		router.HandleFunc("/health/ready", gs.handleReadiness).Methods("GET")
+
+		// Detailed diagnostics expose internals, so they follow the admin endpoint gating
+		if gs.config.EnableAdminEndpoints {
+			var detailedHandler http.Handler = http.HandlerFunc(gs.handleDetailedHealth)
+			if gs.config.AdminAPIKey != "" {
+				detailedHandler = gs.adminAuthMiddleware(detailedHandler)
+			}
+			router.Handle("/health/detailed", detailedHandler).Methods("GET")
+		}
 	}
 
 	if gs.config.EnableMetricsEndpoint {
 		router.HandleFunc("/metrics", gs.handleMetrics).Methods("GET")
 	}
 
+	// Services authenticate themselves, so self-registration sits outside /admin
+	if gs.config.SelfRegistration.Enabled {
+		router.HandleFunc("/register", gs.handleSelfRegister).Methods("POST")
+	}
+
 	if gs.config.EnableAdminEndpoints {
 		adminRouter := router.PathPrefix("/admin").Subrouter()
 
@@ -363,8 +812,11 @@ func (gs *GatewayServer) setupManagementRoutes(router *mux.Router) {
 		if gs.config.AdminAPIKey != "" {
 			adminRouter.Use(gs.adminAuthMiddleware)
 		}
+		adminRouter.Use(gs.contentNegotiationMiddleware)
 
 		gs.setupAdminRoutes(adminRouter)
+	} else if gs.config.EnableProfiling {
+		gs.logger.Warn("profiling_requires_admin_endpoints")
 	}
 }
 
@@ -379,6 +831,10 @@ func (gs *GatewayServer) setupAdminRoutes(router *mux.Router) {
 	router.HandleFunc("/services/{id}/capabilities", gs.handleServiceCapabilities).Methods("GET")
 	router.HandleFunc("/services/types", gs.handleServiceTypes).Methods("GET")
 
+	// Registry backup and migration
+	router.HandleFunc("/registry/export", gs.handleExportRegistry).Methods("GET")
+	router.HandleFunc("/registry/import", gs.handleImportRegistry).Methods("POST")
+
 	// Service discovery
 	router.HandleFunc("/discovery/trigger", gs.handleTriggerDiscovery).Methods("POST")
 	router.HandleFunc("/discovery/services", gs.handleDiscoveredServices).Methods("GET")
@@ -389,12 +845,30 @@ func (gs *GatewayServer) setupAdminRoutes(router *mux.Router) {
 	router.HandleFunc("/loadbalancer/stats/{service_id}", gs.handleServiceLoadBalancerStats).Methods("GET")
 	router.HandleFunc("/loadbalancer/reset/{service_id}", gs.handleResetCircuitBreaker).Methods("POST")
 	router.HandleFunc("/loadbalancer/strategies", gs.handleLoadBalancerStrategies).Methods("GET")
+	router.HandleFunc("/loadbalancer/strategy", gs.handleSetLoadBalancerStrategy).Methods("PUT")
+	router.HandleFunc("/loadbalancer/canary", gs.handleListCanaryWeights).Methods("GET")
+	router.HandleFunc("/loadbalancer/canary/{service_type}", gs.handleGetCanaryWeights).Methods("GET")
+	router.HandleFunc("/loadbalancer/canary/{service_type}", gs.handleSetCanaryWeights).Methods("PUT")
+	router.HandleFunc("/loadbalancer/canary/{service_type}", gs.handleClearCanaryWeights).Methods("DELETE")
 
 	// Configuration
 	router.HandleFunc("/config", gs.handleGetConfig).Methods("GET")
 	router.HandleFunc("/config", gs.handleUpdateConfig).Methods("PUT")
 	router.HandleFunc("/config/reload", gs.handleConfigReload).Methods("POST")
 
+	// Rate limit overrides
+	router.HandleFunc("/ratelimit/overrides", gs.handleListRateLimitOverrides).Methods("GET")
+	router.HandleFunc("/ratelimit/overrides", gs.handleSetRateLimitOverride).Methods("PUT")
+	router.HandleFunc("/ratelimit/overrides", gs.handleDeleteRateLimitOverride).Methods("DELETE")
+
+	// Capability policy
+	router.HandleFunc("/policy/capabilities", gs.handleGetCapabilityPolicy).Methods("GET")
+	router.HandleFunc("/policy/capabilities", gs.handleSetCapabilityPolicy).Methods("PUT")
+
+	// Maintenance mode
+	router.HandleFunc("/maintenance", gs.handleGetMaintenance).Methods("GET")
+	router.HandleFunc("/maintenance", gs.handleSetMaintenance).Methods("PUT")
+
 	// Plugin management
 	router.HandleFunc("/plugins", gs.handleListPlugins).Methods("GET")
 	router.HandleFunc("/plugins/{name}", gs.handleGetPlugin).Methods("GET")
@@ -403,6 +877,7 @@ func (gs *GatewayServer) setupAdminRoutes(router *mux.Router) {
 	router.HandleFunc("/plugins/{name}/tools", gs.handleGetPluginTools).Methods("GET")
 	router.HandleFunc("/plugins/{name}/resources", gs.handleGetPluginResources).Methods("GET")
 	router.HandleFunc("/plugins/{name}/health", gs.handleGetPluginHealth).Methods("GET")
+	router.HandleFunc("/plugins/{name}/reload", gs.handleReloadPlugin).Methods("POST")
 	router.HandleFunc("/plugins/health", gs.handleGetAllPluginHealth).Methods("GET")
 	router.HandleFunc("/plugins/metrics", gs.handleGetPluginMetrics).Methods("GET")
 	router.HandleFunc("/plugins/capabilities", gs.handleGetPluginCapabilities).Methods("GET")
@@ -411,6 +886,12 @@ func (gs *GatewayServer) setupAdminRoutes(router *mux.Router) {
 	router.HandleFunc("/info", gs.handleSystemInfo).Methods("GET")
 	router.HandleFunc("/stats", gs.handleSystemStats).Methods("GET")
 	router.HandleFunc("/debug/goroutines", gs.handleGoroutineStats).Methods("GET")
+	router.HandleFunc("/debug/requests", gs.handleRecentRequests).Methods("GET")
+	router.HandleFunc("/metrics/snapshot", gs.handleMetricsSnapshot).Methods("GET")
+	router.HandleFunc("/metrics/reset", gs.handleMetricsReset).Methods("POST")
+	if gs.config.EnableProfiling {
+		gs.setupProfilingRoutes(router)
+	}
 
 	// API documentation
 	router.HandleFunc("/api", gs.handleAPIDocumentation).Methods("GET")
@@ -423,28 +904,115 @@ func (gs *GatewayServer) Start(ctx context.Context) error {
 		"tls_enabled", gs.config.TLSEnabled)
 
 	// Initialize plugins
-	if err := gs.pluginIntegration.InitializePlugins(ctx); err != nil {
+	if err := gs.initializePlugins(ctx); err != nil {
 		gs.logger.Error("failed_to_initialize_plugins", "error", err)
 		return fmt.Errorf("failed to initialize plugins: %w", err)
 	}
+	gs.pluginIntegration.StartHealthMonitor(ctx)
+	gs.EmitLifecycleEvent(LifecycleEvent{
+		Event:   LifecyclePluginsReady,
+		Details: map[string]interface{}{"plugins": len(gs.pluginIntegration.GetPluginManager().ListEnabledPlugins())},
+	})
 
-	// Start HTTP server in a goroutine
-	errChan := make(chan error, 1)
-	go func() {
-		var err error
-		if gs.config.TLSEnabled {
-			err = gs.httpServer.ListenAndServeTLS(gs.config.TLSCertFile, gs.config.TLSKeyFile)
-		} else {
-			err = gs.httpServer.ListenAndServe()
+	// Surface broken plugins and unreachable backends before taking traffic
+	if gs.config.SelfTest.Enabled {
+		if _, err := gs.runSelfTest(ctx); err != nil {
+			gs.logger.Error("startup_self_test_failed", "error", err)
+			return fmt.Errorf("startup self-test failed: %w", err)
 		}
-		if err != nil && err != http.ErrServerClosed {
-			errChan <- err
+	}
+
+	// Optionally hold the listener back until the readiness gate passes
+	if gs.config.WaitForReadiness {
+		if err := gs.waitForReadiness(ctx); err != nil && ctx.Err() != nil {
+			gs.logger.Info("gateway_server_stopping", "reason", "context_cancelled")
+			return gs.Stop()
 		}
-	}()
+	}
+
+	// Listen through the connection limiter, which also tracks active connections
+	var limited net.Listener
+	if !gs.config.UnixSocket.DisableTCP {
+		listenConfig := net.ListenConfig{KeepAlive: gs.config.KeepAlive.TCPPeriod}
+		if gs.config.ReusePort {
+			listenConfig.Control = reusePortControl
+		}
+		listener, err := listenConfig.Listen(ctx, "tcp", gs.httpServer.Addr)
+		if err != nil {
+			gs.logger.Error("gateway_server_listen_failed",
+				"address", gs.httpServer.Addr,
+				"error", err)
+			return fmt.Errorf("failed to listen on %s: %w", gs.httpServer.Addr, err)
+		}
+		limited = gs.newLimitListener(listener)
+	}
 
+	var unixListener net.Listener
+	if gs.config.UnixSocket.Path != "" {
+		listener, err := gs.listenUnixSocket()
+		if err != nil {
+			gs.logger.Error("gateway_server_listen_failed",
+				"address", gs.config.UnixSocket.Path,
+				"error", err)
+			if limited != nil {
+				limited.Close()
+			}
+			return err
+		}
+		// The socket shares the TCP listener's connection limit; local
+		// proxies connect in plain HTTP, so rejections get the 503
+		limitedUnix := gs.newLimitListener(listener)
+		limitedUnix.writeReject = true
+		unixListener = limitedUnix
+	}
+
+	// Start HTTP server in a goroutine per listener
+	errChan := make(chan error, 2)
+	if limited != nil {
+		go func() {
+			var err error
+			if gs.config.TLSEnabled {
+				err = gs.httpServer.ServeTLS(limited, gs.config.TLSCertFile, gs.config.TLSKeyFile)
+			} else {
+				err = gs.httpServer.Serve(limited)
+			}
+			if err != nil && err != http.ErrServerClosed {
+				errChan <- err
+			}
+		}()
+	}
+	if unixListener != nil {
+		// Local proxies connect in plain HTTP; TLS applies to TCP only
+		go func() {
+			if err := gs.httpServer.Serve(unixListener); err != nil && err != http.ErrServerClosed {
+				errChan <- err
+			}
+		}()
+	}
+
+	address := gs.httpServer.Addr
+	if limited == nil {
+		address = ""
+	}
 	gs.logger.Info("gateway_server_started",
-		"address", gs.httpServer.Addr,
+		"address", address,
+		"unix_socket", gs.config.UnixSocket.Path,
+		"max_concurrent_connections", gs.config.MaxConcurrentConnections,
 		"pid", fmt.Sprintf("%d", gs.getPID()))
+	gs.EmitLifecycleEvent(LifecycleEvent{
+		Event: LifecycleServing,
+		Details: map[string]interface{}{
+			"address":     address,
+			"unix_socket": gs.config.UnixSocket.Path,
+			"tls_enabled": gs.config.TLSEnabled,
+			"reuse_port":  gs.config.ReusePort,
+		},
+	})
+	select {
+	case <-gs.serving:
+	default:
+		close(gs.serving)
+	}
 
 	// Wait for context cancellation or server error
 	select {
@@ -457,33 +1025,43 @@ func (gs *GatewayServer) Start(ctx context.Context) error {
 	}
 }
 
+// Serving returns a channel that is closed once Start has bound its
+// listeners and the server accepts connections
+func (gs *GatewayServer) Serving() <-chan struct{} {
+	return gs.serving
+}
+
 // Stop gracefully stops the gateway server
 func (gs *GatewayServer) Stop() error {
 	gs.logger.Info("gateway_server_shutting_down")
+	gs.EmitLifecycleEvent(LifecycleEvent{
+		Event:   LifecycleDraining,
+		Details: map[string]interface{}{"shutdown_timeout": gs.config.ShutdownTimeout.String()},
+	})
+
+	// Fail readiness probes first so load balancers stop sending traffic
+	gs.setReadinessStopping()
 
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), gs.config.ShutdownTimeout)
 	defer cancel()
 
-	// Shutdown plugins first
-	if err := gs.pluginIntegration.ShutdownPlugins(ctx); err != nil {
-		gs.logger.Error("plugin_shutdown_error", "error", err)
-		// Don't return error, continue with shutdown
-	}
-
-	// Shutdown HTTP server
-	if err := gs.httpServer.Shutdown(ctx); err != nil {
-		gs.logger.Error("http_server_shutdown_error", "error", err)
-		return err
-	}
-
-	// Shutdown service registry
-	if err := gs.registry.Shutdown(); err != nil {
-		gs.logger.Error("service_registry_shutdown_error", "error", err)
+	// Stop accepting and drain requests before shutting down the plugins and
+	// registry they depend on; every phase shares the shutdown deadline
+	if err := gs.runShutdownPhases(ctx, gs.shutdownPhases()); err != nil {
+		gs.logger.Error("gateway_server_shutdown_incomplete", "error", err)
+		gs.EmitLifecycleEvent(LifecycleEvent{
+			Event:   LifecycleStopped,
+			Details: map[string]interface{}{"clean": false, "error": err.Error()},
+		})
 		return err
 	}
 
 	gs.logger.Info("gateway_server_shutdown_complete")
+	gs.EmitLifecycleEvent(LifecycleEvent{
+		Event:   LifecycleStopped,
+		Details: map[string]interface{}{"clean": true},
+	})
 	return nil
 }
 
@@ -505,20 +1083,115 @@ func (gs *GatewayServer) handleLiveness(w http.ResponseWriter, r *http.Request)
 
 func (gs *GatewayServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
 	// Readiness check - server can handle requests
-	healthyServices := gs.registry.GetHealthyServices()
+	report := gs.checkReadiness(r.Context())
 
 	status := "ready"
 	httpStatus := http.StatusOK
 
-	if len(healthyServices) == 0 {
+	if report.State != ReadinessReady {
 		status = "not_ready"
 		httpStatus = http.StatusServiceUnavailable
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(httpStatus)
-	fmt.Fprintf(w, `{"status":"%s","healthy_services":%d,"timestamp":"%s"}`,
-		status, len(healthyServices), time.Now().Format(time.RFC3339))
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"status":           status,
+		"state":            report.State,
+		"healthy_services": report.HealthyServices,
+		"reasons":          report.Reasons,
+		"timestamp":        time.Now().Format(time.RFC3339),
+	})
+}
+
+// handleDetailedHealth returns per-component diagnostics from the health manager
+// together with registered service and plugin health
+func (gs *GatewayServer) handleDetailedHealth(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	status := health.StatusHealthy
+	report := map[string]interface{}{}
+
+	// Component checks from the health manager
+	components := make(map[string]interface{})
+	if gs.healthMgr != nil {
+		overall := gs.healthMgr.GetHealth(r.Context())
+		status = overall.Status
+
+		for _, check := range overall.Checks {
+			components[check.Name] = map[string]interface{}{
+				"status":     check.Status,
+				"critical":   check.Critical,
+				"message":    check.Message,
+				"error":      check.Error,
+				"last_check": check.Timestamp.Format(time.RFC3339),
+				"duration":   check.Duration.String(),
+			}
+
+			if check.Critical && check.Status == health.StatusUnhealthy {
+				status = health.StatusUnhealthy
+			}
+		}
+
+		report["summary"] = overall.Summary
+		report["suggestions"] = overall.Suggestions
+	}
+	report["components"] = components
+
+	// Registered service health
+	services := gs.registry.GetAllServices()
+	serviceHealth := make(map[string]interface{}, len(services))
+	unhealthyServices := 0
+	for id, service := range services {
+		serviceHealth[id] = map[string]interface{}{
+			"name":      service.Name,
+			"type":      service.Type,
+			"status":    service.Status,
+			"health":    service.Health,
+			"last_seen": service.LastSeen.Format(time.RFC3339),
+		}
+		if service.Health == registry.HealthUnhealthy {
+			unhealthyServices++
+		}
+	}
+	report["services"] = map[string]interface{}{
+		"total":     len(services),
+		"unhealthy": unhealthyServices,
+		"services":  serviceHealth,
+	}
+
+	// Plugin health
+	pluginHealth := gs.pluginIntegration.HealthCheckPlugins(r.Context())
+	report["plugins"] = pluginHealth
+
+	// Non-critical service and plugin failures degrade the gateway
+	if status == health.StatusHealthy {
+		if unhealthyServices > 0 || pluginHealth["overall_status"] != "healthy" {
+			status = health.StatusDegraded
+		}
+	}
+
+	report["status"] = status
+	report["version"] = gs.version
+	report["uptime"] = time.Since(gs.startTime).String()
+	report["timestamp"] = time.Now().Format(time.RFC3339)
+
+	httpStatus := http.StatusOK
+	if status == health.StatusUnhealthy {
+		httpStatus = http.StatusServiceUnavailable
+	}
+
+	gs.metrics.Inc("health_detailed_requests_total", "status", string(status))
+	gs.logger.Info("detailed_health_check_completed",
+		"status", status,
+		"components", len(components),
+		"services", len(services),
+		"duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(httpStatus)
+	gs.writeJSONResponse(w, report)
 }
 
 func (gs *GatewayServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
@@ -526,8 +1199,15 @@ func (gs *GatewayServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 		"remote_addr", r.RemoteAddr,
 		"user_agent", r.Header.Get("User-Agent"))
 
+	// Exemplars are only exposed to scrapers that negotiate OpenMetrics
+	openMetrics := wantsOpenMetrics(r)
+
 	// Set Prometheus content type
-	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if openMetrics {
+		w.Header().Set("Content-Type", openMetricsContentType)
+	} else {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	}
 	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	w.Header().Set("Pragma", "no-cache")
 	w.Header().Set("Expires", "0")
@@ -535,7 +1215,15 @@ func (gs *GatewayServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	// Write Prometheus metrics
 	startTime := time.Now()
 
-	if err := gs.writePrometheusMetrics(w); err != nil {
+	if openMetrics {
+		var buf bytes.Buffer
+		if err := gs.writePrometheusMetrics(&buf, true); err != nil {
+			gs.logger.Error("prometheus_metrics_write_failed", "error", err)
+			http.Error(w, "Error generating metrics", http.StatusInternalServerError)
+			return
+		}
+		w.Write(toOpenMetrics(buf.Bytes()))
+	} else if err := gs.writePrometheusMetrics(w, false); err != nil {
 		gs.logger.Error("prometheus_metrics_write_failed", "error", err)
 		http.Error(w, "Error generating metrics", http.StatusInternalServerError)
 		return
@@ -550,8 +1238,9 @@ func (gs *GatewayServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	gs.metrics.Observe("prometheus_metrics_generation_duration_ms", float64(duration.Milliseconds()))
 }
 
-// writePrometheusMetrics writes all metrics in Prometheus format
-func (gs *GatewayServer) writePrometheusMetrics(w io.Writer) error {
+// writePrometheusMetrics writes all metrics in Prometheus format, with
+// histogram exemplars when exemplars is set
+func (gs *GatewayServer) writePrometheusMetrics(w io.Writer, exemplars bool) error {
 	// Write header
 	fmt.Fprintf(w, "# HELP mcpeg_info Information about the MCPEG gateway instance\n")
 	fmt.Fprintf(w, "# TYPE mcpeg_info gauge\n")
@@ -571,7 +1260,7 @@ func (gs *GatewayServer) writePrometheusMetrics(w io.Writer) error {
 		gs.config.Address, gs.config.Port, gs.config.TLSEnabled)
 
 	// HTTP metrics
-	if err := gs.writeHTTPMetrics(w); err != nil {
+	if err := gs.writeHTTPMetrics(w, exemplars); err != nil {
 		return fmt.Errorf("failed to write HTTP metrics: %w", err)
 	}
 
@@ -585,6 +1274,16 @@ func (gs *GatewayServer) writePrometheusMetrics(w io.Writer) error {
 		return fmt.Errorf("failed to write MCP router metrics: %w", err)
 	}
 
+	// Plugin invocation metrics
+	if err := gs.writePluginMetrics(w); err != nil {
+		return fmt.Errorf("failed to write plugin metrics: %w", err)
+	}
+
+	// Rate limiter metrics
+	if err := gs.writeRateLimiterMetrics(w); err != nil {
+		return fmt.Errorf("failed to write rate limiter metrics: %w", err)
+	}
+
 	// Health metrics
 	if err := gs.writeHealthMetrics(w); err != nil {
 		return fmt.Errorf("failed to write health metrics: %w", err)
@@ -604,7 +1303,7 @@ func (gs *GatewayServer) writePrometheusMetrics(w io.Writer) error {
 }
 
 // writeHTTPMetrics writes HTTP-related metrics
-func (gs *GatewayServer) writeHTTPMetrics(w io.Writer) error {
+func (gs *GatewayServer) writeHTTPMetrics(w io.Writer, exemplars bool) error {
 	// HTTP request metrics
 	stats := gs.metrics.GetAllStats()
 
@@ -624,7 +1323,10 @@ func (gs *GatewayServer) writeHTTPMetrics(w io.Writer) error {
 	fmt.Fprintf(w, "# HELP mcpeg_http_request_duration_seconds HTTP request duration in seconds\n")
 	fmt.Fprintf(w, "# TYPE mcpeg_http_request_duration_seconds histogram\n")
 
-	if stat, exists := stats["http_request_duration_ms"]; exists {
+	if histograms, ok := gs.metrics.(metrics.HistogramObserver); ok {
+		writeHistograms(w, "mcpeg_http_request_duration_seconds",
+			histograms.Histograms("http_request_duration_seconds"), exemplars)
+	} else if stat, exists := stats["http_request_duration_ms"]; exists {
 		// Convert milliseconds to seconds for Prometheus convention
 		fmt.Fprintf(w, "mcpeg_http_request_duration_seconds_sum %f\n", stat.Sum/1000.0)
 		fmt.Fprintf(w, "mcpeg_http_request_duration_seconds_count %d\n", stat.Count)
@@ -772,6 +1474,56 @@ func (gs *GatewayServer) writeMCPRouterMetrics(w io.Writer) error {
 	return nil
 }
 
+// writePluginMetrics writes per-plugin invocation metrics, labelled by plugin
+func (gs *GatewayServer) writePluginMetrics(w io.Writer) error {
+	pluginStats := gs.pluginIntegration.GetPluginInvocationStats()
+
+	fmt.Fprintf(w, "# HELP mcpeg_plugin_invocations_total Total plugin tool invocations\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_plugin_invocations_total counter\n")
+	for _, stats := range pluginStats {
+		fmt.Fprintf(w, "mcpeg_plugin_invocations_total{plugin=\"%s\"} %d\n", stats.Plugin, stats.Invocations)
+	}
+
+	fmt.Fprintf(w, "# HELP mcpeg_plugin_errors_total Total failed plugin tool invocations\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_plugin_errors_total counter\n")
+	for _, stats := range pluginStats {
+		fmt.Fprintf(w, "mcpeg_plugin_errors_total{plugin=\"%s\"} %d\n", stats.Plugin, stats.Errors)
+	}
+
+	fmt.Fprintf(w, "# HELP mcpeg_plugin_error_rate Fraction of plugin tool invocations that failed\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_plugin_error_rate gauge\n")
+	for _, stats := range pluginStats {
+		fmt.Fprintf(w, "mcpeg_plugin_error_rate{plugin=\"%s\"} %f\n", stats.Plugin, stats.ErrorRate())
+	}
+
+	fmt.Fprintf(w, "# HELP mcpeg_plugin_invocation_duration_seconds Plugin tool invocation duration\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_plugin_invocation_duration_seconds summary\n")
+	for _, stats := range pluginStats {
+		fmt.Fprintf(w, "mcpeg_plugin_invocation_duration_seconds_sum{plugin=\"%s\"} %f\n", stats.Plugin, stats.DurationSumMs/1000.0)
+		fmt.Fprintf(w, "mcpeg_plugin_invocation_duration_seconds_count{plugin=\"%s\"} %d\n", stats.Plugin, stats.Invocations)
+	}
+
+	fmt.Fprintf(w, "# HELP mcpeg_plugin_invocations_in_flight Plugin tool invocations executing\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_plugin_invocations_in_flight gauge\n")
+	for _, stats := range pluginStats {
+		fmt.Fprintf(w, "mcpeg_plugin_invocations_in_flight{plugin=\"%s\"} %d\n", stats.Plugin, stats.InFlight)
+	}
+
+	fmt.Fprintf(w, "# HELP mcpeg_plugin_invocations_queued Plugin tool invocations waiting at the concurrency limit\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_plugin_invocations_queued gauge\n")
+	for _, stats := range pluginStats {
+		fmt.Fprintf(w, "mcpeg_plugin_invocations_queued{plugin=\"%s\"} %d\n", stats.Plugin, stats.Queued)
+	}
+
+	fmt.Fprintf(w, "# HELP mcpeg_plugin_saturated_total Plugin tool invocations that found the concurrency limit reached\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_plugin_saturated_total counter\n")
+	for _, stats := range pluginStats {
+		fmt.Fprintf(w, "mcpeg_plugin_saturated_total{plugin=\"%s\"} %d\n", stats.Plugin, stats.Saturated)
+	}
+
+	return nil
+}
+
 // writeHealthMetrics writes health check metrics
 func (gs *GatewayServer) writeHealthMetrics(w io.Writer) error {
 	// Gateway health status
@@ -890,8 +1642,47 @@ func (gs *GatewayServer) writeBusinessMetrics(w io.Writer) error {
 // Admin endpoint handlers (simplified implementations)
 
 func (gs *GatewayServer) handleListServices(w http.ResponseWriter, r *http.Request) {
+	query, err := parseServiceQuery(r, gs.config.Pagination)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_query_parameter",
+			"message": err.Error(),
+		})
+		return
+	}
+
 	services := gs.registry.GetAllServices()
-	gs.writeJSONResponse(w, services)
+	filtered, page := query.apply(services)
+
+	gs.logger.Debug("admin_list_services_response",
+		"total_services", len(services),
+		"filtered_count", len(filtered),
+		"returned_count", len(page))
+
+	gs.metrics.Inc("admin_api_list_services_requests_total")
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"services": page,
+		"metadata": map[string]interface{}{
+			"total_count":    len(services),
+			"filtered_count": len(filtered),
+			"returned_count": len(page),
+			"limit":          query.Limit,
+			"limit_clamped":  query.Clamped,
+			"offset":         query.Offset,
+			"sort":           query.Sort,
+			"descending":     query.Descending,
+			"filters_applied": map[string]interface{}{
+				"type":   query.Type,
+				"health": query.Health,
+				"status": query.Status,
+				"tag":    query.Tags,
+				"name":   query.Name,
+			},
+			"timestamp": time.Now().Format(time.RFC3339),
+		},
+	})
 }
 
 func (gs *GatewayServer) handleRegisterService(w http.ResponseWriter, r *http.Request) {
@@ -945,6 +1736,7 @@ func (gs *GatewayServer) handleRegisterService(w http.ResponseWriter, r *http.Re
 		"service_name", req.Name,
 		"service_type", req.Type,
 		"endpoint", req.Endpoint)
+	gs.publishListChanged(req.Type)
 
 	// Record metrics
 	gs.metrics.Inc("admin_api_service_registrations_total",
@@ -973,11 +1765,17 @@ func (gs *GatewayServer) handleUnregisterService(w http.ResponseWriter, r *http.
 	vars := mux.Vars(r)
 	serviceID := vars["id"]
 
+	var serviceType string
+	if service := gs.registry.GetService(serviceID); service != nil {
+		serviceType = service.Type
+	}
+
 	if err := gs.registry.UnregisterService(r.Context(), serviceID); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Failed to unregister service: %v", err)
 		return
 	}
+	gs.publishListChanged(serviceType)
 
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintf(w, "Service unregistered: %s", serviceID)
@@ -1063,45 +1861,158 @@ func (gs *GatewayServer) handleDiscoveredServices(w http.ResponseWriter, r *http
 		"source_filter", source,
 		"type_filter", serviceType)
 
-	// Record metrics
-	gs.metrics.Inc("admin_api_discovered_services_requests_total")
-	gs.metrics.Set("admin_api_discovered_services_count", float64(len(filtered)))
+	// Record metrics
+	gs.metrics.Inc("admin_api_discovered_services_requests_total")
+	gs.metrics.Set("admin_api_discovered_services_count", float64(len(filtered)))
+
+	// Return response with metadata
+	response := map[string]interface{}{
+		"services": filtered,
+		"metadata": map[string]interface{}{
+			"total_count":    len(discovered),
+			"filtered_count": len(filtered),
+			"filters_applied": map[string]string{
+				"source":     source,
+				"type":       serviceType,
+				"registered": registered,
+			},
+			"timestamp": time.Now().Format(time.RFC3339),
+		},
+	}
+
+	gs.writeJSONResponse(w, response)
+}
+
+func (gs *GatewayServer) handleLoadBalancerStats(w http.ResponseWriter, r *http.Request) {
+	stats := gs.registry.GetLoadBalancer().GetAllStats()
+	gs.writeJSONResponse(w, stats)
+}
+
+func (gs *GatewayServer) handleResetCircuitBreaker(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serviceID := vars["service_id"]
+
+	gs.registry.GetLoadBalancer().ResetCircuitBreaker(serviceID)
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "Circuit breaker reset for service: %s", serviceID)
+}
+
+func (gs *GatewayServer) handleListCanaryWeights(w http.ResponseWriter, r *http.Request) {
+	gs.writeJSONResponse(w, gs.registry.GetLoadBalancer().GetAllCanaryWeights())
+}
+
+func (gs *GatewayServer) handleGetCanaryWeights(w http.ResponseWriter, r *http.Request) {
+	serviceType := mux.Vars(r)["service_type"]
+
+	weights := gs.registry.GetLoadBalancer().GetCanaryWeights(serviceType)
+	if weights == nil {
+		w.WriteHeader(http.StatusNotFound)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "canary_not_configured",
+			"message": fmt.Sprintf("No canary split configured for service type: %s", serviceType),
+		})
+		return
+	}
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"service_type": serviceType,
+		"weights":      weights,
+	})
+}
+
+func (gs *GatewayServer) handleSetCanaryWeights(w http.ResponseWriter, r *http.Request) {
+	serviceType := mux.Vars(r)["service_type"]
+
+	var req struct {
+		Weights map[string]int `json:"weights"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_request_body",
+			"message": "Failed to parse JSON request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := gs.registry.GetLoadBalancer().SetCanaryWeights(serviceType, req.Weights); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_canary_weights",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	gs.logger.Info("admin_canary_weights_updated",
+		"service_type", serviceType,
+		"weights", req.Weights,
+		"remote_addr", r.RemoteAddr)
+	gs.metrics.Inc("admin_api_canary_updates_total", "service_type", serviceType)
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"service_type": serviceType,
+		"weights":      req.Weights,
+	})
+}
+
+func (gs *GatewayServer) handleClearCanaryWeights(w http.ResponseWriter, r *http.Request) {
+	serviceType := mux.Vars(r)["service_type"]
 
-	// Return response with metadata
-	response := map[string]interface{}{
-		"services": filtered,
-		"metadata": map[string]interface{}{
-			"total_count":    len(discovered),
-			"filtered_count": len(filtered),
-			"filters_applied": map[string]string{
-				"source":     source,
-				"type":       serviceType,
-				"registered": registered,
-			},
-			"timestamp": time.Now().Format(time.RFC3339),
-		},
-	}
+	gs.registry.GetLoadBalancer().ClearCanaryWeights(serviceType)
 
-	gs.writeJSONResponse(w, response)
+	gs.logger.Info("admin_canary_weights_cleared",
+		"service_type", serviceType,
+		"remote_addr", r.RemoteAddr)
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (gs *GatewayServer) handleLoadBalancerStats(w http.ResponseWriter, r *http.Request) {
-	stats := gs.registry.GetLoadBalancer().GetAllStats()
-	gs.writeJSONResponse(w, stats)
+func (gs *GatewayServer) handleGetCapabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	gs.writeJSONResponse(w, gs.mcpRouter.GetCapabilityPolicy())
 }
 
-func (gs *GatewayServer) handleResetCircuitBreaker(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	serviceID := vars["service_id"]
+// handleSetCapabilityPolicy replaces the capability policy without a restart
+func (gs *GatewayServer) handleSetCapabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy router.CapabilityPolicyConfig
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_request_body",
+			"message": "Failed to parse JSON request body",
+			"details": err.Error(),
+		})
+		return
+	}
 
-	gs.registry.GetLoadBalancer().ResetCircuitBreaker(serviceID)
+	if err := gs.mcpRouter.SetCapabilityPolicy(policy); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_capability_policy",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, "Circuit breaker reset for service: %s", serviceID)
+	gs.configMutex.Lock()
+	gs.config.CapabilityPolicy = policy
+	gs.configMutex.Unlock()
+
+	gs.logger.Info("admin_capability_policy_updated",
+		"remote_addr", r.RemoteAddr)
+	gs.metrics.Inc("admin_api_capability_policy_updates_total")
+
+	gs.writeJSONResponse(w, policy)
 }
 
 func (gs *GatewayServer) handleGetConfig(w http.ResponseWriter, r *http.Request) {
-	gs.writeJSONResponse(w, gs.config)
+	gs.configMutex.RLock()
+	config := gs.config
+	gs.configMutex.RUnlock()
+
+	gs.writeJSONResponse(w, config)
 }
 
 func (gs *GatewayServer) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
@@ -1250,30 +2161,44 @@ func (gs *GatewayServer) compressionMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Check if client accepts gzip
-		if !gs.clientAcceptsGzip(r) {
+		// Pick the best algorithm the client accepts
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), gs.compressionAlgorithms())
+		if encoding == "" {
 			next.ServeHTTP(w, r)
 			return
 		}
 
 		// Create compressed response writer
-		compressedWriter := gs.newCompressedResponseWriter(w, r)
-		defer compressedWriter.Close()
-
-		// Log compression metrics
-		startTime := time.Now()
-		originalSize := compressedWriter.GetOriginalSize()
+		compressedWriter, err := gs.newCompressedResponseWriter(w, encoding)
+		if err != nil {
+			gs.logger.Warn("http_compression_setup_failed",
+				"encoding", encoding,
+				"error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
 
 		// Serve the request with compression
+		startTime := time.Now()
 		next.ServeHTTP(compressedWriter, r)
+		if err := compressedWriter.Close(); err != nil {
+			gs.logger.Warn("http_compression_close_failed",
+				"encoding", encoding,
+				"error", err)
+		}
 
-		// Record compression metrics
+		// Record compression metrics once the encoder has flushed everything
+		originalSize := compressedWriter.GetOriginalSize()
 		compressedSize := compressedWriter.GetCompressedSize()
-		compressionRatio := float64(originalSize-compressedSize) / float64(originalSize) * 100
 		duration := time.Since(startTime)
+		if originalSize == 0 {
+			return
+		}
+		compressionRatio := float64(originalSize-compressedSize) / float64(originalSize) * 100
 
 		gs.logger.Debug("http_compression_applied",
 			"path", r.URL.Path,
+			"encoding", encoding,
 			"original_size", originalSize,
 			"compressed_size", compressedSize,
 			"compression_ratio_percent", compressionRatio,
@@ -1281,7 +2206,7 @@ func (gs *GatewayServer) compressionMiddleware(next http.Handler) http.Handler {
 
 		// Record metrics
 		gs.metrics.Observe("http_compression_ratio_percent", compressionRatio,
-			"path", r.URL.Path, "method", r.Method)
+			"path", r.URL.Path, "method", r.Method, "encoding", encoding)
 		gs.metrics.Observe("http_compression_duration_ms", float64(duration.Milliseconds()),
 			"path", r.URL.Path)
 		gs.metrics.Add("http_compression_bytes_saved", float64(originalSize-compressedSize),
@@ -1327,7 +2252,7 @@ func (gs *GatewayServer) rateLimitMiddleware(next http.Handler) http.Handler {
 				"method", r.Method)
 
 			// Set rate limit headers
-			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", gs.rateLimiter.GetLimit()))
+			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", gs.rateLimiter.GetClientLimit(clientID)))
 			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", resetTime.Unix()))
 			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(time.Until(resetTime).Seconds())))
 
@@ -1347,8 +2272,10 @@ func (gs *GatewayServer) rateLimitMiddleware(next http.Handler) http.Handler {
 		}
 
 		// Request allowed - set rate limit headers for transparency
-		w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", gs.rateLimiter.GetLimit()))
-		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", resetTime.Unix()))
+		if limit := gs.rateLimiter.GetClientLimit(clientID); limit != RateLimitUnlimited {
+			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", resetTime.Unix()))
+		}
 
 		// Record successful rate limit check
 		gs.metrics.Inc("rate_limit_allowed_total",
@@ -1360,27 +2287,125 @@ func (gs *GatewayServer) rateLimitMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// requestTimeoutMiddleware bounds total handler time, answering with a JSON-RPC
+// timeout error when exceeded. Health and metrics endpoints are exempt so probes
+// keep working while the gateway is under load.
+func (gs *GatewayServer) requestTimeoutMiddleware(next http.Handler) http.Handler {
+	timeoutBody, _ := json.Marshal(types.Response{
+		JSONRPC: "2.0",
+		Error: &types.Error{
+			Code:    types.ErrorCodeRequestTimeout,
+			Message: fmt.Sprintf("Request exceeded timeout of %s", gs.config.RequestTimeout),
+		},
+	})
+	timeoutHandler := http.TimeoutHandler(next, gs.config.RequestTimeout, string(timeoutBody))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Streams are long-lived by design and need direct access to Flush;
+		// profiles run for as long as the caller asks
+		if r.URL.Path == "/metrics" || r.URL.Path == "/health" || strings.HasPrefix(r.URL.Path, "/health/") ||
+			r.URL.Path == NotificationStreamPath || strings.HasPrefix(r.URL.Path, "/admin/debug/pprof/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// TimeoutHandler copies the handler's own headers on success, so this
+		// only remains in effect for the timeout response
+		w.Header().Set("Content-Type", "application/json")
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		timeoutHandler.ServeHTTP(recorder, r)
+
+		if recorder.status == http.StatusServiceUnavailable && time.Since(start) >= gs.config.RequestTimeout {
+			gs.metrics.Inc("http_request_timeouts_total", "method", r.Method, "path", r.URL.Path)
+			gs.logger.Warn("http_request_timeout",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"request_id", r.Header.Get(gs.config.RequestIDHeader),
+				"timeout", gs.config.RequestTimeout)
+		}
+	})
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	sr.status = code
+	sr.ResponseWriter.WriteHeader(code)
+}
+
 func (gs *GatewayServer) metricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
 		next.ServeHTTP(w, r)
 
-		duration := time.Since(start)
-		gs.metrics.Observe("http_request_duration_seconds", duration.Seconds(),
-			"method", r.Method,
-			"path", r.URL.Path)
+		duration := time.Since(start).Seconds()
+		path := metricsRouteLabel(r)
+		if histograms, ok := gs.metrics.(metrics.HistogramObserver); ok {
+			histograms.ObserveWithExemplar("http_request_duration_seconds", duration,
+				requestExemplar(r, duration),
+				"method", r.Method,
+				"path", path)
+		} else {
+			gs.metrics.Observe("http_request_duration_seconds", duration,
+				"method", r.Method,
+				"path", path)
+		}
 		gs.metrics.Inc("http_requests_total",
 			"method", r.Method,
-			"path", r.URL.Path)
+			"path", path)
+	})
+}
+
+// metricsRouteLabel returns the matched route template, such as
+// /admin/services/{id}, so IDs in URLs do not create a series per request.
+// Requests without a templated route are bucketed as "other".
+func metricsRouteLabel(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "other"
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil || template == "" {
+		return "other"
+	}
+	return template
+}
+
+// requestIDMiddleware assigns a request ID to every request, preserving a valid
+// client-supplied ID, and echoes it back in the response headers
+func (gs *GatewayServer) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(gs.config.RequestIDHeader)
+		if !router.IsValidRequestID(requestID) {
+			requestID = router.GenerateRequestID(gs.config.RequestIDFormat)
+			r.Header.Set(gs.config.RequestIDHeader, requestID)
+		}
+
+		w.Header().Set(gs.config.RequestIDHeader, requestID)
+		next.ServeHTTP(w, r)
 	})
 }
 
 func (gs *GatewayServer) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if trace := traceFromContext(r.Context()); trace != nil && trace.Sampled {
+			gs.serveTraced(w, r, next, trace)
+			return
+		}
+
 		start := time.Now()
+		requestID := r.Header.Get(gs.config.RequestIDHeader)
 
 		gs.logger.Debug("http_request_started",
+			"request_id", requestID,
 			"method", r.Method,
 			"path", r.URL.Path,
 			"remote_addr", r.RemoteAddr,
@@ -1390,6 +2415,7 @@ func (gs *GatewayServer) loggingMiddleware(next http.Handler) http.Handler {
 
 		duration := time.Since(start)
 		gs.logger.Info("http_request_completed",
+			"request_id", requestID,
 			"method", r.Method,
 			"path", r.URL.Path,
 			"duration", duration)
@@ -1400,13 +2426,26 @@ func (gs *GatewayServer) recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
+				stack := debug.Stack()
+				route := metricsRouteLabel(r)
+
 				gs.logger.Error("panic_recovered",
+					"request_id", r.Header.Get(gs.config.RequestIDHeader),
 					"error", err,
 					"method", r.Method,
-					"path", r.URL.Path)
+					"path", r.URL.Path,
+					"route", route,
+					"stack_trace", string(stack))
+				gs.metrics.Inc("http_panics_total", "method", r.Method, "path", route)
 
 				w.WriteHeader(http.StatusInternalServerError)
 				fmt.Fprintf(w, "Internal server error")
+				if gs.config.ExposePanicTraces {
+					if len(stack) > maxExposedPanicTrace {
+						stack = append(stack[:maxExposedPanicTrace:maxExposedPanicTrace], "\n... (truncated)"...)
+					}
+					fmt.Fprintf(w, ": %v\n\n%s", err, stack)
+				}
 			}
 		}()
 
@@ -1416,13 +2455,22 @@ func (gs *GatewayServer) recoveryMiddleware(next http.Handler) http.Handler {
 
 // Helper methods
 
+// writeJSONResponse writes data as JSON, or in the format negotiated for an
+// admin request
 func (gs *GatewayServer) writeJSONResponse(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		gs.logger.Error("json_encoding_failed", "error", err)
+	format := ResponseFormatJSON
+	if negotiated, ok := w.(*negotiatedResponseWriter); ok {
+		format = negotiated.format
+	}
+
+	body, err := encodeResponse(format, data)
+	if err != nil {
+		gs.logger.Error("json_encoding_failed", "format", format, "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("Content-Type", responseFormatTypes[format][0])
+	w.Write(body)
 }
 
 func (gs *GatewayServer) getPID() int {
@@ -1437,6 +2485,9 @@ func defaultServerConfig() ServerConfig {
 		WriteTimeout:          30 * time.Second,
 		IdleTimeout:           60 * time.Second,
 		ShutdownTimeout:       30 * time.Second,
+		RequestTimeout:        25 * time.Second,
+		ReadHeaderTimeout:     defaultReadHeaderTimeout,
+		MaxHeaderBytes:        defaultMaxHeaderBytes,
 		TLSEnabled:            false,
 		CORSEnabled:           true,
 		CORSAllowOrigins:      []string{"*"},
@@ -1452,31 +2503,45 @@ func defaultServerConfig() ServerConfig {
 		// Admin API authentication (empty by default for backward compatibility)
 		AdminAPIKey:    "", // Must be set explicitly for security
 		AdminAPIHeader: "X-Admin-API-Key",
+
+		// Request identification
+		RequestIDHeader: "X-Request-ID",
+		RequestIDFormat: "uuid",
 	}
 }
 
-// CompressedResponseWriter wraps http.ResponseWriter to provide gzip compression
+// CompressedResponseWriter wraps http.ResponseWriter to compress the response
+// body with the negotiated algorithm
 type CompressedResponseWriter struct {
 	http.ResponseWriter
-	gzipWriter    *gzip.Writer
+	encoding      string
+	encoder       io.WriteCloser
+	counter       *countingWriter
 	originalSize  int64
 	headerWritten bool
 	mutex         sync.Mutex
 }
 
 // newCompressedResponseWriter creates a new compressed response writer
-func (gs *GatewayServer) newCompressedResponseWriter(w http.ResponseWriter, r *http.Request) *CompressedResponseWriter {
-	gzWriter := gzip.NewWriter(w)
+func (gs *GatewayServer) newCompressedResponseWriter(w http.ResponseWriter, encoding string) (*CompressedResponseWriter, error) {
+	counter := &countingWriter{w: w}
+	encoder, err := newEncoder(encoding, gs.config.CompressionLevel, counter)
+	if err != nil {
+		return nil, err
+	}
+
 	crw := &CompressedResponseWriter{
 		ResponseWriter: w,
-		gzipWriter:     gzWriter,
+		encoding:       encoding,
+		encoder:        encoder,
+		counter:        counter,
 	}
 
 	// Set compression headers
-	crw.Header().Set("Content-Encoding", "gzip")
-	crw.Header().Set("Vary", "Accept-Encoding")
+	crw.Header().Set("Content-Encoding", encoding)
+	crw.Header().Add("Vary", "Accept-Encoding")
 
-	return crw
+	return crw, nil
 }
 
 // Write compresses and writes data
@@ -1485,11 +2550,11 @@ func (crw *CompressedResponseWriter) Write(data []byte) (int, error) {
 	defer crw.mutex.Unlock()
 
 	if !crw.headerWritten {
-		crw.WriteHeader(http.StatusOK)
+		crw.writeHeaderLocked(http.StatusOK)
 	}
 
 	crw.originalSize += int64(len(data))
-	return crw.gzipWriter.Write(data)
+	return crw.encoder.Write(data)
 }
 
 // WriteHeader writes the status code
@@ -1498,14 +2563,32 @@ func (crw *CompressedResponseWriter) WriteHeader(statusCode int) {
 	defer crw.mutex.Unlock()
 
 	if !crw.headerWritten {
-		crw.headerWritten = true
-		crw.ResponseWriter.WriteHeader(statusCode)
+		crw.writeHeaderLocked(statusCode)
 	}
 }
 
-// Close closes the gzip writer
+func (crw *CompressedResponseWriter) writeHeaderLocked(statusCode int) {
+	crw.headerWritten = true
+	// Any length set by the handler describes the uncompressed body
+	crw.Header().Del("Content-Length")
+	crw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap returns the underlying writer so http.ResponseController can reach it
+func (crw *CompressedResponseWriter) Unwrap() http.ResponseWriter {
+	return crw.ResponseWriter
+}
+
+// Close flushes and closes the encoder
 func (crw *CompressedResponseWriter) Close() error {
-	return crw.gzipWriter.Close()
+	crw.mutex.Lock()
+	defer crw.mutex.Unlock()
+	return crw.encoder.Close()
+}
+
+// GetEncoding returns the Content-Encoding applied to the response
+func (crw *CompressedResponseWriter) GetEncoding() string {
+	return crw.encoding
 }
 
 // GetOriginalSize returns the uncompressed size
@@ -1515,11 +2598,12 @@ func (crw *CompressedResponseWriter) GetOriginalSize() int64 {
 	return crw.originalSize
 }
 
-// GetCompressedSize returns the compressed size
+// GetCompressedSize returns the number of compressed bytes written to the
+// client so far; it is only complete after Close
 func (crw *CompressedResponseWriter) GetCompressedSize() int64 {
-	// This would require tracking bytes written to the underlying writer
-	// For now, we estimate based on gzip compression ratio (typically 70-80%)
-	return int64(float64(crw.originalSize) * 0.25) // Assume 75% compression
+	crw.mutex.Lock()
+	defer crw.mutex.Unlock()
+	return crw.counter.count
 }
 
 // Helper functions for compression middleware
@@ -1530,6 +2614,7 @@ func (gs *GatewayServer) shouldSkipCompression(r *http.Request) bool {
 	path := r.URL.Path
 	if strings.HasPrefix(path, "/metrics") ||
 		strings.HasPrefix(path, "/health") ||
+		path == NotificationStreamPath ||
 		strings.HasSuffix(path, ".jpg") ||
 		strings.HasSuffix(path, ".png") ||
 		strings.HasSuffix(path, ".gif") ||
@@ -1549,16 +2634,11 @@ func (gs *GatewayServer) shouldSkipCompression(r *http.Request) bool {
 	return false
 }
 
-// clientAcceptsGzip checks if the client accepts gzip encoding
-func (gs *GatewayServer) clientAcceptsGzip(r *http.Request) bool {
-	acceptEncoding := r.Header.Get("Accept-Encoding")
-	return strings.Contains(strings.ToLower(acceptEncoding), "gzip")
-}
-
 // RateLimiter interface for rate limiting
 type RateLimiter interface {
 	IsAllowed(clientID string, r *http.Request) (allowed bool, resetTime time.Time, err error)
 	GetLimit() int
+	GetClientLimit(clientID string) int
 }
 
 // SimpleRateLimiter implements a basic in-memory rate limiter
@@ -1566,9 +2646,17 @@ type SimpleRateLimiter struct {
 	limit      int
 	windowSize time.Duration
 	clients    map[string]*ClientRateInfo
+	overrides  []rateLimitOverride
 	mutex      sync.RWMutex
 	logger     logging.Logger
 	metrics    metrics.Metrics
+
+	// Idle client eviction and the state behind the client gauges
+	clientTTL      time.Duration
+	clientIDBytes  int64
+	evictedClients uint64
+	stop           chan struct{}
+	stopOnce       sync.Once
 }
 
 // ClientRateInfo tracks rate limiting info for a client
@@ -1576,6 +2664,7 @@ type ClientRateInfo struct {
 	requestCount int
 	windowStart  time.Time
 	lastRequest  time.Time
+	evicted      bool
 	mutex        sync.Mutex
 }
 
@@ -1586,34 +2675,71 @@ func (gs *GatewayServer) newRateLimiter() RateLimiter {
 		limit = 100 // Default to 100 requests per second
 	}
 
-	return &SimpleRateLimiter{
+	clientTTL := gs.config.RateLimitClientTTL
+	if clientTTL <= 0 {
+		clientTTL = defaultRateLimitClientTTL
+	}
+
+	limiter := &SimpleRateLimiter{
 		limit:      limit,
 		windowSize: time.Second,
 		clients:    make(map[string]*ClientRateInfo),
 		logger:     gs.logger.WithComponent("rate_limiter"),
 		metrics:    gs.metrics,
+		clientTTL:  clientTTL,
+		stop:       make(chan struct{}),
+	}
+	limiter.startEviction()
+
+	for key, rps := range gs.config.RateLimitOverrides {
+		if err := limiter.SetOverride(key, rps); err != nil {
+			gs.logger.Warn("rate_limit_override_invalid", "key", key, "error", err)
+		}
 	}
+
+	return limiter
 }
 
 // IsAllowed checks if a request is allowed under the rate limit
 func (srl *SimpleRateLimiter) IsAllowed(clientID string, r *http.Request) (bool, time.Time, error) {
 	now := time.Now()
 
-	srl.mutex.Lock()
-	clientInfo, exists := srl.clients[clientID]
-	if !exists {
-		clientInfo = &ClientRateInfo{
-			requestCount: 0,
-			windowStart:  now,
-			lastRequest:  now,
+	var limit int
+	var clientInfo *ClientRateInfo
+	for {
+		srl.mutex.Lock()
+		limit = srl.limitFor(clientID)
+		if limit == RateLimitUnlimited {
+			srl.mutex.Unlock()
+			return true, now.Add(srl.windowSize), nil
 		}
-		srl.clients[clientID] = clientInfo
-	}
-	srl.mutex.Unlock()
+		var exists bool
+		clientInfo, exists = srl.clients[clientID]
+		if !exists {
+			clientInfo = &ClientRateInfo{
+				requestCount: 0,
+				windowStart:  now,
+				lastRequest:  now,
+			}
+			srl.clients[clientID] = clientInfo
+			srl.clientIDBytes += int64(len(clientID))
+			srl.recordClientGaugesLocked()
+		}
+		srl.mutex.Unlock()
 
-	clientInfo.mutex.Lock()
+		clientInfo.mutex.Lock()
+		if !clientInfo.evicted {
+			break
+		}
+		// Evicted between lookup and lock; start over with a fresh entry
+		clientInfo.mutex.Unlock()
+	}
 	defer clientInfo.mutex.Unlock()
 
+	// Rejected requests also count as activity so throttled clients are not
+	// evicted and handed a fresh window
+	clientInfo.lastRequest = now
+
 	// Reset window if expired
 	if now.Sub(clientInfo.windowStart) >= srl.windowSize {
 		clientInfo.requestCount = 0
@@ -1621,14 +2747,13 @@ func (srl *SimpleRateLimiter) IsAllowed(clientID string, r *http.Request) (bool,
 	}
 
 	// Check if limit exceeded
-	if clientInfo.requestCount >= srl.limit {
+	if clientInfo.requestCount >= limit {
 		resetTime := clientInfo.windowStart.Add(srl.windowSize)
 		return false, resetTime, nil
 	}
 
 	// Allow request and increment counter
 	clientInfo.requestCount++
-	clientInfo.lastRequest = now
 
 	// Record rate limiting metrics
 	srl.metrics.Observe("rate_limit_current_requests", float64(clientInfo.requestCount),
@@ -1649,29 +2774,7 @@ func (srl *SimpleRateLimiter) GetLimit() int {
 
 // getClientIdentifier extracts a client identifier for rate limiting
 func (gs *GatewayServer) getClientIdentifier(r *http.Request) string {
-	// Try to get IP from X-Forwarded-For header first (for load balancers)
-	xff := r.Header.Get("X-Forwarded-For")
-	if xff != "" {
-		// Take the first IP from the comma-separated list
-		parts := strings.Split(xff, ",")
-		if len(parts) > 0 {
-			return strings.TrimSpace(parts[0])
-		}
-	}
-
-	// Try X-Real-IP header
-	xri := r.Header.Get("X-Real-IP")
-	if xri != "" {
-		return xri
-	}
-
-	// Fall back to remote address
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
-
-	return host
+	return gs.clientIdentity.identify(r)
 }
 
 // Additional admin API endpoints
@@ -1710,6 +2813,7 @@ func (gs *GatewayServer) handleServiceHealth(w http.ResponseWriter, r *http.Requ
 			"success_requests": stats.SuccessRequests,
 			"failed_requests":  stats.FailedRequests,
 			"circuit_open":     stats.CircuitOpen,
+			"circuit_state":    stats.CircuitState(),
 			"last_used":        stats.LastUsed.Format(time.RFC3339),
 		}
 	}
@@ -1834,27 +2938,63 @@ func (gs *GatewayServer) handleServiceLoadBalancerStats(w http.ResponseWriter, r
 }
 
 func (gs *GatewayServer) handleLoadBalancerStrategies(w http.ResponseWriter, r *http.Request) {
+	lb := gs.registry.GetLoadBalancer()
 	strategies := map[string]interface{}{
-		"available_strategies": []string{
-			"round_robin",
-			"least_connections",
-			"weighted",
-			"hash",
-			"random",
-		},
-		"current_strategy": "round_robin", // Default strategy
+		"available_strategies": registry.SupportedStrategies,
+		"current_strategy":     lb.GetStrategy(),
+		"type_strategies":      lb.GetTypeStrategies(),
 		"descriptions": map[string]string{
 			"round_robin":       "Distributes requests evenly across all healthy services",
 			"least_connections": "Routes to the service with the fewest active connections",
 			"weighted":          "Routes based on service weights",
 			"hash":              "Consistent hash-based routing for session affinity",
 			"random":            "Random service selection",
+			"load_aware":        "Routes to the service with the lowest latency average scaled by its active requests",
 		},
 	}
 
 	gs.writeJSONResponse(w, strategies)
 }
 
+func (gs *GatewayServer) handleSetLoadBalancerStrategy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Strategy    string `json:"strategy"`
+		ServiceType string `json:"service_type,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_request_body",
+			"message": "Failed to parse JSON request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	lb := gs.registry.GetLoadBalancer()
+	if err := lb.SetStrategy(req.ServiceType, req.Strategy); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":                "invalid_strategy",
+			"message":              err.Error(),
+			"available_strategies": registry.SupportedStrategies,
+		})
+		return
+	}
+
+	gs.logger.Info("admin_load_balancer_strategy_updated",
+		"strategy", req.Strategy,
+		"service_type", req.ServiceType,
+		"remote_addr", r.RemoteAddr)
+	gs.metrics.Inc("admin_api_strategy_updates_total", "strategy", req.Strategy)
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"service_type":       req.ServiceType,
+		"effective_strategy": lb.GetEffectiveStrategy(req.ServiceType),
+		"default_strategy":   lb.GetStrategy(),
+	})
+}
+
 func (gs *GatewayServer) handleConfigReload(w http.ResponseWriter, r *http.Request) {
 	gs.logger.Info("admin_config_reload_requested",
 		"remote_addr", r.RemoteAddr)
@@ -1964,7 +3104,7 @@ func (gs *GatewayServer) handleAPIDocumentation(w http.ResponseWriter, r *http.R
 			"base_path": "/admin",
 			"endpoints": map[string]interface{}{
 				"services": map[string]interface{}{
-					"GET /services":                   "List all registered services",
+					"GET /services":                   "List registered services; filter by type, health, status, tag, name with sort, order, limit, offset",
 					"POST /services":                  "Register a new service",
 					"GET /services/{id}":              "Get service details",
 					"DELETE /services/{id}":           "Unregister a service",
@@ -1978,16 +3118,34 @@ func (gs *GatewayServer) handleAPIDocumentation(w http.ResponseWriter, r *http.R
 					"GET /discovery/status":   "Get discovery status and statistics",
 				},
 				"loadbalancer": map[string]interface{}{
-					"GET /loadbalancer/stats":               "Get load balancer statistics for all services",
-					"GET /loadbalancer/stats/{service_id}":  "Get load balancer statistics for specific service",
-					"POST /loadbalancer/reset/{service_id}": "Reset circuit breaker for service",
-					"GET /loadbalancer/strategies":          "List available load balancing strategies",
+					"GET /loadbalancer/stats":                    "Get load balancer statistics for all services",
+					"GET /loadbalancer/stats/{service_id}":       "Get load balancer statistics for specific service",
+					"POST /loadbalancer/reset/{service_id}":      "Reset circuit breaker for service",
+					"GET /loadbalancer/strategies":               "List available load balancing strategies",
+					"PUT /loadbalancer/strategy":                 "Change the load balancing strategy, optionally for one service type",
+					"GET /loadbalancer/canary":                   "List canary traffic splits for all service types",
+					"GET /loadbalancer/canary/{service_type}":    "Get canary traffic split for service type",
+					"PUT /loadbalancer/canary/{service_type}":    "Set canary traffic percentages per service version",
+					"DELETE /loadbalancer/canary/{service_type}": "Remove canary traffic split for service type",
 				},
 				"config": map[string]interface{}{
 					"GET /config":         "Get current configuration",
 					"PUT /config":         "Update configuration",
 					"POST /config/reload": "Reload configuration from file",
 				},
+				"ratelimit": map[string]interface{}{
+					"GET /ratelimit/overrides":                 "List per-client rate limit overrides",
+					"PUT /ratelimit/overrides":                 "Set rate limit override for a client ID, CIDR or prefix",
+					"DELETE /ratelimit/overrides?client={key}": "Remove rate limit override",
+				},
+				"policy": map[string]interface{}{
+					"GET /policy/capabilities": "Get gateway tool and resource allowlist/denylist",
+					"PUT /policy/capabilities": "Replace gateway tool and resource allowlist/denylist",
+				},
+				"maintenance": map[string]interface{}{
+					"GET /maintenance": "Get maintenance mode status",
+					"PUT /maintenance": "Enable or disable maintenance mode, which rejects MCP traffic with 503",
+				},
 				"plugins": map[string]interface{}{
 					"GET /plugins":                  "List all plugins",
 					"GET /plugins/{name}":           "Get plugin information",
@@ -1996,6 +3154,7 @@ func (gs *GatewayServer) handleAPIDocumentation(w http.ResponseWriter, r *http.R
 					"GET /plugins/{name}/tools":     "Get plugin tools",
 					"GET /plugins/{name}/resources": "Get plugin resources",
 					"GET /plugins/{name}/health":    "Get plugin health status",
+					"POST /plugins/{name}/reload":   "Reload a single plugin",
 					"GET /plugins/health":           "Get all plugin health status",
 					"GET /plugins/metrics":          "Get plugin metrics",
 					"GET /plugins/capabilities":     "Get plugin capabilities summary",
@@ -2004,26 +3163,36 @@ func (gs *GatewayServer) handleAPIDocumentation(w http.ResponseWriter, r *http.R
 					"GET /info":             "Get system information",
 					"GET /stats":            "Get system statistics",
 					"GET /debug/goroutines": "Get goroutine and memory statistics",
+					"GET /debug/requests":   "Recent MCP requests, filterable by status, method and limit (when request history is enabled)",
+					"GET /debug/pprof/":     "pprof profiles: heap, goroutine, profile (CPU), block (when profiling is enabled)",
+					"GET /metrics/snapshot": "All recorded metric series with their statistics as JSON",
+					"POST /metrics/reset":   "Clear counters and histograms, keeping gauges (development mode only)",
 					"GET /api":              "Get API documentation (this endpoint)",
 				},
 			},
 		},
 		"health_endpoints": map[string]interface{}{
-			"GET /health":       "General health check",
-			"GET /health/live":  "Liveness probe",
-			"GET /health/ready": "Readiness probe",
+			"GET /health":          "General health check",
+			"GET /health/live":     "Liveness probe",
+			"GET /health/ready":    "Readiness probe",
+			"GET /health/detailed": "Per-component diagnostics (admin only)",
 		},
 		"metrics": map[string]interface{}{
 			"GET /metrics": "Prometheus metrics endpoint",
 		},
+		"registration_endpoints": map[string]interface{}{
+			"POST /register": "Self-register a service authenticated by client certificate or service token (when enabled)",
+		},
 		"mcp_endpoints": map[string]interface{}{
-			"POST /mcp":                "Main MCP JSON-RPC endpoint",
-			"POST /mcp/tools/list":     "List available tools",
-			"POST /mcp/tools/call":     "Call a specific tool",
-			"POST /mcp/resources/list": "List available resources",
-			"POST /mcp/resources/read": "Read a specific resource",
-			"POST /mcp/prompts/list":   "List available prompts",
-			"POST /mcp/prompts/get":    "Get a specific prompt",
+			"POST /mcp":                            "Main MCP JSON-RPC endpoint",
+			"POST /mcp/tools/list":                 "List available tools",
+			"POST /mcp/tools/call":                 "Call a specific tool",
+			"POST /mcp/resources/list":             "List available resources",
+			"POST /mcp/resources/read":             "Read a specific resource",
+			"GET /mcp/resources/content?uri={uri}": "Serve resource content with its own Content-Type",
+			"POST /mcp/prompts/list":               "List available prompts",
+			"POST /mcp/prompts/get":                "Get a specific prompt",
+			"GET /api/openapi.json":                "OpenAPI 3.1 document with one operation per registered tool",
 		},
 		"version":   gs.version,
 		"timestamp": time.Now().Format(time.RFC3339),
@@ -2095,6 +3264,20 @@ func (gs *GatewayServer) handleUpdatePluginConfig(w http.ResponseWriter, r *http
 		"plugin_name", pluginName,
 		"remote_addr", r.RemoteAddr)
 
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			gs.writeJSONResponse(w, map[string]interface{}{
+				"error":   "invalid_query",
+				"message": fmt.Sprintf("dry_run must be true or false, got %q", raw),
+			})
+			return
+		}
+		dryRun = value
+	}
+
 	var configUpdate map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&configUpdate); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -2105,8 +3288,44 @@ func (gs *GatewayServer) handleUpdatePluginConfig(w http.ResponseWriter, r *http
 		return
 	}
 
+	// A dry run validates the configuration and reports what it would change
+	// without applying it
+	if dryRun {
+		preview, err := gs.pluginIntegration.PreviewPluginConfiguration(pluginName, configUpdate)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			gs.writeJSONResponse(w, map[string]interface{}{
+				"error":   "plugin_not_found",
+				"message": fmt.Sprintf("Plugin not found: %s", pluginName),
+			})
+			return
+		}
+
+		gs.metrics.Inc("admin_api_plugin_config_dry_run_requests_total", "plugin", pluginName)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"dry_run":   true,
+			"plugin":    preview.Plugin,
+			"valid":     preview.Valid,
+			"validated": preview.Validated,
+			"errors":    preview.Errors,
+			"changes":   preview.Changes,
+		})
+		return
+	}
+
 	ctx := r.Context()
 	err := gs.pluginIntegration.UpdatePluginConfiguration(ctx, pluginName, configUpdate)
+	var invalid *plugins.InvalidConfigError
+	if errors.As(err, &invalid) {
+		gs.metrics.Inc("admin_api_plugin_config_rejections_total", "plugin", pluginName)
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_plugin_config",
+			"message": invalid.Error(),
+			"errors":  invalid.Errors,
+		})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		gs.writeJSONResponse(w, map[string]interface{}{
@@ -2116,6 +3335,9 @@ func (gs *GatewayServer) handleUpdatePluginConfig(w http.ResponseWriter, r *http
 		return
 	}
 
+	// A new configuration may change the tools, resources or prompts offered
+	gs.detectCapabilityChanges(pluginName)
+
 	gs.metrics.Inc("admin_api_plugin_config_update_requests_total", "plugin", pluginName)
 	gs.writeJSONResponse(w, map[string]interface{}{
 		"status":  "success",
@@ -2131,7 +3353,7 @@ func (gs *GatewayServer) handleGetPluginTools(w http.ResponseWriter, r *http.Req
 		"plugin_name", pluginName,
 		"remote_addr", r.RemoteAddr)
 
-	pluginInfo, err := gs.pluginIntegration.GetPluginInfo(pluginName)
+	tools, err := gs.refreshToolSchemas(pluginName)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
 		gs.writeJSONResponse(w, map[string]interface{}{
@@ -2141,9 +3363,11 @@ func (gs *GatewayServer) handleGetPluginTools(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	described, withoutSchema := describeToolSchemas(tools)
 	response := map[string]interface{}{
-		"plugin": pluginName,
-		"tools":  pluginInfo["tools"],
+		"plugin":               pluginName,
+		"tools":                described,
+		"tools_without_schema": withoutSchema,
 	}
 
 	gs.metrics.Inc("admin_api_plugin_tools_requests_total", "plugin", pluginName)
@@ -2216,6 +3440,84 @@ func (gs *GatewayServer) handleGetPluginHealth(w http.ResponseWriter, r *http.Re
 	gs.writeJSONResponse(w, response)
 }
 
+// handleReloadPlugin reloads a single plugin and re-runs discovery and validation for it
+func (gs *GatewayServer) handleReloadPlugin(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pluginName := vars["name"]
+
+	gs.logger.Info("admin_reload_plugin_request",
+		"plugin_name", pluginName,
+		"remote_addr", r.RemoteAddr)
+
+	if _, exists := gs.pluginIntegration.GetPluginManager().GetPlugin(pluginName); !exists {
+		w.WriteHeader(http.StatusNotFound)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "plugin_not_found",
+			"message": fmt.Sprintf("Plugin not found: %s", pluginName),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), gs.config.ShutdownTimeout)
+	defer cancel()
+
+	pluginInfo, err := gs.pluginIntegration.ReloadPlugin(ctx, pluginName)
+	if err != nil {
+		gs.metrics.Inc("admin_api_plugin_reload_failures_total", "plugin", pluginName)
+		w.WriteHeader(http.StatusInternalServerError)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "plugin_reload_failed",
+			"message": fmt.Sprintf("Reload failed, previous instance still serving: %v", err),
+		})
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":       "success",
+		"plugin":       pluginName,
+		"version":      pluginInfo["version"],
+		"capabilities": pluginInfo["capabilities"],
+	}
+
+	// The new instance may publish different tool schemas
+	if _, err := gs.refreshToolSchemas(pluginName); err != nil {
+		gs.logger.Warn("tool_schema_refresh_failed",
+			"plugin", pluginName,
+			"error", err)
+	}
+	if gs.validator != nil {
+		gs.validator.InvalidateCache("plugin_reloaded")
+	}
+
+	// Refresh discovery and validation results for the new instance
+	if gs.discoveryEngine != nil {
+		result, err := gs.discoveryEngine.DiscoverPlugin(ctx, pluginName)
+		if err != nil {
+			gs.logger.Warn("plugin_rediscovery_failed",
+				"plugin", pluginName,
+				"error", err)
+			response["discovery"] = map[string]interface{}{"error": err.Error()}
+		} else {
+			validationsPassed := 0
+			for _, capability := range result.Capabilities {
+				validation, err := gs.validationEngine.ValidateCapability(ctx, pluginName, capability.CapabilityName)
+				if err == nil && validation.Status == capabilities.StatusPassed {
+					validationsPassed++
+				}
+			}
+			response["discovery"] = map[string]interface{}{
+				"capabilities":       len(result.Capabilities),
+				"dependencies":       len(result.Dependencies),
+				"conflicts":          len(result.Conflicts),
+				"validations_passed": validationsPassed,
+			}
+		}
+	}
+
+	gs.metrics.Inc("admin_api_plugin_reload_requests_total", "plugin", pluginName)
+	gs.writeJSONResponse(w, response)
+}
+
 func (gs *GatewayServer) handleGetAllPluginHealth(w http.ResponseWriter, r *http.Request) {
 	gs.logger.Debug("admin_get_all_plugin_health_request", "remote_addr", r.RemoteAddr)
 
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestHeaderLimits tests that header size and header read timeout limits are enforced
+func TestHeaderLimits(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	t.Run("defaults are applied", func(t *testing.T) {
+		server := NewGatewayServer(ServerConfig{}, logger, mockMetrics, validator, healthMgr)
+		defer server.registry.Shutdown()
+
+		if server.httpServer.ReadHeaderTimeout != defaultReadHeaderTimeout {
+			t.Errorf("expected default read header timeout %s, got %s", defaultReadHeaderTimeout, server.httpServer.ReadHeaderTimeout)
+		}
+		if server.httpServer.MaxHeaderBytes != defaultMaxHeaderBytes {
+			t.Errorf("expected default max header bytes %d, got %d", defaultMaxHeaderBytes, server.httpServer.MaxHeaderBytes)
+		}
+	})
+
+	config := ServerConfig{
+		EnableHealthEndpoints: true,
+		ReadHeaderTimeout:     100 * time.Millisecond,
+		MaxHeaderBytes:        1024,
+	}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go server.httpServer.Serve(listener)
+	defer server.httpServer.Close()
+	addr := listener.Addr().String()
+
+	t.Run("oversized headers are rejected", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "http://"+addr+"/health", nil)
+		req.Header.Set("X-Padding", strings.Repeat("a", 64*1024))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+			t.Errorf("expected status 431, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("normal headers are accepted", func(t *testing.T) {
+		resp, err := http.Get("http://" + addr + "/health")
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("slow headers are cut off", func(t *testing.T) {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+
+		// Send a partial request and never finish the headers
+		fmt.Fprintf(conn, "GET /health HTTP/1.1\r\nHost: %s\r\nX-Slow: ", addr)
+
+		start := time.Now()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = bufio.NewReader(conn).ReadByte()
+		elapsed := time.Since(start)
+
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			t.Fatal("expected server to close the connection, but it stayed open")
+		}
+		if elapsed > 2*time.Second {
+			t.Errorf("expected connection closed shortly after read header timeout, took %v", elapsed)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestDetailedHealthEndpoint tests the /health/detailed diagnostics endpoint
+func TestDetailedHealthEndpoint(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+
+	t.Run("reports unhealthy critical component", func(t *testing.T) {
+		healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+		defer healthMgr.Shutdown()
+		healthMgr.RegisterChecker(&staticHealthChecker{
+			name:     "database",
+			critical: true,
+			result: health.CheckResult{
+				Name:    "database",
+				Status:  health.StatusUnhealthy,
+				Message: "connection refused",
+				Error:   "dial tcp: connection refused",
+			},
+		})
+
+		config := ServerConfig{
+			EnableHealthEndpoints: true,
+			EnableAdminEndpoints:  true,
+		}
+		server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+
+		req := httptest.NewRequest("GET", "/health/detailed", nil)
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503 for unhealthy critical component, got %d", w.Code)
+		}
+
+		var report map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatalf("failed to decode report: %v", err)
+		}
+
+		if report["status"] != string(health.StatusUnhealthy) {
+			t.Errorf("expected overall status unhealthy, got %v", report["status"])
+		}
+
+		components, ok := report["components"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected components in report, got %v", report["components"])
+		}
+		database, ok := components["database"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected database component in report, got %v", components)
+		}
+		if database["status"] != string(health.StatusUnhealthy) {
+			t.Errorf("expected database status unhealthy, got %v", database["status"])
+		}
+		if database["error"] != "dial tcp: connection refused" {
+			t.Errorf("expected database error to be reported, got %v", database["error"])
+		}
+		if database["last_check"] == "" {
+			t.Error("expected database last check time to be reported")
+		}
+
+		for _, section := range []string{"services", "plugins"} {
+			if _, ok := report[section]; !ok {
+				t.Errorf("expected %s section in report", section)
+			}
+		}
+	})
+
+	t.Run("requires admin key when configured", func(t *testing.T) {
+		healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+		defer healthMgr.Shutdown()
+
+		config := ServerConfig{
+			EnableHealthEndpoints: true,
+			EnableAdminEndpoints:  true,
+			AdminAPIKey:           "test-secret-key",
+			AdminAPIHeader:        "X-Admin-API-Key",
+		}
+		server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+
+		req := httptest.NewRequest("GET", "/health/detailed", nil)
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401 without admin key, got %d", w.Code)
+		}
+
+		req = httptest.NewRequest("GET", "/health/detailed", nil)
+		req.Header.Set("X-Admin-API-Key", "test-secret-key")
+		w = httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+
+		if w.Code == http.StatusUnauthorized || w.Code == http.StatusNotFound {
+			t.Errorf("expected detailed report with admin key, got %d", w.Code)
+		}
+	})
+
+	t.Run("not exposed when admin endpoints disabled", func(t *testing.T) {
+		healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+		defer healthMgr.Shutdown()
+
+		config := ServerConfig{
+			EnableHealthEndpoints: true,
+		}
+		server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+
+		req := httptest.NewRequest("GET", "/health/detailed", nil)
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404 when admin endpoints disabled, got %d", w.Code)
+		}
+	})
+}
+
+// staticHealthChecker returns a fixed health check result
+type staticHealthChecker struct {
+	name     string
+	critical bool
+	result   health.CheckResult
+}
+
+func (c *staticHealthChecker) Check(ctx context.Context) health.CheckResult {
+	result := c.result
+	result.Critical = c.critical
+	return result
+}
+func (c *staticHealthChecker) Name() string            { return c.name }
+func (c *staticHealthChecker) IsCritical() bool        { return c.critical }
+func (c *staticHealthChecker) Interval() time.Duration { return time.Minute }
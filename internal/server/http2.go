@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// HTTP2Config enables HTTP/2 so clients can multiplex concurrent MCP requests
+// over one connection. TLS listeners negotiate h2 through ALPN; plaintext
+// listeners accept h2c only when Cleartext is set. Zero values fall back to
+// the defaults of golang.org/x/net/http2.
+type HTTP2Config struct {
+	Enabled              bool          `yaml:"enabled"`
+	Cleartext            bool          `yaml:"cleartext"`              // Accept h2c on plaintext listeners
+	MaxConcurrentStreams uint32        `yaml:"max_concurrent_streams"` // Streams per connection, 0 uses 250
+	MaxReadFrameSize     uint32        `yaml:"max_read_frame_size"`    // Largest frame accepted, 16KiB to 16MiB
+	ReadIdleTimeout      time.Duration `yaml:"read_idle_timeout"`      // Ping connections silent this long, 0 disables health checks
+	PingTimeout          time.Duration `yaml:"ping_timeout"`           // Close connections not answering a ping within this time
+}
+
+// KeepAliveConfig tunes how long idle client connections are kept open;
+// ServerConfig.IdleTimeout bounds the idle time of both protocols
+type KeepAliveConfig struct {
+	Disabled  bool          `yaml:"disabled"`   // Close HTTP/1.1 connections after each response
+	TCPPeriod time.Duration `yaml:"tcp_period"` // Interval between TCP keepalive probes, 0 uses the Go default and negative disables them
+}
+
+// Frame size bounds from RFC 7540 section 4.2
+const (
+	minHTTP2FrameSize = 1 << 14
+	maxHTTP2FrameSize = 1<<24 - 1
+)
+
+// Validate checks the HTTP/2 settings when HTTP/2 is enabled
+func (c HTTP2Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxReadFrameSize != 0 && (c.MaxReadFrameSize < minHTTP2FrameSize || c.MaxReadFrameSize > maxHTTP2FrameSize) {
+		return fmt.Errorf("max read frame size must be between %d and %d, got %d",
+			minHTTP2FrameSize, maxHTTP2FrameSize, c.MaxReadFrameSize)
+	}
+	if c.ReadIdleTimeout < 0 || c.PingTimeout < 0 {
+		return fmt.Errorf("http2 read idle and ping timeouts must not be negative")
+	}
+	return nil
+}
+
+// configureHTTP2 applies the keepalive settings and, when enabled, registers
+// HTTP/2 with the server. h2c wraps the handler, so this must run after the
+// server's handler and TLS config are in place.
+func (gs *GatewayServer) configureHTTP2() error {
+	gs.httpServer.SetKeepAlivesEnabled(!gs.config.KeepAlive.Disabled)
+
+	if !gs.config.HTTP2.Enabled {
+		return nil
+	}
+
+	h2Server := &http2.Server{
+		MaxConcurrentStreams: gs.config.HTTP2.MaxConcurrentStreams,
+		MaxReadFrameSize:     gs.config.HTTP2.MaxReadFrameSize,
+		IdleTimeout:          gs.config.IdleTimeout,
+		ReadIdleTimeout:      gs.config.HTTP2.ReadIdleTimeout,
+		PingTimeout:          gs.config.HTTP2.PingTimeout,
+	}
+	if err := http2.ConfigureServer(gs.httpServer, h2Server); err != nil {
+		return fmt.Errorf("failed to configure HTTP/2: %w", err)
+	}
+
+	if gs.config.HTTP2.Cleartext && !gs.config.TLSEnabled {
+		gs.httpServer.Handler = h2c.NewHandler(gs.httpServer.Handler, h2Server)
+	}
+
+	gs.logger.Info("http2_enabled",
+		"tls", gs.config.TLSEnabled,
+		"cleartext", gs.config.HTTP2.Cleartext && !gs.config.TLSEnabled,
+		"max_concurrent_streams", gs.config.HTTP2.MaxConcurrentStreams)
+	return nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/net/http2"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestHTTP2Multiplexing tests that an h2c client sends concurrent MCP requests over one connection
+func TestHTTP2Multiplexing(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		HTTP2: HTTP2Config{Enabled: true, Cleartext: true, MaxConcurrentStreams: 100},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go server.httpServer.Serve(listener)
+	defer server.httpServer.Close()
+
+	var dials int32
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}}
+
+	const requests = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			body, _ := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0", "id": id, "method": "initialize",
+				"params": map[string]interface{}{"protocolVersion": "2025-03-26"},
+			})
+			resp, err := client.Post("http://"+listener.Addr().String()+"/mcp", "application/json", bytes.NewReader(body))
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer resp.Body.Close()
+
+			var decoded struct {
+				ID     int                    `json:"id"`
+				Result map[string]interface{} `json:"result"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+				errs <- err
+				return
+			}
+			if resp.ProtoMajor != 2 || decoded.ID != id || decoded.Result == nil {
+				t.Errorf("expected an HTTP/2 result for request %d, got %s with %+v", id, resp.Proto, decoded)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("request failed: %v", err)
+	}
+
+	if n := atomic.LoadInt32(&dials); n != 1 {
+		t.Errorf("expected all requests to share one connection, got %d connections", n)
+	}
+}
+
+// TestHTTP2ConfigValidate tests HTTP/2 frame size validation
+func TestHTTP2ConfigValidate(t *testing.T) {
+	if err := (HTTP2Config{Enabled: true, MaxReadFrameSize: 1024}).Validate(); err == nil {
+		t.Error("expected a frame size below 16KiB to be rejected")
+	}
+	if err := (HTTP2Config{Enabled: true, MaxReadFrameSize: 1 << 20}).Validate(); err != nil {
+		t.Errorf("expected a 1MiB frame size to be accepted, got %v", err)
+	}
+}
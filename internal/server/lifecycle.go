@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lifecycle events, in the order a gateway run emits them
+const (
+	LifecycleStarting     = "starting"      // Process started
+	LifecycleConfigLoaded = "config_loaded" // Configuration loaded and validated
+	LifecyclePluginsReady = "plugins_ready" // Plugins initialized
+	LifecycleServing      = "serving"       // Listeners accepting requests
+	LifecycleDraining     = "draining"      // Shutdown started, in-flight requests draining
+	LifecycleStopped      = "stopped"       // Shutdown finished
+)
+
+const defaultLifecycleSinkTimeout = 5 * time.Second
+
+// LifecycleConfig configures where lifecycle events are delivered besides
+// the gateway log, in which every event is a line with a lifecycle_event
+// field. At most one of FilePath or Endpoint may be set.
+type LifecycleConfig struct {
+	FilePath string        `yaml:"file_path" json:"file_path"` // Events are appended as JSON lines
+	Endpoint string        `yaml:"endpoint" json:"endpoint"`   // Events are POSTed as JSON
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`     // Endpoint request timeout
+}
+
+// Validate checks that the config names at most one sink
+func (c LifecycleConfig) Validate() error {
+	if c.FilePath != "" && c.Endpoint != "" {
+		return fmt.Errorf("only one of file_path or endpoint may be set")
+	}
+	if c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "http://") && !strings.HasPrefix(c.Endpoint, "https://") {
+		return fmt.Errorf("endpoint must be an http or https URL, got %s", c.Endpoint)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// LifecycleEvent is a gateway state transition
+type LifecycleEvent struct {
+	Event     string                 `json:"event"`
+	Timestamp time.Time              `json:"timestamp"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// LifecycleSink receives lifecycle events as they are emitted
+type LifecycleSink interface {
+	Write(event LifecycleEvent) error
+}
+
+func newLifecycleSink(config LifecycleConfig) (LifecycleSink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case config.FilePath != "":
+		return &fileLifecycleSink{path: config.FilePath}, nil
+	case config.Endpoint != "":
+		timeout := config.Timeout
+		if timeout == 0 {
+			timeout = defaultLifecycleSinkTimeout
+		}
+		return &httpLifecycleSink{
+			endpoint: config.Endpoint,
+			client:   &http.Client{Timeout: timeout},
+		}, nil
+	}
+	return nil, nil
+}
+
+// fileLifecycleSink appends events to a file as JSON lines
+type fileLifecycleSink struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func (s *fileLifecycleSink) Write(event LifecycleEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lifecycle event: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lifecycle event file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write lifecycle event: %w", err)
+	}
+	return nil
+}
+
+// httpLifecycleSink POSTs each event to an endpoint
+type httpLifecycleSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (s *httpLifecycleSink) Write(event LifecycleEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lifecycle event: %w", err)
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send lifecycle event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("lifecycle endpoint returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SetLifecycleSink replaces the sink receiving lifecycle events; nil leaves
+// only the log lines
+func (gs *GatewayServer) SetLifecycleSink(sink LifecycleSink) {
+	gs.lifecycleMutex.Lock()
+	defer gs.lifecycleMutex.Unlock()
+	gs.lifecycleSink = sink
+}
+
+// EmitLifecycleEvent logs a lifecycle event and delivers it to the sink. A
+// zero timestamp is set to now, so events that happened before the sink was
+// configured, such as starting, can be emitted with their original time.
+func (gs *GatewayServer) EmitLifecycleEvent(event LifecycleEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	event.Timestamp = event.Timestamp.UTC()
+
+	fields := []interface{}{
+		"lifecycle_event", event.Event,
+		"timestamp", event.Timestamp.Format(time.RFC3339Nano),
+	}
+	keys := make([]string, 0, len(event.Details))
+	for key := range event.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, key, event.Details[key])
+	}
+	gs.logger.Info("lifecycle_event", fields...)
+
+	// Held while writing so events reach the sink in order
+	gs.lifecycleMutex.Lock()
+	defer gs.lifecycleMutex.Unlock()
+	if gs.lifecycleSink == nil {
+		return
+	}
+	if err := gs.lifecycleSink.Write(event); err != nil {
+		gs.metrics.Inc("lifecycle_event_sink_failures_total", "event", event.Event)
+		gs.logger.Warn("lifecycle_event_sink_failed",
+			"lifecycle_event", event.Event,
+			"error", err)
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestLifecycleEvents tests that a start/stop cycle emits the lifecycle
+// events in order, each with a timestamp, to the configured sink
+func TestLifecycleEvents(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	dir := t.TempDir()
+	eventsPath := filepath.Join(dir, "lifecycle.jsonl")
+	socketPath := filepath.Join(dir, "gateway.sock")
+	server := NewGatewayServer(ServerConfig{
+		ShutdownTimeout: 5 * time.Second,
+		UnixSocket:      UnixSocketConfig{Path: socketPath, DisableTCP: true},
+		Lifecycle:       LifecycleConfig{FilePath: eventsPath},
+	}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+
+	// Emitted by the application before starting the server
+	started := time.Now().Add(-time.Second)
+	server.EmitLifecycleEvent(LifecycleEvent{Event: LifecycleStarting, Timestamp: started})
+	server.EmitLifecycleEvent(LifecycleEvent{Event: LifecycleConfigLoaded})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.Start(ctx) }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for len(readLifecycleEvents(t, eventsPath)) < 4 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the server to report serving")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("server did not stop")
+	}
+
+	events := readLifecycleEvents(t, eventsPath)
+
+	t.Run("events are emitted in order", func(t *testing.T) {
+		expected := []string{
+			LifecycleStarting,
+			LifecycleConfigLoaded,
+			LifecyclePluginsReady,
+			LifecycleServing,
+			LifecycleDraining,
+			LifecycleStopped,
+		}
+		if len(events) != len(expected) {
+			t.Fatalf("expected %d events, got %+v", len(expected), events)
+		}
+		for i, event := range events {
+			if event.Event != expected[i] {
+				t.Errorf("event %d: expected %s, got %s", i, expected[i], event.Event)
+			}
+		}
+	})
+
+	t.Run("events carry timestamps and details", func(t *testing.T) {
+		if !events[0].Timestamp.Equal(started) {
+			t.Errorf("expected starting at its original time %v, got %v", started, events[0].Timestamp)
+		}
+		for i := 1; i < len(events); i++ {
+			if events[i].Timestamp.Before(events[i-1].Timestamp) {
+				t.Errorf("event %s is timestamped before %s", events[i].Event, events[i-1].Event)
+			}
+		}
+		if serving := events[3]; serving.Details["unix_socket"] != socketPath {
+			t.Errorf("expected serving to report the socket, got %v", serving.Details)
+		}
+		if stopped := events[5]; stopped.Details["clean"] != true {
+			t.Errorf("expected a clean stop, got %v", stopped.Details)
+		}
+	})
+
+	t.Run("invalid sinks are rejected", func(t *testing.T) {
+		for name, config := range map[string]LifecycleConfig{
+			"two sinks":        {FilePath: eventsPath, Endpoint: "http://localhost/events"},
+			"endpoint scheme":  {Endpoint: "ftp://localhost/events"},
+			"negative timeout": {Endpoint: "http://localhost/events", Timeout: -time.Second},
+		} {
+			if err := config.Validate(); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
+
+func readLifecycleEvents(t *testing.T, path string) []LifecycleEvent {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("failed to open lifecycle events: %v", err)
+	}
+	defer file.Close()
+
+	var events []LifecycleEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event LifecycleEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid lifecycle event %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
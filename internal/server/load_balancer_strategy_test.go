@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestSetLoadBalancerStrategy tests changing the load balancing strategy through PUT /admin/loadbalancer/strategy
+func TestSetLoadBalancerStrategy(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{EnableAdminEndpoints: true}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	lb := server.registry.GetLoadBalancer()
+
+	put := func(t *testing.T, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		t.Helper()
+		req := httptest.NewRequest("PUT", "/admin/loadbalancer/strategy", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+
+		var resp map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+		}
+		return w, resp
+	}
+
+	t.Run("valid strategy changes the default", func(t *testing.T) {
+		w, resp := put(t, `{"strategy": "least_connections"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if resp["effective_strategy"] != "least_connections" {
+			t.Errorf("expected effective_strategy least_connections, got %v", resp["effective_strategy"])
+		}
+		if got := lb.GetStrategy(); got != "least_connections" {
+			t.Errorf("expected live load balancer to use least_connections, got %s", got)
+		}
+	})
+
+	t.Run("invalid strategy is rejected", func(t *testing.T) {
+		w, resp := put(t, `{"strategy": "fastest"}`)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+		if resp["error"] != "invalid_strategy" {
+			t.Errorf("expected error invalid_strategy, got %v", resp["error"])
+		}
+		if got := lb.GetStrategy(); got != "least_connections" {
+			t.Errorf("expected strategy to be unchanged, got %s", got)
+		}
+	})
+
+	t.Run("type scoped change leaves the default alone", func(t *testing.T) {
+		w, resp := put(t, `{"strategy": "hash", "service_type": "search"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if resp["effective_strategy"] != "hash" || resp["default_strategy"] != "least_connections" {
+			t.Errorf("expected hash for search over default least_connections, got %v", resp)
+		}
+		if got := lb.GetEffectiveStrategy("search"); got != "hash" {
+			t.Errorf("expected search services to use hash, got %s", got)
+		}
+		if got := lb.GetEffectiveStrategy("other"); got != "least_connections" {
+			t.Errorf("expected other services to keep the default, got %s", got)
+		}
+	})
+}
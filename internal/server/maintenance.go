@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaintenanceMessage    = "The gateway is down for planned maintenance. Please try again later."
+	defaultMaintenanceRetryAfter = 5 * time.Minute
+)
+
+// MaintenanceConfig configures maintenance mode, in which MCP traffic is
+// rejected with 503 while health, metrics and admin endpoints keep serving
+type MaintenanceConfig struct {
+	Enabled    bool          `yaml:"enabled" json:"enabled"`
+	Message    string        `yaml:"message" json:"message"`         // Empty uses a generic maintenance message
+	RetryAfter time.Duration `yaml:"retry_after" json:"retry_after"` // Sent as Retry-After; 0 uses 5m
+}
+
+// maintenanceStatus is maintenance mode as reported by the admin API
+type maintenanceStatus struct {
+	Enabled           bool       `json:"enabled"`
+	Message           string     `json:"message"`
+	RetryAfterSeconds int        `json:"retry_after_seconds"`
+	Since             *time.Time `json:"since,omitempty"`
+}
+
+// maintenanceUpdate is the admin API request body; omitted fields keep their value
+type maintenanceUpdate struct {
+	Enabled    *bool   `json:"enabled"`
+	Message    *string `json:"message"`
+	RetryAfter *string `json:"retry_after"` // Duration such as "10m"
+}
+
+// isMCPPath reports whether a request path is MCP traffic
+func isMCPPath(path string) bool {
+	return path == "/mcp" || strings.HasPrefix(path, "/mcp/")
+}
+
+// retryAfterSeconds rounds a Retry-After duration up to whole seconds
+func retryAfterSeconds(retryAfter time.Duration) int {
+	if retryAfter <= 0 {
+		retryAfter = defaultMaintenanceRetryAfter
+	}
+	return int(math.Ceil(retryAfter.Seconds()))
+}
+
+// maintenanceState returns the current maintenance settings and when they were enabled
+func (gs *GatewayServer) maintenanceState() (MaintenanceConfig, time.Time) {
+	gs.maintenanceMutex.RLock()
+	defer gs.maintenanceMutex.RUnlock()
+	return gs.maintenance, gs.maintenanceSince
+}
+
+// inMaintenance reports whether maintenance mode is enabled
+func (gs *GatewayServer) inMaintenance() bool {
+	config, _ := gs.maintenanceState()
+	return config.Enabled
+}
+
+// setMaintenance replaces the maintenance settings, logging enable/disable transitions
+func (gs *GatewayServer) setMaintenance(config MaintenanceConfig, source string) {
+	gs.maintenanceMutex.Lock()
+	wasEnabled := gs.maintenance.Enabled
+	gs.maintenance = config
+	if config.Enabled && !wasEnabled {
+		gs.maintenanceSince = time.Now()
+	} else if !config.Enabled {
+		gs.maintenanceSince = time.Time{}
+	}
+	gs.maintenanceMutex.Unlock()
+
+	gauge := 0.0
+	if config.Enabled {
+		gauge = 1
+	}
+	gs.metrics.Set("server_maintenance_mode", gauge)
+	if config.Enabled != wasEnabled {
+		gs.logger.Warn("maintenance_mode_changed",
+			"enabled", config.Enabled,
+			"source", source,
+			"retry_after_seconds", retryAfterSeconds(config.RetryAfter))
+	}
+	if config.Enabled && !wasEnabled {
+		gs.closeStreamsForMaintenance()
+	}
+}
+
+// closeStreamsForMaintenance ends the streams opened before maintenance mode
+// was enabled. Clients see a shutdown event and their reconnect gets the 503.
+func (gs *GatewayServer) closeStreamsForMaintenance() {
+	conns := gs.streamConnections()
+	for _, conn := range conns {
+		if err := conn.NotifyShutdown(); err != nil {
+			gs.logger.Warn("stream_shutdown_notify_failed", "error", err)
+		}
+	}
+	if len(conns) > 0 {
+		gs.metrics.Inc("maintenance_streams_closed_total")
+		gs.logger.Info("maintenance_streams_closed", "count", len(conns))
+	}
+}
+
+// maintenanceMiddleware rejects MCP requests with 503 while maintenance mode is
+// enabled. Health, metrics and admin endpoints are not affected.
+func (gs *GatewayServer) maintenanceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config, _ := gs.maintenanceState()
+		if !config.Enabled || !isMCPPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		message := config.Message
+		if message == "" {
+			message = defaultMaintenanceMessage
+		}
+		retryAfter := retryAfterSeconds(config.RetryAfter)
+
+		gs.metrics.Inc("http_maintenance_rejections_total")
+		gs.logger.Debug("maintenance_request_rejected",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"request_id", r.Header.Get(gs.config.RequestIDHeader))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":               "maintenance",
+			"message":             message,
+			"retry_after_seconds": retryAfter,
+		})
+	})
+}
+
+// handleGetMaintenance reports whether maintenance mode is enabled
+func (gs *GatewayServer) handleGetMaintenance(w http.ResponseWriter, r *http.Request) {
+	gs.writeJSONResponse(w, gs.maintenanceStatus())
+}
+
+// handleSetMaintenance enables or disables maintenance mode without a restart
+func (gs *GatewayServer) handleSetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var update maintenanceUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_request_body",
+			"message": "Failed to parse JSON request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	config, _ := gs.maintenanceState()
+	if update.Enabled != nil {
+		config.Enabled = *update.Enabled
+	}
+	if update.Message != nil {
+		config.Message = *update.Message
+	}
+	if update.RetryAfter != nil {
+		retryAfter, err := time.ParseDuration(*update.RetryAfter)
+		if err != nil || retryAfter < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			gs.writeJSONResponse(w, map[string]interface{}{
+				"error":   "invalid_retry_after",
+				"message": fmt.Sprintf("retry_after must be a non-negative duration such as \"10m\", got %q", *update.RetryAfter),
+			})
+			return
+		}
+		config.RetryAfter = retryAfter
+	}
+
+	gs.setMaintenance(config, "admin_api")
+	gs.configMutex.Lock()
+	gs.config.Maintenance = config
+	gs.configMutex.Unlock()
+
+	gs.logger.Info("admin_maintenance_updated",
+		"enabled", config.Enabled,
+		"remote_addr", r.RemoteAddr)
+	gs.metrics.Inc("admin_api_maintenance_updates_total")
+
+	// Readiness follows maintenance mode straight away
+	gs.checkReadiness(r.Context())
+
+	gs.writeJSONResponse(w, gs.maintenanceStatus())
+}
+
+func (gs *GatewayServer) maintenanceStatus() maintenanceStatus {
+	config, since := gs.maintenanceState()
+	status := maintenanceStatus{
+		Enabled:           config.Enabled,
+		Message:           config.Message,
+		RetryAfterSeconds: retryAfterSeconds(config.RetryAfter),
+	}
+	if status.Message == "" {
+		status.Message = defaultMaintenanceMessage
+	}
+	if config.Enabled {
+		status.Since = &since
+	}
+	return status
+}
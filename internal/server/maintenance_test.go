@@ -0,0 +1,160 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestMaintenanceMode tests that maintenance mode rejects MCP traffic while
+// health and admin endpoints keep serving
+func TestMaintenanceMode(t *testing.T) {
+	logger := logging.New("test")
+	m := metrics.NewProductionMetrics(logger)
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	t.Cleanup(healthMgr.Shutdown)
+
+	server := NewGatewayServer(ServerConfig{
+		EnableHealthEndpoints: true,
+		EnableAdminEndpoints:  true,
+		Maintenance: MaintenanceConfig{
+			Enabled:    true,
+			Message:    "upgrading backends",
+			RetryAfter: 90 * time.Second,
+		},
+	}, logger, m, validation.NewValidator(logger, m), healthMgr)
+	t.Cleanup(func() { server.registry.Shutdown() })
+	handler := server.httpServer.Handler
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+	if _, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+		Name:     "maintenance-backend",
+		Type:     "maintenance",
+		Version:  "1.0.0",
+		Endpoint: backend.URL,
+		Protocol: "http",
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := server.initializePlugins(context.Background()); err != nil {
+		t.Fatalf("failed to initialize plugins: %v", err)
+	}
+	t.Cleanup(func() { server.pluginIntegration.ShutdownPlugins(context.Background()) })
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) map[string]interface{} {
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+	mcpRequest := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+
+	t.Run("MCP requests are rejected with 503 and Retry-After", func(t *testing.T) {
+		w := call("POST", "/mcp", mcpRequest)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503, got %d %s", w.Code, w.Body.String())
+		}
+		if w.Header().Get("Retry-After") != "90" {
+			t.Errorf("expected Retry-After 90, got %q", w.Header().Get("Retry-After"))
+		}
+		resp := decode(w)
+		if resp["error"] != "maintenance" || resp["message"] != "upgrading backends" {
+			t.Errorf("expected the maintenance error and message, got %v", resp)
+		}
+
+		if w := call("POST", "/mcp/tools/list", mcpRequest); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected 503 for MCP subpaths, got %d", w.Code)
+		}
+	})
+
+	t.Run("health and admin endpoints keep serving", func(t *testing.T) {
+		if w := call("GET", "/health/live", ""); w.Code != http.StatusOK {
+			t.Errorf("expected liveness to succeed, got %d", w.Code)
+		}
+		w := call("GET", "/admin/maintenance", "")
+		if w.Code != http.StatusOK || decode(w)["enabled"] != true {
+			t.Errorf("expected admin maintenance status, got %d %s", w.Code, w.Body.String())
+		}
+		if w := call("GET", "/admin/info", ""); w.Code != http.StatusOK {
+			t.Errorf("expected admin info to succeed, got %d", w.Code)
+		}
+	})
+
+	t.Run("readiness reports not_ready", func(t *testing.T) {
+		w := call("GET", "/health/ready", "")
+		resp := decode(w)
+		if w.Code != http.StatusServiceUnavailable || resp["status"] != "not_ready" {
+			t.Fatalf("expected not_ready during maintenance, got %d %v", w.Code, resp)
+		}
+		if !strings.Contains(w.Body.String(), "maintenance mode is enabled") {
+			t.Errorf("expected maintenance among the readiness reasons, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("disabling via the admin API restores traffic", func(t *testing.T) {
+		if w := call("PUT", "/admin/maintenance", `{"retry_after": "soon"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("expected an invalid retry_after to be rejected, got %d", w.Code)
+		}
+
+		w := call("PUT", "/admin/maintenance", `{"enabled": false}`)
+		if w.Code != http.StatusOK || decode(w)["enabled"] != false {
+			t.Fatalf("expected maintenance to be disabled, got %d %s", w.Code, w.Body.String())
+		}
+		if w := call("POST", "/mcp", mcpRequest); w.Code == http.StatusServiceUnavailable {
+			t.Errorf("expected MCP traffic after maintenance, got %d %s", w.Code, w.Body.String())
+		}
+		if w := call("GET", "/health/ready", ""); w.Code != http.StatusOK {
+			t.Errorf("expected ready after maintenance, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("enabling via the admin API closes open streams", func(t *testing.T) {
+		httpServer := httptest.NewServer(handler)
+		defer httpServer.Close()
+
+		resp, err := http.Get(httpServer.URL + NotificationStreamPath)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer resp.Body.Close()
+		reader := bufio.NewReader(resp.Body)
+		if frame := readSSEFrame(t, reader); !strings.HasPrefix(frame, "retry: ") {
+			t.Fatalf("expected retry hint first, got %q", frame)
+		}
+
+		if w := call("PUT", "/admin/maintenance", `{"enabled": true}`); w.Code != http.StatusOK {
+			t.Fatalf("expected maintenance to be enabled, got %d %s", w.Code, w.Body.String())
+		}
+		if frame := readSSEFrame(t, reader); !strings.Contains(frame, "event: shutdown") {
+			t.Errorf("expected the open stream to be closed, got %q", frame)
+		}
+
+		reconnect, err := http.Get(httpServer.URL + NotificationStreamPath)
+		if err != nil {
+			t.Fatalf("failed to reconnect: %v", err)
+		}
+		reconnect.Body.Close()
+		if reconnect.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected the reconnect to be rejected, got %d", reconnect.StatusCode)
+		}
+	})
+}
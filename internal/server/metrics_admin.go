@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/metrics"
+)
+
+// handleMetricsSnapshot returns every recorded metric series with its
+// statistics, keyed by name and labels, for programmatic inspection
+func (gs *GatewayServer) handleMetricsSnapshot(w http.ResponseWriter, r *http.Request) {
+	stats := gs.metrics.GetAllStats()
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"metrics":   stats,
+		"count":     len(stats),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// handleMetricsReset clears counters and histograms so a test run starts
+// from zero. Gauges keep their current values. Only available when
+// EnableMetricsReset is set, which development mode does.
+func (gs *GatewayServer) handleMetricsReset(w http.ResponseWriter, r *http.Request) {
+	if !gs.config.EnableMetricsReset {
+		w.WriteHeader(http.StatusForbidden)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "metrics_reset_disabled",
+			"message": "Metrics reset is only available in development mode",
+		})
+		return
+	}
+
+	resetter, ok := gs.metrics.(metrics.Resetter)
+	if !ok {
+		w.WriteHeader(http.StatusNotImplemented)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "metrics_reset_unsupported",
+			"message": "The configured metrics backend does not support reset",
+		})
+		return
+	}
+
+	cleared := resetter.Reset()
+
+	gs.logger.Warn("metrics_reset",
+		"series_cleared", cleared,
+		"remote_addr", r.RemoteAddr,
+		"user_agent", r.Header.Get("User-Agent"))
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"reset":          true,
+		"series_cleared": cleared,
+		"timestamp":      time.Now().Format(time.RFC3339),
+	})
+}
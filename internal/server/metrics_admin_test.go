@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestMetricsSnapshotAndReset tests the admin metrics snapshot and development-only reset
+func TestMetricsSnapshotAndReset(t *testing.T) {
+	logger := logging.New("test")
+
+	newHandler := func(t *testing.T, enableReset bool) (http.Handler, *metrics.ProductionMetrics) {
+		t.Helper()
+		m := metrics.NewProductionMetrics(logger)
+		healthMgr := health.NewHealthManager(logger, m, "test")
+		t.Cleanup(healthMgr.Shutdown)
+
+		server := NewGatewayServer(ServerConfig{
+			EnableAdminEndpoints: true,
+			EnableMetricsReset:   enableReset,
+		}, logger, m, validation.NewValidator(logger, m), healthMgr)
+		t.Cleanup(func() { server.registry.Shutdown() })
+		return server.httpServer.Handler, m
+	}
+
+	call := func(handler http.Handler, method, path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	snapshot := func(t *testing.T, handler http.Handler) map[string]interface{} {
+		t.Helper()
+		code, resp := call(handler, "GET", "/admin/metrics/snapshot")
+		if code != http.StatusOK {
+			t.Fatalf("expected snapshot to succeed, got %d %v", code, resp)
+		}
+		series, _ := resp["metrics"].(map[string]interface{})
+		return series
+	}
+
+	t.Run("snapshot reflects recorded metrics and reset zeros counters", func(t *testing.T) {
+		handler, m := newHandler(t, true)
+		m.Inc("test_requests_total", "status", "ok")
+		m.Inc("test_requests_total", "status", "ok")
+		m.Set("test_connections", 3)
+
+		series := snapshot(t, handler)
+		counter, _ := series["test_requests_total:status=ok"].(map[string]interface{})
+		if counter["count"] != float64(2) || counter["sum"] != float64(2) {
+			t.Fatalf("expected the counter in the snapshot, got %v", counter)
+		}
+
+		if code, resp := call(handler, "POST", "/admin/metrics/reset"); code != http.StatusOK || resp["reset"] != true {
+			t.Fatalf("expected reset to succeed, got %d %v", code, resp)
+		}
+
+		if stats := m.GetStats("test_requests_total:status=ok"); stats.Count != 0 {
+			t.Errorf("expected the counter to be zeroed, got %+v", stats)
+		}
+		series = snapshot(t, handler)
+		if _, ok := series["test_requests_total:status=ok"]; ok {
+			t.Error("expected the counter to be gone from the snapshot after reset")
+		}
+		if gauge, _ := series["test_connections"].(map[string]interface{}); gauge["last_value"] != float64(3) {
+			t.Errorf("expected gauges to survive reset, got %v", series["test_connections"])
+		}
+	})
+
+	t.Run("reset is refused outside development mode", func(t *testing.T) {
+		handler, m := newHandler(t, false)
+		m.Inc("test_requests_total")
+
+		if code, resp := call(handler, "POST", "/admin/metrics/reset"); code != http.StatusForbidden || resp["error"] != "metrics_reset_disabled" {
+			t.Fatalf("expected reset to be forbidden, got %d %v", code, resp)
+		}
+		if stats := m.GetStats("test_requests_total"); stats.Count != 1 {
+			t.Errorf("expected the counter to be kept, got %+v", stats)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRequestDurationExemplars tests that sampled requests leave trace
+// exemplars on the duration histogram, exposed only through OpenMetrics
+func TestRequestDurationExemplars(t *testing.T) {
+	logger := logging.New("test")
+	m := metrics.NewProductionMetrics(logger)
+	healthMgr := health.NewHealthManager(logger, m, "test")
+	t.Cleanup(healthMgr.Shutdown)
+
+	server := NewGatewayServer(ServerConfig{
+		EnableHealthEndpoints: true,
+		EnableMetricsEndpoint: true,
+	}, logger, m, validation.NewValidator(logger, m), healthMgr)
+	t.Cleanup(func() { server.registry.Shutdown() })
+	handler := server.httpServer.Handler
+
+	const (
+		sampledTrace   = "4bf92f3577b34da6a3ce929d0e0e4736"
+		unsampledTrace = "0af7651916cd43dd8448eb211c80319c"
+	)
+	for _, traceparent := range []string{
+		"00-" + sampledTrace + "-00f067aa0ba902b7-01",
+		"00-" + unsampledTrace + "-b7ad6b7169203331-00",
+	} {
+		req := httptest.NewRequest("GET", "/health/live", nil)
+		req.Header.Set("traceparent", traceparent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	scrape := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected metrics to be served, got %d", w.Code)
+		}
+		return w
+	}
+
+	t.Run("openmetrics renders exemplars of sampled traces", func(t *testing.T) {
+		w := scrape("application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+			t.Errorf("expected an OpenMetrics content type, got %q", ct)
+		}
+
+		body := w.Body.String()
+		var bucket string
+		for _, line := range strings.Split(body, "\n") {
+			if strings.HasPrefix(line, `mcpeg_http_request_duration_seconds_bucket{method="GET",path="/health/live",`) &&
+				strings.Contains(line, " # ") {
+				bucket = line
+				break
+			}
+		}
+		if !strings.Contains(bucket, ` # {trace_id="`+sampledTrace+`"} `) {
+			t.Errorf("expected a bucket with the sampled trace exemplar, got:\n%s", body)
+		}
+		if strings.Contains(body, unsampledTrace) {
+			t.Error("expected no exemplar for the unsampled trace")
+		}
+		if !strings.Contains(body, "# TYPE mcpeg_http_requests counter\n") {
+			t.Error("expected counter families to be named without _total")
+		}
+		if !strings.HasSuffix(body, "# EOF\n") {
+			t.Error("expected the exposition to end with # EOF")
+		}
+	})
+
+	t.Run("prometheus text has buckets without exemplars", func(t *testing.T) {
+		w := scrape("")
+		body := w.Body.String()
+		if !strings.Contains(body, `mcpeg_http_request_duration_seconds_count{method="GET",path="/health/live"} 2`) {
+			t.Errorf("expected both requests in the histogram, got:\n%s", body)
+		}
+		if strings.Contains(body, "trace_id") || strings.Contains(body, "# EOF") {
+			t.Error("expected no OpenMetrics syntax in the Prometheus text format")
+		}
+	})
+}
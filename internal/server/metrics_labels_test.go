@@ -0,0 +1,83 @@
+package server
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestMetricsRouteLabels tests that HTTP metrics are labelled by route template rather than raw path
+func TestMetricsRouteLabels(t *testing.T) {
+	logger := logging.New("test")
+	recorder := &labelRecordingMetrics{}
+	validator := validation.NewValidator(logger, &mockMetrics{})
+	healthMgr := health.NewHealthManager(logger, &mockMetrics{}, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{EnableAdminEndpoints: true}, logger, recorder, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	t.Run("different IDs share one templated series", func(t *testing.T) {
+		for _, id := range []string{"abc-123", "def-456"} {
+			req := httptest.NewRequest("GET", "/admin/services/"+id, nil)
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+		}
+
+		paths := recorder.paths("http_requests_total")
+		if paths["/admin/services/{id}"] != 2 {
+			t.Errorf("expected 2 requests under /admin/services/{id}, got %v", paths)
+		}
+		for path := range paths {
+			if path == "/admin/services/abc-123" || path == "/admin/services/def-456" {
+				t.Errorf("expected raw path %s not to be used as a label", path)
+			}
+		}
+	})
+
+	t.Run("requests without a route are labelled other", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/no/such/route/42", nil)
+		if label := metricsRouteLabel(req); label != "other" {
+			t.Errorf("expected label other, got %s", label)
+		}
+	})
+}
+
+// labelRecordingMetrics records the path label of each counter increment
+type labelRecordingMetrics struct {
+	mockMetrics
+	mutex  sync.Mutex
+	counts map[string]map[string]int // metric -> path -> count
+}
+
+func (m *labelRecordingMetrics) Inc(name string, labels ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.counts == nil {
+		m.counts = make(map[string]map[string]int)
+	}
+	if m.counts[name] == nil {
+		m.counts[name] = make(map[string]int)
+	}
+	for i := 0; i+1 < len(labels); i += 2 {
+		if labels[i] == "path" {
+			m.counts[name][labels[i+1]]++
+		}
+	}
+}
+
+func (m *labelRecordingMetrics) paths(name string) map[string]int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	paths := make(map[string]int, len(m.counts[name]))
+	for path, count := range m.counts[name] {
+		paths[path] = count
+	}
+	return paths
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/osakka/mcpeg/internal/router"
+)
+
+const (
+	// defaultNotificationHistory bounds the notifications retained for
+	// Last-Event-ID replay when a stream reconnects
+	defaultNotificationHistory = 256
+
+	// notificationSubscriberBuffer is how far a subscriber may fall behind
+	// before it is disconnected and must resume via Last-Event-ID
+	notificationSubscriberBuffer = 64
+)
+
+// transientNotifications are only meaningful while they are delivered, so they
+// are not retained for replay
+var transientNotifications = map[string]bool{
+	"notifications/progress": true,
+}
+
+// Notification is a server-initiated MCP notification delivered to streaming
+// clients. A nil Audience reaches every stream.
+type Notification struct {
+	ID       uint64              `json:"-"`
+	Method   string              `json:"method"`
+	Params   interface{}         `json:"params,omitempty"`
+	Audience []router.Subscriber `json:"-"`
+}
+
+// deliveredTo reports whether a stream opened by subscriber receives the notification
+func (n Notification) deliveredTo(subscriber router.Subscriber) bool {
+	if n.Audience == nil {
+		return true
+	}
+	for _, target := range n.Audience {
+		if subscriber.Receives(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationHub fans server-initiated notifications out to streaming
+// subscribers and keeps a bounded history so reconnecting clients can resume
+type NotificationHub struct {
+	mutex       sync.Mutex
+	lastID      uint64
+	evictedID   uint64 // ID of the newest notification dropped from history
+	history     []Notification
+	historySize int
+	subscribers map[*NotificationSubscription]struct{}
+}
+
+// NotificationSubscription receives the notifications addressed to its
+// subscriber that were published after it was created
+type NotificationSubscription struct {
+	hub        *NotificationHub
+	subscriber router.Subscriber
+	ch         chan Notification
+	closed     bool
+}
+
+// NewNotificationHub creates a hub retaining up to historySize notifications for replay
+func NewNotificationHub(historySize int) *NotificationHub {
+	if historySize <= 0 {
+		historySize = defaultNotificationHistory
+	}
+	return &NotificationHub{
+		historySize: historySize,
+		subscribers: make(map[*NotificationSubscription]struct{}),
+	}
+}
+
+// Publish assigns the next event ID to a notification and delivers it to the
+// subscribers in audience, or to all subscribers when audience is nil.
+// Subscribers whose buffer is full are disconnected rather than blocking the
+// publisher; they recover the missed events on reconnect.
+func (h *NotificationHub) Publish(method string, params interface{}, audience []router.Subscriber) Notification {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastID++
+	notification := Notification{ID: h.lastID, Method: method, Params: params, Audience: audience}
+
+	if !transientNotifications[method] {
+		h.history = append(h.history, notification)
+		if len(h.history) > h.historySize {
+			evict := len(h.history) - h.historySize
+			h.evictedID = h.history[evict-1].ID
+			h.history = h.history[evict:]
+		}
+	}
+
+	for sub := range h.subscribers {
+		if !notification.deliveredTo(sub.subscriber) {
+			continue
+		}
+		select {
+		case sub.ch <- notification:
+		default:
+			h.removeLocked(sub)
+		}
+	}
+
+	return notification
+}
+
+// Subscribe registers a new subscriber. When resuming is set, retained
+// notifications for subscriber with an ID after lastEventID are returned for
+// replay; complete is false if some retained notifications have already been
+// evicted from the history.
+func (h *NotificationHub) Subscribe(subscriber router.Subscriber, lastEventID uint64, resuming bool) (sub *NotificationSubscription, replay []Notification, complete bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	sub = &NotificationSubscription{
+		hub:        h,
+		subscriber: subscriber,
+		ch:         make(chan Notification, notificationSubscriberBuffer),
+	}
+	h.subscribers[sub] = struct{}{}
+
+	complete = true
+	// An ID ahead of the hub was issued before a restart; nothing can be replayed
+	if !resuming || lastEventID >= h.lastID {
+		return sub, nil, complete
+	}
+
+	if lastEventID < h.evictedID {
+		complete = false
+	}
+	for _, notification := range h.history {
+		if notification.ID > lastEventID && notification.deliveredTo(subscriber) {
+			replay = append(replay, notification)
+		}
+	}
+	return sub, replay, complete
+}
+
+// SubscriberCount returns the number of active subscriptions
+func (h *NotificationHub) SubscriberCount() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.subscribers)
+}
+
+func (h *NotificationHub) removeLocked(sub *NotificationSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(h.subscribers, sub)
+	close(sub.ch)
+}
+
+// C returns the channel notifications are delivered on. It is closed when the
+// subscription ends, including when the subscriber fell too far behind.
+func (s *NotificationSubscription) C() <-chan Notification {
+	return s.ch
+}
+
+// Close ends the subscription
+func (s *NotificationSubscription) Close() {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+	s.hub.removeLocked(s)
+}
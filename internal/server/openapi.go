@@ -0,0 +1,187 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/internal/router"
+	"github.com/osakka/mcpeg/pkg/codegen"
+)
+
+// OpenAPISpecPath serves an OpenAPI document describing the tools the caller
+// may call
+const OpenAPISpecPath = "/api/openapi.json"
+
+var operationIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// handleOpenAPISpec generates the OpenAPI document from the registry on each
+// request. Callers are authenticated like /mcp and only see the tools their
+// capabilities let them call.
+func (gs *GatewayServer) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	capabilities, err := gs.mcpRouter.CallerCapabilities(r)
+	if err != nil {
+		gs.metrics.Inc("openapi_spec_auth_failures_total")
+		w.WriteHeader(http.StatusUnauthorized)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "unauthorized",
+			"message": "Authentication failed",
+		})
+		return
+	}
+
+	spec := gs.buildOpenAPISpec(gs.registry.GetAllServices(), func(service *registry.RegisteredService, tool registry.ToolDefinition) bool {
+		return gs.mcpRouter.CanCallTool(capabilities, service.Name, tool.Name)
+	})
+
+	gs.metrics.Inc("openapi_spec_requests_total")
+	gs.writeJSONResponse(w, spec)
+}
+
+// buildOpenAPISpec describes each tool allowed by visible as a POST operation
+// on its tools/call path, whose request body is the tool's input schema.
+// Services are visited by name, so when several offer the same tool the
+// operation describes the first.
+func (gs *GatewayServer) buildOpenAPISpec(services map[string]*registry.RegisteredService, visible func(*registry.RegisteredService, registry.ToolDefinition) bool) *codegen.OpenAPISpec {
+	spec := &codegen.OpenAPISpec{
+		OpenAPI: "3.1.0",
+		Info: codegen.APIInfo{
+			Title:       "MCpeg",
+			Description: "Tools offered through the Model Context Protocol Enablement Gateway",
+			Version:     gs.version,
+		},
+		Servers: []codegen.Server{{
+			URL:         "/",
+			Description: "Each operation is a tools/call request for one tool, answered with the JSON-RPC response",
+		}},
+		Paths: map[string]codegen.PathItem{},
+		Components: codegen.Components{
+			Schemas:         map[string]codegen.Schema{},
+			Responses:       map[string]codegen.Response{},
+			Parameters:      map[string]codegen.Parameter{},
+			RequestBodies:   map[string]codegen.RequestBody{},
+			Headers:         map[string]codegen.Header{},
+			SecuritySchemes: map[string]codegen.SecurityScheme{},
+		},
+		Security: []codegen.SecurityRequirement{},
+		Tags:     []codegen.Tag{},
+	}
+
+	ordered := make([]*registry.RegisteredService, 0, len(services))
+	for _, service := range services {
+		ordered = append(ordered, service)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].Name != ordered[j].Name {
+			return ordered[i].Name < ordered[j].Name
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	tagged := make(map[string]bool)
+	for _, service := range ordered {
+		for _, tool := range service.Tools {
+			// Tool paths take a single segment
+			if tool.Name == "" || strings.Contains(tool.Name, "/") || !visible(service, tool) {
+				continue
+			}
+			path := router.ToolCallPathPrefix + tool.Name
+			if _, exists := spec.Paths[path]; exists {
+				continue
+			}
+
+			if !tagged[service.Name] {
+				tagged[service.Name] = true
+				spec.Tags = append(spec.Tags, codegen.Tag{Name: service.Name, Description: service.Description})
+			}
+			spec.Paths[path] = codegen.PathItem{POST: gs.toolOperation(service, tool)}
+		}
+	}
+
+	return spec
+}
+
+// toolOperation describes calling one tool
+func (gs *GatewayServer) toolOperation(service *registry.RegisteredService, tool registry.ToolDefinition) *codegen.Operation {
+	inputSchema := gs.openAPISchema(service, tool.Name, "input", tool.InputSchema)
+	result := codegen.Schema{
+		Type: "object",
+		Properties: map[string]codegen.Schema{
+			"content": {Type: "array", Items: &codegen.Schema{Type: "object"}},
+			"isError": {Type: "boolean"},
+		},
+	}
+	if tool.OutputSchema != nil {
+		result.Properties["structuredContent"] = gs.openAPISchema(service, tool.Name, "output", tool.OutputSchema)
+	}
+	response := codegen.Schema{
+		Type:     "object",
+		Required: []string{"jsonrpc", "id"},
+		Properties: map[string]codegen.Schema{
+			"jsonrpc": {Type: "string"},
+			"id":      {},
+			"result":  result,
+			"error": {
+				Type:     "object",
+				Required: []string{"code", "message"},
+				Properties: map[string]codegen.Schema{
+					"code":    {Type: "integer"},
+					"message": {Type: "string"},
+					"data":    {Type: "object"},
+				},
+			},
+		},
+	}
+
+	return &codegen.Operation{
+		OperationID: operationIDUnsafe.ReplaceAllString(tool.Name, "_"),
+		Summary:     tool.Description,
+		Description: tool.Description,
+		Tags:        []string{service.Name},
+		Parameters:  []codegen.Parameter{},
+		RequestBody: &codegen.RequestBody{
+			Description: "Tool arguments",
+			Required:    true,
+			Content: map[string]codegen.MediaType{
+				"application/json": {Schema: inputSchema, Examples: map[string]codegen.Example{}},
+			},
+		},
+		Responses: map[string]codegen.Response{
+			"200": {
+				Description: "JSON-RPC response carrying the tool result or error",
+				Headers:     map[string]codegen.Header{},
+				Content: map[string]codegen.MediaType{
+					"application/json": {Schema: response, Examples: map[string]codegen.Example{}},
+				},
+			},
+		},
+		Security: []codegen.SecurityRequirement{},
+	}
+}
+
+// openAPISchema reads a tool's JSON Schema into the codegen Schema type.
+// Keywords the type cannot hold are dropped; a schema it cannot read at all,
+// such as one with a list of types, is described as any object.
+func (gs *GatewayServer) openAPISchema(service *registry.RegisteredService, toolName, kind string, schema map[string]interface{}) codegen.Schema {
+	if schema == nil {
+		return codegen.Schema{Type: "object"}
+	}
+
+	var converted codegen.Schema
+	data, err := json.Marshal(schema)
+	if err == nil {
+		err = json.Unmarshal(data, &converted)
+	}
+	if err != nil {
+		gs.logger.Warn("openapi_tool_schema_unsupported",
+			"service_id", service.ID,
+			"tool", toolName,
+			"schema", kind,
+			"error", err)
+		return codegen.Schema{Type: "object"}
+	}
+	return converted
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/codegen"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestOpenAPISpec tests that a registered service's tools appear as operations with their input schemas
+func TestOpenAPISpec(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{ErrorDetail: "full"}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+	defer server.registry.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	if _, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+		Name:        "weather",
+		Type:        "openapi_test",
+		Version:     "1.0.0",
+		Description: "Weather lookups",
+		Endpoint:    backend.URL,
+		Protocol:    "http",
+		Tools: []registry.ToolDefinition{{
+			Name:        "list",
+			Description: "Tool sharing its name with the tools/list path",
+		}, {
+			Name:        "get_forecast",
+			Description: "Forecast for a city",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"city": map[string]interface{}{"type": "string", "description": "City name"},
+					"days": map[string]interface{}{"type": "integer", "minimum": 1},
+				},
+				"required": []interface{}{"city"},
+			},
+		}},
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", OpenAPISpecPath, nil)
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var spec codegen.OpenAPISpec
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+	if spec.OpenAPI != "3.1.0" {
+		t.Errorf("expected OpenAPI 3.1.0, got %q", spec.OpenAPI)
+	}
+
+	item, exists := spec.Paths["/mcp/tools/call/get_forecast"]
+	if !exists || item.POST == nil {
+		t.Fatalf("expected a POST operation for get_forecast, got paths %v", spec.Paths)
+	}
+	operation := item.POST
+	if operation.OperationID != "get_forecast" || operation.Summary != "Forecast for a city" {
+		t.Errorf("unexpected operation %q: %q", operation.OperationID, operation.Summary)
+	}
+	if len(operation.Tags) != 1 || operation.Tags[0] != "weather" {
+		t.Errorf("expected the operation to be tagged with its service, got %v", operation.Tags)
+	}
+
+	if operation.RequestBody == nil {
+		t.Fatal("expected the input schema as the request body")
+	}
+	schema := operation.RequestBody.Content["application/json"].Schema
+	if schema.Type != "object" || len(schema.Required) != 1 || schema.Required[0] != "city" {
+		t.Errorf("expected an object schema requiring city, got %+v", schema)
+	}
+	if schema.Properties["city"].Type != "string" || schema.Properties["city"].Description != "City name" {
+		t.Errorf("expected city to be a described string, got %+v", schema.Properties["city"])
+	}
+	if days := schema.Properties["days"]; days.Type != "integer" || days.Minimum == nil || *days.Minimum != 1 {
+		t.Errorf("expected days to be an integer of at least 1, got %+v", days)
+	}
+	if _, exists := spec.Paths["/mcp/tools/call/list"]; !exists {
+		t.Errorf("expected a tool named list to get its own path, got paths %v", spec.Paths)
+	}
+
+	t.Run("documented path calls the tool", func(t *testing.T) {
+		if err := server.pluginIntegration.GetPluginManager().RegisterPlugin(&schemaTestPlugin{
+			tools: []registry.ToolDefinition{{
+				Name: "search",
+				InputSchema: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"query": map[string]interface{}{"type": "string"}},
+					"required":   []interface{}{"query"},
+				},
+			}},
+		}); err != nil {
+			t.Fatalf("failed to register plugin: %v", err)
+		}
+		server.refreshAllToolSchemas()
+
+		call := func(body string) string {
+			req := httptest.NewRequest("POST", "/mcp/tools/call/schema-test.search", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+			return w.Body.String()
+		}
+		if body := call(`{"query": "weather"}`); !strings.Contains(body, `"result"`) {
+			t.Errorf("expected the tool result, got %s", body)
+		}
+		// The body is checked as the tool's arguments
+		if body := call(`{}`); !strings.Contains(body, "missing required argument query") {
+			t.Errorf("expected the arguments to be validated, got %s", body)
+		}
+		if body := call(`[1, 2]`); !strings.Contains(body, "must be a JSON object") {
+			t.Errorf("expected non-object arguments to be rejected, got %s", body)
+		}
+	})
+
+	t.Run("tools the caller may not call are left out", func(t *testing.T) {
+		spec := server.buildOpenAPISpec(server.registry.GetAllServices(), func(service *registry.RegisteredService, tool registry.ToolDefinition) bool {
+			return tool.Name != "get_forecast"
+		})
+		if _, exists := spec.Paths["/mcp/tools/call/get_forecast"]; exists {
+			t.Error("expected the hidden tool not to be described")
+		}
+		if _, exists := spec.Paths["/mcp/tools/call/list"]; !exists {
+			t.Error("expected the visible tool to be described")
+		}
+	})
+}
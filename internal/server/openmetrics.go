@@ -0,0 +1,122 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/osakka/mcpeg/pkg/metrics"
+)
+
+// OpenMetrics exposition, served to scrapers that ask for it so that
+// histogram buckets can carry trace exemplars
+const (
+	openMetricsMediaType   = "application/openmetrics-text"
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// wantsOpenMetrics reports whether the scraper accepts the OpenMetrics format
+func wantsOpenMetrics(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(accepted, ";", 2)[0])
+		if strings.EqualFold(mediaType, openMetricsMediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestExemplar returns the exemplar of a request's duration, or nil when
+// its trace is not sampled and so cannot be looked up
+func requestExemplar(r *http.Request, duration float64) *metrics.Exemplar {
+	trace := traceFromContext(r.Context())
+	if trace == nil || !trace.Sampled {
+		return nil
+	}
+	return &metrics.Exemplar{TraceID: trace.TraceID, Value: duration}
+}
+
+// writeHistograms writes the labelled series of a histogram, with the
+// exemplar of each bucket when exemplars is set
+func writeHistograms(w io.Writer, name string, series []metrics.HistogramSnapshot, exemplars bool) {
+	for _, snapshot := range series {
+		labels := formatLabels(snapshot.Labels)
+
+		for i, count := range snapshot.Counts {
+			le := "+Inf"
+			if i < len(snapshot.Buckets) {
+				le = strconv.FormatFloat(snapshot.Buckets[i], 'g', -1, 64)
+			}
+			bucketLabels := strings.TrimPrefix(labels+`,le="`+le+`"`, ",")
+			fmt.Fprintf(w, "%s_bucket{%s} %d", name, bucketLabels, count)
+
+			if exemplar := snapshot.Exemplars[i]; exemplars && exemplar != nil {
+				fmt.Fprintf(w, " # {trace_id=\"%s\"} %s %.3f",
+					labelValueEscaper.Replace(exemplar.TraceID),
+					strconv.FormatFloat(exemplar.Value, 'g', -1, 64),
+					float64(exemplar.Timestamp.UnixMilli())/1000)
+			}
+			fmt.Fprintln(w)
+		}
+
+		if labels != "" {
+			labels = "{" + labels + "}"
+		}
+		fmt.Fprintf(w, "%s_sum%s %f\n", name, labels, snapshot.Sum)
+		fmt.Fprintf(w, "%s_count%s %d\n", name, labels, snapshot.Count)
+	}
+}
+
+// labelValueEscaper escapes label values for the text exposition formats
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels renders label name/value pairs as name="value",...
+func formatLabels(pairs []string) string {
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+labelValueEscaper.Replace(pairs[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+// toOpenMetrics converts the Prometheus text exposition to OpenMetrics.
+// Counter families are named without the _total suffix their samples must
+// carry, and the exposition is terminated by # EOF.
+func toOpenMetrics(text []byte) []byte {
+	counters := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 4 && fields[0] == "#" && fields[1] == "TYPE" && fields[3] == "counter" {
+			counters[fields[2]] = strings.TrimSuffix(fields[2], "_total")
+		}
+	}
+
+	var out bytes.Buffer
+	scanner = bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "# HELP ") || strings.HasPrefix(line, "# TYPE ") {
+			fields := strings.SplitN(line, " ", 4)
+			if family, ok := counters[fields[2]]; ok && len(fields) == 4 {
+				line = strings.Join([]string{fields[0], fields[1], family, fields[3]}, " ")
+			}
+		} else if !strings.HasPrefix(line, "#") {
+			name := line
+			if end := strings.IndexAny(line, "{ "); end >= 0 {
+				name = line[:end]
+			}
+			if _, ok := counters[name]; ok && !strings.HasSuffix(name, "_total") {
+				line = name + "_total" + line[len(name):]
+			}
+		}
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+	out.WriteString("# EOF\n")
+	return out.Bytes()
+}
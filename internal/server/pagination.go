@@ -0,0 +1,50 @@
+package server
+
+import "fmt"
+
+// Page sizes used when PaginationConfig leaves them unset
+const (
+	defaultPageSize    = 100
+	defaultMaxPageSize = 1000
+)
+
+// PaginationConfig bounds the pages returned by paginated admin list
+// endpoints, so a client cannot ask for every entry at once
+type PaginationConfig struct {
+	DefaultPageSize int `yaml:"default_page_size"` // Used when no positive limit is requested
+	MaxPageSize     int `yaml:"max_page_size"`     // Larger requested limits are clamped to this
+}
+
+// Validate checks that the page sizes are non-negative and the default fits the maximum
+func (c PaginationConfig) Validate() error {
+	if c.DefaultPageSize < 0 || c.MaxPageSize < 0 {
+		return fmt.Errorf("page sizes must not be negative")
+	}
+	if c.DefaultPageSize > c.maxPageSize() {
+		return fmt.Errorf("default page size %d exceeds the max page size %d", c.DefaultPageSize, c.maxPageSize())
+	}
+	return nil
+}
+
+func (c PaginationConfig) maxPageSize() int {
+	if c.MaxPageSize > 0 {
+		return c.MaxPageSize
+	}
+	return defaultMaxPageSize
+}
+
+// pageLimit returns the effective limit for a requested one: the default page
+// size when none was requested, clamped to the max page size
+func (c PaginationConfig) pageLimit(requested int) (limit int, clamped bool) {
+	limit = requested
+	if limit <= 0 {
+		limit = c.DefaultPageSize
+		if limit <= 0 {
+			limit = defaultPageSize
+		}
+	}
+	if max := c.maxPageSize(); limit > max {
+		return max, requested > max
+	}
+	return limit, false
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestListServicesPageSize tests that GET /admin/services applies the default
+// page size and clamps requested limits to the max page size
+func TestListServicesPageSize(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		EnableAdminEndpoints: true,
+		Pagination:           PaginationConfig{DefaultPageSize: 2, MaxPageSize: 3},
+	}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+	defer server.registry.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	for i := 0; i < 5; i++ {
+		if _, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+			Name:     fmt.Sprintf("page-%d", i),
+			Type:     "page_test",
+			Version:  "1.0.0",
+			Endpoint: backend.URL,
+			Protocol: "http",
+		}); err != nil {
+			t.Fatalf("failed to register service: %v", err)
+		}
+	}
+
+	for _, tt := range []struct {
+		query    string
+		returned int
+		clamped  bool
+	}{
+		{"limit=10", 3, true},
+		{"limit=0", 2, false},
+		{"limit=-5", 2, false},
+		{"", 2, false},
+		{"limit=1", 1, false},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/services?type=page_test&"+tt.query, nil)
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var resp struct {
+				Services []json.RawMessage `json:"services"`
+				Metadata struct {
+					FilteredCount int  `json:"filtered_count"`
+					Limit         int  `json:"limit"`
+					LimitClamped  bool `json:"limit_clamped"`
+				} `json:"metadata"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Services) != tt.returned || resp.Metadata.Limit != tt.returned {
+				t.Errorf("expected %d services and limit %d, got %d and %d",
+					tt.returned, tt.returned, len(resp.Services), resp.Metadata.Limit)
+			}
+			if resp.Metadata.LimitClamped != tt.clamped {
+				t.Errorf("expected limit_clamped %t, got %t", tt.clamped, resp.Metadata.LimitClamped)
+			}
+			if resp.Metadata.FilteredCount != 5 {
+				t.Errorf("expected filtered_count 5, got %d", resp.Metadata.FilteredCount)
+			}
+		})
+	}
+
+	t.Run("config validation", func(t *testing.T) {
+		if err := (PaginationConfig{DefaultPageSize: 50, MaxPageSize: 10}).Validate(); err == nil {
+			t.Error("expected a default page size above the max to be rejected")
+		}
+		if err := (PaginationConfig{}).Validate(); err != nil {
+			t.Errorf("expected the zero config to use defaults, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRecoveryMiddlewareStackTrace tests that recovered panics log a stack trace and record a metric
+func TestRecoveryMiddlewareStackTrace(t *testing.T) {
+	logger := &recordingLogger{Logger: logging.New("test")}
+	recorder := &labelRecordingMetrics{}
+	validator := validation.NewValidator(logger, &mockMetrics{})
+	healthMgr := health.NewHealthManager(logger, &mockMetrics{}, "test")
+	defer healthMgr.Shutdown()
+
+	newHandler := func(config ServerConfig) (*GatewayServer, http.Handler) {
+		server := NewGatewayServer(config, logger, recorder, validator, healthMgr)
+		r := mux.NewRouter()
+		r.Use(server.recoveryMiddleware)
+		r.HandleFunc("/boom/{id}", func(w http.ResponseWriter, r *http.Request) {
+			panic("kaboom")
+		})
+		return server, r
+	}
+
+	t.Run("stack trace is logged and panic counted", func(t *testing.T) {
+		server, handler := newHandler(ServerConfig{})
+		defer server.registry.Shutdown()
+
+		req := httptest.NewRequest("GET", "/boom/42", nil)
+		req.Header.Set("X-Request-ID", "req-panic")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected status 500, got %d", w.Code)
+		}
+		if strings.Contains(w.Body.String(), "goroutine") {
+			t.Error("expected stack trace not to be exposed outside development mode")
+		}
+
+		fields, ok := logger.find("panic_recovered")
+		if !ok {
+			t.Fatal("expected panic_recovered to be logged")
+		}
+		if stack, _ := fields["stack_trace"].(string); !strings.Contains(stack, "panic_recovery_test.go") {
+			t.Errorf("expected stack trace pointing at the panicking handler, got %q", stack)
+		}
+		if fields["request_id"] != "req-panic" || fields["path"] != "/boom/42" || fields["method"] != "GET" {
+			t.Errorf("expected request context in log fields, got %v", fields)
+		}
+		if count := recorder.paths("http_panics_total")["/boom/{id}"]; count != 1 {
+			t.Errorf("expected one panic recorded for /boom/{id}, got %d", count)
+		}
+	})
+
+	t.Run("development mode includes a truncated trace", func(t *testing.T) {
+		server, handler := newHandler(ServerConfig{ExposePanicTraces: true})
+		defer server.registry.Shutdown()
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/boom/7", nil))
+
+		body := w.Body.String()
+		if !strings.Contains(body, "kaboom") || !strings.Contains(body, "goroutine") {
+			t.Errorf("expected panic value and trace in response body, got %q", body)
+		}
+		if len(body) > maxExposedPanicTrace+256 {
+			t.Errorf("expected trace to be truncated, got %d bytes", len(body))
+		}
+	})
+}
+
+// recordingLogger captures error entries while delegating to a real logger
+type recordingLogger struct {
+	logging.Logger
+	mutex  sync.Mutex
+	errors map[string]map[string]interface{}
+}
+
+func (l *recordingLogger) Error(operation string, fields ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.errors == nil {
+		l.errors = make(map[string]map[string]interface{})
+	}
+	entry := make(map[string]interface{})
+	for i := 0; i+1 < len(fields); i += 2 {
+		if key, ok := fields[i].(string); ok {
+			entry[key] = fields[i+1]
+		}
+	}
+	l.errors[operation] = entry
+}
+
+func (l *recordingLogger) find(operation string) (map[string]interface{}, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	fields, ok := l.errors[operation]
+	return fields, ok
+}
+
+func (l *recordingLogger) WithComponent(component string) logging.Logger  { return l }
+func (l *recordingLogger) WithContext(ctx context.Context) logging.Logger { return l }
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestPluginConfigDryRun tests that a dry-run config update reports validity
+// and changes without touching the live plugin configuration
+func TestPluginConfigDryRun(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{EnableAdminEndpoints: true}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+	defer server.registry.Shutdown()
+
+	if err := server.pluginIntegration.GetPluginManager().RegisterPlugin(plugins.NewMemoryService()); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	serve := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		return w
+	}
+
+	liveConfig := func(t *testing.T) string {
+		t.Helper()
+		w := serve("GET", "/admin/plugins/memory/config", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return w.Body.String()
+	}
+	before := liveConfig(t)
+
+	type change struct {
+		Key      string      `json:"key"`
+		Change   string      `json:"change"`
+		Current  interface{} `json:"current"`
+		Proposed interface{} `json:"proposed"`
+	}
+	var resp struct {
+		DryRun    bool     `json:"dry_run"`
+		Valid     bool     `json:"valid"`
+		Validated bool     `json:"validated"`
+		Errors    []string `json:"errors"`
+		Changes   []change `json:"changes"`
+	}
+	dryRun := func(t *testing.T, config map[string]interface{}) {
+		t.Helper()
+		w := serve("PUT", "/admin/plugins/memory/config?dry_run=true", config)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		resp.Errors, resp.Changes = nil, nil
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !resp.DryRun || !resp.Validated {
+			t.Errorf("expected a validated dry run, got %s", w.Body.String())
+		}
+	}
+
+	t.Run("valid config reports its changes", func(t *testing.T) {
+		dryRun(t, map[string]interface{}{
+			"data_dir":    "./data",
+			"auto_save":   false,
+			"max_keys":    10000,
+			"default_ttl": 3600,
+			"compression": true,
+		})
+		if !resp.Valid || len(resp.Errors) != 0 {
+			t.Errorf("expected valid config, got errors %v", resp.Errors)
+		}
+
+		want := []change{
+			{Key: "auto_save", Change: "changed", Current: true, Proposed: false},
+			{Key: "compression", Change: "added", Proposed: true},
+		}
+		if len(resp.Changes) != len(want) {
+			t.Fatalf("expected changes %+v, got %+v", want, resp.Changes)
+		}
+		for i := range want {
+			if resp.Changes[i] != want[i] {
+				t.Errorf("expected change %+v, got %+v", want[i], resp.Changes[i])
+			}
+		}
+	})
+
+	t.Run("invalid config reports each error", func(t *testing.T) {
+		dryRun(t, map[string]interface{}{
+			"data_dir": "",
+			"max_keys": -1,
+		})
+		if resp.Valid || len(resp.Errors) != 2 {
+			t.Errorf("expected two validation errors, got valid=%t errors=%v", resp.Valid, resp.Errors)
+		}
+	})
+
+	t.Run("invalid config update is rejected with its errors", func(t *testing.T) {
+		w := serve("PUT", "/admin/plugins/memory/config", map[string]interface{}{"max_keys": -1})
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+		var rejected struct {
+			Error  string   `json:"error"`
+			Errors []string `json:"errors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &rejected); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if rejected.Error != "invalid_plugin_config" || len(rejected.Errors) == 0 {
+			t.Errorf("expected the validation errors, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("live config is unchanged", func(t *testing.T) {
+		if after := liveConfig(t); after != before {
+			t.Errorf("expected live config %s, got %s", before, after)
+		}
+	})
+
+	t.Run("unknown plugin and bad flag are rejected", func(t *testing.T) {
+		if w := serve("PUT", "/admin/plugins/missing/config?dry_run=true", map[string]interface{}{}); w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+		if w := serve("PUT", "/admin/plugins/memory/config?dry_run=maybe", map[string]interface{}{}); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
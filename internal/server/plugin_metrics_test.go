@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestPrometheusPluginMetrics tests that plugin invocations are exported with a bounded plugin label
+func TestPrometheusPluginMetrics(t *testing.T) {
+	logger := logging.New("test")
+	productionMetrics := metrics.NewProductionMetrics(logger)
+	validator := validation.NewValidator(logger, productionMetrics)
+	healthMgr := health.NewHealthManager(logger, productionMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{EnableMetricsEndpoint: true}, logger, productionMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	if err := server.pluginIntegration.GetPluginManager().RegisterPlugin(&metricsTestPlugin{}); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	call := func(t *testing.T, tool string) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params":  map[string]interface{}{"name": tool, "arguments": map[string]interface{}{}},
+		})
+		req := httptest.NewRequest("POST", "/mcp", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d: %s", tool, w.Code, w.Body.String())
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		call(t, "metrics-test.echo")
+	}
+	call(t, "unregistered-plugin.echo")
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, req)
+	output := w.Body.String()
+
+	for _, expected := range []string{
+		`mcpeg_plugin_invocations_total{plugin="metrics-test"} 3`,
+		`mcpeg_plugin_errors_total{plugin="metrics-test"} 0`,
+		`mcpeg_plugin_invocation_duration_seconds_count{plugin="metrics-test"} 3`,
+		`mcpeg_plugin_error_rate{plugin="metrics-test"} 0.000000`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected metrics output to contain %q", expected)
+		}
+	}
+
+	if strings.Contains(output, `plugin="unregistered-plugin"`) {
+		t.Error("expected plugins that are not registered to be left out of the output")
+	}
+}
+
+// metricsTestPlugin answers every tool call; other Plugin methods are unused
+type metricsTestPlugin struct {
+	plugins.Plugin
+}
+
+func (p *metricsTestPlugin) Name() string        { return "metrics-test" }
+func (p *metricsTestPlugin) Version() string     { return "1.0.0" }
+func (p *metricsTestPlugin) Description() string { return "Plugin metrics test plugin" }
+func (p *metricsTestPlugin) GetTools() []registry.ToolDefinition {
+	return []registry.ToolDefinition{{Name: "echo", Description: "Echo"}}
+}
+func (p *metricsTestPlugin) GetResources() []registry.ResourceDefinition { return nil }
+func (p *metricsTestPlugin) GetPrompts() []registry.PromptDefinition     { return nil }
+func (p *metricsTestPlugin) CallTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	return "ok", nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Sampling rates applied while profiling is enabled; block and mutex
+// profiles stay empty unless the runtime samples contention events
+const (
+	profilingBlockRate     = 10000 // Sample one blocking event per 10µs spent blocked
+	profilingMutexFraction = 100   // Sample one in 100 contended mutex events
+)
+
+// setupProfilingRoutes mounts the net/http/pprof handlers under
+// /debug/pprof/ on the admin router, so they share its authentication.
+// Profiles expose the command line and memory contents, so without an admin
+// API key they are only served to local clients.
+func (gs *GatewayServer) setupProfilingRoutes(router *mux.Router) {
+	runtime.SetBlockProfileRate(profilingBlockRate)
+	runtime.SetMutexProfileFraction(profilingMutexFraction)
+
+	// pprof.Index only resolves profiles under /debug/pprof/, so each named
+	// profile is routed explicitly below the /admin prefix
+	pprofRouter := router.PathPrefix("/debug/pprof").Subrouter()
+	if gs.config.AdminAPIKey == "" {
+		gs.logger.Warn("profiling_restricted_to_local_clients",
+			"path", "/admin/debug/pprof/",
+			"reason", "no admin API key configured")
+		pprofRouter.Use(gs.localClientsOnly)
+	}
+	pprofRouter.HandleFunc("/", pprof.Index).Methods("GET")
+	pprofRouter.HandleFunc("/cmdline", pprof.Cmdline).Methods("GET")
+	pprofRouter.HandleFunc("/profile", gs.withProfileWriteDeadline(pprof.Profile, 30)).Methods("GET")
+	pprofRouter.HandleFunc("/symbol", pprof.Symbol).Methods("GET", "POST")
+	pprofRouter.HandleFunc("/trace", gs.withProfileWriteDeadline(pprof.Trace, 1)).Methods("GET")
+	for _, profile := range []string{"heap", "goroutine", "block", "mutex", "allocs", "threadcreate"} {
+		pprofRouter.Handle("/"+profile, pprof.Handler(profile)).Methods("GET")
+	}
+
+	gs.logger.Info("profiling_endpoints_enabled", "path", "/admin/debug/pprof/")
+}
+
+// localClientsOnly rejects requests that did not arrive over loopback or the
+// Unix domain socket
+func (gs *GatewayServer) localClientsOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !localClient(r) {
+			gs.metrics.Inc("profiling_requests_rejected_total")
+			gs.logger.Warn("profiling_request_rejected",
+				"path", r.URL.Path,
+				"remote_addr", r.RemoteAddr)
+			http.Error(w, "profiling is only served to local clients without an admin API key", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// localClient reports whether a request came from this host
+func localClient(r *http.Request) bool {
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok && addr.Network() == "unix" {
+		return true
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// withProfileWriteDeadline extends the write deadline of a sampling profile
+// by its duration, which would otherwise run into the server's WriteTimeout.
+// defaultSeconds matches the duration pprof uses without a seconds parameter.
+func (gs *GatewayServer) withProfileWriteDeadline(next http.HandlerFunc, defaultSeconds float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if gs.config.WriteTimeout > 0 {
+			seconds, err := strconv.ParseFloat(r.FormValue("seconds"), 64)
+			if err != nil || seconds <= 0 {
+				seconds = defaultSeconds
+			}
+			deadline := time.Now().Add(gs.config.WriteTimeout + time.Duration(seconds*float64(time.Second)))
+			if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+				gs.logger.Warn("profiling_write_deadline_failed",
+					"path", r.URL.Path,
+					"error", err)
+			}
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestProfilingEndpoints tests that pprof handlers are mounted behind admin auth only when enabled
+func TestProfilingEndpoints(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	get := func(server *GatewayServer, path, apiKey string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if apiKey != "" {
+			req.Header.Set("X-Admin-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	profiles := []string{
+		"/admin/debug/pprof/",
+		"/admin/debug/pprof/heap",
+		"/admin/debug/pprof/goroutine?debug=1",
+		"/admin/debug/pprof/block",
+		"/admin/debug/pprof/profile?seconds=1",
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		server := NewGatewayServer(ServerConfig{
+			EnableAdminEndpoints: true,
+			EnableProfiling:      true,
+			AdminAPIKey:          "profiling-key",
+			AdminAPIHeader:       "X-Admin-API-Key",
+		}, logger, mockMetrics, validator, healthMgr)
+		defer server.registry.Shutdown()
+		defer runtime.SetBlockProfileRate(0)
+		defer runtime.SetMutexProfileFraction(0)
+
+		for _, path := range profiles {
+			if code := get(server, path, "profiling-key"); code != http.StatusOK {
+				t.Errorf("expected status 200 for %s, got %d", path, code)
+			}
+		}
+		if code := get(server, "/admin/debug/pprof/heap", ""); code != http.StatusUnauthorized {
+			t.Errorf("expected status 401 without the admin key, got %d", code)
+		}
+	})
+
+	t.Run("without an admin key only local clients are served", func(t *testing.T) {
+		server := NewGatewayServer(ServerConfig{
+			EnableAdminEndpoints: true,
+			EnableProfiling:      true,
+		}, logger, mockMetrics, validator, healthMgr)
+		defer server.registry.Shutdown()
+		defer runtime.SetBlockProfileRate(0)
+		defer runtime.SetMutexProfileFraction(0)
+
+		for _, path := range []string{"/admin/debug/pprof/cmdline", "/admin/debug/pprof/heap"} {
+			if code := get(server, path, ""); code != http.StatusForbidden {
+				t.Errorf("expected status 403 for %s from a remote client, got %d", path, code)
+			}
+
+			req := httptest.NewRequest("GET", path, nil)
+			req.RemoteAddr = "127.0.0.1:40000"
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status 200 for %s from loopback, got %d", path, w.Code)
+			}
+		}
+	})
+
+	t.Run("profiles outlast the request and write timeouts", func(t *testing.T) {
+		server := NewGatewayServer(ServerConfig{
+			EnableAdminEndpoints: true,
+			EnableProfiling:      true,
+			RequestTimeout:       200 * time.Millisecond,
+			WriteTimeout:         500 * time.Millisecond,
+		}, logger, mockMetrics, validator, healthMgr)
+		defer server.registry.Shutdown()
+		defer runtime.SetBlockProfileRate(0)
+		defer runtime.SetMutexProfileFraction(0)
+
+		ts := httptest.NewUnstartedServer(server.httpServer.Handler)
+		ts.Config.WriteTimeout = server.config.WriteTimeout
+		ts.Start()
+		defer ts.Close()
+
+		resp, err := http.Get(ts.URL + "/admin/debug/pprof/profile?seconds=1")
+		if err != nil {
+			t.Fatalf("profile request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read profile: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || len(body) == 0 {
+			t.Errorf("expected a profile with status 200, got %d with %d bytes", resp.StatusCode, len(body))
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		server := NewGatewayServer(ServerConfig{EnableAdminEndpoints: true}, logger, mockMetrics, validator, healthMgr)
+		defer server.registry.Shutdown()
+
+		for _, path := range profiles {
+			if code := get(server, path, ""); code != http.StatusNotFound {
+				t.Errorf("expected status 404 for %s, got %d", path, code)
+			}
+		}
+	})
+}
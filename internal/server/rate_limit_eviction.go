@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// defaultRateLimitClientTTL is how long a client may stay idle before its
+	// rate limit state is dropped
+	defaultRateLimitClientTTL = 10 * time.Minute
+
+	// rateLimitClientOverheadBytes estimates the memory held per tracked client
+	// besides its ID: the ClientRateInfo, the pointer to it and the map entry
+	rateLimitClientOverheadBytes = 160
+)
+
+// RateLimiterStats describes the client state held by the rate limiter
+type RateLimiterStats struct {
+	TrackedClients       int    `json:"tracked_clients"`
+	EstimatedMemoryBytes int64  `json:"estimated_memory_bytes"`
+	EvictedClients       uint64 `json:"evicted_clients"`
+}
+
+// Stats returns the number of tracked clients and their estimated memory use
+func (srl *SimpleRateLimiter) Stats() RateLimiterStats {
+	srl.mutex.RLock()
+	defer srl.mutex.RUnlock()
+	return srl.statsLocked()
+}
+
+func (srl *SimpleRateLimiter) statsLocked() RateLimiterStats {
+	return RateLimiterStats{
+		TrackedClients:       len(srl.clients),
+		EstimatedMemoryBytes: srl.clientIDBytes + int64(len(srl.clients))*rateLimitClientOverheadBytes,
+		EvictedClients:       srl.evictedClients,
+	}
+}
+
+// recordClientGaugesLocked publishes the tracked client gauges; srl.mutex must be held
+func (srl *SimpleRateLimiter) recordClientGaugesLocked() {
+	stats := srl.statsLocked()
+	srl.metrics.Set("rate_limit_tracked_clients", float64(stats.TrackedClients))
+	srl.metrics.Set("rate_limit_memory_bytes", float64(stats.EstimatedMemoryBytes))
+}
+
+// evictIdleClients drops clients whose last request is older than the TTL.
+// An evicted client that returns starts with a fresh window.
+func (srl *SimpleRateLimiter) evictIdleClients(now time.Time) int {
+	srl.mutex.Lock()
+	defer srl.mutex.Unlock()
+
+	evicted := 0
+	for clientID, info := range srl.clients {
+		info.mutex.Lock()
+		if now.Sub(info.lastRequest) >= srl.clientTTL {
+			// Requests already holding this entry retry against a fresh one
+			info.evicted = true
+			delete(srl.clients, clientID)
+			srl.clientIDBytes -= int64(len(clientID))
+			evicted++
+		}
+		info.mutex.Unlock()
+	}
+	srl.evictedClients += uint64(evicted)
+
+	srl.recordClientGaugesLocked()
+	if evicted > 0 {
+		srl.metrics.Add("rate_limit_evicted_clients_total", float64(evicted))
+		srl.logger.Debug("rate_limit_idle_clients_evicted",
+			"evicted", evicted,
+			"remaining", len(srl.clients))
+	}
+	return evicted
+}
+
+// startEviction sweeps idle clients in the background until Stop is called
+func (srl *SimpleRateLimiter) startEviction() {
+	interval := srl.clientTTL / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				srl.evictIdleClients(now)
+			case <-srl.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background eviction sweep
+func (srl *SimpleRateLimiter) Stop() {
+	srl.stopOnce.Do(func() { close(srl.stop) })
+}
+
+// writeRateLimiterMetrics writes the rate limiter client count and memory gauges
+func (gs *GatewayServer) writeRateLimiterMetrics(w io.Writer) error {
+	limiter, ok := gs.rateLimiter.(*SimpleRateLimiter)
+	if !ok {
+		return nil
+	}
+	stats := limiter.Stats()
+
+	fmt.Fprintf(w, "# HELP mcpeg_rate_limit_tracked_clients Number of clients with rate limit state\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_rate_limit_tracked_clients gauge\n")
+	fmt.Fprintf(w, "mcpeg_rate_limit_tracked_clients %d\n", stats.TrackedClients)
+
+	fmt.Fprintf(w, "# HELP mcpeg_rate_limit_memory_bytes Estimated memory held by rate limit client state\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_rate_limit_memory_bytes gauge\n")
+	fmt.Fprintf(w, "mcpeg_rate_limit_memory_bytes %d\n", stats.EstimatedMemoryBytes)
+
+	fmt.Fprintf(w, "# HELP mcpeg_rate_limit_evicted_clients_total Total number of idle clients evicted from the rate limiter\n")
+	fmt.Fprintf(w, "# TYPE mcpeg_rate_limit_evicted_clients_total counter\n")
+	fmt.Fprintf(w, "mcpeg_rate_limit_evicted_clients_total %d\n", stats.EvictedClients)
+
+	return nil
+}
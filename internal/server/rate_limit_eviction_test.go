@@ -0,0 +1,101 @@
+package server
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRateLimitClientEviction tests that idle clients are evicted and the client gauges follow the live set
+func TestRateLimitClientEviction(t *testing.T) {
+	logger := logging.New("test")
+	metrics := &gaugeRecordingMetrics{}
+	validator := validation.NewValidator(logger, metrics)
+	healthMgr := health.NewHealthManager(logger, metrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{
+		EnableRateLimit:    true,
+		RateLimitRPS:       2,
+		RateLimitClientTTL: time.Minute,
+	}
+	server := NewGatewayServer(config, logger, metrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	limiter := server.rateLimiter.(*SimpleRateLimiter)
+	defer limiter.Stop()
+
+	allow := func(client string) bool {
+		allowed, _, err := limiter.IsAllowed(client, httptest.NewRequest("POST", "/mcp", nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return allowed
+	}
+
+	for _, client := range []string{"idle-a", "idle-b", "active"} {
+		allow(client)
+	}
+	if got := metrics.gauge("rate_limit_tracked_clients"); got != 3 {
+		t.Fatalf("expected client gauge of 3, got %v", got)
+	}
+	if got := metrics.gauge("rate_limit_memory_bytes"); got <= 0 {
+		t.Errorf("expected a positive memory estimate, got %v", got)
+	}
+
+	t.Run("idle clients are evicted", func(t *testing.T) {
+		limiter.mutex.Lock()
+		limiter.clients["active"].lastRequest = time.Now().Add(time.Minute)
+		limiter.mutex.Unlock()
+
+		if evicted := limiter.evictIdleClients(time.Now().Add(90 * time.Second)); evicted != 2 {
+			t.Errorf("expected 2 idle clients evicted, got %d", evicted)
+		}
+		stats := limiter.Stats()
+		if stats.TrackedClients != 1 || stats.EvictedClients != 2 {
+			t.Errorf("expected 1 tracked and 2 evicted clients, got %+v", stats)
+		}
+		if got := metrics.gauge("rate_limit_tracked_clients"); got != 1 {
+			t.Errorf("expected client gauge of 1, got %v", got)
+		}
+		if got := metrics.gauge("rate_limit_memory_bytes"); got != float64(stats.EstimatedMemoryBytes) {
+			t.Errorf("expected memory gauge of %d, got %v", stats.EstimatedMemoryBytes, got)
+		}
+	})
+
+	t.Run("returning clients start with a fresh window", func(t *testing.T) {
+		if !allow("throttled") || !allow("throttled") || allow("throttled") {
+			t.Fatal("expected the third request in the window to be rejected")
+		}
+
+		limiter.evictIdleClients(time.Now().Add(2 * time.Minute))
+		if got := metrics.gauge("rate_limit_tracked_clients"); got != 0 {
+			t.Errorf("expected client gauge of 0 after eviction, got %v", got)
+		}
+		if !allow("throttled") {
+			t.Error("expected an evicted client to start with a fresh window")
+		}
+	})
+
+	t.Run("gauges are exported to Prometheus", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := server.writeRateLimiterMetrics(&buf); err != nil {
+			t.Fatalf("failed to write metrics: %v", err)
+		}
+		for _, line := range []string{
+			"mcpeg_rate_limit_tracked_clients 1",
+			"mcpeg_rate_limit_evicted_clients_total 4",
+			"mcpeg_rate_limit_memory_bytes ",
+		} {
+			if !strings.Contains(buf.String(), line) {
+				t.Errorf("expected %q in output:\n%s", line, buf.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// RateLimitUnlimited is the override value that exempts a client from rate limiting
+const RateLimitUnlimited = -1
+
+// RateLimitOverrider is implemented by rate limiters that support per-client limits
+type RateLimitOverrider interface {
+	SetOverride(key string, rps int) error
+	RemoveOverride(key string) bool
+	GetOverrides() map[string]int
+}
+
+// rateLimitOverride is a parsed per-client limit. Keys are an exact client ID,
+// a CIDR block such as 10.0.0.0/8, or a prefix ending in * such as internal-*.
+type rateLimitOverride struct {
+	key     string
+	rps     int
+	network *net.IPNet
+	prefix  string
+	isGlob  bool
+}
+
+// parseRateLimitOverride validates an override key and limit
+func parseRateLimitOverride(key string, rps int) (rateLimitOverride, error) {
+	if key == "" {
+		return rateLimitOverride{}, fmt.Errorf("override key must not be empty")
+	}
+	if rps <= 0 && rps != RateLimitUnlimited {
+		return rateLimitOverride{}, fmt.Errorf("override for %s must be a positive RPS or unlimited, got %d", key, rps)
+	}
+
+	override := rateLimitOverride{key: key, rps: rps}
+
+	switch {
+	case strings.HasSuffix(key, "*"):
+		override.prefix = strings.TrimSuffix(key, "*")
+		override.isGlob = true
+	case strings.Contains(key, "/"):
+		_, network, err := net.ParseCIDR(key)
+		if err != nil {
+			return rateLimitOverride{}, fmt.Errorf("invalid CIDR override %s: %w", key, err)
+		}
+		override.network = network
+	}
+
+	return override, nil
+}
+
+// specificity ranks matches so exact IDs beat CIDR blocks, which beat prefixes,
+// and longer CIDR masks or prefixes beat shorter ones
+func (o rateLimitOverride) specificity() int {
+	switch {
+	case o.network != nil:
+		ones, _ := o.network.Mask.Size()
+		return 1000 + ones
+	case o.isGlob:
+		return len(o.prefix)
+	default:
+		return 2000
+	}
+}
+
+func (o rateLimitOverride) matches(clientID string) bool {
+	switch {
+	case o.network != nil:
+		ip := net.ParseIP(clientID)
+		if ip == nil {
+			// Subnet-bucketed clients match when their whole subnet is inside the override
+			_, subnet, err := net.ParseCIDR(clientID)
+			if err != nil {
+				return false
+			}
+			subnetOnes, _ := subnet.Mask.Size()
+			overrideOnes, _ := o.network.Mask.Size()
+			return subnetOnes >= overrideOnes && o.network.Contains(subnet.IP)
+		}
+		return o.network.Contains(ip)
+	case o.isGlob:
+		return strings.HasPrefix(clientID, o.prefix)
+	default:
+		return o.key == clientID
+	}
+}
+
+// SetOverride adds or replaces the limit for a client ID, CIDR block or prefix
+func (srl *SimpleRateLimiter) SetOverride(key string, rps int) error {
+	override, err := parseRateLimitOverride(key, rps)
+	if err != nil {
+		return err
+	}
+
+	srl.mutex.Lock()
+	defer srl.mutex.Unlock()
+
+	overrides := make([]rateLimitOverride, 0, len(srl.overrides)+1)
+	for _, existing := range srl.overrides {
+		if existing.key != key {
+			overrides = append(overrides, existing)
+		}
+	}
+	overrides = append(overrides, override)
+
+	// Most specific first so limitFor can stop at the first match
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return overrides[i].specificity() > overrides[j].specificity()
+	})
+	srl.overrides = overrides
+
+	srl.logger.Info("rate_limit_override_set", "key", key, "rps", rps)
+	return nil
+}
+
+// RemoveOverride deletes the limit for key, reporting whether it existed
+func (srl *SimpleRateLimiter) RemoveOverride(key string) bool {
+	srl.mutex.Lock()
+	defer srl.mutex.Unlock()
+
+	for i, existing := range srl.overrides {
+		if existing.key == key {
+			srl.overrides = append(srl.overrides[:i:i], srl.overrides[i+1:]...)
+			srl.logger.Info("rate_limit_override_removed", "key", key)
+			return true
+		}
+	}
+	return false
+}
+
+// GetOverrides returns all configured overrides keyed by client ID, CIDR or prefix
+func (srl *SimpleRateLimiter) GetOverrides() map[string]int {
+	srl.mutex.RLock()
+	defer srl.mutex.RUnlock()
+
+	result := make(map[string]int, len(srl.overrides))
+	for _, override := range srl.overrides {
+		result[override.key] = override.rps
+	}
+	return result
+}
+
+// limitFor returns the effective limit for a client (assumes lock is held)
+func (srl *SimpleRateLimiter) limitFor(clientID string) int {
+	for _, override := range srl.overrides {
+		if override.matches(clientID) {
+			return override.rps
+		}
+	}
+	return srl.limit
+}
+
+// GetClientLimit returns the effective limit for a client, or RateLimitUnlimited
+func (srl *SimpleRateLimiter) GetClientLimit(clientID string) int {
+	srl.mutex.RLock()
+	defer srl.mutex.RUnlock()
+
+	return srl.limitFor(clientID)
+}
+
+// Admin API handlers for rate limit overrides
+
+func (gs *GatewayServer) handleListRateLimitOverrides(w http.ResponseWriter, r *http.Request) {
+	overrider, ok := gs.rateLimiter.(RateLimitOverrider)
+	if !ok {
+		gs.writeRateLimitOverridesUnsupported(w)
+		return
+	}
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"default_rps": gs.rateLimiter.GetLimit(),
+		"overrides":   overrider.GetOverrides(),
+	})
+}
+
+// handleSetRateLimitOverride upserts one override. The rps field is a positive
+// number or the string "unlimited".
+func (gs *GatewayServer) handleSetRateLimitOverride(w http.ResponseWriter, r *http.Request) {
+	overrider, ok := gs.rateLimiter.(RateLimitOverrider)
+	if !ok {
+		gs.writeRateLimitOverridesUnsupported(w)
+		return
+	}
+
+	var req struct {
+		Client string      `json:"client"`
+		RPS    interface{} `json:"rps"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_request_body",
+			"message": "Failed to parse JSON request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var rps int
+	switch value := req.RPS.(type) {
+	case float64:
+		rps = int(value)
+	case string:
+		if value == "unlimited" {
+			rps = RateLimitUnlimited
+		}
+	}
+
+	if err := overrider.SetOverride(req.Client, rps); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_rate_limit_override",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	gs.logger.Info("admin_rate_limit_override_updated",
+		"client", req.Client,
+		"rps", rps,
+		"remote_addr", r.RemoteAddr)
+	gs.metrics.Inc("admin_api_rate_limit_override_updates_total")
+
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"client": req.Client,
+		"rps":    rps,
+	})
+}
+
+// handleDeleteRateLimitOverride removes the override named by the client query parameter
+func (gs *GatewayServer) handleDeleteRateLimitOverride(w http.ResponseWriter, r *http.Request) {
+	overrider, ok := gs.rateLimiter.(RateLimitOverrider)
+	if !ok {
+		gs.writeRateLimitOverridesUnsupported(w)
+		return
+	}
+
+	client := r.URL.Query().Get("client")
+	if !overrider.RemoveOverride(client) {
+		w.WriteHeader(http.StatusNotFound)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "override_not_found",
+			"message": fmt.Sprintf("No rate limit override for client: %s", client),
+		})
+		return
+	}
+
+	gs.logger.Info("admin_rate_limit_override_removed",
+		"client", client,
+		"remote_addr", r.RemoteAddr)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (gs *GatewayServer) writeRateLimitOverridesUnsupported(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotImplemented)
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"error":   "rate_limit_overrides_unsupported",
+		"message": "The configured rate limiter does not support per-client overrides",
+	})
+}
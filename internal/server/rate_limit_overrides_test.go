@@ -0,0 +1,122 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRateLimitOverrides tests per-client limits consulted before the global limit
+func TestRateLimitOverrides(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{
+		EnableRateLimit:      true,
+		RateLimitRPS:         5,
+		EnableAdminEndpoints: true,
+		RateLimitOverrides: map[string]int{
+			"trusted-client": 50,
+			"10.0.0.0/8":     RateLimitUnlimited,
+			"partner-*":      20,
+		},
+	}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	handler := server.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// send issues n requests from client and returns how many were allowed
+	send := func(client string, n int) int {
+		allowed := 0
+		for i := 0; i < n; i++ {
+			req := httptest.NewRequest("POST", "/mcp", nil)
+			req.Header.Set("X-Real-IP", client)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code == http.StatusOK {
+				allowed++
+			}
+		}
+		return allowed
+	}
+
+	t.Run("default clients use the global limit", func(t *testing.T) {
+		if allowed := send("public-client", 20); allowed != 5 {
+			t.Errorf("expected 5 requests allowed at the global limit, got %d", allowed)
+		}
+	})
+
+	t.Run("higher override is not throttled at the global limit", func(t *testing.T) {
+		if allowed := send("trusted-client", 30); allowed != 30 {
+			t.Errorf("expected all 30 requests allowed, got %d", allowed)
+		}
+	})
+
+	t.Run("prefix override applies", func(t *testing.T) {
+		if allowed := send("partner-acme", 25); allowed != 20 {
+			t.Errorf("expected 20 requests allowed for prefix override, got %d", allowed)
+		}
+	})
+
+	t.Run("allowlisted CIDR is never throttled", func(t *testing.T) {
+		if allowed := send("10.1.2.3", 1000); allowed != 1000 {
+			t.Errorf("expected allowlisted client never to be throttled, got %d of 1000", allowed)
+		}
+	})
+
+	t.Run("exact match beats CIDR", func(t *testing.T) {
+		limiter := server.rateLimiter.(*SimpleRateLimiter)
+		if err := limiter.SetOverride("10.9.9.9", 2); err != nil {
+			t.Fatalf("failed to set override: %v", err)
+		}
+		if limit := limiter.GetClientLimit("10.9.9.9"); limit != 2 {
+			t.Errorf("expected exact override of 2, got %d", limit)
+		}
+		if limit := limiter.GetClientLimit("10.9.9.8"); limit != RateLimitUnlimited {
+			t.Errorf("expected CIDR override to remain unlimited, got %d", limit)
+		}
+	})
+
+	t.Run("overrides are updatable via admin API", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"client": "public-client-2", "rps": "unlimited"})
+		req := httptest.NewRequest("PUT", "/admin/ratelimit/overrides", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if allowed := send("public-client-2", 100); allowed != 100 {
+			t.Errorf("expected updated client to be unlimited, got %d of 100", allowed)
+		}
+
+		body, _ = json.Marshal(map[string]interface{}{"client": "bad", "rps": 0})
+		req = httptest.NewRequest("PUT", "/admin/ratelimit/overrides", bytes.NewReader(body))
+		w = httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400 for invalid override, got %d", w.Code)
+		}
+
+		req = httptest.NewRequest("DELETE", "/admin/ratelimit/overrides?client=public-client-2", nil)
+		w = httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Errorf("expected status 204 on delete, got %d", w.Code)
+		}
+		if _, exists := server.rateLimiter.(RateLimitOverrider).GetOverrides()["public-client-2"]; exists {
+			t.Error("expected override to be removed")
+		}
+	})
+}
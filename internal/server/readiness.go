@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReadinessState is a stage in the gateway startup lifecycle
+type ReadinessState string
+
+const (
+	// ReadinessStarting means plugin initialization has not completed yet
+	ReadinessStarting ReadinessState = "starting"
+	// ReadinessNotReady means plugins are initialized but a readiness condition is unmet
+	ReadinessNotReady ReadinessState = "not_ready"
+	// ReadinessReady means the gateway can serve traffic
+	ReadinessReady ReadinessState = "ready"
+	// ReadinessStopping means the gateway is shutting down
+	ReadinessStopping ReadinessState = "stopping"
+)
+
+// readinessPollInterval is how often Start re-checks readiness while delaying the listener
+const readinessPollInterval = 100 * time.Millisecond
+
+// ReadinessReport describes the current readiness state and any unmet conditions
+type ReadinessReport struct {
+	State           ReadinessState `json:"state"`
+	HealthyServices int            `json:"healthy_services"`
+	Reasons         []string       `json:"reasons,omitempty"`
+}
+
+// initializePlugins loads plugins and marks plugin initialization complete for readiness
+func (gs *GatewayServer) initializePlugins(ctx context.Context) error {
+	if unknown := gs.pluginIntegration.UnknownPlugins(gs.config.ReadinessCriticalPlugins); len(unknown) > 0 {
+		gs.logger.Error("unknown_readiness_critical_plugins", "plugins", unknown)
+		return fmt.Errorf("unknown plugin(s) in readiness critical plugins: %s", strings.Join(unknown, ", "))
+	}
+	if err := gs.pluginIntegration.InitializePlugins(ctx); err != nil {
+		return err
+	}
+	gs.refreshAllToolSchemas()
+
+	// Baselines let the first reload of each plugin report what it changed
+	for _, name := range gs.pluginIntegration.GetPluginManager().GetPlugins() {
+		gs.detectCapabilityChanges(name)
+	}
+
+	gs.readinessMutex.Lock()
+	if gs.readinessState == ReadinessStarting {
+		gs.readinessState = ReadinessNotReady
+	}
+	gs.readinessMutex.Unlock()
+
+	gs.logger.Info("readiness_plugins_initialized")
+	gs.checkReadiness(ctx)
+	return nil
+}
+
+// checkReadiness evaluates readiness conditions and records state transitions.
+// The gateway is ready once plugins are initialized, every critical plugin
+// passes its health check, at least one healthy service is registered (or,
+// when required services are configured, each of them has enough healthy
+// instances), any backends the backend check requires accept connections
+// and maintenance mode is off.
+func (gs *GatewayServer) checkReadiness(ctx context.Context) ReadinessReport {
+	return gs.evaluateReadiness(ctx, true)
+}
+
+// evaluateReadiness evaluates the readiness conditions, leaving out those on
+// registered services unless includeServices is set. Only full evaluations
+// record state transitions. The conditions are checked without holding
+// readinessMutex, so concurrent probes do not queue behind plugin health
+// checks or backend dials.
+func (gs *GatewayServer) evaluateReadiness(ctx context.Context, includeServices bool) ReadinessReport {
+	gs.readinessMutex.Lock()
+	state := gs.readinessState
+	gs.readinessMutex.Unlock()
+
+	report := ReadinessReport{
+		HealthyServices: len(gs.registry.GetHealthyServices()),
+	}
+
+	switch state {
+	case ReadinessStarting:
+		report.State = ReadinessStarting
+		report.Reasons = append(report.Reasons, "plugin initialization in progress")
+		return report
+	case ReadinessStopping:
+		report.State = ReadinessStopping
+		report.Reasons = append(report.Reasons, "server is shutting down")
+		return report
+	}
+
+	manager := gs.pluginIntegration.GetPluginManager()
+	failed := manager.FailedPlugins()
+	for _, name := range gs.config.ReadinessCriticalPlugins {
+		plugin, exists := manager.GetPlugin(name)
+		if !exists {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("critical plugin %s is not loaded", name))
+			continue
+		}
+		if reason, isFailed := failed[name]; isFailed {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("critical plugin %s failed to initialize: %s", name, reason))
+			continue
+		}
+		if err := plugin.HealthCheck(ctx); err != nil {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("critical plugin %s is unhealthy: %v", name, err))
+		}
+	}
+
+	if includeServices {
+		if len(gs.config.ReadinessRequiredServices) > 0 {
+			report.Reasons = append(report.Reasons, gs.requiredServiceReasons()...)
+		} else if report.HealthyServices == 0 {
+			report.Reasons = append(report.Reasons, "no healthy services registered")
+		}
+
+		if gs.config.ReadinessBackendCheck.Enabled {
+			report.Reasons = append(report.Reasons, gs.unreachableBackendReasons(ctx)...)
+		}
+	}
+
+	if gs.inMaintenance() {
+		report.Reasons = append(report.Reasons, "maintenance mode is enabled")
+	}
+
+	report.State = ReadinessReady
+	if len(report.Reasons) > 0 {
+		report.State = ReadinessNotReady
+	}
+
+	if includeServices {
+		gs.recordReadiness(&report)
+	}
+	return report
+}
+
+// recordReadiness records the state of a full evaluation, unless shutdown
+// began while its conditions were being checked
+func (gs *GatewayServer) recordReadiness(report *ReadinessReport) {
+	gs.readinessMutex.Lock()
+	defer gs.readinessMutex.Unlock()
+
+	if gs.readinessState == ReadinessStopping {
+		report.State = ReadinessStopping
+		report.Reasons = []string{"server is shutting down"}
+		return
+	}
+	if report.State != gs.readinessState {
+		gs.logger.Info("readiness_state_changed",
+			"from", gs.readinessState,
+			"to", report.State,
+			"healthy_services", report.HealthyServices,
+			"reasons", report.Reasons)
+		gs.metrics.Inc("server_readiness_transitions_total", "state", string(report.State))
+		gs.readinessState = report.State
+	}
+}
+
+// setReadinessStopping marks the gateway as shutting down so probes fail immediately
+func (gs *GatewayServer) setReadinessStopping() {
+	gs.readinessMutex.Lock()
+	defer gs.readinessMutex.Unlock()
+
+	if gs.readinessState != ReadinessStopping {
+		gs.logger.Info("readiness_state_changed",
+			"from", gs.readinessState,
+			"to", ReadinessStopping)
+		gs.metrics.Inc("server_readiness_transitions_total", "state", string(ReadinessStopping))
+		gs.readinessState = ReadinessStopping
+	}
+}
+
+// waitForReadiness blocks until the gateway is ready or the configured readiness
+// timeout elapses. A timeout is logged but does not prevent startup. Services
+// usually register through the listener this holds back, so conditions on
+// registered services are left to GET /health/ready.
+func (gs *GatewayServer) waitForReadiness(ctx context.Context) error {
+	if gs.config.ReadinessTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gs.config.ReadinessTimeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		report := gs.evaluateReadiness(ctx, false)
+		if report.State == ReadinessReady {
+			gs.logger.Info("readiness_wait_completed", "duration", time.Since(start))
+			return nil
+		}
+		// Maintenance never clears on its own; open the listener so the
+		// admin API can lift it
+		if gs.inMaintenance() {
+			gs.logger.Warn("readiness_wait_skipped_for_maintenance", "duration", time.Since(start))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			gs.logger.Warn("readiness_wait_timed_out",
+				"duration", time.Since(start),
+				"state", report.State,
+				"reasons", report.Reasons)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
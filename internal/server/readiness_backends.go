@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReadinessDialTimeout = time.Second
+	defaultReadinessDialTTL     = 10 * time.Second
+)
+
+// ReadinessBackendCheckConfig makes readiness require that the gateway can
+// open a TCP connection to at least one backend of each service type, so a
+// gateway cut off from its backends stops receiving traffic. Dial results
+// are reused for CacheTTL to keep probes cheap. Services without an HTTP
+// endpoint, such as in-process plugins, are not dialed.
+type ReadinessBackendCheckConfig struct {
+	Enabled      bool          `yaml:"enabled" json:"enabled"`
+	ServiceTypes []string      `yaml:"service_types" json:"service_types"` // Empty checks every type with HTTP backends
+	DialTimeout  time.Duration `yaml:"dial_timeout" json:"dial_timeout"`   // 1s by default
+	CacheTTL     time.Duration `yaml:"cache_ttl" json:"cache_ttl"`         // 10s by default
+}
+
+// Validate checks the service types and durations
+func (c ReadinessBackendCheckConfig) Validate() error {
+	for _, serviceType := range c.ServiceTypes {
+		if strings.TrimSpace(serviceType) == "" {
+			return fmt.Errorf("empty service type")
+		}
+	}
+	if c.DialTimeout < 0 {
+		return fmt.Errorf("dial_timeout must not be negative, got %s", c.DialTimeout)
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("cache_ttl must not be negative, got %s", c.CacheTTL)
+	}
+	return nil
+}
+
+// backendDialCache remembers recent dial results per backend address
+type backendDialCache struct {
+	mu      sync.Mutex
+	results map[string]backendDialResult
+}
+
+type backendDialResult struct {
+	err     error
+	checked time.Time
+}
+
+func newBackendDialCache() *backendDialCache {
+	return &backendDialCache{results: make(map[string]backendDialResult)}
+}
+
+// backendDialAddress returns the host:port of an HTTP(S) endpoint
+func backendDialAddress(endpoint string) (string, bool) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "", false
+	}
+
+	port := u.Port()
+	switch u.Scheme {
+	case "http":
+		if port == "" {
+			port = "80"
+		}
+	case "https":
+		if port == "" {
+			port = "443"
+		}
+	default:
+		return "", false
+	}
+	return net.JoinHostPort(u.Hostname(), port), true
+}
+
+// dialBackend connects to a backend address, reusing a result younger than
+// the cache TTL
+func (gs *GatewayServer) dialBackend(ctx context.Context, address string) error {
+	config := gs.config.ReadinessBackendCheck
+	ttl := config.CacheTTL
+	if ttl == 0 {
+		ttl = defaultReadinessDialTTL
+	}
+	timeout := config.DialTimeout
+	if timeout == 0 {
+		timeout = defaultReadinessDialTimeout
+	}
+
+	cache := gs.backendDials
+	cache.mu.Lock()
+	cached, exists := cache.results[address]
+	cache.mu.Unlock()
+	if exists && time.Since(cached.checked) < ttl {
+		return cached.err
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err == nil {
+		conn.Close()
+	} else {
+		gs.metrics.Inc("readiness_backend_dial_failures_total")
+		gs.logger.Warn("readiness_backend_unreachable",
+			"address", address,
+			"error", err)
+	}
+
+	cache.mu.Lock()
+	cache.results[address] = backendDialResult{err: err, checked: time.Now()}
+	cache.mu.Unlock()
+	return err
+}
+
+// unreachableBackendReasons returns a readiness reason for each checked
+// service type without a backend the gateway can connect to. Uncached
+// backends are dialed in parallel, so a probe takes at most one dial timeout.
+func (gs *GatewayServer) unreachableBackendReasons(ctx context.Context) []string {
+	addresses := make(map[string][]string)
+	for _, service := range gs.registry.GetAllServices() {
+		if address, ok := backendDialAddress(service.Endpoint); ok {
+			addresses[service.Type] = append(addresses[service.Type], address)
+		}
+	}
+
+	serviceTypes := gs.config.ReadinessBackendCheck.ServiceTypes
+	if len(serviceTypes) == 0 {
+		for serviceType := range addresses {
+			serviceTypes = append(serviceTypes, serviceType)
+		}
+		sort.Strings(serviceTypes)
+	}
+
+	var unique []string
+	seen := make(map[string]bool)
+	for _, serviceType := range serviceTypes {
+		for _, address := range addresses[serviceType] {
+			if !seen[address] {
+				seen[address] = true
+				unique = append(unique, address)
+			}
+		}
+	}
+
+	dialErrs := make(map[string]error, len(unique))
+	var dialMu sync.Mutex
+	var wg sync.WaitGroup
+	for _, address := range unique {
+		wg.Add(1)
+		go func(address string) {
+			defer wg.Done()
+			err := gs.dialBackend(ctx, address)
+			dialMu.Lock()
+			dialErrs[address] = err
+			dialMu.Unlock()
+		}(address)
+	}
+	wg.Wait()
+
+	var reasons []string
+	for _, serviceType := range serviceTypes {
+		candidates := addresses[serviceType]
+		if len(candidates) == 0 {
+			reasons = append(reasons, fmt.Sprintf("no %s backends registered", serviceType))
+			continue
+		}
+
+		var lastErr error
+		for _, address := range candidates {
+			if lastErr = dialErrs[address]; lastErr == nil {
+				break
+			}
+		}
+		if lastErr != nil {
+			reasons = append(reasons, fmt.Sprintf("no reachable %s backend: %v", serviceType, lastErr))
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestReadinessBackendCheck tests that readiness requires a reachable
+// backend once the backend check is enabled, even while the registry still
+// considers the backends healthy
+func TestReadinessBackendCheck(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		ReadinessBackendCheck: ReadinessBackendCheckConfig{
+			Enabled:     true,
+			DialTimeout: 500 * time.Millisecond,
+			CacheTTL:    time.Nanosecond,
+		},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	if err := server.initializePlugins(context.Background()); err != nil {
+		t.Fatalf("failed to initialize plugins: %v", err)
+	}
+	defer server.pluginIntegration.ShutdownPlugins(context.Background())
+
+	register := func(name, serviceType, endpoint string) {
+		t.Helper()
+		if _, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+			Name:     name,
+			Type:     serviceType,
+			Version:  "1.0.0",
+			Endpoint: endpoint,
+			Protocol: "http",
+		}); err != nil {
+			t.Fatalf("failed to register service %s: %v", name, err)
+		}
+	}
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	register("weather-1", "weather_provider", backend.URL)
+
+	t.Run("reachable backends are ready", func(t *testing.T) {
+		if report := server.checkReadiness(context.Background()); report.State != ReadinessReady {
+			t.Fatalf("expected ready, got %s %v", report.State, report.Reasons)
+		}
+	})
+
+	t.Run("unreachable backends are not ready", func(t *testing.T) {
+		backend.Close()
+
+		report := server.checkReadiness(context.Background())
+		if report.State != ReadinessNotReady {
+			t.Fatalf("expected not_ready, got %s %v", report.State, report.Reasons)
+		}
+		if report.HealthyServices == 0 {
+			t.Fatal("expected the registry to still report healthy services")
+		}
+		if len(report.Reasons) != 1 || !strings.Contains(report.Reasons[0], "no reachable weather_provider backend") {
+			t.Errorf("expected the unreachable service type as reason, got %v", report.Reasons)
+		}
+	})
+
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer second.Close()
+
+	t.Run("one reachable backend per type is enough", func(t *testing.T) {
+		register("weather-2", "weather_provider", second.URL)
+
+		if report := server.checkReadiness(context.Background()); report.State != ReadinessReady {
+			t.Errorf("expected ready, got %s %v", report.State, report.Reasons)
+		}
+	})
+
+	t.Run("concurrent probes agree", func(t *testing.T) {
+		reports := make(chan ReadinessReport, 8)
+		for i := 0; i < cap(reports); i++ {
+			go func() { reports <- server.checkReadiness(context.Background()) }()
+		}
+		for i := 0; i < cap(reports); i++ {
+			if report := <-reports; report.State != ReadinessReady {
+				t.Errorf("expected ready, got %s %v", report.State, report.Reasons)
+			}
+		}
+	})
+
+	t.Run("required service types must be registered", func(t *testing.T) {
+		server.config.ReadinessBackendCheck.ServiceTypes = []string{"weather_provider", "calendar_provider"}
+		defer func() { server.config.ReadinessBackendCheck.ServiceTypes = nil }()
+
+		report := server.checkReadiness(context.Background())
+		if report.State != ReadinessNotReady || len(report.Reasons) != 1 ||
+			!strings.Contains(report.Reasons[0], "calendar_provider") {
+			t.Errorf("expected the missing calendar_provider as reason, got %s %v", report.State, report.Reasons)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReadinessRequiredService makes readiness depend on a service type having
+// at least MinHealthy healthy instances, for dependencies such as an auth
+// provider that the gateway cannot serve without
+type ReadinessRequiredService struct {
+	Type       string `yaml:"type" json:"type"`
+	MinHealthy int    `yaml:"min_healthy" json:"min_healthy"` // 1 by default
+}
+
+// ValidateReadinessRequiredServices checks each service type is named once
+// with a minimum that is not negative
+func ValidateReadinessRequiredServices(required []ReadinessRequiredService) error {
+	seen := make(map[string]bool, len(required))
+	for i, service := range required {
+		if strings.TrimSpace(service.Type) == "" {
+			return fmt.Errorf("required service %d: type is required", i)
+		}
+		if seen[service.Type] {
+			return fmt.Errorf("required service %s is listed more than once", service.Type)
+		}
+		seen[service.Type] = true
+		if service.MinHealthy < 0 {
+			return fmt.Errorf("required service %s: min_healthy must not be negative, got %d", service.Type, service.MinHealthy)
+		}
+	}
+	return nil
+}
+
+// requiredServiceReasons returns a readiness reason for each required
+// service type with fewer healthy instances than its minimum
+func (gs *GatewayServer) requiredServiceReasons() []string {
+	healthy := make(map[string]int)
+	for _, service := range gs.registry.GetHealthyServices() {
+		healthy[service.Type]++
+	}
+
+	var reasons []string
+	for _, required := range gs.config.ReadinessRequiredServices {
+		minHealthy := required.MinHealthy
+		if minHealthy == 0 {
+			minHealthy = 1
+		}
+		if count := healthy[required.Type]; count < minHealthy {
+			reasons = append(reasons, fmt.Sprintf("required service %s has %d healthy instances, needs %d", required.Type, count, minHealthy))
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestReadinessRequiredServices tests that readiness waits for each required
+// service type to have enough healthy instances, however many services of
+// other types are healthy
+func TestReadinessRequiredServices(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		ReadinessRequiredServices: []ReadinessRequiredService{
+			{Type: "auth_provider"},
+			{Type: "search_provider", MinHealthy: 2},
+		},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	if err := server.initializePlugins(context.Background()); err != nil {
+		t.Fatalf("failed to initialize plugins: %v", err)
+	}
+	defer server.pluginIntegration.ShutdownPlugins(context.Background())
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	register := func(name, serviceType string) *registry.RegisteredService {
+		t.Helper()
+		resp, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+			Name:     name,
+			Type:     serviceType,
+			Version:  "1.0.0",
+			Endpoint: backend.URL,
+			Protocol: "http",
+		})
+		if err != nil {
+			t.Fatalf("failed to register service %s: %v", name, err)
+		}
+		service := server.registry.GetService(resp.ServiceID)
+		service.Health = registry.HealthHealthy
+		return service
+	}
+
+	register("weather-1", "weather_provider")
+	register("search-1", "search_provider")
+	register("search-2", "search_provider")
+	auth := register("auth-1", "auth_provider")
+	auth.Health = registry.HealthUnhealthy
+
+	t.Run("unhealthy required type is not ready", func(t *testing.T) {
+		report := server.checkReadiness(context.Background())
+		if report.State != ReadinessNotReady {
+			t.Fatalf("expected not_ready, got %s %v", report.State, report.Reasons)
+		}
+		if report.HealthyServices < 3 {
+			t.Errorf("expected other types to stay healthy, got %d healthy services", report.HealthyServices)
+		}
+		if len(report.Reasons) != 1 || !strings.Contains(report.Reasons[0], "required service auth_provider has 0 healthy instances") {
+			t.Errorf("expected the auth provider as the only reason, got %v", report.Reasons)
+		}
+	})
+
+	t.Run("required types with enough healthy instances are ready", func(t *testing.T) {
+		auth.Health = registry.HealthHealthy
+		if report := server.checkReadiness(context.Background()); report.State != ReadinessReady {
+			t.Fatalf("expected ready, got %s %v", report.State, report.Reasons)
+		}
+	})
+
+	t.Run("too few healthy instances is not ready", func(t *testing.T) {
+		for _, service := range server.registry.GetServicesByType("search_provider")[:1] {
+			service.Health = registry.HealthUnhealthy
+		}
+		report := server.checkReadiness(context.Background())
+		if report.State != ReadinessNotReady || len(report.Reasons) != 1 || !strings.Contains(report.Reasons[0], "search_provider has 1 healthy instances, needs 2") {
+			t.Errorf("expected search_provider below its minimum, got %s %v", report.State, report.Reasons)
+		}
+	})
+
+	t.Run("invalid policies are rejected", func(t *testing.T) {
+		for name, required := range map[string][]ReadinessRequiredService{
+			"missing type":     {{MinHealthy: 1}},
+			"duplicate type":   {{Type: "auth_provider"}, {Type: "auth_provider"}},
+			"negative minimum": {{Type: "auth_provider", MinHealthy: -1}},
+		} {
+			if err := ValidateReadinessRequiredServices(required); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
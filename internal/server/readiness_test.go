@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestReadinessGate tests that readiness only flips to ready after plugin initialization
+func TestReadinessGate(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{
+		EnableHealthEndpoints:    true,
+		ReadinessCriticalPlugins: []string{"memory"},
+	}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	probe := func() (int, map[string]interface{}) {
+		req := httptest.NewRequest("GET", "/health/ready", nil)
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode readiness response: %v", err)
+		}
+		return w.Code, body
+	}
+
+	// A healthy external service alone must not make the gateway ready
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	if _, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+		Name:     "readiness-backend",
+		Type:     "readiness",
+		Version:  "1.0.0",
+		Endpoint: backend.URL,
+		Protocol: "http",
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+
+	code, body := probe()
+	if code != http.StatusServiceUnavailable || body["status"] != "not_ready" {
+		t.Fatalf("expected not_ready before plugin init, got %d %v", code, body)
+	}
+	if body["state"] != string(ReadinessStarting) {
+		t.Errorf("expected state %s before plugin init, got %v", ReadinessStarting, body["state"])
+	}
+
+	if err := server.initializePlugins(context.Background()); err != nil {
+		t.Fatalf("failed to initialize plugins: %v", err)
+	}
+	defer server.pluginIntegration.ShutdownPlugins(context.Background())
+
+	code, body = probe()
+	if code != http.StatusOK || body["state"] != string(ReadinessReady) {
+		t.Fatalf("expected ready after plugin init, got %d %v", code, body)
+	}
+
+	server.setReadinessStopping()
+	code, body = probe()
+	if code != http.StatusServiceUnavailable || body["state"] != string(ReadinessStopping) {
+		t.Errorf("expected not ready while stopping, got %d %v", code, body)
+	}
+}
+
+// TestReadinessWaitIgnoresServices tests that the pre-listen wait does not
+// block on services, which can only register once the listener is open
+func TestReadinessWaitIgnoresServices(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{
+		WaitForReadiness:          true,
+		ReadinessTimeout:          5 * time.Second,
+		ReadinessRequiredServices: []ReadinessRequiredService{{Type: "tool_provider", MinHealthy: 1}},
+	}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	if err := server.initializePlugins(context.Background()); err != nil {
+		t.Fatalf("failed to initialize plugins: %v", err)
+	}
+	defer server.pluginIntegration.ShutdownPlugins(context.Background())
+
+	start := time.Now()
+	if err := server.waitForReadiness(context.Background()); err != nil {
+		t.Fatalf("expected the wait to succeed without services, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to return promptly, took %v", elapsed)
+	}
+
+	report := server.checkReadiness(context.Background())
+	if report.State != ReadinessNotReady || len(report.Reasons) != 1 {
+		t.Errorf("expected /health/ready to still require the service, got %s %v", report.State, report.Reasons)
+	}
+}
+
+// TestReadinessUnknownCriticalPlugin tests that a critical plugin the gateway
+// does not load fails startup instead of blocking readiness forever
+func TestReadinessUnknownCriticalPlugin(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{
+		ReadinessCriticalPlugins: []string{"does-not-exist"},
+		ReadinessTimeout:         200 * time.Millisecond,
+	}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	err := server.initializePlugins(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "does-not-exist") {
+		t.Fatalf("expected plugin initialization to fail naming the unknown plugin, got %v", err)
+	}
+	if loaded := server.pluginIntegration.GetPluginManager().ListPlugins(); len(loaded) != 0 {
+		t.Errorf("expected no plugins to be loaded, got %d", len(loaded))
+	}
+
+	report := server.checkReadiness(context.Background())
+	if report.State != ReadinessStarting {
+		t.Fatalf("expected the gateway to stay in starting, got %s", report.State)
+	}
+
+	start := time.Now()
+	if err := server.waitForReadiness(context.Background()); err == nil {
+		t.Error("expected readiness wait to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected wait bounded by readiness timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// Admin API handlers for registry backup and migration
+
+// handleExportRegistry returns a snapshot of all registered services
+func (gs *GatewayServer) handleExportRegistry(w http.ResponseWriter, r *http.Request) {
+	snapshot := gs.registry.Export()
+
+	gs.logger.Info("admin_registry_exported",
+		"services", len(snapshot.Services),
+		"remote_addr", r.RemoteAddr)
+	gs.metrics.Inc("admin_api_registry_exports_total")
+
+	gs.writeJSONResponse(w, snapshot)
+}
+
+// handleImportRegistry registers the services of a snapshot produced by
+// handleExportRegistry, reporting services that were already present or conflict
+func (gs *GatewayServer) handleImportRegistry(w http.ResponseWriter, r *http.Request) {
+	var snapshot registry.RegistrySnapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_request_body",
+			"message": "Failed to parse JSON request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := gs.registry.Import(r.Context(), snapshot)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_registry_snapshot",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	published := make(map[string]bool)
+	for _, imported := range result.Imported {
+		if service := gs.registry.GetService(imported.ServiceID); service != nil && !published[service.Type] {
+			published[service.Type] = true
+			gs.publishListChanged(service.Type)
+		}
+	}
+
+	gs.logger.Info("admin_registry_imported",
+		"imported", len(result.Imported),
+		"unchanged", len(result.Unchanged),
+		"conflicts", len(result.Conflicts),
+		"remote_addr", r.RemoteAddr)
+	gs.metrics.Inc("admin_api_registry_imports_total")
+
+	gs.writeJSONResponse(w, result)
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRegistryExportImport tests that a registry exported from one gateway imports into another unchanged
+func TestRegistryExportImport(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	newServer := func() *GatewayServer {
+		return NewGatewayServer(ServerConfig{EnableAdminEndpoints: true}, logger, mockMetrics, validator, healthMgr)
+	}
+	source := newServer()
+	defer source.registry.Shutdown()
+	target := newServer()
+	defer target.registry.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	for _, req := range []registry.ServiceRegistrationRequest{
+		{
+			Name:     "search",
+			Type:     "tool_provider",
+			Version:  "1.0.0",
+			Endpoint: backend.URL,
+			Protocol: "http",
+			Tools: []registry.ToolDefinition{{
+				Name:        "search_docs",
+				Description: "Search documents",
+				InputSchema: map[string]interface{}{"type": "object", "required": []string{"query"}},
+			}},
+			Metadata: map[string]interface{}{"region": "eu", "weight": 3},
+			Tags:     []string{"search", "primary"},
+		},
+		{
+			Name:     "files",
+			Type:     "resource_provider",
+			Version:  "2.1.0",
+			Endpoint: backend.URL,
+			Protocol: "http",
+			Resources: []registry.ResourceDefinition{{
+				URI:  "file:///docs",
+				Name: "docs",
+			}},
+		},
+	} {
+		if _, err := source.registry.RegisterService(context.Background(), req); err != nil {
+			t.Fatalf("failed to register %s: %v", req.Name, err)
+		}
+	}
+
+	export := func(t *testing.T, server *GatewayServer) registry.RegistrySnapshot {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/admin/registry/export", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200 from export, got %d: %s", w.Code, w.Body.String())
+		}
+		var snapshot registry.RegistrySnapshot
+		if err := json.Unmarshal(w.Body.Bytes(), &snapshot); err != nil {
+			t.Fatalf("failed to decode snapshot: %v", err)
+		}
+		return snapshot
+	}
+
+	importSnapshot := func(t *testing.T, server *GatewayServer, snapshot registry.RegistrySnapshot) registry.ImportResult {
+		t.Helper()
+		body, _ := json.Marshal(snapshot)
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest("POST", "/admin/registry/import", bytes.NewReader(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200 from import, got %d: %s", w.Code, w.Body.String())
+		}
+		var result registry.ImportResult
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to decode import result: %v", err)
+		}
+		return result
+	}
+
+	snapshot := export(t, source)
+	if len(snapshot.Services) != 2 {
+		t.Fatalf("expected 2 exported services, got %d", len(snapshot.Services))
+	}
+
+	t.Run("imported registry is equivalent to the exported one", func(t *testing.T) {
+		result := importSnapshot(t, target, snapshot)
+		if len(result.Imported) != 2 || len(result.Conflicts) != 0 {
+			t.Fatalf("expected 2 imported services without conflicts, got %+v", result)
+		}
+		for _, imported := range result.Imported {
+			if imported.Health != registry.HealthHealthy {
+				t.Errorf("expected %s to pass its import health check, got %+v", imported.ServiceID, imported)
+			}
+		}
+
+		if got := export(t, target); !reflect.DeepEqual(got.Services, snapshot.Services) {
+			t.Errorf("expected re-exported services to match the original\ngot:  %+v\nwant: %+v", got.Services, snapshot.Services)
+		}
+		if len(target.registry.GetHealthyServices()) != 2 {
+			t.Error("expected imported services to be selectable")
+		}
+	})
+
+	t.Run("importing again is idempotent", func(t *testing.T) {
+		result := importSnapshot(t, target, snapshot)
+		if len(result.Imported) != 0 || len(result.Unchanged) != 2 || len(result.Conflicts) != 0 {
+			t.Errorf("expected both services reported unchanged, got %+v", result)
+		}
+	})
+
+	t.Run("changed definitions are reported as conflicts", func(t *testing.T) {
+		changed := snapshot
+		changed.Services = append([]registry.ServiceSnapshot(nil), snapshot.Services...)
+		changed.Services[0].Version = "9.9.9"
+
+		result := importSnapshot(t, target, changed)
+		if len(result.Conflicts) != 1 || result.Conflicts[0].ServiceID != changed.Services[0].ID {
+			t.Errorf("expected a conflict for %s, got %+v", changed.Services[0].ID, result)
+		}
+		if service := target.registry.GetService(changed.Services[0].ID); service.Version == "9.9.9" {
+			t.Error("expected the conflicting service to be left unchanged")
+		}
+	})
+
+	t.Run("unsupported snapshot versions are rejected", func(t *testing.T) {
+		body, _ := json.Marshal(registry.RegistrySnapshot{Version: 99})
+		w := httptest.NewRecorder()
+		target.httpServer.Handler.ServeHTTP(w, httptest.NewRequest("POST", "/admin/registry/import", bytes.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/router"
+)
+
+// handleRecentRequests returns the most recent MCP requests, newest first,
+// optionally filtered by status (success or error), method and limit
+func (gs *GatewayServer) handleRecentRequests(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := router.RequestHistoryFilter{
+		Status: query.Get("status"),
+		Method: query.Get("method"),
+	}
+
+	if filter.Status != "" && filter.Status != "success" && filter.Status != "error" {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_status",
+			"message": "status must be success or error",
+		})
+		return
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			gs.writeJSONResponse(w, map[string]interface{}{
+				"error":   "invalid_limit",
+				"message": "limit must be a non-negative integer",
+			})
+			return
+		}
+		filter.Limit = n
+	}
+
+	requests, enabled := gs.mcpRouter.RecentRequests(filter)
+	if !enabled {
+		w.WriteHeader(http.StatusNotFound)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "request_history_disabled",
+			"message": "Request history is disabled, enable server.request_history to record requests",
+		})
+		return
+	}
+
+	gs.metrics.Inc("admin_api_request_history_queries_total")
+	gs.writeJSONResponse(w, map[string]interface{}{
+		"requests":  requests,
+		"count":     len(requests),
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
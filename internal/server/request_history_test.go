@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/router"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRecentRequestsEndpoint tests GET /admin/debug/requests filtering and validation
+func TestRecentRequestsEndpoint(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		EnableAdminEndpoints: true,
+		RequestHistory:       router.RequestHistoryConfig{Enabled: true, Size: 10},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+	handler := server.httpServer.Handler
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "method": "initialize",
+		"params": map[string]interface{}{"protocolVersion": "2025-03-26"},
+	})
+	req := httptest.NewRequest("POST", "/mcp", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	query := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	if code, resp := query("/admin/debug/requests?method=initialize&status=success"); code != http.StatusOK || resp["count"] != float64(1) {
+		t.Errorf("expected one successful initialize request, got %d %v", code, resp)
+	}
+	if code, resp := query("/admin/debug/requests?status=error"); code != http.StatusOK || resp["count"] != float64(0) {
+		t.Errorf("expected no failed requests, got %d %v", code, resp)
+	}
+	if code, _ := query("/admin/debug/requests?status=pending"); code != http.StatusBadRequest {
+		t.Errorf("expected an unknown status to be rejected, got %d", code)
+	}
+}
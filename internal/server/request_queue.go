@@ -0,0 +1,183 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestQueueConfig bounds concurrent request handling. Requests beyond
+// MaxConcurrent wait in a FIFO queue for a free slot instead of being rejected
+// immediately; they are rejected only when the queue is full or they have
+// waited longer than MaxWait.
+type RequestQueueConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	MaxConcurrent int           `yaml:"max_concurrent"` // Requests handled at once
+	MaxDepth      int           `yaml:"max_depth"`      // Requests allowed to wait, 0 rejects as soon as all slots are busy
+	MaxWait       time.Duration `yaml:"max_wait"`       // Longest a request may wait, 0 waits until the client gives up
+}
+
+// Validate checks the queue settings when the queue is enabled
+func (c RequestQueueConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxConcurrent <= 0 {
+		return fmt.Errorf("max concurrent requests must be positive, got %d", c.MaxConcurrent)
+	}
+	if c.MaxDepth < 0 {
+		return fmt.Errorf("max queue depth must not be negative, got %d", c.MaxDepth)
+	}
+	if c.MaxWait < 0 {
+		return fmt.Errorf("max queue wait must not be negative, got %s", c.MaxWait)
+	}
+	return nil
+}
+
+var (
+	errRequestQueueFull    = errors.New("request queue is full")
+	errRequestQueueTimeout = errors.New("request waited too long in queue")
+)
+
+// requestQueue hands out a fixed number of slots, queueing callers in arrival
+// order once every slot is taken
+type requestQueue struct {
+	config  RequestQueueConfig
+	mutex   sync.Mutex
+	active  int
+	waiters *list.List // of chan struct{}, closed when the waiter is handed a slot
+}
+
+func newRequestQueue(config RequestQueueConfig) *requestQueue {
+	return &requestQueue{
+		config:  config,
+		waiters: list.New(),
+	}
+}
+
+// acquire takes a slot, waiting in the queue if none is free. The caller must
+// call release once it no longer needs the slot.
+func (q *requestQueue) acquire(ctx context.Context) error {
+	q.mutex.Lock()
+	if q.active < q.config.MaxConcurrent && q.waiters.Len() == 0 {
+		q.active++
+		q.mutex.Unlock()
+		return nil
+	}
+	if q.waiters.Len() >= q.config.MaxDepth {
+		q.mutex.Unlock()
+		return errRequestQueueFull
+	}
+	granted := make(chan struct{})
+	element := q.waiters.PushBack(granted)
+	q.mutex.Unlock()
+
+	var timeout <-chan time.Time
+	if q.config.MaxWait > 0 {
+		timer := time.NewTimer(q.config.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = errRequestQueueTimeout
+	}
+
+	q.mutex.Lock()
+	select {
+	case <-granted:
+		// A slot was handed over while giving up; pass it on
+		q.mutex.Unlock()
+		q.release()
+	default:
+		q.waiters.Remove(element)
+		q.mutex.Unlock()
+	}
+	return err
+}
+
+// release frees a slot, handing it directly to the longest waiting request
+func (q *requestQueue) release() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if front := q.waiters.Front(); front != nil {
+		q.waiters.Remove(front)
+		close(front.Value.(chan struct{}))
+		return
+	}
+	q.active--
+}
+
+// depth returns the number of requests waiting for a slot
+func (q *requestQueue) depth() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.waiters.Len()
+}
+
+// requestQueueMiddleware limits concurrent requests, queueing the excess up to
+// the configured depth and wait. Health, metrics and admin endpoints bypass
+// the queue so the gateway stays observable and manageable under load, as do
+// notification streams, which would otherwise hold a slot while idle.
+func (gs *GatewayServer) requestQueueMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" || r.URL.Path == "/health" ||
+			strings.HasPrefix(r.URL.Path, "/health/") || strings.HasPrefix(r.URL.Path, "/admin/") ||
+			r.URL.Path == NotificationStreamPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		err := gs.requestQueue.acquire(r.Context())
+		waited := time.Since(start)
+		gs.metrics.Set("http_request_queue_depth", float64(gs.requestQueue.depth()))
+
+		if err != nil {
+			reason := "queue_full"
+			switch {
+			case errors.Is(err, errRequestQueueTimeout):
+				reason = "queue_timeout"
+			case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+				reason = "client_cancelled"
+			}
+
+			gs.metrics.Inc("http_request_queue_rejections_total", "reason", reason)
+			gs.logger.Warn("request_queue_rejected",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"request_id", r.Header.Get(gs.config.RequestIDHeader),
+				"reason", reason,
+				"waited", waited)
+
+			if reason == "client_cancelled" {
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			gs.writeJSONResponse(w, map[string]interface{}{
+				"error":   reason,
+				"message": "Server is at capacity. Please try again later.",
+			})
+			return
+		}
+		defer gs.requestQueue.release()
+
+		gs.metrics.Observe("http_request_queue_wait_seconds", waited.Seconds())
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRequestQueue tests that requests beyond the concurrency limit queue and
+// drain in order, and that a full queue rejects with 503
+func TestRequestQueue(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		RequestQueue: RequestQueueConfig{
+			Enabled:       true,
+			MaxConcurrent: 1,
+			MaxDepth:      2,
+			MaxWait:       5 * time.Second,
+		},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	unblock := make(chan struct{})
+	started := make(chan string, 10)
+	handler := server.requestQueueMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- r.URL.Query().Get("n")
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(n string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/mcp?n="+n, nil))
+		return w
+	}
+
+	waitForDepth := func(t *testing.T, depth int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for server.requestQueue.depth() != depth {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected queue depth %d, got %d", depth, server.requestQueue.depth())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("spike queues and drains in order", func(t *testing.T) {
+		var wg sync.WaitGroup
+		codes := make([]int, 3)
+		for i, n := range []string{"1", "2", "3"} {
+			wg.Add(1)
+			go func(i int, n string) {
+				defer wg.Done()
+				codes[i] = send(n).Code
+			}(i, n)
+			if i == 0 {
+				<-started
+			} else {
+				waitForDepth(t, i)
+			}
+		}
+
+		t.Run("full queue rejects with 503", func(t *testing.T) {
+			w := send("4")
+			if w.Code != http.StatusServiceUnavailable {
+				t.Fatalf("expected status 503, got %d", w.Code)
+			}
+			if w.Header().Get("Retry-After") == "" {
+				t.Error("expected Retry-After header on rejection")
+			}
+		})
+
+		close(unblock)
+		wg.Wait()
+
+		for i, code := range codes {
+			if code != http.StatusOK {
+				t.Errorf("expected queued request %d to succeed, got %d", i+1, code)
+			}
+		}
+		if order := []string{<-started, <-started}; order[0] != "2" || order[1] != "3" {
+			t.Errorf("expected queued requests to run in arrival order, got %v", order)
+		}
+		if depth := server.requestQueue.depth(); depth != 0 {
+			t.Errorf("expected queue to drain, depth %d", depth)
+		}
+	})
+}
+
+// TestRequestQueueWaitLimits tests that queued requests give up on the max wait and on client cancellation
+func TestRequestQueueWaitLimits(t *testing.T) {
+	queue := newRequestQueue(RequestQueueConfig{Enabled: true, MaxConcurrent: 1, MaxDepth: 1, MaxWait: 20 * time.Millisecond})
+	if err := queue.acquire(context.Background()); err != nil {
+		t.Fatalf("expected free slot, got %v", err)
+	}
+
+	t.Run("wait beyond deadline is rejected", func(t *testing.T) {
+		if err := queue.acquire(context.Background()); err != errRequestQueueTimeout {
+			t.Errorf("expected queue timeout, got %v", err)
+		}
+		if queue.depth() != 0 {
+			t.Errorf("expected timed out request to leave the queue, depth %d", queue.depth())
+		}
+	})
+
+	t.Run("cancelled client leaves the queue", func(t *testing.T) {
+		queue.config.MaxWait = 0
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() { errCh <- queue.acquire(ctx) }()
+
+		for queue.depth() != 1 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+
+		select {
+		case err := <-errCh:
+			if err != context.Canceled {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected cancelled request to stop waiting")
+		}
+		if queue.depth() != 0 {
+			t.Errorf("expected cancelled request to leave the queue, depth %d", queue.depth())
+		}
+	})
+
+	t.Run("released slot is reusable", func(t *testing.T) {
+		queue.release()
+		if err := queue.acquire(context.Background()); err != nil {
+			t.Errorf("expected released slot to be available, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/mcp/types"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestRequestTimeoutMiddleware tests that slow handlers are cut off with a JSON-RPC timeout
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{RequestTimeout: 50 * time.Millisecond}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+
+	release := make(chan struct{})
+	defer close(release)
+	slowHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(500 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := server.requestTimeoutMiddleware(slowHandler)
+
+	t.Run("slow handler returns timely 503", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/mcp", nil)
+		w := httptest.NewRecorder()
+
+		start := time.Now()
+		handler.ServeHTTP(w, req)
+		elapsed := time.Since(start)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected status 503, got %d", w.Code)
+		}
+		if elapsed > 300*time.Millisecond {
+			t.Errorf("expected response shortly after the timeout, took %v", elapsed)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected JSON content type, got %q", ct)
+		}
+
+		var resp types.Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode timeout response: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Code != types.ErrorCodeRequestTimeout {
+			t.Errorf("expected JSON-RPC timeout error, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("fast handler is unaffected", func(t *testing.T) {
+		fast := server.requestTimeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("ok"))
+		}))
+
+		req := httptest.NewRequest("GET", "/admin/services", nil)
+		w := httptest.NewRecorder()
+		fast.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "ok" {
+			t.Errorf("expected 200 ok, got %d %q", w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "text/plain" {
+			t.Errorf("expected handler content type to be preserved, got %q", ct)
+		}
+	})
+
+	for _, path := range []string{"/health", "/health/ready", "/metrics"} {
+		t.Run(path+" is exempt", func(t *testing.T) {
+			exempt := server.requestTimeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(100 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			exempt.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected exempt endpoint to complete with 200, got %d", w.Code)
+			}
+		})
+	}
+}
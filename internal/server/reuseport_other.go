@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package server
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+)
+
+// reusePortControl fails the listen on platforms without SO_REUSEPORT
+func reusePortControl(network, address string, conn syscall.RawConn) error {
+	return fmt.Errorf("reuse_port is not supported on %s", runtime.GOOS)
+}
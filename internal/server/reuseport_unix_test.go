@@ -0,0 +1,137 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestReusePortHandoff tests that a replacement gateway binds the port while
+// the old one holds it, and that the port accepts connections throughout
+// the old gateway's shutdown. A handshake still queued on the old listener
+// when it closes may be reset by the kernel, but no connection is refused.
+func TestReusePortHandoff(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	port := probe.Addr().(*net.TCPAddr).Port
+	probe.Close()
+	address := probe.Addr().String()
+
+	start := func(t *testing.T, reusePort bool) (*GatewayServer, context.CancelFunc, chan error) {
+		t.Helper()
+		server := NewGatewayServer(ServerConfig{
+			Address:               "127.0.0.1",
+			Port:                  port,
+			ShutdownTimeout:       5 * time.Second,
+			EnableHealthEndpoints: true,
+			ReusePort:             reusePort,
+		}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan error, 1)
+		go func() { stopped <- server.Start(ctx) }()
+
+		select {
+		case <-server.Serving():
+		case err := <-stopped:
+			cancel()
+			stopped <- err
+			return nil, cancel, stopped
+		case <-time.After(10 * time.Second):
+			cancel()
+			t.Fatal("server did not start serving")
+		}
+		return server, cancel, stopped
+	}
+
+	old, stopOld, oldStopped := start(t, true)
+	if old == nil {
+		t.Fatalf("expected the first gateway to serve, got %v", <-oldStopped)
+	}
+
+	t.Run("port is not shared without reuse_port", func(t *testing.T) {
+		server, cancel, stopped := start(t, false)
+		defer cancel()
+		if server != nil {
+			t.Fatal("expected the bind to fail while the port is held")
+		}
+		if err := <-stopped; err == nil {
+			t.Error("expected a listen error")
+		}
+	})
+
+	replacement, stopReplacement, replacementStopped := start(t, true)
+	if replacement == nil {
+		stopOld()
+		t.Fatalf("expected the replacement to bind the held port, got %v", <-replacementStopped)
+	}
+	defer func() {
+		stopReplacement()
+		<-replacementStopped
+	}()
+
+	t.Run("port accepts connections while the old gateway stops", func(t *testing.T) {
+		var dials, refused atomic.Int64
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			deadline := time.Now().Add(10 * time.Second)
+			for {
+				select {
+				case <-oldStopped:
+					return
+				default:
+				}
+				if time.Now().After(deadline) {
+					return
+				}
+				conn, err := net.DialTimeout("tcp", address, time.Second)
+				dials.Add(1)
+				if errors.Is(err, syscall.ECONNREFUSED) {
+					refused.Add(1)
+				}
+				if err != nil {
+					continue
+				}
+				conn.Close()
+			}
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		stopOld()
+		<-done
+
+		if refused.Load() > 0 {
+			t.Errorf("expected the port to stay bound during handoff, %d of %d connections refused", refused.Load(), dials.Load())
+		}
+	})
+
+	t.Run("replacement serves after the old gateway stopped", func(t *testing.T) {
+		resp, err := http.Get("http://" + address + "/health")
+		if err != nil {
+			t.Fatalf("expected the replacement to serve, got %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+	})
+}
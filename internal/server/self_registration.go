@@ -0,0 +1,219 @@
+package server
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// SelfRegistrationConfig enables POST /register, where a backend registers
+// itself by proving its identity with a client certificate or a token
+type SelfRegistrationConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Token identities; services with client certificates need no entry here
+	Credentials []ServiceCredential `yaml:"credentials"`
+}
+
+// ServiceCredential binds a bearer token to the service name and endpoint
+// hosts it may register
+type ServiceCredential struct {
+	Token string   `yaml:"token"`
+	Name  string   `yaml:"name"`
+	Hosts []string `yaml:"hosts"`
+}
+
+// Validate checks that every credential has a token, a name and at least one host
+func (c SelfRegistrationConfig) Validate() error {
+	tokens := make(map[string]bool, len(c.Credentials))
+	for i, credential := range c.Credentials {
+		if credential.Token == "" || credential.Name == "" {
+			return fmt.Errorf("credential %d requires a token and a name", i)
+		}
+		if len(credential.Hosts) == 0 {
+			return fmt.Errorf("credential for %s must list at least one endpoint host", credential.Name)
+		}
+		if tokens[credential.Token] {
+			return fmt.Errorf("credential for %s reuses another credential's token", credential.Name)
+		}
+		tokens[credential.Token] = true
+	}
+	return nil
+}
+
+// serviceIdentity is the authenticated identity of a self-registering service
+type serviceIdentity struct {
+	source string // mtls or token
+	name   string
+	cert   *x509.Certificate
+	hosts  []string
+}
+
+func (id *serviceIdentity) String() string {
+	if id.cert != nil {
+		return fmt.Sprintf("%s:%s (serial %s)", id.source, id.cert.Subject.CommonName, id.cert.SerialNumber)
+	}
+	return fmt.Sprintf("%s:%s", id.source, id.name)
+}
+
+// allowsEndpoint reports whether the identity may register an endpoint on host.
+// Certificates must name the host in their SANs; tokens must list it.
+func (id *serviceIdentity) allowsEndpoint(host string) bool {
+	if id.cert != nil {
+		return id.cert.VerifyHostname(host) == nil
+	}
+	for _, allowed := range id.hosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticateService resolves the identity of a self-registering service. A
+// verified client certificate takes precedence over a bearer token.
+func (gs *GatewayServer) authenticateService(r *http.Request) *serviceIdentity {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		leaf := r.TLS.VerifiedChains[0][0]
+		return &serviceIdentity{source: "mtls", name: leaf.Subject.CommonName, cert: leaf}
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return nil
+	}
+	for _, credential := range gs.config.SelfRegistration.Credentials {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(credential.Token)) == 1 {
+			return &serviceIdentity{source: "token", name: credential.Name, hosts: credential.Hosts}
+		}
+	}
+	return nil
+}
+
+// handleSelfRegister registers a service on its own authority, rejecting
+// registrations whose name or endpoint the caller's identity does not cover
+func (gs *GatewayServer) handleSelfRegister(w http.ResponseWriter, r *http.Request) {
+	identity := gs.authenticateService(r)
+	if identity == nil {
+		gs.logger.Warn("self_registration_unauthenticated", "remote_addr", r.RemoteAddr)
+		gs.metrics.Inc("self_registrations_total", "source", "none", "status", "unauthenticated")
+		w.WriteHeader(http.StatusUnauthorized)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "authentication_required",
+			"message": "Self-registration requires a verified client certificate or a service token",
+		})
+		return
+	}
+
+	var req registry.ServiceRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_request_body",
+			"message": "Failed to parse JSON request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if req.Name == "" || req.Type == "" || req.Endpoint == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "missing_required_fields",
+			"message": "Name, Type, and Endpoint are required fields",
+		})
+		return
+	}
+
+	endpoint, err := url.Parse(req.Endpoint)
+	if err != nil || endpoint.Hostname() == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "invalid_endpoint",
+			"message": fmt.Sprintf("Endpoint %q is not a valid URL", req.Endpoint),
+		})
+		return
+	}
+
+	var mismatch string
+	switch {
+	case req.Name != identity.name:
+		mismatch = fmt.Sprintf("name %q does not match authenticated identity %q", req.Name, identity.name)
+	case !identity.allowsEndpoint(endpoint.Hostname()):
+		mismatch = fmt.Sprintf("endpoint host %q is not covered by identity %s", endpoint.Hostname(), identity)
+	}
+	if mismatch != "" {
+		gs.logger.Warn("self_registration_identity_mismatch",
+			"identity", identity.String(),
+			"service_name", req.Name,
+			"endpoint", req.Endpoint,
+			"reason", mismatch)
+		gs.metrics.Inc("self_registrations_total", "source", identity.source, "status", "rejected")
+		w.WriteHeader(http.StatusForbidden)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "identity_mismatch",
+			"message": "Claimed " + mismatch,
+		})
+		return
+	}
+
+	// Record who registered the service alongside its own metadata
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]interface{})
+	}
+	req.Metadata["self_registered"] = true
+	req.Metadata["registration_identity"] = identity.String()
+
+	resp, err := gs.registry.RegisterService(r.Context(), req)
+	if err != nil {
+		gs.logger.Error("self_registration_failed",
+			"identity", identity.String(),
+			"service_name", req.Name,
+			"error", err)
+		gs.metrics.Inc("self_registrations_total", "source", identity.source, "status", "failed")
+		w.WriteHeader(http.StatusInternalServerError)
+		gs.writeJSONResponse(w, map[string]interface{}{
+			"error":   "registration_failed",
+			"message": "Failed to register service",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	gs.logger.Info("service_self_registered",
+		"service_id", resp.ServiceID,
+		"service_name", req.Name,
+		"endpoint", req.Endpoint,
+		"identity", identity.String())
+	gs.metrics.Inc("self_registrations_total", "source", identity.source, "status", "success")
+	gs.publishListChanged(req.Type)
+
+	w.WriteHeader(http.StatusCreated)
+	gs.writeJSONResponse(w, resp)
+}
+
+// clientCATLSConfig requests client certificates signed by the configured CA
+// so services can authenticate with mTLS; certificates remain optional for
+// every other endpoint
+func clientCATLSConfig(caFile string) (*tls.Config, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file %s: %w", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", caFile)
+	}
+	return &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  pool,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestSelfRegistration tests POST /register with certificate and token identities
+func TestSelfRegistration(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	config := ServerConfig{
+		SelfRegistration: SelfRegistrationConfig{
+			Enabled: true,
+			Credentials: []ServiceCredential{
+				{Token: "token-search", Name: "token-search", Hosts: []string{"127.0.0.1"}},
+			},
+		},
+	}
+	server := NewGatewayServer(config, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	cert := newServiceCertificate(t, "cert-search", net.ParseIP("127.0.0.1"))
+
+	register := func(t *testing.T, name, endpoint string, configure func(*http.Request)) *httptest.ResponseRecorder {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{
+			"name":     name,
+			"type":     "search_test",
+			"version":  "1.0.0",
+			"endpoint": endpoint,
+			"protocol": "http",
+		})
+		req := httptest.NewRequest("POST", "/register", bytes.NewReader(body))
+		configure(req)
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+	withCert := func(req *http.Request) {
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+
+	t.Run("certificate identity registers its own service", func(t *testing.T) {
+		w := register(t, "cert-search", backend.URL, withCert)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var resp struct {
+			ServiceID string `json:"service_id"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		service := server.registry.GetService(resp.ServiceID)
+		if service == nil {
+			t.Fatal("expected service to be registered")
+		}
+		if identity, _ := service.Metadata["registration_identity"].(string); identity == "" || service.Metadata["self_registered"] != true {
+			t.Errorf("expected registration identity to be recorded, got %v", service.Metadata)
+		}
+	})
+
+	t.Run("endpoint outside the certificate identity is rejected", func(t *testing.T) {
+		w := register(t, "cert-search", "http://attacker.example.com:8080", withCert)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("expected status 403, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if resp["error"] != "identity_mismatch" {
+			t.Errorf("expected identity_mismatch error, got %v", resp["error"])
+		}
+	})
+
+	t.Run("name outside the certificate identity is rejected", func(t *testing.T) {
+		if w := register(t, "other-service", backend.URL, withCert); w.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("service token registers its own service", func(t *testing.T) {
+		w := register(t, "token-search", backend.URL, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer token-search")
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("unauthenticated registration is rejected", func(t *testing.T) {
+		w := register(t, "token-search", backend.URL, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer wrong-token")
+		})
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+// newServiceCertificate creates a self-signed client certificate whose common
+// name identifies the service and whose SANs list its endpoint hosts
+func newServiceCertificate(t *testing.T, name string, ips ...net.IP) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IPAddresses:  ips,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/plugins"
+)
+
+const defaultSelfTestTimeout = 5 * time.Second
+
+// SelfTestConfig configures the startup check of plugins and backends that
+// runs before the listener opens, so broken components surface at startup
+// rather than on the first client request
+type SelfTestConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	FailOnError bool          `yaml:"fail_on_error"` // Abort startup when a critical component fails
+	Critical    []string      `yaml:"critical"`      // Plugin or service names that are critical; empty treats every component as critical
+	Timeout     time.Duration `yaml:"timeout"`       // Per-component check timeout
+}
+
+// SelfTestResult is the outcome of checking one plugin or backend
+type SelfTestResult struct {
+	Component string        `json:"component"`
+	Kind      string        `json:"kind"` // plugin or backend
+	Passed    bool          `json:"passed"`
+	Critical  bool          `json:"critical"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// SelfTestReport summarizes a startup self-test
+type SelfTestReport struct {
+	Results          []SelfTestResult `json:"results"`
+	Passed           int              `json:"passed"`
+	Failed           int              `json:"failed"`
+	CriticalFailures int              `json:"critical_failures"`
+}
+
+// runSelfTest lists the tools of every enabled plugin and health checks every
+// registered backend. It returns an error only when fail_on_error is set and
+// a critical component failed; other failures are logged.
+func (gs *GatewayServer) runSelfTest(ctx context.Context) (SelfTestReport, error) {
+	start := time.Now()
+	timeout := gs.config.SelfTest.Timeout
+	if timeout <= 0 {
+		timeout = defaultSelfTestTimeout
+	}
+
+	var (
+		mutex   sync.Mutex
+		wg      sync.WaitGroup
+		results []SelfTestResult
+	)
+	check := func(kind, component string, aliases []string, fn func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			checkStart := time.Now()
+			err := fn(checkCtx)
+			result := SelfTestResult{
+				Component: component,
+				Kind:      kind,
+				Passed:    err == nil,
+				Critical:  gs.selfTestCritical(aliases),
+				Duration:  time.Since(checkStart),
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+
+			mutex.Lock()
+			results = append(results, result)
+			mutex.Unlock()
+		}()
+	}
+
+	for name, plugin := range gs.pluginIntegration.GetPluginManager().ListEnabledPlugins() {
+		plugin := plugin
+		check("plugin", name, []string{name}, func(ctx context.Context) error {
+			if err := plugin.HealthCheck(ctx); err != nil {
+				return fmt.Errorf("health check failed: %w", err)
+			}
+			return listPluginTools(plugin)
+		})
+	}
+
+	for id, service := range gs.registry.GetAllServices() {
+		// Plugin services are covered by the plugin checks above
+		if strings.HasPrefix(service.Endpoint, "plugin://") {
+			continue
+		}
+		id := id
+		check("backend", service.Name, []string{service.Name, id}, func(ctx context.Context) error {
+			return gs.registry.CheckServiceHealth(ctx, id)
+		})
+	}
+
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind > results[j].Kind
+		}
+		return results[i].Component < results[j].Component
+	})
+
+	report := SelfTestReport{Results: results}
+	for _, result := range results {
+		status := "passed"
+		if result.Passed {
+			report.Passed++
+		} else {
+			status = "failed"
+			report.Failed++
+			if result.Critical {
+				report.CriticalFailures++
+			}
+			gs.logger.Warn("self_test_component_failed",
+				"kind", result.Kind,
+				"component", result.Component,
+				"critical", result.Critical,
+				"error", result.Error)
+		}
+		gs.metrics.Inc("self_test_checks_total", "kind", result.Kind, "status", status)
+	}
+
+	gs.logger.Info("self_test_completed",
+		"passed", report.Passed,
+		"failed", report.Failed,
+		"critical_failures", report.CriticalFailures,
+		"duration", time.Since(start))
+
+	if report.CriticalFailures > 0 && gs.config.SelfTest.FailOnError {
+		return report, fmt.Errorf("%d critical component(s) failed the startup self-test", report.CriticalFailures)
+	}
+	return report, nil
+}
+
+// selfTestCritical reports whether a component, known by any of the given
+// names, is critical for the self-test
+func (gs *GatewayServer) selfTestCritical(names []string) bool {
+	if len(gs.config.SelfTest.Critical) == 0 {
+		return true
+	}
+	for _, critical := range gs.config.SelfTest.Critical {
+		for _, name := range names {
+			if critical == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listPluginTools invokes a plugin's tool listing, reporting a panic as a failure
+func listPluginTools(plugin plugins.Plugin) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("tools/list panicked: %v", recovered)
+		}
+	}()
+	plugin.GetTools()
+	return nil
+}
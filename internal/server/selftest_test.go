@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestStartupSelfTest tests that the self-test detects an unreachable backend and honours fail_on_error
+func TestStartupSelfTest(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	register := func(name string, handler http.HandlerFunc) *httptest.Server {
+		backend := httptest.NewServer(handler)
+		if _, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+			Name:     name,
+			Type:     "selftest",
+			Version:  "1.0.0",
+			Endpoint: backend.URL,
+			Protocol: "http",
+		}); err != nil {
+			t.Fatalf("failed to register %s: %v", name, err)
+		}
+		return backend
+	}
+
+	healthy := register("healthy-backend", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer healthy.Close()
+
+	// Reachable at registration, gone by the time the self-test runs
+	register("unreachable-backend", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Close()
+
+	run := func(config SelfTestConfig) (SelfTestReport, error) {
+		server.config.SelfTest = config
+		return server.runSelfTest(context.Background())
+	}
+
+	t.Run("unreachable backend is detected", func(t *testing.T) {
+		report, err := run(SelfTestConfig{Enabled: true})
+		if err != nil {
+			t.Fatalf("expected failures to be logged only without fail_on_error, got %v", err)
+		}
+
+		results := make(map[string]SelfTestResult)
+		for _, result := range report.Results {
+			results[result.Component] = result
+		}
+		if result := results["unreachable-backend"]; result.Passed || result.Error == "" {
+			t.Errorf("expected unreachable backend to fail with an error, got %+v", result)
+		}
+		if result := results["healthy-backend"]; !result.Passed {
+			t.Errorf("expected healthy backend to pass, got %+v", result)
+		}
+		if report.Failed != 1 || report.CriticalFailures != 1 {
+			t.Errorf("expected 1 critical failure, got %d failed and %d critical", report.Failed, report.CriticalFailures)
+		}
+	})
+
+	t.Run("fail_on_error aborts on a critical failure", func(t *testing.T) {
+		if _, err := run(SelfTestConfig{Enabled: true, FailOnError: true}); err == nil {
+			t.Error("expected self-test to fail")
+		}
+	})
+
+	t.Run("failures of non-critical components do not abort", func(t *testing.T) {
+		report, err := run(SelfTestConfig{Enabled: true, FailOnError: true, Critical: []string{"healthy-backend"}})
+		if err != nil {
+			t.Errorf("expected non-critical failure to be tolerated, got %v", err)
+		}
+		if report.Failed != 1 || report.CriticalFailures != 0 {
+			t.Errorf("expected 1 non-critical failure, got %d failed and %d critical", report.Failed, report.CriticalFailures)
+		}
+	})
+}
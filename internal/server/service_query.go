@@ -0,0 +1,150 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// serviceSortFields maps the sort query parameter to a comparison of two services
+var serviceSortFields = map[string]func(a, b *registry.RegisteredService) int{
+	"name":          func(a, b *registry.RegisteredService) int { return strings.Compare(a.Name, b.Name) },
+	"type":          func(a, b *registry.RegisteredService) int { return strings.Compare(a.Type, b.Type) },
+	"status":        func(a, b *registry.RegisteredService) int { return strings.Compare(string(a.Status), string(b.Status)) },
+	"health":        func(a, b *registry.RegisteredService) int { return strings.Compare(string(a.Health), string(b.Health)) },
+	"registered_at": func(a, b *registry.RegisteredService) int { return a.RegisteredAt.Compare(b.RegisteredAt) },
+	"last_seen":     func(a, b *registry.RegisteredService) int { return a.LastSeen.Compare(b.LastSeen) },
+}
+
+// serviceQuery holds the filters, ordering and page requested from GET /admin/services
+type serviceQuery struct {
+	Type       string
+	Health     string
+	Status     string
+	Tags       []string
+	Name       string
+	Sort       string
+	Descending bool
+	Limit      int  // Effective page size, after defaults and clamping
+	Clamped    bool // The requested limit exceeded the max page size
+	Offset     int
+}
+
+// parseServiceQuery reads type, health, status, tag (repeatable), name, sort,
+// order, limit and offset from the request query string. The limit is bounded
+// by pagination.
+func parseServiceQuery(r *http.Request, pagination PaginationConfig) (serviceQuery, error) {
+	values := r.URL.Query()
+
+	query := serviceQuery{
+		Type:   values.Get("type"),
+		Health: values.Get("health"),
+		Status: values.Get("status"),
+		Tags:   values["tag"],
+		Name:   strings.ToLower(values.Get("name")),
+		Sort:   values.Get("sort"),
+	}
+
+	if query.Sort == "" {
+		query.Sort = "name"
+	}
+	if _, ok := serviceSortFields[query.Sort]; !ok {
+		return query, fmt.Errorf("invalid sort field: %s", query.Sort)
+	}
+
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return query, fmt.Errorf("invalid order: %s, must be asc or desc", order)
+	}
+
+	// A missing, zero or negative limit gets the default page size
+	requested := 0
+	if raw := values.Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, fmt.Errorf("limit must be an integer, got %q", raw)
+		}
+		requested = value
+	}
+	query.Limit, query.Clamped = pagination.pageLimit(requested)
+
+	if raw := values.Get("offset"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return query, fmt.Errorf("offset must be a non-negative integer, got %q", raw)
+		}
+		query.Offset = value
+	}
+
+	return query, nil
+}
+
+// matches reports whether a service passes every filter in the query
+func (q serviceQuery) matches(service *registry.RegisteredService) bool {
+	if q.Type != "" && service.Type != q.Type {
+		return false
+	}
+	if q.Health != "" && !strings.EqualFold(string(service.Health), q.Health) {
+		return false
+	}
+	if q.Status != "" && !strings.EqualFold(string(service.Status), q.Status) {
+		return false
+	}
+	if q.Name != "" && !strings.Contains(strings.ToLower(service.Name), q.Name) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !containsString(service.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply filters and sorts services, returning the matches and the requested page
+func (q serviceQuery) apply(services map[string]*registry.RegisteredService) (filtered, page []*registry.RegisteredService) {
+	filtered = make([]*registry.RegisteredService, 0, len(services))
+	for _, service := range services {
+		if q.matches(service) {
+			filtered = append(filtered, service)
+		}
+	}
+
+	compare := serviceSortFields[q.Sort]
+	sort.Slice(filtered, func(i, j int) bool {
+		c := compare(filtered[i], filtered[j])
+		if c == 0 {
+			// Tie-break on ID so pages are stable between requests
+			c = strings.Compare(filtered[i].ID, filtered[j].ID)
+		}
+		if q.Descending {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	if q.Offset >= len(filtered) {
+		return filtered, []*registry.RegisteredService{}
+	}
+	page = filtered[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(page) {
+		page = page[:q.Limit]
+	}
+	return filtered, page
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestListServicesQuery tests filtering, sorting and pagination on GET /admin/services
+func TestListServicesQuery(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{EnableAdminEndpoints: true}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	fixtures := []struct {
+		name   string
+		tags   []string
+		health registry.HealthStatus
+	}{
+		{"search-alpha", []string{"prod", "eu"}, registry.HealthHealthy},
+		{"search-beta", []string{"prod"}, registry.HealthUnhealthy},
+		{"search-gamma", []string{"staging"}, registry.HealthHealthy},
+		{"search-delta", []string{"prod", "us"}, registry.HealthUnhealthy},
+		{"other-epsilon", []string{"prod"}, registry.HealthHealthy},
+	}
+	for _, fixture := range fixtures {
+		resp, err := server.registry.RegisterService(context.Background(), registry.ServiceRegistrationRequest{
+			Name:     fixture.name,
+			Type:     "search_test",
+			Version:  "1.0.0",
+			Endpoint: backend.URL,
+			Protocol: "http",
+			Tags:     fixture.tags,
+		})
+		if err != nil {
+			t.Fatalf("failed to register %s: %v", fixture.name, err)
+		}
+		server.registry.GetService(resp.ServiceID).Health = fixture.health
+	}
+	total := len(server.registry.GetAllServices())
+
+	type serviceSummary struct {
+		Name string `json:"name"`
+	}
+	type listResponse struct {
+		Services []serviceSummary `json:"services"`
+		Metadata struct {
+			TotalCount    int `json:"total_count"`
+			FilteredCount int `json:"filtered_count"`
+		} `json:"metadata"`
+	}
+
+	list := func(t *testing.T, query string) listResponse {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/admin/services?"+query, nil)
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %q, got %d: %s", query, w.Code, w.Body.String())
+		}
+
+		var resp listResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Metadata.TotalCount != total {
+			t.Errorf("expected total_count %d to reflect the unfiltered set, got %d", total, resp.Metadata.TotalCount)
+		}
+		return resp
+	}
+
+	names := func(services []serviceSummary) string {
+		result := ""
+		for i, service := range services {
+			if i > 0 {
+				result += ","
+			}
+			result += service.Name
+		}
+		return result
+	}
+
+	t.Run("filter by health", func(t *testing.T) {
+		resp := list(t, "health=unhealthy")
+		if got := names(resp.Services); got != "search-beta,search-delta" {
+			t.Errorf("expected unhealthy services search-beta,search-delta, got %s", got)
+		}
+		if resp.Metadata.FilteredCount != 2 {
+			t.Errorf("expected filtered_count 2, got %d", resp.Metadata.FilteredCount)
+		}
+	})
+
+	t.Run("filter by tag and name", func(t *testing.T) {
+		resp := list(t, "tag=prod&name=SEARCH")
+		if got := names(resp.Services); got != "search-alpha,search-beta,search-delta" {
+			t.Errorf("expected prod search services, got %s", got)
+		}
+
+		resp = list(t, "tag=prod&tag=eu")
+		if got := names(resp.Services); got != "search-alpha" {
+			t.Errorf("expected only search-alpha to carry both tags, got %s", got)
+		}
+	})
+
+	t.Run("paginate sorted results", func(t *testing.T) {
+		var pages []string
+		for offset := 0; offset < 4; offset += 2 {
+			resp := list(t, fmt.Sprintf("type=search_test&name=search&sort=name&order=desc&limit=2&offset=%d", offset))
+			if resp.Metadata.FilteredCount != 4 {
+				t.Errorf("expected filtered_count 4 on every page, got %d", resp.Metadata.FilteredCount)
+			}
+			pages = append(pages, names(resp.Services))
+		}
+		if pages[0] != "search-gamma,search-delta" || pages[1] != "search-beta,search-alpha" {
+			t.Errorf("unexpected pages %v", pages)
+		}
+
+		resp := list(t, "type=search_test&offset=10")
+		if len(resp.Services) != 0 || resp.Metadata.FilteredCount != 5 {
+			t.Errorf("expected empty page past the end with filtered_count 5, got %d services and %d", len(resp.Services), resp.Metadata.FilteredCount)
+		}
+	})
+
+	t.Run("invalid parameters are rejected", func(t *testing.T) {
+		for _, query := range []string{"limit=abc", "offset=abc", "offset=-1", "sort=endpoint", "order=sideways"} {
+			req := httptest.NewRequest("GET", "/admin/services?"+query, nil)
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400 for %q, got %d", query, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// shutdownPhase is one step of the gateway shutdown sequence
+type shutdownPhase struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// shutdownPhases returns the shutdown sequence. New requests are refused and
+// in-flight ones drained before the plugins and registry serving them are stopped.
+func (gs *GatewayServer) shutdownPhases() []shutdownPhase {
+	return []shutdownPhase{
+		{
+			// Streams first: the HTTP server neither notifies hijacked
+			// connections nor waits for them to close
+			name: "drain_streams",
+			run: func(ctx context.Context) error {
+				gs.drainStreamConnections(ctx)
+				return nil
+			},
+		},
+		{
+			// Closes the listeners, then waits for in-flight requests
+			name: "http_server",
+			run:  gs.shutdownHTTPServer,
+		},
+		{
+			// After the HTTP server, so every finished call has queued its record
+			name: "tool_audit",
+			run:  gs.mcpRouter.CloseToolAudit,
+		},
+		{
+			name: "unix_socket",
+			run: func(ctx context.Context) error {
+				return gs.removeUnixSocket()
+			},
+		},
+		{
+			name: "plugins",
+			run:  gs.pluginIntegration.ShutdownPlugins,
+		},
+		{
+			name: "registry",
+			run: func(ctx context.Context) error {
+				if limiter, ok := gs.rateLimiter.(interface{ Stop() }); ok {
+					limiter.Stop()
+				}
+				return gs.registry.Shutdown()
+			},
+		},
+	}
+}
+
+// shutdownHTTPServer stops accepting requests and waits for in-flight ones,
+// force-closing remaining connections once ctx expires
+func (gs *GatewayServer) shutdownHTTPServer(ctx context.Context) error {
+	err := gs.httpServer.Shutdown(ctx)
+	if err != nil && ctx.Err() != nil {
+		gs.httpServer.Close()
+	}
+	return err
+}
+
+// runShutdownPhases runs each phase in order within the shutdown deadline. A
+// failed or timed out phase is logged and the remaining phases still run.
+func (gs *GatewayServer) runShutdownPhases(ctx context.Context, phases []shutdownPhase) error {
+	var errs []error
+	for _, phase := range phases {
+		start := time.Now()
+		gs.logger.Info("shutdown_phase_started", "phase", phase.name)
+
+		err := phase.run(ctx)
+		duration := time.Since(start)
+		gs.metrics.Observe("shutdown_phase_duration_ms", float64(duration.Milliseconds()), "phase", phase.name)
+
+		if err != nil {
+			gs.logger.Error("shutdown_phase_failed",
+				"phase", phase.name,
+				"duration_ms", duration.Milliseconds(),
+				"deadline_exceeded", ctx.Err() != nil,
+				"error", err)
+			errs = append(errs, fmt.Errorf("%s shutdown: %w", phase.name, err))
+			continue
+		}
+
+		gs.logger.Info("shutdown_phase_completed",
+			"phase", phase.name,
+			"duration_ms", duration.Milliseconds())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestShutdownDrainsBeforePlugins tests that a request in flight when shutdown starts completes against a live plugin
+func TestShutdownDrainsBeforePlugins(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{ShutdownTimeout: 5 * time.Second}, logger, mockMetrics, validator, healthMgr)
+
+	plugin := &slowShutdownPlugin{started: make(chan struct{}), release: make(chan struct{})}
+	if err := server.pluginIntegration.GetPluginManager().RegisterPlugin(plugin); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go server.httpServer.Serve(listener)
+
+	type response struct {
+		body string
+		err  error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow","arguments":{}}}`
+		resp, err := http.Post(fmt.Sprintf("http://%s/mcp", listener.Addr()), "application/json", strings.NewReader(body))
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		responses <- response{body: string(data), err: err}
+	}()
+
+	select {
+	case <-plugin.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not reach the plugin")
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.Stop() }()
+
+	// Give shutdown time to reach the point where it would stop plugins
+	time.Sleep(100 * time.Millisecond)
+	if plugin.wasShutDown() {
+		t.Fatal("expected plugins to stay up while a request is in flight")
+	}
+	close(plugin.release)
+
+	result := <-responses
+	if result.err != nil {
+		t.Fatalf("in-flight request failed: %v", result.err)
+	}
+	var resp struct {
+		Result struct {
+			IsError bool `json:"isError"`
+		} `json:"result"`
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(result.body), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", result.body, err)
+	}
+	if resp.Error != nil || resp.Result.IsError {
+		t.Errorf("expected the in-flight call to succeed, got %s", result.body)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Errorf("expected a clean shutdown, got %v", err)
+	}
+	if !plugin.wasShutDown() {
+		t.Error("expected plugins to be shut down once requests drained")
+	}
+	if plugin.calledAfterShutdown {
+		t.Error("expected the tool call to finish before the plugin shut down")
+	}
+}
+
+// slowShutdownPlugin blocks tool calls until released and records when it is shut down
+type slowShutdownPlugin struct {
+	plugins.Plugin
+	started             chan struct{}
+	release             chan struct{}
+	mutex               sync.Mutex
+	shutDown            bool
+	calledAfterShutdown bool
+}
+
+func (p *slowShutdownPlugin) Name() string        { return "slow-shutdown" }
+func (p *slowShutdownPlugin) Version() string     { return "1.0.0" }
+func (p *slowShutdownPlugin) Description() string { return "Shutdown ordering test plugin" }
+func (p *slowShutdownPlugin) GetTools() []registry.ToolDefinition {
+	return []registry.ToolDefinition{{Name: "slow", Description: "Blocks until released"}}
+}
+func (p *slowShutdownPlugin) GetResources() []registry.ResourceDefinition { return nil }
+func (p *slowShutdownPlugin) GetPrompts() []registry.PromptDefinition     { return nil }
+func (p *slowShutdownPlugin) CallTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	close(p.started)
+	<-p.release
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.shutDown {
+		p.calledAfterShutdown = true
+		return nil, fmt.Errorf("plugin is shut down")
+	}
+	return "ok", nil
+}
+func (p *slowShutdownPlugin) Shutdown(ctx context.Context) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.shutDown = true
+	return nil
+}
+func (p *slowShutdownPlugin) wasShutDown() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.shutDown
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package server
+
+import "os"
+
+// withSocketMode runs listen; without a umask the mode is only applied by
+// the chmod that follows
+func withSocketMode(mode os.FileMode, listen func() error) error {
+	return listen()
+}
@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package server
+
+import (
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// umaskMutex serializes umask changes, which apply to the whole process
+var umaskMutex sync.Mutex
+
+// withSocketMode runs listen with the umask set so the socket file is
+// created with mode, leaving no window in which it is more accessible
+func withSocketMode(mode os.FileMode, listen func() error) error {
+	umaskMutex.Lock()
+	defer umaskMutex.Unlock()
+
+	previous := unix.Umask(int(^mode.Perm() & 0777))
+	defer unix.Umask(previous)
+	return listen()
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/router"
+	"github.com/osakka/mcpeg/pkg/capabilities"
+)
+
+// NotificationStreamPath is the SSE endpoint streaming server-initiated notifications
+const NotificationStreamPath = "/mcp/events"
+
+const (
+	// sseKeepaliveInterval keeps idle streams open through proxies that close quiet connections
+	sseKeepaliveInterval = 15 * time.Second
+
+	// sseRetryMillis is the reconnection delay suggested to EventSource clients
+	sseRetryMillis = 3000
+)
+
+// MCP list-changed notifications published when backends come and go
+var listChangedNotifications = map[string]string{
+	"tool_provider":     "notifications/tools/list_changed",
+	"resource_provider": "notifications/resources/list_changed",
+	"prompt_provider":   "notifications/prompts/list_changed",
+}
+
+// MCP list-changed notifications published when a plugin's capabilities change
+var capabilityListChangedNotifications = map[capabilities.CapabilityType]string{
+	capabilities.CapabilityTool:     listChangedNotifications["tool_provider"],
+	capabilities.CapabilityResource: listChangedNotifications["resource_provider"],
+	capabilities.CapabilityPrompt:   listChangedNotifications["prompt_provider"],
+}
+
+// PublishNotification streams a server-initiated notification, such as
+// notifications/resources/updated or notifications/message, to the SSE
+// clients of audience, or to every client when audience is nil
+func (gs *GatewayServer) PublishNotification(method string, params interface{}, audience []router.Subscriber) {
+	notification := gs.notifications.Publish(method, params, audience)
+	gs.metrics.Inc("sse_notifications_published_total", "method", method)
+	gs.logger.Debug("notification_published",
+		"method", method,
+		"event_id", notification.ID,
+		"audience", len(audience))
+}
+
+// publishListChanged tells clients that the capabilities offered by a service type changed
+func (gs *GatewayServer) publishListChanged(serviceType string) {
+	if method, ok := listChangedNotifications[serviceType]; ok {
+		gs.PublishNotification(method, nil, nil)
+	}
+}
+
+// publishCapabilityChanges tells clients which lists a plugin capability change affects
+func (gs *GatewayServer) publishCapabilityChanges(diff capabilities.CapabilityDiff) {
+	for _, capabilityType := range diff.ChangedTypes() {
+		if method, ok := capabilityListChangedNotifications[capabilityType]; ok {
+			gs.PublishNotification(method, nil, nil)
+		}
+	}
+}
+
+// detectCapabilityChanges compares a plugin's capabilities with those last
+// seen, publishing list-changed notifications for any difference. The first
+// call for a plugin records the baseline later changes are compared with.
+func (gs *GatewayServer) detectCapabilityChanges(pluginName string) {
+	if gs.discoveryEngine == nil {
+		return
+	}
+	if _, err := gs.discoveryEngine.DetectCapabilityChanges(context.Background(), pluginName); err != nil {
+		gs.logger.Warn("plugin_capability_change_detection_failed",
+			"plugin", pluginName,
+			"error", err)
+	}
+}
+
+// sseConnection tracks an SSE stream for shutdown draining
+type sseConnection struct {
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	done         chan struct{}
+	cancel       context.CancelFunc
+}
+
+func (c *sseConnection) NotifyShutdown() error {
+	c.shutdownOnce.Do(func() { close(c.shutdown) })
+	return nil
+}
+
+func (c *sseConnection) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *sseConnection) Close() error {
+	c.cancel()
+	return nil
+}
+
+// handleNotificationStream streams notifications as server-sent events. The
+// stream is authenticated like /mcp and only carries notifications addressed
+// to the caller or to everyone. Each event carries an id so a reconnecting
+// client can send Last-Event-ID and receive what it missed, as far as the
+// retained history allows.
+func (gs *GatewayServer) handleNotificationStream(w http.ResponseWriter, r *http.Request) {
+	// Keeps the session's resource subscriptions alive across reconnects
+	subscriber, disconnected, err := gs.mcpRouter.ConnectNotificationStream(r)
+	if err != nil {
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+	defer disconnected()
+
+	controller := http.NewResponseController(w)
+
+	// Streams outlive the server write timeout, so lift it for this response
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		gs.logger.Warn("sse_write_deadline_reset_failed", "error", err)
+	}
+
+	var lastEventID uint64
+	resuming := false
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			gs.logger.Debug("sse_invalid_last_event_id", "last_event_id", header)
+		} else {
+			lastEventID, resuming = id, true
+		}
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	conn := &sseConnection{
+		shutdown: make(chan struct{}),
+		done:     make(chan struct{}),
+		cancel:   cancel,
+	}
+	defer close(conn.done)
+	untrack := gs.TrackStreamConnection(conn)
+	defer untrack()
+
+	sub, replay, complete := gs.notifications.Subscribe(subscriber, lastEventID, resuming)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	gs.metrics.Inc("sse_connections_total")
+	gs.metrics.Set("sse_connections_active", float64(gs.notifications.SubscriberCount()))
+	defer func() {
+		gs.metrics.Set("sse_connections_active", float64(gs.notifications.SubscriberCount()))
+	}()
+
+	gs.logger.Info("sse_stream_opened",
+		"remote_addr", r.RemoteAddr,
+		"user_id", subscriber.UserID,
+		"session_id", subscriber.SessionID,
+		"last_event_id", lastEventID,
+		"replayed", len(replay))
+	if !complete {
+		gs.metrics.Inc("sse_replay_incomplete_total")
+		gs.logger.Warn("sse_replay_incomplete",
+			"remote_addr", r.RemoteAddr,
+			"last_event_id", lastEventID)
+	}
+
+	send := func(notification Notification) error {
+		if err := writeNotificationEvent(w, notification); err != nil {
+			return err
+		}
+		gs.metrics.Inc("sse_notifications_sent_total", "method", notification.Method)
+		return controller.Flush()
+	}
+
+	reason := "client_disconnected"
+	defer func() {
+		gs.logger.Info("sse_stream_closed",
+			"remote_addr", r.RemoteAddr,
+			"reason", reason)
+	}()
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis); err != nil {
+		return
+	}
+	for _, notification := range replay {
+		if err := send(notification); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(sseKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case notification, ok := <-sub.C():
+			if !ok {
+				// Fell too far behind; the client resumes from its last event ID
+				reason = "subscriber_lagging"
+				gs.metrics.Inc("sse_subscribers_dropped_total")
+				return
+			}
+			if err := send(notification); err != nil {
+				reason = "write_failed"
+				return
+			}
+
+		case <-keepalive.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				reason = "write_failed"
+				return
+			}
+			if err := controller.Flush(); err != nil {
+				reason = "write_failed"
+				return
+			}
+
+		case <-conn.shutdown:
+			reason = "server_shutdown"
+			io.WriteString(w, "event: shutdown\ndata: {}\n\n")
+			controller.Flush()
+			return
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// writeNotificationEvent writes a notification as a JSON-RPC message event
+func writeNotificationEvent(w io.Writer, notification Notification) error {
+	data, err := json.Marshal(struct {
+		JSONRPC string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{
+		JSONRPC: "2.0",
+		Method:  notification.Method,
+		Params:  notification.Params,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", notification.ID, data)
+	return err
+}
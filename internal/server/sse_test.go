@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/router"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestNotificationStream tests that SSE clients receive pushed notifications and can resume after reconnecting
+func TestNotificationStream(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	httpServer := httptest.NewServer(server.httpServer.Handler)
+	defer httpServer.Close()
+
+	connectSession := func(t *testing.T, sessionID, lastEventID string) (*http.Response, *bufio.Reader) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", httpServer.URL+NotificationStreamPath, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		if sessionID != "" {
+			req.Header.Set("X-Session-ID", sessionID)
+		}
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("expected text/event-stream, got %q", ct)
+		}
+		if resp.Header.Get("Content-Encoding") != "" {
+			t.Fatalf("expected stream not to be compressed")
+		}
+
+		reader := bufio.NewReader(resp.Body)
+		if frame := readSSEFrame(t, reader); !strings.HasPrefix(frame, "retry: ") {
+			t.Fatalf("expected retry hint first, got %q", frame)
+		}
+		return resp, reader
+	}
+	connect := func(t *testing.T, lastEventID string) (*http.Response, *bufio.Reader) {
+		t.Helper()
+		return connectSession(t, "", lastEventID)
+	}
+
+	resp, reader := connect(t, "")
+
+	t.Run("pushed notification is delivered with an event id", func(t *testing.T) {
+		server.PublishNotification("notifications/resources/updated", map[string]interface{}{"uri": "file:///a.txt"}, nil)
+
+		frame := readSSEFrame(t, reader)
+		for _, line := range []string{
+			"id: 1",
+			"event: message",
+			`data: {"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":"file:///a.txt"}}`,
+		} {
+			if !strings.Contains(frame, line) {
+				t.Errorf("expected frame to contain %q, got %q", line, frame)
+			}
+		}
+	})
+
+	t.Run("client disconnect releases the subscription", func(t *testing.T) {
+		resp.Body.Close()
+
+		deadline := time.Now().Add(2 * time.Second)
+		for server.notifications.SubscriberCount() != 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("expected subscription to end after client disconnect")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("reconnect with Last-Event-ID replays missed notifications", func(t *testing.T) {
+		server.PublishNotification("notifications/tools/list_changed", nil, nil)
+
+		resp, reader := connect(t, "1")
+		defer resp.Body.Close()
+
+		frame := readSSEFrame(t, reader)
+		if !strings.Contains(frame, "id: 2") || !strings.Contains(frame, "notifications/tools/list_changed") {
+			t.Errorf("expected missed notification 2 to be replayed, got %q", frame)
+		}
+	})
+
+	t.Run("addressed notifications reach only their session", func(t *testing.T) {
+		mine := []router.Subscriber{{UserID: "anonymous", SessionID: "mine"}}
+		server.PublishNotification("notifications/resources/updated", map[string]interface{}{"uri": "file:///mine.txt"}, mine)
+
+		// Replay skips the other session's notification 3
+		other, otherReader := connectSession(t, "other", "2")
+		defer other.Body.Close()
+		resp, reader := connectSession(t, "mine", "2")
+		defer resp.Body.Close()
+
+		if frame := readSSEFrame(t, reader); !strings.Contains(frame, "id: 3") {
+			t.Errorf("expected notification 3 to be replayed to its session, got %q", frame)
+		}
+
+		server.PublishNotification("notifications/resources/updated", map[string]interface{}{"uri": "file:///mine.txt"}, mine)
+		server.PublishNotification("notifications/tools/list_changed", nil, nil)
+
+		if frame := readSSEFrame(t, otherReader); !strings.Contains(frame, "id: 5") {
+			t.Errorf("expected the other session to receive only the broadcast 5, got %q", frame)
+		}
+		if frame := readSSEFrame(t, reader); !strings.Contains(frame, "id: 4") {
+			t.Errorf("expected notification 4 to reach its session, got %q", frame)
+		}
+	})
+
+	t.Run("progress is delivered but not replayed", func(t *testing.T) {
+		subscriber := router.Subscriber{UserID: "anonymous", SessionID: "mine"}
+		sub, _, _ := server.notifications.Subscribe(subscriber, 0, false)
+		defer sub.Close()
+
+		progress := server.notifications.Publish("notifications/progress", map[string]interface{}{"progress": 1}, []router.Subscriber{subscriber})
+		select {
+		case notification := <-sub.C():
+			if notification.ID != progress.ID {
+				t.Errorf("expected progress %d to be delivered, got %d", progress.ID, notification.ID)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected progress to be delivered to its session")
+		}
+
+		resumed, replay, complete := server.notifications.Subscribe(subscriber, progress.ID-1, true)
+		defer resumed.Close()
+		if len(replay) != 0 || !complete {
+			t.Errorf("expected no replay of progress and a complete history, got %d replayed, complete=%v", len(replay), complete)
+		}
+	})
+}
+
+// readSSEFrame reads one blank-line terminated SSE frame
+func readSSEFrame(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+
+	type result struct {
+		frame string
+		err   error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		var frame strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				ch <- result{frame.String(), err}
+				return
+			}
+			if line == "\n" {
+				ch <- result{frame.String(), nil}
+				return
+			}
+			frame.WriteString(line)
+		}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("failed to read SSE frame: %v", r.err)
+		}
+		return r.frame
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for SSE frame")
+		return ""
+	}
+}
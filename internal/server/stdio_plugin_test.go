@@ -0,0 +1,160 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestStdioPluginHelperProcess is not a real test: it runs a mock MCP server
+// on stdin/stdout when launched as a subprocess by TestStdioPlugin
+func TestStdioPluginHelperProcess(t *testing.T) {
+	if os.Getenv("MCPEG_STDIO_PLUGIN_HELPER") != "1" {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req struct {
+			ID     *int64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == nil {
+			continue
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "initialize":
+			result = map[string]interface{}{
+				"protocolVersion": "2024-11-05",
+				"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+				"serverInfo":      map[string]interface{}{"name": "mock-stdio", "version": "1.0.0"},
+			}
+		case "tools/list":
+			result = map[string]interface{}{"tools": []map[string]interface{}{
+				{"name": "shout", "description": "Upper-cases a message", "inputSchema": map[string]interface{}{"type": "object"}},
+				{"name": "crash", "description": "Exits the server", "inputSchema": map[string]interface{}{"type": "object"}},
+			}}
+		case "tools/call":
+			var params struct {
+				Name      string `json:"name"`
+				Arguments struct {
+					Message string `json:"message"`
+				} `json:"arguments"`
+			}
+			json.Unmarshal(req.Params, &params)
+			if params.Name == "crash" {
+				os.Exit(1)
+			}
+			result = map[string]interface{}{"content": []map[string]interface{}{
+				{"type": "text", "text": strings.ToUpper(params.Arguments.Message)},
+			}}
+		default:
+			result = map[string]interface{}{}
+		}
+
+		data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": *req.ID, "result": result})
+		fmt.Println(string(data))
+	}
+	os.Exit(0)
+}
+
+// TestStdioPlugin tests invoking a subprocess MCP server's tool through the gateway and its restart on crash
+func TestStdioPlugin(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	plugin := plugins.NewStdioPlugin(plugins.StdioPluginConfig{
+		Name:           "mock-stdio",
+		Command:        os.Args[0],
+		Args:           []string{"-test.run=^TestStdioPluginHelperProcess$"},
+		Env:            map[string]string{"MCPEG_STDIO_PLUGIN_HELPER": "1"},
+		RequestTimeout: 5 * time.Second,
+		RestartDelay:   10 * time.Millisecond,
+	})
+	manager := server.pluginIntegration.GetPluginManager()
+	if err := manager.RegisterPlugin(plugin); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+	if err := manager.InitializePlugin(context.Background(), "mock-stdio", plugins.PluginConfig{Name: "mock-stdio"}); err != nil {
+		t.Fatalf("failed to start stdio plugin: %v", err)
+	}
+	defer plugin.Shutdown(context.Background())
+
+	callShout := func(t *testing.T) string {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params":  map[string]interface{}{"name": "shout", "arguments": map[string]interface{}{"message": "hello"}},
+		})
+		req := httptest.NewRequest("POST", "/mcp", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return w.Body.String()
+	}
+
+	t.Run("tools are discovered from the subprocess", func(t *testing.T) {
+		tools := plugin.GetTools()
+		if len(tools) != 2 || tools[0].Name != "shout" {
+			t.Errorf("expected shout and crash tools, got %+v", tools)
+		}
+	})
+
+	t.Run("tool call is forwarded through the gateway", func(t *testing.T) {
+		if body := callShout(t); !strings.Contains(body, `"text":"HELLO"`) {
+			t.Errorf("expected the subprocess result in the response, got %s", body)
+		}
+	})
+
+	t.Run("crashed subprocess is restarted", func(t *testing.T) {
+		if _, err := plugin.CallTool(context.Background(), "crash", nil); err == nil {
+			t.Fatal("expected the call that crashed the subprocess to fail")
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for plugin.HealthCheck(context.Background()) != nil {
+			if time.Now().After(deadline) {
+				t.Fatal("expected the subprocess to be restarted")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if body := callShout(t); !strings.Contains(body, `"text":"HELLO"`) {
+			t.Errorf("expected the restarted subprocess to serve calls, got %s", body)
+		}
+	})
+
+	t.Run("shutdown stops the subprocess", func(t *testing.T) {
+		if err := plugin.Shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown failed: %v", err)
+		}
+		if err := plugin.HealthCheck(context.Background()); err == nil {
+			t.Error("expected the plugin to be unhealthy after shutdown")
+		}
+	})
+}
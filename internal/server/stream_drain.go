@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+)
+
+// StreamConnection is a long-lived client connection, such as a WebSocket or
+// SSE stream, that must be drained explicitly when the gateway shuts down
+type StreamConnection interface {
+	// NotifyShutdown sends a close frame (WebSocket) or shutdown event (SSE) to the client
+	NotifyShutdown() error
+
+	// Done is closed once the client has disconnected
+	Done() <-chan struct{}
+
+	// Close forcibly terminates the connection
+	Close() error
+}
+
+// TrackStreamConnection registers a streaming connection for shutdown draining.
+// The returned function must be called when the connection ends.
+func (gs *GatewayServer) TrackStreamConnection(conn StreamConnection) func() {
+	gs.streamMutex.Lock()
+	gs.streamConns[conn] = struct{}{}
+	gs.streamMutex.Unlock()
+
+	return func() {
+		gs.streamMutex.Lock()
+		delete(gs.streamConns, conn)
+		gs.streamMutex.Unlock()
+	}
+}
+
+// streamConnections returns the currently tracked streaming connections
+func (gs *GatewayServer) streamConnections() []StreamConnection {
+	gs.streamMutex.Lock()
+	defer gs.streamMutex.Unlock()
+	conns := make([]StreamConnection, 0, len(gs.streamConns))
+	for conn := range gs.streamConns {
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+// drainStreamConnections notifies all streaming connections of the shutdown, waits
+// until they disconnect or ctx expires, then force-closes any that remain
+func (gs *GatewayServer) drainStreamConnections(ctx context.Context) (graceful, forced int) {
+	conns := gs.streamConnections()
+	if len(conns) == 0 {
+		return 0, 0
+	}
+
+	gs.logger.Info("stream_connections_draining", "count", len(conns))
+
+	for _, conn := range conns {
+		if err := conn.NotifyShutdown(); err != nil {
+			gs.logger.Warn("stream_shutdown_notify_failed", "error", err)
+		}
+	}
+
+	for _, conn := range conns {
+		select {
+		case <-conn.Done():
+			graceful++
+			continue
+		case <-ctx.Done():
+		}
+
+		// Deadline passed; anything still open is closed without waiting
+		select {
+		case <-conn.Done():
+			graceful++
+		default:
+			if err := conn.Close(); err != nil {
+				gs.logger.Warn("stream_force_close_failed", "error", err)
+			}
+			forced++
+		}
+	}
+
+	gs.metrics.Add("stream_connections_drained_total", float64(graceful), "mode", "graceful")
+	gs.metrics.Add("stream_connections_drained_total", float64(forced), "mode", "forced")
+	gs.logger.Info("stream_connections_drained",
+		"graceful", graceful,
+		"forced", forced)
+
+	return graceful, forced
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestStreamConnectionDrain tests graceful and forced draining of streaming connections
+func TestStreamConnectionDrain(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{}, logger, mockMetrics, validator, healthMgr)
+
+	cooperative := newFakeStreamConnection(true)
+	stubborn := newFakeStreamConnection(false)
+	departed := newFakeStreamConnection(true)
+
+	server.TrackStreamConnection(cooperative)
+	server.TrackStreamConnection(stubborn)
+	untrack := server.TrackStreamConnection(departed)
+	untrack()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	graceful, forced := server.drainStreamConnections(ctx)
+
+	if graceful != 1 || forced != 1 {
+		t.Errorf("expected 1 graceful and 1 forced close, got %d graceful and %d forced", graceful, forced)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected drain to finish shortly after the timeout, took %v", elapsed)
+	}
+
+	if !cooperative.notified || cooperative.forced {
+		t.Error("expected cooperative connection to be notified and close on its own")
+	}
+	if !stubborn.notified || !stubborn.forced {
+		t.Error("expected stubborn connection to be notified and then force-closed")
+	}
+	if departed.notified {
+		t.Error("expected untracked connection not to be notified")
+	}
+}
+
+// fakeStreamConnection simulates a client that may or may not honour shutdown notices
+type fakeStreamConnection struct {
+	cooperative bool
+	done        chan struct{}
+	once        sync.Once
+	notified    bool
+	forced      bool
+}
+
+func newFakeStreamConnection(cooperative bool) *fakeStreamConnection {
+	return &fakeStreamConnection{cooperative: cooperative, done: make(chan struct{})}
+}
+
+func (c *fakeStreamConnection) NotifyShutdown() error {
+	c.notified = true
+	if c.cooperative {
+		c.once.Do(func() { close(c.done) })
+	}
+	return nil
+}
+
+func (c *fakeStreamConnection) Done() <-chan struct{} { return c.done }
+
+func (c *fakeStreamConnection) Close() error {
+	c.forced = true
+	c.once.Do(func() { close(c.done) })
+	return nil
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/osakka/mcpeg/internal/registry"
+)
+
+// ToolSchema holds the schemas a plugin publishes for one of its tools
+type ToolSchema struct {
+	Plugin       string                 `json:"plugin"`
+	Tool         string                 `json:"tool"`
+	InputSchema  map[string]interface{} `json:"input_schema,omitempty"`
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty"`
+}
+
+// toolSchemaCache caches plugin tool schemas so argument validation does not
+// have to query plugins on every call. Entries are replaced per plugin when the
+// plugin is initialized, reloaded or listed through the admin API.
+type toolSchemaCache struct {
+	mutex   sync.RWMutex
+	schemas map[string]map[string]ToolSchema // plugin -> tool -> schema
+}
+
+func newToolSchemaCache() *toolSchemaCache {
+	return &toolSchemaCache{
+		schemas: make(map[string]map[string]ToolSchema),
+	}
+}
+
+// store replaces the cached schemas for a plugin
+func (c *toolSchemaCache) store(pluginName string, tools []registry.ToolDefinition) {
+	schemas := make(map[string]ToolSchema, len(tools))
+	for _, tool := range tools {
+		schemas[tool.Name] = ToolSchema{
+			Plugin:       pluginName,
+			Tool:         tool.Name,
+			InputSchema:  tool.InputSchema,
+			OutputSchema: tool.OutputSchema,
+		}
+	}
+
+	c.mutex.Lock()
+	c.schemas[pluginName] = schemas
+	c.mutex.Unlock()
+}
+
+func (c *toolSchemaCache) get(pluginName, toolName string) (ToolSchema, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	schema, ok := c.schemas[pluginName][toolName]
+	return schema, ok
+}
+
+// refreshToolSchemas reloads a plugin's tool definitions into the schema cache
+func (gs *GatewayServer) refreshToolSchemas(pluginName string) ([]registry.ToolDefinition, error) {
+	plugin, exists := gs.pluginIntegration.GetPluginManager().GetPlugin(pluginName)
+	if !exists {
+		return nil, fmt.Errorf("plugin %s not found", pluginName)
+	}
+
+	tools := plugin.GetTools()
+	gs.toolSchemas.store(pluginName, tools)
+
+	withoutSchema := 0
+	for _, tool := range tools {
+		if len(tool.InputSchema) == 0 {
+			withoutSchema++
+		}
+	}
+	if withoutSchema > 0 {
+		gs.logger.Warn("plugin_tools_missing_input_schema",
+			"plugin", pluginName,
+			"tools", len(tools),
+			"without_input_schema", withoutSchema)
+	}
+	gs.metrics.Set("plugin_tools_without_input_schema", float64(withoutSchema), "plugin", pluginName)
+
+	return tools, nil
+}
+
+// refreshAllToolSchemas populates the schema cache for every loaded plugin
+func (gs *GatewayServer) refreshAllToolSchemas() {
+	for _, pluginName := range gs.pluginIntegration.GetPluginManager().GetPlugins() {
+		if _, err := gs.refreshToolSchemas(pluginName); err != nil {
+			gs.logger.Warn("tool_schema_refresh_failed",
+				"plugin", pluginName,
+				"error", err)
+		}
+	}
+}
+
+// ToolInputSchema returns the cached input schema for a plugin tool. ok is
+// false when the tool is unknown or publishes no input schema.
+func (gs *GatewayServer) ToolInputSchema(pluginName, toolName string) (schema map[string]interface{}, ok bool) {
+	cached, found := gs.toolSchemas.get(pluginName, toolName)
+	if !found || len(cached.InputSchema) == 0 {
+		return nil, false
+	}
+	return cached.InputSchema, true
+}
+
+// describeToolSchemas renders tool definitions for the admin API, publishing
+// full input and output schemas and flagging tools that have no input schema
+func describeToolSchemas(tools []registry.ToolDefinition) (described []map[string]interface{}, withoutSchema []string) {
+	described = make([]map[string]interface{}, 0, len(tools))
+	withoutSchema = make([]string, 0)
+
+	for _, tool := range tools {
+		hasInputSchema := len(tool.InputSchema) > 0
+		entry := map[string]interface{}{
+			"name":             tool.Name,
+			"description":      tool.Description,
+			"input_schema":     tool.InputSchema,
+			"has_input_schema": hasInputSchema,
+		}
+		if tool.Category != "" {
+			entry["category"] = tool.Category
+		}
+		if len(tool.OutputSchema) > 0 {
+			entry["output_schema"] = tool.OutputSchema
+		}
+		if len(tool.Examples) > 0 {
+			entry["examples"] = tool.Examples
+		}
+		if len(tool.Metadata) > 0 {
+			entry["metadata"] = tool.Metadata
+		}
+		if !hasInputSchema {
+			withoutSchema = append(withoutSchema, tool.Name)
+		}
+		described = append(described, entry)
+	}
+
+	return described, withoutSchema
+}
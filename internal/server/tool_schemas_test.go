@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestPluginToolSchemas tests that the admin tools listing publishes schemas and flags tools without one
+func TestPluginToolSchemas(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{EnableAdminEndpoints: true}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	inputSchema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{"type": "string"},
+		},
+		"required": []interface{}{"query"},
+	}
+	outputSchema := map[string]interface{}{"type": "array"}
+
+	if err := server.pluginIntegration.GetPluginManager().RegisterPlugin(&schemaTestPlugin{
+		tools: []registry.ToolDefinition{
+			{Name: "search", Description: "Search things", InputSchema: inputSchema, OutputSchema: outputSchema},
+			{Name: "ping", Description: "No arguments described"},
+		},
+	}); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/admin/plugins/schema-test/tools", nil)
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Tools []struct {
+			Name           string                 `json:"name"`
+			InputSchema    map[string]interface{} `json:"input_schema"`
+			OutputSchema   map[string]interface{} `json:"output_schema"`
+			HasInputSchema bool                   `json:"has_input_schema"`
+		} `json:"tools"`
+		ToolsWithoutSchema []string `json:"tools_without_schema"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	tools := make(map[string]int)
+	for i, tool := range resp.Tools {
+		tools[tool.Name] = i
+	}
+
+	t.Run("listing includes full input and output schemas", func(t *testing.T) {
+		search := resp.Tools[tools["search"]]
+		if !search.HasInputSchema {
+			t.Error("expected search to be marked as having an input schema")
+		}
+		if required, _ := search.InputSchema["required"].([]interface{}); len(required) != 1 || required[0] != "query" {
+			t.Errorf("expected full input schema with required fields, got %v", search.InputSchema)
+		}
+		if search.OutputSchema["type"] != "array" {
+			t.Errorf("expected output schema to be published, got %v", search.OutputSchema)
+		}
+	})
+
+	t.Run("tool without a schema is flagged", func(t *testing.T) {
+		if resp.Tools[tools["ping"]].HasInputSchema {
+			t.Error("expected ping to be marked as having no input schema")
+		}
+		if len(resp.ToolsWithoutSchema) != 1 || resp.ToolsWithoutSchema[0] != "ping" {
+			t.Errorf("expected tools_without_schema [ping], got %v", resp.ToolsWithoutSchema)
+		}
+	})
+
+	t.Run("schemas are cached for argument validation", func(t *testing.T) {
+		if schema, ok := server.ToolInputSchema("schema-test", "search"); !ok || schema["type"] != "object" {
+			t.Errorf("expected cached input schema for search, got %v %v", schema, ok)
+		}
+		if _, ok := server.ToolInputSchema("schema-test", "ping"); ok {
+			t.Error("expected no cached input schema for ping")
+		}
+	})
+
+	t.Run("tools/call arguments are checked against the cached schema", func(t *testing.T) {
+		call := func(tool string, arguments map[string]interface{}) map[string]interface{} {
+			body, _ := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"method":  "tools/call",
+				"params":  map[string]interface{}{"name": tool, "arguments": arguments},
+			})
+			req := httptest.NewRequest("POST", "/mcp", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+			}
+			return resp
+		}
+
+		resp := call("schema-test.search", map[string]interface{}{"query": 42})
+		rpcErr, _ := resp["error"].(map[string]interface{})
+		if rpcErr == nil || rpcErr["code"] != float64(-32602) {
+			t.Errorf("expected invalid params for a mistyped argument, got %v", resp)
+		}
+
+		if resp := call("schema-test.search", map[string]interface{}{}); resp["error"] == nil {
+			t.Errorf("expected a missing required argument to be rejected, got %v", resp)
+		}
+		if resp := call("schema-test.search", map[string]interface{}{"query": "weather"}); resp["error"] != nil {
+			t.Errorf("expected valid arguments to reach the plugin, got %v", resp)
+		}
+		if resp := call("schema-test.ping", map[string]interface{}{"anything": true}); resp["error"] != nil {
+			t.Errorf("expected a tool without a schema to accept any arguments, got %v", resp)
+		}
+	})
+}
+
+// schemaTestPlugin publishes fixed tool definitions; other Plugin methods are unused
+type schemaTestPlugin struct {
+	plugins.Plugin
+	tools []registry.ToolDefinition
+}
+
+func (p *schemaTestPlugin) Name() string                                { return "schema-test" }
+func (p *schemaTestPlugin) Version() string                             { return "1.0.0" }
+func (p *schemaTestPlugin) Description() string                         { return "Schema publishing test plugin" }
+func (p *schemaTestPlugin) GetTools() []registry.ToolDefinition         { return p.tools }
+func (p *schemaTestPlugin) GetResources() []registry.ResourceDefinition { return nil }
+func (p *schemaTestPlugin) GetPrompts() []registry.PromptDefinition     { return nil }
+func (p *schemaTestPlugin) CallTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	return "ok", nil
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/router"
+)
+
+// defaultTraceForceHeader lets a caller trace one request regardless of the
+// sampling rate
+const defaultTraceForceHeader = "X-MCPEG-Trace"
+
+// TraceSamplingConfig selects the requests that get detailed trace logging.
+// Requests carrying a traceparent header follow the caller's sampled flag;
+// other requests are sampled at Rate. Sending the force header with a true
+// value samples a request unconditionally.
+type TraceSamplingConfig struct {
+	Rate        float64 `yaml:"rate"`         // Fraction of requests sampled, 0 to 1
+	ForceHeader string  `yaml:"force_header"` // Header forcing a request to be sampled
+}
+
+// Validate checks the sampling rate
+func (c TraceSamplingConfig) Validate() error {
+	if c.Rate < 0 || c.Rate > 1 {
+		return fmt.Errorf("trace sampling rate must be between 0 and 1, got %v", c.Rate)
+	}
+	return nil
+}
+
+// Reasons recorded for a sampling decision
+const (
+	traceSampledByParent = "parent"
+	traceSampledByForce  = "forced"
+	traceSampledByRate   = "rate"
+)
+
+type traceContextKey struct{}
+
+// requestTrace is the gateway span of one request
+type requestTrace struct {
+	router.TraceParent
+	ParentSpanID string // Caller's span, empty when the gateway started the trace
+	Reason       string // Why the request was or was not sampled
+}
+
+// traceFromContext returns the request's trace, or nil outside traceMiddleware
+func traceFromContext(ctx context.Context) *requestTrace {
+	trace, _ := ctx.Value(traceContextKey{}).(*requestTrace)
+	return trace
+}
+
+// traceMiddleware starts a gateway span for every request and decides whether
+// it is sampled. The span replaces the inbound traceparent header so backends
+// see the gateway as their parent, and is echoed in the response.
+func (gs *GatewayServer) traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace := gs.startTrace(r)
+
+		r.Header.Set(router.TraceParentHeader, trace.String())
+		w.Header().Set(router.TraceParentHeader, trace.String())
+		gs.metrics.Inc("trace_sampling_decisions_total",
+			"sampled", fmt.Sprintf("%t", trace.Sampled),
+			"reason", trace.Reason)
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), traceContextKey{}, trace)))
+	})
+}
+
+// startTrace continues the caller's trace when it sent a valid traceparent
+// header and starts a new one otherwise
+func (gs *GatewayServer) startTrace(r *http.Request) *requestTrace {
+	trace := &requestTrace{TraceParent: router.TraceParent{SpanID: router.NewSpanID()}}
+
+	if parent, ok := router.ParseTraceParent(r.Header.Get(router.TraceParentHeader)); ok {
+		trace.TraceID = parent.TraceID
+		trace.ParentSpanID = parent.SpanID
+		trace.Sampled = parent.Sampled
+		trace.Reason = traceSampledByParent
+	} else {
+		trace.TraceID = router.NewTraceID()
+		trace.Sampled = gs.config.TraceSampling.Rate > 0 && rand.Float64() < gs.config.TraceSampling.Rate
+		trace.Reason = traceSampledByRate
+	}
+
+	if forced, _ := strconv.ParseBool(r.Header.Get(gs.config.TraceSampling.ForceHeader)); forced && !trace.Sampled {
+		trace.Sampled = true
+		trace.Reason = traceSampledByForce
+	}
+	return trace
+}
+
+// serveTraced handles a sampled request with span logging detailed enough to
+// follow it without a tracing backend
+func (gs *GatewayServer) serveTraced(w http.ResponseWriter, r *http.Request, next http.Handler, trace *requestTrace) {
+	start := time.Now()
+	requestID := r.Header.Get(gs.config.RequestIDHeader)
+
+	headerNames := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	gs.logger.Info("trace_span_started",
+		"request_id", requestID,
+		"trace_id", trace.TraceID,
+		"span_id", trace.SpanID,
+		"parent_span_id", trace.ParentSpanID,
+		"sampling_reason", trace.Reason,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"query", r.URL.RawQuery,
+		"proto", r.Proto,
+		"remote_addr", r.RemoteAddr,
+		"user_agent", r.UserAgent(),
+		"content_length", r.ContentLength,
+		"header_names", headerNames)
+
+	next.ServeHTTP(w, r)
+
+	gs.logger.Info("trace_span_completed",
+		"request_id", requestID,
+		"trace_id", trace.TraceID,
+		"span_id", trace.SpanID,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"route", metricsRouteLabel(r),
+		"content_type", w.Header().Get("Content-Type"),
+		"duration", time.Since(start))
+}
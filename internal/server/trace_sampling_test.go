@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osakka/mcpeg/internal/router"
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestTraceSampling tests the sampling rate, the force header and propagation of the caller's decision
+func TestTraceSampling(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	validator := validation.NewValidator(logger, mockMetrics)
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	server := NewGatewayServer(ServerConfig{
+		EnableHealthEndpoints: true,
+		TraceSampling:         TraceSamplingConfig{Rate: 0.2},
+	}, logger, mockMetrics, validator, healthMgr)
+	defer server.registry.Shutdown()
+
+	trace := func(t *testing.T, headers map[string]string) router.TraceParent {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/health/live", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		tp, ok := router.ParseTraceParent(w.Header().Get(router.TraceParentHeader))
+		if !ok {
+			t.Fatalf("expected a valid traceparent response header, got %q", w.Header().Get(router.TraceParentHeader))
+		}
+		return tp
+	}
+
+	t.Run("sampling rate is approximately honored", func(t *testing.T) {
+		const requests = 2000
+		sampled := 0
+		for i := 0; i < requests; i++ {
+			if trace(t, nil).Sampled {
+				sampled++
+			}
+		}
+		if rate := float64(sampled) / requests; rate < 0.15 || rate > 0.25 {
+			t.Errorf("expected a sampled fraction near 0.2, got %.3f", rate)
+		}
+	})
+
+	t.Run("force header always samples", func(t *testing.T) {
+		parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+		for i := 0; i < 100; i++ {
+			if !trace(t, map[string]string{defaultTraceForceHeader: "true"}).Sampled {
+				t.Fatal("expected a forced request to be sampled")
+			}
+		}
+		if !trace(t, map[string]string{defaultTraceForceHeader: "1", router.TraceParentHeader: parent}).Sampled {
+			t.Error("expected the force header to override an unsampled parent")
+		}
+	})
+
+	t.Run("caller's trace and sampling decision are continued", func(t *testing.T) {
+		for _, flags := range []string{"00", "01"} {
+			parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-" + flags
+			for i := 0; i < 20; i++ {
+				tp := trace(t, map[string]string{router.TraceParentHeader: parent})
+				if tp.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || tp.SpanID == "00f067aa0ba902b7" {
+					t.Fatalf("expected a new span in the caller's trace, got %s", tp)
+				}
+				if tp.Sampled != (flags == "01") {
+					t.Fatalf("expected the sampled flag of %s to be kept, got %s", parent, tp)
+				}
+			}
+		}
+	})
+}
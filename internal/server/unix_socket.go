@@ -0,0 +1,144 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// defaultUnixSocketMode lets the gateway user and its group connect
+	defaultUnixSocketMode os.FileMode = 0660
+
+	// unixSocketProbeTimeout bounds the dial that checks whether an existing
+	// socket is still served
+	unixSocketProbeTimeout = time.Second
+)
+
+// UnixSocketConfig serves the gateway on a Unix domain socket, for local
+// proxies that should not go through TCP. The socket serves every endpoint
+// the TCP listener does, in plain HTTP, and is removed on shutdown.
+type UnixSocketConfig struct {
+	Path       string `yaml:"path"`        // Socket file; empty disables the listener
+	Mode       string `yaml:"mode"`        // Octal file permissions, 0660 by default
+	DisableTCP bool   `yaml:"disable_tcp"` // Serve only on the socket
+}
+
+// Validate checks the socket permissions and that TCP is only disabled when
+// the socket replaces it
+func (c UnixSocketConfig) Validate() error {
+	if c.DisableTCP && c.Path == "" {
+		return fmt.Errorf("disable_tcp requires a socket path")
+	}
+	if _, err := c.fileMode(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c UnixSocketConfig) fileMode() (os.FileMode, error) {
+	if c.Mode == "" {
+		return defaultUnixSocketMode, nil
+	}
+	mode, err := strconv.ParseUint(c.Mode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid socket mode %q: expected octal permissions such as 0660", c.Mode)
+	}
+	return os.FileMode(mode), nil
+}
+
+// listenUnixSocket creates the configured socket with its permissions,
+// replacing one left behind by a previous run
+func (gs *GatewayServer) listenUnixSocket() (net.Listener, error) {
+	config := gs.config.UnixSocket
+	mode, err := config.fileMode()
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Lstat(config.Path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", config.Path)
+		}
+		if err := probeStaleSocket(config.Path); err != nil {
+			return nil, err
+		}
+		if err := os.Remove(config.Path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", config.Path, err)
+		}
+		gs.logger.Warn("unix_socket_stale_removed", "path", config.Path)
+	}
+
+	var listener net.Listener
+	err = withSocketMode(mode, func() error {
+		listener, err = net.Listen("unix", config.Path)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", config.Path, err)
+	}
+	if err := os.Chmod(config.Path, mode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set permissions on %s: %w", config.Path, err)
+	}
+
+	// Closing the listener must not unlink a socket that a restarted gateway
+	// has replaced; removeUnixSocket deletes it only if it is still ours
+	if unixListener, ok := listener.(*net.UnixListener); ok {
+		unixListener.SetUnlinkOnClose(false)
+	}
+	if info, err := os.Lstat(config.Path); err == nil {
+		gs.unixSocketFile = info
+	}
+
+	gs.logger.Info("unix_socket_listening",
+		"path", config.Path,
+		"mode", fmt.Sprintf("%04o", mode))
+	return listener, nil
+}
+
+// probeStaleSocket dials an existing socket and returns an error unless
+// nothing is listening on it, so a running gateway's socket is never removed
+func probeStaleSocket(path string) error {
+	conn, err := net.DialTimeout("unix", path, unixSocketProbeTimeout)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is in use by another process", path)
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return nil
+	}
+	return fmt.Errorf("failed to check whether %s is in use: %w", path, err)
+}
+
+// removeUnixSocket deletes the socket file once the HTTP server has stopped,
+// in case closing the listener did not
+func (gs *GatewayServer) removeUnixSocket() error {
+	path := gs.config.UnixSocket.Path
+	if path == "" {
+		return nil
+	}
+
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	if gs.unixSocketFile != nil && !os.SameFile(gs.unixSocketFile, info) {
+		gs.logger.Info("unix_socket_replaced_not_removed", "path", path)
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove socket %s: %w", path, err)
+	}
+	return nil
+}
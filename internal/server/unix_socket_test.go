@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/health"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/validation"
+)
+
+// TestUnixSocketListener tests that the gateway serves MCP and health
+// requests on a Unix socket and removes the socket on shutdown
+func TestUnixSocketListener(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	socketPath := filepath.Join(t.TempDir(), "gateway.sock")
+	server := NewGatewayServer(ServerConfig{
+		ShutdownTimeout:       5 * time.Second,
+		EnableHealthEndpoints: true,
+		UnixSocket:            UnixSocketConfig{Path: socketPath, Mode: "0600", DisableTCP: true},
+	}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.Start(ctx) }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if info, err := os.Stat(socketPath); err == nil && info.Mode()&os.ModeSocket != 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the socket to be created")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	t.Run("socket has the configured permissions", func(t *testing.T) {
+		info, err := os.Stat(socketPath)
+		if err != nil {
+			t.Fatalf("failed to stat socket: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600, got %04o", info.Mode().Perm())
+		}
+	})
+
+	t.Run("MCP requests are served", func(t *testing.T) {
+		resp, err := client.Post("http://gateway/mcp", "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		if err != nil {
+			t.Fatalf("request over the socket failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("health checks are served", func(t *testing.T) {
+		resp, err := client.Get("http://gateway/health")
+		if err != nil {
+			t.Fatalf("health check over the socket failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	client.CloseIdleConnections()
+	cancel()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("server did not stop")
+	}
+
+	if _, err := os.Lstat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("expected the socket to be removed on shutdown, got %v", err)
+	}
+
+	t.Run("invalid settings are rejected", func(t *testing.T) {
+		for name, config := range map[string]UnixSocketConfig{
+			"TCP disabled without a socket": {DisableTCP: true},
+			"mode is not octal":             {Path: socketPath, Mode: "rw-rw----"},
+			"mode out of range":             {Path: socketPath, Mode: "1777"},
+		} {
+			if err := config.Validate(); err == nil {
+				t.Errorf("%s: expected validation error", name)
+			}
+		}
+	})
+}
+
+// TestUnixSocketSafety tests that the socket shares the connection limit and
+// that only a socket nobody is serving gets replaced
+func TestUnixSocketSafety(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	healthMgr := health.NewHealthManager(logger, mockMetrics, "test")
+	defer healthMgr.Shutdown()
+
+	newServer := func(socketPath string, maxConns int) *GatewayServer {
+		return NewGatewayServer(ServerConfig{
+			ShutdownTimeout:          5 * time.Second,
+			MaxConcurrentConnections: maxConns,
+			UnixSocket:               UnixSocketConfig{Path: socketPath, DisableTCP: true},
+		}, logger, mockMetrics, validation.NewValidator(logger, mockMetrics), healthMgr)
+	}
+
+	t.Run("a socket in use is not replaced", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "gateway.sock")
+		other, err := net.Listen("unix", socketPath)
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer other.Close()
+
+		if _, err := newServer(socketPath, 0).listenUnixSocket(); err == nil || !strings.Contains(err.Error(), "in use") {
+			t.Errorf("expected the socket in use to be refused, got %v", err)
+		}
+		if conn, err := net.Dial("unix", socketPath); err != nil {
+			t.Errorf("expected the other process to keep its socket, got %v", err)
+		} else {
+			conn.Close()
+		}
+	})
+
+	t.Run("a stale socket is replaced", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "gateway.sock")
+		stale, err := net.Listen("unix", socketPath)
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		stale.(*net.UnixListener).SetUnlinkOnClose(false)
+		stale.Close()
+
+		listener, err := newServer(socketPath, 0).listenUnixSocket()
+		if err != nil {
+			t.Fatalf("expected the stale socket to be replaced, got %v", err)
+		}
+		listener.Close()
+	})
+
+	t.Run("socket connections count against the connection limit", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "gateway.sock")
+		server := newServer(socketPath, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan error, 1)
+		go func() { stopped <- server.Start(ctx) }()
+		defer func() {
+			cancel()
+			<-stopped
+		}()
+
+		var held net.Conn
+		deadline := time.Now().Add(10 * time.Second)
+		for held == nil || server.activeConnections.Load() != 1 {
+			if held == nil {
+				held, _ = net.Dial("unix", socketPath)
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("expected the first connection to be accepted")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		defer held.Close()
+
+		rejected, err := net.Dial("unix", socketPath)
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer rejected.Close()
+		rejected.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 64)
+		n, _ := rejected.Read(buf)
+		if !strings.Contains(string(buf[:n]), "503") {
+			t.Errorf("expected the connection over the limit to get a 503, got %q", buf[:n])
+		}
+	})
+}
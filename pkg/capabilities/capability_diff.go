@@ -0,0 +1,168 @@
+package capabilities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/osakka/mcpeg/pkg/plugins"
+)
+
+// CapabilityRef identifies one capability of a plugin
+type CapabilityRef struct {
+	Type CapabilityType `json:"type"`
+	Name string         `json:"name"`
+}
+
+// CapabilityDiff lists the capabilities a plugin gained, lost or redefined
+// between two discoveries
+type CapabilityDiff struct {
+	PluginName string          `json:"plugin_name"`
+	Added      []CapabilityRef `json:"added"`
+	Removed    []CapabilityRef `json:"removed"`
+	Changed    []CapabilityRef `json:"changed"`
+}
+
+// Empty reports whether the plugin's capabilities are unchanged
+func (d CapabilityDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ChangedTypes returns the capability types with any change, in a stable order
+func (d CapabilityDiff) ChangedTypes() []CapabilityType {
+	seen := make(map[CapabilityType]bool)
+	for _, refs := range [][]CapabilityRef{d.Added, d.Removed, d.Changed} {
+		for _, ref := range refs {
+			seen[ref.Type] = true
+		}
+	}
+
+	var types []CapabilityType
+	for _, capabilityType := range []CapabilityType{CapabilityTool, CapabilityResource, CapabilityPrompt} {
+		if seen[capabilityType] {
+			types = append(types, capabilityType)
+		}
+	}
+	return types
+}
+
+// CapabilityChangeListener is called with every non-empty capability diff
+type CapabilityChangeListener func(diff CapabilityDiff)
+
+// capabilitySnapshot maps each capability of a plugin to a fingerprint of its
+// definition, so redefined capabilities are told apart from unchanged ones
+type capabilitySnapshot map[CapabilityRef]string
+
+// snapshotCapabilities records the tools, resources and prompts a plugin offers
+func snapshotCapabilities(plugin plugins.Plugin) capabilitySnapshot {
+	snapshot := make(capabilitySnapshot)
+	add := func(capabilityType CapabilityType, name string, definition interface{}) {
+		fingerprint, err := json.Marshal(definition)
+		if err != nil {
+			fingerprint = []byte(fmt.Sprintf("%v", definition))
+		}
+		snapshot[CapabilityRef{Type: capabilityType, Name: name}] = string(fingerprint)
+	}
+
+	for _, tool := range plugin.GetTools() {
+		add(CapabilityTool, tool.Name, tool)
+	}
+	for _, resource := range plugin.GetResources() {
+		add(CapabilityResource, resource.URI, resource)
+	}
+	for _, prompt := range plugin.GetPrompts() {
+		add(CapabilityPrompt, prompt.Name, prompt)
+	}
+	return snapshot
+}
+
+// diffSnapshots compares two snapshots of a plugin's capabilities
+func diffSnapshots(pluginName string, previous, current capabilitySnapshot) CapabilityDiff {
+	diff := CapabilityDiff{PluginName: pluginName}
+	for ref, fingerprint := range current {
+		previousFingerprint, existed := previous[ref]
+		switch {
+		case !existed:
+			diff.Added = append(diff.Added, ref)
+		case previousFingerprint != fingerprint:
+			diff.Changed = append(diff.Changed, ref)
+		}
+	}
+	for ref := range previous {
+		if _, exists := current[ref]; !exists {
+			diff.Removed = append(diff.Removed, ref)
+		}
+	}
+
+	for _, refs := range [][]CapabilityRef{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].Type != refs[j].Type {
+				return refs[i].Type < refs[j].Type
+			}
+			return refs[i].Name < refs[j].Name
+		})
+	}
+	return diff
+}
+
+// OnCapabilityChange registers a listener for plugin capability changes, such
+// as a reload adding a tool. Listeners run synchronously after the discovery
+// that detected the change.
+func (de *DiscoveryEngine) OnCapabilityChange(listener CapabilityChangeListener) {
+	de.mutex.Lock()
+	defer de.mutex.Unlock()
+	de.listeners = append(de.listeners, listener)
+}
+
+// DetectCapabilityChanges compares a plugin's capabilities with those seen at
+// its last discovery and notifies listeners of any difference. The first call
+// for a plugin records a baseline and reports no change.
+func (de *DiscoveryEngine) DetectCapabilityChanges(ctx context.Context, pluginName string) (CapabilityDiff, error) {
+	plugin, exists := de.pluginManager.GetPlugin(pluginName)
+	if !exists {
+		return CapabilityDiff{PluginName: pluginName}, fmt.Errorf("plugin not found: %s", pluginName)
+	}
+	return de.detectCapabilityChanges(ctx, pluginName, plugin), nil
+}
+
+func (de *DiscoveryEngine) detectCapabilityChanges(ctx context.Context, pluginName string, plugin plugins.Plugin) CapabilityDiff {
+	current := snapshotCapabilities(plugin)
+
+	de.mutex.Lock()
+	previous, known := de.snapshots[pluginName]
+	de.snapshots[pluginName] = current
+	listeners := append([]CapabilityChangeListener(nil), de.listeners...)
+	de.mutex.Unlock()
+
+	if !known {
+		de.logger.WithContext(ctx).Debug("plugin_capabilities_baseline_recorded",
+			"plugin", pluginName,
+			"capabilities", len(current))
+		return CapabilityDiff{PluginName: pluginName}
+	}
+
+	diff := diffSnapshots(pluginName, previous, current)
+	if diff.Empty() {
+		return diff
+	}
+
+	for change, refs := range map[string][]CapabilityRef{"added": diff.Added, "removed": diff.Removed, "changed": diff.Changed} {
+		for _, ref := range refs {
+			de.metrics.Inc("plugin_capability_changes_total",
+				"plugin", pluginName,
+				"type", string(ref.Type),
+				"change", change)
+		}
+	}
+	de.logger.WithContext(ctx).Info("plugin_capabilities_changed",
+		"plugin", pluginName,
+		"added", diff.Added,
+		"removed", diff.Removed,
+		"changed", diff.Changed)
+
+	for _, listener := range listeners {
+		listener(diff)
+	}
+	return diff
+}
@@ -25,6 +25,10 @@ type DiscoveryEngine struct {
 	discoveries map[string]*DiscoveryResult
 	dependencyGraph map[string][]string
 	conflictMatrix  map[string][]string
+
+	// Capabilities seen at each plugin's last discovery, and who to tell when they change
+	snapshots map[string]capabilitySnapshot
+	listeners []CapabilityChangeListener
 	
 	// Synchronization
 	mutex sync.RWMutex
@@ -63,6 +67,7 @@ func NewDiscoveryEngine(
 		discoveries:     make(map[string]*DiscoveryResult),
 		dependencyGraph: make(map[string][]string),
 		conflictMatrix:  make(map[string][]string),
+		snapshots:       make(map[string]capabilitySnapshot),
 		config:          config,
 	}
 }
@@ -82,6 +87,9 @@ func (de *DiscoveryEngine) DiscoverPlugin(ctx context.Context, pluginName string
 		return nil, fmt.Errorf("plugin not found: %s", pluginName)
 	}
 
+	// Tell listeners what changed since the last discovery, such as after a reload
+	de.detectCapabilityChanges(ctx, pluginName, plugin)
+
 	result := &DiscoveryResult{
 		PluginName:      pluginName,
 		Capabilities:    []*CapabilityAnalysis{},
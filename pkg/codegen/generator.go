@@ -3,10 +3,11 @@ package codegen
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"go/format"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -45,6 +46,12 @@ type GeneratorConfig struct {
 	// Template options
 	TemplateDir     string            `yaml:"template_dir"`
 	CustomTemplates map[string]string `yaml:"custom_templates"`
+
+	// Type-check the written package and fail on compile errors
+	Verify bool `yaml:"verify"`
+
+	// Goroutines rendering declarations in StreamCode; 0 uses GOMAXPROCS
+	Workers int `yaml:"workers"`
 }
 
 // OpenAPISpec represents a parsed OpenAPI specification
@@ -276,10 +283,15 @@ type VariableDefinition struct {
 
 // NewCodeGenerator creates a new code generator
 func NewCodeGenerator(logger logging.Logger, metrics metrics.Metrics) *CodeGenerator {
+	return NewCodeGeneratorWithConfig(logger, metrics, defaultGeneratorConfig())
+}
+
+// NewCodeGeneratorWithConfig creates a new code generator with an explicit configuration
+func NewCodeGeneratorWithConfig(logger logging.Logger, metrics metrics.Metrics, config GeneratorConfig) *CodeGenerator {
 	cg := &CodeGenerator{
 		logger:    logger.WithComponent("code_generator"),
 		metrics:   metrics,
-		config:    defaultGeneratorConfig(),
+		config:    config,
 		templates: make(map[string]*template.Template),
 	}
 
@@ -356,7 +368,7 @@ func (cg *CodeGenerator) GenerateFromSpec(ctx context.Context, spec *OpenAPISpec
 		"constants_generated", len(generated.Constants))
 
 	// Record metrics
-	cg.recordGenerationMetrics(spec, generated, duration)
+	cg.recordGenerationMetrics(spec, len(generated.Types), len(generated.Functions), len(generated.Constants), duration)
 
 	return generated, nil
 }
@@ -367,31 +379,69 @@ func (cg *CodeGenerator) WriteCode(ctx context.Context, generated *GeneratedCode
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	// Written file contents by name, for verification
+	written := make(map[string][]byte)
+
 	// Generate main types file
 	if len(generated.Types) > 0 {
-		if err := cg.writeTypesFile(generated); err != nil {
+		content, err := cg.writeTypesFile(generated)
+		if err != nil {
 			return fmt.Errorf("failed to write types file: %w", err)
 		}
+		written["types.go"] = content
 	}
 
 	// Generate handlers file
 	if len(generated.Functions) > 0 {
-		if err := cg.writeFunctionsFile(generated); err != nil {
+		content, err := cg.writeFunctionsFile(generated)
+		if err != nil {
 			return fmt.Errorf("failed to write functions file: %w", err)
 		}
+		written["handlers.go"] = content
 	}
 
 	// Generate constants file
 	if len(generated.Constants) > 0 {
-		if err := cg.writeConstantsFile(generated); err != nil {
+		content, err := cg.writeConstantsFile(generated)
+		if err != nil {
 			return fmt.Errorf("failed to write constants file: %w", err)
 		}
+		written["constants.go"] = content
 	}
 
 	cg.logger.Info("code_files_written",
 		"output_dir", cg.config.OutputDir,
 		"package", generated.Package)
 
+	if cg.config.Verify {
+		return cg.verifyWrittenCode(generated.Package, written)
+	}
+	return nil
+}
+
+// verifyWrittenCode type-checks the written files, reporting errors by their
+// path in the output directory. The files are left in place for inspection.
+func (cg *CodeGenerator) verifyWrittenCode(packageName string, written map[string][]byte) error {
+	files := make(map[string][]byte, len(written))
+	for name, content := range written {
+		files[filepath.Join(cg.config.OutputDir, name)] = content
+	}
+
+	if err := VerifyGoSource(packageName, files); err != nil {
+		var verr *VerificationError
+		if errors.As(err, &verr) {
+			cg.metrics.Inc("codegen_verification_failures_total")
+			cg.logger.Error("generated_code_verification_failed",
+				"output_dir", cg.config.OutputDir,
+				"error_count", len(verr.Errors),
+				"first_error", verr.Errors[0].String())
+		}
+		return err
+	}
+
+	cg.logger.Info("generated_code_verified",
+		"output_dir", cg.config.OutputDir,
+		"files", len(files))
 	return nil
 }
 
@@ -399,8 +449,8 @@ func (cg *CodeGenerator) WriteCode(ctx context.Context, generated *GeneratedCode
 func (cg *CodeGenerator) generateTypes(ctx context.Context, spec *OpenAPISpec) ([]TypeDefinition, error) {
 	var types []TypeDefinition
 
-	for name, schema := range spec.Components.Schemas {
-		typeDef, err := cg.schemaToType(name, schema, spec)
+	for _, name := range sortedSchemaNames(spec) {
+		typeDef, err := cg.schemaToType(name, spec.Components.Schemas[name], spec)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert schema %s: %w", name, err)
 		}
@@ -414,23 +464,8 @@ func (cg *CodeGenerator) generateTypes(ctx context.Context, spec *OpenAPISpec) (
 func (cg *CodeGenerator) generateHandlers(ctx context.Context, spec *OpenAPISpec) ([]FunctionDefinition, error) {
 	var functions []FunctionDefinition
 
-	for path, pathItem := range spec.Paths {
-		if pathItem.GET != nil {
-			fn := cg.operationToHandler("GET", path, pathItem.GET, spec)
-			functions = append(functions, fn)
-		}
-		if pathItem.POST != nil {
-			fn := cg.operationToHandler("POST", path, pathItem.POST, spec)
-			functions = append(functions, fn)
-		}
-		if pathItem.PUT != nil {
-			fn := cg.operationToHandler("PUT", path, pathItem.PUT, spec)
-			functions = append(functions, fn)
-		}
-		if pathItem.DELETE != nil {
-			fn := cg.operationToHandler("DELETE", path, pathItem.DELETE, spec)
-			functions = append(functions, fn)
-		}
+	for _, op := range sortedOperations(spec, handlerMethods...) {
+		functions = append(functions, cg.operationToHandler(op.method, op.path, op.op, spec))
 	}
 
 	return functions, nil
@@ -438,11 +473,38 @@ func (cg *CodeGenerator) generateHandlers(ctx context.Context, spec *OpenAPISpec
 
 // generateClients generates client code from OpenAPI paths
 func (cg *CodeGenerator) generateClients(ctx context.Context, spec *OpenAPISpec) ([]TypeDefinition, []FunctionDefinition, error) {
-	var types []TypeDefinition
-	var functions []FunctionDefinition
+	types := []TypeDefinition{cg.clientType(spec)}
+	functions := []FunctionDefinition{cg.clientConstructor(spec)}
+	functions = append(functions, cg.clientAuthFunctions(spec)...)
+
+	// Generate client methods for each operation
+	for _, op := range sortedOperations(spec, clientMethods...) {
+		functions = append(functions, cg.operationToClientMethod(op.method, op.path, op.op, spec))
+	}
 
-	// Generate client struct
-	clientType := TypeDefinition{
+	return types, functions, nil
+}
+
+// clientType generates the API client struct. With supported security schemes
+// it holds credentials per scheme instead of a single API key.
+func (cg *CodeGenerator) clientType(spec *OpenAPISpec) TypeDefinition {
+	if schemes := clientSecuritySchemes(spec); len(schemes) > 0 {
+		fields := []FieldDefinition{
+			{Name: "baseURL", Type: "string", Tags: map[string]string{"json": "base_url"}},
+			{Name: "httpClient", Type: "*http.Client", Tags: map[string]string{"json": "-"}},
+		}
+		for _, scheme := range schemes {
+			fields = append(fields, scheme.securityFields()...)
+		}
+		return TypeDefinition{
+			Name:    "Client",
+			Type:    "struct",
+			Comment: fmt.Sprintf("Client provides access to the %s API", spec.Info.Title),
+			Fields:  fields,
+		}
+	}
+
+	return TypeDefinition{
 		Name:    "Client",
 		Type:    "struct",
 		Comment: fmt.Sprintf("Client provides access to the %s API", spec.Info.Title),
@@ -464,10 +526,25 @@ func (cg *CodeGenerator) generateClients(ctx context.Context, spec *OpenAPISpec)
 			},
 		},
 	}
-	types = append(types, clientType)
+}
+
+// clientConstructor generates the API client constructor. Credentials for
+// declared security schemes are set after construction.
+func (cg *CodeGenerator) clientConstructor(spec *OpenAPISpec) FunctionDefinition {
+	if len(clientSecuritySchemes(spec)) > 0 {
+		return FunctionDefinition{
+			Name:       "NewClient",
+			Parameters: []ParameterDefinition{{Name: "baseURL", Type: "string"}},
+			Returns:    []ParameterDefinition{{Name: "", Type: "*Client"}},
+			Body: `	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}`,
+			Comment: "NewClient creates a new API client",
+		}
+	}
 
-	// Generate constructor
-	constructor := FunctionDefinition{
+	return FunctionDefinition{
 		Name: "NewClient",
 		Parameters: []ParameterDefinition{
 			{Name: "baseURL", Type: "string"},
@@ -479,30 +556,14 @@ func (cg *CodeGenerator) generateClients(ctx context.Context, spec *OpenAPISpec)
 		Body:    cg.generateClientConstructor(),
 		Comment: "NewClient creates a new API client",
 	}
-	functions = append(functions, constructor)
-
-	// Generate client methods for each operation
-	for path, pathItem := range spec.Paths {
-		if pathItem.GET != nil {
-			method := cg.operationToClientMethod("GET", path, pathItem.GET, spec)
-			functions = append(functions, method)
-		}
-		if pathItem.POST != nil {
-			method := cg.operationToClientMethod("POST", path, pathItem.POST, spec)
-			functions = append(functions, method)
-		}
-		// Add other HTTP methods as needed
-	}
-
-	return types, functions, nil
 }
 
 // generateValidators generates validation functions
 func (cg *CodeGenerator) generateValidators(ctx context.Context, spec *OpenAPISpec) ([]FunctionDefinition, error) {
 	var functions []FunctionDefinition
 
-	for name, schema := range spec.Components.Schemas {
-		validator := cg.schemaToValidator(name, schema)
+	for _, name := range sortedSchemaNames(spec) {
+		validator := cg.schemaToValidator(name, spec.Components.Schemas[name])
 		functions = append(functions, validator)
 	}
 
@@ -548,11 +609,6 @@ func (cg *CodeGenerator) generateImports(spec *OpenAPISpec) []string {
 		"time",
 	}
 
-	// Add validation imports if generating validators
-	if cg.config.GenerateValidators {
-		imports = append(imports, "github.com/osakka/mcpeg/pkg/validation")
-	}
-
 	return imports
 }
 
@@ -567,7 +623,8 @@ func (cg *CodeGenerator) schemaToType(name string, schema Schema, spec *OpenAPIS
 	if schema.Type == "object" || len(schema.Properties) > 0 {
 		typeDef.Type = "struct"
 
-		for propName, propSchema := range schema.Properties {
+		for _, propName := range sortedKeys(schema.Properties) {
+			propSchema := schema.Properties[propName]
 			field := FieldDefinition{
 				Name:    toPascalCase(propName),
 				Comment: propSchema.Description,
@@ -742,7 +799,8 @@ func (cg *CodeGenerator) operationToClientMethod(method, path string, op *Operat
 	// Generate return type from response schemas
 	responseType := "interface{}"
 	if op.Responses != nil {
-		for statusCode, response := range op.Responses {
+		for _, statusCode := range sortedKeys(op.Responses) {
+			response := op.Responses[statusCode]
 			if statusCode == "200" || statusCode == "201" {
 				if response.Content != nil {
 					for contentType, mediaType := range response.Content {
@@ -766,7 +824,7 @@ func (cg *CodeGenerator) operationToClientMethod(method, path string, op *Operat
 		Name:       funcName,
 		Parameters: params,
 		Returns:    returns,
-		Body:       cg.generateClientMethodBody(method, path, op, spec),
+		Body:       cg.generateClientMethodBody(method, path, responseType, cg.generateClientAuth(op, spec)),
 		Comment:    fmt.Sprintf("%s calls %s %s - %s", funcName, method, path, op.Summary),
 	}
 }
@@ -782,7 +840,7 @@ func (cg *CodeGenerator) schemaToValidator(name string, schema Schema) FunctionD
 			{Name: "value", Type: toPascalCase(name)},
 		},
 		Returns: []ParameterDefinition{
-			{Name: "", Type: "validation.ValidationResult"},
+			{Name: "", Type: "error"},
 		},
 		Body:    cg.generateValidatorBody(name, schema),
 		Comment: fmt.Sprintf("%s validates a %s instance", funcName, name),
@@ -825,41 +883,39 @@ func (cg *CodeGenerator) generateHandlerBody(method, path string, op *Operation,
 }
 
 // generateClientMethodBody generates the body of a client method
-func (cg *CodeGenerator) generateClientMethodBody(method, path string, op *Operation, spec *OpenAPISpec) string {
+func (cg *CodeGenerator) generateClientMethodBody(method, path, responseType, auth string) string {
 	return fmt.Sprintf(`	// HTTP %s request to %s
 	url := c.baseURL + "%s"
+	var result %s
 	
 	// Create request
-	req, err := http.NewRequest("%s", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "%s", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %%w", err)
+		return result, fmt.Errorf("failed to create request: %%w", err)
 	}
 	
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer " + c.apiKey)
-	}
+%s
 	
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %%w", err)
+		return result, fmt.Errorf("request failed: %%w", err)
 	}
 	defer resp.Body.Close()
 	
 	// Check response status
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("HTTP %%d: request failed", resp.StatusCode)
+		return result, fmt.Errorf("HTTP %%d: request failed", resp.StatusCode)
 	}
 	
 	// Parse response
-	var result interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %%w", err)
+		return result, fmt.Errorf("failed to decode response: %%w", err)
 	}
 	
-	return result, nil`, method, path, path, strings.ToUpper(method))
+	return result, nil`, method, path, path, responseType, strings.ToUpper(method), auth)
 }
 
 // generateClientConstructor generates the client constructor body
@@ -871,22 +927,54 @@ func (cg *CodeGenerator) generateClientConstructor() string {
 	}`
 }
 
-// generateValidatorBody generates the body of a validator function
+// generateValidatorBody generates the body of a validator function, checking
+// required string fields and string length limits
 func (cg *CodeGenerator) generateValidatorBody(name string, schema Schema) string {
-	return fmt.Sprintf(`	// Validate %s against schema
-	if input == nil {
-		return validation.ValidationResult{Valid: false, Error: "input is nil"}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "\t// Validate %s against schema\n", name)
+
+	required := make(map[string]bool)
+	for _, propName := range schema.Required {
+		required[propName] = true
 	}
-	
-	// Basic validation - in production, use JSON schema validation
-	result := validation.ValidationResult{Valid: true}
-	
-	// Type validation
-	if schema.Type != "" {
-		// Add type checking logic here
+
+	propNames := make([]string, 0, len(schema.Properties))
+	for propName := range schema.Properties {
+		propNames = append(propNames, propName)
 	}
-	
-	return result`, name)
+	sort.Strings(propNames)
+
+	for _, propName := range propNames {
+		propSchema := schema.Properties[propName]
+		if propSchema.Type != "string" || propSchema.Ref != "" {
+			continue
+		}
+
+		field := "value." + toPascalCase(propName)
+		if required[propName] {
+			fmt.Fprintf(&buf, "\tif %s == \"\" {\n\t\treturn fmt.Errorf(\"%s is required\")\n\t}\n", field, propName)
+		} else if propSchema.MinLength != nil || propSchema.MaxLength != nil {
+			// Optional fields are pointers
+			fmt.Fprintf(&buf, "\tif %s != nil {\n", field)
+			field = "*" + field
+		}
+
+		if propSchema.MinLength != nil {
+			fmt.Fprintf(&buf, "\tif len(%s) < %d {\n\t\treturn fmt.Errorf(\"%s must be at least %d characters\")\n\t}\n",
+				field, *propSchema.MinLength, propName, *propSchema.MinLength)
+		}
+		if propSchema.MaxLength != nil {
+			fmt.Fprintf(&buf, "\tif len(%s) > %d {\n\t\treturn fmt.Errorf(\"%s must be at most %d characters\")\n\t}\n",
+				field, *propSchema.MaxLength, propName, *propSchema.MaxLength)
+		}
+
+		if !required[propName] && (propSchema.MinLength != nil || propSchema.MaxLength != nil) {
+			buf.WriteString("\t}\n")
+		}
+	}
+
+	buf.WriteString("\treturn nil")
+	return buf.String()
 }
 
 // generateValidationTag generates validation tags for a schema
@@ -914,6 +1002,63 @@ func (cg *CodeGenerator) generateValidationTag(schema Schema, required bool) str
 
 // Helper functions for code generation
 
+// HTTP methods generated as handlers and as client methods
+var (
+	handlerMethods = []string{"GET", "POST", "PUT", "DELETE"}
+	clientMethods  = []string{"GET", "POST"}
+)
+
+// specOperation is one operation of a spec path
+type specOperation struct {
+	method string
+	path   string
+	op     *Operation
+}
+
+// sortedOperations lists the spec's operations for the given methods, ordered
+// by path and then by the order of methods
+func sortedOperations(spec *OpenAPISpec, methods ...string) []specOperation {
+	var operations []specOperation
+	for _, path := range sortedKeys(spec.Paths) {
+		pathItem := spec.Paths[path]
+		for _, method := range methods {
+			var op *Operation
+			switch method {
+			case "GET":
+				op = pathItem.GET
+			case "POST":
+				op = pathItem.POST
+			case "PUT":
+				op = pathItem.PUT
+			case "DELETE":
+				op = pathItem.DELETE
+			case "PATCH":
+				op = pathItem.PATCH
+			}
+			if op != nil {
+				operations = append(operations, specOperation{method: method, path: path, op: op})
+			}
+		}
+	}
+	return operations
+}
+
+// sortedSchemaNames returns the component schema names in generation order
+func sortedSchemaNames(spec *OpenAPISpec) []string {
+	return sortedKeys(spec.Components.Schemas)
+}
+
+// sortedKeys returns the keys of m in sorted order, so generated output does
+// not depend on map iteration order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func toPascalCase(s string) string {
 	if s == "" {
 		return ""
@@ -964,158 +1109,171 @@ func defaultGeneratorConfig() GeneratorConfig {
 
 // File writing methods
 
-func (cg *CodeGenerator) writeTypesFile(generated *GeneratedCode) error {
+func (cg *CodeGenerator) writeTypesFile(generated *GeneratedCode) ([]byte, error) {
 	filename := filepath.Join(cg.config.OutputDir, "types.go")
 	content := cg.renderTypesFile(generated)
 
-	formatted, err := format.Source([]byte(content))
+	formatted, err := formatGoSource([]byte(content))
 	if err != nil {
 		cg.logger.Warn("failed_to_format_types_file", "error", err)
 		formatted = []byte(content)
 	}
 
-	return os.WriteFile(filename, formatted, 0644)
+	return formatted, os.WriteFile(filename, formatted, 0644)
 }
 
-func (cg *CodeGenerator) writeFunctionsFile(generated *GeneratedCode) error {
+func (cg *CodeGenerator) writeFunctionsFile(generated *GeneratedCode) ([]byte, error) {
 	filename := filepath.Join(cg.config.OutputDir, "handlers.go")
 	content := cg.renderFunctionsFile(generated)
 
-	formatted, err := format.Source([]byte(content))
+	formatted, err := formatGoSource([]byte(content))
 	if err != nil {
 		cg.logger.Warn("failed_to_format_functions_file", "error", err)
 		formatted = []byte(content)
 	}
 
-	return os.WriteFile(filename, formatted, 0644)
+	return formatted, os.WriteFile(filename, formatted, 0644)
 }
 
-func (cg *CodeGenerator) writeConstantsFile(generated *GeneratedCode) error {
+func (cg *CodeGenerator) writeConstantsFile(generated *GeneratedCode) ([]byte, error) {
 	filename := filepath.Join(cg.config.OutputDir, "constants.go")
 	content := cg.renderConstantsFile(generated)
 
-	formatted, err := format.Source([]byte(content))
+	formatted, err := formatGoSource([]byte(content))
 	if err != nil {
 		cg.logger.Warn("failed_to_format_constants_file", "error", err)
 		formatted = []byte(content)
 	}
 
-	return os.WriteFile(filename, formatted, 0644)
+	return formatted, os.WriteFile(filename, formatted, 0644)
 }
 
 func (cg *CodeGenerator) renderTypesFile(generated *GeneratedCode) string {
 	var buf bytes.Buffer
+	buf.WriteString(renderFileHeader(generated.Package, generated.Imports))
+
+	for _, typeDef := range generated.Types {
+		buf.WriteString(renderTypeDecl(typeDef))
+	}
+
+	return buf.String()
+}
+
+func (cg *CodeGenerator) renderFunctionsFile(generated *GeneratedCode) string {
+	var buf bytes.Buffer
+	buf.WriteString(renderFileHeader(generated.Package, generated.Imports))
+
+	for _, funcDef := range generated.Functions {
+		buf.WriteString(renderFunctionDecl(funcDef))
+	}
+
+	return buf.String()
+}
+
+// renderFileHeader renders the package clause and imports of a generated file
+func renderFileHeader(packageName string, imports []string) string {
+	var buf bytes.Buffer
 
 	// Package declaration
-	fmt.Fprintf(&buf, "package %s\n\n", generated.Package)
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
 
 	// Imports
-	if len(generated.Imports) > 0 {
+	if len(imports) > 0 {
 		buf.WriteString("import (\n")
-		for _, imp := range generated.Imports {
+		for _, imp := range imports {
 			fmt.Fprintf(&buf, "\t\"%s\"\n", imp)
 		}
 		buf.WriteString(")\n\n")
 	}
 
-	// Types
-	for _, typeDef := range generated.Types {
-		if typeDef.Comment != "" {
-			fmt.Fprintf(&buf, "// %s %s\n", typeDef.Name, typeDef.Comment)
-		}
+	return buf.String()
+}
 
-		if typeDef.Type == "struct" {
-			fmt.Fprintf(&buf, "type %s struct {\n", typeDef.Name)
-			for _, field := range typeDef.Fields {
-				if field.Comment != "" {
-					fmt.Fprintf(&buf, "\t// %s\n", field.Comment)
-				}
+// renderTypeDecl renders one type declaration
+func renderTypeDecl(typeDef TypeDefinition) string {
+	var buf bytes.Buffer
 
-				tagStr := ""
-				if len(field.Tags) > 0 {
-					var tags []string
-					for k, v := range field.Tags {
-						tags = append(tags, fmt.Sprintf(`%s:"%s"`, k, v))
-					}
-					tagStr = fmt.Sprintf(" `%s`", strings.Join(tags, " "))
-				}
+	if typeDef.Comment != "" {
+		fmt.Fprintf(&buf, "// %s %s\n", typeDef.Name, typeDef.Comment)
+	}
 
-				fmt.Fprintf(&buf, "\t%s %s%s\n", field.Name, field.Type, tagStr)
+	if typeDef.Type == "struct" {
+		fmt.Fprintf(&buf, "type %s struct {\n", typeDef.Name)
+		for _, field := range typeDef.Fields {
+			if field.Comment != "" {
+				fmt.Fprintf(&buf, "\t// %s\n", field.Comment)
 			}
-			buf.WriteString("}\n\n")
-		} else {
-			fmt.Fprintf(&buf, "type %s %s\n\n", typeDef.Name, typeDef.Type)
+
+			tagStr := ""
+			if len(field.Tags) > 0 {
+				var tags []string
+				for _, k := range sortedKeys(field.Tags) {
+					tags = append(tags, fmt.Sprintf(`%s:"%s"`, k, field.Tags[k]))
+				}
+				tagStr = fmt.Sprintf(" `%s`", strings.Join(tags, " "))
+			}
+
+			fmt.Fprintf(&buf, "\t%s %s%s\n", field.Name, field.Type, tagStr)
 		}
+		buf.WriteString("}\n\n")
+	} else {
+		fmt.Fprintf(&buf, "type %s %s\n\n", typeDef.Name, typeDef.Type)
 	}
 
 	return buf.String()
 }
 
-func (cg *CodeGenerator) renderFunctionsFile(generated *GeneratedCode) string {
+// renderFunctionDecl renders one function or client method declaration
+func renderFunctionDecl(funcDef FunctionDefinition) string {
 	var buf bytes.Buffer
 
-	// Package declaration
-	fmt.Fprintf(&buf, "package %s\n\n", generated.Package)
-
-	// Imports
-	if len(generated.Imports) > 0 {
-		buf.WriteString("import (\n")
-		for _, imp := range generated.Imports {
-			fmt.Fprintf(&buf, "\t\"%s\"\n", imp)
-		}
-		buf.WriteString(")\n\n")
+	if funcDef.Comment != "" {
+		fmt.Fprintf(&buf, "// %s\n", funcDef.Comment)
 	}
 
-	// Functions
-	for _, funcDef := range generated.Functions {
-		if funcDef.Comment != "" {
-			fmt.Fprintf(&buf, "// %s\n", funcDef.Comment)
+	// Function signature
+	buf.WriteString("func ")
+	if len(funcDef.Parameters) > 0 && funcDef.Parameters[0].Type == "*Client" {
+		// Method
+		fmt.Fprintf(&buf, "(%s %s) %s(", funcDef.Parameters[0].Name, funcDef.Parameters[0].Type, funcDef.Name)
+		for i, param := range funcDef.Parameters[1:] {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%s %s", param.Name, param.Type)
 		}
-
-		// Function signature
-		buf.WriteString("func ")
-		if len(funcDef.Parameters) > 0 && funcDef.Parameters[0].Type == "*Client" {
-			// Method
-			fmt.Fprintf(&buf, "(%s %s) %s(", funcDef.Parameters[0].Name, funcDef.Parameters[0].Type, funcDef.Name)
-			for i, param := range funcDef.Parameters[1:] {
-				if i > 0 {
-					buf.WriteString(", ")
-				}
-				fmt.Fprintf(&buf, "%s %s", param.Name, param.Type)
+	} else {
+		// Function
+		fmt.Fprintf(&buf, "%s(", funcDef.Name)
+		for i, param := range funcDef.Parameters {
+			if i > 0 {
+				buf.WriteString(", ")
 			}
+			fmt.Fprintf(&buf, "%s %s", param.Name, param.Type)
+		}
+	}
+	buf.WriteString(")")
+
+	// Return types
+	if len(funcDef.Returns) > 0 {
+		if len(funcDef.Returns) == 1 {
+			fmt.Fprintf(&buf, " %s", funcDef.Returns[0].Type)
 		} else {
-			// Function
-			fmt.Fprintf(&buf, "%s(", funcDef.Name)
-			for i, param := range funcDef.Parameters {
+			buf.WriteString(" (")
+			for i, ret := range funcDef.Returns {
 				if i > 0 {
 					buf.WriteString(", ")
 				}
-				fmt.Fprintf(&buf, "%s %s", param.Name, param.Type)
+				buf.WriteString(ret.Type)
 			}
+			buf.WriteString(")")
 		}
-		buf.WriteString(")")
-
-		// Return types
-		if len(funcDef.Returns) > 0 {
-			if len(funcDef.Returns) == 1 {
-				fmt.Fprintf(&buf, " %s", funcDef.Returns[0].Type)
-			} else {
-				buf.WriteString(" (")
-				for i, ret := range funcDef.Returns {
-					if i > 0 {
-						buf.WriteString(", ")
-					}
-					buf.WriteString(ret.Type)
-				}
-				buf.WriteString(")")
-			}
-		}
-
-		buf.WriteString(" {\n")
-		buf.WriteString(funcDef.Body)
-		buf.WriteString("\n}\n\n")
 	}
 
+	buf.WriteString(" {\n")
+	buf.WriteString(funcDef.Body)
+	buf.WriteString("\n}\n\n")
+
 	return buf.String()
 }
 
@@ -1146,15 +1304,15 @@ func (cg *CodeGenerator) loadTemplates() error {
 	return nil
 }
 
-func (cg *CodeGenerator) recordGenerationMetrics(spec *OpenAPISpec, generated *GeneratedCode, duration time.Duration) {
+func (cg *CodeGenerator) recordGenerationMetrics(spec *OpenAPISpec, types, functions, constants int, duration time.Duration) {
 	labels := []string{
 		"spec_title", spec.Info.Title,
 		"spec_version", spec.Info.Version,
 	}
 
 	cg.metrics.Set("codegen_duration_seconds", duration.Seconds(), labels...)
-	cg.metrics.Set("codegen_types_generated", float64(len(generated.Types)), labels...)
-	cg.metrics.Set("codegen_functions_generated", float64(len(generated.Functions)), labels...)
-	cg.metrics.Set("codegen_constants_generated", float64(len(generated.Constants)), labels...)
+	cg.metrics.Set("codegen_types_generated", float64(types), labels...)
+	cg.metrics.Set("codegen_functions_generated", float64(functions), labels...)
+	cg.metrics.Set("codegen_constants_generated", float64(constants), labels...)
 	cg.metrics.Inc("codegen_runs_total", labels...)
 }
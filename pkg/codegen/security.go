@@ -0,0 +1,238 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// clientSecurityScheme is a declared security scheme the generated client
+// can send credentials for
+type clientSecurityScheme struct {
+	name  string // Scheme name in the spec
+	kind  string // apiKey, bearer or basic
+	in    string // header, query or cookie, for apiKey
+	param string // Header, query parameter or cookie name, for apiKey
+	field string // Prefix of the client fields holding the credentials
+}
+
+// supportedSecurityScheme maps a spec security scheme to the credentials the
+// client sends. OAuth2 and OpenID Connect access tokens are sent as bearer
+// tokens; obtaining them is left to the caller.
+func supportedSecurityScheme(name string, scheme SecurityScheme) (clientSecurityScheme, bool) {
+	result := clientSecurityScheme{name: name, field: toCamelCase(name)}
+
+	switch scheme.Type {
+	case "apiKey":
+		switch scheme.In {
+		case "header", "query", "cookie":
+		default:
+			return result, false
+		}
+		if scheme.Name == "" {
+			return result, false
+		}
+		result.kind, result.in, result.param = "apiKey", scheme.In, scheme.Name
+	case "http":
+		switch strings.ToLower(scheme.Scheme) {
+		case "bearer":
+			result.kind = "bearer"
+		case "basic":
+			result.kind = "basic"
+		default:
+			return result, false
+		}
+	case "oauth2", "openIdConnect":
+		result.kind = "bearer"
+	default:
+		return result, false
+	}
+	return result, true
+}
+
+// clientSecuritySchemes returns the supported security schemes of the spec in name order
+func clientSecuritySchemes(spec *OpenAPISpec) []clientSecurityScheme {
+	var schemes []clientSecurityScheme
+	for _, name := range sortedKeys(spec.Components.SecuritySchemes) {
+		if scheme, ok := supportedSecurityScheme(name, spec.Components.SecuritySchemes[name]); ok {
+			schemes = append(schemes, scheme)
+		}
+	}
+	return schemes
+}
+
+// securityFields returns the client fields holding a scheme's credentials
+func (s clientSecurityScheme) securityFields() []FieldDefinition {
+	switch s.kind {
+	case "basic":
+		return []FieldDefinition{
+			{Name: s.field + "Username", Type: "string", Tags: map[string]string{"json": "-"}},
+			{Name: s.field + "Password", Type: "string", Tags: map[string]string{"json": "-"}},
+		}
+	case "bearer":
+		return []FieldDefinition{{Name: s.field + "Token", Type: "string", Tags: map[string]string{"json": "-"}}}
+	default:
+		return []FieldDefinition{{Name: s.field + "Key", Type: "string", Tags: map[string]string{"json": "-"}}}
+	}
+}
+
+// clientAuthFunctions generates a credential setter per supported security
+// scheme and the applySecurity method client methods call
+func (cg *CodeGenerator) clientAuthFunctions(spec *OpenAPISpec) []FunctionDefinition {
+	for _, name := range sortedKeys(spec.Components.SecuritySchemes) {
+		if _, ok := supportedSecurityScheme(name, spec.Components.SecuritySchemes[name]); !ok {
+			scheme := spec.Components.SecuritySchemes[name]
+			cg.logger.Warn("unsupported_security_scheme",
+				"scheme", name,
+				"type", scheme.Type,
+				"http_scheme", scheme.Scheme,
+				"in", scheme.In)
+		}
+	}
+
+	schemes := clientSecuritySchemes(spec)
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	var functions []FunctionDefinition
+	for _, scheme := range schemes {
+		funcName := "Set" + toPascalCase(scheme.name)
+		params := []ParameterDefinition{{Name: "c", Type: "*Client"}}
+		var body, comment string
+
+		switch scheme.kind {
+		case "basic":
+			params = append(params, ParameterDefinition{Name: "username", Type: "string"}, ParameterDefinition{Name: "password", Type: "string"})
+			body = fmt.Sprintf("\tc.%sUsername = username\n\tc.%sPassword = password", scheme.field, scheme.field)
+			comment = fmt.Sprintf("%s sets the basic auth credentials for the %s security scheme", funcName, scheme.name)
+		case "bearer":
+			params = append(params, ParameterDefinition{Name: "token", Type: "string"})
+			body = fmt.Sprintf("\tc.%sToken = token", scheme.field)
+			comment = fmt.Sprintf("%s sets the bearer token for the %s security scheme", funcName, scheme.name)
+		default:
+			params = append(params, ParameterDefinition{Name: "key", Type: "string"})
+			body = fmt.Sprintf("\tc.%sKey = key", scheme.field)
+			comment = fmt.Sprintf("%s sets the API key sent in the %s %s for the %s security scheme",
+				funcName, scheme.param, scheme.in, scheme.name)
+		}
+
+		functions = append(functions, FunctionDefinition{
+			Name:       funcName,
+			Parameters: params,
+			Body:       body,
+			Comment:    comment,
+		})
+	}
+
+	functions = append(functions, FunctionDefinition{
+		Name: "applySecurity",
+		Parameters: []ParameterDefinition{
+			{Name: "c", Type: "*Client"},
+			{Name: "req", Type: "*http.Request"},
+			{Name: "requirements", Type: "[][]string"},
+		},
+		Body:    generateApplySecurityBody(schemes),
+		Comment: "applySecurity sends the credentials of the first security requirement the client has all credentials for",
+	})
+
+	return functions
+}
+
+// generateApplySecurityBody generates the body of the client's applySecurity method
+func generateApplySecurityBody(schemes []clientSecurityScheme) string {
+	var buf bytes.Buffer
+	buf.WriteString("\tfor _, requirement := range requirements {\n")
+	buf.WriteString("\t\tsatisfied := true\n")
+	buf.WriteString("\t\tfor _, scheme := range requirement {\n")
+	buf.WriteString("\t\t\tswitch scheme {\n")
+	for _, scheme := range schemes {
+		var check []string
+		for _, field := range scheme.securityFields() {
+			check = append(check, fmt.Sprintf("c.%s != \"\"", field.Name))
+		}
+		fmt.Fprintf(&buf, "\t\t\tcase %q:\n\t\t\t\tsatisfied = satisfied && %s\n", scheme.name, strings.Join(check, " && "))
+	}
+	buf.WriteString("\t\t\tdefault:\n\t\t\t\tsatisfied = false\n")
+	buf.WriteString("\t\t\t}\n\t\t}\n")
+	buf.WriteString("\t\tif !satisfied {\n\t\t\tcontinue\n\t\t}\n\n")
+
+	buf.WriteString("\t\tfor _, scheme := range requirement {\n")
+	buf.WriteString("\t\t\tswitch scheme {\n")
+	for _, scheme := range schemes {
+		fmt.Fprintf(&buf, "\t\t\tcase %q:\n", scheme.name)
+		switch {
+		case scheme.kind == "basic":
+			fmt.Fprintf(&buf, "\t\t\t\treq.SetBasicAuth(c.%sUsername, c.%sPassword)\n", scheme.field, scheme.field)
+		case scheme.kind == "bearer":
+			fmt.Fprintf(&buf, "\t\t\t\treq.Header.Set(\"Authorization\", \"Bearer \"+c.%sToken)\n", scheme.field)
+		case scheme.in == "header":
+			fmt.Fprintf(&buf, "\t\t\t\treq.Header.Set(%q, c.%sKey)\n", scheme.param, scheme.field)
+		case scheme.in == "query":
+			fmt.Fprintf(&buf, "\t\t\t\tquery := req.URL.Query()\n\t\t\t\tquery.Set(%q, c.%sKey)\n\t\t\t\treq.URL.RawQuery = query.Encode()\n",
+				scheme.param, scheme.field)
+		default:
+			fmt.Fprintf(&buf, "\t\t\t\treq.AddCookie(&http.Cookie{Name: %q, Value: c.%sKey})\n", scheme.param, scheme.field)
+		}
+	}
+	buf.WriteString("\t\t\t}\n\t\t}\n")
+	buf.WriteString("\t\treturn\n\t}")
+	return buf.String()
+}
+
+// securityRequirements returns the security requirements of an operation as
+// alternatives, each a list of scheme names that must all be applied. The
+// operation's own requirements replace the spec's; with neither declared,
+// any supported scheme satisfies the operation. Empty requirements, which
+// make credentials optional, are left out so a requirement the client has
+// credentials for is still applied wherever it is listed. Nil means no
+// credentials.
+func securityRequirements(op *Operation, spec *OpenAPISpec, schemes []clientSecurityScheme) [][]string {
+	requirements := spec.Security
+	if op.Security != nil {
+		requirements = op.Security
+	}
+
+	if requirements == nil {
+		var result [][]string
+		for _, scheme := range schemes {
+			result = append(result, []string{scheme.name})
+		}
+		return result
+	}
+
+	var result [][]string
+	for _, requirement := range requirements {
+		if len(requirement) > 0 {
+			result = append(result, sortedKeys(requirement))
+		}
+	}
+	return result
+}
+
+// generateClientAuth generates the statements of a client method that add
+// credentials to req. Specs without supported security schemes keep sending
+// the constructor's API key as a bearer token.
+func (cg *CodeGenerator) generateClientAuth(op *Operation, spec *OpenAPISpec) string {
+	schemes := clientSecuritySchemes(spec)
+	if len(schemes) == 0 {
+		return `	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer " + c.apiKey)
+	}`
+	}
+
+	requirements := securityRequirements(op, spec, schemes)
+	if len(requirements) == 0 {
+		return ""
+	}
+
+	literals := make([]string, len(requirements))
+	for i, requirement := range requirements {
+		names := make([]string, len(requirement))
+		for j, name := range requirement {
+			names[j] = fmt.Sprintf("%q", name)
+		}
+		literals[i] = "{" + strings.Join(names, ", ") + "}"
+	}
+	return fmt.Sprintf("\tc.applySecurity(req, [][]string{%s})", strings.Join(literals, ", "))
+}
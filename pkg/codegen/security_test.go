@@ -0,0 +1,102 @@
+package codegen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestClientSecurityGeneration tests that declared security schemes are wired into generated client methods
+func TestClientSecurityGeneration(t *testing.T) {
+	generate := func(t *testing.T, spec *OpenAPISpec) string {
+		t.Helper()
+		config := defaultGeneratorConfig()
+		config.OutputDir = t.TempDir()
+		config.Verify = true
+		generator := NewCodeGeneratorWithConfig(logging.New("test"), &mockMetrics{}, config)
+
+		generated, err := generator.GenerateFromSpec(context.Background(), spec)
+		if err != nil {
+			t.Fatalf("generation failed: %v", err)
+		}
+		if err := generator.WriteCode(context.Background(), generated); err != nil {
+			t.Fatalf("expected generated client to compile, got %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(config.OutputDir, "handlers.go"))
+		if err != nil {
+			t.Fatalf("failed to read generated handlers: %v", err)
+		}
+		return string(content)
+	}
+
+	t.Run("bearer scheme sets the authorization header", func(t *testing.T) {
+		spec := newVerifyTestSpec()
+		spec.Components.SecuritySchemes = map[string]SecurityScheme{
+			"token": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+		spec.Security = []SecurityRequirement{{"token": {}}}
+
+		code := generate(t, spec)
+		if !strings.Contains(code, `req.Header.Set("Authorization", "Bearer "+c.tokenToken)`) {
+			t.Errorf("expected the bearer token in the Authorization header, got:\n%s", code)
+		}
+		if !strings.Contains(code, "func (c *Client) SetToken(token string)") {
+			t.Error("expected a setter for the bearer token")
+		}
+		if strings.Count(code, `c.applySecurity(req, [][]string{{"token"}})`) != 2 {
+			t.Error("expected both client methods to apply the token scheme")
+		}
+		if strings.Contains(code, "apiKey") {
+			t.Error("expected the untyped API key to be replaced by scheme credentials")
+		}
+	})
+
+	t.Run("api key, basic and per-operation requirements", func(t *testing.T) {
+		spec := newVerifyTestSpec()
+		spec.Components.SecuritySchemes = map[string]SecurityScheme{
+			"key":    {Type: "apiKey", In: "query", Name: "api_key"},
+			"basic":  {Type: "http", Scheme: "basic"},
+			"digest": {Type: "http", Scheme: "digest"},
+		}
+		spec.Security = []SecurityRequirement{{"key": {}}, {"basic": {}}}
+		pets := spec.Paths["/pets"]
+		pets.POST.Security = []SecurityRequirement{}
+		spec.Paths["/pets"] = pets
+
+		code := generate(t, spec)
+		for _, expected := range []string{
+			`query.Set("api_key", c.keyKey)`,
+			"req.SetBasicAuth(c.basicUsername, c.basicPassword)",
+			"func (c *Client) SetBasic(username string, password string)",
+			`c.applySecurity(req, [][]string{{"key"}, {"basic"}})`,
+		} {
+			if !strings.Contains(code, expected) {
+				t.Errorf("expected generated client to contain %s", expected)
+			}
+		}
+		if strings.Count(code, "c.applySecurity(req,") != 1 {
+			t.Error("expected the operation with empty security to send no credentials")
+		}
+		if strings.Contains(code, "digest") {
+			t.Error("expected unsupported schemes to be skipped")
+		}
+	})
+
+	t.Run("optional security still sends available credentials", func(t *testing.T) {
+		spec := newVerifyTestSpec()
+		spec.Components.SecuritySchemes = map[string]SecurityScheme{
+			"token": {Type: "http", Scheme: "bearer"},
+		}
+		spec.Security = []SecurityRequirement{{}, {"token": {}}}
+
+		code := generate(t, spec)
+		if strings.Count(code, `c.applySecurity(req, [][]string{{"token"}})`) != 2 {
+			t.Errorf("expected the empty requirement to be skipped for the token, got:\n%s", code)
+		}
+	})
+}
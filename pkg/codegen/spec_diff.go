@@ -0,0 +1,289 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ChangeType classifies a difference between two specifications
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// maxSchemaDiffDepth bounds recursion into nested schema properties
+const maxSchemaDiffDepth = 32
+
+// SpecChange describes a single difference between two specifications
+type SpecChange struct {
+	Type     ChangeType `json:"type"`
+	Location string     `json:"location"`
+	Message  string     `json:"message"`
+	Breaking bool       `json:"breaking"`
+}
+
+// SpecDiff holds all differences found between an old and a new specification
+type SpecDiff struct {
+	Changes []SpecChange `json:"changes"`
+}
+
+// HasBreakingChanges reports whether any change is backward-incompatible
+func (d *SpecDiff) HasBreakingChanges() bool {
+	for _, change := range d.Changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// BreakingChanges returns only the backward-incompatible changes
+func (d *SpecDiff) BreakingChanges() []SpecChange {
+	var breaking []SpecChange
+	for _, change := range d.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+func (d *SpecDiff) add(changeType ChangeType, location string, breaking bool, format string, args ...interface{}) {
+	d.Changes = append(d.Changes, SpecChange{
+		Type:     changeType,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+		Breaking: breaking,
+	})
+}
+
+// DiffSpecs compares two parsed specifications and classifies each change.
+// Removed paths, operations, responses and fields, narrowed types and newly
+// required fields or parameters are breaking; additions of optional elements are not.
+func DiffSpecs(oldSpec, newSpec *OpenAPISpec) *SpecDiff {
+	diff := &SpecDiff{}
+
+	for _, path := range unionKeys(oldSpec.Paths, newSpec.Paths) {
+		oldItem, inOld := oldSpec.Paths[path]
+		newItem, inNew := newSpec.Paths[path]
+		switch {
+		case !inNew:
+			diff.add(ChangeRemoved, path, true, "path removed")
+		case !inOld:
+			diff.add(ChangeAdded, path, false, "path added")
+		default:
+			diffPathItem(diff, path, oldItem, newItem)
+		}
+	}
+
+	for _, name := range unionKeys(oldSpec.Components.Schemas, newSpec.Components.Schemas) {
+		location := "#/components/schemas/" + name
+		oldSchema, inOld := oldSpec.Components.Schemas[name]
+		newSchema, inNew := newSpec.Components.Schemas[name]
+		switch {
+		case !inNew:
+			diff.add(ChangeRemoved, location, true, "schema removed")
+		case !inOld:
+			diff.add(ChangeAdded, location, false, "schema added")
+		default:
+			diffSchema(diff, location, oldSchema, newSchema, 0)
+		}
+	}
+
+	return diff
+}
+
+// diffPathItem compares the operations of a path
+func diffPathItem(diff *SpecDiff, path string, oldItem, newItem PathItem) {
+	oldOps := pathOperations(oldItem)
+	newOps := pathOperations(newItem)
+
+	for _, method := range []string{"GET", "POST", "PUT", "DELETE", "PATCH"} {
+		location := method + " " + path
+		oldOp, newOp := oldOps[method], newOps[method]
+		switch {
+		case oldOp == nil && newOp == nil:
+		case newOp == nil:
+			diff.add(ChangeRemoved, location, true, "operation removed")
+		case oldOp == nil:
+			diff.add(ChangeAdded, location, false, "operation added")
+		default:
+			diffOperation(diff, location, oldOp, newOp)
+		}
+	}
+}
+
+// diffOperation compares parameters, request bodies and responses of an operation
+func diffOperation(diff *SpecDiff, location string, oldOp, newOp *Operation) {
+	oldParams := make(map[string]Parameter)
+	for _, param := range oldOp.Parameters {
+		oldParams[param.In+":"+param.Name] = param
+	}
+	newParams := make(map[string]Parameter)
+	for _, param := range newOp.Parameters {
+		newParams[param.In+":"+param.Name] = param
+	}
+
+	for _, key := range unionKeys(oldParams, newParams) {
+		paramLocation := location + " parameter " + key
+		oldParam, inOld := oldParams[key]
+		newParam, inNew := newParams[key]
+		switch {
+		case !inNew:
+			diff.add(ChangeRemoved, paramLocation, true, "parameter removed")
+		case !inOld:
+			diff.add(ChangeAdded, paramLocation, newParam.Required, "%s parameter added", requiredLabel(newParam.Required))
+		default:
+			if !oldParam.Required && newParam.Required {
+				diff.add(ChangeChanged, paramLocation, true, "parameter became required")
+			} else if oldParam.Required && !newParam.Required {
+				diff.add(ChangeChanged, paramLocation, false, "parameter became optional")
+			}
+			diffSchema(diff, paramLocation, oldParam.Schema, newParam.Schema, 0)
+		}
+	}
+
+	switch {
+	case oldOp.RequestBody == nil && newOp.RequestBody == nil:
+	case newOp.RequestBody == nil:
+		diff.add(ChangeRemoved, location+" requestBody", true, "request body removed")
+	case oldOp.RequestBody == nil:
+		diff.add(ChangeAdded, location+" requestBody", newOp.RequestBody.Required,
+			"%s request body added", requiredLabel(newOp.RequestBody.Required))
+	default:
+		if !oldOp.RequestBody.Required && newOp.RequestBody.Required {
+			diff.add(ChangeChanged, location+" requestBody", true, "request body became required")
+		}
+		diffContent(diff, location+" requestBody", oldOp.RequestBody.Content, newOp.RequestBody.Content)
+	}
+
+	for _, status := range unionKeys(oldOp.Responses, newOp.Responses) {
+		responseLocation := location + " response " + status
+		oldResp, inOld := oldOp.Responses[status]
+		newResp, inNew := newOp.Responses[status]
+		switch {
+		case !inNew:
+			diff.add(ChangeRemoved, responseLocation, true, "response removed")
+		case !inOld:
+			diff.add(ChangeAdded, responseLocation, false, "response added")
+		default:
+			diffContent(diff, responseLocation, oldResp.Content, newResp.Content)
+		}
+	}
+}
+
+// diffContent compares media type schemas of a request body or response
+func diffContent(diff *SpecDiff, location string, oldContent, newContent map[string]MediaType) {
+	for _, mediaType := range unionKeys(oldContent, newContent) {
+		contentLocation := location + " " + mediaType
+		oldMedia, inOld := oldContent[mediaType]
+		newMedia, inNew := newContent[mediaType]
+		switch {
+		case !inNew:
+			diff.add(ChangeRemoved, contentLocation, true, "media type removed")
+		case !inOld:
+			diff.add(ChangeAdded, contentLocation, false, "media type added")
+		default:
+			diffSchema(diff, contentLocation, oldMedia.Schema, newMedia.Schema, 0)
+		}
+	}
+}
+
+// diffSchema compares schema types, required fields and properties recursively
+func diffSchema(diff *SpecDiff, location string, oldSchema, newSchema Schema, depth int) {
+	if depth > maxSchemaDiffDepth {
+		return
+	}
+
+	if oldSchema.Ref != newSchema.Ref {
+		diff.add(ChangeChanged, location, true, "reference changed from %q to %q", oldSchema.Ref, newSchema.Ref)
+	}
+
+	if oldSchema.Type != newSchema.Type {
+		diff.add(ChangeChanged, location, isNarrowedType(oldSchema.Type, newSchema.Type),
+			"type changed from %q to %q", oldSchema.Type, newSchema.Type)
+	}
+
+	oldRequired := make(map[string]bool, len(oldSchema.Required))
+	for _, name := range oldSchema.Required {
+		oldRequired[name] = true
+	}
+	newRequired := make(map[string]bool, len(newSchema.Required))
+	for _, name := range newSchema.Required {
+		newRequired[name] = true
+	}
+
+	for _, name := range unionKeys(oldSchema.Properties, newSchema.Properties) {
+		fieldLocation := location + "." + name
+		oldField, inOld := oldSchema.Properties[name]
+		newField, inNew := newSchema.Properties[name]
+		switch {
+		case !inNew:
+			diff.add(ChangeRemoved, fieldLocation, true, "%s field removed", requiredLabel(oldRequired[name]))
+		case !inOld:
+			diff.add(ChangeAdded, fieldLocation, newRequired[name], "%s field added", requiredLabel(newRequired[name]))
+		default:
+			if !oldRequired[name] && newRequired[name] {
+				diff.add(ChangeChanged, fieldLocation, true, "field became required")
+			} else if oldRequired[name] && !newRequired[name] {
+				diff.add(ChangeChanged, fieldLocation, false, "field became optional")
+			}
+			diffSchema(diff, fieldLocation, oldField, newField, depth+1)
+		}
+	}
+
+	switch {
+	case oldSchema.Items == nil && newSchema.Items == nil:
+	case oldSchema.Items == nil || newSchema.Items == nil:
+		diff.add(ChangeChanged, location+"[]", true, "array items schema changed")
+	default:
+		diffSchema(diff, location+"[]", *oldSchema.Items, *newSchema.Items, depth+1)
+	}
+}
+
+// isNarrowedType reports whether a type change restricts the accepted values
+func isNarrowedType(oldType, newType string) bool {
+	if newType == "" {
+		return false // untyped accepts anything
+	}
+	if oldType == "integer" && newType == "number" {
+		return false
+	}
+	return true
+}
+
+func requiredLabel(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+func pathOperations(item PathItem) map[string]*Operation {
+	return map[string]*Operation{
+		"GET":    item.GET,
+		"POST":   item.POST,
+		"PUT":    item.PUT,
+		"DELETE": item.DELETE,
+		"PATCH":  item.PATCH,
+	}
+}
+
+// unionKeys returns the sorted union of keys from two maps
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]V{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
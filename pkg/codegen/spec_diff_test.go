@@ -0,0 +1,113 @@
+package codegen
+
+import (
+	"testing"
+)
+
+func newDiffTestSpec(schema Schema) *OpenAPISpec {
+	return &OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Paths: map[string]PathItem{
+			"/users": {
+				POST: &Operation{
+					OperationID: "createUser",
+					RequestBody: &RequestBody{
+						Required: true,
+						Content: map[string]MediaType{
+							"application/json": {Schema: Schema{Ref: "#/components/schemas/User"}},
+						},
+					},
+					Responses: map[string]Response{"201": {Description: "created"}},
+				},
+			},
+		},
+		Components: Components{
+			Schemas: map[string]Schema{"User": schema},
+		},
+	}
+}
+
+// TestDiffSpecs tests breaking-change classification between specifications
+func TestDiffSpecs(t *testing.T) {
+	baseUser := Schema{
+		Type:     "object",
+		Required: []string{"id", "name"},
+		Properties: map[string]Schema{
+			"id":   {Type: "string"},
+			"name": {Type: "string"},
+		},
+	}
+
+	t.Run("removed required field is breaking", func(t *testing.T) {
+		newUser := Schema{
+			Type:       "object",
+			Required:   []string{"id"},
+			Properties: map[string]Schema{"id": {Type: "string"}},
+		}
+
+		diff := DiffSpecs(newDiffTestSpec(baseUser), newDiffTestSpec(newUser))
+
+		if !diff.HasBreakingChanges() {
+			t.Fatalf("expected breaking change, got %+v", diff.Changes)
+		}
+		breaking := diff.BreakingChanges()
+		if len(breaking) != 1 || breaking[0].Location != "#/components/schemas/User.name" || breaking[0].Type != ChangeRemoved {
+			t.Errorf("expected removal of User.name to be the only breaking change, got %+v", breaking)
+		}
+	})
+
+	t.Run("added optional field is non-breaking", func(t *testing.T) {
+		newUser := baseUser
+		newUser.Properties = map[string]Schema{
+			"id":    {Type: "string"},
+			"name":  {Type: "string"},
+			"email": {Type: "string"},
+		}
+
+		diff := DiffSpecs(newDiffTestSpec(baseUser), newDiffTestSpec(newUser))
+
+		if diff.HasBreakingChanges() {
+			t.Errorf("expected no breaking changes, got %+v", diff.BreakingChanges())
+		}
+		if len(diff.Changes) != 1 || diff.Changes[0].Type != ChangeAdded {
+			t.Errorf("expected a single added change, got %+v", diff.Changes)
+		}
+	})
+
+	t.Run("added required field and narrowed type are breaking", func(t *testing.T) {
+		newUser := Schema{
+			Type:     "object",
+			Required: []string{"id", "name", "tenant"},
+			Properties: map[string]Schema{
+				"id":     {Type: "integer"},
+				"name":   {Type: "string"},
+				"tenant": {Type: "string"},
+			},
+		}
+
+		diff := DiffSpecs(newDiffTestSpec(baseUser), newDiffTestSpec(newUser))
+
+		if got := len(diff.BreakingChanges()); got != 2 {
+			t.Errorf("expected 2 breaking changes, got %d: %+v", got, diff.Changes)
+		}
+	})
+
+	t.Run("removed operation is breaking", func(t *testing.T) {
+		newSpec := newDiffTestSpec(baseUser)
+		newSpec.Paths["/users"] = PathItem{}
+
+		diff := DiffSpecs(newDiffTestSpec(baseUser), newSpec)
+
+		breaking := diff.BreakingChanges()
+		if len(breaking) != 1 || breaking[0].Location != "POST /users" {
+			t.Errorf("expected removed POST /users to be breaking, got %+v", breaking)
+		}
+	})
+
+	t.Run("identical specs have no changes", func(t *testing.T) {
+		diff := DiffSpecs(newDiffTestSpec(baseUser), newDiffTestSpec(baseUser))
+		if len(diff.Changes) != 0 {
+			t.Errorf("expected no changes, got %+v", diff.Changes)
+		}
+	})
+}
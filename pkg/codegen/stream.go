@@ -0,0 +1,329 @@
+package codegen
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamSummary describes the code written by StreamCode
+type StreamSummary struct {
+	Package   string
+	Files     []string
+	Types     int
+	Functions int
+	Constants int
+}
+
+// declJob renders one top-level declaration
+type declJob func() (string, error)
+
+// renderedDecl is a formatted declaration and the package names it references;
+// used is nil when the declaration could not be formatted
+type renderedDecl struct {
+	src  string
+	used map[string]bool
+	err  error
+}
+
+// StreamCode generates code from an OpenAPI specification and writes it to
+// the output directory as it goes, producing the same files as
+// GenerateFromSpec followed by WriteCode. Declarations are rendered in
+// parallel by Workers goroutines and written in the same order as the
+// in-memory path, with only a small window of them held in memory at once.
+func (cg *CodeGenerator) StreamCode(ctx context.Context, spec *OpenAPISpec) (*StreamSummary, error) {
+	start := time.Now()
+
+	cg.logger.Info("starting_streaming_code_generation",
+		"spec_title", spec.Info.Title,
+		"spec_version", spec.Info.Version,
+		"paths_count", len(spec.Paths),
+		"schemas_count", len(spec.Components.Schemas),
+		"workers", cg.workerCount())
+
+	if err := os.MkdirAll(cg.config.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	imports := cg.generateImports(spec)
+	schemaNames := sortedSchemaNames(spec)
+
+	// Schema types, then the client struct
+	var typeDecls []declJob
+	if cg.config.GenerateTypes {
+		for _, name := range schemaNames {
+			typeDecls = append(typeDecls, func() (string, error) {
+				typeDef, err := cg.schemaToType(name, spec.Components.Schemas[name], spec)
+				if err != nil {
+					return "", fmt.Errorf("failed to convert schema %s: %w", name, err)
+				}
+				return renderTypeDecl(*typeDef), nil
+			})
+		}
+	}
+	if cg.config.GenerateClients {
+		typeDecls = append(typeDecls, func() (string, error) {
+			return renderTypeDecl(cg.clientType(spec)), nil
+		})
+	}
+
+	// Handlers, then the client constructor and methods, then validators
+	var funcDecls []declJob
+	if cg.config.GenerateHandlers {
+		for _, op := range sortedOperations(spec, handlerMethods...) {
+			funcDecls = append(funcDecls, func() (string, error) {
+				return renderFunctionDecl(cg.operationToHandler(op.method, op.path, op.op, spec)), nil
+			})
+		}
+	}
+	if cg.config.GenerateClients {
+		funcDecls = append(funcDecls, func() (string, error) {
+			return renderFunctionDecl(cg.clientConstructor(spec)), nil
+		})
+		for _, fn := range cg.clientAuthFunctions(spec) {
+			funcDecls = append(funcDecls, func() (string, error) {
+				return renderFunctionDecl(fn), nil
+			})
+		}
+		for _, op := range sortedOperations(spec, clientMethods...) {
+			funcDecls = append(funcDecls, func() (string, error) {
+				return renderFunctionDecl(cg.operationToClientMethod(op.method, op.path, op.op, spec)), nil
+			})
+		}
+	}
+	if cg.config.GenerateValidators {
+		for _, name := range schemaNames {
+			funcDecls = append(funcDecls, func() (string, error) {
+				return renderFunctionDecl(cg.schemaToValidator(name, spec.Components.Schemas[name])), nil
+			})
+		}
+	}
+
+	summary := &StreamSummary{
+		Package:   cg.config.PackageName,
+		Types:     len(typeDecls),
+		Functions: len(funcDecls),
+	}
+
+	if len(typeDecls) > 0 {
+		if err := cg.streamFile(ctx, "types.go", imports, typeDecls); err != nil {
+			return nil, fmt.Errorf("failed to write types file: %w", err)
+		}
+		summary.Files = append(summary.Files, "types.go")
+	}
+
+	if len(funcDecls) > 0 {
+		if err := cg.streamFile(ctx, "handlers.go", imports, funcDecls); err != nil {
+			return nil, fmt.Errorf("failed to write functions file: %w", err)
+		}
+		summary.Files = append(summary.Files, "handlers.go")
+	}
+
+	constants := cg.generateConstants(spec)
+	summary.Constants = len(constants)
+	if len(constants) > 0 {
+		if _, err := cg.writeConstantsFile(&GeneratedCode{Package: cg.config.PackageName, Constants: constants}); err != nil {
+			return nil, fmt.Errorf("failed to write constants file: %w", err)
+		}
+		summary.Files = append(summary.Files, "constants.go")
+	}
+
+	duration := time.Since(start)
+
+	cg.logger.Info("streaming_code_generation_completed",
+		"duration", duration,
+		"output_dir", cg.config.OutputDir,
+		"types_generated", summary.Types,
+		"functions_generated", summary.Functions,
+		"constants_generated", summary.Constants)
+
+	cg.recordGenerationMetrics(spec, summary.Types, summary.Functions, summary.Constants, duration)
+
+	if cg.config.Verify {
+		written := make(map[string][]byte, len(summary.Files))
+		for _, name := range summary.Files {
+			content, err := os.ReadFile(filepath.Join(cg.config.OutputDir, name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s for verification: %w", name, err)
+			}
+			written[name] = content
+		}
+		if err := cg.verifyWrittenCode(summary.Package, written); err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+// streamFile renders decls across the worker pool and writes them to the named
+// file in order. Each declaration is rendered by its own goroutine, at most
+// Workers at a time, and at most twice that many finished ones wait to be
+// written, so memory stays bounded however many declarations there are.
+func (cg *CodeGenerator) streamFile(ctx context.Context, name string, imports []string, decls []declJob) error {
+	out, err := cg.newDeclFileWriter(name)
+	if err != nil {
+		return err
+	}
+	defer out.discard()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := cg.workerCount()
+	pending := make(chan chan renderedDecl, 2*workers)
+	go func() {
+		defer close(pending)
+		slots := make(chan struct{}, workers)
+		for _, render := range decls {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			result := make(chan renderedDecl, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				<-slots
+				return
+			}
+
+			go func() {
+				defer func() { <-slots }()
+				result <- cg.renderDecl(name, render)
+			}()
+		}
+	}()
+
+	for result := range pending {
+		decl := <-result
+		if decl.err != nil {
+			return decl.err
+		}
+		if err := out.write(decl); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return out.finish(cg.config.PackageName, imports)
+}
+
+// renderDecl renders and formats one declaration, keeping it unformatted if
+// it does not parse, as WriteCode does for whole files
+func (cg *CodeGenerator) renderDecl(file string, render declJob) renderedDecl {
+	src, err := render()
+	if err != nil {
+		return renderedDecl{err: err}
+	}
+
+	formatted, used, err := formatGoDecls(src)
+	if err != nil {
+		cg.logger.Warn("failed_to_format_declaration", "file", file, "error", err)
+		return renderedDecl{src: strings.TrimSpace(src)}
+	}
+	return renderedDecl{src: formatted, used: used}
+}
+
+func (cg *CodeGenerator) workerCount() int {
+	if cg.config.Workers > 0 {
+		return cg.config.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// declFileWriter spools formatted declarations of one generated file to a
+// temporary file, then writes the file headed by only the imports they use
+type declFileWriter struct {
+	path        string
+	body        *os.File
+	buf         *bufio.Writer
+	used        map[string]bool
+	unformatted bool
+}
+
+func (cg *CodeGenerator) newDeclFileWriter(name string) (*declFileWriter, error) {
+	body, err := os.CreateTemp(cg.config.OutputDir, "."+name+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	return &declFileWriter{
+		path: filepath.Join(cg.config.OutputDir, name),
+		body: body,
+		buf:  bufio.NewWriter(body),
+		used: make(map[string]bool),
+	}, nil
+}
+
+// write appends a declaration, separated from the previous one by a blank line
+func (w *declFileWriter) write(decl renderedDecl) error {
+	if decl.used == nil {
+		w.unformatted = true
+	}
+	for name := range decl.used {
+		w.used[name] = true
+	}
+
+	w.buf.WriteString("\n")
+	w.buf.WriteString(decl.src)
+	_, err := w.buf.WriteString("\n")
+	return err
+}
+
+// finish writes the file: its package clause and used imports, then the
+// spooled declarations
+func (w *declFileWriter) finish(packageName string, imports []string) error {
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	var used []string
+	for _, imp := range imports {
+		spec := &ast.ImportSpec{Path: &ast.BasicLit{Value: strconv.Quote(imp)}}
+		if w.unformatted || importUsed(spec, w.used) {
+			used = append(used, imp)
+		}
+	}
+	header, err := format.Source([]byte(renderFileHeader(packageName, used)))
+	if err != nil {
+		return fmt.Errorf("failed to format file header: %w", err)
+	}
+
+	out, err := os.Create(w.path)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(header); err != nil {
+		out.Close()
+		return err
+	}
+	if _, err := w.body.Seek(0, io.SeekStart); err != nil {
+		out.Close()
+		return err
+	}
+	if _, err := io.Copy(out, w.body); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// discard removes the temporary file
+func (w *declFileWriter) discard() {
+	w.body.Close()
+	os.Remove(w.body.Name())
+}
@@ -0,0 +1,161 @@
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// newLargeTestSpec builds a spec with the given number of schemas, each
+// referencing its predecessor, and one path per ten schemas
+func newLargeTestSpec(schemas int) *OpenAPISpec {
+	minLength := 1
+	spec := &OpenAPISpec{
+		OpenAPI:    "3.0.0",
+		Info:       APIInfo{Title: "Large", Version: "1.0.0"},
+		Servers:    []Server{{URL: "https://large.example.com", Description: "production"}},
+		Paths:      make(map[string]PathItem),
+		Components: Components{Schemas: make(map[string]Schema)},
+	}
+
+	for i := 0; i < schemas; i++ {
+		name := fmt.Sprintf("Resource%d", i)
+		properties := map[string]Schema{
+			"id":      {Type: "integer", Format: "int64", Description: "Unique identifier"},
+			"name":    {Type: "string", MinLength: &minLength},
+			"labels":  {Type: "array", Items: &Schema{Type: "string"}},
+			"score":   {Type: "number", Format: "float"},
+			"enabled": {Type: "boolean"},
+		}
+		if i > 0 {
+			properties["parent"] = Schema{Ref: fmt.Sprintf("#/components/schemas/Resource%d", i-1)}
+		}
+		spec.Components.Schemas[name] = Schema{
+			Type:        "object",
+			Description: fmt.Sprintf("is resource number %d", i),
+			Required:    []string{"id", "name"},
+			Properties:  properties,
+		}
+
+		if i%10 == 0 {
+			spec.Paths[fmt.Sprintf("/resources%d/{id}", i)] = PathItem{
+				GET:    &Operation{OperationID: fmt.Sprintf("get_resource_%d", i), Summary: "Get a resource"},
+				POST:   &Operation{OperationID: fmt.Sprintf("update_resource_%d", i), Summary: "Update a resource"},
+				DELETE: &Operation{OperationID: fmt.Sprintf("delete_resource_%d", i), Summary: "Delete a resource"},
+			}
+		}
+	}
+
+	return spec
+}
+
+func newSecuredTestSpec() *OpenAPISpec {
+	spec := newVerifyTestSpec()
+	spec.Components.SecuritySchemes = map[string]SecurityScheme{
+		"token": {Type: "http", Scheme: "bearer"},
+		"key":   {Type: "apiKey", In: "header", Name: "X-API-Key"},
+	}
+	return spec
+}
+
+func newStreamTestGenerator(dir string, workers int) *CodeGenerator {
+	config := defaultGeneratorConfig()
+	config.OutputDir = dir
+	config.Workers = workers
+	return NewCodeGeneratorWithConfig(logging.New("test"), &mockMetrics{}, config)
+}
+
+// TestStreamCodeMatchesWriteCode tests that streaming generation writes the same files as the in-memory path
+func TestStreamCodeMatchesWriteCode(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		spec *OpenAPISpec
+	}{
+		{"verify spec", newVerifyTestSpec()},
+		{"large spec", newLargeTestSpec(500)},
+		{"secured spec", newSecuredTestSpec()},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			inMemory := newStreamTestGenerator(t.TempDir(), 0)
+			generated, err := inMemory.GenerateFromSpec(ctx, test.spec)
+			if err != nil {
+				t.Fatalf("generation failed: %v", err)
+			}
+			if err := inMemory.WriteCode(ctx, generated); err != nil {
+				t.Fatalf("failed to write code: %v", err)
+			}
+
+			streamed := newStreamTestGenerator(t.TempDir(), 8)
+			summary, err := streamed.StreamCode(ctx, test.spec)
+			if err != nil {
+				t.Fatalf("streaming generation failed: %v", err)
+			}
+			if summary.Types != len(generated.Types) || summary.Functions != len(generated.Functions) {
+				t.Errorf("expected %d types and %d functions, got %d and %d",
+					len(generated.Types), len(generated.Functions), summary.Types, summary.Functions)
+			}
+
+			expected, _ := filepath.Glob(filepath.Join(inMemory.config.OutputDir, "*"))
+			actual, _ := filepath.Glob(filepath.Join(streamed.config.OutputDir, "*"))
+			if len(actual) != len(expected) {
+				t.Fatalf("expected files %v, got %v", expected, actual)
+			}
+			for _, path := range expected {
+				name := filepath.Base(path)
+				want, _ := os.ReadFile(path)
+				got, err := os.ReadFile(filepath.Join(streamed.config.OutputDir, name))
+				if err != nil {
+					t.Fatalf("missing streamed file %s: %v", name, err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("streamed %s differs from the in-memory output", name)
+				}
+			}
+		})
+	}
+
+	t.Run("streamed output compiles", func(t *testing.T) {
+		generator := newStreamTestGenerator(t.TempDir(), 4)
+		generator.config.Verify = true
+		if _, err := generator.StreamCode(context.Background(), newVerifyTestSpec()); err != nil {
+			t.Fatalf("expected streamed code to compile, got %v", err)
+		}
+	})
+}
+
+func BenchmarkStreamCode(b *testing.B) {
+	spec := newLargeTestSpec(5000)
+	generator := newStreamTestGenerator(b.TempDir(), 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := generator.StreamCode(context.Background(), spec); err != nil {
+			b.Fatalf("streaming generation failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkGenerateAndWriteCode(b *testing.B) {
+	spec := newLargeTestSpec(5000)
+	generator := newStreamTestGenerator(b.TempDir(), 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		generated, err := generator.GenerateFromSpec(context.Background(), spec)
+		if err != nil {
+			b.Fatalf("generation failed: %v", err)
+		}
+		if err := generator.WriteCode(context.Background(), generated); err != nil {
+			b.Fatalf("failed to write code: %v", err)
+		}
+	}
+}
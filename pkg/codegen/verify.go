@@ -0,0 +1,203 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompileError is one compile error in generated code
+type CompileError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e CompileError) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// VerificationError reports generated code that does not compile
+type VerificationError struct {
+	Errors []CompileError
+}
+
+func (e *VerificationError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("generated code does not compile (%d errors):", len(e.Errors)))
+	for _, compileErr := range e.Errors {
+		lines = append(lines, "\t"+compileErr.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// VerifyGoSource parses and type-checks the files of one generated package,
+// keyed by file name, returning a *VerificationError listing every error by
+// file and line. Imports are resolved from the compiled standard library and
+// the packages available to the go command.
+func VerifyGoSource(packageName string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fset := token.NewFileSet()
+	verr := &VerificationError{}
+	var parsed []*ast.File
+	for _, name := range names {
+		file, err := parser.ParseFile(fset, name, files[name], parser.AllErrors)
+		if err != nil {
+			var list scanner.ErrorList
+			if !errors.As(err, &list) {
+				return fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+			for _, parseErr := range list {
+				verr.Errors = append(verr.Errors, CompileError{
+					File:    parseErr.Pos.Filename,
+					Line:    parseErr.Pos.Line,
+					Column:  parseErr.Pos.Column,
+					Message: parseErr.Msg,
+				})
+			}
+			continue
+		}
+		if file.Name.Name != packageName {
+			position := fset.Position(file.Name.Pos())
+			verr.Errors = append(verr.Errors, CompileError{
+				File:    position.Filename,
+				Line:    position.Line,
+				Column:  position.Column,
+				Message: fmt.Sprintf("package %s, expected %s", file.Name.Name, packageName),
+			})
+		}
+		parsed = append(parsed, file)
+	}
+
+	// Type errors are only meaningful once every file parses
+	if len(verr.Errors) == 0 {
+		conf := types.Config{
+			Importer: importer.Default(),
+			Error: func(err error) {
+				var typeErr types.Error
+				if errors.As(err, &typeErr) {
+					position := typeErr.Fset.Position(typeErr.Pos)
+					verr.Errors = append(verr.Errors, CompileError{
+						File:    position.Filename,
+						Line:    position.Line,
+						Column:  position.Column,
+						Message: typeErr.Msg,
+					})
+					return
+				}
+				verr.Errors = append(verr.Errors, CompileError{Message: err.Error()})
+			},
+		}
+		conf.Check(packageName, fset, parsed, nil)
+	}
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// formatGoSource drops imports the file does not reference, since every file
+// is rendered with the imports of the whole package, and formats the result
+func formatGoSource(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := selectorPackages(file)
+
+	decls := file.Decls[:0]
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+
+		specs := genDecl.Specs[:0]
+		for _, spec := range genDecl.Specs {
+			if importUsed(spec.(*ast.ImportSpec), used) {
+				specs = append(specs, spec)
+			}
+		}
+		if len(specs) > 0 {
+			genDecl.Specs = specs
+			decls = append(decls, genDecl)
+		}
+	}
+	file.Decls = decls
+
+	return printGoFile(fset, file)
+}
+
+// formatGoDecls formats a run of top-level declarations on their own, as
+// formatGoSource would within a whole file, and returns the package names
+// they reference so the file's imports can be pruned once all are written
+func formatGoDecls(src string) (string, map[string]bool, error) {
+	const header = "package p\n\n"
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", header+src, parser.ParseComments)
+	if err != nil {
+		return "", nil, err
+	}
+
+	formatted, err := printGoFile(fset, file)
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.TrimSpace(strings.TrimPrefix(string(formatted), header)), selectorPackages(file), nil
+}
+
+// selectorPackages returns the identifiers used as selector operands, which
+// covers every package name a file references
+func selectorPackages(file *ast.File) map[string]bool {
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// importUsed reports whether an import is referenced by a file using the
+// given package names
+func importUsed(importSpec *ast.ImportSpec, used map[string]bool) bool {
+	importPath, _ := strconv.Unquote(importSpec.Path.Value)
+	name := path.Base(importPath)
+	if importSpec.Name != nil {
+		name = importSpec.Name.Name
+	}
+	return used[name] || name == "_" || name == "."
+}
+
+func printGoFile(fset *token.FileSet, file *ast.File) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,140 @@
+package codegen
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+)
+
+func newVerifyTestSpec() *OpenAPISpec {
+	maxLength := 64
+	return &OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Info:    APIInfo{Title: "Pet Store", Version: "1.0.0"},
+		Servers: []Server{{URL: "https://pets.example.com"}},
+		Paths: map[string]PathItem{
+			"/pets": {
+				GET: &Operation{
+					OperationID: "listPets",
+					Parameters:  []Parameter{{Name: "limit", In: "query", Schema: Schema{Type: "integer"}}},
+					Responses: map[string]Response{"200": {Content: map[string]MediaType{
+						"application/json": {Schema: Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}}},
+					}}},
+				},
+				POST: &Operation{
+					OperationID: "createPet",
+					RequestBody: &RequestBody{Content: map[string]MediaType{
+						"application/json": {Schema: Schema{Type: "object"}},
+					}},
+					Responses: map[string]Response{"201": {Content: map[string]MediaType{
+						"application/json": {Schema: Schema{Type: "string"}},
+					}}},
+				},
+			},
+			"/pets/{id}": {
+				DELETE: &Operation{OperationID: "deletePet"},
+			},
+		},
+		Components: Components{
+			Schemas: map[string]Schema{
+				"Pet": {
+					Type:     "object",
+					Required: []string{"id", "name"},
+					Properties: map[string]Schema{
+						"id":   {Type: "integer", Format: "int64"},
+						"name": {Type: "string", MaxLength: &maxLength},
+						"tags": {Type: "array", Items: &Schema{Type: "string"}},
+						"age":  {Type: "number"},
+					},
+				},
+				"PetList": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}},
+			},
+		},
+	}
+}
+
+// TestWriteCodeVerification tests that generated code is type-checked when verification is enabled
+func TestWriteCodeVerification(t *testing.T) {
+	logger := logging.New("test")
+
+	newGenerator := func(t *testing.T) *CodeGenerator {
+		config := defaultGeneratorConfig()
+		config.OutputDir = t.TempDir()
+		config.Verify = true
+		return NewCodeGeneratorWithConfig(logger, &mockMetrics{}, config)
+	}
+
+	t.Run("well-formed output passes verification", func(t *testing.T) {
+		generator := newGenerator(t)
+		generated, err := generator.GenerateFromSpec(context.Background(), newVerifyTestSpec())
+		if err != nil {
+			t.Fatalf("generation failed: %v", err)
+		}
+		if err := generator.WriteCode(context.Background(), generated); err != nil {
+			t.Fatalf("expected generated code to compile, got %v", err)
+		}
+	})
+
+	t.Run("broken template fails verification", func(t *testing.T) {
+		generator := newGenerator(t)
+		generated, err := generator.GenerateFromSpec(context.Background(), newVerifyTestSpec())
+		if err != nil {
+			t.Fatalf("generation failed: %v", err)
+		}
+		generated.Functions = append(generated.Functions, FunctionDefinition{
+			Name:    "Broken",
+			Returns: []ParameterDefinition{{Type: "int"}},
+			Body:    "\treturn undefinedValue",
+		})
+
+		err = generator.WriteCode(context.Background(), generated)
+		var verr *VerificationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected a verification error, got %v", err)
+		}
+		if len(verr.Errors) != 1 {
+			t.Fatalf("expected exactly one compile error, got %v", verr)
+		}
+		compileErr := verr.Errors[0]
+		if compileErr.File != filepath.Join(generator.config.OutputDir, "handlers.go") || compileErr.Line == 0 ||
+			!strings.Contains(compileErr.Message, "undefinedValue") {
+			t.Errorf("expected the error to point at undefinedValue in handlers.go, got %s", compileErr)
+		}
+	})
+
+	t.Run("syntax errors are reported with their position", func(t *testing.T) {
+		err := VerifyGoSource("generated", map[string][]byte{
+			"types.go": []byte("package generated\n\ntype Pet struct {\n\tName string\n"),
+		})
+		var verr *VerificationError
+		if !errors.As(err, &verr) || verr.Errors[0].File != "types.go" || verr.Errors[0].Line == 0 {
+			t.Errorf("expected a positioned syntax error in types.go, got %v", err)
+		}
+	})
+}
+
+// mockMetrics implements a basic metrics interface for testing
+type mockMetrics struct{}
+
+func (m *mockMetrics) Inc(name string, labels ...string)                    {}
+func (m *mockMetrics) Add(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Set(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Observe(name string, value float64, labels ...string) {}
+func (m *mockMetrics) Time(name string, labels ...string) metrics.Timer     { return &mockTimer{} }
+func (m *mockMetrics) WithLabels(labels map[string]string) metrics.Metrics  { return m }
+func (m *mockMetrics) WithPrefix(prefix string) metrics.Metrics             { return m }
+func (m *mockMetrics) GetStats(name string) metrics.MetricStats             { return metrics.MetricStats{} }
+func (m *mockMetrics) GetAllStats() map[string]metrics.MetricStats {
+	return make(map[string]metrics.MetricStats)
+}
+
+type mockTimer struct{}
+
+func (t *mockTimer) Duration() time.Duration { return 0 }
+func (t *mockTimer) Stop() time.Duration     { return 0 }
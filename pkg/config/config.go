@@ -73,6 +73,16 @@ type LoadOptions struct {
 
 	// Default configuration to merge with loaded config
 	Defaults interface{}
+
+	// Deprecated field migrations applied before decoding
+	Migrations *MigrationRegistry
+
+	// Where to write the migrated file when deprecated fields were found;
+	// empty leaves the original file as the only copy
+	MigratedFilePath string
+
+	// Fail on fields unknown to the target struct instead of warning
+	Strict bool
 }
 
 // LoadFromFile loads configuration from a YAML file with optional environment overrides
@@ -104,8 +114,34 @@ func (l *Loader) LoadFromFile(filePath string, config interface{}, opts *LoadOpt
 		return fmt.Errorf("failed to read configuration file %s: %w", filePath, err)
 	}
 
-	// Parse YAML
-	if err := yaml.Unmarshal(data, config); err != nil {
+	// Move deprecated fields to their replacements before decoding
+	if opts.Migrations != nil {
+		migrated, applied, err := l.migrateDocument(filePath, data, opts.Migrations)
+		if err != nil {
+			l.logger.Error("config_yaml_parse_failed",
+				"file_path", filePath,
+				"error", err)
+			return fmt.Errorf("failed to parse YAML configuration: %w", err)
+		}
+		if len(applied) > 0 && opts.MigratedFilePath != "" {
+			if err := l.writeMigratedConfig(opts.MigratedFilePath, migrated); err != nil {
+				return err
+			}
+		}
+		data = migrated
+	}
+
+	// Check duration fields so malformed values name the offending field
+	data, err = normalizeDurations(data, config)
+	if err != nil {
+		l.logger.Error("config_duration_invalid",
+			"file_path", filePath,
+			"error", err)
+		return fmt.Errorf("invalid configuration %s: %w", filePath, err)
+	}
+
+	// Parse YAML; unknown fields warn unless strict mode is set
+	if err := l.decodeKnownFields(filePath, data, config, opts.Strict); err != nil {
 		l.logger.Error("config_yaml_parse_failed",
 			"file_path", filePath,
 			"error", err)
@@ -281,6 +317,16 @@ func (l *Loader) envKeyToFieldName(key string) string {
 
 // setFieldValue sets a field value with proper type conversion
 func (l *Loader) setFieldValue(field reflect.Value, value string, fieldName string) error {
+	// time.Duration is an int64, so it must be matched before the kind switch
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration value for %s: %w", fieldName, err)
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(value)
@@ -322,16 +368,7 @@ func (l *Loader) setFieldValue(field reflect.Value, value string, fieldName stri
 			return fmt.Errorf("unsupported slice type for %s", fieldName)
 		}
 	case reflect.Struct:
-		// Handle time.Duration
-		if field.Type().String() == "time.Duration" {
-			duration, err := time.ParseDuration(value)
-			if err != nil {
-				return fmt.Errorf("invalid duration value for %s: %w", fieldName, err)
-			}
-			field.Set(reflect.ValueOf(duration))
-		} else {
-			return fmt.Errorf("unsupported struct type for %s: %s", fieldName, field.Type())
-		}
+		return fmt.Errorf("unsupported struct type for %s: %s", fieldName, field.Type())
 	default:
 		return fmt.Errorf("unsupported field type for %s: %s", fieldName, field.Kind())
 	}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DurationError lists the malformed duration values of a configuration file
+type DurationError struct {
+	Errors []string
+}
+
+func (e *DurationError) Error() string {
+	return "invalid duration values:\n  " + strings.Join(e.Errors, "\n  ")
+}
+
+// normalizeDurations checks every value decoded into a time.Duration field
+// of config before it reaches the YAML decoder, whose own errors do not say
+// which field was wrong or what a valid value looks like. Values must be Go
+// duration strings such as "30s", "5m" or "1h30m"; a bare 0 is accepted and
+// rewritten to "0s". Other unit-less numbers are rejected, since yaml.v3
+// would not read them as nanoseconds either. It returns the document to
+// decode, re-encoded only when a value was rewritten.
+func normalizeDurations(data []byte, config interface{}) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return data, nil
+	}
+
+	var errs []string
+	rewritten := checkDurations(doc.Content[0], reflect.TypeOf(config), "", &errs)
+	if len(errs) > 0 {
+		return nil, &DurationError{Errors: errs}
+	}
+	if !rewritten {
+		return data, nil
+	}
+	return yaml.Marshal(&doc)
+}
+
+// checkDurations walks node alongside the type it decodes into, recording
+// malformed durations under their dotted path. It reports whether any node
+// was rewritten.
+func checkDurations(node *yaml.Node, t reflect.Type, path string, errs *[]string) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		return checkDuration(node, path, errs)
+	}
+
+	rewritten := false
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type)
+		collectYAMLFields(t, fields)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if fieldType, ok := fields[key]; ok {
+				rewritten = checkDurations(node.Content[i+1], fieldType, joinConfigPath(path, key), errs) || rewritten
+			}
+		}
+
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			rewritten = checkDurations(node.Content[i+1], t.Elem(), joinConfigPath(path, node.Content[i].Value), errs) || rewritten
+		}
+
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			rewritten = checkDurations(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs) || rewritten
+		}
+	}
+	return rewritten
+}
+
+// checkDuration validates one duration scalar, rewriting a bare 0 to "0s"
+func checkDuration(node *yaml.Node, path string, errs *[]string) bool {
+	if node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return false
+	}
+
+	value := strings.TrimSpace(node.Value)
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		if number == 0 {
+			node.Tag = "!!str"
+			node.Value = "0s"
+			return true
+		}
+		*errs = append(*errs, fmt.Sprintf("%s (line %d): %s has no unit, use a duration such as \"%ss\" or \"%sm\"",
+			path, node.Line, value, value, value))
+		return false
+	}
+
+	if _, err := time.ParseDuration(value); err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s (line %d): %q is not a duration, use a number with a unit (ns, us, ms, s, m, h) such as \"30s\" or \"1h30m\"",
+			path, node.Line, node.Value))
+	}
+	return false
+}
+
+// collectYAMLFields maps the keys of a struct, including inlined ones, to their types
+func collectYAMLFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline, skip := yamlFieldName(field)
+		if skip {
+			continue
+		}
+		if inline {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				collectYAMLFields(fieldType, fields)
+			}
+			continue
+		}
+		fields[name] = field.Type
+	}
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDurationParsing tests that duration fields accept Go duration strings and reject malformed values
+func TestDurationParsing(t *testing.T) {
+	load := func(t *testing.T, content string) (*GatewayConfig, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg := GetDefaults()
+		err := NewLoader(&noOpLogger{}).LoadFromFile(path, cfg, &LoadOptions{})
+		return cfg, err
+	}
+
+	t.Run("duration strings are parsed", func(t *testing.T) {
+		cfg, err := load(t, `
+server:
+  read_timeout: 30s
+  write_timeout: "5m"
+  idle_timeout: 1h30m
+  request_timeout: 0
+  method_timeouts:
+    tools/call: 250ms
+`)
+		if err != nil {
+			t.Fatalf("expected durations to load, got %v", err)
+		}
+		expected := map[string][2]time.Duration{
+			"read_timeout":    {cfg.Server.ReadTimeout, 30 * time.Second},
+			"write_timeout":   {cfg.Server.WriteTimeout, 5 * time.Minute},
+			"idle_timeout":    {cfg.Server.IdleTimeout, 90 * time.Minute},
+			"request_timeout": {cfg.Server.RequestTimeout, 0},
+			"tools/call":      {cfg.Server.MethodTimeouts["tools/call"], 250 * time.Millisecond},
+		}
+		for name, values := range expected {
+			if values[0] != values[1] {
+				t.Errorf("expected %s to be %s, got %s", name, values[1], values[0])
+			}
+		}
+	})
+
+	t.Run("malformed durations are rejected with their path", func(t *testing.T) {
+		_, err := load(t, `
+server:
+  read_timeout: 30x
+  write_timeout: 30
+  method_timeouts:
+    tools/call: fast
+`)
+		var durationErr *DurationError
+		if !errors.As(err, &durationErr) {
+			t.Fatalf("expected a duration error, got %v", err)
+		}
+		if len(durationErr.Errors) != 3 {
+			t.Fatalf("expected three errors, got %v", durationErr.Errors)
+		}
+		for _, fragment := range []string{
+			`server.read_timeout (line 3): "30x" is not a duration`,
+			`server.write_timeout (line 4): 30 has no unit`,
+			`server.method_timeouts.tools/call (line 6): "fast" is not a duration`,
+		} {
+			if !strings.Contains(err.Error(), fragment) {
+				t.Errorf("expected error to contain %q, got:\n%v", fragment, err)
+			}
+		}
+	})
+
+	t.Run("shipped configuration files load", func(t *testing.T) {
+		for _, name := range []string{"development.yaml", "production.yaml"} {
+			cfg := GetDefaults()
+			path := filepath.Join("..", "..", "config", name)
+			if err := NewLoader(&noOpLogger{}).LoadFromFile(path, cfg, &LoadOptions{}); err != nil {
+				t.Errorf("expected %s to load, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("environment overrides accept duration strings", func(t *testing.T) {
+		type envConfig struct {
+			Timeout time.Duration `yaml:"timeout"`
+		}
+		path := filepath.Join(t.TempDir(), "env.yaml")
+		if err := os.WriteFile(path, []byte("timeout: 1s\n"), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		t.Setenv("DURATIONTEST_TIMEOUT", "45s")
+
+		var cfg envConfig
+		err := NewLoader(&noOpLogger{}).LoadFromFile(path, &cfg, &LoadOptions{EnvPrefix: "DURATIONTEST", AllowEnvOverrides: true})
+		if err != nil || cfg.Timeout != 45*time.Second {
+			t.Errorf("expected the override to set 45s, got %s (%v)", cfg.Timeout, err)
+		}
+	})
+}
@@ -4,41 +4,186 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/internal/router"
 	"github.com/osakka/mcpeg/internal/server"
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/mcp"
+	"github.com/osakka/mcpeg/pkg/plugins"
+	"github.com/osakka/mcpeg/pkg/rbac"
+	"github.com/osakka/mcpeg/pkg/validation"
 )
 
 // GatewayConfig represents the complete gateway configuration
 type GatewayConfig struct {
 	// Server configuration
-	Server ServerConfig `yaml:"server"`
+	Server ServerConfig `yaml:"server" description:"HTTP server, middleware and request handling settings"`
 
 	// Logging configuration
-	Logging LoggingConfig `yaml:"logging"`
+	Logging LoggingConfig `yaml:"logging" description:"Log level, format and outputs"`
 
 	// Metrics configuration
-	Metrics MetricsConfig `yaml:"metrics"`
+	Metrics MetricsConfig `yaml:"metrics" description:"Metrics collection and the Prometheus endpoint"`
 
 	// Service registry configuration
-	Registry RegistryConfig `yaml:"registry"`
+	Registry RegistryConfig `yaml:"registry" description:"Service discovery, load balancing and health checks"`
 
 	// Security configuration
-	Security SecurityConfig `yaml:"security"`
+	Security SecurityConfig `yaml:"security" description:"Authentication, authorization and capability policy"`
 
 	// Development mode settings
-	Development DevelopmentConfig `yaml:"development"`
+	Development DevelopmentConfig `yaml:"development" description:"Development-only features such as admin endpoints"`
+
+	// External plugin configuration
+	Plugins PluginsConfig `yaml:"plugins" description:"External MCP servers exposed as plugins"`
+}
+
+// PluginsConfig configures plugins loaded alongside the built-in ones
+type PluginsConfig struct {
+	// MCP servers launched as subprocesses speaking JSON-RPC over stdio
+	Stdio []plugins.StdioPluginConfig `yaml:"stdio"`
+
+	// Plugins whose initialization failure stops startup. Any other plugin
+	// that fails is logged, excluded from routing and can be reloaded later.
+	Required []string `yaml:"required"`
+
+	// Tool name patterns routed to a plugin for tools/call, checked in order.
+	// Tools not matched are found in the tool lists plugins report.
+	ToolRoutes []router.PluginToolRoute `yaml:"tool_routes"`
+
+	// Limits on concurrent tool calls per plugin; calls over a limit wait for
+	// a free slot until their timeout
+	Concurrency mcp.PluginConcurrencyConfig `yaml:"concurrency"`
 }
 
 // ServerConfig configures the HTTP server
 type ServerConfig struct {
 	// Basic server settings
-	Address string `yaml:"address"`
-	Port    int    `yaml:"port"`
+	Address string `yaml:"address" validate:"required" description:"Interface to listen on"`
+	Port    int    `yaml:"port" validate:"required" description:"TCP port to listen on (1-65535)"`
 
 	// Timeout settings
 	ReadTimeout     time.Duration `yaml:"read_timeout"`
 	WriteTimeout    time.Duration `yaml:"write_timeout"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	RequestTimeout  time.Duration `yaml:"request_timeout"` // Per-request handler deadline, 0 disables
+
+	// Backend timeouts keyed by MCP method, overriding the router default
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts"`
+
+	// Largest backend response body the gateway reads, in bytes; 0 uses the
+	// 50MB default
+	MaxResponseSize int64 `yaml:"max_response_size"`
+
+	// Client deadline header (an RFC 3339 time or a duration), which can only
+	// shorten the gateway's timeouts
+	RequestDeadline router.RequestDeadlineConfig `yaml:"request_deadline"`
+
+	// Stale read responses when every backend is unhealthy
+	DegradedMode router.DegradedModeConfig `yaml:"degraded_mode"`
+
+	// Tool calls repeating an idempotency key within this window return the
+	// first result; a negative value disables deduplication
+	IdempotencyWindow time.Duration `yaml:"idempotency_window"`
+
+	// Resource subscriptions of a session (X-Session-ID) are kept this long
+	// after its last /mcp/events stream closes, and restored if it
+	// reconnects in time; a negative value keeps them until unsubscribed
+	SubscriptionGracePeriod time.Duration `yaml:"subscription_grace_period"`
+
+	// Concurrent identical read requests (tools/list, resources/read, ...)
+	// share a single upstream call
+	RequestCoalescing bool `yaml:"request_coalescing"`
+
+	// Array fields of MCP results that are null or missing (e.g. tools,
+	// resources, content) are written as [] for strict clients
+	NormalizeEmptyArrays bool `yaml:"normalize_empty_arrays"`
+
+	// Record requests that exhaust their retries to a file or endpoint
+	DeadLetter router.DeadLetterConfig `yaml:"dead_letter"`
+
+	// Record every tools/call, with hashes of the redacted arguments and of
+	// the result, to a file or endpoint
+	ToolAudit router.ToolAuditConfig `yaml:"tool_audit"`
+
+	// Recent requests kept in memory and served at GET /admin/debug/requests
+	RequestHistory router.RequestHistoryConfig `yaml:"request_history"`
+
+	// Inbound headers forwarded to backends (e.g. Accept-Language) and static
+	// headers injected on every backend request; hop-by-hop and credential
+	// headers are never forwarded. Limits bound the count and size of
+	// forwarded headers, dropping the excess or rejecting the request.
+	BackendHeaders router.BackendHeadersConfig `yaml:"backend_headers"`
+
+	// Rules renaming, setting, defaulting or removing JSON fields in the
+	// params sent to matching services and the results they return
+	Transformations []router.TransformRule `yaml:"transformations"`
+
+	// Calls per window allowed for tools matching each entry, shared by all
+	// clients or counted per client; enforced on tools/call on top of the
+	// HTTP rate limit
+	ToolRateLimits []router.ToolRateLimit `yaml:"tool_rate_limits"`
+
+	// Follow backend 307/308 redirects, which keep the POST body; by default
+	// any redirect fails the call and its Location is logged
+	FollowBackendRedirects bool `yaml:"follow_backend_redirects"`
+
+	// MCP requests are rejected with 503 and Retry-After while enabled; health,
+	// metrics and admin endpoints keep serving and readiness reports not_ready.
+	// Also toggled at runtime via PUT /admin/maintenance.
+	Maintenance server.MaintenanceConfig `yaml:"maintenance"`
+
+	// Page size applied to paginated admin lists such as GET /admin/services
+	// when the client sends no limit, and the largest limit honoured
+	Pagination server.PaginationConfig `yaml:"pagination"`
+
+	// Whether MCP logging/setLevel adjusts the gateway logger (local), is
+	// forwarded to logging_provider services (forward), or both
+	LogLevelMode string `yaml:"log_level_mode"`
+
+	// JSON-RPC error data returned to clients: full includes the error text,
+	// sanitized replaces it with a reference ID logged with the full error
+	ErrorDetail string `yaml:"error_detail"`
+
+	// Methods without a prefix (e.g. "ping") are sent to services registered
+	// as generic_adapter; by default they get a method not found error
+	GenericAdapterFallback bool `yaml:"generic_adapter_fallback"`
+
+	// Accept requests that omit the jsonrpc member by defaulting it to "2.0";
+	// any other declared version is still rejected. Strict by default.
+	LenientJSONRPCVersion bool `yaml:"lenient_jsonrpc_version"`
+
+	// Slow-loris protection
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes"`
+
+	// Connection flood protection; 0 disables the limit
+	MaxConcurrentConnections int `yaml:"max_concurrent_connections"`
+
+	// Queue requests beyond a concurrency limit instead of rejecting them
+	RequestQueue server.RequestQueueConfig `yaml:"request_queue"`
+
+	// HTTP/2 for multiplexing clients, h2c on plaintext listeners when
+	// cleartext is set
+	HTTP2 server.HTTP2Config `yaml:"http2"`
+
+	// HTTP/1.1 keep-alives and TCP keepalive probes
+	KeepAlive server.KeepAliveConfig `yaml:"keepalive"`
+
+	// Unix domain socket for local proxies, served in plain HTTP next to the
+	// TCP listener, or alone with disable_tcp; removed on shutdown
+	UnixSocket server.UnixSocketConfig `yaml:"unix_socket"`
+
+	// Bind the TCP port with SO_REUSEPORT so -restart starts the new daemon
+	// before stopping the old one and the port is never unbound; not
+	// supported on Windows
+	ReusePort bool `yaml:"reuse_port"`
+
+	// Lifecycle events (starting, config_loaded, plugins_ready, serving,
+	// draining, stopped) appended to a JSON lines file or POSTed to an
+	// endpoint; they are logged with a lifecycle_event field either way
+	Lifecycle server.LifecycleConfig `yaml:"lifecycle"`
 
 	// TLS configuration
 	TLS TLSConfig `yaml:"tls"`
@@ -59,6 +204,9 @@ type TLSConfig struct {
 	CertFile string `yaml:"cert_file"`
 	KeyFile  string `yaml:"key_file"`
 
+	// CA bundle verifying optional client certificates presented for mTLS
+	ClientCAFile string `yaml:"client_ca_file"`
+
 	// Advanced TLS settings
 	MinVersion string   `yaml:"min_version"` // "1.2" or "1.3"
 	Ciphers    []string `yaml:"ciphers"`
@@ -85,13 +233,21 @@ type MiddlewareConfig struct {
 
 	// Request logging settings
 	RequestLogging RequestLoggingConfig `yaml:"request_logging"`
+
+	// Request ID settings
+	RequestID RequestIDConfig `yaml:"request_id"`
+
+	// Detailed span logging for a sample of requests; requests carrying a
+	// traceparent header follow the caller's sampled flag
+	TraceSampling server.TraceSamplingConfig `yaml:"trace_sampling"`
 }
 
 // CompressionConfig configures response compression
 type CompressionConfig struct {
-	Enabled bool     `yaml:"enabled"`
-	Level   int      `yaml:"level"` // 1-9, higher = better compression
-	Types   []string `yaml:"types"` // MIME types to compress
+	Enabled    bool     `yaml:"enabled"`
+	Level      int      `yaml:"level"`      // 1-9 (up to 11 for br), higher = better compression, 0 uses defaults
+	Types      []string `yaml:"types"`      // MIME types to compress
+	Algorithms []string `yaml:"algorithms"` // Preference order among gzip, deflate and br
 }
 
 // RateLimitConfig configures request rate limiting
@@ -100,6 +256,22 @@ type RateLimitConfig struct {
 	RPS        int           `yaml:"rps"`         // Requests per second
 	Burst      int           `yaml:"burst"`       // Burst capacity
 	WindowSize time.Duration `yaml:"window_size"` // Time window for rate limiting
+
+	// ClientOverrides maps a client ID, CIDR block or prefix* to its own RPS;
+	// -1 exempts the client from rate limiting
+	ClientOverrides map[string]int `yaml:"client_overrides"`
+
+	// Subnet prefix lengths clients are bucketed by (e.g. 24 and 64); 0 keys by full address
+	ClientIPv4Prefix int `yaml:"client_ipv4_prefix"`
+	ClientIPv6Prefix int `yaml:"client_ipv6_prefix"`
+
+	// TrustedProxies lists CIDRs whose X-Forwarded-For and X-Real-IP headers are
+	// honoured; when empty, forwarding headers are trusted from any peer
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// ClientTTL is how long a client may stay idle before its rate limit
+	// state is evicted
+	ClientTTL time.Duration `yaml:"client_ttl"`
 }
 
 // RequestLoggingConfig configures request/response logging
@@ -108,6 +280,17 @@ type RequestLoggingConfig struct {
 	IncludeBody    bool     `yaml:"include_body"`
 	ExcludePaths   []string `yaml:"exclude_paths"`
 	IncludeHeaders []string `yaml:"include_headers"`
+
+	// JSON-RPC body logging at trace level, used when include_body is set
+	BodyPaths   []string `yaml:"body_paths"`    // URL paths to log bodies for, empty logs all
+	RedactPaths []string `yaml:"redact_paths"`  // JSON paths replaced by "***", e.g. arguments.api_key
+	MaxBodySize int      `yaml:"max_body_size"` // Logged bodies are truncated to this many bytes
+}
+
+// RequestIDConfig configures request ID assignment and propagation
+type RequestIDConfig struct {
+	Header string `yaml:"header"` // Header read from clients and echoed in responses
+	Format string `yaml:"format"` // uuid, timestamp
 }
 
 // HealthCheckConfig configures health check endpoints
@@ -115,12 +298,41 @@ type HealthCheckConfig struct {
 	Enabled  bool   `yaml:"enabled"`
 	Endpoint string `yaml:"endpoint"`
 	Detailed bool   `yaml:"detailed"` // Include detailed health information
+
+	// Readiness gate settings
+	Readiness ReadinessConfig `yaml:"readiness"`
+
+	// One-off check of plugins and backends before the listener opens
+	SelfTest server.SelfTestConfig `yaml:"self_test"`
+
+	// Plugin health monitoring
+	Plugins PluginHealthConfig `yaml:"plugins"`
+}
+
+// PluginHealthConfig configures taking unhealthy plugins out of rotation
+type PluginHealthConfig struct {
+	AutoDisableThreshold int           `yaml:"auto_disable_threshold"` // Consecutive failed checks before disabling, negative turns auto-disable off
+	CheckInterval        time.Duration `yaml:"check_interval"`         // How often plugins are health checked
+}
+
+// ReadinessConfig configures when the gateway reports ready
+type ReadinessConfig struct {
+	CriticalPlugins  []string      `yaml:"critical_plugins"`   // Plugins that must be healthy before ready
+	WaitBeforeListen bool          `yaml:"wait_before_listen"` // Delay opening the listener until ready
+	Timeout          time.Duration `yaml:"timeout"`            // Maximum listener delay, 0 waits indefinitely
+
+	// Service types that must each have a minimum of healthy instances;
+	// when unset, any one healthy service is enough
+	RequiredServices []server.ReadinessRequiredService `yaml:"required_services"`
+
+	// Only ready while a backend of each service type accepts TCP connections
+	BackendCheck server.ReadinessBackendCheckConfig `yaml:"backend_check"`
 }
 
 // LoggingConfig configures application logging
 type LoggingConfig struct {
 	Level  string `yaml:"level"`  // trace, debug, info, warn, error
-	Format string `yaml:"format"` // json, text
+	Format string `yaml:"format"` // json, logfmt or console (text)
 
 	// Output configuration
 	Output OutputConfig `yaml:"output"`
@@ -200,6 +412,19 @@ type RegistryConfig struct {
 
 	// Health checking settings
 	HealthChecks HealthChecksConfig `yaml:"health_checks"`
+
+	// Backends registering themselves via POST /register with a client
+	// certificate or service token instead of the admin key
+	SelfRegistration server.SelfRegistrationConfig `yaml:"self_registration"`
+
+	// Registration retries the required health check with exponential backoff
+	// over this window, for backends deployed together with the gateway
+	RegistrationRetry registry.RegistrationRetryConfig `yaml:"registration_retry"`
+
+	// Directories the tls_ca_file, tls_cert_file and tls_key_file named in
+	// registration metadata must be in; with none, such registrations are
+	// rejected
+	UpstreamTLSDirs []string `yaml:"upstream_tls_dirs"`
 }
 
 // DiscoveryConfig configures service discovery mechanisms
@@ -271,7 +496,17 @@ type FileDiscoveryConfig struct {
 
 // LoadBalancerConfig configures load balancing behavior
 type LoadBalancerConfig struct {
-	Strategy string `yaml:"strategy"` // round_robin, least_connections, weighted, hash, random
+	Strategy string `yaml:"strategy"` // round_robin, least_connections, weighted, hash, random, load_aware
+
+	// Request header holding the session key the hash strategy pins to a backend
+	SessionHeader string `yaml:"session_header"`
+
+	// Region and zone whose backends requests prefer; backends declare theirs
+	// in region and zone registration metadata
+	RegionAffinity router.RegionAffinityConfig `yaml:"region_affinity"`
+
+	// Cap on retries across all backends, as a ratio of routed requests
+	RetryBudget router.RetryBudgetConfig `yaml:"retry_budget"`
 
 	// Health-based routing
 	HealthAware bool `yaml:"health_aware"`
@@ -286,6 +521,9 @@ type CircuitBreakerConfig struct {
 	FailureThreshold    int           `yaml:"failure_threshold"`
 	RecoveryTimeout     time.Duration `yaml:"recovery_timeout"`
 	HalfOpenMaxRequests int           `yaml:"half_open_max_requests"`
+
+	// Webhook and SSE notification of state transitions
+	Alerts server.CircuitBreakerAlertConfig `yaml:"alerts"`
 }
 
 // HealthChecksConfig configures service health checking
@@ -323,6 +561,13 @@ type SecurityConfig struct {
 
 	// Request validation
 	Validation ValidationConfig `yaml:"validation"`
+
+	// Gateway-wide tool and resource allowlist/denylist
+	CapabilityPolicy router.CapabilityPolicyConfig `yaml:"capability_policy"`
+
+	// Permission (read, write, execute, admin or none) required per MCP
+	// method and per tool; methods without a default require admin
+	MethodPolicy rbac.MethodPolicy `yaml:"method_policy"`
 }
 
 // APIKeyConfig configures API key authentication
@@ -349,6 +594,14 @@ type ValidationConfig struct {
 	Enabled      bool `yaml:"enabled"`
 	StrictMode   bool `yaml:"strict_mode"`
 	ValidateBody bool `yaml:"validate_body"`
+
+	// Cached validation results and compiled struct schemas; 0 uses the defaults
+	CacheSize       int `yaml:"cache_size"`
+	SchemaCacheSize int `yaml:"schema_cache_size"`
+
+	// Reuse the result for identical values validated within MemoizeTTL
+	MemoizeResults bool          `yaml:"memoize_results"`
+	MemoizeTTL     time.Duration `yaml:"memoize_ttl"`
 }
 
 // DevelopmentConfig configures development-specific settings
@@ -360,6 +613,10 @@ type DevelopmentConfig struct {
 	DebugMode    bool `yaml:"debug_mode"`
 	ProfilerPort int  `yaml:"profiler_port"`
 
+	// Expose net/http/pprof under /admin/debug/pprof/; off by default as
+	// profiles reveal internals and CPU profiling is costly
+	Profiling bool `yaml:"profiling"`
+
 	// Admin endpoints
 	AdminEndpoints AdminEndpointsConfig `yaml:"admin_endpoints"`
 }
@@ -369,6 +626,10 @@ type AdminEndpointsConfig struct {
 	Enabled bool   `yaml:"enabled"`
 	Prefix  string `yaml:"prefix"`
 
+	// Response format when the Accept header allows any: json (default),
+	// yaml or msgpack
+	ResponseFormat string `yaml:"response_format"`
+
 	// Available admin functions
 	ConfigReload     bool `yaml:"config_reload"`
 	ServiceDiscovery bool `yaml:"service_discovery"`
@@ -389,6 +650,189 @@ func (c *GatewayConfig) Validate() error {
 		if c.Server.TLS.KeyFile == "" {
 			return fmt.Errorf("TLS key file is required when TLS is enabled")
 		}
+	} else if c.Server.TLS.ClientCAFile != "" {
+		return fmt.Errorf("TLS client CA file requires TLS to be enabled")
+	}
+
+	if c.Server.ReadHeaderTimeout < 0 {
+		return fmt.Errorf("server read header timeout must not be negative, got %s", c.Server.ReadHeaderTimeout)
+	}
+
+	if c.Server.MaxHeaderBytes < 0 {
+		return fmt.Errorf("server max header bytes must not be negative, got %d", c.Server.MaxHeaderBytes)
+	}
+
+	if c.Server.MaxConcurrentConnections < 0 {
+		return fmt.Errorf("server max concurrent connections must not be negative, got %d", c.Server.MaxConcurrentConnections)
+	}
+
+	if err := c.Registry.SelfRegistration.Validate(); err != nil {
+		return fmt.Errorf("invalid self-registration config: %w", err)
+	}
+
+	if err := c.Registry.RegistrationRetry.Validate(); err != nil {
+		return fmt.Errorf("invalid registration retry config: %w", err)
+	}
+
+	if err := registry.ValidateUpstreamTLSDirs(c.Registry.UpstreamTLSDirs); err != nil {
+		return fmt.Errorf("invalid registry config: %w", err)
+	}
+
+	if err := c.Server.DeadLetter.Validate(); err != nil {
+		return fmt.Errorf("invalid dead letter config: %w", err)
+	}
+
+	if err := c.Server.ToolAudit.Validate(); err != nil {
+		return fmt.Errorf("invalid tool audit config: %w", err)
+	}
+
+	if err := c.Server.RequestHistory.Validate(); err != nil {
+		return fmt.Errorf("invalid request history: %w", err)
+	}
+
+	if err := router.ValidateLogLevelMode(c.Server.LogLevelMode); err != nil {
+		return fmt.Errorf("invalid server log level mode: %w", err)
+	}
+
+	if err := router.ValidateErrorDetail(c.Server.ErrorDetail); err != nil {
+		return fmt.Errorf("invalid server error detail: %w", err)
+	}
+
+	if err := c.Server.RequestQueue.Validate(); err != nil {
+		return fmt.Errorf("invalid request queue: %w", err)
+	}
+
+	if err := c.Server.HTTP2.Validate(); err != nil {
+		return fmt.Errorf("invalid http2 config: %w", err)
+	}
+
+	if err := c.Server.UnixSocket.Validate(); err != nil {
+		return fmt.Errorf("invalid unix socket config: %w", err)
+	}
+
+	if err := c.Server.Lifecycle.Validate(); err != nil {
+		return fmt.Errorf("invalid lifecycle config: %w", err)
+	}
+
+	if err := c.Server.Middleware.TraceSampling.Validate(); err != nil {
+		return fmt.Errorf("invalid trace sampling: %w", err)
+	}
+
+	if c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("server request timeout must not be negative, got %s", c.Server.RequestTimeout)
+	}
+
+	if c.Server.DegradedMode.MaxStaleness < 0 {
+		return fmt.Errorf("degraded mode max staleness must not be negative, got %s", c.Server.DegradedMode.MaxStaleness)
+	}
+
+	for method, timeout := range c.Server.MethodTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout for method %s must be positive, got %s", method, timeout)
+		}
+	}
+	validationCfg := c.Security.Validation
+	if validationCfg.CacheSize < 0 || validationCfg.SchemaCacheSize < 0 || validationCfg.MemoizeTTL < 0 {
+		return fmt.Errorf("validation cache sizes and memoize TTL must not be negative")
+	}
+	if c.Server.MaxResponseSize < 0 {
+		return fmt.Errorf("max response size must not be negative, got %d", c.Server.MaxResponseSize)
+	}
+	if c.Server.RequestDeadline.MaxDuration < 0 {
+		return fmt.Errorf("request deadline max duration must not be negative, got %s", c.Server.RequestDeadline.MaxDuration)
+	}
+	if c.Server.Maintenance.RetryAfter < 0 {
+		return fmt.Errorf("maintenance retry_after must not be negative, got %s", c.Server.Maintenance.RetryAfter)
+	}
+	if err := c.Server.Pagination.Validate(); err != nil {
+		return fmt.Errorf("invalid pagination configuration: %w", err)
+	}
+
+	for client, rps := range c.Server.Middleware.RateLimit.ClientOverrides {
+		if rps <= 0 && rps != -1 {
+			return fmt.Errorf("rate limit override for %s must be a positive RPS or -1 for unlimited, got %d", client, rps)
+		}
+	}
+
+	rateLimit := c.Server.Middleware.RateLimit
+	if err := server.ValidateClientIdentification(rateLimit.ClientIPv4Prefix, rateLimit.ClientIPv6Prefix, rateLimit.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid rate limit client identification: %w", err)
+	}
+
+	if err := c.Security.CapabilityPolicy.Validate(); err != nil {
+		return fmt.Errorf("invalid capability policy: %w", err)
+	}
+
+	if err := c.Security.MethodPolicy.Validate(); err != nil {
+		return fmt.Errorf("invalid method policy: %w", err)
+	}
+
+	if c.Server.HealthCheck.Readiness.Timeout < 0 {
+		return fmt.Errorf("readiness timeout must not be negative, got %s", c.Server.HealthCheck.Readiness.Timeout)
+	}
+	if err := server.ValidateReadinessRequiredServices(c.Server.HealthCheck.Readiness.RequiredServices); err != nil {
+		return fmt.Errorf("invalid readiness required services: %w", err)
+	}
+	if err := c.Server.HealthCheck.Readiness.BackendCheck.Validate(); err != nil {
+		return fmt.Errorf("invalid readiness backend check: %w", err)
+	}
+
+	if c.Server.HealthCheck.Plugins.CheckInterval < 0 {
+		return fmt.Errorf("plugin health check interval must not be negative, got %s", c.Server.HealthCheck.Plugins.CheckInterval)
+	}
+
+	stdioNames := make(map[string]bool)
+	for _, plugin := range c.Plugins.Stdio {
+		if err := plugin.Validate(); err != nil {
+			return fmt.Errorf("invalid plugin configuration: %w", err)
+		}
+		if stdioNames[plugin.Name] {
+			return fmt.Errorf("duplicate stdio plugin name: %s", plugin.Name)
+		}
+		stdioNames[plugin.Name] = true
+	}
+	for _, name := range c.Plugins.Required {
+		if name == "" {
+			return fmt.Errorf("invalid plugin configuration: required plugin name must not be empty")
+		}
+	}
+	if err := c.Server.BackendHeaders.Limits.Validate(); err != nil {
+		return fmt.Errorf("invalid backend header limits: %w", err)
+	}
+	if err := router.ValidateTransformRules(c.Server.Transformations); err != nil {
+		return fmt.Errorf("invalid transformations: %w", err)
+	}
+	if err := router.ValidateToolRateLimits(c.Server.ToolRateLimits); err != nil {
+		return fmt.Errorf("invalid tool rate limits: %w", err)
+	}
+	if err := router.ValidatePluginToolRoutes(c.Plugins.ToolRoutes); err != nil {
+		return fmt.Errorf("invalid plugin tool routes: %w", err)
+	}
+	if err := server.ValidateResponseFormat(c.Development.AdminEndpoints.ResponseFormat); err != nil {
+		return fmt.Errorf("invalid admin endpoints configuration: %w", err)
+	}
+	if err := logging.ValidateFormat(c.Logging.Format); err != nil {
+		return fmt.Errorf("invalid logging configuration: %w", err)
+	}
+	if err := c.Registry.LoadBalancer.RetryBudget.Validate(); err != nil {
+		return fmt.Errorf("invalid retry budget: %w", err)
+	}
+	if err := c.Plugins.Concurrency.Validate(); err != nil {
+		return fmt.Errorf("invalid plugin concurrency: %w", err)
+	}
+
+	if err := server.ValidateCompressionSettings(c.Server.Middleware.Compression.Level, c.Server.Middleware.Compression.Algorithms); err != nil {
+		return fmt.Errorf("invalid compression settings: %w", err)
+	}
+
+	if c.Server.Middleware.RequestLogging.MaxBodySize < 0 {
+		return fmt.Errorf("request logging max body size must not be negative, got %d", c.Server.Middleware.RequestLogging.MaxBodySize)
+	}
+
+	switch c.Server.Middleware.RequestID.Format {
+	case "", "uuid", "timestamp":
+	default:
+		return fmt.Errorf("invalid request ID format: %s, must be one of [uuid timestamp]", c.Server.Middleware.RequestID.Format)
 	}
 
 	// Metrics validation
@@ -398,6 +842,10 @@ func (c *GatewayConfig) Validate() error {
 		}
 	}
 
+	if err := c.Registry.LoadBalancer.CircuitBreaker.Alerts.Validate(); err != nil {
+		return fmt.Errorf("invalid circuit breaker alerts: %w", err)
+	}
+
 	// Load balancer strategy validation
 	validStrategies := []string{"round_robin", "least_connections", "weighted", "hash", "random"}
 	strategy := c.Registry.LoadBalancer.Strategy
@@ -422,38 +870,157 @@ func (c *GatewayConfig) Validate() error {
 // ToServerConfig converts GatewayConfig to server.ServerConfig
 func (c *GatewayConfig) ToServerConfig() server.ServerConfig {
 	return server.ServerConfig{
-		Address:               c.Server.Address,
-		Port:                  c.Server.Port,
-		ReadTimeout:           c.Server.ReadTimeout,
-		WriteTimeout:          c.Server.WriteTimeout,
-		IdleTimeout:           c.Server.IdleTimeout,
-		ShutdownTimeout:       c.Server.ShutdownTimeout,
-		TLSEnabled:            c.Server.TLS.Enabled,
-		TLSCertFile:           c.Server.TLS.CertFile,
-		TLSKeyFile:            c.Server.TLS.KeyFile,
-		CORSEnabled:           c.Server.CORS.Enabled,
-		CORSAllowOrigins:      c.Server.CORS.AllowOrigins,
-		CORSAllowMethods:      c.Server.CORS.AllowMethods,
-		CORSAllowHeaders:      c.Server.CORS.AllowHeaders,
-		EnableCompression:     c.Server.Middleware.Compression.Enabled,
-		EnableRateLimit:       c.Server.Middleware.RateLimit.Enabled,
-		RateLimitRPS:          c.Server.Middleware.RateLimit.RPS,
-		EnableHealthEndpoints: c.Server.HealthCheck.Enabled,
-		EnableMetricsEndpoint: c.Metrics.Enabled,
-		EnableAdminEndpoints:  c.Development.AdminEndpoints.Enabled,
+		Address:                    c.Server.Address,
+		Port:                       c.Server.Port,
+		ReadTimeout:                c.Server.ReadTimeout,
+		WriteTimeout:               c.Server.WriteTimeout,
+		IdleTimeout:                c.Server.IdleTimeout,
+		ShutdownTimeout:            c.Server.ShutdownTimeout,
+		RequestTimeout:             c.Server.RequestTimeout,
+		MethodTimeouts:             c.Server.MethodTimeouts,
+		MaxResponseSize:            c.Server.MaxResponseSize,
+		RequestDeadline:            c.Server.RequestDeadline,
+		DegradedMode:               c.Server.DegradedMode,
+		IdempotencyWindow:          c.Server.IdempotencyWindow,
+		SubscriptionGracePeriod:    c.Server.SubscriptionGracePeriod,
+		DisableRequestCoalescing:   !c.Server.RequestCoalescing,
+		DisableArrayNormalization:  !c.Server.NormalizeEmptyArrays,
+		DeadLetter:                 c.Server.DeadLetter,
+		ToolAudit:                  c.Server.ToolAudit,
+		RequestHistory:             c.Server.RequestHistory,
+		BackendHeaders:             c.Server.BackendHeaders,
+		Transformations:            c.Server.Transformations,
+		ToolRateLimits:             c.Server.ToolRateLimits,
+		FollowBackendRedirects:     c.Server.FollowBackendRedirects,
+		Maintenance:                c.Server.Maintenance,
+		Pagination:                 c.Server.Pagination,
+		LogLevelMode:               c.Server.LogLevelMode,
+		ErrorDetail:                c.Server.ErrorDetail,
+		GenericAdapterFallback:     c.Server.GenericAdapterFallback,
+		LenientJSONRPCVersion:      c.Server.LenientJSONRPCVersion,
+		ReadHeaderTimeout:          c.Server.ReadHeaderTimeout,
+		MaxHeaderBytes:             c.Server.MaxHeaderBytes,
+		MaxConcurrentConnections:   c.Server.MaxConcurrentConnections,
+		RequestQueue:               c.Server.RequestQueue,
+		HTTP2:                      c.Server.HTTP2,
+		KeepAlive:                  c.Server.KeepAlive,
+		UnixSocket:                 c.Server.UnixSocket,
+		ReusePort:                  c.Server.ReusePort,
+		Lifecycle:                  c.Server.Lifecycle,
+		TLSEnabled:                 c.Server.TLS.Enabled,
+		TLSCertFile:                c.Server.TLS.CertFile,
+		TLSKeyFile:                 c.Server.TLS.KeyFile,
+		TLSClientCAFile:            c.Server.TLS.ClientCAFile,
+		SelfRegistration:           c.Registry.SelfRegistration,
+		RegistrationRetry:          c.Registry.RegistrationRetry,
+		UpstreamTLSDirs:            c.Registry.UpstreamTLSDirs,
+		CORSEnabled:                c.Server.CORS.Enabled,
+		CORSAllowOrigins:           c.Server.CORS.AllowOrigins,
+		CORSAllowMethods:           c.Server.CORS.AllowMethods,
+		CORSAllowHeaders:           c.Server.CORS.AllowHeaders,
+		EnableCompression:          c.Server.Middleware.Compression.Enabled,
+		CompressionLevel:           c.Server.Middleware.Compression.Level,
+		CompressionAlgorithms:      c.Server.Middleware.Compression.Algorithms,
+		EnableRateLimit:            c.Server.Middleware.RateLimit.Enabled,
+		RateLimitRPS:               c.Server.Middleware.RateLimit.RPS,
+		RateLimitOverrides:         c.Server.Middleware.RateLimit.ClientOverrides,
+		RateLimitIPv4Prefix:        c.Server.Middleware.RateLimit.ClientIPv4Prefix,
+		RateLimitIPv6Prefix:        c.Server.Middleware.RateLimit.ClientIPv6Prefix,
+		TrustedProxies:             c.Server.Middleware.RateLimit.TrustedProxies,
+		RateLimitClientTTL:         c.Server.Middleware.RateLimit.ClientTTL,
+		EnableHealthEndpoints:      c.Server.HealthCheck.Enabled,
+		EnableMetricsEndpoint:      c.Metrics.Enabled,
+		EnableAdminEndpoints:       c.Development.AdminEndpoints.Enabled,
+		AdminResponseFormat:        c.Development.AdminEndpoints.ResponseFormat,
+		EnableProfiling:            c.Development.Profiling,
+		EnableMetricsReset:         c.Development.Enabled,
+		ReadinessCriticalPlugins:   c.Server.HealthCheck.Readiness.CriticalPlugins,
+		WaitForReadiness:           c.Server.HealthCheck.Readiness.WaitBeforeListen,
+		ReadinessTimeout:           c.Server.HealthCheck.Readiness.Timeout,
+		ReadinessRequiredServices:  c.Server.HealthCheck.Readiness.RequiredServices,
+		ReadinessBackendCheck:      c.Server.HealthCheck.Readiness.BackendCheck,
+		SelfTest:                   c.Server.HealthCheck.SelfTest,
+		PluginAutoDisableThreshold: c.Server.HealthCheck.Plugins.AutoDisableThreshold,
+		PluginHealthCheckInterval:  c.Server.HealthCheck.Plugins.CheckInterval,
+		StdioPlugins:               c.Plugins.Stdio,
+		RequiredPlugins:            c.Plugins.Required,
+		PluginToolRoutes:           c.Plugins.ToolRoutes,
+		PluginConcurrency:          c.Plugins.Concurrency,
+		RequestIDHeader:            c.Server.Middleware.RequestID.Header,
+		RequestIDFormat:            c.Server.Middleware.RequestID.Format,
+		TraceSampling:              c.Server.Middleware.TraceSampling,
+		LoadBalancerStrategy:       c.Registry.LoadBalancer.Strategy,
+		SessionHeader:              c.Registry.LoadBalancer.SessionHeader,
+		RegionAffinity:             c.Registry.LoadBalancer.RegionAffinity,
+		RetryBudget:                c.Registry.LoadBalancer.RetryBudget,
+		CircuitBreakerAlerts:       c.Registry.LoadBalancer.CircuitBreaker.Alerts,
+		LogRequestBodies:           c.Server.Middleware.RequestLogging.Enabled && c.Server.Middleware.RequestLogging.IncludeBody,
+		BodyLogPaths:               c.Server.Middleware.RequestLogging.BodyPaths,
+		BodyLogRedactPaths:         c.Server.Middleware.RequestLogging.RedactPaths,
+		BodyLogMaxSize:             c.Server.Middleware.RequestLogging.MaxBodySize,
+		CapabilityPolicy:           c.Security.CapabilityPolicy,
+		MethodPolicy:               c.Security.MethodPolicy,
+		ExposePanicTraces:          c.Development.Enabled && c.Development.DebugMode,
 	}
 }
 
+// ToValidationConfig converts the security validation settings to the validator configuration
+func (c *GatewayConfig) ToValidationConfig() validation.ValidationConfig {
+	config := validation.DefaultValidationConfig()
+	config.StrictMode = c.Security.Validation.StrictMode
+	config.MaxCacheSize = c.Security.Validation.CacheSize
+	config.MaxSchemaCacheSize = c.Security.Validation.SchemaCacheSize
+	config.MemoizeResults = c.Security.Validation.MemoizeResults
+	config.MemoizeTTL = c.Security.Validation.MemoizeTTL
+	return config
+}
+
 // GetDefaults returns a configuration with sensible defaults
 func GetDefaults() *GatewayConfig {
 	return &GatewayConfig{
 		Server: ServerConfig{
-			Address:         "0.0.0.0",
-			Port:            8080,
-			ReadTimeout:     30 * time.Second,
-			WriteTimeout:    30 * time.Second,
-			IdleTimeout:     60 * time.Second,
-			ShutdownTimeout: 30 * time.Second,
+			Address:                  "0.0.0.0",
+			Port:                     8080,
+			ReadTimeout:              30 * time.Second,
+			WriteTimeout:             30 * time.Second,
+			IdleTimeout:              60 * time.Second,
+			ShutdownTimeout:          30 * time.Second,
+			RequestTimeout:           25 * time.Second,
+			MaxResponseSize:          50 << 20,
+			ReadHeaderTimeout:        10 * time.Second,
+			MaxHeaderBytes:           1 << 20,
+			MaxConcurrentConnections: 10000,
+			IdempotencyWindow:        5 * time.Minute,
+			SubscriptionGracePeriod:  30 * time.Second,
+			RequestCoalescing:        true,
+			NormalizeEmptyArrays:     true,
+			LogLevelMode:             router.LogLevelModeBoth,
+			ErrorDetail:              router.ErrorDetailSanitized,
+			DeadLetter: router.DeadLetterConfig{
+				Enabled: false,
+				Timeout: 5 * time.Second,
+			},
+			RequestHistory: router.RequestHistoryConfig{
+				Enabled:       false,
+				Size:          100,
+				MaxParamsSize: 1024,
+			},
+			RequestQueue: server.RequestQueueConfig{
+				Enabled:       false,
+				MaxConcurrent: 1000,
+				MaxDepth:      500,
+				MaxWait:       2 * time.Second,
+			},
+			HTTP2: server.HTTP2Config{
+				Enabled:              true,
+				Cleartext:            false,
+				MaxConcurrentStreams: 250,
+			},
+			DegradedMode: router.DegradedModeConfig{
+				Enabled:      false,
+				MaxStaleness: 5 * time.Minute,
+				MaxEntries:   1000,
+			},
 			TLS: TLSConfig{
 				Enabled:    false,
 				MinVersion: "1.2",
@@ -466,26 +1033,50 @@ func GetDefaults() *GatewayConfig {
 			},
 			Middleware: MiddlewareConfig{
 				Compression: CompressionConfig{
-					Enabled: true,
-					Level:   6,
-					Types:   []string{"application/json", "text/html", "text/css", "application/javascript"},
+					Enabled:    true,
+					Level:      6,
+					Types:      []string{"application/json", "text/html", "text/css", "application/javascript"},
+					Algorithms: []string{"br", "gzip", "deflate"},
 				},
 				RateLimit: RateLimitConfig{
 					Enabled:    false,
 					RPS:        1000,
 					Burst:      2000,
 					WindowSize: time.Minute,
+					ClientTTL:  10 * time.Minute,
 				},
 				RequestLogging: RequestLoggingConfig{
 					Enabled:      true,
 					IncludeBody:  false,
 					ExcludePaths: []string{"/health", "/metrics"},
+					MaxBodySize:  4096,
+				},
+				RequestID: RequestIDConfig{
+					Header: "X-Request-ID",
+					Format: "uuid",
+				},
+				TraceSampling: server.TraceSamplingConfig{
+					Rate:        0.01,
+					ForceHeader: "X-MCPEG-Trace",
 				},
 			},
 			HealthCheck: HealthCheckConfig{
 				Enabled:  true,
 				Endpoint: "/health",
 				Detailed: false,
+				Readiness: ReadinessConfig{
+					WaitBeforeListen: false,
+					Timeout:          30 * time.Second,
+				},
+				Plugins: PluginHealthConfig{
+					AutoDisableThreshold: 3,
+					CheckInterval:        30 * time.Second,
+				},
+				SelfTest: server.SelfTestConfig{
+					Enabled:     false,
+					FailOnError: false,
+					Timeout:     5 * time.Second,
+				},
 			},
 		},
 		Logging: LoggingConfig{
@@ -545,13 +1136,21 @@ func GetDefaults() *GatewayConfig {
 				},
 			},
 			LoadBalancer: LoadBalancerConfig{
-				Strategy:    "round_robin",
+				Strategy:      "round_robin",
+				SessionHeader: "X-Session-ID",
+				RegionAffinity: router.RegionAffinityConfig{
+					RegionHeader: "X-Client-Region",
+					ZoneHeader:   "X-Client-Zone",
+				},
 				HealthAware: true,
 				CircuitBreaker: CircuitBreakerConfig{
 					Enabled:             true,
 					FailureThreshold:    5,
 					RecoveryTimeout:     30 * time.Second,
 					HalfOpenMaxRequests: 3,
+					Alerts: server.CircuitBreakerAlertConfig{
+						WebhookTimeout: 5 * time.Second,
+					},
 				},
 			},
 			HealthChecks: HealthChecksConfig{
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Migration moves a deprecated configuration field to its new location.
+// Paths are dotted YAML keys such as "server.tls.cert". A migration without
+// a To path marks a field that was removed and is dropped with a warning.
+type Migration struct {
+	From  string
+	To    string
+	Since string // Release that deprecated the field
+}
+
+// MigrationRegistry holds the field migrations applied when loading a file
+type MigrationRegistry struct {
+	migrations []Migration
+}
+
+// NewMigrationRegistry creates a registry with the given migrations
+func NewMigrationRegistry(migrations ...Migration) *MigrationRegistry {
+	r := &MigrationRegistry{}
+	for _, m := range migrations {
+		r.Register(m)
+	}
+	return r
+}
+
+// Register adds a migration; migrations are applied in registration order
+func (r *MigrationRegistry) Register(m Migration) {
+	r.migrations = append(r.migrations, m)
+}
+
+// Migrations returns the registered migrations
+func (r *MigrationRegistry) Migrations() []Migration {
+	return append([]Migration(nil), r.migrations...)
+}
+
+// gatewayMigrations lists renamed and removed GatewayConfig fields. Add an
+// entry here whenever a field moves so existing files keep their settings.
+var gatewayMigrations []Migration
+
+// GatewayMigrations returns the migration registry for GatewayConfig files
+func GatewayMigrations() *MigrationRegistry {
+	return NewMigrationRegistry(gatewayMigrations...)
+}
+
+// AppliedMigration records a deprecated field found in a loaded file
+type AppliedMigration struct {
+	Migration
+	Overridden bool // The new field was also set, so the deprecated value was discarded
+}
+
+// apply rewrites deprecated fields in a parsed YAML document in place
+func (r *MigrationRegistry) apply(doc *yaml.Node) []AppliedMigration {
+	if doc == nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	root := doc.Content[0]
+
+	var applied []AppliedMigration
+	for _, m := range r.migrations {
+		key, value, ok := removeYAMLPath(root, strings.Split(m.From, "."))
+		if !ok {
+			continue
+		}
+
+		result := AppliedMigration{Migration: m}
+		if m.To != "" {
+			toPath := strings.Split(m.To, ".")
+			if _, exists := lookupYAMLPath(root, toPath); exists {
+				result.Overridden = true
+			} else {
+				setYAMLPath(root, toPath, key, value)
+			}
+		}
+		applied = append(applied, result)
+	}
+	return applied
+}
+
+// lookupYAMLPath returns the value at a dotted path in a mapping node
+func lookupYAMLPath(node *yaml.Node, path []string) (*yaml.Node, bool) {
+	for _, name := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil, false
+		}
+		found := false
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				node, found = node.Content[i+1], true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// removeYAMLPath deletes the key at a dotted path, returning its key and value nodes
+func removeYAMLPath(root *yaml.Node, path []string) (*yaml.Node, *yaml.Node, bool) {
+	parent, ok := lookupYAMLPath(root, path[:len(path)-1])
+	if !ok || parent.Kind != yaml.MappingNode {
+		return nil, nil, false
+	}
+
+	name := path[len(path)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == name {
+			key, value := parent.Content[i], parent.Content[i+1]
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return key, value, true
+		}
+	}
+	return nil, nil, false
+}
+
+// setYAMLPath stores value at a dotted path, creating intermediate mappings.
+// The moved key keeps its comments under the new name.
+func setYAMLPath(root *yaml.Node, path []string, oldKey, value *yaml.Node) {
+	node := root
+	for _, name := range path[:len(path)-1] {
+		child, ok := lookupYAMLPath(node, []string{name})
+		if !ok || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, child)
+		}
+		node = child
+	}
+
+	key := &yaml.Node{
+		Kind:        yaml.ScalarNode,
+		Tag:         "!!str",
+		Value:       path[len(path)-1],
+		HeadComment: oldKey.HeadComment,
+		LineComment: oldKey.LineComment,
+	}
+	node.Content = append(node.Content, key, value)
+}
+
+// migrateDocument parses YAML, applies migrations and returns the rewritten
+// document, logging a deprecation warning for each migrated field
+func (l *Loader) migrateDocument(filePath string, data []byte, registry *MigrationRegistry) ([]byte, []AppliedMigration, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	applied := registry.apply(&doc)
+	for _, m := range applied {
+		switch {
+		case m.To == "":
+			l.logger.Warn("config_removed_field_ignored",
+				"file_path", filePath,
+				"field", m.From,
+				"deprecated_since", m.Since)
+		case m.Overridden:
+			l.logger.Warn("config_deprecated_field_overridden",
+				"file_path", filePath,
+				"field", m.From,
+				"replacement", m.To,
+				"deprecated_since", m.Since)
+		default:
+			l.logger.Warn("config_deprecated_field_migrated",
+				"file_path", filePath,
+				"field", m.From,
+				"replacement", m.To,
+				"deprecated_since", m.Since)
+		}
+	}
+	if len(applied) == 0 {
+		return data, nil, nil
+	}
+
+	migrated, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode migrated configuration: %w", err)
+	}
+	return migrated, applied, nil
+}
+
+// writeMigratedConfig saves the migrated document so operators can replace
+// the deprecated file. Configs may hold secrets, so the file is owner-only.
+func (l *Loader) writeMigratedConfig(filePath string, data []byte) error {
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write migrated configuration %s: %w", filePath, err)
+	}
+
+	l.logger.Info("config_migrated_file_written",
+		"file_path", filePath,
+		"size_bytes", len(data))
+	return nil
+}
+
+// decodeKnownFields decodes YAML into config, reporting fields that match
+// nothing in the target struct. In strict mode they fail the load; otherwise
+// each one is logged and the remaining fields are still applied.
+func (l *Loader) decodeKnownFields(filePath string, data []byte, config interface{}, strict bool) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(config)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+
+	var remaining []string
+	for _, message := range typeErr.Errors {
+		if strict || !strings.Contains(message, "not found in type") {
+			remaining = append(remaining, message)
+			continue
+		}
+		l.logger.Warn("config_unknown_field_ignored",
+			"file_path", filePath,
+			"detail", message)
+	}
+
+	if len(remaining) > 0 {
+		return &yaml.TypeError{Errors: remaining}
+	}
+	return nil
+}
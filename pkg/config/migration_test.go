@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadMigratesDeprecatedFields tests that deprecated fields move to their new names and unknown fields warn
+func TestLoadMigratesDeprecatedFields(t *testing.T) {
+	type migrationTestConfig struct {
+		Server struct {
+			Host       string `yaml:"host"`
+			ListenPort int    `yaml:"listen_port"`
+		} `yaml:"server"`
+	}
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	content := "server:\n  host: localhost\n  # Public port\n  port: 9090\n  colour: blue\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	migrations := NewMigrationRegistry(Migration{From: "server.port", To: "server.listen_port", Since: "0.9.0"})
+
+	t.Run("deprecated field is migrated and unknown field warns", func(t *testing.T) {
+		logger := &warningRecorder{}
+		migratedPath := filepath.Join(dir, "migrated.yaml")
+
+		var cfg migrationTestConfig
+		err := NewLoader(logger).LoadFromFile(configPath, &cfg, &LoadOptions{
+			Migrations:       migrations,
+			MigratedFilePath: migratedPath,
+		})
+		if err != nil {
+			t.Fatalf("expected load to succeed, got %v", err)
+		}
+		if cfg.Server.ListenPort != 9090 || cfg.Server.Host != "localhost" {
+			t.Errorf("expected migrated port 9090 and host localhost, got %+v", cfg.Server)
+		}
+
+		for _, warning := range []string{"config_deprecated_field_migrated", "config_unknown_field_ignored"} {
+			if !logger.warned(warning) {
+				t.Errorf("expected %s warning, got %v", warning, logger.warnings)
+			}
+		}
+
+		migrated, err := os.ReadFile(migratedPath)
+		if err != nil {
+			t.Fatalf("expected migrated config to be written: %v", err)
+		}
+		if !strings.Contains(string(migrated), "listen_port: 9090") || strings.Contains(string(migrated), " port: 9090") {
+			t.Errorf("expected migrated config to use listen_port, got:\n%s", migrated)
+		}
+	})
+
+	t.Run("new field wins over deprecated one", func(t *testing.T) {
+		path := filepath.Join(dir, "both.yaml")
+		os.WriteFile(path, []byte("server:\n  port: 9090\n  listen_port: 8080\n"), 0644)
+
+		logger := &warningRecorder{}
+		var cfg migrationTestConfig
+		if err := NewLoader(logger).LoadFromFile(path, &cfg, &LoadOptions{Migrations: migrations}); err != nil {
+			t.Fatalf("expected load to succeed, got %v", err)
+		}
+		if cfg.Server.ListenPort != 8080 || !logger.warned("config_deprecated_field_overridden") {
+			t.Errorf("expected listen_port 8080 with an override warning, got %d %v", cfg.Server.ListenPort, logger.warnings)
+		}
+	})
+
+	t.Run("strict mode rejects unknown fields", func(t *testing.T) {
+		var cfg migrationTestConfig
+		err := NewLoader(&warningRecorder{}).LoadFromFile(configPath, &cfg, &LoadOptions{Migrations: migrations, Strict: true})
+		if err == nil || !strings.Contains(err.Error(), "colour") {
+			t.Errorf("expected unknown field error, got %v", err)
+		}
+	})
+}
+
+// warningRecorder records warning messages; other log levels are discarded
+type warningRecorder struct {
+	noOpLogger
+	warnings []string
+}
+
+func (l *warningRecorder) Warn(msg string, fields ...interface{}) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func (l *warningRecorder) warned(msg string) bool {
+	for _, warning := range l.warnings {
+		if warning == msg {
+			return true
+		}
+	}
+	return false
+}
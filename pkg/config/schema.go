@@ -0,0 +1,176 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect emitted by GenerateSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches Go duration strings such as "30s" or "1h30m"
+const durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// Schema is a JSON Schema node describing one configuration value
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// GenerateSchema describes GatewayConfig as a JSON Schema so configuration
+// files can be validated by external tooling. Property names follow the yaml
+// tags, defaults come from GetDefaults, descriptions from `description` tags
+// and required fields from `validate:"required"` tags.
+func GenerateSchema() *Schema {
+	schema := schemaFor(reflect.TypeOf(GatewayConfig{}), reflect.ValueOf(*GetDefaults()))
+	schema.Schema = JSONSchemaDraft
+	schema.Title = "MCPEG gateway configuration"
+	return schema
+}
+
+// schemaFor builds the schema for a type; defaults holds the default value
+// of that type and may be invalid when no default is known
+func schemaFor(t reflect.Type, defaults reflect.Value) *Schema {
+	if t == durationType {
+		schema := &Schema{Type: "string", Format: "duration", Pattern: durationPattern}
+		if defaults.IsValid() {
+			schema.Default = time.Duration(defaults.Int()).String()
+		}
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if defaults.IsValid() && !defaults.IsNil() {
+			return schemaFor(t.Elem(), defaults.Elem())
+		}
+		return schemaFor(t.Elem(), reflect.Value{})
+
+	case reflect.Struct:
+		return structSchema(t, defaults)
+
+	case reflect.Map:
+		schema := &Schema{
+			Type:                 "object",
+			AdditionalProperties: schemaFor(t.Elem(), reflect.Value{}),
+		}
+		if defaults.IsValid() && defaults.Len() > 0 {
+			schema.Default = defaults.Interface()
+		}
+		return schema
+
+	case reflect.Slice, reflect.Array:
+		schema := &Schema{Type: "array", Items: schemaFor(t.Elem(), reflect.Value{})}
+		if defaults.IsValid() && defaults.Len() > 0 {
+			schema.Default = defaults.Interface()
+		}
+		return schema
+
+	case reflect.Interface:
+		// Any value is accepted
+		return &Schema{}
+	}
+
+	schema := &Schema{Type: scalarType(t.Kind())}
+	if defaults.IsValid() {
+		schema.Default = defaults.Interface()
+	}
+	return schema
+}
+
+func structSchema(t reflect.Type, defaults reflect.Value) *Schema {
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, inline, skip := yamlFieldName(field)
+		if skip {
+			continue
+		}
+
+		var fieldDefaults reflect.Value
+		if defaults.IsValid() {
+			fieldDefaults = defaults.Field(i)
+		}
+		fieldSchema := schemaFor(field.Type, fieldDefaults)
+
+		if inline {
+			for key, property := range fieldSchema.Properties {
+				schema.Properties[key] = property
+			}
+			schema.Required = append(schema.Required, fieldSchema.Required...)
+			continue
+		}
+
+		fieldSchema.Description = field.Tag.Get("description")
+		schema.Properties[name] = fieldSchema
+		if isRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// yamlFieldName returns the key a field is read from, mirroring yaml.v3
+func yamlFieldName(field reflect.StructField) (name string, inline, skip bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "inline" {
+			return "", true, false
+		}
+	}
+
+	if parts[0] != "" {
+		return parts[0], false, false
+	}
+	return strings.ToLower(field.Name), false, false
+}
+
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func scalarType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestGenerateSchema tests that the config schema describes known fields with types and defaults
+func TestGenerateSchema(t *testing.T) {
+	schema := GenerateSchema()
+
+	// Round-trip through JSON so assertions match what the command emits
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("failed to encode schema: %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode schema: %v", err)
+	}
+
+	property := func(t *testing.T, path ...string) map[string]interface{} {
+		t.Helper()
+		node := doc
+		for _, name := range path {
+			properties, _ := node["properties"].(map[string]interface{})
+			next, ok := properties[name].(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected property %v in schema", path)
+			}
+			node = next
+		}
+		return node
+	}
+
+	if doc["$schema"] != JSONSchemaDraft || doc["type"] != "object" {
+		t.Errorf("expected a JSON Schema object at the root, got %v / %v", doc["$schema"], doc["type"])
+	}
+
+	tests := []struct {
+		path        []string
+		wantType    string
+		wantDefault interface{}
+	}{
+		{[]string{"server", "port"}, "integer", float64(8080)},
+		{[]string{"server", "address"}, "string", "0.0.0.0"},
+		{[]string{"server", "read_timeout"}, "string", "30s"},
+		{[]string{"server", "tls", "enabled"}, "boolean", false},
+		{[]string{"server", "cors", "allow_methods"}, "array", []interface{}{"GET", "POST", "PUT", "DELETE", "OPTIONS"}},
+		{[]string{"server", "method_timeouts"}, "object", nil},
+		{[]string{"server", "max_response_size"}, "integer", float64(50 << 20)},
+		{[]string{"server", "degraded_mode", "max_entries"}, "integer", float64(1000)},
+		{[]string{"security", "capability_policy", "denied_tools"}, "array", nil},
+	}
+
+	for _, tt := range tests {
+		node := property(t, tt.path...)
+		if node["type"] != tt.wantType {
+			t.Errorf("%v: expected type %s, got %v", tt.path, tt.wantType, node["type"])
+		}
+		if got, _ := json.Marshal(node["default"]); string(got) != mustJSON(t, tt.wantDefault) {
+			t.Errorf("%v: expected default %v, got %v", tt.path, tt.wantDefault, node["default"])
+		}
+	}
+
+	t.Run("durations are described as Go duration strings", func(t *testing.T) {
+		node := property(t, "server", "shutdown_timeout")
+		if node["format"] != "duration" || node["pattern"] == nil {
+			t.Errorf("expected duration format and pattern, got %v", node)
+		}
+	})
+
+	t.Run("required fields and descriptions come from struct tags", func(t *testing.T) {
+		required, _ := property(t, "server")["required"].([]interface{})
+		if len(required) != 2 || required[0] != "address" || required[1] != "port" {
+			t.Errorf("expected server to require address and port, got %v", required)
+		}
+		if property(t, "server")["description"] == "" || property(t, "server")["description"] == nil {
+			t.Error("expected server to carry a description")
+		}
+	})
+
+	t.Run("unknown keys are rejected", func(t *testing.T) {
+		if property(t, "server")["additionalProperties"] != false {
+			t.Error("expected structs to disallow additional properties")
+		}
+	})
+}
+
+func mustJSON(t *testing.T, value interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to encode %v: %v", value, err)
+	}
+	return string(data)
+}
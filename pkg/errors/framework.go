@@ -382,7 +382,7 @@ func (eh *ErrorHandler) logError(mcpErr *MCPError) {
 // Standard error constructors with enhanced context
 func ValidationError(service, operation, message string, context map[string]interface{}) *MCPError {
 	return &MCPError{
-		Code:      -32602,
+		Code:      CodeInvalidParams,
 		Message:   message,
 		Category:  CategoryValidation,
 		Severity:  SeverityMedium,
@@ -407,7 +407,7 @@ func TimeoutError(service, operation string, timeout time.Duration, context map[
 	context["timeout_duration"] = timeout.String()
 
 	return &MCPError{
-		Code:      -32001,
+		Code:      CodeTimeout,
 		Message:   fmt.Sprintf("Operation timed out after %v", timeout),
 		Category:  CategoryTimeout,
 		Severity:  SeverityHigh,
@@ -428,7 +428,7 @@ func TimeoutError(service, operation string, timeout time.Duration, context map[
 
 func UnavailableError(service, operation string, cause error, context map[string]interface{}) *MCPError {
 	return &MCPError{
-		Code:      -32004,
+		Code:      CodeUnavailable,
 		Message:   fmt.Sprintf("Service %s is unavailable", service),
 		Category:  CategoryUnavailable,
 		Severity:  SeverityCritical,
@@ -448,6 +448,25 @@ func UnavailableError(service, operation string, cause error, context map[string
 	}
 }
 
+func AuthorizationError(service, operation, message string, context map[string]interface{}) *MCPError {
+	return &MCPError{
+		Code:      CodeForbidden,
+		Message:   message,
+		Category:  CategoryAuthorization,
+		Severity:  SeverityMedium,
+		Service:   service,
+		Operation: operation,
+		Context:   context,
+		UserError: true,
+		Retryable: false,
+		Timestamp: time.Now(),
+		Suggestions: []string{
+			"Check that the capability is enabled for this gateway",
+			"Contact an administrator to request access",
+		},
+	}
+}
+
 // Helper functions
 func contains(s string, substrings ...string) bool {
 	for _, substr := range substrings {
@@ -613,3 +632,10 @@ func IsTimeoutError(err error) bool {
 	}
 	return false
 }
+
+func IsAuthorizationError(err error) bool {
+	if mcpErr, ok := err.(*MCPError); ok {
+		return mcpErr.Category == CategoryAuthorization
+	}
+	return false
+}
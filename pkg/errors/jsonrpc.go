@@ -0,0 +1,251 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+)
+
+// CategoryUpstream marks a backend that answered with a server error
+const CategoryUpstream ErrorCategory = "upstream"
+
+// CategoryInvalidResponse marks a backend whose response is not valid JSON-RPC
+const CategoryInvalidResponse ErrorCategory = "invalid_response"
+
+// CategoryMethodNotFound marks a method neither the gateway nor any backend serves
+const CategoryMethodNotFound ErrorCategory = "method_not_found"
+
+// JSON-RPC error codes returned for each error category. JSON-RPC defines
+// invalid params and internal error; the gateway codes mirror the matching
+// HTTP status in the implementation-defined range, as the MCP codes in pkg/mcp do.
+const (
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeUnauthorized   = -32401
+	CodeForbidden      = -32403
+	CodeNotFound       = -32404
+	CodeTimeout        = -32408
+	CodeRateLimited    = -32429
+	CodeUpstreamError  = -32502
+	CodeUnavailable    = -32503
+)
+
+// Machine-readable reasons carried in JSON-RPC error data
+const (
+	ReasonMethodNotFound  = "method_not_found"
+	ReasonInvalidParams   = "invalid_params"
+	ReasonUnauthenticated = "unauthenticated"
+	ReasonForbidden       = "forbidden"
+	ReasonNotFound        = "not_found"
+	ReasonTimeout         = "timeout"
+	ReasonCancelled       = "cancelled"
+	ReasonRateLimited     = "rate_limited"
+	ReasonUpstreamError   = "upstream_error"
+	ReasonInvalidResponse = "invalid_upstream_response"
+	ReasonUnavailable     = "unavailable"
+	ReasonUnreachable     = "upstream_unreachable"
+	ReasonInternal        = "internal_error"
+)
+
+// JSONRPCMapping is the wire representation of an error
+type JSONRPCMapping struct {
+	Code      int
+	Message   string
+	Reason    string
+	Retryable bool
+}
+
+// categoryMappings maps each error category onto its JSON-RPC code
+var categoryMappings = map[ErrorCategory]JSONRPCMapping{
+	CategoryMethodNotFound:  {CodeMethodNotFound, "Method not found", ReasonMethodNotFound, false},
+	CategoryValidation:      {CodeInvalidParams, "Invalid parameters", ReasonInvalidParams, false},
+	CategoryAuthentication:  {CodeUnauthorized, "Authentication failed", ReasonUnauthenticated, false},
+	CategoryAuthorization:   {CodeForbidden, "Permission denied", ReasonForbidden, false},
+	CategoryResource:        {CodeNotFound, "Not found", ReasonNotFound, false},
+	CategoryTimeout:         {CodeTimeout, "Request timeout", ReasonTimeout, true},
+	CategoryRateLimit:       {CodeRateLimited, "Rate limit exceeded", ReasonRateLimited, true},
+	CategoryUpstream:        {CodeUpstreamError, "Upstream service error", ReasonUpstreamError, true},
+	CategoryInvalidResponse: {CodeUpstreamError, "Invalid upstream response", ReasonInvalidResponse, false},
+	CategoryUnavailable:     {CodeUnavailable, "Service unavailable", ReasonUnavailable, true},
+	CategoryNetwork:         {CodeUnavailable, "Service unreachable", ReasonUnreachable, true},
+}
+
+var internalMapping = JSONRPCMapping{CodeInternalError, "Internal error", ReasonInternal, false}
+
+// ToJSONRPC maps an error, including wrapped ones, to its JSON-RPC code,
+// message and reason. Context deadlines count as timeouts; anything
+// unclassified is an internal error.
+func ToJSONRPC(err error) JSONRPCMapping {
+	var mcpErr *MCPError
+	if stderrors.As(err, &mcpErr) {
+		if mapping, ok := categoryMappings[mcpErr.Category]; ok {
+			return mapping
+		}
+		return internalMapping
+	}
+
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return categoryMappings[CategoryTimeout]
+	case stderrors.Is(err, context.Canceled):
+		return JSONRPCMapping{CodeInternalError, "Request cancelled", ReasonCancelled, false}
+	}
+	return internalMapping
+}
+
+// codeReasons names codes sent without a classified error
+var codeReasons = map[int]string{
+	-32700:             "parse_error",
+	-32600:             "invalid_request",
+	CodeMethodNotFound: ReasonMethodNotFound,
+	CodeInvalidParams:  ReasonInvalidParams,
+	CodeInternalError:  ReasonInternal,
+	CodeUnauthorized:   ReasonUnauthenticated,
+	CodeForbidden:      ReasonForbidden,
+	CodeNotFound:       ReasonNotFound,
+	CodeTimeout:        ReasonTimeout,
+	CodeRateLimited:    ReasonRateLimited,
+	CodeUpstreamError:  ReasonUpstreamError,
+	CodeUnavailable:    ReasonUnavailable,
+}
+
+// JSONRPCData builds the error data object sent to clients with code: a
+// reason matching the code, whether a retry may succeed and the error details
+func JSONRPCData(code int, err error) map[string]interface{} {
+	mapping := ToJSONRPC(err)
+	if mapping.Code != code {
+		mapping = JSONRPCMapping{Code: code, Reason: codeReasons[code]}
+		if mapping.Reason == "" {
+			mapping.Reason = ReasonInternal
+		}
+	}
+
+	data := map[string]interface{}{
+		"reason":    mapping.Reason,
+		"retryable": mapping.Retryable,
+		"details":   err.Error(),
+	}
+
+	var mcpErr *MCPError
+	if stderrors.As(err, &mcpErr) {
+		data["category"] = string(mcpErr.Category)
+		if status, ok := mcpErr.Context["status_code"]; ok {
+			data["upstream_status"] = status
+		}
+		if retryAfter, ok := mcpErr.Context["retry_after"]; ok {
+			data["retry_after"] = retryAfter
+		}
+		if contentType, ok := mcpErr.Context["content_type"]; ok {
+			data["upstream_content_type"] = contentType
+		}
+		if supported, ok := mcpErr.Context["supported_methods"]; ok {
+			data["supported_methods"] = supported
+		}
+	}
+	return data
+}
+
+// RateLimitError reports a request rejected by a rate limit
+func RateLimitError(service, operation string, retryAfter time.Duration, context map[string]interface{}) *MCPError {
+	if context == nil {
+		context = make(map[string]interface{})
+	}
+	if retryAfter > 0 {
+		context["retry_after"] = retryAfter.String()
+	}
+
+	return &MCPError{
+		Code:      CodeRateLimited,
+		Message:   "Rate limit exceeded",
+		Category:  CategoryRateLimit,
+		Severity:  SeverityLow,
+		Service:   service,
+		Operation: operation,
+		Context:   context,
+		Retryable: true,
+		Temporary: true,
+		Timestamp: time.Now(),
+		Suggestions: []string{
+			"Retry after the indicated delay",
+			"Reduce request rate",
+		},
+	}
+}
+
+// UpstreamError reports a backend that answered with a server error status
+func UpstreamError(service, operation string, statusCode int, cause error, context map[string]interface{}) *MCPError {
+	if context == nil {
+		context = make(map[string]interface{})
+	}
+	context["status_code"] = statusCode
+
+	return &MCPError{
+		Code:      CodeUpstreamError,
+		Message:   fmt.Sprintf("Service %s returned HTTP %d", service, statusCode),
+		Category:  CategoryUpstream,
+		Severity:  SeverityHigh,
+		Service:   service,
+		Operation: operation,
+		Context:   context,
+		Cause:     cause,
+		Retryable: true,
+		Temporary: true,
+		Timestamp: time.Now(),
+		Suggestions: []string{
+			"Check backend service logs",
+			"Retry operation after delay",
+		},
+	}
+}
+
+// MethodNotFoundError reports a method nothing serves, listing the methods
+// clients can call instead
+func MethodNotFoundError(service, method string, supported []string) *MCPError {
+	return &MCPError{
+		Code:      CodeMethodNotFound,
+		Message:   fmt.Sprintf("Method %s is not supported", method),
+		Category:  CategoryMethodNotFound,
+		Severity:  SeverityLow,
+		Service:   service,
+		Operation: method,
+		Context: map[string]interface{}{
+			"method":            method,
+			"supported_methods": supported,
+		},
+		Timestamp: time.Now(),
+		Suggestions: []string{
+			"Call one of the supported methods",
+			"Register a service providing the method",
+		},
+	}
+}
+
+// InvalidResponseError reports a backend that answered 200 with a body that is
+// not a JSON-RPC response, such as an HTML error page from a proxy. The
+// content type and the start of the body are kept for diagnosis.
+func InvalidResponseError(service, operation, contentType, snippet string, cause error) *MCPError {
+	if contentType == "" {
+		contentType = "none"
+	}
+
+	return &MCPError{
+		Code:      CodeUpstreamError,
+		Message:   fmt.Sprintf("Service %s returned an invalid response (Content-Type: %s): %q", service, contentType, snippet),
+		Category:  CategoryInvalidResponse,
+		Severity:  SeverityHigh,
+		Service:   service,
+		Operation: operation,
+		Context: map[string]interface{}{
+			"content_type": contentType,
+			"body_snippet": snippet,
+		},
+		Cause:     cause,
+		Timestamp: time.Now(),
+		Suggestions: []string{
+			"Check that the service endpoint points at the MCP handler",
+			"Check backend service logs",
+		},
+	}
+}
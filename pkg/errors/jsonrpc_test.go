@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestToJSONRPC tests that each error category maps to its own JSON-RPC code and reason
+func TestToJSONRPC(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		code   int
+		reason string
+	}{
+		{"validation", ValidationError("svc", "op", "bad input", nil), CodeInvalidParams, ReasonInvalidParams},
+		{"timeout", TimeoutError("svc", "op", time.Second, nil), CodeTimeout, ReasonTimeout},
+		{"rate limit", RateLimitError("svc", "op", time.Second, nil), CodeRateLimited, ReasonRateLimited},
+		{"upstream", UpstreamError("svc", "op", 502, nil, nil), CodeUpstreamError, ReasonUpstreamError},
+		{"invalid response", InvalidResponseError("svc", "op", "text/html", "<html>", nil), CodeUpstreamError, ReasonInvalidResponse},
+		{"unavailable", UnavailableError("svc", "op", fmt.Errorf("down"), nil), CodeUnavailable, ReasonUnavailable},
+		{"authorization", AuthorizationError("svc", "op", "denied", nil), CodeForbidden, ReasonForbidden},
+		{"wrapped", fmt.Errorf("routing: %w", RateLimitError("svc", "op", 0, nil)), CodeRateLimited, ReasonRateLimited},
+		{"context deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), CodeTimeout, ReasonTimeout},
+		{"context cancelled", context.Canceled, CodeInternalError, ReasonCancelled},
+		{"unclassified", fmt.Errorf("boom"), CodeInternalError, ReasonInternal},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mapping := ToJSONRPC(tc.err)
+			if mapping.Code != tc.code || mapping.Reason != tc.reason {
+				t.Errorf("expected %d/%s, got %d/%s", tc.code, tc.reason, mapping.Code, mapping.Reason)
+			}
+		})
+	}
+}
+
+// TestJSONRPCData tests the machine-readable error data sent to clients
+func TestJSONRPCData(t *testing.T) {
+	t.Run("classified error carries reason and context", func(t *testing.T) {
+		data := JSONRPCData(CodeRateLimited, RateLimitError("svc", "op", 2*time.Second, nil))
+		if data["reason"] != ReasonRateLimited || data["retryable"] != true {
+			t.Errorf("unexpected data %v", data)
+		}
+		if data["retry_after"] != "2s" {
+			t.Errorf("expected retry_after 2s, got %v", data["retry_after"])
+		}
+	})
+
+	t.Run("reason follows the code actually sent", func(t *testing.T) {
+		data := JSONRPCData(-32601, fmt.Errorf("method not found: foo"))
+		if data["reason"] != "method_not_found" || data["details"] != "method not found: foo" {
+			t.Errorf("unexpected data %v", data)
+		}
+	})
+}
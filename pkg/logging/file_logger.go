@@ -402,8 +402,19 @@ type ProductionLogger struct {
 	level       string
 }
 
-// NewProductionLogger creates a production logger with both console and file output
+// NewProductionLogger creates a production logger with both console and file
+// output, writing human-readable console lines
 func NewProductionLogger(level string, fileConfig FileLoggerConfig) (*ProductionLogger, error) {
+	return NewProductionLoggerWithFormat(level, FormatConsole, fileConfig)
+}
+
+// NewProductionLoggerWithFormat creates a production logger with both console
+// and file output, writing lines in format: json, logfmt or console
+func NewProductionLoggerWithFormat(level, format string, fileConfig FileLoggerConfig) (*ProductionLogger, error) {
+	if err := ValidateFormat(format); err != nil {
+		return nil, err
+	}
+
 	// Create file logger
 	fileLogger, err := NewFileLogger(fileConfig)
 	if err != nil {
@@ -416,6 +427,7 @@ func NewProductionLogger(level string, fileConfig FileLoggerConfig) (*Production
 	// Create console logger
 	consoleLogger := &SimpleLogger{
 		level:  level,
+		format: normalizeFormat(format),
 		writer: multiWriter,
 	}
 
@@ -430,13 +442,15 @@ func NewProductionLogger(level string, fileConfig FileLoggerConfig) (*Production
 // Simple logger implementation for production use
 type SimpleLogger struct {
 	level  string
+	format string // json, logfmt or console
 	writer io.Writer
 	mutex  sync.Mutex
 }
 
-// WithComponent implements Logger interface
+// WithComponent implements Logger interface. Components share the logger so
+// level changes apply to all of them.
 func (sl *SimpleLogger) WithComponent(component string) Logger {
-	return &SimpleLogger{level: sl.level, writer: sl.writer}
+	return sl
 }
 
 // WithContext implements Logger interface
@@ -494,7 +508,9 @@ func (sl *SimpleLogger) shouldLog(level string) bool {
 		"error": 4,
 	}
 
+	sl.mutex.Lock()
 	currentLevel, ok1 := levels[sl.level]
+	sl.mutex.Unlock()
 	targetLevel, ok2 := levels[level]
 
 	if !ok1 || !ok2 {
@@ -508,17 +524,7 @@ func (sl *SimpleLogger) log(level, msg string, fields ...interface{}) {
 	sl.mutex.Lock()
 	defer sl.mutex.Unlock()
 
-	timestamp := time.Now().Format("2006-01-02T15:04:05.000Z")
-
-	logLine := fmt.Sprintf("[%s] %s %s", timestamp, level, msg)
-
-	if len(fields) > 0 {
-		logLine += fmt.Sprintf(" %v", fields)
-	}
-
-	logLine += "\n"
-
-	sl.writer.Write([]byte(logLine))
+	sl.writer.Write(formatLine(sl.format, time.Now(), level, msg, fields))
 }
 
 // RotateLog forces log rotation
@@ -540,7 +546,9 @@ func (pl *ProductionLogger) Close() error {
 func (pl *ProductionLogger) SetLevel(level string) {
 	pl.level = level
 	if simpleLogger, ok := pl.console.(*SimpleLogger); ok {
+		simpleLogger.mutex.Lock()
 		simpleLogger.level = level
+		simpleLogger.mutex.Unlock()
 	}
 }
 
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Output formats of the production logger
+const (
+	FormatJSON    = "json"    // One JSON object per line
+	FormatLogfmt  = "logfmt"  // key=value pairs per line
+	FormatConsole = "console" // Human-readable lines for terminals
+)
+
+// timestampLayout renders UTC timestamps with fixed millisecond precision
+const timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// ValidateFormat checks that format names a supported output format. An empty
+// format and "text", an older name for console, are accepted.
+func ValidateFormat(format string) error {
+	switch normalizeFormat(format) {
+	case FormatJSON, FormatLogfmt, FormatConsole:
+		return nil
+	default:
+		return fmt.Errorf("log format must be %s, %s or %s, got %q", FormatJSON, FormatLogfmt, FormatConsole, format)
+	}
+}
+
+func normalizeFormat(format string) string {
+	switch format := strings.ToLower(strings.TrimSpace(format)); format {
+	case "", "text":
+		return FormatConsole
+	default:
+		return format
+	}
+}
+
+// formatLine renders one log line. Every format writes the time, level and
+// message first, then the fields in the order they were given; field values
+// are rendered the same way in each format.
+func formatLine(format string, timestamp time.Time, level, msg string, fields []interface{}) []byte {
+	pairs := fieldPairs(fields)
+	ts := timestamp.UTC().Format(timestampLayout)
+
+	var line bytes.Buffer
+	switch format {
+	case FormatJSON:
+		line.WriteString(`{"time":`)
+		line.Write(marshalValue(ts))
+		line.WriteString(`,"level":`)
+		line.Write(marshalValue(level))
+		line.WriteString(`,"msg":`)
+		line.Write(marshalValue(msg))
+		for _, pair := range pairs {
+			line.WriteByte(',')
+			line.Write(marshalValue(pair.key))
+			line.WriteByte(':')
+			line.Write(marshalValue(pair.value))
+		}
+		line.WriteByte('}')
+	case FormatLogfmt:
+		fmt.Fprintf(&line, "time=%s level=%s msg=%s", ts, level, logfmtValue(msg))
+		for _, pair := range pairs {
+			fmt.Fprintf(&line, " %s=%s", logfmtKey(pair.key), logfmtValue(pair.value))
+		}
+	default:
+		fmt.Fprintf(&line, "%s %-5s %s", ts, level, msg)
+		for _, pair := range pairs {
+			fmt.Fprintf(&line, " %s=%s", logfmtKey(pair.key), logfmtValue(pair.value))
+		}
+	}
+	line.WriteByte('\n')
+	return line.Bytes()
+}
+
+type fieldPair struct {
+	key   string
+	value interface{}
+}
+
+// fieldPairs splits alternating keys and values, keeping their order. A key
+// without a value gets nil.
+func fieldPairs(fields []interface{}) []fieldPair {
+	pairs := make([]fieldPair, 0, (len(fields)+1)/2)
+	for i := 0; i < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		if !ok {
+			key = fmt.Sprint(fields[i])
+		}
+		var value interface{}
+		if i+1 < len(fields) {
+			value = fields[i+1]
+		}
+		pairs = append(pairs, fieldPair{key: key, value: fieldValue(value)})
+	}
+	return pairs
+}
+
+// fieldValue converts values that marshal poorly to the text they print as:
+// errors to their message, durations to "1.5s" and times to UTC timestamps
+func fieldValue(value interface{}) interface{} {
+	if isNilPointer(value) {
+		return nil
+	}
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case time.Time:
+		return v.UTC().Format(timestampLayout)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return value
+	}
+}
+
+func isNilPointer(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// marshalValue encodes a value as JSON without HTML escaping, falling back to
+// its printed form when it cannot be encoded
+func marshalValue(value interface{}) []byte {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		encoded.Reset()
+		encoder.Encode(fmt.Sprintf("%+v", value))
+	}
+	return bytes.TrimSuffix(encoded.Bytes(), []byte("\n"))
+}
+
+// logfmtValue renders numbers, booleans and null as in JSON, strings bare
+// unless they need quoting, and objects and arrays as quoted JSON
+func logfmtValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return logfmtString(s)
+	}
+
+	encoded := marshalValue(value)
+	switch encoded[0] {
+	case '"':
+		var s string
+		json.Unmarshal(encoded, &s)
+		return logfmtString(s)
+	case '{', '[':
+		return strconv.Quote(string(encoded))
+	default:
+		return string(encoded)
+	}
+}
+
+func logfmtString(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// logfmtKey replaces characters a logfmt key cannot hold
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
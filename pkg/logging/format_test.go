@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLogFormats tests that the same log call renders in each output format
+// with the fixed fields first, fields in call order and stable value types
+func TestLogFormats(t *testing.T) {
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.FixedZone("CET", 3600))
+	fields := []interface{}{
+		"service_id", "search-1",
+		"attempt", 2,
+		"ratio", 0.25,
+		"cached", false,
+		"duration", 1500 * time.Millisecond,
+		"error", errors.New(`backend said "no"`),
+		"tags", []string{"a", "b"},
+		"missing", nil,
+	}
+
+	t.Run("json", func(t *testing.T) {
+		line := string(formatLine(FormatJSON, timestamp, "WARN", "request_failed", fields))
+		expected := `{"time":"2024-01-02T02:04:05.678Z","level":"WARN","msg":"request_failed",` +
+			`"service_id":"search-1","attempt":2,"ratio":0.25,"cached":false,"duration":"1.5s",` +
+			`"error":"backend said \"no\"","tags":["a","b"],"missing":null}` + "\n"
+		if line != expected {
+			t.Errorf("expected\n%s got\n%s", expected, line)
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Errorf("expected valid JSON, got %v", err)
+		}
+	})
+
+	t.Run("logfmt", func(t *testing.T) {
+		line := string(formatLine(FormatLogfmt, timestamp, "WARN", "request_failed", fields))
+		expected := `time=2024-01-02T02:04:05.678Z level=WARN msg=request_failed ` +
+			`service_id=search-1 attempt=2 ratio=0.25 cached=false duration=1.5s ` +
+			`error="backend said \"no\"" tags="[\"a\",\"b\"]" missing=null` + "\n"
+		if line != expected {
+			t.Errorf("expected\n%s got\n%s", expected, line)
+		}
+	})
+
+	t.Run("console", func(t *testing.T) {
+		line := string(formatLine(FormatConsole, timestamp, "WARN", "request_failed", fields))
+		expected := `2024-01-02T02:04:05.678Z WARN  request_failed ` +
+			`service_id=search-1 attempt=2 ratio=0.25 cached=false duration=1.5s ` +
+			`error="backend said \"no\"" tags="[\"a\",\"b\"]" missing=null` + "\n"
+		if line != expected {
+			t.Errorf("expected\n%s got\n%s", expected, line)
+		}
+	})
+
+	t.Run("odd and non-string keys are kept", func(t *testing.T) {
+		line := string(formatLine(FormatLogfmt, timestamp, "INFO", "started", []interface{}{42, "x", "dangling"}))
+		if !strings.HasSuffix(line, " 42=x dangling=null\n") {
+			t.Errorf("unexpected fields in %q", line)
+		}
+	})
+
+	t.Run("simple logger writes the configured format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := &SimpleLogger{level: "info", format: FormatJSON, writer: &buf}
+
+		logger.Debug("hidden")
+		logger.Info("gateway_started", "port", 8080)
+
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("expected a single JSON line, got %q: %v", buf.String(), err)
+		}
+		if decoded["msg"] != "gateway_started" || decoded["level"] != "INFO" || decoded["port"] != float64(8080) {
+			t.Errorf("unexpected entry %v", decoded)
+		}
+	})
+
+	t.Run("unknown formats are rejected", func(t *testing.T) {
+		for _, format := range []string{"", "text", "JSON", "logfmt", "console"} {
+			if err := ValidateFormat(format); err != nil {
+				t.Errorf("expected %q to be accepted, got %v", format, err)
+			}
+		}
+		fileConfig := FileLoggerConfig{FilePath: filepath.Join(t.TempDir(), "gateway.log")}
+		if _, err := NewProductionLoggerWithFormat("info", "xml", fileConfig); err == nil {
+			t.Error("expected an unknown format to be rejected")
+		}
+	})
+}
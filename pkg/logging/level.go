@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// LevelController is implemented by loggers whose verbosity can be changed at
+// runtime. Levels are the gateway names trace, debug, info, warn and error.
+type LevelController interface {
+	SetLevel(level string)
+	GetLevel() string
+}
+
+// levelRanks orders log levels from most to least verbose
+var levelRanks = map[LogLevel]int32{
+	LevelTrace: 0,
+	LevelDebug: 1,
+	LevelInfo:  2,
+	LevelWarn:  3,
+	LevelError: 4,
+}
+
+// ParseLevel converts a case-insensitive level name to a LogLevel
+func ParseLevel(level string) (LogLevel, bool) {
+	parsed := LogLevel(strings.ToUpper(strings.TrimSpace(level)))
+	if parsed == "WARNING" {
+		parsed = LevelWarn
+	}
+	_, ok := levelRanks[parsed]
+	return parsed, ok
+}
+
+// levelState is shared between a logger and every logger derived from it so a
+// level change applies to all components at once
+type levelState struct {
+	rank atomic.Int32
+}
+
+func newLevelState(level LogLevel) *levelState {
+	state := &levelState{}
+	state.rank.Store(levelRanks[level])
+	return state
+}
+
+func (s *levelState) enabled(level LogLevel) bool {
+	return s == nil || levelRanks[level] >= s.rank.Load()
+}
+
+func (s *levelState) level() LogLevel {
+	rank := int32(0)
+	if s != nil {
+		rank = s.rank.Load()
+	}
+	for level, r := range levelRanks {
+		if r == rank {
+			return level
+		}
+	}
+	return LevelTrace
+}
+
+// SetLevel changes the minimum level logged by this logger and every logger
+// derived from it; unknown levels are ignored
+func (l *llmLogger) SetLevel(level string) {
+	parsed, ok := ParseLevel(level)
+	if !ok || l.level == nil {
+		return
+	}
+	l.level.rank.Store(levelRanks[parsed])
+}
+
+// GetLevel returns the minimum level currently logged
+func (l *llmLogger) GetLevel() string {
+	return strings.ToLower(string(l.level.level()))
+}
+
+var _ LevelController = (*llmLogger)(nil)
+var _ LevelController = (*ProductionLogger)(nil)
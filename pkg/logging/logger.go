@@ -137,6 +137,7 @@ type llmLogger struct {
 	parentSpanID string
 	breadcrumbs  []Breadcrumb
 	output       func(entry Entry)
+	level        *levelState // shared with derived loggers; nil logs everything
 }
 
 // New creates a new LLM-optimized logger
@@ -144,6 +145,7 @@ func New(component string) Logger {
 	return &llmLogger{
 		component: component,
 		output:    defaultOutput,
+		level:     newLevelState(LevelTrace),
 	}
 }
 
@@ -154,6 +156,10 @@ func defaultOutput(entry Entry) {
 }
 
 func (l *llmLogger) log(level LogLevel, operation string, fields []interface{}) {
+	if !l.level.enabled(level) {
+		return
+	}
+
 	entry := Entry{
 		Timestamp:    time.Now().UTC(),
 		Level:        level,
@@ -207,3 +207,29 @@ func (e *wrappedError) Error() string {
 func (e *wrappedError) Unwrap() error {
 	return e.cause
 }
+
+func TestLLMLoggerSetLevel(t *testing.T) {
+	output := &testOutput{}
+	root := &llmLogger{
+		component: "test.root",
+		output:    output.capture,
+		level:     newLevelState(LevelTrace),
+	}
+	child := root.WithComponent("test.child")
+
+	root.SetLevel("warn")
+	if root.GetLevel() != "warn" {
+		t.Errorf("expected level warn, got %s", root.GetLevel())
+	}
+
+	child.Info("suppressed_operation")
+	child.Warn("logged_operation")
+	if len(output.entries) != 1 || output.entries[0].Operation != "logged_operation" {
+		t.Fatalf("expected only the warning from the derived logger, got %d entries", len(output.entries))
+	}
+
+	root.SetLevel("bogus")
+	if root.GetLevel() != "warn" {
+		t.Errorf("expected unknown level to be ignored, got %s", root.GetLevel())
+	}
+}
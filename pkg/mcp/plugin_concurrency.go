@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+)
+
+// Gauges and counter recorded per plugin for every tool invocation
+const (
+	PluginInFlightMetric  = "plugin_invocations_in_flight" // Calls executing
+	PluginQueuedMetric    = "plugin_invocations_queued"    // Calls waiting for a free slot
+	PluginSaturatedMetric = "plugin_invocations_saturated" // Calls that found every slot taken
+)
+
+// PluginConcurrencyConfig bounds how many tool calls run at once against a
+// plugin. Calls over the limit wait for a slot until their timeout expires.
+// Zero leaves a plugin unlimited; its calls are still counted.
+type PluginConcurrencyConfig struct {
+	MaxInFlight int            `yaml:"max_in_flight"` // Limit for every plugin
+	Limits      map[string]int `yaml:"limits"`        // Per-plugin limits overriding max_in_flight
+}
+
+// Validate checks that no limit is negative
+func (c PluginConcurrencyConfig) Validate() error {
+	if c.MaxInFlight < 0 {
+		return fmt.Errorf("max_in_flight must not be negative, got %d", c.MaxInFlight)
+	}
+	for plugin, limit := range c.Limits {
+		if plugin == "" {
+			return fmt.Errorf("plugin name must not be empty")
+		}
+		if limit < 0 {
+			return fmt.Errorf("limit for plugin %s must not be negative, got %d", plugin, limit)
+		}
+	}
+	return nil
+}
+
+// limitFor returns the concurrency limit of a plugin, zero when unlimited
+func (c PluginConcurrencyConfig) limitFor(plugin string) int {
+	if limit, ok := c.Limits[plugin]; ok {
+		return limit
+	}
+	return c.MaxInFlight
+}
+
+// pluginConcurrency tracks the calls running and waiting against each plugin
+type pluginConcurrency struct {
+	config  PluginConcurrencyConfig
+	logger  logging.Logger
+	metrics metrics.Metrics
+	mutex   sync.Mutex
+	plugins map[string]*pluginSlots
+}
+
+// pluginSlots holds the counts of one plugin. Gauges are set while mutex is
+// held so the last value recorded is always the current one.
+type pluginSlots struct {
+	slots    chan struct{} // nil when unlimited
+	mutex    sync.Mutex
+	inFlight int
+	queued   int
+}
+
+func newPluginConcurrency(config PluginConcurrencyConfig, logger logging.Logger, metrics metrics.Metrics) *pluginConcurrency {
+	return &pluginConcurrency{
+		config:  config,
+		logger:  logger,
+		metrics: metrics,
+		plugins: make(map[string]*pluginSlots),
+	}
+}
+
+func (pc *pluginConcurrency) slotsFor(plugin string) *pluginSlots {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	slots, exists := pc.plugins[plugin]
+	if !exists {
+		slots = &pluginSlots{}
+		if limit := pc.config.limitFor(plugin); limit > 0 {
+			slots.slots = make(chan struct{}, limit)
+		}
+		pc.plugins[plugin] = slots
+	}
+	return slots
+}
+
+// acquire takes a slot for a call to plugin, waiting while the plugin is at
+// its limit until ctx is done. The returned function frees the slot.
+func (pc *pluginConcurrency) acquire(ctx context.Context, plugin string) (func(), error) {
+	s := pc.slotsFor(plugin)
+
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+		default:
+			pc.metrics.Inc(PluginSaturatedMetric, "plugin", plugin)
+			pc.logger.Warn("plugin_concurrency_saturated",
+				"plugin", plugin,
+				"limit", cap(s.slots))
+
+			pc.update(plugin, s, 0, 1)
+			select {
+			case s.slots <- struct{}{}:
+				pc.update(plugin, s, 0, -1)
+			case <-ctx.Done():
+				pc.update(plugin, s, 0, -1)
+				return nil, fmt.Errorf("plugin %s is at its concurrency limit of %d: %w", plugin, cap(s.slots), ctx.Err())
+			}
+		}
+	}
+
+	pc.update(plugin, s, 1, 0)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			pc.update(plugin, s, -1, 0)
+			if s.slots != nil {
+				<-s.slots
+			}
+		})
+	}, nil
+}
+
+// update applies changes to the in-flight and queued counts of a plugin and
+// records the new values
+func (pc *pluginConcurrency) update(plugin string, s *pluginSlots, inFlight, queued int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.inFlight += inFlight
+	s.queued += queued
+	if inFlight != 0 {
+		pc.metrics.Set(PluginInFlightMetric, float64(s.inFlight), "plugin", plugin)
+	}
+	if queued != 0 {
+		pc.metrics.Set(PluginQueuedMetric, float64(s.queued), "plugin", plugin)
+	}
+}
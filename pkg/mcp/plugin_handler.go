@@ -20,6 +20,7 @@ type PluginHandlerImpl struct {
 	pluginDiscovery     *PluginDiscovery
 	pluginCommunication *PluginCommunication
 	pluginHotReload     *PluginHotReload
+	concurrency         *pluginConcurrency
 	logger              logging.Logger
 	metrics             metrics.Metrics
 	config              PluginHandlerConfig
@@ -32,6 +33,9 @@ type PluginHandlerConfig struct {
 	RetryBackoff   time.Duration `yaml:"retry_backoff"`
 	CacheEnabled   bool          `yaml:"cache_enabled"`
 	CacheTTL       time.Duration `yaml:"cache_ttl"`
+
+	// Limits on concurrent tool calls per plugin
+	Concurrency PluginConcurrencyConfig `yaml:"concurrency"`
 }
 
 // NewPluginHandler creates a new plugin handler instance
@@ -54,6 +58,7 @@ func NewPluginHandler(pluginManager *plugins.PluginManager, config PluginHandler
 		logger:        logger,
 		metrics:       metrics,
 		config:        config,
+		concurrency:   newPluginConcurrency(config.Concurrency, logger, metrics),
 	}
 
 	// Initialize plugin discovery (registry will be set later if available)
@@ -83,8 +88,15 @@ func (ph *PluginHandlerImpl) InvokePlugin(ctx context.Context, pluginName, toolN
 		return nil, fmt.Errorf("access denied to plugin: %s", pluginName)
 	}
 
-	// Get plugin instance
-	plugin, exists := ph.pluginManager.GetPlugin(pluginName)
+	// Reject calls to plugins taken out of rotation after failing health checks
+	if reason, disabled := ph.pluginManager.DisabledReason(pluginName); disabled {
+		ph.metrics.Inc("plugin_disabled_rejections", "plugin", pluginName, "tool", toolName)
+		return nil, fmt.Errorf("plugin %s is disabled: %s", pluginName, reason)
+	}
+
+	// Get plugin instance, holding it until the call completes so reloads can drain
+	plugin, release, exists := ph.pluginManager.AcquirePlugin(pluginName)
+	defer release()
 	if !exists {
 		ph.metrics.Inc("plugin_not_found", "plugin", pluginName)
 		return nil, fmt.Errorf("plugin not found: %s", pluginName)
@@ -104,6 +116,14 @@ func (ph *PluginHandlerImpl) InvokePlugin(ctx context.Context, pluginName, toolN
 	timeoutCtx, cancel := context.WithTimeout(ctx, ph.config.DefaultTimeout)
 	defer cancel()
 
+	// Wait for a free slot if the plugin is at its concurrency limit
+	releaseSlot, err := ph.concurrency.acquire(timeoutCtx, pluginName)
+	if err != nil {
+		ph.metrics.Inc("plugin_concurrency_rejections", "plugin", pluginName, "tool", toolName)
+		return nil, err
+	}
+	defer releaseSlot()
+
 	// Execute plugin tool with metrics and logging
 	ph.metrics.Inc("plugin_tool_calls", "plugin", pluginName, "tool", toolName)
 	ph.logger.Info("plugin_tool_invocation_started",
@@ -177,7 +197,7 @@ func (ph *PluginHandlerImpl) GetPluginCapabilities(pluginName string, capabiliti
 
 // ListAvailablePlugins returns a list of plugins the user has access to
 func (ph *PluginHandlerImpl) ListAvailablePlugins(capabilities *rbac.ProcessedCapabilities) []string {
-	allPlugins := ph.pluginManager.ListPlugins()
+	allPlugins := ph.pluginManager.ListEnabledPlugins()
 	accessible := make([]string, 0, len(allPlugins))
 
 	for pluginName := range allPlugins {
@@ -324,6 +344,44 @@ func (ph *PluginHandlerImpl) ReadPluginResource(ctx context.Context, uri string,
 	return result, nil
 }
 
+// WatchPluginResource calls onChange with uri whenever the plugin resource it
+// names changes, until the returned stop function is called. The plugin must
+// implement plugins.ResourceWatcher.
+func (ph *PluginHandlerImpl) WatchPluginResource(uri string, capabilities *rbac.ProcessedCapabilities, onChange func(uri string)) (func(), error) {
+	pluginName, resourceName, found := strings.Cut(strings.TrimPrefix(uri, "plugin://"), "/")
+	if !strings.HasPrefix(uri, "plugin://") || !found {
+		return nil, fmt.Errorf("invalid plugin resource URI format: %s", uri)
+	}
+
+	if !ph.hasPluginAccess(pluginName, capabilities) {
+		return nil, fmt.Errorf("access denied to plugin: %s", pluginName)
+	}
+	permission := capabilities.Plugins[pluginName]
+	if wildcardPerm, hasWildcard := capabilities.Plugins["*"]; hasWildcard && len(capabilities.Plugins) == 1 {
+		permission = wildcardPerm
+	}
+	if !permission.CanRead {
+		return nil, fmt.Errorf("read access denied for plugin: %s", pluginName)
+	}
+
+	plugin, exists := ph.pluginManager.GetPlugin(pluginName)
+	if !exists {
+		return nil, fmt.Errorf("plugin not found: %s", pluginName)
+	}
+	watcher, ok := plugin.(plugins.ResourceWatcher)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s does not support resource subscriptions", pluginName)
+	}
+
+	stop, err := watcher.WatchResource(resourceName, func(string) { onChange(uri) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch resource %s from plugin %s: %w", resourceName, pluginName, err)
+	}
+
+	ph.metrics.Inc("plugin_resource_watches_total", "plugin", pluginName)
+	return stop, nil
+}
+
 // GetPluginPrompts returns the prompts available for a plugin
 func (ph *PluginHandlerImpl) GetPluginPrompts(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]Prompt, error) {
 	if !ph.hasPluginAccess(pluginName, capabilities) {
@@ -359,6 +417,35 @@ func (ph *PluginHandlerImpl) GetPluginPrompts(pluginName string, capabilities *r
 	return mcpPrompts, nil
 }
 
+// GetPluginRoots returns the roots a plugin exposes, or none when the plugin
+// does not implement plugins.RootsProvider or the user cannot read it
+func (ph *PluginHandlerImpl) GetPluginRoots(pluginName string, capabilities *rbac.ProcessedCapabilities) ([]Root, error) {
+	if !ph.hasPluginAccess(pluginName, capabilities) {
+		return nil, fmt.Errorf("access denied to plugin: %s", pluginName)
+	}
+
+	plugin, exists := ph.pluginManager.GetPlugin(pluginName)
+	if !exists {
+		return nil, fmt.Errorf("plugin not found: %s", pluginName)
+	}
+
+	permission := capabilities.Plugins[pluginName]
+	if wildcardPerm, hasWildcard := capabilities.Plugins["*"]; hasWildcard && len(capabilities.Plugins) == 1 {
+		permission = wildcardPerm
+	}
+	provider, ok := plugin.(plugins.RootsProvider)
+	if !ok || !permission.CanRead {
+		return nil, nil
+	}
+
+	pluginRoots := provider.GetRoots()
+	roots := make([]Root, 0, len(pluginRoots))
+	for _, root := range pluginRoots {
+		roots = append(roots, Root{URI: root.URI, Name: root.Name})
+	}
+	return roots, nil
+}
+
 // HealthCheck checks if a plugin is healthy and accessible
 func (ph *PluginHandlerImpl) HealthCheck(pluginName string) (*PluginHealth, error) {
 	plugin, exists := ph.pluginManager.GetPlugin(pluginName)
@@ -513,6 +600,14 @@ func (ph *PluginHandlerImpl) convertToToolResult(result interface{}) *ToolResult
 			IsError: false,
 		}
 	case map[string]interface{}:
+		// Results already shaped as an MCP tool result (e.g. from stdio plugins)
+		if items, ok := v["content"].([]interface{}); ok {
+			isError, _ := v["isError"].(bool)
+			return &ToolResult{
+				Content: convertContentItems(items),
+				IsError: isError,
+			}
+		}
 		// Handle structured results
 		if text, ok := v["text"].(string); ok {
 			return &ToolResult{
@@ -549,6 +644,29 @@ func (ph *PluginHandlerImpl) convertToToolResult(result interface{}) *ToolResult
 	}
 }
 
+// convertContentItems decodes MCP content items; kinds without a typed
+// representation are passed on as their JSON text
+func convertContentItems(items []interface{}) []Content {
+	contents := make([]Content, 0, len(items))
+	for _, item := range items {
+		fields, _ := item.(map[string]interface{})
+		contentType, _ := fields["type"].(string)
+		switch contentType {
+		case "text":
+			text, _ := fields["text"].(string)
+			contents = append(contents, TextContent{Type: "text", Text: text})
+		case "image":
+			data, _ := fields["data"].(string)
+			mimeType, _ := fields["mimeType"].(string)
+			contents = append(contents, ImageContent{Type: "image", Data: data, MimeType: mimeType})
+		default:
+			encoded, _ := json.Marshal(item)
+			contents = append(contents, TextContent{Type: "text", Text: string(encoded)})
+		}
+	}
+	return contents
+}
+
 // Enhanced Discovery Methods for Phase 2
 
 // DiscoverPlugins performs enhanced plugin discovery
@@ -733,6 +851,14 @@ func (ph *PluginHandlerImpl) ReloadPlugin(ctx context.Context, pluginName string
 	return operation, nil
 }
 
+// OnPluginReloaded registers a listener called with the plugin name after
+// each successful hot reload
+func (ph *PluginHandlerImpl) OnPluginReloaded(listener func(pluginName string)) {
+	if ph.pluginHotReload != nil {
+		ph.pluginHotReload.OnReloadCompleted(listener)
+	}
+}
+
 // GetReloadStatus returns the status of a reload operation
 func (ph *PluginHandlerImpl) GetReloadStatus(operationID string) (interface{}, error) {
 	if ph.pluginHotReload == nil {
@@ -27,6 +27,9 @@ type PluginHotReload struct {
 	// Dependency tracking
 	dependencyGraph map[string][]string
 	reverseDeps     map[string][]string
+
+	// Called with the plugin name after each successful reload
+	reloadListeners []func(pluginName string)
 }
 
 // PluginHotReloadConfig configures hot reloading behavior
@@ -145,6 +148,14 @@ func NewPluginHotReload(
 	return phr
 }
 
+// OnReloadCompleted registers a listener called with the plugin name after
+// each successful reload, once the new instance is serving
+func (phr *PluginHotReload) OnReloadCompleted(listener func(pluginName string)) {
+	phr.mutex.Lock()
+	defer phr.mutex.Unlock()
+	phr.reloadListeners = append(phr.reloadListeners, listener)
+}
+
 // ReloadPlugin performs a hot reload of a specific plugin
 func (phr *PluginHotReload) ReloadPlugin(ctx context.Context, pluginName string, newPlugin plugins.Plugin) (*ReloadOperation, error) {
 	if !phr.config.EnableHotReload {
@@ -428,8 +439,15 @@ func (phr *PluginHotReload) executeReload(ctx context.Context, operation *Reload
 		Success:   success,
 		Duration:  time.Since(operation.StartTime),
 	})
+	listeners := append([]func(string){}, phr.reloadListeners...)
 	phr.mutex.Unlock()
 
+	if success {
+		for _, listener := range listeners {
+			listener(operation.PluginName)
+		}
+	}
+
 	phr.metrics.Inc("plugin_reloads_total", "plugin", operation.PluginName, "success", fmt.Sprintf("%t", success))
 	phr.metrics.Observe("plugin_reload_duration", time.Since(operation.StartTime).Seconds(), "plugin", operation.PluginName)
 
@@ -189,6 +189,12 @@ type Resource struct {
 	Annotations map[string]interface{} `json:"annotations,omitempty"`
 }
 
+// Root represents an MCP filesystem or workspace root
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
 // Prompt represents an MCP prompt template
 type Prompt struct {
 	Name        string           `json:"name"`
@@ -324,6 +330,8 @@ const (
 	ErrorCodeUnauthorized = -32401
 	ErrorCodeTimeout      = -32408
 	ErrorCodeRateLimited  = -32429
+	ErrorCodeUpstream     = -32502
+	ErrorCodeUnavailable  = -32503
 )
 
 // Helper functions for creating common responses
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of duration histograms
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Exemplar links a histogram observation to the trace it was recorded in, so
+// a slow bucket can be followed to an example request
+type Exemplar struct {
+	TraceID   string    `json:"trace_id"`
+	Value     float64   `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// HistogramObserver is implemented by metrics that keep bucketed histograms
+// whose buckets can carry exemplars
+type HistogramObserver interface {
+	// ObserveWithExemplar records an observation as Observe does and counts it
+	// in its DefaultBuckets bucket. A non-nil exemplar replaces the bucket's
+	// previous one.
+	ObserveWithExemplar(name string, value float64, exemplar *Exemplar, labels ...string)
+
+	// Histograms returns every labelled series of a histogram, ordered by labels
+	Histograms(name string) []HistogramSnapshot
+}
+
+// HistogramSnapshot is the state of one labelled histogram series
+type HistogramSnapshot struct {
+	Labels    []string    // Label name/value pairs
+	Buckets   []float64   // Upper bounds in ascending order; +Inf is implicit
+	Counts    []uint64    // Cumulative count per bucket, followed by +Inf
+	Exemplars []*Exemplar // Latest exemplar per bucket, followed by +Inf; nil where none
+	Count     uint64
+	Sum       float64
+}
+
+// histogram is one labelled series of bucketed observations
+type histogram struct {
+	name      string
+	labels    []string
+	counts    []uint64 // Per bucket, not cumulative, followed by +Inf
+	exemplars []*Exemplar
+	count     uint64
+	sum       float64
+}
+
+func newHistogram(name string, labels []string) *histogram {
+	return &histogram{
+		name:      name,
+		labels:    labels,
+		counts:    make([]uint64, len(DefaultBuckets)+1),
+		exemplars: make([]*Exemplar, len(DefaultBuckets)+1),
+	}
+}
+
+func (h *histogram) observe(value float64, exemplar *Exemplar) {
+	bucket := sort.SearchFloat64s(DefaultBuckets, value)
+	h.counts[bucket]++
+	h.count++
+	h.sum += value
+
+	if exemplar != nil && exemplar.TraceID != "" {
+		copied := *exemplar
+		if copied.Timestamp.IsZero() {
+			copied.Timestamp = time.Now()
+		}
+		h.exemplars[bucket] = &copied
+	}
+}
+
+func (h *histogram) snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Labels:    append([]string(nil), h.labels...),
+		Buckets:   append([]float64(nil), DefaultBuckets...),
+		Counts:    make([]uint64, len(h.counts)),
+		Exemplars: make([]*Exemplar, len(h.exemplars)),
+		Count:     h.count,
+		Sum:       h.sum,
+	}
+
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		snapshot.Counts[i] = cumulative
+		if h.exemplars[i] != nil {
+			copied := *h.exemplars[i]
+			snapshot.Exemplars[i] = &copied
+		}
+	}
+	return snapshot
+}
+
+// ObserveWithExemplar records an observation in the metric's stats and in its
+// bucketed histogram, keeping exemplar as the latest of its bucket
+func (m *ProductionMetrics) ObserveWithExemplar(name string, value float64, exemplar *Exemplar, labels ...string) {
+	m.Observe(name, value, labels...)
+
+	pairs := m.labelPairs(labels)
+	key := m.prefix + name + "{" + strings.Join(pairs, ",") + "}"
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	h, exists := m.histograms[key]
+	if !exists {
+		h = newHistogram(m.prefix+name, pairs)
+		m.histograms[key] = h
+	}
+	h.observe(value, exemplar)
+}
+
+// Histograms returns every labelled series of a histogram recorded with
+// ObserveWithExemplar, ordered by labels
+func (m *ProductionMetrics) Histograms(name string) []HistogramSnapshot {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var keys []string
+	for key, h := range m.histograms {
+		if h.name == m.prefix+name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	snapshots := make([]HistogramSnapshot, 0, len(keys))
+	for _, key := range keys {
+		snapshots = append(snapshots, m.histograms[key].snapshot())
+	}
+	return snapshots
+}
+
+// labelPairs returns the instance labels, sorted by name, followed by the
+// call's label pairs, as a flat name/value list
+func (m *ProductionMetrics) labelPairs(labels []string) []string {
+	names := make([]string, 0, len(m.labels))
+	for name := range m.labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, 2*len(names)+len(labels))
+	for _, name := range names {
+		pairs = append(pairs, name, m.labels[name])
+	}
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i], labels[i+1])
+	}
+	return pairs
+}
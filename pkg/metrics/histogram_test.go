@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestObserveWithExemplar tests that observations are bucketed and that
+// exemplars are kept on the bucket of their observation
+func TestObserveWithExemplar(t *testing.T) {
+	m := NewProductionMetrics(logging.New("test"))
+
+	m.ObserveWithExemplar("duration_seconds", 0.02, &Exemplar{TraceID: "trace-a", Value: 0.02}, "path", "/a")
+	m.ObserveWithExemplar("duration_seconds", 0.3, nil, "path", "/a")
+	m.ObserveWithExemplar("duration_seconds", 30, &Exemplar{TraceID: "trace-b", Value: 30}, "path", "/a")
+	m.ObserveWithExemplar("duration_seconds", 0.02, &Exemplar{Value: 0.02}, "path", "/b")
+
+	series := m.Histograms("duration_seconds")
+	if len(series) != 2 {
+		t.Fatalf("expected a series per label set, got %d", len(series))
+	}
+
+	a := series[0]
+	if a.Labels[1] != "/a" || a.Count != 3 || a.Sum != 30.32 {
+		t.Fatalf("unexpected series %+v", a)
+	}
+	// 0.02 falls in le=0.025, 0.3 in le=0.5 and 30 only in +Inf
+	expected := map[float64]uint64{0.01: 0, 0.025: 1, 0.25: 1, 0.5: 2, 10: 2}
+	for i, bound := range a.Buckets {
+		if want, ok := expected[bound]; ok && a.Counts[i] != want {
+			t.Errorf("expected %d observations up to %g, got %d", want, bound, a.Counts[i])
+		}
+	}
+	if a.Counts[len(a.Buckets)] != 3 {
+		t.Errorf("expected +Inf to count every observation, got %d", a.Counts[len(a.Buckets)])
+	}
+
+	for i, exemplar := range a.Exemplars {
+		switch {
+		case i < len(a.Buckets) && a.Buckets[i] == 0.025:
+			if exemplar == nil || exemplar.TraceID != "trace-a" || exemplar.Timestamp.IsZero() {
+				t.Errorf("expected trace-a on the 0.025 bucket, got %+v", exemplar)
+			}
+		case i == len(a.Buckets):
+			if exemplar == nil || exemplar.TraceID != "trace-b" {
+				t.Errorf("expected trace-b on the +Inf bucket, got %+v", exemplar)
+			}
+		case exemplar != nil:
+			t.Errorf("expected no exemplar on bucket %d, got %+v", i, exemplar)
+		}
+	}
+
+	for _, exemplar := range series[1].Exemplars {
+		if exemplar != nil {
+			t.Errorf("expected an exemplar without a trace ID to be dropped, got %+v", exemplar)
+		}
+	}
+
+	m.Reset()
+	if len(m.Histograms("duration_seconds")) != 0 {
+		t.Error("expected Reset to clear histograms")
+	}
+}
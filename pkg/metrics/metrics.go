@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -30,6 +31,13 @@ type Metrics interface {
 	GetAllStats() map[string]MetricStats
 }
 
+// Resetter is implemented by metrics that can clear recorded values
+type Resetter interface {
+	// Reset clears counters and histograms, keeping gauges, which describe
+	// current state, and returns the number of series cleared
+	Reset() int
+}
+
 // Timer tracks operation duration
 type Timer interface {
 	Duration() time.Duration
@@ -427,22 +435,30 @@ func (t *timer) Stop() time.Duration {
 	return t.end.Sub(t.start)
 }
 
-// ProductionMetrics implements the Metrics interface with real metric collection
+// ProductionMetrics implements the Metrics interface with in-memory series
+// keyed by name and labels, safe for concurrent use. Counters (Inc, Add)
+// report their running total as LastValue, gauges (Set) the value last set,
+// and histograms (Observe, Time) the last observation, with Sum and Count
+// covering every observation.
 type ProductionMetrics struct {
-	stats  map[string]*MetricStats
-	mutex  *sync.RWMutex
-	logger logging.Logger
-	prefix string
-	labels map[string]string
+	stats      map[string]*MetricStats
+	gauges     map[string]bool // Keys recorded with Set
+	histograms map[string]*histogram
+	mutex      *sync.RWMutex
+	logger     logging.Logger
+	prefix     string
+	labels     map[string]string
 }
 
 // NewProductionMetrics creates a new production metrics instance
 func NewProductionMetrics(logger logging.Logger) *ProductionMetrics {
 	return &ProductionMetrics{
-		stats:  make(map[string]*MetricStats),
-		mutex:  &sync.RWMutex{},
-		logger: logger.WithComponent("metrics"),
-		labels: make(map[string]string),
+		stats:      make(map[string]*MetricStats),
+		gauges:     make(map[string]bool),
+		histograms: make(map[string]*histogram),
+		mutex:      &sync.RWMutex{},
+		logger:     logger.WithComponent("metrics"),
+		labels:     make(map[string]string),
 	}
 }
 
@@ -451,6 +467,13 @@ func (m *ProductionMetrics) Inc(name string, labels ...string) {
 }
 
 func (m *ProductionMetrics) Add(name string, value float64, labels ...string) {
+	m.record(name, value, true, labels)
+}
+
+// record adds a counter increment or a histogram observation to its series.
+// A counter's current value is its running total; a histogram's is the
+// latest observation.
+func (m *ProductionMetrics) record(name string, value float64, cumulative bool, labels []string) {
 	key := m.buildKey(name, labels)
 
 	m.mutex.Lock()
@@ -469,6 +492,9 @@ func (m *ProductionMetrics) Add(name string, value float64, labels ...string) {
 	stats.Count++
 	stats.Sum += value
 	stats.LastValue = value
+	if cumulative {
+		stats.LastValue = stats.Sum
+	}
 	stats.LastUpdated = time.Now()
 
 	// Update min/max
@@ -514,6 +540,7 @@ func (m *ProductionMetrics) Set(name string, value float64, labels ...string) {
 		}
 		m.stats[key] = stats
 	}
+	m.gauges[key] = true
 
 	stats.LastValue = value
 	stats.LastUpdated = time.Now()
@@ -530,11 +557,11 @@ func (m *ProductionMetrics) Set(name string, value float64, labels ...string) {
 		}
 	}
 
-	// Update min/max
-	if value < stats.Min || stats.Count == 1 {
+	// Update min/max; the series was created with the first value as both
+	if value < stats.Min {
 		stats.Min = value
 	}
-	if value > stats.Max || stats.Count == 1 {
+	if value > stats.Max {
 		stats.Max = value
 	}
 
@@ -545,8 +572,7 @@ func (m *ProductionMetrics) Set(name string, value float64, labels ...string) {
 }
 
 func (m *ProductionMetrics) Observe(name string, value float64, labels ...string) {
-	// For histograms, we use Add to track observations
-	m.Add(name, value, labels...)
+	m.record(name, value, false, labels)
 }
 
 func (m *ProductionMetrics) Time(name string, labels ...string) Timer {
@@ -560,11 +586,13 @@ func (m *ProductionMetrics) Time(name string, labels ...string) Timer {
 
 func (m *ProductionMetrics) WithLabels(labels map[string]string) Metrics {
 	newMetrics := &ProductionMetrics{
-		stats:  m.stats, // Share the same stats map
-		mutex:  m.mutex,
-		logger: m.logger,
-		prefix: m.prefix,
-		labels: make(map[string]string),
+		stats:      m.stats, // Share the same stats map
+		gauges:     m.gauges,
+		histograms: m.histograms,
+		mutex:      m.mutex,
+		logger:     m.logger,
+		prefix:     m.prefix,
+		labels:     make(map[string]string),
 	}
 
 	// Copy existing labels
@@ -582,11 +610,13 @@ func (m *ProductionMetrics) WithLabels(labels map[string]string) Metrics {
 
 func (m *ProductionMetrics) WithPrefix(prefix string) Metrics {
 	newMetrics := &ProductionMetrics{
-		stats:  m.stats, // Share the same stats map
-		mutex:  m.mutex,
-		logger: m.logger,
-		prefix: m.buildPrefix(prefix),
-		labels: make(map[string]string),
+		stats:      m.stats, // Share the same stats map
+		gauges:     m.gauges,
+		histograms: m.histograms,
+		mutex:      m.mutex,
+		logger:     m.logger,
+		prefix:     m.buildPrefix(prefix),
+		labels:     make(map[string]string),
 	}
 
 	// Copy labels
@@ -622,12 +652,37 @@ func (m *ProductionMetrics) GetAllStats() map[string]MetricStats {
 	return result
 }
 
+// Reset clears all counters and histograms, including those recorded through
+// WithLabels and WithPrefix views, which share the same stats
+func (m *ProductionMetrics) Reset() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	cleared := 0
+	for key := range m.stats {
+		if !m.gauges[key] {
+			delete(m.stats, key)
+			cleared++
+		}
+	}
+	for key := range m.histograms {
+		delete(m.histograms, key)
+	}
+
+	return cleared
+}
+
 func (m *ProductionMetrics) buildKey(name string, labels []string) string {
 	key := m.prefix + name
 
-	// Add instance labels
-	for k, v := range m.labels {
-		key += ":" + k + "=" + v
+	// Add instance labels, sorted so a series always has the same key
+	names := make([]string, 0, len(m.labels))
+	for k := range m.labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		key += ":" + k + "=" + m.labels[k]
 	}
 
 	// Add method labels
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestProductionMetricsConcurrency tests that counters, gauges and
+// histograms aggregate correctly when recorded from many goroutines
+func TestProductionMetricsConcurrency(t *testing.T) {
+	m := NewProductionMetrics(logging.New("test"))
+
+	const workers, perWorker = 16, 250
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				m.Inc("requests_total", "method", "tools/call")
+				m.Add("bytes_total", 2)
+				m.Observe("latency_ms", float64(i%10))
+				m.Set("queue_depth", float64(w))
+				m.GetAllStats()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	total := float64(workers * perWorker)
+	all := m.GetAllStats()
+
+	t.Run("counters accumulate", func(t *testing.T) {
+		requests := all["requests_total:method=tools/call"]
+		if requests.Count != uint64(total) || requests.Sum != total || requests.LastValue != total {
+			t.Errorf("expected %v requests, got %+v", total, requests)
+		}
+		bytes := m.GetStats("bytes_total")
+		if bytes.Sum != 2*total || bytes.LastValue != 2*total {
+			t.Errorf("expected %v bytes, got %+v", 2*total, bytes)
+		}
+	})
+
+	t.Run("gauges track the last value", func(t *testing.T) {
+		depth := m.GetStats("queue_depth")
+		if depth.LastValue < 0 || depth.LastValue >= workers || depth.Min != 0 || depth.Max != workers-1 {
+			t.Errorf("expected a value set by one of the workers, got %+v", depth)
+		}
+	})
+
+	t.Run("histograms compute sum and count", func(t *testing.T) {
+		latency := m.GetStats("latency_ms")
+		// Each worker observes 0..9 twenty-five times
+		expectedSum := float64(workers * (perWorker / 10) * 45)
+		if latency.Count != uint64(total) || latency.Sum != expectedSum {
+			t.Errorf("expected count %v and sum %v, got %+v", total, expectedSum, latency)
+		}
+		if latency.Min != 0 || latency.Max != 9 || latency.Average != expectedSum/total {
+			t.Errorf("unexpected distribution %+v", latency)
+		}
+		if latency.LastValue > 9 {
+			t.Errorf("expected the last observation rather than a total, got %v", latency.LastValue)
+		}
+	})
+
+	t.Run("labelled views share series with stable keys", func(t *testing.T) {
+		view := m.WithLabels(map[string]string{"zone": "b", "region": "eu"})
+		for i := 0; i < 20; i++ {
+			view.Inc("view_total")
+		}
+		if got := m.GetAllStats()["view_total:region=eu:zone=b"]; got.LastValue != 20 {
+			t.Errorf("expected one series for the view, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Helpers for ConfigValidator implementations. Each checks one optional
+// setting, accepting both the Go types of the built-in defaults and the
+// types JSON decoding produces, and returns nil when the setting is absent.
+
+func appendConfigError(errs []error, err error) []error {
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func requireConfigString(config map[string]interface{}, key string) error {
+	value, exists := config[key]
+	if !exists {
+		return nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("%s must be a string, got %T", key, value)
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("%s must not be empty", key)
+	}
+	return nil
+}
+
+func requireConfigBool(config map[string]interface{}, key string) error {
+	value, exists := config[key]
+	if !exists {
+		return nil
+	}
+	if _, ok := value.(bool); !ok {
+		return fmt.Errorf("%s must be a boolean, got %T", key, value)
+	}
+	return nil
+}
+
+func requireConfigNumber(config map[string]interface{}, key string, minimum float64) error {
+	value, exists := config[key]
+	if !exists {
+		return nil
+	}
+
+	var number float64
+	switch n := value.(type) {
+	case float64:
+		number = n
+	case int:
+		number = float64(n)
+	case int64:
+		number = float64(n)
+	default:
+		return fmt.Errorf("%s must be a number, got %T", key, value)
+	}
+	if number < minimum {
+		return fmt.Errorf("%s must be at least %v, got %v", key, minimum, number)
+	}
+	return nil
+}
+
+func requireConfigExtensions(config map[string]interface{}, key string) error {
+	value, exists := config[key]
+	if !exists {
+		return nil
+	}
+
+	var extensions []string
+	switch list := value.(type) {
+	case []string:
+		extensions = list
+	case []interface{}:
+		for _, item := range list {
+			extension, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%s must be a list of strings, got a %T", key, item)
+			}
+			extensions = append(extensions, extension)
+		}
+	default:
+		return fmt.Errorf("%s must be a list of strings, got %T", key, value)
+	}
+
+	for _, extension := range extensions {
+		if !strings.HasPrefix(extension, ".") {
+			return fmt.Errorf("%s entries must start with a dot, got %q", key, extension)
+		}
+	}
+	return nil
+}
@@ -90,6 +90,16 @@ func (es *EditorService) Initialize(ctx context.Context, config PluginConfig) er
 	return nil
 }
 
+// ValidateConfig checks the settings Initialize reads
+func (es *EditorService) ValidateConfig(config map[string]interface{}) []error {
+	var errs []error
+	errs = appendConfigError(errs, requireConfigString(config, "working_dir"))
+	errs = appendConfigError(errs, requireConfigNumber(config, "max_file_size", 1))
+	errs = appendConfigError(errs, requireConfigBool(config, "backup_enabled"))
+	errs = appendConfigError(errs, requireConfigExtensions(config, "allowed_extensions"))
+	return errs
+}
+
 // GetTools returns the tools provided by the editor service
 func (es *EditorService) GetTools() []registry.ToolDefinition {
 	return []registry.ToolDefinition{
@@ -451,11 +461,13 @@ func (es *EditorService) ReadResource(ctx context.Context, uri string) (interfac
 		es.LogResourceAccess(uri, time.Since(start), nil)
 	}()
 
-	switch uri {
-	case "file_tree":
+	switch {
+	case uri == "file_tree":
 		return es.getFileTree()
-	case "file_stats":
+	case uri == "file_stats":
 		return es.getFileStats()
+	case strings.HasPrefix(uri, editorFileResourcePrefix):
+		return es.readFileResource(uri)
 	default:
 		return nil, fmt.Errorf("unknown resource: %s", uri)
 	}
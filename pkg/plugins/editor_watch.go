@@ -0,0 +1,115 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// editorFileResourcePrefix addresses a file under the working directory as
+	// a resource, e.g. file/src/main.go
+	editorFileResourcePrefix = "file/"
+
+	// editorWatchDebounce coalesces the burst of events a single save produces
+	editorWatchDebounce = 50 * time.Millisecond
+)
+
+// readFileResource reads a file/<path> resource through the read_file tool
+func (es *EditorService) readFileResource(uri string) (interface{}, error) {
+	args, err := json.Marshal(map[string]string{"path": strings.TrimPrefix(uri, editorFileResourcePrefix)})
+	if err != nil {
+		return nil, err
+	}
+	return es.handleReadFile(args)
+}
+
+// WatchResource watches a file/<path> resource for changes on disk. The
+// parent directory is watched rather than the file, since editors often save
+// by replacing the file, which would drop a watch on the file itself.
+func (es *EditorService) WatchResource(uri string, onChange func(uri string)) (func(), error) {
+	path, ok := strings.CutPrefix(uri, editorFileResourcePrefix)
+	if !ok {
+		return nil, fmt.Errorf("resource %s cannot be watched, only %s<path> resources can", uri, editorFileResourcePrefix)
+	}
+	if err := es.validatePath(path); err != nil {
+		return nil, err
+	}
+
+	target := filepath.Clean(filepath.Join(es.workingDir, path))
+	if _, err := os.Stat(target); err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(target)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	go es.runWatch(watcher, target, uri, onChange)
+
+	es.metrics.Inc("editor_watches_started_total")
+	es.logger.Debug("editor_watch_started", "uri", uri, "path", target)
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			watcher.Close()
+			es.logger.Debug("editor_watch_stopped", "uri", uri)
+		})
+	}
+	return stop, nil
+}
+
+// runWatch reports changes to target until the watcher is closed
+func (es *EditorService) runWatch(watcher *fsnotify.Watcher, target, uri string, onChange func(uri string)) {
+	var mutex sync.Mutex
+	var pending *time.Timer
+	defer func() {
+		mutex.Lock()
+		if pending != nil {
+			pending.Stop()
+		}
+		mutex.Unlock()
+	}()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != target || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			mutex.Lock()
+			if pending == nil {
+				pending = time.AfterFunc(editorWatchDebounce, func() {
+					mutex.Lock()
+					pending = nil
+					mutex.Unlock()
+					es.metrics.Inc("editor_watch_changes_total")
+					onChange(uri)
+				})
+			}
+			mutex.Unlock()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			es.logger.Warn("editor_watch_error", "uri", uri, "error", err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestEditorWatchResource tests that modifying a watched file reports a change until the watch stops
+func TestEditorWatchResource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("one"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	service := NewEditorService()
+	err := service.Initialize(context.Background(), PluginConfig{
+		Name:    "editor",
+		Config:  map[string]interface{}{"working_dir": dir},
+		Logger:  logging.New("test"),
+		Metrics: &mockMetrics{},
+	})
+	if err != nil {
+		t.Fatalf("failed to initialize editor: %v", err)
+	}
+
+	changes := make(chan string, 10)
+	stop, err := service.WatchResource("file/notes.txt", func(uri string) { changes <- uri })
+	if err != nil {
+		t.Fatalf("failed to watch resource: %v", err)
+	}
+
+	// Changes to other files in the directory are not reported
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("two"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+
+	select {
+	case uri := <-changes:
+		if uri != "file/notes.txt" {
+			t.Errorf("expected change for file/notes.txt, got %s", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change notification after modifying the file")
+	}
+
+	stop()
+	stop()
+	for len(changes) > 0 {
+		<-changes
+	}
+	if err := os.WriteFile(path, []byte("three"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+	select {
+	case uri := <-changes:
+		t.Errorf("expected no notification after stop, got %s", uri)
+	case <-time.After(4 * editorWatchDebounce):
+	}
+
+	t.Run("rejects unwatchable resources", func(t *testing.T) {
+		if _, err := service.WatchResource("workspace", func(string) {}); err == nil {
+			t.Error("expected non-file resource to be rejected")
+		}
+		if _, err := service.WatchResource("file/../secret.txt", func(string) {}); err == nil {
+			t.Error("expected path traversal to be rejected")
+		}
+		if _, err := service.WatchResource("file/missing.txt", func(string) {}); err == nil {
+			t.Error("expected missing file to be rejected")
+		}
+	})
+}
@@ -61,6 +61,16 @@ func (gs *GitService) Initialize(ctx context.Context, config PluginConfig) error
 	return nil
 }
 
+// ValidateConfig checks the settings Initialize reads
+func (gs *GitService) ValidateConfig(config map[string]interface{}) []error {
+	var errs []error
+	errs = appendConfigError(errs, requireConfigString(config, "working_dir"))
+	errs = appendConfigError(errs, requireConfigString(config, "git_path"))
+	errs = appendConfigError(errs, requireConfigBool(config, "auto_detect"))
+	errs = appendConfigError(errs, requireConfigBool(config, "safe_mode"))
+	return errs
+}
+
 // GetTools returns the tools provided by the git service
 func (gs *GitService) GetTools() []registry.ToolDefinition {
 	return []registry.ToolDefinition{
@@ -12,20 +12,72 @@ import (
 
 // PluginLoader manages loading and registration of all plugins
 type PluginLoader struct {
-	manager *PluginManager
-	logger  logging.Logger
-	metrics metrics.Metrics
+	manager      *PluginManager
+	factories    map[string]func() Plugin
+	stdioPlugins []StdioPluginConfig
+	logger       logging.Logger
+	metrics      metrics.Metrics
 }
 
 // NewPluginLoader creates a new plugin loader
 func NewPluginLoader(logger logging.Logger, metrics metrics.Metrics) *PluginLoader {
 	return &PluginLoader{
 		manager: NewPluginManager(logger, metrics),
+		factories: map[string]func() Plugin{
+			"memory": func() Plugin { return NewMemoryService() },
+			"git":    func() Plugin { return NewGitService() },
+			"editor": func() Plugin { return NewEditorService() },
+		},
 		logger:  logger.WithComponent("plugin_loader"),
 		metrics: metrics.WithPrefix("plugin_loader"),
 	}
 }
 
+// RegisterPluginFactory sets the constructor used to create fresh instances of a plugin on reload
+func (pl *PluginLoader) RegisterPluginFactory(name string, factory func() Plugin) {
+	pl.factories[name] = factory
+}
+
+// RegisterStdioPlugin adds an external MCP server, launched as a subprocess,
+// to the plugins loaded by LoadAllPlugins
+func (pl *PluginLoader) RegisterStdioPlugin(config StdioPluginConfig) {
+	pl.stdioPlugins = append(pl.stdioPlugins, config)
+	pl.factories[config.Name] = func() Plugin { return NewStdioPlugin(config) }
+}
+
+// KnownPlugin reports whether name is a built-in or registered stdio plugin
+// that LoadAllPlugins will load
+func (pl *PluginLoader) KnownPlugin(name string) bool {
+	_, exists := pl.factories[name]
+	return exists
+}
+
+// ReloadPlugin replaces a loaded plugin with a freshly constructed and initialized
+// instance. On failure the previous instance keeps serving.
+func (pl *PluginLoader) ReloadPlugin(ctx context.Context, name string, config PluginConfig) error {
+	factory, exists := pl.factories[name]
+	if !exists {
+		return fmt.Errorf("plugin %s has no registered factory", name)
+	}
+	if _, loaded := pl.manager.GetPlugin(name); !loaded {
+		return fmt.Errorf("plugin %s not found", name)
+	}
+
+	pl.logger.Info("plugin_reload_started", "plugin", name)
+
+	if err := pl.manager.ReplacePlugin(ctx, factory(), config); err != nil {
+		pl.metrics.Inc("plugin_reloads_total", "plugin", name, "success", "false")
+		pl.logger.Error("plugin_reload_failed",
+			"plugin", name,
+			"error", err)
+		return err
+	}
+
+	pl.metrics.Inc("plugin_reloads_total", "plugin", name, "success", "true")
+	pl.logger.Info("plugin_reload_completed", "plugin", name)
+	return nil
+}
+
 // LoadAllPlugins loads and registers all built-in plugins
 func (pl *PluginLoader) LoadAllPlugins(ctx context.Context, configs map[string]PluginConfig) error {
 	pl.logger.Info("loading_built_in_plugins")
@@ -36,6 +88,9 @@ func (pl *PluginLoader) LoadAllPlugins(ctx context.Context, configs map[string]P
 		NewGitService(),
 		NewEditorService(),
 	}
+	for _, config := range pl.stdioPlugins {
+		plugins = append(plugins, NewStdioPlugin(config))
+	}
 
 	// Register each plugin
 	for _, plugin := range plugins {
@@ -57,8 +112,11 @@ func (pl *PluginLoader) LoadAllPlugins(ctx context.Context, configs map[string]P
 		return fmt.Errorf("failed to initialize plugins: %w", err)
 	}
 
-	pl.metrics.Set("plugins_loaded_count", float64(len(plugins)))
-	pl.logger.Info("all_plugins_loaded", "count", len(plugins))
+	failed := pl.manager.FailedPlugins()
+	pl.metrics.Set("plugins_loaded_count", float64(len(plugins)-len(failed)))
+	pl.logger.Info("all_plugins_loaded",
+		"count", len(plugins)-len(failed),
+		"failed_count", len(failed))
 
 	return nil
 }
@@ -68,11 +126,22 @@ func (pl *PluginLoader) GetPluginManager() *PluginManager {
 	return pl.manager
 }
 
-// CreateRegisteredServices converts plugins to registered services for the gateway
+// CreateRegisteredServices converts plugins to registered services for the
+// gateway, leaving out plugins that failed to initialize
 func (pl *PluginLoader) CreateRegisteredServices() []*registry.RegisteredService {
 	var services []*registry.RegisteredService
 
-	for _, plugin := range pl.manager.ListPlugins() {
+	failed := pl.manager.FailedPlugins()
+	for name, plugin := range pl.manager.ListPlugins() {
+		if _, isFailed := failed[name]; isFailed {
+			continue
+		}
+
+		pluginType := "built_in"
+		if _, ok := plugin.(*StdioPlugin); ok {
+			pluginType = "stdio"
+		}
+
 		service := &registry.RegisteredService{
 			ID:          fmt.Sprintf("plugin_%s", plugin.Name()),
 			Name:        plugin.Name(),
@@ -95,7 +164,7 @@ func (pl *PluginLoader) CreateRegisteredServices() []*registry.RegisteredService
 
 			// Metadata
 			Metadata: map[string]interface{}{
-				"plugin_type":    "built_in",
+				"plugin_type":    pluginType,
 				"plugin_version": plugin.Version(),
 				"capabilities": map[string]interface{}{
 					"tools_count":     len(plugin.GetTools()),
@@ -103,7 +172,7 @@ func (pl *PluginLoader) CreateRegisteredServices() []*registry.RegisteredService
 					"prompts_count":   len(plugin.GetPrompts()),
 				},
 			},
-			Tags: []string{"plugin", "built_in", plugin.Name()},
+			Tags: []string{"plugin", pluginType, plugin.Name()},
 
 			// Metrics
 			Metrics: registry.ServiceMetrics{
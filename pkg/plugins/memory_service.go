@@ -67,6 +67,16 @@ func (ms *MemoryService) Initialize(ctx context.Context, config PluginConfig) er
 	return nil
 }
 
+// ValidateConfig checks the settings Initialize reads
+func (ms *MemoryService) ValidateConfig(config map[string]interface{}) []error {
+	var errs []error
+	errs = appendConfigError(errs, requireConfigString(config, "data_dir"))
+	errs = appendConfigError(errs, requireConfigBool(config, "auto_save"))
+	errs = appendConfigError(errs, requireConfigNumber(config, "max_keys", 0))
+	errs = appendConfigError(errs, requireConfigNumber(config, "default_ttl", 0))
+	return errs
+}
+
 // GetTools returns the tools provided by the memory service
 func (ms *MemoryService) GetTools() []registry.ToolDefinition {
 	return []registry.ToolDefinition{
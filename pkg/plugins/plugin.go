@@ -61,6 +61,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/osakka/mcpeg/internal/registry"
@@ -98,12 +101,42 @@ type Plugin interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// ResourceWatcher is implemented by plugins whose resources can change
+// outside the gateway, such as files on disk. WatchResource calls onChange
+// with the resource URI after each change until stop is called.
+type ResourceWatcher interface {
+	WatchResource(uri string, onChange func(uri string)) (stop func(), err error)
+}
+
+// RootsProvider is implemented by plugins that operate on part of the
+// filesystem or a workspace; their roots are aggregated into MCP roots/list
+type RootsProvider interface {
+	GetRoots() []Root
+}
+
+// ConfigValidator is implemented by plugins that can check a configuration
+// before it is applied, so a bad update is rejected without touching the
+// running instance. ValidateConfig returns one error per invalid setting.
+type ConfigValidator interface {
+	ValidateConfig(config map[string]interface{}) []error
+}
+
+// Root is a filesystem or workspace root, identified by a file:// URI
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
 // PluginConfig contains plugin configuration
 type PluginConfig struct {
 	Name    string                 `json:"name"`
 	Config  map[string]interface{} `json:"config"`
 	Logger  logging.Logger         `json:"-"`
 	Metrics metrics.Metrics        `json:"-"`
+
+	// Required plugins fail startup when they cannot be initialized; other
+	// plugins are excluded from routing and the gateway serves the rest
+	Required bool `json:"required"`
 }
 
 // BasePlugin provides common functionality for all plugins
@@ -197,17 +230,24 @@ func (p *BasePlugin) LogResourceAccess(resourceURI string, duration time.Duratio
 
 // PluginManager manages the lifecycle of plugins
 type PluginManager struct {
-	plugins map[string]Plugin
-	logger  logging.Logger
-	metrics metrics.Metrics
+	plugins  map[string]Plugin
+	inFlight map[string]*sync.WaitGroup
+	disabled map[string]string // plugin name -> reason it stopped receiving traffic
+	failed   map[string]string // plugin name -> initialization error, cleared by a successful reload
+	mutex    sync.RWMutex
+	logger   logging.Logger
+	metrics  metrics.Metrics
 }
 
 // NewPluginManager creates a new plugin manager
 func NewPluginManager(logger logging.Logger, metrics metrics.Metrics) *PluginManager {
 	return &PluginManager{
-		plugins: make(map[string]Plugin),
-		logger:  logger.WithComponent("plugin_manager"),
-		metrics: metrics.WithPrefix("plugin_manager"),
+		plugins:  make(map[string]Plugin),
+		inFlight: make(map[string]*sync.WaitGroup),
+		disabled: make(map[string]string),
+		failed:   make(map[string]string),
+		logger:   logger.WithComponent("plugin_manager"),
+		metrics:  metrics.WithPrefix("plugin_manager"),
 	}
 }
 
@@ -215,11 +255,15 @@ func NewPluginManager(logger logging.Logger, metrics metrics.Metrics) *PluginMan
 func (pm *PluginManager) RegisterPlugin(plugin Plugin) error {
 	name := plugin.Name()
 
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
 	if _, exists := pm.plugins[name]; exists {
 		return fmt.Errorf("plugin %s already registered", name)
 	}
 
 	pm.plugins[name] = plugin
+	pm.inFlight[name] = &sync.WaitGroup{}
 
 	pm.logger.Info("plugin_registered",
 		"plugin", name,
@@ -234,7 +278,7 @@ func (pm *PluginManager) RegisterPlugin(plugin Plugin) error {
 
 // InitializePlugin initializes a specific plugin
 func (pm *PluginManager) InitializePlugin(ctx context.Context, name string, config PluginConfig) error {
-	plugin, exists := pm.plugins[name]
+	plugin, exists := pm.GetPlugin(name)
 	if !exists {
 		return fmt.Errorf("plugin %s not found", name)
 	}
@@ -252,33 +296,239 @@ func (pm *PluginManager) InitializePlugin(ctx context.Context, name string, conf
 	return nil
 }
 
-// InitializeAllPlugins initializes all registered plugins
+// InitializeAllPlugins initializes all registered plugins. A plugin that fails
+// to initialize is marked failed and excluded from routing while the others
+// keep serving; an error is returned only if a required plugin failed.
 func (pm *PluginManager) InitializeAllPlugins(ctx context.Context, configs map[string]PluginConfig) error {
-	for name := range pm.plugins {
+	names := pm.GetPlugins()
+	sort.Strings(names)
+
+	var succeeded, failed, requiredFailed []string
+	for _, name := range names {
 		config, exists := configs[name]
 		if !exists {
 			config = PluginConfig{Name: name, Config: make(map[string]interface{})}
 		}
 
 		if err := pm.InitializePlugin(ctx, name, config); err != nil {
-			return err
+			pm.mutex.Lock()
+			pm.failed[name] = err.Error()
+			pm.mutex.Unlock()
+
+			pm.metrics.Inc("plugin_initialization_failures_total", "plugin", name, "required", fmt.Sprintf("%t", config.Required))
+			failed = append(failed, name)
+			if config.Required {
+				requiredFailed = append(requiredFailed, name)
+			}
+			continue
 		}
+		succeeded = append(succeeded, name)
 	}
 
-	pm.logger.Info("all_plugins_initialized",
-		"plugin_count", len(pm.plugins))
+	pm.metrics.Set("plugins_failed_count", float64(len(failed)))
+	pm.logger.Info("plugin_initialization_summary",
+		"plugin_count", len(names),
+		"succeeded", succeeded,
+		"failed", failed)
 
+	if len(requiredFailed) > 0 {
+		return fmt.Errorf("required plugins failed to initialize: %s", strings.Join(requiredFailed, ", "))
+	}
 	return nil
 }
 
+// FailedPlugins returns the plugins that failed to initialize and why. They
+// stay registered so a reload can recover them, but receive no traffic.
+func (pm *PluginManager) FailedPlugins() map[string]string {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	result := make(map[string]string, len(pm.failed))
+	for name, reason := range pm.failed {
+		result[name] = reason
+	}
+	return result
+}
+
 // GetPlugin returns a plugin by name
 func (pm *PluginManager) GetPlugin(name string) (Plugin, bool) {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
 	plugin, exists := pm.plugins[name]
 	return plugin, exists
 }
 
+// AcquirePlugin returns a plugin for a call and a release function that must be
+// called when the call completes. ReplacePlugin waits for acquired calls to drain
+// before shutting down the previous instance.
+func (pm *PluginManager) AcquirePlugin(name string) (Plugin, func(), bool) {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	plugin, exists := pm.plugins[name]
+	if !exists {
+		return nil, func() {}, false
+	}
+
+	wg := pm.inFlight[name]
+	wg.Add(1)
+	return plugin, wg.Done, true
+}
+
+// ReplacePlugin swaps a registered plugin for a new instance. The new instance is
+// initialized and health checked before the swap; if either fails it is shut down
+// and the current instance keeps serving. After the swap, in-flight calls on the
+// previous instance are drained (bounded by ctx) before it is shut down.
+func (pm *PluginManager) ReplacePlugin(ctx context.Context, plugin Plugin, config PluginConfig) error {
+	name := plugin.Name()
+
+	if _, exists := pm.GetPlugin(name); !exists {
+		return fmt.Errorf("plugin %s not found", name)
+	}
+
+	config.Logger = pm.logger
+	config.Metrics = pm.metrics
+
+	if err := plugin.Initialize(ctx, config); err != nil {
+		pm.metrics.Inc("plugin_replacements_failed_total", "plugin", name)
+		pm.logger.Error("plugin_replacement_initialization_failed",
+			"plugin", name,
+			"error", err)
+		return fmt.Errorf("failed to initialize plugin %s: %w", name, err)
+	}
+
+	if err := plugin.HealthCheck(ctx); err != nil {
+		pm.metrics.Inc("plugin_replacements_failed_total", "plugin", name)
+		pm.logger.Error("plugin_replacement_health_check_failed",
+			"plugin", name,
+			"error", err)
+		if shutdownErr := plugin.Shutdown(ctx); shutdownErr != nil {
+			pm.logger.Warn("plugin_replacement_cleanup_failed",
+				"plugin", name,
+				"error", shutdownErr)
+		}
+		return fmt.Errorf("plugin %s failed health check: %w", name, err)
+	}
+
+	pm.mutex.Lock()
+	oldPlugin := pm.plugins[name]
+	oldInFlight := pm.inFlight[name]
+	_, oldFailed := pm.failed[name]
+	_, wasDisabled := pm.disabled[name]
+	pm.plugins[name] = plugin
+	pm.inFlight[name] = &sync.WaitGroup{}
+	delete(pm.failed, name)
+	pm.metrics.Set("plugins_failed_count", float64(len(pm.failed)))
+	// The new instance passed its health check, so it starts out enabled
+	delete(pm.disabled, name)
+	pm.metrics.Set("plugins_disabled_count", float64(len(pm.disabled)))
+	pm.mutex.Unlock()
+
+	if wasDisabled {
+		pm.logger.Info("plugin_enabled_by_replacement", "plugin", name)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		oldInFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		pm.logger.Warn("plugin_replacement_drain_timeout",
+			"plugin", name,
+			"error", ctx.Err())
+	}
+
+	// An instance that failed to initialize has nothing to shut down
+	if !oldFailed {
+		if err := oldPlugin.Shutdown(ctx); err != nil {
+			pm.logger.Warn("plugin_replacement_old_shutdown_failed",
+				"plugin", name,
+				"error", err)
+		}
+	}
+
+	pm.metrics.Inc("plugin_replacements_total", "plugin", name)
+	pm.logger.Info("plugin_replaced",
+		"plugin", name,
+		"old_version", oldPlugin.Version(),
+		"new_version", plugin.Version())
+
+	return nil
+}
+
+// DisablePlugin stops routing traffic to a plugin without unloading it. The
+// plugin keeps being health checked so it can be re-enabled. Returns false if
+// the plugin is unknown or already disabled.
+func (pm *PluginManager) DisablePlugin(name, reason string) bool {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if _, exists := pm.plugins[name]; !exists {
+		return false
+	}
+	if _, disabled := pm.disabled[name]; disabled {
+		return false
+	}
+
+	pm.disabled[name] = reason
+	pm.metrics.Set("plugins_disabled_count", float64(len(pm.disabled)))
+	return true
+}
+
+// EnablePlugin resumes routing traffic to a disabled plugin. Returns false if
+// the plugin was not disabled.
+func (pm *PluginManager) EnablePlugin(name string) bool {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if _, disabled := pm.disabled[name]; !disabled {
+		return false
+	}
+
+	delete(pm.disabled, name)
+	pm.metrics.Set("plugins_disabled_count", float64(len(pm.disabled)))
+	return true
+}
+
+// DisabledReason returns why a plugin is disabled or failed to initialize,
+// or false if it is enabled
+func (pm *PluginManager) DisabledReason(name string) (string, bool) {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	if reason, failed := pm.failed[name]; failed {
+		return "initialization failed: " + reason, true
+	}
+	reason, disabled := pm.disabled[name]
+	return reason, disabled
+}
+
+// ListEnabledPlugins returns the registered plugins that are receiving traffic
+func (pm *PluginManager) ListEnabledPlugins() map[string]Plugin {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	result := make(map[string]Plugin)
+	for name, plugin := range pm.plugins {
+		_, disabled := pm.disabled[name]
+		_, failed := pm.failed[name]
+		if !disabled && !failed {
+			result[name] = plugin
+		}
+	}
+	return result
+}
+
 // ListPlugins returns all registered plugins
 func (pm *PluginManager) ListPlugins() map[string]Plugin {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
 	result := make(map[string]Plugin)
 	for name, plugin := range pm.plugins {
 		result[name] = plugin
@@ -288,6 +538,9 @@ func (pm *PluginManager) ListPlugins() map[string]Plugin {
 
 // GetPlugins returns a list of all registered plugin names
 func (pm *PluginManager) GetPlugins() []string {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
 	names := make([]string, 0, len(pm.plugins))
 	for name := range pm.plugins {
 		names = append(names, name)
@@ -299,7 +552,12 @@ func (pm *PluginManager) GetPlugins() []string {
 func (pm *PluginManager) ShutdownAllPlugins(ctx context.Context) error {
 	var lastError error
 
-	for name, plugin := range pm.plugins {
+	failed := pm.FailedPlugins()
+	for name, plugin := range pm.ListPlugins() {
+		// Plugins that failed to initialize have nothing to shut down
+		if _, isFailed := failed[name]; isFailed {
+			continue
+		}
 		if err := plugin.Shutdown(ctx); err != nil {
 			pm.logger.Error("plugin_shutdown_failed",
 				"plugin", name,
@@ -316,7 +574,7 @@ func (pm *PluginManager) ShutdownAllPlugins(ctx context.Context) error {
 func (pm *PluginManager) GetAllTools() []registry.ToolDefinition {
 	var tools []registry.ToolDefinition
 
-	for _, plugin := range pm.plugins {
+	for _, plugin := range pm.ListEnabledPlugins() {
 		pluginTools := plugin.GetTools()
 		tools = append(tools, pluginTools...)
 	}
@@ -328,7 +586,7 @@ func (pm *PluginManager) GetAllTools() []registry.ToolDefinition {
 func (pm *PluginManager) GetAllResources() []registry.ResourceDefinition {
 	var resources []registry.ResourceDefinition
 
-	for _, plugin := range pm.plugins {
+	for _, plugin := range pm.ListEnabledPlugins() {
 		pluginResources := plugin.GetResources()
 		resources = append(resources, pluginResources...)
 	}
@@ -339,12 +597,22 @@ func (pm *PluginManager) GetAllResources() []registry.ResourceDefinition {
 // CallTool calls a tool from any plugin
 func (pm *PluginManager) CallTool(ctx context.Context, toolName string, args json.RawMessage) (interface{}, error) {
 	// Find which plugin has this tool
-	for _, plugin := range pm.plugins {
+	for name, plugin := range pm.ListPlugins() {
 		for _, tool := range plugin.GetTools() {
 			if tool.Name == toolName {
+				if reason, disabled := pm.DisabledReason(name); disabled {
+					return nil, fmt.Errorf("plugin %s is disabled: %s", name, reason)
+				}
+
+				plugin, release, exists := pm.AcquirePlugin(name)
+				if !exists {
+					break
+				}
+
 				start := time.Now()
 				result, err := plugin.CallTool(ctx, toolName, args)
 				duration := time.Since(start)
+				release()
 
 				pm.logger.Info("plugin_tool_called",
 					"plugin", plugin.Name(),
@@ -3,9 +3,11 @@ package plugins
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/osakka/mcpeg/internal/registry"
 	"github.com/osakka/mcpeg/pkg/logging"
 	"github.com/osakka/mcpeg/pkg/metrics"
 )
@@ -325,6 +327,223 @@ func TestPluginManager(t *testing.T) {
 	})
 }
 
+// TestPluginReload tests replacing a single plugin without disturbing others
+func TestPluginReload(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	ctx := context.Background()
+
+	newLoader := func(t *testing.T) (*PluginLoader, *reloadTestPlugin) {
+		loader := NewPluginLoader(logger, mockMetrics)
+		original := newReloadTestPlugin("1.0.0", nil)
+		if err := loader.GetPluginManager().RegisterPlugin(original); err != nil {
+			t.Fatalf("failed to register plugin: %v", err)
+		}
+		if err := loader.GetPluginManager().InitializePlugin(ctx, "reloadable", PluginConfig{Name: "reloadable"}); err != nil {
+			t.Fatalf("failed to initialize plugin: %v", err)
+		}
+		return loader, original
+	}
+
+	t.Run("successful reload swaps instance", func(t *testing.T) {
+		loader, original := newLoader(t)
+		loader.RegisterPluginFactory("reloadable", func() Plugin { return newReloadTestPlugin("2.0.0", nil) })
+
+		if err := loader.ReloadPlugin(ctx, "reloadable", PluginConfig{Name: "reloadable"}); err != nil {
+			t.Fatalf("reload failed: %v", err)
+		}
+
+		plugin, _ := loader.GetPluginManager().GetPlugin("reloadable")
+		if plugin.Version() != "2.0.0" {
+			t.Errorf("expected reloaded version 2.0.0, got %s", plugin.Version())
+		}
+		if err := plugin.HealthCheck(ctx); err != nil {
+			t.Errorf("expected reloaded plugin to be healthy: %v", err)
+		}
+		if err := original.HealthCheck(ctx); err == nil {
+			t.Error("expected previous instance to be shut down")
+		}
+	})
+
+	t.Run("reloaded plugin is enabled again", func(t *testing.T) {
+		loader, _ := newLoader(t)
+		loader.RegisterPluginFactory("reloadable", func() Plugin { return newReloadTestPlugin("2.0.0", nil) })
+		loader.GetPluginManager().DisablePlugin("reloadable", "unhealthy for 3 consecutive health checks")
+
+		if err := loader.ReloadPlugin(ctx, "reloadable", PluginConfig{Name: "reloadable"}); err != nil {
+			t.Fatalf("reload failed: %v", err)
+		}
+		if reason, disabled := loader.GetPluginManager().DisabledReason("reloadable"); disabled {
+			t.Errorf("expected the reloaded plugin to be enabled, still disabled: %s", reason)
+		}
+	})
+
+	t.Run("failed initialization keeps previous instance", func(t *testing.T) {
+		loader, original := newLoader(t)
+		loader.RegisterPluginFactory("reloadable", func() Plugin {
+			return newReloadTestPlugin("2.0.0", fmt.Errorf("bad configuration"))
+		})
+
+		if err := loader.ReloadPlugin(ctx, "reloadable", PluginConfig{Name: "reloadable"}); err == nil {
+			t.Fatal("expected reload to fail")
+		}
+
+		plugin, _ := loader.GetPluginManager().GetPlugin("reloadable")
+		if plugin != Plugin(original) {
+			t.Errorf("expected previous instance to remain registered, got version %s", plugin.Version())
+		}
+		if err := original.HealthCheck(ctx); err != nil {
+			t.Errorf("expected previous instance to keep serving: %v", err)
+		}
+	})
+
+	t.Run("in-flight calls drain before shutdown", func(t *testing.T) {
+		loader, original := newLoader(t)
+		loader.RegisterPluginFactory("reloadable", func() Plugin { return newReloadTestPlugin("2.0.0", nil) })
+
+		_, release, _ := loader.GetPluginManager().AcquirePlugin("reloadable")
+		done := make(chan error, 1)
+		go func() {
+			done <- loader.ReloadPlugin(ctx, "reloadable", PluginConfig{Name: "reloadable"})
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		if err := original.HealthCheck(ctx); err != nil {
+			t.Error("expected previous instance to stay up while a call is in flight")
+		}
+
+		release()
+		if err := <-done; err != nil {
+			t.Fatalf("reload failed: %v", err)
+		}
+		if err := original.HealthCheck(ctx); err == nil {
+			t.Error("expected previous instance to be shut down after draining")
+		}
+	})
+
+	t.Run("unknown plugin", func(t *testing.T) {
+		loader := NewPluginLoader(logger, mockMetrics)
+		if err := loader.ReloadPlugin(ctx, "missing", PluginConfig{}); err == nil {
+			t.Error("expected error reloading unknown plugin")
+		}
+	})
+}
+
+// TestPartialPluginInitialization tests that a plugin failing to initialize is excluded while the others serve
+func TestPartialPluginInitialization(t *testing.T) {
+	logger := logging.New("test")
+	mockMetrics := &mockMetrics{}
+	ctx := context.Background()
+
+	newLoader := func(t *testing.T) *PluginLoader {
+		loader := NewPluginLoader(logger, mockMetrics)
+		manager := loader.GetPluginManager()
+		if err := manager.RegisterPlugin(NewMemoryService()); err != nil {
+			t.Fatalf("failed to register memory plugin: %v", err)
+		}
+		if err := manager.RegisterPlugin(newReloadTestPlugin("1.0.0", fmt.Errorf("missing credentials"))); err != nil {
+			t.Fatalf("failed to register failing plugin: %v", err)
+		}
+		return loader
+	}
+
+	t.Run("failed plugin is excluded", func(t *testing.T) {
+		loader := newLoader(t)
+		manager := loader.GetPluginManager()
+
+		if err := manager.InitializeAllPlugins(ctx, map[string]PluginConfig{}); err != nil {
+			t.Fatalf("expected startup to continue past an optional plugin failure: %v", err)
+		}
+
+		failed := manager.FailedPlugins()
+		if len(failed) != 1 || failed["reloadable"] == "" {
+			t.Fatalf("expected only reloadable to be marked failed, got %v", failed)
+		}
+		enabled := manager.ListEnabledPlugins()
+		if _, ok := enabled["memory"]; !ok {
+			t.Error("expected memory to be enabled")
+		}
+		if _, ok := enabled["reloadable"]; ok {
+			t.Error("expected failed plugin to be excluded from routing")
+		}
+		if reason, disabled := manager.DisabledReason("reloadable"); !disabled || reason == "" {
+			t.Error("expected failed plugin to report a disabled reason")
+		}
+
+		if _, err := manager.CallTool(ctx, "memory_list", json.RawMessage(`{}`)); err != nil {
+			t.Errorf("expected memory plugin to serve calls: %v", err)
+		}
+
+		services := loader.CreateRegisteredServices()
+		if len(services) != 1 || services[0].Name != "memory" {
+			t.Errorf("expected only memory to be registered as a service, got %d services", len(services))
+		}
+
+		// Health recovery must not re-enable a plugin that never initialized
+		manager.EnablePlugin("reloadable")
+		if _, disabled := manager.DisabledReason("reloadable"); !disabled {
+			t.Error("expected failed plugin to stay excluded after EnablePlugin")
+		}
+
+		if err := manager.ReplacePlugin(ctx, newReloadTestPlugin("1.0.1", nil), PluginConfig{Name: "reloadable"}); err != nil {
+			t.Fatalf("reload failed: %v", err)
+		}
+		if _, ok := manager.ListEnabledPlugins()["reloadable"]; !ok {
+			t.Error("expected a successful reload to recover the failed plugin")
+		}
+	})
+
+	t.Run("required plugin failure fails startup", func(t *testing.T) {
+		manager := newLoader(t).GetPluginManager()
+
+		err := manager.InitializeAllPlugins(ctx, map[string]PluginConfig{
+			"reloadable": {Name: "reloadable", Required: true},
+		})
+		if err == nil {
+			t.Fatal("expected startup to fail when a required plugin fails")
+		}
+		if _, ok := manager.ListEnabledPlugins()["memory"]; !ok {
+			t.Error("expected the other plugins to still be initialized")
+		}
+	})
+}
+
+// reloadTestPlugin is a minimal plugin whose initialization can be made to fail
+type reloadTestPlugin struct {
+	*BasePlugin
+	initErr error
+}
+
+func newReloadTestPlugin(version string, initErr error) *reloadTestPlugin {
+	return &reloadTestPlugin{
+		BasePlugin: NewBasePlugin("reloadable", version, "Reload test plugin"),
+		initErr:    initErr,
+	}
+}
+
+func (p *reloadTestPlugin) Initialize(ctx context.Context, config PluginConfig) error {
+	if p.initErr != nil {
+		return p.initErr
+	}
+	return p.BasePlugin.Initialize(ctx, config)
+}
+
+func (p *reloadTestPlugin) GetTools() []registry.ToolDefinition         { return nil }
+func (p *reloadTestPlugin) GetResources() []registry.ResourceDefinition { return nil }
+func (p *reloadTestPlugin) GetPrompts() []registry.PromptDefinition     { return nil }
+func (p *reloadTestPlugin) CallTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+func (p *reloadTestPlugin) ReadResource(ctx context.Context, uri string) (interface{}, error) {
+	return nil, nil
+}
+func (p *reloadTestPlugin) ListResources(ctx context.Context) ([]registry.ResourceDefinition, error) {
+	return nil, nil
+}
+func (p *reloadTestPlugin) GetPrompt(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
 // mockMetrics implements metrics.Metrics interface for testing
 type mockMetrics struct {
 	metrics map[string]interface{}
@@ -0,0 +1,23 @@
+package plugins
+
+import "context"
+
+// ProgressFunc receives the progress of a long-running tool call. Total is 0
+// when the plugin does not know how much work remains.
+type ProgressFunc func(progress, total float64, message string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose tool call reports progress to report.
+// The gateway sets it when the client asked for progress with a progressToken.
+func WithProgress(ctx context.Context, report ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// ReportProgress reports the progress of the tool call ctx belongs to.
+// Plugins may call it freely; it does nothing when no progress was requested.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if report, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && report != nil {
+		report(progress, total, message)
+	}
+}
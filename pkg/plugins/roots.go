@@ -0,0 +1,28 @@
+package plugins
+
+import (
+	"net/url"
+	"path/filepath"
+)
+
+// directoryRoot returns the file:// root for a directory, resolved to an
+// absolute path so that plugins sharing a directory report the same URI
+func directoryRoot(dir, name string) Root {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return Root{
+		URI:  (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String(),
+		Name: name,
+	}
+}
+
+// GetRoots returns the working directory files are edited in
+func (es *EditorService) GetRoots() []Root {
+	return []Root{directoryRoot(es.workingDir, "editor working directory")}
+}
+
+// GetRoots returns the working directory of the git repository
+func (gs *GitService) GetRoots() []Root {
+	return []Root{directoryRoot(gs.workingDir, "git repository")}
+}
@@ -0,0 +1,747 @@
+package plugins
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/osakka/mcpeg/internal/registry"
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+const (
+	// stdioProtocolVersion is the MCP protocol version offered during the handshake
+	stdioProtocolVersion = "2024-11-05"
+
+	defaultStdioRequestTimeout  = 30 * time.Second
+	defaultStdioRestartDelay    = time.Second
+	defaultStdioShutdownTimeout = 5 * time.Second
+	defaultStdioMaxRestarts     = 5
+	maxStdioRestartDelay        = 30 * time.Second
+)
+
+// StdioPluginConfig configures a plugin backed by an external MCP server that
+// speaks JSON-RPC over its stdin and stdout
+type StdioPluginConfig struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Command     string            `yaml:"command"`
+	Args        []string          `yaml:"args"`
+	Env         map[string]string `yaml:"env"`         // Added to the gateway's environment
+	WorkingDir  string            `yaml:"working_dir"` // Defaults to the gateway's working directory
+
+	RequestTimeout  time.Duration `yaml:"request_timeout"`  // Per request sent to the subprocess
+	RestartDelay    time.Duration `yaml:"restart_delay"`    // Doubles after each failed restart, up to 30s
+	MaxRestarts     int           `yaml:"max_restarts"`     // Consecutive failed restarts before giving up; -1 retries forever
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // Grace period after closing stdin before the process is killed
+}
+
+// Validate checks that the plugin can be launched
+func (c StdioPluginConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("stdio plugin name is required")
+	}
+	if c.Command == "" {
+		return fmt.Errorf("stdio plugin %s: command is required", c.Name)
+	}
+	if c.RequestTimeout < 0 || c.RestartDelay < 0 || c.ShutdownTimeout < 0 {
+		return fmt.Errorf("stdio plugin %s: timeouts must not be negative", c.Name)
+	}
+	return nil
+}
+
+// StdioPlugin exposes the tools, resources and prompts of an MCP server run as
+// a subprocess. The subprocess is restarted with backoff when it exits
+// unexpectedly, and its capabilities are re-read after every (re)start.
+type StdioPlugin struct {
+	*BasePlugin
+	config StdioPluginConfig
+
+	mutex     sync.RWMutex
+	process   *stdioProcess // nil while the subprocess is down
+	tools     []registry.ToolDefinition
+	resources []registry.ResourceDefinition
+	prompts   []registry.PromptDefinition
+	stopping  bool
+	done      chan struct{}
+}
+
+// NewStdioPlugin creates a plugin for the MCP server launched by config
+func NewStdioPlugin(config StdioPluginConfig) *StdioPlugin {
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = defaultStdioRequestTimeout
+	}
+	if config.RestartDelay == 0 {
+		config.RestartDelay = defaultStdioRestartDelay
+	}
+	if config.ShutdownTimeout == 0 {
+		config.ShutdownTimeout = defaultStdioShutdownTimeout
+	}
+	if config.MaxRestarts == 0 {
+		config.MaxRestarts = defaultStdioMaxRestarts
+	}
+
+	description := config.Description
+	if description == "" {
+		description = fmt.Sprintf("MCP server run as subprocess: %s", config.Command)
+	}
+
+	return &StdioPlugin{
+		BasePlugin: NewBasePlugin(config.Name, "1.0.0", description),
+		config:     config,
+	}
+}
+
+// Initialize launches the subprocess and performs the MCP handshake
+func (sp *StdioPlugin) Initialize(ctx context.Context, config PluginConfig) error {
+	if err := sp.config.Validate(); err != nil {
+		return err
+	}
+	if err := sp.BasePlugin.Initialize(ctx, config); err != nil {
+		return err
+	}
+
+	sp.mutex.Lock()
+	sp.stopping = false
+	sp.done = make(chan struct{})
+	sp.mutex.Unlock()
+
+	if err := sp.start(ctx); err != nil {
+		return fmt.Errorf("failed to start stdio plugin %s: %w", sp.name, err)
+	}
+	return nil
+}
+
+// Shutdown closes the subprocess's stdin and kills it if it has not exited
+// within the shutdown timeout
+func (sp *StdioPlugin) Shutdown(ctx context.Context) error {
+	sp.mutex.Lock()
+	if sp.stopping || sp.done == nil {
+		sp.mutex.Unlock()
+		return nil
+	}
+	sp.stopping = true
+	close(sp.done)
+	process := sp.process
+	sp.process = nil
+	sp.mutex.Unlock()
+
+	if process != nil {
+		process.stop(ctx, sp.config.ShutdownTimeout)
+	}
+	return sp.BasePlugin.Shutdown(ctx)
+}
+
+// HealthCheck reports an error while the subprocess is down or not answering pings
+func (sp *StdioPlugin) HealthCheck(ctx context.Context) error {
+	if err := sp.BasePlugin.HealthCheck(ctx); err != nil {
+		return err
+	}
+	_, err := sp.call(ctx, "ping", nil)
+	return err
+}
+
+// GetTools returns the tools last reported by the subprocess
+func (sp *StdioPlugin) GetTools() []registry.ToolDefinition {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+	return append([]registry.ToolDefinition(nil), sp.tools...)
+}
+
+// GetResources returns the resources last reported by the subprocess
+func (sp *StdioPlugin) GetResources() []registry.ResourceDefinition {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+	return append([]registry.ResourceDefinition(nil), sp.resources...)
+}
+
+// GetPrompts returns the prompts last reported by the subprocess
+func (sp *StdioPlugin) GetPrompts() []registry.PromptDefinition {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+	return append([]registry.PromptDefinition(nil), sp.prompts...)
+}
+
+// ListResources returns the resources last reported by the subprocess
+func (sp *StdioPlugin) ListResources(ctx context.Context) ([]registry.ResourceDefinition, error) {
+	return sp.GetResources(), nil
+}
+
+// CallTool forwards a tools/call request to the subprocess
+func (sp *StdioPlugin) CallTool(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	start := time.Now()
+	params := map[string]interface{}{"name": name}
+	if len(args) > 0 && string(args) != "null" {
+		params["arguments"] = args
+	}
+
+	result, err := sp.callForObject(ctx, "tools/call", params)
+	sp.LogToolCall(name, time.Since(start), err)
+	return result, err
+}
+
+// ReadResource forwards a resources/read request to the subprocess
+func (sp *StdioPlugin) ReadResource(ctx context.Context, uri string) (interface{}, error) {
+	start := time.Now()
+	result, err := sp.callForObject(ctx, "resources/read", map[string]interface{}{"uri": uri})
+	sp.LogResourceAccess(uri, time.Since(start), err)
+	return result, err
+}
+
+// GetPrompt forwards a prompts/get request to the subprocess
+func (sp *StdioPlugin) GetPrompt(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
+	params := map[string]interface{}{"name": name}
+	if len(args) > 0 && string(args) != "null" {
+		params["arguments"] = args
+	}
+	return sp.callForObject(ctx, "prompts/get", params)
+}
+
+// callForObject sends a request and decodes its result as a JSON object
+func (sp *StdioPlugin) callForObject(ctx context.Context, method string, params interface{}) (map[string]interface{}, error) {
+	raw, err := sp.call(ctx, method, params)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid %s result from stdio plugin %s: %w", method, sp.name, err)
+	}
+	return result, nil
+}
+
+// call sends a request to the running subprocess
+func (sp *StdioPlugin) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	sp.mutex.RLock()
+	process := sp.process
+	sp.mutex.RUnlock()
+
+	if process == nil {
+		return nil, fmt.Errorf("stdio plugin %s is not running", sp.name)
+	}
+	return process.request(ctx, method, params, sp.config.RequestTimeout)
+}
+
+// start launches a subprocess, performs the handshake and reads its capabilities
+func (sp *StdioPlugin) start(ctx context.Context) error {
+	process, err := startStdioProcess(sp.config, sp.logger, sp.handleNotification)
+	if err != nil {
+		return err
+	}
+
+	capabilities, err := sp.handshake(ctx, process)
+	if err == nil {
+		err = sp.refreshCapabilities(ctx, process, capabilities)
+	}
+	if err != nil {
+		process.stop(ctx, sp.config.ShutdownTimeout)
+		return err
+	}
+
+	sp.mutex.Lock()
+	if sp.stopping {
+		sp.mutex.Unlock()
+		process.stop(ctx, sp.config.ShutdownTimeout)
+		return fmt.Errorf("stdio plugin %s is shutting down", sp.name)
+	}
+	sp.process = process
+	sp.mutex.Unlock()
+
+	sp.logger.Info("stdio_plugin_started",
+		"plugin", sp.name,
+		"command", sp.config.Command,
+		"pid", process.cmd.Process.Pid,
+		"tools_count", len(sp.GetTools()))
+
+	go sp.supervise(process)
+	return nil
+}
+
+// handshake sends initialize and initialized, returning the server's capabilities
+func (sp *StdioPlugin) handshake(ctx context.Context, process *stdioProcess) (map[string]interface{}, error) {
+	raw, err := process.request(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": stdioProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo": map[string]interface{}{
+			"name":    "mcpeg",
+			"version": sp.version,
+		},
+	}, sp.config.RequestTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("initialize failed: %w", err)
+	}
+
+	var result struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid initialize result: %w", err)
+	}
+
+	if err := process.notify("notifications/initialized", nil); err != nil {
+		return nil, err
+	}
+	return result.Capabilities, nil
+}
+
+// refreshCapabilities re-reads the tools, resources and prompts the server advertises
+func (sp *StdioPlugin) refreshCapabilities(ctx context.Context, process *stdioProcess, capabilities map[string]interface{}) error {
+	var tools []registry.ToolDefinition
+	var resources []registry.ResourceDefinition
+	var prompts []registry.PromptDefinition
+
+	if _, ok := capabilities["tools"]; ok {
+		var page struct {
+			Tools []struct {
+				Name        string                 `json:"name"`
+				Description string                 `json:"description"`
+				InputSchema map[string]interface{} `json:"inputSchema"`
+			} `json:"tools"`
+		}
+		err := sp.listAll(ctx, process, "tools/list", &page, func() {
+			for _, tool := range page.Tools {
+				tools = append(tools, registry.ToolDefinition{
+					Name:        tool.Name,
+					Description: tool.Description,
+					InputSchema: tool.InputSchema,
+				})
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, ok := capabilities["resources"]; ok {
+		var page struct {
+			Resources []struct {
+				URI         string `json:"uri"`
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				MimeType    string `json:"mimeType"`
+			} `json:"resources"`
+		}
+		err := sp.listAll(ctx, process, "resources/list", &page, func() {
+			for _, resource := range page.Resources {
+				resources = append(resources, registry.ResourceDefinition{
+					URI:         resource.URI,
+					Name:        resource.Name,
+					Description: resource.Description,
+					MimeType:    resource.MimeType,
+				})
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, ok := capabilities["prompts"]; ok {
+		var page struct {
+			Prompts []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Arguments   []struct {
+					Name        string `json:"name"`
+					Description string `json:"description"`
+					Required    bool   `json:"required"`
+				} `json:"arguments"`
+			} `json:"prompts"`
+		}
+		err := sp.listAll(ctx, process, "prompts/list", &page, func() {
+			for _, prompt := range page.Prompts {
+				definition := registry.PromptDefinition{
+					Name:        prompt.Name,
+					Description: prompt.Description,
+				}
+				for _, arg := range prompt.Arguments {
+					definition.Arguments = append(definition.Arguments, registry.PromptArgument{
+						Name:        arg.Name,
+						Description: arg.Description,
+						Type:        "string",
+						Required:    arg.Required,
+					})
+				}
+				prompts = append(prompts, definition)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	sp.mutex.Lock()
+	sp.tools = tools
+	sp.resources = resources
+	sp.prompts = prompts
+	sp.mutex.Unlock()
+	return nil
+}
+
+// listAll requests every page of a list method, calling collect after each
+// page has been decoded into page
+func (sp *StdioPlugin) listAll(ctx context.Context, process *stdioProcess, method string, page interface{}, collect func()) error {
+	cursor := ""
+	for {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]interface{}{"cursor": cursor}
+		}
+		raw, err := process.request(ctx, method, params, sp.config.RequestTimeout)
+		if err != nil {
+			return fmt.Errorf("%s failed: %w", method, err)
+		}
+		if err := json.Unmarshal(raw, page); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+		collect()
+
+		var next struct {
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &next); err != nil || next.NextCursor == "" {
+			return nil
+		}
+		cursor = next.NextCursor
+	}
+}
+
+// handleNotification re-reads capabilities when the server reports a list change
+func (sp *StdioPlugin) handleNotification(process *stdioProcess, method string) {
+	switch method {
+	case "notifications/tools/list_changed", "notifications/resources/list_changed", "notifications/prompts/list_changed":
+	default:
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sp.config.RequestTimeout)
+		defer cancel()
+
+		capabilities := map[string]interface{}{
+			"tools": struct{}{}, "resources": struct{}{}, "prompts": struct{}{},
+		}
+		if err := sp.refreshCapabilities(ctx, process, capabilities); err != nil {
+			sp.logger.Warn("stdio_plugin_capability_refresh_failed",
+				"plugin", sp.name,
+				"notification", method,
+				"error", err)
+		}
+	}()
+}
+
+// supervise waits for the subprocess to exit and restarts it unless the plugin
+// is shutting down, backing off between failed attempts
+func (sp *StdioPlugin) supervise(process *stdioProcess) {
+	<-process.exited
+
+	sp.mutex.Lock()
+	if sp.stopping || sp.process != process {
+		sp.mutex.Unlock()
+		return
+	}
+	sp.process = nil
+	done := sp.done
+	sp.mutex.Unlock()
+
+	sp.metrics.Inc("stdio_plugin_exits_total", "plugin", sp.name)
+	sp.logger.Warn("stdio_plugin_exited",
+		"plugin", sp.name,
+		"error", process.exitErr)
+
+	delay := sp.config.RestartDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(delay):
+		case <-done:
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sp.config.RequestTimeout)
+		err := sp.start(ctx)
+		cancel()
+		if err == nil {
+			sp.metrics.Inc("stdio_plugin_restarts_total", "plugin", sp.name)
+			return
+		}
+
+		sp.logger.Error("stdio_plugin_restart_failed",
+			"plugin", sp.name,
+			"attempt", attempt,
+			"error", err)
+		if sp.config.MaxRestarts > 0 && attempt >= sp.config.MaxRestarts {
+			sp.logger.Error("stdio_plugin_restart_abandoned",
+				"plugin", sp.name,
+				"attempts", attempt)
+			return
+		}
+
+		delay *= 2
+		if delay > maxStdioRestartDelay {
+			delay = maxStdioRestartDelay
+		}
+	}
+}
+
+// stdioProcess is a running MCP server subprocess and its pending requests
+type stdioProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	logger logging.Logger
+
+	writeMutex sync.Mutex
+	nextID     int64
+
+	pendingMutex sync.Mutex
+	pending      map[int64]chan stdioResponse
+
+	onNotification func(process *stdioProcess, method string)
+
+	exited  chan struct{}
+	exitErr error
+}
+
+// stdioMessage is any JSON-RPC message read from the subprocess
+type stdioMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *stdioError     `json:"error,omitempty"`
+}
+
+type stdioResponse struct {
+	result json.RawMessage
+	err    error
+}
+
+type stdioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *stdioError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// startStdioProcess launches the configured command with piped stdio. The
+// notification handler is set before the read loop starts, since the
+// subprocess may notify as soon as it runs.
+func startStdioProcess(config StdioPluginConfig, logger logging.Logger, onNotification func(process *stdioProcess, method string)) (*stdioProcess, error) {
+	cmd := exec.Command(config.Command, config.Args...)
+	cmd.Dir = config.WorkingDir
+	if len(config.Env) > 0 {
+		cmd.Env = os.Environ()
+		for key, value := range config.Env {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+	cmd.Stderr = &stdioStderrWriter{logger: logger, plugin: config.Name}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	process := &stdioProcess{
+		cmd:     cmd,
+		stdin:   stdin,
+		logger:  logger,
+		pending: make(map[int64]chan stdioResponse),
+		exited:  make(chan struct{}),
+
+		onNotification: onNotification,
+	}
+	go process.readLoop(stdout)
+	return process, nil
+}
+
+// readLoop dispatches messages from stdout until the subprocess closes it,
+// then reaps the process and fails every pending request
+func (p *stdioProcess) readLoop(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			p.dispatch(line)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	p.exitErr = p.cmd.Wait()
+	if p.exitErr == nil {
+		p.exitErr = fmt.Errorf("process exited")
+	}
+
+	p.pendingMutex.Lock()
+	for id, ch := range p.pending {
+		ch <- stdioResponse{err: fmt.Errorf("subprocess exited: %w", p.exitErr)}
+		delete(p.pending, id)
+	}
+	close(p.exited)
+	p.pendingMutex.Unlock()
+}
+
+// dispatch routes a response to its caller and answers server-initiated requests
+func (p *stdioProcess) dispatch(line []byte) {
+	var msg stdioMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		p.logger.Warn("stdio_plugin_invalid_message", "error", err)
+		return
+	}
+
+	hasID := len(msg.ID) > 0 && string(msg.ID) != "null"
+	switch {
+	case msg.Method != "" && !hasID:
+		if p.onNotification != nil {
+			p.onNotification(p, msg.Method)
+		}
+	case msg.Method != "":
+		// The gateway offers no client capabilities, so only ping is answered
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID}
+		if msg.Method == "ping" {
+			response["result"] = map[string]interface{}{}
+		} else {
+			response["error"] = stdioError{Code: -32601, Message: "Method not found"}
+		}
+		if err := p.write(response); err != nil {
+			p.logger.Warn("stdio_plugin_response_failed", "method", msg.Method, "error", err)
+		}
+	default:
+		var id int64
+		if err := json.Unmarshal(msg.ID, &id); err != nil {
+			return
+		}
+		p.pendingMutex.Lock()
+		ch, ok := p.pending[id]
+		delete(p.pending, id)
+		p.pendingMutex.Unlock()
+		if !ok {
+			return
+		}
+		if msg.Error != nil {
+			ch <- stdioResponse{err: msg.Error}
+		} else {
+			ch <- stdioResponse{result: msg.Result}
+		}
+	}
+}
+
+// request sends a JSON-RPC request and waits for its response
+func (p *stdioProcess) request(ctx context.Context, method string, params interface{}, timeout time.Duration) (json.RawMessage, error) {
+	id := atomic.AddInt64(&p.nextID, 1)
+	ch := make(chan stdioResponse, 1)
+
+	p.pendingMutex.Lock()
+	select {
+	case <-p.exited:
+		p.pendingMutex.Unlock()
+		return nil, fmt.Errorf("subprocess exited: %w", p.exitErr)
+	default:
+	}
+	p.pending[id] = ch
+	p.pendingMutex.Unlock()
+
+	message := map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method}
+	if params != nil {
+		message["params"] = params
+	}
+	if err := p.write(message); err != nil {
+		p.forget(id)
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case response := <-ch:
+		return response.result, response.err
+	case <-timer.C:
+		p.forget(id)
+		return nil, fmt.Errorf("%s timed out after %s", method, timeout)
+	case <-ctx.Done():
+		p.forget(id)
+		return nil, ctx.Err()
+	}
+}
+
+// notify sends a JSON-RPC notification
+func (p *stdioProcess) notify(method string, params interface{}) error {
+	message := map[string]interface{}{"jsonrpc": "2.0", "method": method}
+	if params != nil {
+		message["params"] = params
+	}
+	return p.write(message)
+}
+
+func (p *stdioProcess) write(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	p.writeMutex.Lock()
+	defer p.writeMutex.Unlock()
+	if _, err := p.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to subprocess: %w", err)
+	}
+	return nil
+}
+
+func (p *stdioProcess) forget(id int64) {
+	p.pendingMutex.Lock()
+	delete(p.pending, id)
+	p.pendingMutex.Unlock()
+}
+
+// stop closes stdin so the server can exit cleanly, killing it after the grace period
+func (p *stdioProcess) stop(ctx context.Context, grace time.Duration) {
+	p.stdin.Close()
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-p.exited:
+		return
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	p.logger.Warn("stdio_plugin_killed", "pid", p.cmd.Process.Pid)
+	p.cmd.Process.Kill()
+	<-p.exited
+}
+
+// stdioStderrWriter logs the subprocess's stderr line by line
+type stdioStderrWriter struct {
+	logger logging.Logger
+	plugin string
+}
+
+func (w *stdioStderrWriter) Write(data []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if line != "" {
+			w.logger.Debug("stdio_plugin_stderr", "plugin", w.plugin, "line", line)
+		}
+	}
+	return len(data), nil
+}
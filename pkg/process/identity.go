@@ -0,0 +1,122 @@
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// processName is matched against a process command line to decide whether a
+// PID still belongs to MCpeg when no start-time marker is available
+const processName = "mcpeg"
+
+// startTimeMarker prefixes the start-time line recorded after the PID
+const startTimeMarker = "start_time="
+
+// errProcessInfoUnavailable is returned when the platform exposes no /proc
+var errProcessInfoUnavailable = fmt.Errorf("process information unavailable")
+
+// processStartTime returns the kernel start time of pid in clock ticks since
+// boot, which stays fixed for the life of a process and changes on PID reuse
+func processStartTime(pid int) (string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			if _, statErr := os.Stat("/proc/self/stat"); statErr != nil {
+				return "", errProcessInfoUnavailable
+			}
+		}
+		return "", err
+	}
+
+	// The command name may contain spaces and parentheses, so fields are
+	// counted from the last closing parenthesis; start time is field 22
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return "", fmt.Errorf("malformed stat for process %d", pid)
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 20 {
+		return "", fmt.Errorf("malformed stat for process %d", pid)
+	}
+	return fields[19], nil
+}
+
+// processCommand returns the executable name of pid from its command line
+func processCommand(pid int) (string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		if _, statErr := os.Stat("/proc/self/cmdline"); statErr != nil {
+			return "", errProcessInfoUnavailable
+		}
+		return "", err
+	}
+
+	argv0, _, _ := bytes.Cut(data, []byte{0})
+	return filepath.Base(string(argv0)), nil
+}
+
+// parsePIDFile parses PID file content: the PID on the first line, optionally
+// followed by a start-time marker line. Files without a marker remain valid.
+func parsePIDFile(content string) (pid int, startTime string, err error) {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+
+	pidStr := strings.TrimSpace(lines[0])
+	pid, err = strconv.Atoi(pidStr)
+	if err != nil || pid <= 0 {
+		return 0, "", fmt.Errorf("invalid PID %q", pidStr)
+	}
+
+	for _, line := range lines[1:] {
+		if value, found := strings.CutPrefix(strings.TrimSpace(line), startTimeMarker); found {
+			startTime = value
+		}
+	}
+	return pid, startTime, nil
+}
+
+// formatPIDFile renders PID file content, recording the start time when known
+func formatPIDFile(pid int) string {
+	content := fmt.Sprintf("%d\n", pid)
+	if startTime, err := processStartTime(pid); err == nil {
+		content += startTimeMarker + startTime + "\n"
+	}
+	return content
+}
+
+// isOwnProcess reports whether a live pid is still the MCpeg process that
+// wrote the PID file. A recorded start time must match exactly; otherwise the
+// command line must name MCpeg. Platforms without /proc trust the PID as before.
+func (pm *PIDManager) isOwnProcess(pid int, recordedStartTime string) bool {
+	if recordedStartTime != "" {
+		startTime, err := processStartTime(pid)
+		if err == errProcessInfoUnavailable {
+			return true
+		}
+		if err != nil || startTime != recordedStartTime {
+			pm.logger.Warn("pid_reused_by_another_process",
+				"pid_file", pm.pidFile,
+				"pid", pid,
+				"recorded_start_time", recordedStartTime,
+				"actual_start_time", startTime)
+			return false
+		}
+		return true
+	}
+
+	command, err := processCommand(pid)
+	if err == errProcessInfoUnavailable {
+		return true
+	}
+	if err != nil || !strings.Contains(strings.ToLower(command), processName) {
+		pm.logger.Warn("pid_belongs_to_another_process",
+			"pid_file", pm.pidFile,
+			"pid", pid,
+			"command", command)
+		return false
+	}
+	return true
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
 	"syscall"
 
 	"github.com/osakka/mcpeg/pkg/logging"
@@ -27,6 +25,17 @@ func NewPIDManager(pidFile string, logger logging.Logger) *PIDManager {
 
 // WritePID writes the current process ID to the PID file
 func (pm *PIDManager) WritePID() error {
+	return pm.writePID(0)
+}
+
+// TakeOverPID writes the current process ID to the PID file in place of a
+// running gateway it is replacing, which is expected to exit shortly. Any
+// other running process recorded in the file still blocks startup.
+func (pm *PIDManager) TakeOverPID(previousPID int) error {
+	return pm.writePID(previousPID)
+}
+
+func (pm *PIDManager) writePID(previousPID int) error {
 	if pm.pidFile == "" {
 		return nil // No PID file configured
 	}
@@ -38,13 +47,13 @@ func (pm *PIDManager) WritePID() error {
 	}
 
 	// Check if PID file already exists and process is running
-	if err := pm.checkExistingProcess(); err != nil {
+	if err := pm.checkExistingProcess(previousPID); err != nil {
 		return err
 	}
 
-	// Write current process ID
+	// Write current process ID with its start time so a reused PID is detectable
 	pid := os.Getpid()
-	content := fmt.Sprintf("%d\n", pid)
+	content := formatPIDFile(pid)
 
 	if err := os.WriteFile(pm.pidFile, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write PID file %s: %w", pm.pidFile, err)
@@ -52,7 +61,8 @@ func (pm *PIDManager) WritePID() error {
 
 	pm.logger.Info("pid_file_created",
 		"pid_file", pm.pidFile,
-		"pid", pid)
+		"pid", pid,
+		"previous_pid", previousPID)
 
 	return nil
 }
@@ -78,16 +88,31 @@ func (pm *PIDManager) RemovePID() error {
 	return nil
 }
 
-// checkExistingProcess checks if there's already a running process
-func (pm *PIDManager) checkExistingProcess() error {
+// RemoveOwnPID removes the PID file if it still records the current
+// process, leaving alone one rewritten by a gateway that took over
+func (pm *PIDManager) RemoveOwnPID() error {
+	if pm.pidFile == "" {
+		return nil
+	}
+
+	if pid, err := pm.ReadPID(); err == nil && pid != os.Getpid() {
+		pm.logger.Info("pid_file_taken_over", "pid_file", pm.pidFile, "pid", pid)
+		return nil
+	}
+	return pm.RemovePID()
+}
+
+// checkExistingProcess checks if there's already a running process other
+// than the one being taken over, if any
+func (pm *PIDManager) checkExistingProcess(previousPID int) error {
 	if _, err := os.Stat(pm.pidFile); os.IsNotExist(err) {
 		return nil // No existing PID file
 	}
 
 	// Read existing PID
-	existingPID, err := pm.ReadPID()
+	existingPID, startTime, err := pm.readPIDFile()
 	if err != nil {
-		pm.logger.Warn("failed_to_read_existing_pid_file",
+		pm.logger.Warn("corrupt_pid_file_overwritten",
 			"pid_file", pm.pidFile,
 			"error", err)
 		// Remove invalid PID file
@@ -95,8 +120,15 @@ func (pm *PIDManager) checkExistingProcess() error {
 		return nil
 	}
 
-	// Check if process is still running
-	if pm.isProcessRunning(existingPID) {
+	// Check if process is still running and is still MCpeg rather than an
+	// unrelated process that inherited the PID
+	if previousPID > 0 && existingPID == previousPID {
+		pm.logger.Info("pid_file_taking_over",
+			"pid_file", pm.pidFile,
+			"previous_pid", previousPID)
+		return nil
+	}
+	if pm.isProcessRunning(existingPID) && pm.isOwnProcess(existingPID, startTime) {
 		return fmt.Errorf("MCpeg is already running with PID %d (PID file: %s)", existingPID, pm.pidFile)
 	}
 
@@ -111,22 +143,27 @@ func (pm *PIDManager) checkExistingProcess() error {
 
 // ReadPID reads the PID from the PID file
 func (pm *PIDManager) ReadPID() (int, error) {
+	pid, _, err := pm.readPIDFile()
+	return pid, err
+}
+
+// readPIDFile reads the PID and its recorded start time, if any
+func (pm *PIDManager) readPIDFile() (int, string, error) {
 	if pm.pidFile == "" {
-		return 0, fmt.Errorf("no PID file configured")
+		return 0, "", fmt.Errorf("no PID file configured")
 	}
 
 	content, err := os.ReadFile(pm.pidFile)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read PID file %s: %w", pm.pidFile, err)
+		return 0, "", fmt.Errorf("failed to read PID file %s: %w", pm.pidFile, err)
 	}
 
-	pidStr := strings.TrimSpace(string(content))
-	pid, err := strconv.Atoi(pidStr)
+	pid, startTime, err := parsePIDFile(string(content))
 	if err != nil {
-		return 0, fmt.Errorf("invalid PID in file %s: %s", pm.pidFile, pidStr)
+		return 0, "", fmt.Errorf("invalid PID file %s: %w", pm.pidFile, err)
 	}
 
-	return pid, nil
+	return pid, startTime, nil
 }
 
 // isProcessRunning checks if a process with the given PID is running
@@ -150,7 +187,9 @@ func (pm *PIDManager) GetPIDFile() string {
 	return pm.pidFile
 }
 
-// IsRunning checks if MCpeg is currently running based on PID file
+// IsRunning checks if MCpeg is currently running based on PID file. A PID that
+// now belongs to an unrelated process, or a corrupt PID file, reports not
+// running so callers never signal the wrong process.
 func (pm *PIDManager) IsRunning() (bool, int, error) {
 	if pm.pidFile == "" {
 		return false, 0, fmt.Errorf("no PID file configured")
@@ -160,12 +199,15 @@ func (pm *PIDManager) IsRunning() (bool, int, error) {
 		return false, 0, nil // No PID file means not running
 	}
 
-	pid, err := pm.ReadPID()
+	pid, startTime, err := pm.readPIDFile()
 	if err != nil {
-		return false, 0, err
+		pm.logger.Warn("corrupt_pid_file",
+			"pid_file", pm.pidFile,
+			"error", err)
+		return false, 0, nil
 	}
 
-	isRunning := pm.isProcessRunning(pid)
+	isRunning := pm.isProcessRunning(pid) && pm.isOwnProcess(pid, startTime)
 	return isRunning, pid, nil
 }
 
@@ -363,17 +405,6 @@ func (pm *PIDManager) ReloadConfigSignal() error {
 	return nil
 }
 
-// SetupCleanupOnExit sets up cleanup of PID file on process exit
-func (pm *PIDManager) SetupCleanupOnExit() {
-	// This will be called when the process exits normally
-	go func() {
-		// Create a channel to listen for interrupt signals
-		// This is handled in the main application, but we can also
-		// register cleanup here as a backup
-		defer pm.RemovePID()
-	}()
-}
-
 // PIDFileManager interface for dependency injection
 type PIDFileManager interface {
 	WritePID() error
@@ -0,0 +1,145 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+)
+
+// TestPIDManagerStalePID tests that a PID reused by an unrelated process is never treated as MCpeg
+func TestPIDManagerStalePID(t *testing.T) {
+	if _, err := processStartTime(os.Getpid()); err != nil {
+		t.Skipf("process start times unavailable: %v", err)
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "mcpeg.pid")
+	pm := NewPIDManager(pidFile, logging.New("test"))
+
+	// An unrelated long-running process stands in for one that inherited the PID
+	other := exec.Command("sleep", "30")
+	if err := other.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	defer other.Process.Kill()
+	otherPID := other.Process.Pid
+
+	t.Run("PID of a foreign process without a marker is not running", func(t *testing.T) {
+		writeFile(t, pidFile, "%d\n", otherPID)
+
+		running, pid, err := pm.IsRunning()
+		if err != nil || running || pid != otherPID {
+			t.Errorf("expected foreign process reported as not running, got running=%v pid=%d err=%v", running, pid, err)
+		}
+		if err := pm.StopProcess(false); err != nil {
+			t.Fatalf("unexpected stop error: %v", err)
+		}
+		if !pm.isProcessRunning(otherPID) {
+			t.Fatal("expected stop not to signal the unrelated process")
+		}
+	})
+
+	t.Run("mismatched start time is not running", func(t *testing.T) {
+		writeFile(t, pidFile, "%d\nstart_time=1\n", os.Getpid())
+
+		if running, _, _ := pm.IsRunning(); running {
+			t.Error("expected reused PID with a different start time to be reported as not running")
+		}
+	})
+
+	t.Run("stale PID file is replaced on startup", func(t *testing.T) {
+		writeFile(t, pidFile, "%d\n", otherPID)
+
+		if err := pm.WritePID(); err != nil {
+			t.Fatalf("expected stale PID file to be replaced, got %v", err)
+		}
+		if pid, err := pm.ReadPID(); err != nil || pid != os.Getpid() {
+			t.Errorf("expected PID file to hold %d, got %d (%v)", os.Getpid(), pid, err)
+		}
+	})
+
+	t.Run("own PID with matching start time is running", func(t *testing.T) {
+		if running, pid, err := pm.IsRunning(); err != nil || !running || pid != os.Getpid() {
+			t.Errorf("expected own process to be running, got running=%v pid=%d err=%v", running, pid, err)
+		}
+		if err := pm.WritePID(); err == nil {
+			t.Error("expected startup to refuse while the recorded process is alive")
+		}
+	})
+}
+
+// TestPIDManagerCorruptFile tests that a non-numeric PID file is tolerated and overwritten
+func TestPIDManagerCorruptFile(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "mcpeg.pid")
+	pm := NewPIDManager(pidFile, logging.New("test"))
+	writeFile(t, pidFile, "not-a-pid\n")
+
+	if running, pid, err := pm.IsRunning(); err != nil || running || pid != 0 {
+		t.Errorf("expected corrupt PID file to report not running, got running=%v pid=%d err=%v", running, pid, err)
+	}
+	if _, err := pm.ReadPID(); err == nil {
+		t.Error("expected ReadPID to reject a corrupt PID file")
+	}
+
+	if err := pm.WritePID(); err != nil {
+		t.Fatalf("expected corrupt PID file to be overwritten, got %v", err)
+	}
+	content, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("failed to read PID file: %v", err)
+	}
+	if pid, _, err := parsePIDFile(string(content)); err != nil || pid != os.Getpid() {
+		t.Errorf("expected PID file to hold %d, got %q", os.Getpid(), strings.TrimSpace(string(content)))
+	}
+}
+
+// TestPIDManagerTakeOver tests that a replacement daemon can take over the
+// PID file of the running one, which then leaves the file in place on exit
+func TestPIDManagerTakeOver(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "mcpeg.pid")
+	pm := NewPIDManager(pidFile, logging.New("test"))
+
+	// The running daemon is stood in for by this process
+	writeFile(t, pidFile, "%s", formatPIDFile(os.Getpid()))
+	if running, _, _ := pm.IsRunning(); !running {
+		t.Skip("process identity unavailable")
+	}
+
+	if err := pm.WritePID(); err == nil {
+		t.Error("expected startup to refuse while the recorded process is alive")
+	}
+	if err := pm.TakeOverPID(os.Getpid() + 1); err == nil {
+		t.Error("expected take over of a different PID to be refused")
+	}
+	if err := pm.TakeOverPID(os.Getpid()); err != nil {
+		t.Fatalf("expected take over of the recorded PID, got %v", err)
+	}
+
+	// The file now records the replacement, which the old daemon must keep
+	writeFile(t, pidFile, "%d\n", os.Getpid()+1)
+	if err := pm.RemoveOwnPID(); err != nil {
+		t.Fatalf("unexpected remove error: %v", err)
+	}
+	if _, err := os.Stat(pidFile); err != nil {
+		t.Errorf("expected a PID file taken over to be kept, got %v", err)
+	}
+
+	writeFile(t, pidFile, "%d\n", os.Getpid())
+	if err := pm.RemoveOwnPID(); err != nil {
+		t.Fatalf("unexpected remove error: %v", err)
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Errorf("expected own PID file to be removed, got %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, format string, args ...interface{}) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(format, args...)), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,107 @@
+package rbac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Permissions granted by PluginPermission and checked by HasPermission
+const (
+	PermissionRead    = "read"
+	PermissionWrite   = "write"
+	PermissionExecute = "execute"
+	PermissionAdmin   = "admin"
+
+	// PermissionNone marks methods any caller may use, such as the handshake
+	PermissionNone = "none"
+)
+
+// defaultMethodPermissions is the permission each MCP method requires unless
+// overridden. Methods without an entry require admin, so new methods are
+// denied to ordinary users until they are classified.
+var defaultMethodPermissions = map[string]string{
+	"initialize":               PermissionNone,
+	"ping":                     PermissionNone,
+	"tools/list":               PermissionRead,
+	"tools/call":               PermissionExecute,
+	"resources/list":           PermissionRead,
+	"resources/templates/list": PermissionRead,
+	"resources/read":           PermissionRead,
+	"resources/subscribe":      PermissionRead,
+	"resources/unsubscribe":    PermissionRead,
+	"prompts/list":             PermissionRead,
+	"prompts/get":              PermissionRead,
+	"completion/complete":      PermissionRead,
+	"roots/list":               PermissionRead,
+	"sampling/createMessage":   PermissionExecute,
+	"logging/setLevel":         PermissionAdmin,
+	"plugins/list":             PermissionRead,
+	"plugins/discover":         PermissionRead,
+	"plugins/capabilities":     PermissionRead,
+	"plugins/dependencies":     PermissionRead,
+	"plugins/filter":           PermissionRead,
+
+	// Invalidates the roots cached for the sending session
+	"notifications/roots/list_changed": PermissionRead,
+}
+
+// MethodPolicy maps MCP methods, and tools by name, to the permission a
+// caller needs. Entries override the defaults; tool entries apply to
+// tools/call and take precedence over the tools/call method entry, so
+// destructive tools can require write while others only need execute.
+type MethodPolicy struct {
+	Methods map[string]string `yaml:"methods" json:"methods"`
+	Tools   map[string]string `yaml:"tools" json:"tools"`
+}
+
+// Validate checks that every entry names a known permission
+func (p MethodPolicy) Validate() error {
+	for kind, entries := range map[string]map[string]string{"method": p.Methods, "tool": p.Tools} {
+		for name, permission := range entries {
+			if !isMethodPermission(permission) {
+				return fmt.Errorf("%s %s has unknown permission %q, expected read, write, execute, admin or none",
+					kind, name, permission)
+			}
+		}
+	}
+	return nil
+}
+
+// RequiredPermission returns the permission needed to call method; toolName
+// is the target of a tools/call and ignored for other methods
+func (p MethodPolicy) RequiredPermission(method, toolName string) string {
+	if method == "tools/call" && toolName != "" {
+		if permission, ok := p.Tools[toolName]; ok {
+			return permission
+		}
+	}
+	if permission, ok := p.Methods[method]; ok {
+		return permission
+	}
+	if permission, ok := defaultMethodPermissions[method]; ok {
+		return permission
+	}
+	// Clients send notifications without expecting an answer
+	if strings.HasPrefix(method, "notifications/") {
+		return PermissionNone
+	}
+	return PermissionAdmin
+}
+
+// HasAnyPermission checks if capabilities allow an action on any plugin
+func (pc *ProcessedCapabilities) HasAnyPermission(permission string) bool {
+	for _, perm := range pc.Plugins {
+		if pc.checkPermission(perm, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func isMethodPermission(permission string) bool {
+	switch permission {
+	case PermissionRead, PermissionWrite, PermissionExecute, PermissionAdmin, PermissionNone:
+		return true
+	}
+	return false
+}
@@ -26,16 +26,22 @@ type CacheEntry struct {
 
 // NewMemoryCache creates a new in-memory validation cache
 func NewMemoryCache() *MemoryCache {
+	return NewMemoryCacheWithSize(1000) // Default max size
+}
+
+// NewMemoryCacheWithSize creates an in-memory validation cache holding at most maxSize results
+func NewMemoryCacheWithSize(maxSize int) *MemoryCache {
 	return &MemoryCache{
 		cache:   make(map[string]*CacheEntry),
-		maxSize: 1000, // Default max size
+		maxSize: maxSize,
 	}
 }
 
 // Get retrieves a validation result from cache
 func (c *MemoryCache) Get(key string) (*ValidationResult, bool) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	// Access statistics are updated, so this takes the write lock
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
 	entry, exists := c.cache[key]
 	if !exists {
@@ -44,8 +50,7 @@ func (c *MemoryCache) Get(key string) (*ValidationResult, bool) {
 
 	// Check if expired
 	if time.Now().After(entry.ExpiresAt) {
-		// Remove expired entry (deferred cleanup)
-		go c.removeExpired(key)
+		delete(c.cache, key)
 		return nil, false
 	}
 
@@ -82,14 +87,6 @@ func (c *MemoryCache) Clear() {
 	c.cache = make(map[string]*CacheEntry)
 }
 
-// removeExpired removes an expired entry
-func (c *MemoryCache) removeExpired(key string) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	delete(c.cache, key)
-}
-
 // evictLRU removes the least recently used entry
 func (c *MemoryCache) evictLRU() {
 	var oldestKey string
@@ -127,9 +124,10 @@ func (v *Validator) setCachedResult(value interface{}, category string, result *
 func (v *Validator) generateCacheKey(value interface{}, category string) string {
 	// Serialize value to create consistent key
 	data, err := json.Marshal(map[string]interface{}{
-		"value":    value,
-		"category": category,
-		"config":   v.config,
+		"value":      value,
+		"category":   category,
+		"config":     v.config,
+		"generation": v.compiled.currentGeneration(),
 	})
 	if err != nil {
 		// Fallback to string representation
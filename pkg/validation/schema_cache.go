@@ -0,0 +1,394 @@
+package validation
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Patterns of the built-in format rules, compiled once
+var (
+	emailPattern   = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	httpURLPattern = regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`)
+	pluginPattern  = regexp.MustCompile(`^plugin://[^\s]*$`)
+	addressPattern = regexp.MustCompile(`^[^\s/:?#]+(:\d+)?(/[^\s]*)?$`)
+)
+
+// compiledTagRule is one parsed entry of a validate struct tag
+type compiledTagRule struct {
+	name  string
+	value string
+}
+
+// compiledField is an exported struct field with its parsed validation tags
+type compiledField struct {
+	index    int
+	name     string
+	required bool
+	rules    []compiledTagRule
+}
+
+// compiledSchema is the validation plan for a struct type, built once from
+// its field tags instead of on every validation
+type compiledSchema struct {
+	fields []compiledField
+}
+
+// compileSchema parses the validation tags of a struct type
+func compileSchema(rt reflect.Type) *compiledSchema {
+	schema := &compiledSchema{fields: make([]compiledField, 0, rt.NumField())}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		compiled := compiledField{
+			index:    i,
+			name:     field.Name,
+			required: field.Tag.Get("required") == "true",
+		}
+		if tag := field.Tag.Get("validate"); tag != "" {
+			for _, rule := range strings.Split(tag, ",") {
+				parts := strings.Split(strings.TrimSpace(rule), "=")
+				parsed := compiledTagRule{name: parts[0]}
+				if len(parts) > 1 {
+					parsed.value = parts[1]
+				}
+				compiled.rules = append(compiled.rules, parsed)
+			}
+		}
+		schema.fields = append(schema.fields, compiled)
+	}
+	return schema
+}
+
+// schemaCache holds compiled struct schemas and regex patterns. Both are
+// bounded; when full an arbitrary entry makes room, as struct types and tag
+// patterns form a small fixed set in practice.
+type schemaCache struct {
+	mutex    sync.RWMutex
+	schemas  map[reflect.Type]*compiledSchema
+	patterns map[string]*regexp.Regexp
+	maxSize  int
+
+	// Bumped on every invalidation so memoized results of earlier schemas
+	// are never served
+	generation uint64
+}
+
+func newSchemaCache(maxSize int) *schemaCache {
+	if maxSize <= 0 {
+		maxSize = DefaultValidationConfig().MaxSchemaCacheSize
+	}
+	return &schemaCache{
+		schemas:  make(map[reflect.Type]*compiledSchema),
+		patterns: make(map[string]*regexp.Regexp),
+		maxSize:  maxSize,
+	}
+}
+
+// schema returns the compiled schema of a struct type, compiling it on first use
+func (c *schemaCache) schema(rt reflect.Type) (schema *compiledSchema, hit bool) {
+	c.mutex.RLock()
+	schema, hit = c.schemas[rt]
+	c.mutex.RUnlock()
+	if hit {
+		return schema, true
+	}
+
+	schema = compileSchema(rt)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.schemas) >= c.maxSize {
+		for key := range c.schemas {
+			delete(c.schemas, key)
+			break
+		}
+	}
+	c.schemas[rt] = schema
+	return schema, false
+}
+
+// pattern returns the compiled regex for a tag pattern, or nil if it is invalid
+func (c *schemaCache) pattern(expr string) *regexp.Regexp {
+	c.mutex.RLock()
+	compiled, hit := c.patterns[expr]
+	c.mutex.RUnlock()
+	if hit {
+		return compiled
+	}
+
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		compiled = nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.patterns) >= c.maxSize {
+		for key := range c.patterns {
+			delete(c.patterns, key)
+			break
+		}
+	}
+	c.patterns[expr] = compiled
+	return compiled
+}
+
+// invalidate drops every compiled schema and returns the new generation
+func (c *schemaCache) invalidate() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.schemas = make(map[reflect.Type]*compiledSchema)
+	c.patterns = make(map[string]*regexp.Regexp)
+	c.generation++
+	return c.generation
+}
+
+func (c *schemaCache) currentGeneration() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.generation
+}
+
+// InvalidateCache drops compiled schemas and memoized validation results, so
+// the next validations see schemas changed by a plugin reload
+func (v *Validator) InvalidateCache(reason string) {
+	generation := v.compiled.invalidate()
+	v.cache.Clear()
+
+	v.metrics.Inc("validation_cache_invalidations_total", "reason", reason)
+	v.logger.Info("validation_cache_invalidated",
+		"reason", reason,
+		"generation", generation)
+}
+
+// maxMemoKeyDepth bounds how deep memoKey follows nested values, so cyclic
+// values are not memoized rather than encoded forever
+const maxMemoKeyDepth = 32
+
+// memoKey identifies a struct value for result memoization. Every field,
+// including unexported and json:"-" fields, is encoded with its type and
+// length, so two values share a key only when they are identical. Values
+// holding functions, channels or unsafe pointers are never memoized.
+func (v *Validator) memoKey(value interface{}) (string, bool) {
+	key := strconv.AppendUint([]byte("struct:"), v.compiled.currentGeneration(), 10)
+	key, ok := appendMemoValue(append(key, ':'), reflect.ValueOf(value), 0)
+	if !ok {
+		return "", false
+	}
+	return string(key), true
+}
+
+// appendMemoValue appends an unambiguous encoding of rv to key
+func appendMemoValue(key []byte, rv reflect.Value, depth int) ([]byte, bool) {
+	if depth > maxMemoKeyDepth {
+		return nil, false
+	}
+	if !rv.IsValid() {
+		return append(key, 'z'), true
+	}
+
+	rt := rv.Type()
+	key = appendMemoString(key, rt.PkgPath()+"."+rt.String())
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(key, '1'), true
+		}
+		return append(key, '0'), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return append(strconv.AppendInt(key, rv.Int(), 10), ';'), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return append(strconv.AppendUint(key, rv.Uint(), 10), ';'), true
+	case reflect.Float32, reflect.Float64:
+		return append(strconv.AppendUint(key, math.Float64bits(rv.Float()), 16), ';'), true
+	case reflect.Complex64, reflect.Complex128:
+		c := rv.Complex()
+		key = append(strconv.AppendUint(key, math.Float64bits(real(c)), 16), ',')
+		return append(strconv.AppendUint(key, math.Float64bits(imag(c)), 16), ';'), true
+	case reflect.String:
+		return appendMemoString(key, rv.String()), true
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return append(key, 'n'), true
+		}
+		return appendMemoValue(append(key, 'p'), rv.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			var ok bool
+			if key, ok = appendMemoValue(key, rv.Field(i), depth+1); !ok {
+				return nil, false
+			}
+		}
+		return key, true
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return append(key, 'n'), true
+		}
+		key = append(strconv.AppendInt(key, int64(rv.Len()), 10), '[')
+		for i := 0; i < rv.Len(); i++ {
+			var ok bool
+			if key, ok = appendMemoValue(key, rv.Index(i), depth+1); !ok {
+				return nil, false
+			}
+		}
+		return key, true
+	case reflect.Map:
+		if rv.IsNil() {
+			return append(key, 'n'), true
+		}
+		// Encode entries independently and sort them, as map order is random
+		entries := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			entry, ok := appendMemoValue(nil, iter.Key(), depth+1)
+			if !ok {
+				return nil, false
+			}
+			if entry, ok = appendMemoValue(entry, iter.Value(), depth+1); !ok {
+				return nil, false
+			}
+			entries = append(entries, string(entry))
+		}
+		sort.Strings(entries)
+		key = append(strconv.AppendInt(key, int64(len(entries)), 10), '{')
+		for _, entry := range entries {
+			key = append(key, entry...)
+		}
+		return key, true
+	default:
+		return nil, false
+	}
+}
+
+// appendMemoString appends a length-prefixed string, so no content can be
+// mistaken for the encoding around it
+func appendMemoString(key []byte, s string) []byte {
+	key = append(strconv.AppendInt(key, int64(len(s)), 10), ':')
+	return append(key, s...)
+}
+
+// cloneResult copies the slices and maps of a validation result, so a memoized
+// result and the results served from it never share mutable state
+func cloneResult(result ValidationResult) ValidationResult {
+	if result.Errors != nil {
+		errs := make([]ValidationError, len(result.Errors))
+		for i, err := range result.Errors {
+			err.Suggestions = append([]string(nil), err.Suggestions...)
+			if err.Context != nil {
+				copied := make(map[string]interface{}, len(err.Context))
+				for k, value := range err.Context {
+					copied[k] = value
+				}
+				err.Context = copied
+			}
+			errs[i] = err
+		}
+		result.Errors = errs
+	}
+	if result.Warnings != nil {
+		warnings := make([]ValidationWarning, len(result.Warnings))
+		for i, warning := range result.Warnings {
+			warning.Suggestions = append([]string(nil), warning.Suggestions...)
+			warnings[i] = warning
+		}
+		result.Warnings = warnings
+	}
+	if result.Context != nil {
+		copied := make(map[string]interface{}, len(result.Context))
+		for k, value := range result.Context {
+			copied[k] = value
+		}
+		result.Context = copied
+	}
+	if result.Suggestions != nil {
+		result.Suggestions = append([]string(nil), result.Suggestions...)
+	}
+	return result
+}
+
+// memoizedResult returns the memoized ValidateStruct result for an identical value
+func (v *Validator) memoizedResult(value interface{}) (ValidationResult, bool) {
+	key, ok := v.memoKey(value)
+	if !ok {
+		return ValidationResult{}, false
+	}
+
+	cached, found := v.cache.Get(key)
+	if !found {
+		v.metrics.Inc("validation_memo_misses_total")
+		return ValidationResult{}, false
+	}
+
+	v.metrics.Inc("validation_memo_hits_total")
+	result := cloneResult(*cached)
+	result.Performance.CacheHits++
+	return result, true
+}
+
+// memoizeResult keeps a ValidateStruct result for MemoizeTTL
+func (v *Validator) memoizeResult(value interface{}, result ValidationResult) {
+	if key, ok := v.memoKey(value); ok {
+		memoized := cloneResult(result)
+		v.cache.Set(key, &memoized, v.config.MemoizeTTL)
+	}
+}
+
+// validateCompiledStruct validates the fields of a struct value against its compiled schema
+func (v *Validator) validateCompiledStruct(ctx context.Context, rv reflect.Value, fieldPath string, depth int) ValidationResult {
+	result := ValidationResult{
+		Valid:    true,
+		Errors:   make([]ValidationError, 0),
+		Warnings: make([]ValidationWarning, 0),
+	}
+
+	schema, hit := v.compiled.schema(rv.Type())
+	if hit {
+		result.Performance.CacheHits++
+	} else {
+		result.Performance.CacheMisses++
+	}
+
+	for _, field := range schema.fields {
+		fieldValue := rv.Field(field.index)
+
+		currentPath := field.name
+		if fieldPath != "" {
+			currentPath = fieldPath + "." + field.name
+		}
+
+		// Validate field using tags
+		fieldResult := v.validateFieldByTags(ctx, fieldValue.Interface(), field, currentPath)
+		result.Errors = append(result.Errors, fieldResult.Errors...)
+		result.Warnings = append(result.Warnings, fieldResult.Warnings...)
+		if !fieldResult.Valid {
+			result.Valid = false
+		}
+		result.Performance.FieldsChecked++
+
+		// Recursively validate nested structs
+		if fieldValue.Kind() == reflect.Struct || (fieldValue.Kind() == reflect.Ptr && fieldValue.Elem().Kind() == reflect.Struct) {
+			nestedResult := v.validateStructRecursive(ctx, fieldValue.Interface(), currentPath, depth+1)
+			result.Errors = append(result.Errors, nestedResult.Errors...)
+			result.Warnings = append(result.Warnings, nestedResult.Warnings...)
+			if !nestedResult.Valid {
+				result.Valid = false
+			}
+			result.Performance.FieldsChecked += nestedResult.Performance.FieldsChecked
+			result.Performance.CacheHits += nestedResult.Performance.CacheHits
+			result.Performance.CacheMisses += nestedResult.Performance.CacheMisses
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,201 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/osakka/mcpeg/pkg/logging"
+	"github.com/osakka/mcpeg/pkg/metrics"
+)
+
+type mockMetrics struct{}
+
+func (m *mockMetrics) Inc(name string, labels ...string)                    {}
+func (m *mockMetrics) Add(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Set(name string, value float64, labels ...string)     {}
+func (m *mockMetrics) Observe(name string, value float64, labels ...string) {}
+func (m *mockMetrics) Time(name string, labels ...string) metrics.Timer     { return &mockTimer{} }
+func (m *mockMetrics) WithLabels(labels map[string]string) metrics.Metrics  { return m }
+func (m *mockMetrics) WithPrefix(prefix string) metrics.Metrics             { return m }
+func (m *mockMetrics) GetStats(name string) metrics.MetricStats             { return metrics.MetricStats{} }
+func (m *mockMetrics) GetAllStats() map[string]metrics.MetricStats          { return nil }
+
+type mockTimer struct{}
+
+func (t *mockTimer) Duration() time.Duration { return 0 }
+func (t *mockTimer) Stop() time.Duration     { return 0 }
+
+type testRegistration struct {
+	Name     string          `json:"name" required:"true"`
+	Endpoint string          `json:"endpoint" validate:"required,endpoint"`
+	Contact  string          `json:"contact" validate:"email"`
+	Port     int             `json:"port" validate:"min=1,max=65535"`
+	Version  string          `json:"version" validate:"regex=^v[0-9]+$"`
+	Tags     []string        `json:"tags" validate:"min=1"`
+	Owner    testOwner       `json:"owner"`
+	Backup   *testOwner      `json:"backup"`
+	internal string          // unexported fields are skipped
+	Extra    map[string]bool `json:"extra"`
+}
+
+type testOwner struct {
+	Team  string `json:"team" required:"true"`
+	Email string `json:"email" validate:"email"`
+}
+
+func testRegistrations() []testRegistration {
+	return []testRegistration{
+		{
+			Name: "search", Endpoint: "http://search:8080/mcp", Contact: "ops@example.com", Port: 8080,
+			Version: "v2", Tags: []string{"prod"}, Owner: testOwner{Team: "core", Email: "core@example.com"},
+		},
+		{
+			Endpoint: "not a url", Contact: "nobody", Port: 70000, Version: "2.0",
+			Owner: testOwner{Email: "bad"}, Backup: &testOwner{Email: "backup"}, internal: "x",
+		},
+		{
+			Name: "files", Endpoint: "files:9000", Contact: "files@example.com", Port: 9000, Version: "v10", Tags: []string{"a", "b"},
+			Owner: testOwner{Team: "storage", Email: "storage@example.com"}, Backup: &testOwner{Team: "oncall", Email: "oncall@example.com"},
+		},
+	}
+}
+
+// TestCompiledSchemaValidation tests that validating with cached compiled schemas gives the same results as compiling afresh
+func TestCompiledSchemaValidation(t *testing.T) {
+	ctx := context.Background()
+	validator := NewValidator(logging.New("test"), &mockMetrics{})
+
+	for i, registration := range testRegistrations() {
+		// Compile from scratch on every call for the reference result
+		validator.InvalidateCache("test")
+		first := validator.ValidateStruct(ctx, registration)
+		if first.Performance.CacheMisses == 0 {
+			t.Errorf("registration %d: expected the first validation to compile the schema", i)
+		}
+
+		cached := validator.ValidateStruct(ctx, registration)
+		if cached.Performance.CacheMisses != 0 {
+			t.Errorf("registration %d: expected the second validation to use compiled schemas, got %d misses",
+				i, cached.Performance.CacheMisses)
+		}
+
+		if cached.Valid != first.Valid ||
+			!reflect.DeepEqual(cached.Errors, first.Errors) ||
+			!reflect.DeepEqual(cached.Warnings, first.Warnings) {
+			t.Errorf("registration %d: cached result %+v differs from compiled result %+v", i, cached, first)
+		}
+	}
+
+	results := make([]bool, 0, 3)
+	for _, registration := range testRegistrations() {
+		results = append(results, validator.ValidateStruct(ctx, registration).Valid)
+	}
+	if !reflect.DeepEqual(results, []bool{true, false, true}) {
+		t.Errorf("expected validity [true false true], got %v", results)
+	}
+
+	invalid := validator.ValidateStruct(ctx, testRegistrations()[1])
+	codes := make(map[string]bool)
+	for _, err := range invalid.Errors {
+		codes[err.Field+":"+err.Code] = true
+	}
+	for _, expected := range []string{
+		"Name:REQUIRED", "Endpoint:INVALID_ENDPOINT", "Contact:INVALID_EMAIL", "Port:MAX_VALUE",
+		"Version:REGEX_MISMATCH", "Tags:MIN_VALUE", "Owner.Team:REQUIRED", "Owner.Email:INVALID_EMAIL",
+		"Backup.Email:INVALID_EMAIL",
+	} {
+		if !codes[expected] {
+			t.Errorf("expected error %s, got %v", expected, codes)
+		}
+	}
+}
+
+// TestMemoizedValidation tests that memoized results are reused for identical values until invalidated
+func TestMemoizedValidation(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultValidationConfig()
+	config.MemoizeResults = true
+	validator := NewValidatorWithConfig(logging.New("test"), &mockMetrics{}, config)
+
+	registration := testRegistrations()[1]
+	first := validator.ValidateStruct(ctx, registration)
+	memoized := validator.ValidateStruct(ctx, registration)
+	if memoized.Performance.CacheHits != first.Performance.CacheHits+1 {
+		t.Errorf("expected the identical value to be served from the memo, got %d cache hits", memoized.Performance.CacheHits)
+	}
+	if !reflect.DeepEqual(memoized.Errors, first.Errors) {
+		t.Error("expected the memoized result to match the validated one")
+	}
+
+	changed := registration
+	changed.Name = "renamed"
+	if result := validator.ValidateStruct(ctx, changed); len(result.Errors) != len(first.Errors)-1 {
+		t.Errorf("expected a changed value to be validated again, got %d errors", len(result.Errors))
+	}
+
+	// Fields left out of JSON still tell values apart
+	type secretOnly struct {
+		Token string `json:"-" required:"true"`
+	}
+	validator.ValidateStruct(ctx, secretOnly{Token: "set"})
+	if result := validator.ValidateStruct(ctx, secretOnly{}); result.Valid {
+		t.Error("expected a value differing only in a json:\"-\" field not to reuse the memoized result")
+	}
+
+	// Callers modifying a served result do not change the memo
+	served := validator.ValidateStruct(ctx, registration)
+	served.Errors[0].Message = "overwritten"
+	if result := validator.ValidateStruct(ctx, registration); result.Errors[0].Message == "overwritten" {
+		t.Error("expected the memoized result not to share errors with served results")
+	}
+
+	validator.InvalidateCache("plugin_reloaded")
+	if result := validator.ValidateStruct(ctx, registration); result.Performance.CacheMisses == 0 {
+		t.Error("expected invalidation to drop memoized results and compiled schemas")
+	}
+}
+
+// TestSchemaCacheBounded tests that the compiled schema cache never grows past its size
+func TestSchemaCacheBounded(t *testing.T) {
+	cache := newSchemaCache(2)
+	for _, value := range []interface{}{testRegistration{}, testOwner{}, mockMetrics{}, mockTimer{}} {
+		cache.schema(reflect.TypeOf(value))
+	}
+	if len(cache.schemas) > 2 {
+		t.Errorf("expected at most 2 compiled schemas, got %d", len(cache.schemas))
+	}
+}
+
+func BenchmarkValidateStruct(b *testing.B) {
+	ctx := context.Background()
+	registration := testRegistrations()[0]
+
+	b.Run("uncached", func(b *testing.B) {
+		validator := NewValidator(logging.New("bench"), &mockMetrics{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			validator.compiled.invalidate()
+			validator.ValidateStruct(ctx, registration)
+		}
+	})
+
+	b.Run("compiled", func(b *testing.B) {
+		validator := NewValidator(logging.New("bench"), &mockMetrics{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			validator.ValidateStruct(ctx, registration)
+		}
+	})
+
+	b.Run("memoized", func(b *testing.B) {
+		config := DefaultValidationConfig()
+		config.MemoizeResults = true
+		validator := NewValidatorWithConfig(logging.New("bench"), &mockMetrics{}, config)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			validator.ValidateStruct(ctx, registration)
+		}
+	})
+}
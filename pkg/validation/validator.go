@@ -63,9 +63,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/osakka/mcpeg/internal/mcp/types"
@@ -141,7 +141,11 @@ type Validator struct {
 	config  ValidationConfig
 
 	// Schema validation
-	schemas map[string]interface{}
+	schemas     map[string]interface{}
+	schemaMutex sync.RWMutex
+
+	// Struct schemas compiled from field tags, and tag regex patterns
+	compiled *schemaCache
 
 	// MCP-specific validators
 	mcpValidator *MCPValidator
@@ -154,6 +158,14 @@ type ValidationConfig struct {
 	CacheExpiry   time.Duration `yaml:"cache_expiry"`
 	MaxCacheSize  int           `yaml:"max_cache_size"`
 
+	// Compiled struct schemas kept; types beyond this are recompiled
+	MaxSchemaCacheSize int `yaml:"max_schema_cache_size"`
+
+	// Serve ValidateStruct results for identical values validated within
+	// MemoizeTTL instead of validating again
+	MemoizeResults bool          `yaml:"memoize_results"`
+	MemoizeTTL     time.Duration `yaml:"memoize_ttl"`
+
 	// Validation settings
 	StrictMode          bool `yaml:"strict_mode"`
 	FailFast            bool `yaml:"fail_fast"`
@@ -178,13 +190,33 @@ type ValidationCache interface {
 
 // NewValidator creates a comprehensive validation system
 func NewValidator(logger logging.Logger, metrics metrics.Metrics) *Validator {
+	return NewValidatorWithConfig(logger, metrics, DefaultValidationConfig())
+}
+
+// NewValidatorWithConfig creates a validation system with custom configuration
+func NewValidatorWithConfig(logger logging.Logger, metrics metrics.Metrics, config ValidationConfig) *Validator {
+	defaults := DefaultValidationConfig()
+	if config.MaxCacheSize <= 0 {
+		config.MaxCacheSize = defaults.MaxCacheSize
+	}
+	if config.MaxSchemaCacheSize <= 0 {
+		config.MaxSchemaCacheSize = defaults.MaxSchemaCacheSize
+	}
+	if config.MemoizeTTL <= 0 {
+		config.MemoizeTTL = defaults.MemoizeTTL
+	}
+	if config.MaxErrors <= 0 {
+		config.MaxErrors = defaults.MaxErrors
+	}
+
 	v := &Validator{
-		rules:   make(map[string][]ValidationRule),
-		logger:  logger.WithComponent("validator"),
-		metrics: metrics,
-		cache:   NewMemoryCache(),
-		config:  defaultValidationConfig(),
-		schemas: make(map[string]interface{}),
+		rules:    make(map[string][]ValidationRule),
+		logger:   logger.WithComponent("validator"),
+		metrics:  metrics,
+		cache:    NewMemoryCacheWithSize(config.MaxCacheSize),
+		config:   config,
+		schemas:  make(map[string]interface{}),
+		compiled: newSchemaCache(config.MaxSchemaCacheSize),
 	}
 
 	// Initialize MCP-specific validator
@@ -211,13 +243,22 @@ func (v *Validator) RegisterRule(category string, rule ValidationRule) {
 		"severity", rule.Severity())
 }
 
-// RegisterSchema registers a JSON schema for validation
+// RegisterSchema registers a JSON schema for validation. Replacing a
+// registered schema invalidates cached validation results.
 func (v *Validator) RegisterSchema(name string, schema interface{}) error {
+	v.schemaMutex.Lock()
+	_, replaced := v.schemas[name]
 	v.schemas[name] = schema
+	v.schemaMutex.Unlock()
 
 	v.logger.Info("validation_schema_registered",
 		"schema_name", name,
-		"schema_type", reflect.TypeOf(schema).String())
+		"schema_type", reflect.TypeOf(schema).String(),
+		"replaced", replaced)
+
+	if replaced {
+		v.InvalidateCache("schema_replaced")
+	}
 
 	return nil
 }
@@ -229,8 +270,9 @@ func (v *Validator) Validate(ctx context.Context, value interface{}, category st
 	// Check cache first
 	if v.config.EnableCaching {
 		if cached := v.getCachedResult(value, category); cached != nil {
-			cached.Performance.CacheHits++
-			return *cached
+			result := *cached
+			result.Performance.CacheHits++
+			return result
 		}
 	}
 
@@ -333,9 +375,21 @@ func (v *Validator) Validate(ctx context.Context, value interface{}, category st
 	return result
 }
 
-// ValidateStruct validates a struct using field tags and registered rules
+// ValidateStruct validates a struct using field tags and registered rules.
+// Field tags are compiled once per struct type; with MemoizeResults the
+// result for an identical value is reused within MemoizeTTL.
 func (v *Validator) ValidateStruct(ctx context.Context, value interface{}) ValidationResult {
-	return v.validateStructRecursive(ctx, value, "", 0)
+	if !v.config.MemoizeResults {
+		return v.validateStructRecursive(ctx, value, "", 0)
+	}
+
+	if result, found := v.memoizedResult(value); found {
+		return result
+	}
+
+	result := v.validateStructRecursive(ctx, value, "", 0)
+	v.memoizeResult(value, result)
+	return result
 }
 
 // ValidateMCPRequest validates an MCP request
@@ -350,7 +404,9 @@ func (v *Validator) ValidateMCPResponse(ctx context.Context, response types.Resp
 
 // ValidateJSON validates JSON against a registered schema
 func (v *Validator) ValidateJSON(ctx context.Context, data []byte, schemaName string) ValidationResult {
+	v.schemaMutex.RLock()
 	schema, exists := v.schemas[schemaName]
+	v.schemaMutex.RUnlock()
 	if !exists {
 		return ValidationResult{
 			Valid: false,
@@ -428,47 +484,11 @@ func (v *Validator) validateStructRecursive(ctx context.Context, value interface
 		return result
 	}
 
-	rt := rv.Type()
-
-	for i := 0; i < rv.NumField(); i++ {
-		field := rt.Field(i)
-		fieldValue := rv.Field(i)
-
-		if !fieldValue.CanInterface() {
-			continue
-		}
-
-		currentPath := field.Name
-		if fieldPath != "" {
-			currentPath = fieldPath + "." + field.Name
-		}
-
-		// Validate field using tags
-		fieldResult := v.validateFieldByTags(ctx, fieldValue.Interface(), field, currentPath)
-
-		// Merge results
-		result.Errors = append(result.Errors, fieldResult.Errors...)
-		result.Warnings = append(result.Warnings, fieldResult.Warnings...)
-		if !fieldResult.Valid {
-			result.Valid = false
-		}
-
-		// Recursively validate nested structs
-		if fieldValue.Kind() == reflect.Struct || (fieldValue.Kind() == reflect.Ptr && fieldValue.Elem().Kind() == reflect.Struct) {
-			nestedResult := v.validateStructRecursive(ctx, fieldValue.Interface(), currentPath, depth+1)
-			result.Errors = append(result.Errors, nestedResult.Errors...)
-			result.Warnings = append(result.Warnings, nestedResult.Warnings...)
-			if !nestedResult.Valid {
-				result.Valid = false
-			}
-		}
-	}
-
-	return result
+	return v.validateCompiledStruct(ctx, rv, fieldPath, depth)
 }
 
-// validateFieldByTags validates a field using struct tags
-func (v *Validator) validateFieldByTags(ctx context.Context, value interface{}, field reflect.StructField, fieldPath string) ValidationResult {
+// validateFieldByTags validates a field using its compiled struct tags
+func (v *Validator) validateFieldByTags(ctx context.Context, value interface{}, field compiledField, fieldPath string) ValidationResult {
 	result := ValidationResult{
 		Valid:    true,
 		Errors:   make([]ValidationError, 0),
@@ -476,7 +496,7 @@ func (v *Validator) validateFieldByTags(ctx context.Context, value interface{},
 	}
 
 	// Check required tag
-	if required := field.Tag.Get("required"); required == "true" {
+	if field.required {
 		if v.isEmpty(value) {
 			result.Valid = false
 			result.Errors = append(result.Errors, ValidationError{
@@ -494,8 +514,8 @@ func (v *Validator) validateFieldByTags(ctx context.Context, value interface{},
 	}
 
 	// Check validation tag
-	if validate := field.Tag.Get("validate"); validate != "" {
-		tagResult := v.validateByTag(ctx, value, validate, fieldPath)
+	if len(field.rules) > 0 {
+		tagResult := v.validateByTag(ctx, value, field.rules, fieldPath)
 		result.Errors = append(result.Errors, tagResult.Errors...)
 		result.Warnings = append(result.Warnings, tagResult.Warnings...)
 		if !tagResult.Valid {
@@ -506,28 +526,16 @@ func (v *Validator) validateFieldByTags(ctx context.Context, value interface{},
 	return result
 }
 
-// validateByTag validates using validation tags
-func (v *Validator) validateByTag(ctx context.Context, value interface{}, tag, fieldPath string) ValidationResult {
+// validateByTag validates using parsed validation tags
+func (v *Validator) validateByTag(ctx context.Context, value interface{}, rules []compiledTagRule, fieldPath string) ValidationResult {
 	result := ValidationResult{
 		Valid:    true,
 		Errors:   make([]ValidationError, 0),
 		Warnings: make([]ValidationWarning, 0),
 	}
 
-	// Parse validation tags (simplified implementation)
-	rules := strings.Split(tag, ",")
-
 	for _, rule := range rules {
-		rule = strings.TrimSpace(rule)
-		parts := strings.Split(rule, "=")
-		ruleName := parts[0]
-
-		var ruleValue string
-		if len(parts) > 1 {
-			ruleValue = parts[1]
-		}
-
-		ruleResult := v.applyTagRule(ctx, value, ruleName, ruleValue, fieldPath)
+		ruleResult := v.applyTagRule(ctx, value, rule.name, rule.value, fieldPath)
 		result.Errors = append(result.Errors, ruleResult.Errors...)
 		result.Warnings = append(result.Warnings, ruleResult.Warnings...)
 		if !ruleResult.Valid {
@@ -609,6 +617,22 @@ func (v *Validator) applyTagRule(ctx context.Context, value interface{}, ruleNam
 			})
 		}
 
+	case "endpoint":
+		if !v.validateEndpoint(value) {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fieldPath,
+				Message:  "Invalid endpoint format",
+				Code:     "INVALID_ENDPOINT",
+				Value:    value,
+				Severity: SeverityError,
+				Suggestions: []string{
+					"Use a URL (http://host:port/path) or a host:port address",
+					"Check for typos in endpoint",
+				},
+			})
+		}
+
 	case "regex":
 		if !v.validateRegex(value, ruleValue) {
 			result.Valid = false
@@ -687,8 +711,7 @@ func (v *Validator) validateEmail(value interface{}) bool {
 		return false
 	}
 
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	return emailRegex.MatchString(str)
+	return emailPattern.MatchString(str)
 }
 
 func (v *Validator) validateURL(value interface{}) bool {
@@ -698,10 +721,21 @@ func (v *Validator) validateURL(value interface{}) bool {
 	}
 
 	// Accept HTTP/HTTPS URLs and internal plugin URLs
-	httpRegex := regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`)
-	pluginRegex := regexp.MustCompile(`^plugin://[^\s]*$`)
+	return httpURLPattern.MatchString(str) || pluginPattern.MatchString(str)
+}
+
+// validateEndpoint accepts a URL or a scheme-less host[:port][/path] address
+func (v *Validator) validateEndpoint(value interface{}) bool {
+	if v.validateURL(value) {
+		return true
+	}
 
-	return httpRegex.MatchString(str) || pluginRegex.MatchString(str)
+	str, ok := value.(string)
+	if !ok {
+		return false
+	}
+
+	return addressPattern.MatchString(str)
 }
 
 func (v *Validator) validateRegex(value interface{}, pattern string) bool {
@@ -710,8 +744,8 @@ func (v *Validator) validateRegex(value interface{}, pattern string) bool {
 		return false
 	}
 
-	regex, err := regexp.Compile(pattern)
-	if err != nil {
+	regex := v.compiled.pattern(pattern)
+	if regex == nil {
 		return false
 	}
 
@@ -751,6 +785,9 @@ func (v *Validator) getAvailableCategories() []string {
 }
 
 func (v *Validator) getAvailableSchemas() []string {
+	v.schemaMutex.RLock()
+	defer v.schemaMutex.RUnlock()
+
 	schemas := make([]string, 0, len(v.schemas))
 	for name := range v.schemas {
 		schemas = append(schemas, name)
@@ -758,11 +795,15 @@ func (v *Validator) getAvailableSchemas() []string {
 	return schemas
 }
 
-func defaultValidationConfig() ValidationConfig {
+// DefaultValidationConfig returns the default validation configuration
+func DefaultValidationConfig() ValidationConfig {
 	return ValidationConfig{
 		EnableCaching:          true,
 		CacheExpiry:            5 * time.Minute,
 		MaxCacheSize:           1000,
+		MaxSchemaCacheSize:     256,
+		MemoizeResults:         false,
+		MemoizeTTL:             5 * time.Second,
 		StrictMode:             false,
 		FailFast:               false,
 		GenerateSuggestions:    true,